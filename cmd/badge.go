@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var badgeOutFlag string
+
+var badgeCmd = &cobra.Command{
+	Use:   "badge [path]",
+	Short: "Write a readiness score badge",
+	Long: `Run all enabled checks and write a badge showing the readiness score
+(passing checks count in full, warnings for half) for launch dashboards and
+READMEs. The badge is red when any check fails, yellow for warnings only and
+green otherwise.
+
+An --out path ending in .json writes the shields.io endpoint format instead
+of an SVG. 'preflight serve --ui' also serves both at /badge.svg and
+/badge.json. Like report, badge exits 0 once the file is written.
+
+Example:
+  preflight badge
+  preflight badge --out docs/preflight.svg
+  preflight badge --out badge.json ./site`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBadge,
+}
+
+func init() {
+	badgeCmd.Flags().StringVarP(&badgeOutFlag, "out", "o", "badge.svg", "File to write the badge to (.svg, or .json for shields.io)")
+	rootCmd.AddCommand(badgeCmd)
+}
+
+func runBadge(cmd *cobra.Command, args []string) error {
+	projectDir, err := resolveProjectDir(args)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(projectDir)
+	if err != nil {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+
+	spinner := output.NewSpinner()
+	spinner.Start("Preparing scan...")
+	defer spinner.Stop()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	results, err := scanProject(ctx, projectDir, cfg, scanOptions{Progress: spinner.Update})
+	spinner.Stop()
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, "\nBadge cancelled.")
+			return &ExitError{Code: ExitCanceled}
+		}
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+
+	badge := output.NewBadge(results)
+	data := badge.SVG()
+	if strings.HasSuffix(strings.ToLower(badgeOutFlag), ".json") {
+		if data, err = json.Marshal(badge); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(badgeOutFlag, data, 0644); err != nil {
+		return fmt.Errorf("failed to write badge: %w", err)
+	}
+	fmt.Printf("Wrote %s (readiness %s)\n", badgeOutFlag, badge.Message)
+	return nil
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/checks"
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/workspace"
+)
+
+// The scan benchmarks run against three golden repos generated on the fly:
+// a small Next.js site, a medium Rails app and a monorepo. `make
+// bench-compare` runs them on the base branch and on this one and compares
+// the two with benchstat, so a PR that adds a check, or slows one down,
+// shows what it costs. The repos have no URLs and no lockfiles: the
+// benchmarks stay offline and never start an audit tool.
+
+// goldenSmall is a Next.js marketing site.
+func goldenSmall() map[string]string {
+	files := map[string]string{
+		"preflight.yml": "projectName: small\nstack: next\n",
+		"package.json":  `{"name": "small", "dependencies": {"next": "14.2.0", "react": "18.3.0"}}`,
+		"app/layout.tsx": `export const metadata = { title: "Small", description: "A small site" }
+export default function RootLayout({ children }) {
+  return <html lang="en"><head><meta name="viewport" content="width=device-width" /></head><body>{children}</body></html>
+}
+`,
+		"app/page.tsx":       "export default function Home() { return <h1>Home</h1> }\n",
+		"app/not-found.tsx":  "export default function NotFound() { return <h1>Not found</h1> }\n",
+		"public/robots.txt":  "User-agent: *\nAllow: /\n",
+		"public/favicon.ico": "ico",
+	}
+	for i := range 10 {
+		files[fmt.Sprintf("app/blog/post-%d/page.tsx", i)] = fmt.Sprintf(
+			"export default function Post() {\n  return <article><h1>Post %d</h1><img src=\"/img/%d.png\" alt=\"Post %d\" /></article>\n}\n", i, i, i)
+	}
+	return files
+}
+
+// goldenMedium is a Rails app with a few hundred models, controllers,
+// views and scripts.
+func goldenMedium() map[string]string {
+	files := map[string]string{
+		"preflight.yml": "projectName: medium\nstack: rails\nchecks:\n  secrets:\n    enabled: true\n",
+		"Gemfile":       "source 'https://rubygems.org'\ngem 'rails', '~> 7.1'\ngem 'sentry-ruby'\n",
+		"app/views/layouts/application.html.erb": `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <title><%= content_for(:title) || "Medium" %></title>
+  <meta name="description" content="A medium app">
+  <meta name="viewport" content="width=device-width,initial-scale=1">
+  <%= render "layouts/head" %>
+</head>
+<body><%= yield %></body>
+</html>
+`,
+		"app/views/layouts/_head.html.erb":       `<link rel="icon" href="/favicon.ico">` + "\n",
+		"public/404.html":                        "<h1>Not found</h1>\n",
+		"public/500.html":                        "<h1>Something went wrong</h1>\n",
+		"public/robots.txt":                      "User-agent: *\nDisallow: /admin\n",
+		"config/initializers/sentry.rb":          "Sentry.init { |config| config.dsn = ENV['SENTRY_DSN'] }\n",
+		".env.example":                           "SENTRY_DSN=\nDATABASE_URL=\n",
+		"config/environments/production.rb":      "Rails.application.configure do\n  config.force_ssl = true\nend\n",
+		"app/javascript/application.js":          "import \"@hotwired/turbo-rails\"\n",
+		"app/assets/stylesheets/application.css": "body { font-family: system-ui, sans-serif; }\n",
+	}
+	for i := range 60 {
+		name := fmt.Sprintf("widget%d", i)
+		class := fmt.Sprintf("Widget%d", i)
+		files["app/models/"+name+".rb"] = fmt.Sprintf(
+			"class %s < ApplicationRecord\n  belongs_to :account\n  validates :name, presence: true\n\n  def display_name\n    \"#{name} (#{id})\"\n  end\nend\n", class)
+		files["app/controllers/"+name+"s_controller.rb"] = fmt.Sprintf(
+			"class %ssController < ApplicationController\n  def index\n    @items = %s.order(:name).limit(50)\n  end\n\n  def show\n    @item = %s.find(params[:id])\n  end\nend\n", class, class, class)
+		for _, view := range []string{"index", "show", "_form"} {
+			files[fmt.Sprintf("app/views/%ss/%s.html.erb", name, view)] = strings.Repeat(
+				fmt.Sprintf("<div class=\"%s\"><%%= link_to item.display_name, item %%></div>\n", name), 20)
+		}
+		files[fmt.Sprintf("app/javascript/controllers/%s_controller.js", name)] = fmt.Sprintf(
+			"import { Controller } from \"@hotwired/stimulus\"\nexport default class extends Controller {\n  connect() { this.element.dataset.widget = %d }\n}\n", i)
+	}
+	return files
+}
+
+// goldenMonorepo is a Next.js web app, a Go API and a shared UI package.
+func goldenMonorepo() map[string]string {
+	files := map[string]string{
+		"preflight.yml":            "projectName: mono\nstack: node\nmonorepo:\n  enabled: true\n",
+		"package.json":             `{"name": "mono", "private": true, "workspaces": ["apps/*", "packages/*"]}`,
+		"apps/api/go.mod":          "module example.com/api\n\ngo 1.22\n",
+		"apps/api/main.go":         "package main\n\nimport \"net/http\"\n\nfunc main() {\n\thttp.HandleFunc(\"/health\", func(w http.ResponseWriter, r *http.Request) {})\n\t_ = http.ListenAndServe(\":8080\", nil)\n}\n",
+		"packages/ui/package.json": `{"name": "@mono/ui", "dependencies": {"react": "18.3.0"}}`,
+		"packages/ui/index.tsx":    "export function Button(props) { return <button {...props} /> }\n",
+	}
+	for path, content := range goldenSmall() {
+		if path != "preflight.yml" {
+			files["apps/web/"+path] = content
+		}
+	}
+	for i := range 20 {
+		files[fmt.Sprintf("apps/api/handlers/h%d.go", i)] = fmt.Sprintf(
+			"package handlers\n\nimport \"net/http\"\n\nfunc Handle%d(w http.ResponseWriter, r *http.Request) {\n\tw.WriteHeader(http.StatusOK)\n}\n", i)
+	}
+	return files
+}
+
+func writeGoldenRepo(b *testing.B, files map[string]string) string {
+	b.Helper()
+	dir := b.TempDir()
+	for path, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func loadGoldenRepo(b *testing.B, files map[string]string) (string, *config.PreflightConfig) {
+	b.Helper()
+	dir := writeGoldenRepo(b, files)
+	cfg, err := config.Load(dir)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return dir, cfg
+}
+
+// BenchmarkScan times a whole scan of each golden repo, file indexing
+// included.
+func BenchmarkScan(b *testing.B) {
+	for _, repo := range []struct {
+		name  string
+		files map[string]string
+	}{
+		{"small", goldenSmall()},
+		{"medium", goldenMedium()},
+	} {
+		b.Run(repo.name, func(b *testing.B) {
+			dir, cfg := loadGoldenRepo(b, repo.files)
+			for b.Loop() {
+				if _, err := scanProject(context.Background(), dir, cfg, scanOptions{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+
+	b.Run("monorepo", func(b *testing.B) {
+		dir, cfg := loadGoldenRepo(b, goldenMonorepo())
+		apps, err := workspace.Discover(dir, nil)
+		if err != nil || len(apps) != 3 {
+			b.Fatalf("Discover = %v, %v; want 3 apps", apps, err)
+		}
+		for b.Loop() {
+			for _, app := range apps {
+				appCfg, _, err := monorepoAppConfig(cfg, app)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := scanProject(context.Background(), app.Dir, appCfg, scanOptions{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+// BenchmarkChecks times each check enabled for the medium repo on its own,
+// so a new check gets a line in the comparison without touching this file.
+func BenchmarkChecks(b *testing.B) {
+	dir, cfg := loadGoldenRepo(b, goldenMedium())
+	checks.ConfigureWalk(cfg.Walk)
+	ctx := checks.Context{
+		Ctx:     context.Background(),
+		RootDir: dir,
+		Config:  cfg,
+		Client:  newCheckClient(cfg),
+		Files:   checks.BuildFileIndex(dir),
+	}
+	for _, check := range buildEnabledChecks(cfg, dir) {
+		b.Run(check.ID(), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := check.Run(ctx); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/cache"
+	"github.com/preflightsh/preflight/internal/checks"
+)
+
+// scanCacheKey turns the --cache-key a pipeline passes (usually the commit
+// SHA) into the key results are stored under. The suffix fingerprints
+// everything besides the commit that shapes the results: the preflight
+// version, the config file, and the run's --only/--skip/--verbose,
+// --profile-checks and --files-only. A stage that narrows the scan, or a
+// config edit that isn't committed yet, then misses instead of reusing
+// results that don't apply.
+func scanCacheKey(userKey, configPath string, opts scanOptions) (string, error) {
+	if err := cache.ValidateKey(userKey); err != nil {
+		return "", err
+	}
+	only, skip := slices.Sorted(slices.Values(opts.Only)), slices.Sorted(slices.Values(opts.Skip))
+	configData, _ := os.ReadFile(configPath)
+	configSum := sha256.Sum256(configData)
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%x\n%s\n%s\n%s\n%t\n%t\n%t\n", version, configSum, opts.CheckProfile,
+		strings.Join(only, ","), strings.Join(skip, ","), opts.Verbose, opts.Profile, filesOnlyFlag)
+	key := userKey + "-" + hex.EncodeToString(h.Sum(nil))[:12]
+	return key, cache.ValidateKey(key)
+}
+
+// loadCachedScan returns the results stored under key, or ok=false on a
+// miss. Any other failure is returned so the caller can warn and re-scan.
+func loadCachedScan(ctx context.Context, store cache.Store, key string) (entry cache.Entry, ok bool, err error) {
+	data, err := store.Get(ctx, key)
+	if errors.Is(err, cache.ErrMiss) {
+		return cache.Entry{}, false, nil
+	}
+	if err != nil {
+		return cache.Entry{}, false, err
+	}
+	entry, err = cache.Decode(data, key)
+	if err != nil {
+		return cache.Entry{}, false, err
+	}
+	return entry, true, nil
+}
+
+// storeCachedScan saves results under key for later stages.
+func storeCachedScan(ctx context.Context, store cache.Store, key, projectName string, results []checks.CheckResult) error {
+	data, err := cache.Encode(cache.Entry{
+		Key:       key,
+		Version:   version,
+		CreatedAt: time.Now().UTC(),
+		Project:   projectName,
+		Results:   results,
+	})
+	if err != nil {
+		return err
+	}
+	return store.Put(ctx, key, data)
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/preflightsh/preflight/internal/checks"
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	compareRef    string
+	compareFormat string
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <dirA> <dirB> | --ref <a>..<b> [path]",
+	Short: "Run the suite against two trees and show which checks differ",
+	Long: `Run every check against two versions of a project and list the checks whose
+outcome differs: regressions, fixes, and failures whose message changed.
+Useful for confirming a refactor or framework migration didn't drop icons,
+meta tags or error pages.
+
+Compare two directories:
+  preflight compare ./site-old ./site-new
+
+Compare two git refs (each is checked out into a temporary worktree):
+  preflight compare --ref v1.2.0..HEAD
+  preflight compare --ref main..        # main vs. the current working tree
+
+Each side is scanned with its own preflight.yml, falling back to the other
+side's when one lacks it (e.g. a ref from before preflight was set up).
+Exits 2 when any check regressed, 0 otherwise.`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: runCompare,
+}
+
+func init() {
+	compareCmd.Flags().StringVar(&compareRef, "ref", "", "Compare two git refs, as <a>..<b> (empty <b> means the working tree)")
+	compareCmd.Flags().StringVar(&compareFormat, "format", "human", "Output format: human or json")
+	rootCmd.AddCommand(compareCmd)
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	if compareFormat != "human" && compareFormat != "json" {
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("invalid --format %q (want human or json)", compareFormat)}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if compareRef != "" {
+		if len(args) > 1 {
+			return &ExitError{Code: ExitUsage, Err: fmt.Errorf("--ref takes at most one path (the project inside the repository)")}
+		}
+		projectDir, err := resolveProjectDir(args)
+		if err != nil {
+			return err
+		}
+		from, to, ok := strings.Cut(compareRef, "..")
+		if !ok || from == "" {
+			return &ExitError{Code: ExitUsage, Err: fmt.Errorf("invalid --ref %q (want <a>..<b>, e.g. v1.2.0..HEAD)", compareRef)}
+		}
+
+		beforeDir, cleanup, err := checkoutRef(projectDir, from)
+		if err != nil {
+			return &ExitError{Code: ExitUsage, Err: err}
+		}
+		defer cleanup()
+		afterDir, afterLabel := projectDir, "working tree"
+		if to != "" {
+			dir, cleanup, err := checkoutRef(projectDir, to)
+			if err != nil {
+				return &ExitError{Code: ExitUsage, Err: err}
+			}
+			defer cleanup()
+			afterDir, afterLabel = dir, to
+		}
+		return compareDirs(ctx, beforeDir, afterDir, from, afterLabel)
+	}
+
+	if len(args) != 2 {
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("compare needs two directories, or --ref <a>..<b>")}
+	}
+	for _, dir := range args {
+		if _, err := resolveProjectDir([]string{dir}); err != nil {
+			return err
+		}
+	}
+	return compareDirs(ctx, args[0], args[1], args[0], args[1])
+}
+
+// compareDirs scans both trees, prints the diff and maps regressions to
+// ExitFail.
+func compareDirs(ctx context.Context, beforeDir, afterDir, beforeLabel, afterLabel string) error {
+	beforeCfg, beforeErr := config.Load(beforeDir)
+	afterCfg, afterErr := config.Load(afterDir)
+	switch {
+	case beforeErr != nil && afterErr != nil:
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("neither side has a usable preflight.yml: %v", afterErr)}
+	case beforeErr != nil:
+		fmt.Fprintf(os.Stderr, "Note: %s has no usable preflight.yml; using the one from %s\n", beforeLabel, afterLabel)
+		beforeCfg = afterCfg
+	case afterErr != nil:
+		fmt.Fprintf(os.Stderr, "Note: %s has no usable preflight.yml; using the one from %s\n", afterLabel, beforeLabel)
+		afterCfg = beforeCfg
+	}
+
+	before, err := compareScan(ctx, beforeDir, beforeCfg, beforeLabel)
+	if err != nil {
+		return err
+	}
+	after, err := compareScan(ctx, afterDir, afterCfg, afterLabel)
+	if err != nil {
+		return err
+	}
+
+	diff := output.DiffResults(before, after)
+	if compareFormat == "json" {
+		if err := printJSON(map[string]any{"before": beforeLabel, "after": afterLabel, "diff": diff}); err != nil {
+			return err
+		}
+	} else {
+		output.WriteDiff(os.Stdout, beforeLabel, afterLabel, diff)
+	}
+
+	if diff.Regressions() > 0 {
+		return &ExitError{Code: ExitFail}
+	}
+	return nil
+}
+
+func compareScan(ctx context.Context, dir string, cfg *config.PreflightConfig, label string) ([]checks.CheckResult, error) {
+	if compareFormat == "human" {
+		fmt.Fprintf(os.Stderr, "Scanning %s...\n", label)
+	}
+	results, err := scanProject(ctx, dir, cfg, scanOptions{})
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, "\nCompare cancelled.")
+			return nil, &ExitError{Code: ExitCanceled}
+		}
+		return nil, &ExitError{Code: ExitUsage, Err: err}
+	}
+	return results, nil
+}
+
+// checkoutRef materializes ref of the git repository containing projectDir
+// in a temporary detached worktree and returns the project's directory
+// inside it, plus a cleanup that removes the worktree.
+func checkoutRef(projectDir, ref string) (string, func(), error) {
+	prefix, err := gitOutput(projectDir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", nil, fmt.Errorf("--ref needs a git repository: %w", err)
+	}
+	tmp, err := os.MkdirTemp("", "preflight-compare-*")
+	if err != nil {
+		return "", nil, err
+	}
+	worktree := filepath.Join(tmp, "tree")
+	if _, err := gitOutput(projectDir, "worktree", "add", "--detach", "--quiet", worktree, ref); err != nil {
+		os.RemoveAll(tmp)
+		return "", nil, fmt.Errorf("checking out %s: %w", ref, err)
+	}
+	cleanup := func() {
+		_, _ = gitOutput(projectDir, "worktree", "remove", "--force", worktree)
+		os.RemoveAll(tmp)
+	}
+	dir := filepath.Join(worktree, filepath.FromSlash(prefix))
+	if _, err := os.Stat(dir); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("%s does not exist at %s", prefix, ref)
+	}
+	return dir, cleanup, nil
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
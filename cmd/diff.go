@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/preflightsh/preflight/internal/checks"
+	"github.com/preflightsh/preflight/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var diffFormat string
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old.json> <new.json>",
+	Short: "Show which checks changed between two saved JSON reports",
+	Long: `Compare two reports saved with 'preflight scan --format json' and list the
+checks whose outcome differs: regressions, newly passing checks, and failures
+whose severity or message changed. Handy for a "what changed since the last
+release" section in a launch review, without re-running either scan.
+
+Example:
+  preflight scan --ci --format json > preflight-v1.4.json
+  preflight diff preflight-v1.3.json preflight-v1.4.json
+
+Like compare, diff exits 2 when any check regressed and 0 otherwise.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFormat, "format", "human", "Output format: human or json")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if diffFormat != "human" && diffFormat != "json" {
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("invalid --format %q (want human or json)", diffFormat)}
+	}
+	before, err := readReport(args[0])
+	if err != nil {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+	after, err := readReport(args[1])
+	if err != nil {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+
+	diff := output.DiffResults(before, after)
+	if diffFormat == "json" {
+		if err := printJSON(map[string]any{"before": args[0], "after": args[1], "diff": diff}); err != nil {
+			return err
+		}
+	} else {
+		output.WriteDiff(os.Stdout, args[0], args[1], diff)
+	}
+
+	if diff.Regressions() > 0 {
+		return &ExitError{Code: ExitFail}
+	}
+	return nil
+}
+
+// readReport loads the check results from a saved JSON report.
+func readReport(path string) ([]checks.CheckResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	_, results, err := output.ReadJSONOutput(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return results, nil
+}
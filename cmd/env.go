@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/checks"
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envFileFlag   string
+	envDryRunFlag bool
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Work with the project's environment variables",
+}
+
+var envScaffoldCmd = &cobra.Command{
+	Use:   "scaffold [path]",
+	Short: "Generate or update .env.example from the variables the code reads",
+	Long: `Search the project's source for environment variable reads (process.env.X,
+import.meta.env.X, ENV["X"], os.Getenv("X"), env('X'), os.environ["X"] and
+the like) and add every variable .env.example doesn't document yet, with an
+empty value and a comment naming the files that read it. The file is
+created when it doesn't exist.
+
+Existing entries are kept as written and nothing is removed; variables the
+file documents that no code reads are listed so you can decide. Tests,
+dependencies, build output and gitignored files aren't searched, and
+variables the platform sets (NODE_ENV, VERCEL_*, FLY_* and the like) are
+left out.
+
+The example file is checks.envParity.exampleFile from preflight.yml when
+there is one, else .env.example.
+
+Example:
+  preflight env scaffold
+  preflight env scaffold --dry-run
+  preflight env scaffold --file config/.env.sample ./api`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runEnvScaffold,
+}
+
+func init() {
+	envScaffoldCmd.Flags().StringVar(&envFileFlag, "file", "", "Example file to update, relative to the project (default .env.example)")
+	envScaffoldCmd.Flags().BoolVar(&envDryRunFlag, "dry-run", false, "Print the updated file instead of writing it")
+	envCmd.AddCommand(envScaffoldCmd)
+	rootCmd.AddCommand(envCmd)
+}
+
+func runEnvScaffold(cmd *cobra.Command, args []string) error {
+	projectDir, err := resolveProjectDir(args)
+	if err != nil {
+		return err
+	}
+
+	// preflight.yml is optional here; without one the walk uses its
+	// defaults and the usual file name.
+	file := ".env.example"
+	if cfg, err := config.Load(projectDir); err == nil {
+		checks.ConfigureWalk(cfg.Walk)
+		if cfg.Checks.EnvParity != nil && cfg.Checks.EnvParity.ExampleFile != "" {
+			file = cfg.Checks.EnvParity.ExampleFile
+		}
+	}
+	if envFileFlag != "" {
+		file = envFileFlag
+	}
+	path := file
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectDir, path)
+	}
+
+	refs := checks.FindEnvReferences(checks.BuildFileIndex(projectDir))
+	scaffold, err := checks.ScaffoldEnvExample(path, refs)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	if envDryRunFlag {
+		os.Stdout.Write(scaffold.Content)
+	} else if len(scaffold.Added) > 0 {
+		if err := os.WriteFile(path, scaffold.Content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+
+	// The report goes to stderr so --dry-run output can be redirected.
+	switch {
+	case len(refs) == 0:
+		fmt.Fprintln(os.Stderr, "No environment variable reads found.")
+	case len(scaffold.Added) == 0:
+		fmt.Fprintf(os.Stderr, "%s already documents all %d variables the code reads.\n", file, len(refs))
+	case envDryRunFlag:
+		fmt.Fprintf(os.Stderr, "Would add %d variable(s) to %s: %s\n", len(scaffold.Added), file, strings.Join(scaffold.Added, ", "))
+	default:
+		fmt.Fprintf(os.Stderr, "Added %d variable(s) to %s: %s\n", len(scaffold.Added), file, strings.Join(scaffold.Added, ", "))
+	}
+	if len(scaffold.Unused) > 0 {
+		fmt.Fprintf(os.Stderr, "Documented but not read by the code: %s\n", strings.Join(scaffold.Unused, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/checks"
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/output"
+	"github.com/preflightsh/preflight/internal/privacy"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormatFlag string
+	exportCrawlFlag  int
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export data preflight discovers about a project",
+}
+
+var exportURLsCmd = &cobra.Command{
+	Use:   "urls [path]",
+	Short: "List the project's URLs for uptime monitors and load tests",
+	Long: `Print every page URL preflight can find for the project: fixed routes from
+the framework's route definitions (Next.js, Rails, Laravel), the entries of
+the built and served sitemaps, and the pages linked from the live site,
+crawled breadth-first from the homepage. Feed the list to an uptime monitor
+or load-test tool instead of maintaining one by hand.
+
+URLs are made absolute with the production URL (staging when production
+isn't set); without either, paths are printed and nothing is fetched.
+
+Example:
+  preflight export urls > urls.txt
+  preflight export urls --format csv --crawl 200 > urls.csv
+  preflight export urls --crawl 0      # repo and sitemap only`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExportURLs,
+}
+
+var exportDataMapCmd = &cobra.Command{
+	Use:   "data-map [path]",
+	Short: "Draft a GDPR data-processing inventory from the services the project uses",
+	Long: `Write a Markdown data-processing inventory to start a privacy review from.
+It lists every payment, auth, email, analytics, error tracking, support and
+AI provider declared in preflight.yml or detected in the project, with the
+personal data that kind of service typically receives, and leaves the
+lawful basis, retention and transfer details for the review to fill in.
+
+The personal data listed is a starting point, not a finding: confirm it
+against what the app actually sends.
+
+Example:
+  preflight export data-map > docs/data-processing.md`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExportDataMap,
+}
+
+func init() {
+	exportURLsCmd.Flags().StringVar(&exportFormatFlag, "format", "text", "Output format: text (one URL per line) or csv (url, path, sources)")
+	exportURLsCmd.Flags().IntVar(&exportCrawlFlag, "crawl", 50, "Most pages of the live site to fetch while following links (0 disables crawling)")
+	exportCmd.AddCommand(exportURLsCmd)
+	exportCmd.AddCommand(exportDataMapCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExportURLs(cmd *cobra.Command, args []string) error {
+	if exportFormatFlag != "text" && exportFormatFlag != "csv" {
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("invalid --format %q (want text or csv)", exportFormatFlag)}
+	}
+	projectDir, err := resolveProjectDir(args)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(projectDir)
+	if err != nil {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+
+	spinner := output.NewSpinner()
+	spinner.Start("Discovering URLs...")
+	defer spinner.Stop()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	urls := checks.DiscoverURLs(checks.Context{
+		Ctx:     ctx,
+		RootDir: projectDir,
+		Config:  cfg,
+		Client:  newCheckClient(cfg),
+	}, exportCrawlFlag)
+	spinner.Stop()
+	if ctx.Err() != nil {
+		fmt.Fprintln(os.Stderr, "\nExport cancelled.")
+		return &ExitError{Code: ExitCanceled}
+	}
+
+	if exportFormatFlag == "csv" {
+		w := csv.NewWriter(os.Stdout)
+		_ = w.Write([]string{"url", "path", "sources"})
+		for _, u := range urls {
+			_ = w.Write([]string{u.URL, u.Path, strings.Join(u.Sources, ";")})
+		}
+		w.Flush()
+		return w.Error()
+	}
+	for _, u := range urls {
+		if u.URL != "" {
+			fmt.Println(u.URL)
+		} else {
+			fmt.Println(u.Path)
+		}
+	}
+	if len(urls) == 0 {
+		fmt.Fprintln(os.Stderr, "No URLs found.")
+	}
+	return nil
+}
+
+func runExportDataMap(cmd *cobra.Command, args []string) error {
+	projectDir, err := resolveProjectDir(args)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(projectDir)
+	if err != nil {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+	entries := privacy.Inventory(cfg, config.DetectServices(projectDir))
+	privacy.WriteMarkdown(os.Stdout, cfg.ProjectName, entries, time.Now())
+	return nil
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/preflightsh/preflight/internal/checks"
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fixDryRunFlag bool
+	fixYesFlag    bool
+	fixOnlyFlag   []string
+)
+
+var fixCmd = &cobra.Command{
+	Use:   "fix [path]",
+	Short: "Apply automatic fixes for failing checks",
+	Long: `Run the checks that have an automatic fix and repair what they report:
+
+  robotsTxt         create robots.txt in the web root
+  favicon           create site.webmanifest and add manifest and
+                    apple-touch-icon link tags to the layout
+  error_pages       scaffold a 404 page where the stack expects one
+  debug_statements  delete lines that hold nothing but a debug call
+
+The planned changes are listed, with the lines each edit adds or removes,
+and nothing is written until you confirm. Images can't be generated, so
+a missing favicon or apple-touch-icon.png is left as a note.
+
+Example:
+  preflight fix --dry-run
+  preflight fix --only debug_statements --yes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFix,
+}
+
+func init() {
+	fixCmd.Flags().BoolVar(&fixDryRunFlag, "dry-run", false, "Show the planned changes without writing anything")
+	fixCmd.Flags().BoolVarP(&fixYesFlag, "yes", "y", false, "Apply the changes without asking")
+	fixCmd.Flags().StringSliceVar(&fixOnlyFlag, "only", nil, "Fix only these check IDs (comma-separated)")
+	rootCmd.AddCommand(fixCmd)
+}
+
+func runFix(cmd *cobra.Command, args []string) error {
+	projectDir, err := resolveProjectDir(args)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(projectDir)
+	if err != nil {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+
+	fixable := checks.FixableCheckIDs()
+	for _, id := range fixOnlyFlag {
+		if !slices.Contains(fixable, id) {
+			return &ExitError{Code: ExitUsage, Err: fmt.Errorf("%q has no automatic fix (fixable checks: %s)", id, strings.Join(fixable, ", "))}
+		}
+	}
+	var only []string
+	for _, c := range buildEnabledChecks(cfg, projectDir) {
+		if slices.Contains(fixable, c.ID()) && (len(fixOnlyFlag) == 0 || slices.Contains(fixOnlyFlag, c.ID())) {
+			only = append(only, c.ID())
+		}
+	}
+	if len(only) == 0 {
+		fmt.Println("None of the fixable checks are enabled for this project.")
+		return nil
+	}
+
+	spinner := output.NewSpinner()
+	spinner.Start("Preparing scan...")
+	defer spinner.Stop()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	results, err := scanProject(ctx, projectDir, cfg, scanOptions{Only: only, Progress: spinner.Update})
+	spinner.Stop()
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, "\nFix cancelled.")
+			return &ExitError{Code: ExitCanceled}
+		}
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+
+	fixes, err := checks.PlanFixes(checks.Context{RootDir: projectDir, Config: cfg}, results)
+	if err != nil {
+		return err
+	}
+	if len(fixes) == 0 {
+		fmt.Println("Nothing to fix.")
+		return nil
+	}
+
+	fmt.Printf("%d change(s) planned:\n\n", len(fixes))
+	for _, f := range fixes {
+		printFix(projectDir, f)
+	}
+
+	if fixDryRunFlag {
+		fmt.Println("Dry run: nothing was written.")
+		return nil
+	}
+	if !fixYesFlag && !promptYesNo(bufio.NewReader(os.Stdin), "Apply these changes?", false) {
+		fmt.Println("No changes made.")
+		return nil
+	}
+
+	applied := 0
+	for _, f := range fixes {
+		if err := f.Apply(projectDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not %s: %v\n", f.Summary, err)
+			continue
+		}
+		applied++
+	}
+	fmt.Printf("Applied %d of %d change(s). Run 'preflight scan' to confirm.\n", applied, len(fixes))
+	if applied < len(fixes) {
+		return &ExitError{Code: ExitFail}
+	}
+	return nil
+}
+
+func printFix(projectDir string, f checks.Fix) {
+	fmt.Printf("  [%s] %s\n", f.CheckID, f.Summary)
+	if !f.Create {
+		before, err := os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(f.Path)))
+		if err == nil {
+			for _, line := range fixDiffLines(string(before), string(f.Content)) {
+				fmt.Println("      " + line)
+			}
+		}
+	}
+	for _, note := range f.Notes {
+		fmt.Println("      note: " + note)
+	}
+	fmt.Println()
+}
+
+// fixDiffLines lists the lines an edit removes or adds, numbered by their
+// line in the file. Every fixer either only removes lines or only inserts
+// them, so a single pass in one direction is enough.
+func fixDiffLines(before, after string) []string {
+	oldLines := strings.Split(before, "\n")
+	newLines := strings.Split(after, "\n")
+	removing := len(oldLines) > len(newLines)
+
+	var out []string
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			i++
+			j++
+		case removing && i < len(oldLines):
+			out = append(out, fmt.Sprintf("- %d: %s", i+1, strings.TrimSpace(oldLines[i])))
+			i++
+		case !removing && j < len(newLines):
+			out = append(out, fmt.Sprintf("+ %d: %s", j+1, strings.TrimSpace(newLines[j])))
+			j++
+		default:
+			return out
+		}
+	}
+	return out
+}
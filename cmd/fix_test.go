@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFixDiffLines(t *testing.T) {
+	removed := fixDiffLines("a\n  console.log(1)\nb\nconsole.log(2)\n", "a\nb\n")
+	if want := []string{"- 2: console.log(1)", "- 4: console.log(2)"}; !slices.Equal(removed, want) {
+		t.Errorf("removed = %q, want %q", removed, want)
+	}
+	added := fixDiffLines("<head>\n</head>\n", "<head>\n  <link rel=\"manifest\">\n</head>\n")
+	if want := []string{`+ 2: <link rel="manifest">`}; !slices.Equal(added, want) {
+		t.Errorf("added = %q, want %q", added, want)
+	}
+}
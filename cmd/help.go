@@ -19,10 +19,20 @@ USAGE:
 
 COMMANDS:
   init          Initialize preflight configuration for your project
-  scan          Run all enabled checks and report results
+  scan          Run all enabled checks and report results (alias: check)
   ignore        Add a check to the ignore list
   unignore      Remove a check from the ignore list
   checks        List all available check IDs
+  serve         Serve scan results in a local web UI (--ui) or a JSON scan API (--api)
+  compare       Show which checks differ between two directories or git refs
+  diff          Show which checks changed between two saved JSON reports
+  remote        Run the filesystem checks on a deployed copy over SSH
+  mcp           Serve checks to AI coding agents over the Model Context Protocol
+  export        List the site's URLs (urls) or draft a GDPR data inventory (data-map)
+  env           Generate or update .env.example from the code (scaffold)
+  report        Write a shareable single-file HTML report
+  fix           Apply automatic fixes for failing checks
+  badge         Write a readiness score badge (SVG or shields.io JSON)
   version       Show version information
   help          Show this help message
 
@@ -37,6 +47,12 @@ EXAMPLES:
   Run in CI mode with JSON output:
     $ preflight scan --ci --format json
 
+  Run only the security checks:
+    $ preflight check --profile security
+
+  Scan each app of a monorepo:
+    $ preflight scan --monorepo
+
   Silence a specific check:
     $ preflight ignore sitemap
     $ preflight ignore llmsTxt
@@ -48,6 +64,24 @@ EXAMPLES:
   List all check IDs:
     $ preflight checks
 
+  Review results in the browser:
+    $ preflight serve --ui
+
+  Share results with a client:
+    $ preflight report -o launch-review.html
+
+  Preview automatic fixes:
+    $ preflight fix --dry-run
+
+  Scan each commit once across CI jobs:
+    $ preflight scan --ci --cache-key $GITHUB_SHA --cache-url s3://ci-cache/preflight
+
+  Check a migration didn't regress anything:
+    $ preflight compare --ref v1.2.0..HEAD
+
+  Audit what's deployed on a server:
+    $ preflight remote --host deploy@web1 --path /var/www/app
+
 EXIT CODES:
   0  All checks passed
   1  Warnings only
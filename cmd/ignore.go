@@ -5,10 +5,16 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/preflightsh/preflight/internal/audit"
+	"github.com/preflightsh/preflight/internal/config"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
+// reasonFlag is the justification recorded in the audit log by ignore and
+// unignore.
+var reasonFlag string
+
 var ignoreCmd = &cobra.Command{
 	Use:   "ignore <check-id> [path]",
 	Short: "Add a check to the ignore list",
@@ -23,12 +29,18 @@ Example:
 To allowlist a single file from the secrets scan (rather than silencing
 the whole check), pass "secrets" and a project-relative path:
 
-  preflight ignore secrets web/js/golden-hour.js`,
+  preflight ignore secrets web/js/golden-hour.js
+
+Every change is recorded in .preflight/audit.log with the time, your git
+identity and the --reason you give:
+
+  preflight ignore secrets --reason "test fixtures only, see SEC-142"`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runIgnore,
 }
 
 func init() {
+	ignoreCmd.Flags().StringVar(&reasonFlag, "reason", "", "Why the check is being ignored (recorded in .preflight/audit.log)")
 	rootCmd.AddCommand(ignoreCmd)
 }
 
@@ -43,30 +55,92 @@ func runIgnore(cmd *cobra.Command, args []string) error {
 	configPath := filepath.Join(cwd, "preflight.yml")
 
 	// Read existing config
+	cfg, err := readConfigMap(configPath)
+	if err != nil {
+		return err
+	}
+	noteHandEdits(cwd, "cli")
+
+	// Two-arg form: `preflight ignore secrets <path>` → append an
+	// allowlist entry instead of silencing the whole check.
+	if len(args) == 2 {
+		if checkID != "secrets" {
+			return fmt.Errorf("per-path ignore is only supported for 'secrets' (got %q)", checkID)
+		}
+		added, err := addSecretsAllowlistEntry(configPath, cfg, args[1])
+		if err != nil || !added {
+			return err
+		}
+		recordAudit(cwd, audit.Entry{Action: audit.ActionAllowlist, CheckID: checkID, Path: args[1], Reason: reasonFlag, Source: "cli"})
+		return nil
+	}
+
+	if !addToIgnoreList(cfg, checkID) {
+		fmt.Printf("'%s' is already in the ignore list\n", checkID)
+		return nil
+	}
+
+	if err := writeConfigMap(configPath, cfg); err != nil {
+		return err
+	}
+
+	recordAudit(cwd, audit.Entry{Action: audit.ActionIgnore, CheckID: checkID, Reason: reasonFlag, Source: "cli"})
+	fmt.Printf("Added '%s' to ignore list\n", checkID)
+	return nil
+}
+
+// recordAudit appends e to the project's audit log. The config change has
+// already been written by then, so a failure is reported, not returned.
+func recordAudit(projectDir string, e audit.Entry) {
+	if err := audit.Append(projectDir, e); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: change not recorded in audit log: %v\n", err)
+	}
+}
+
+// noteHandEdits records edits made to preflight.yml by hand since the last
+// audit entry, before a command makes its own. Scans don't, so reading a
+// project never writes to its audit log.
+func noteHandEdits(projectDir, source string) {
+	if _, err := audit.RecordConfigChange(projectDir, source); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not update audit log: %v\n", err)
+	}
+}
+
+// readConfigMap parses preflight.yml as a generic map so edits preserve
+// keys this binary doesn't know about.
+func readConfigMap(configPath string) (map[string]interface{}, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return fmt.Errorf("preflight.yml not found. Run 'preflight init' first")
+			return nil, fmt.Errorf("preflight.yml not found. Run 'preflight init' first")
 		}
-		return fmt.Errorf("failed to read config: %w", err)
+		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
-	// Parse as generic map to preserve structure
 	var cfg map[string]interface{}
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return fmt.Errorf("failed to parse preflight.yml: %w", err)
+		return nil, fmt.Errorf("failed to parse preflight.yml: %w", err)
+	}
+	if cfg == nil {
+		cfg = map[string]interface{}{}
 	}
+	return cfg, nil
+}
 
-	// Two-arg form: `preflight ignore secrets <path>` → append an
-	// allowlist entry instead of silencing the whole check.
-	if len(args) == 2 {
-		if checkID != "secrets" {
-			return fmt.Errorf("per-path ignore is only supported for 'secrets' (got %q)", checkID)
-		}
-		return addSecretsAllowlistEntry(configPath, cfg, args[1])
+// writeConfigMap serializes cfg back to preflight.yml.
+func writeConfigMap(configPath string, cfg map[string]interface{}) error {
+	newData, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to serialize config: %w", err)
 	}
+	if err := os.WriteFile(configPath, newData, 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
 
-	// Get or create ignore list
+// ignoreListOf returns the string entries of cfg's top-level ignore list.
+func ignoreListOf(cfg map[string]interface{}) []string {
 	var ignoreList []string
 	if existing, ok := cfg["ignore"]; ok {
 		if list, ok := existing.([]interface{}); ok {
@@ -77,38 +151,39 @@ func runIgnore(cmd *cobra.Command, args []string) error {
 			}
 		}
 	}
+	return ignoreList
+}
 
-	// Check if already ignored
+// addToIgnoreList appends checkID to cfg's ignore list, reporting false
+// when it was already there.
+func addToIgnoreList(cfg map[string]interface{}, checkID string) bool {
+	ignoreList := ignoreListOf(cfg)
 	for _, id := range ignoreList {
 		if id == checkID {
-			fmt.Printf("'%s' is already in the ignore list\n", checkID)
-			return nil
+			return false
 		}
 	}
+	cfg["ignore"] = append(ignoreList, checkID)
+	return true
+}
 
-	// Add to ignore list
-	ignoreList = append(ignoreList, checkID)
-	cfg["ignore"] = ignoreList
-
-	// Write back
-	newData, err := yaml.Marshal(cfg)
-	if err != nil {
-		return fmt.Errorf("failed to serialize config: %w", err)
-	}
-
-	if err := os.WriteFile(configPath, newData, 0644); err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
+// setSnooze records that checkID is snoozed through until (a
+// config.SnoozeDateLayout date), replacing any earlier snooze for it.
+func setSnooze(cfg map[string]interface{}, checkID, until string) {
+	snooze, _ := cfg["snooze"].(map[string]interface{})
+	if snooze == nil {
+		snooze = map[string]interface{}{}
+		cfg["snooze"] = snooze
 	}
-
-	fmt.Printf("Added '%s' to ignore list\n", checkID)
-	return nil
+	snooze[checkID] = until
 }
 
 // addSecretsAllowlistEntry appends {path: <path>} to
 // checks.secrets.allowlist in preflight.yml. It does not set a
 // fingerprint — users can edit the file to pin one (recommended; see
-// README). Intermediate maps and lists are created as needed.
-func addSecretsAllowlistEntry(configPath string, cfg map[string]interface{}, path string) error {
+// README). Intermediate maps and lists are created as needed. It reports
+// whether an entry was added.
+func addSecretsAllowlistEntry(configPath string, cfg map[string]interface{}, path string) (bool, error) {
 	checksRaw, _ := cfg["checks"].(map[string]interface{})
 	if checksRaw == nil {
 		checksRaw = map[string]interface{}{}
@@ -131,7 +206,7 @@ func addSecretsAllowlistEntry(configPath string, cfg map[string]interface{}, pat
 		if entry, ok := item.(map[string]interface{}); ok {
 			if p, _ := entry["path"].(string); p == path {
 				fmt.Printf("'%s' is already in the secrets allowlist\n", path)
-				return nil
+				return false, nil
 			}
 		}
 	}
@@ -139,16 +214,12 @@ func addSecretsAllowlistEntry(configPath string, cfg map[string]interface{}, pat
 	allowlist = append(allowlist, map[string]interface{}{"path": path})
 	secretsRaw["allowlist"] = allowlist
 
-	newData, err := yaml.Marshal(cfg)
-	if err != nil {
-		return fmt.Errorf("failed to serialize config: %w", err)
-	}
-	if err := os.WriteFile(configPath, newData, 0644); err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
+	if err := writeConfigMap(configPath, cfg); err != nil {
+		return false, err
 	}
 
 	fmt.Printf("Added '%s' to secrets allowlist. Consider adding a fingerprint to re-alert on key rotation (see README).\n", path)
-	return nil
+	return true, nil
 }
 
 // Also add an unignore command
@@ -164,6 +235,7 @@ Example:
 }
 
 func init() {
+	unignoreCmd.Flags().StringVar(&reasonFlag, "reason", "", "Why the check is being re-enabled (recorded in .preflight/audit.log)")
 	rootCmd.AddCommand(unignoreCmd)
 }
 
@@ -177,35 +249,16 @@ func runUnignore(cmd *cobra.Command, args []string) error {
 
 	configPath := filepath.Join(cwd, "preflight.yml")
 
-	data, err := os.ReadFile(configPath)
+	cfg, err := readConfigMap(configPath)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("preflight.yml not found. Run 'preflight init' first")
-		}
-		return fmt.Errorf("failed to read config: %w", err)
-	}
-
-	var cfg map[string]interface{}
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return fmt.Errorf("failed to parse preflight.yml: %w", err)
-	}
-
-	// Get ignore list
-	var ignoreList []string
-	if existing, ok := cfg["ignore"]; ok {
-		if list, ok := existing.([]interface{}); ok {
-			for _, item := range list {
-				if s, ok := item.(string); ok {
-					ignoreList = append(ignoreList, s)
-				}
-			}
-		}
+		return err
 	}
+	noteHandEdits(cwd, "cli")
 
 	// Find and remove
 	found := false
 	var newList []string
-	for _, id := range ignoreList {
+	for _, id := range ignoreListOf(cfg) {
 		if id == checkID {
 			found = true
 		} else {
@@ -225,15 +278,11 @@ func runUnignore(cmd *cobra.Command, args []string) error {
 		delete(cfg, "ignore")
 	}
 
-	newData, err := yaml.Marshal(cfg)
-	if err != nil {
-		return fmt.Errorf("failed to serialize config: %w", err)
-	}
-
-	if err := os.WriteFile(configPath, newData, 0644); err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
+	if err := writeConfigMap(configPath, cfg); err != nil {
+		return err
 	}
 
+	recordAudit(cwd, audit.Entry{Action: audit.ActionUnignore, CheckID: checkID, Reason: reasonFlag, Source: "cli"})
 	fmt.Printf("Removed '%s' from ignore list\n", checkID)
 	return nil
 }
@@ -251,7 +300,10 @@ var listChecksCmd = &cobra.Command{
 		fmt.Println("  - canonical")
 		fmt.Println("  - structured_data")
 		fmt.Println("  - indexNow (opt-in)")
+		fmt.Println("  - search_console")
 		fmt.Println("  - ogTwitter")
+		fmt.Println("  - twitter_card (when urls.production is set)")
+		fmt.Println("  - hreflang (when urls.production is set)")
 		fmt.Println("  - viewport")
 		fmt.Println("  - lang")
 		fmt.Println()
@@ -261,35 +313,85 @@ var listChecksCmd = &cobra.Command{
 		fmt.Println("  - ssl")
 		fmt.Println("  - www_redirect")
 		fmt.Println("  - email_auth (opt-in)")
+		fmt.Println("  - bimi (opt-in)")
+		fmt.Println("  - ipv6 (opt-in)")
+		fmt.Println("  - cloudflare_zone (opt-in)")
+		fmt.Println("  - app_links (when apps are declared)")
 		fmt.Println("  - secrets")
+		fmt.Println("  - bot_protection")
+		fmt.Println("  - rate_limit")
 		fmt.Println()
 
 		fmt.Println("Environment & Health:")
 		fmt.Println("  - envParity")
 		fmt.Println("  - healthEndpoint")
+		fmt.Println("  - routes (opt-in)")
+		fmt.Println("  - auth_routes (when checks.authRoutes is set)")
+		fmt.Println("  - smoke (when checks.smoke.endpoints is set)")
+		fmt.Println("  - drift (opt-in)")
+		fmt.Println("  - mirrors (when checks.mirrors.urls is set)")
+		fmt.Println("  - ops_readiness (opt-in)")
+		fmt.Println("  - changelog (opt-in)")
+		fmt.Println("  - deployed_version (opt-in)")
+		fmt.Println("  - rollback (opt-in)")
+		fmt.Println("  - stale_flags (opt-in, or when a feature flag service is declared)")
 		fmt.Println()
 
 		fmt.Println("Code Quality & Performance:")
 		fmt.Println("  - vulnerability")
+		fmt.Println("  - osv (opt-in)")
+		fmt.Println("  - supply_chain")
+		fmt.Println("  - dockerfile")
+		fmt.Println("  - compose")
+		fmt.Println("  - iac")
+		fmt.Println("  - dependencies")
+		fmt.Println("  - outdated_deps (opt-in)")
 		fmt.Println("  - debug_statements")
+		fmt.Println("  - debug_mode")
+		fmt.Println("  - localhost_urls")
+		fmt.Println("  - redirect_ssrf")
+		fmt.Println("  - sql_injection")
+		fmt.Println("  - legacy_artifacts")
+		fmt.Println("  - analytics_ids")
 		fmt.Println("  - error_pages")
 		fmt.Println("  - image_optimization")
+		fmt.Println("  - image_alt")
+		fmt.Println("  - fonts")
+		fmt.Println("  - resilience (opt-in)")
+		fmt.Println()
+
+		fmt.Println("Services:")
+		fmt.Println("  - required_services (when require: is set)")
 		fmt.Println()
 
 		fmt.Println("Legal & Compliance:")
 		fmt.Println("  - legal_pages")
+		fmt.Println("  - cookies")
+		fmt.Println("  - regulated_gating (when compliance: is set)")
+		fmt.Println("  - a11y_statement (opt-in)")
+		fmt.Println("  - refund_policy (when a payments service is declared)")
 		fmt.Println()
 
 		fmt.Println("Web Standard Files:")
 		fmt.Println("  - favicon")
 		fmt.Println("  - robotsTxt")
 		fmt.Println("  - sitemap")
+		fmt.Println("  - sitemap_coverage")
 		fmt.Println("  - llmsTxt")
 		fmt.Println("  - adsTxt (opt-in)")
 		fmt.Println("  - humansTxt (opt-in)")
 		fmt.Println("  - license (opt-in)")
+		fmt.Println("  - required_files (when checks.requiredFiles is set)")
 		fmt.Println()
 
+		if cfg, err := config.Load("."); err == nil && len(cfg.CustomChecks) > 0 {
+			fmt.Println("Custom (customChecks in preflight.yml):")
+			for _, r := range cfg.CustomChecks {
+				fmt.Printf("  - %s\n", r.ID)
+			}
+			fmt.Println()
+		}
+
 		fmt.Println("=== Services (with validation checks) ===")
 		fmt.Println()
 		fmt.Println("These services have checks that verify proper integration:")
@@ -346,6 +448,13 @@ var listChecksCmd = &cobra.Command{
 		fmt.Println("  - hotjar: Verifies Hotjar tracking code in templates")
 		fmt.Println()
 
+		fmt.Println("Feature Flags:")
+		fmt.Println("  - launchdarkly: Verifies LaunchDarkly SDK key or client initialization")
+		fmt.Println("  - flagsmith: Verifies Flagsmith environment key or SDK initialization")
+		fmt.Println("  - unleash: Verifies Unleash API config or client initialization")
+		fmt.Println("  - posthog_flags: Verifies PostHog feature flags are read in code")
+		fmt.Println()
+
 		fmt.Println("Auth:")
 		fmt.Println("  - auth0: Verifies Auth0 SDK/API configuration")
 		fmt.Println("  - clerk: Verifies Clerk SDK initialization")
@@ -402,8 +511,18 @@ var listChecksCmd = &cobra.Command{
 		fmt.Println("  - iubenda: Verifies Iubenda script in templates")
 		fmt.Println()
 
+		fmt.Println("=== Tags ===")
+		fmt.Println()
+		fmt.Println("  - seo: search and social sharing checks")
+		fmt.Println("  - security: secrets, dependencies, headers, TLS and env checks")
+		fmt.Println("  - files: checks that read the project")
+		fmt.Println("  - services: declared-service checks")
+		fmt.Println("  - network: checks that make requests (--skip network runs offline)")
+		fmt.Println()
+
 		fmt.Println("Use 'preflight ignore <id>' to silence a check or service")
 		fmt.Println("Use 'preflight unignore <id>' to re-enable it")
+		fmt.Println("Use 'preflight scan --only <tag>' or '--skip <tag>' for a one-off run")
 		return nil
 	},
 }
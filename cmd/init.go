@@ -13,6 +13,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/preflightsh/preflight/internal/checks"
 	"github.com/preflightsh/preflight/internal/config"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -115,7 +116,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 	hasAds := promptYesNo(reader, "Does this site serve ads or advertisements?", false)
 
 	// Ask about email authentication
-	checkEmailAuth := promptYesNo(reader, "Check email deliverability on prod (SPF/DMARC records)?", false)
+	checkEmailAuth := promptYesNo(reader, "Check email deliverability on prod (SPF/DKIM/DMARC/MX records)?", false)
 
 	// Ask about humans.txt
 	checkHumansTxt := promptYesNo(reader, "Got a humans.txt crediting the team?", false)
@@ -175,6 +176,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 	cfg := config.PreflightConfig{
 		ProjectName: projectName,
 		Stack:       stack,
+		Stacks:      config.StackList{stack},
 		URLs: config.URLConfig{
 			Staging:    stagingURL,
 			Production: productionURL,
@@ -426,8 +428,15 @@ func detectMainLayout(cwd, stack string) string {
 		"node":    {"views/layout.ejs", "views/layout.pug", "views/layout.hbs"},
 		"laravel": {"resources/views/layouts/app.blade.php", "resources/views/layout.blade.php"},
 		"django":  {"templates/base.html", "templates/layout.html"},
+		"flask":   {"templates/base.html", "templates/layout.html", "app/templates/base.html"},
+		"phoenix": {"lib/*_web/components/layouts/root.html.heex", "lib/*_web/templates/layout/root.html.heex"},
 		"static":  {"index.html"},
 
+		// Meta-frameworks
+		"nuxt":      {"app.vue", "layouts/default.vue", "app/app.vue"},
+		"remix":     {"app/root.tsx", "app/root.jsx"},
+		"sveltekit": {"src/app.html"},
+
 		// Traditional CMS
 		"wordpress": {"wp-content/themes/theme/header.php", "wp-content/themes/theme/functions.php"},
 		"craft":     {"templates/_layout.twig", "templates/_layout.html"},
@@ -449,12 +458,8 @@ func detectMainLayout(cwd, stack string) string {
 	}
 
 	// Check stack-specific paths first
-	if paths, ok := stackLayouts[stack]; ok {
-		for _, path := range paths {
-			if _, err := os.Stat(filepath.Join(cwd, path)); err == nil {
-				return path
-			}
-		}
+	if path, ok := checks.FindPath(cwd, stackLayouts[stack]); ok {
+		return filepath.ToSlash(path)
 	}
 
 	// Universal layout patterns (checked for all stacks)
@@ -599,6 +604,12 @@ func formatServiceName(svc string) string {
 		"segment":          "Segment",
 		"hotjar":           "Hotjar",
 
+		// Feature Flags
+		"launchdarkly":  "LaunchDarkly",
+		"flagsmith":     "Flagsmith",
+		"unleash":       "Unleash",
+		"posthog_flags": "PostHog Feature Flags",
+
 		// Auth
 		"auth0":    "Auth0",
 		"clerk":    "Clerk",
@@ -670,11 +681,18 @@ func formatStackName(stack string) string {
 		"angular": "Angular",
 		"laravel": "Laravel",
 		"django":  "Django",
+		"flask":   "Flask",
+		"phoenix": "Phoenix",
 		"python":  "Python",
 		"go":      "Go",
 		"rust":    "Rust",
 		"static":  "Static Site",
 
+		// Meta-frameworks
+		"nuxt":      "Nuxt",
+		"sveltekit": "SvelteKit",
+		"remix":     "Remix",
+
 		// Traditional CMS
 		"wordpress": "WordPress",
 		"craft":     "Craft CMS",
@@ -719,6 +737,12 @@ func detectStackVersion(cwd, stack string) string {
 		}
 	case "next":
 		return detectNpmVersion(cwd, "next")
+	case "nuxt":
+		return detectNpmVersion(cwd, "nuxt")
+	case "sveltekit":
+		return detectNpmVersion(cwd, "@sveltejs/kit")
+	case "remix":
+		return detectNpmVersion(cwd, "@remix-run/react")
 	case "gatsby":
 		return detectNpmVersion(cwd, "gatsby")
 	case "astro":
@@ -912,6 +936,11 @@ func detectWebRoot(cwd, stack string) string {
 		"craft":     "web",
 		"symfony":   "public",
 		"django":    "static",
+		"flask":     "static",
+		"phoenix":   "priv/static",
+		"nuxt":      "public",
+		"sveltekit": "static",
+		"remix":     "public",
 		"hugo":      "static",
 		"jekyll":    "_site",
 		"gatsby":    "public",
@@ -0,0 +1,370 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+
+	"github.com/preflightsh/preflight/internal/checks"
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp [path]",
+	Short: "Run a Model Context Protocol server for AI coding agents",
+	Long: `Serve preflight to AI coding agents over the Model Context Protocol (MCP),
+on stdin and stdout. Agents get three tools:
+
+  list_checks   every check ID with its title, category, fix estimate and
+                whether it's enabled for the project
+  run_checks    scan the project (optionally --only/--skip style) and return
+                the same report as 'scan --format json'
+  get_result    one check's full result from the last run, with details and
+                file locations
+
+Paths the tools take are relative to [path], the current directory by
+default. Register the server with your agent, e.g. for Claude Code:
+
+  claude mcp add preflight -- preflight mcp
+
+or in Cursor's .cursor/mcp.json:
+
+  {"mcpServers": {"preflight": {"command": "preflight", "args": ["mcp"]}}}`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMCP,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	projectDir, err := resolveProjectDir(args)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	m := &mcpServer{root: projectDir, last: map[string][]checks.CheckResult{}}
+	if err := m.serve(ctx, os.Stdin, os.Stdout); err != nil && !errors.Is(err, context.Canceled) {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+	return nil
+}
+
+// mcpProtocolVersion is the newest MCP revision the server speaks. A
+// client asking for an older one it lists in mcpProtocolVersions gets that.
+const mcpProtocolVersion = "2025-06-18"
+
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC 2.0 error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // absent for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// mcpServer answers MCP requests one at a time. last holds each project's
+// most recent results, for get_result.
+type mcpServer struct {
+	root string
+	last map[string][]checks.CheckResult
+}
+
+// serve reads newline-delimited JSON-RPC messages from in until it's
+// closed, writing responses to out. Stdout is the protocol channel, so
+// nothing else may print to it while the server runs.
+func (m *mcpServer) serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	dec := json.NewDecoder(in)
+	enc := json.NewEncoder(out)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			// The stream can't be resynchronized after a syntax error.
+			_ = enc.Encode(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"),
+				Error: &rpcError{Code: rpcParseError, Message: err.Error()}})
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var req rpcRequest
+		if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+			_ = enc.Encode(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"),
+				Error: &rpcError{Code: rpcInvalidRequest, Message: "not a JSON-RPC 2.0 request"}})
+			continue
+		}
+		result, rerr := m.handle(ctx, req)
+		if req.ID == nil {
+			continue // notifications get no reply
+		}
+		resp := rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rerr}
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (m *mcpServer) handle(ctx context.Context, req rpcRequest) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &p)
+		v := mcpProtocolVersion
+		if slices.Contains(mcpProtocolVersions, p.ProtocolVersion) {
+			v = p.ProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": v,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": "preflight", "version": version},
+			"instructions": "Preflight checks a web project for launch readiness. Call run_checks to scan, " +
+				"fix what fails, then run_checks again (with only: the IDs you fixed) to confirm.",
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": mcpTools}, nil
+	case "tools/call":
+		var p struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+		}
+		var args mcpToolArgs
+		if len(p.Arguments) > 0 {
+			if err := json.Unmarshal(p.Arguments, &args); err != nil {
+				return nil, &rpcError{Code: rpcInvalidParams, Message: "arguments: " + err.Error()}
+			}
+		}
+		var structured any
+		var err error
+		switch p.Name {
+		case "list_checks":
+			structured, err = m.listChecks(args)
+		case "run_checks":
+			structured, err = m.runChecks(ctx, args)
+		case "get_result":
+			structured, err = m.getResult(args)
+		default:
+			return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("unknown tool %q", p.Name)}
+		}
+		// Tool failures go back to the agent as results it can read and
+		// act on, per the spec, rather than as protocol errors.
+		if err != nil {
+			return map[string]any{
+				"content": []map[string]string{{"type": "text", "text": err.Error()}},
+				"isError": true,
+			}, nil
+		}
+		text, _ := json.MarshalIndent(structured, "", "  ")
+		return map[string]any{
+			"content":           []map[string]string{{"type": "text", "text": string(text)}},
+			"structuredContent": structured,
+		}, nil
+	}
+	return nil, &rpcError{Code: rpcMethodNotFound, Message: "method not found: " + req.Method}
+}
+
+// mcpToolArgs is the union of the tools' arguments.
+type mcpToolArgs struct {
+	Path string   `json:"path"`
+	Only []string `json:"only"`
+	Skip []string `json:"skip"`
+	ID   string   `json:"id"`
+}
+
+var mcpPathSchema = map[string]any{
+	"type":        "string",
+	"description": "Project directory, relative to the server's (default: the server's)",
+}
+
+var mcpTools = []map[string]any{
+	{
+		"name":        "list_checks",
+		"title":       "List checks",
+		"description": "List every preflight check ID with its title, category, estimated fix effort, and whether preflight.yml enables it for the project.",
+		"inputSchema": map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"path": mcpPathSchema},
+		},
+		"annotations": map[string]any{"readOnlyHint": true},
+	},
+	{
+		"name":        "run_checks",
+		"title":       "Run checks",
+		"description": "Scan the project and return the launch-readiness report: a summary and each check's outcome, message, fix suggestions and effort. Pass only to re-run just the checks you fixed.",
+		"inputSchema": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path": mcpPathSchema,
+				"only": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Run only these check IDs or tags (seo, security, files, services, network)"},
+				"skip": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Skip these check IDs or tags"},
+			},
+		},
+		// Checks only read the project, but they do make requests to the
+		// configured URLs.
+		"annotations": map[string]any{"readOnlyHint": true, "openWorldHint": true},
+	},
+	{
+		"name":        "get_result",
+		"title":       "Get check result",
+		"description": "Return one check's full result from the last run_checks on the project, including verbose details and the file and line of each finding.",
+		"inputSchema": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path": mcpPathSchema,
+				"id":   map[string]any{"type": "string", "description": "Check ID, as in the run_checks report"},
+			},
+			"required": []string{"id"},
+		},
+		"annotations": map[string]any{"readOnlyHint": true},
+	},
+}
+
+// projectDir resolves a tool's path argument against the server's root.
+func (m *mcpServer) projectDir(p string) (string, error) {
+	dir := m.root
+	if p != "" {
+		if filepath.IsAbs(p) {
+			dir = p
+		} else {
+			dir = filepath.Join(m.root, p)
+		}
+	}
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("path is not a directory: %s", dir)
+	}
+	return dir, nil
+}
+
+// mcpCheck is one entry of list_checks.
+type mcpCheck struct {
+	ID       string        `json:"id"`
+	Title    string        `json:"title"`
+	Category string        `json:"category"`
+	Service  bool          `json:"service,omitempty"`
+	Enabled  bool          `json:"enabled"`
+	Effort   checks.Effort `json:"effort"`
+	Tags     []string      `json:"tags"`
+}
+
+func (m *mcpServer) listChecks(args mcpToolArgs) (any, error) {
+	dir, err := m.projectDir(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	// Without a preflight.yml every check is listed as disabled, which is
+	// true: scan won't run until 'preflight init' writes one.
+	enabled := map[string]bool{}
+	all := append([]checks.Check(nil), checks.Registry...)
+	if cfg, err := config.Load(dir); err == nil {
+		for _, c := range buildEnabledChecks(cfg, dir) {
+			enabled[c.ID()] = true
+		}
+		custom, err := checks.NewCustomChecks(cfg.CustomChecks, serviceCheckIDs())
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range custom {
+			enabled[c.ID()] = true
+		}
+		all = append(all, custom...)
+		for _, id := range cfg.Ignore {
+			delete(enabled, id)
+		}
+	}
+	list := make([]mcpCheck, 0, len(all))
+	for _, c := range all {
+		list = append(list, mcpCheck{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Category: output.Category(c.ID()),
+			Service:  output.IsServiceCheck(c.ID()),
+			Enabled:  enabled[c.ID()],
+			Effort:   checks.EffortFor(c.ID()),
+			Tags:     checks.CheckTags(c),
+		})
+	}
+	return map[string]any{"checks": list}, nil
+}
+
+func (m *mcpServer) runChecks(ctx context.Context, args mcpToolArgs) (any, error) {
+	dir, err := m.projectDir(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	var diagnostics []checks.Diagnostic
+	results, err := scanProject(ctx, dir, cfg, scanOptions{
+		Only:          args.Only,
+		Skip:          args.Skip,
+		OnDiagnostics: func(d []checks.Diagnostic) { diagnostics = d },
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.last[dir] = results
+	report := output.BuildJSONOutput(cfg.ProjectName, results)
+	report.Diagnostics = diagnostics
+	report.Verdict = output.NewVerdict(results, cfg.FailOn)
+	return report, nil
+}
+
+func (m *mcpServer) getResult(args mcpToolArgs) (any, error) {
+	dir, err := m.projectDir(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	results, ok := m.last[dir]
+	if !ok {
+		return nil, errors.New("no results yet for this project; call run_checks first")
+	}
+	for _, r := range results {
+		if r.ID == args.ID {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("check %q did not run in the last run_checks", args.ID)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/checks"
+)
+
+func TestMCPServer(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "preflight.yml"), []byte("projectName: demo\nstack: static\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "app.js"), []byte("console.log('debug')\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"get_result","arguments":{"id":"debug_statements"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"run_checks","arguments":{"only":["debug_statements"]}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"get_result","arguments":{"id":"debug_statements"}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"list_checks"}}`,
+		`{"jsonrpc":"2.0","id":7,"method":"resources/list"}`,
+	}, "\n")
+	var out strings.Builder
+	m := &mcpServer{root: dir, last: map[string][]checks.CheckResult{}}
+	if err := m.serve(context.Background(), strings.NewReader(in), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	type toolResult struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		StructuredContent json.RawMessage `json:"structuredContent"`
+		IsError           bool            `json:"isError"`
+	}
+	responses := map[int]json.RawMessage{}
+	sc := bufio.NewScanner(strings.NewReader(out.String()))
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		var resp struct {
+			ID     int             `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  *rpcError       `json:"error"`
+		}
+		if err := json.Unmarshal(sc.Bytes(), &resp); err != nil {
+			t.Fatalf("bad response line %s: %v", sc.Text(), err)
+		}
+		if resp.Error != nil {
+			responses[resp.ID] = nil
+			if resp.ID != 7 || resp.Error.Code != rpcMethodNotFound {
+				t.Errorf("response %d: unexpected error %+v", resp.ID, resp.Error)
+			}
+			continue
+		}
+		responses[resp.ID] = resp.Result
+	}
+	if len(responses) != 7 {
+		t.Fatalf("got %d responses, want 7 (none for the notification):\n%s", len(responses), out.String())
+	}
+
+	var init struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	json.Unmarshal(responses[1], &init)
+	if init.ProtocolVersion != "2025-03-26" {
+		t.Errorf("protocolVersion = %q, want the client's", init.ProtocolVersion)
+	}
+	if !strings.Contains(string(responses[2]), `"run_checks"`) {
+		t.Errorf("tools/list missing run_checks: %s", responses[2])
+	}
+
+	var early toolResult
+	json.Unmarshal(responses[3], &early)
+	if !early.IsError || !strings.Contains(early.Content[0].Text, "call run_checks first") {
+		t.Errorf("get_result before a run = %s", responses[3])
+	}
+
+	var run toolResult
+	json.Unmarshal(responses[4], &run)
+	var report struct {
+		Project string `json:"project"`
+		Checks  []struct {
+			ID     string `json:"id"`
+			Passed bool   `json:"passed"`
+		} `json:"checks"`
+	}
+	json.Unmarshal(run.StructuredContent, &report)
+	if run.IsError || report.Project != "demo" || len(report.Checks) != 1 || report.Checks[0].Passed {
+		t.Errorf("run_checks = %s", responses[4])
+	}
+
+	var got toolResult
+	json.Unmarshal(responses[5], &got)
+	var result checks.CheckResult
+	json.Unmarshal(got.StructuredContent, &result)
+	if result.ID != "debug_statements" || len(result.Locations) == 0 || result.Locations[0].File != "app.js" {
+		t.Errorf("get_result = %s", responses[5])
+	}
+
+	var list toolResult
+	json.Unmarshal(responses[6], &list)
+	var listed struct {
+		Checks []mcpCheck `json:"checks"`
+	}
+	json.Unmarshal(list.StructuredContent, &listed)
+	found := map[string]mcpCheck{}
+	for _, c := range listed.Checks {
+		found[c.ID] = c
+	}
+	if c := found["debug_statements"]; !c.Enabled || c.Category != "DEBUG" {
+		t.Errorf("debug_statements = %+v", c)
+	}
+	if found["routes"].Enabled {
+		t.Error("routes should not be enabled without checks.routes")
+	}
+}
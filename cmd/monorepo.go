@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/audit"
+	"github.com/preflightsh/preflight/internal/checks"
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/output"
+	"github.com/preflightsh/preflight/internal/telemetry"
+	"github.com/preflightsh/preflight/internal/workspace"
+)
+
+// monorepoApp is one app's scan in a --monorepo run.
+type monorepoApp struct {
+	app workspace.App
+	cfg *config.PreflightConfig
+	// own is set when the app has a preflight.yml of its own.
+	own         bool
+	results     []checks.CheckResult
+	skipped     []string
+	diagnostics []checks.Diagnostic
+}
+
+type monorepoJSON struct {
+	Project string         `json:"project"`
+	Summary output.Summary `json:"summary"`
+	// Verdict covers every app; its blocking IDs are prefixed with the
+	// app's path (apps/web:secrets), app by app.
+	Verdict output.Verdict    `json:"verdict"`
+	Apps    []monorepoAppJSON `json:"apps"`
+}
+
+type monorepoAppJSON struct {
+	Path  string `json:"path"`
+	Stack string `json:"stack"`
+	output.JSONOutput
+}
+
+// runMonorepoScan scans each app of the monorepo at projectDir as its own
+// project and prints one report grouped by app. The exit code covers
+// every app.
+func runMonorepoScan(scanCtx context.Context, projectDir string, cfg *config.PreflightConfig, opts scanOptions, spinner *output.Spinner, tracer *telemetry.Tracer, annotate bool) error {
+	var patterns []string
+	if cfg.Monorepo != nil {
+		patterns = cfg.Monorepo.Workspaces
+	}
+	apps, err := workspace.Discover(projectDir, patterns)
+	if err != nil {
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("monorepo: %w", err)}
+	}
+	if len(apps) == 0 {
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("monorepo: no apps found (set monorepo.workspaces in preflight.yml)")}
+	}
+
+	// --max-duration covers the whole run, not each app: once it's spent,
+	// the apps left skip their checks.
+	if opts.MaxDuration > 0 {
+		opts.Deadline = time.Now().Add(opts.MaxDuration)
+	}
+	scans := make([]monorepoApp, 0, len(apps))
+	for i, app := range apps {
+		appCfg, own, err := monorepoAppConfig(cfg, app)
+		if err != nil {
+			return &ExitError{Code: ExitUsage, Err: fmt.Errorf("%s: %w", app.Path, err)}
+		}
+		scan := monorepoApp{app: app, cfg: appCfg, own: own}
+		appOpts := opts
+		appOpts.Progress = func(msg string) {
+			spinner.Update(fmt.Sprintf("[%d/%d] %s: %s", i+1, len(apps), app.Path, msg))
+		}
+		appOpts.OnSkipped = func(ids []string) { scan.skipped = ids }
+		appOpts.OnDiagnostics = func(diags []checks.Diagnostic) { scan.diagnostics = diags }
+		scan.results, err = scanProject(scanCtx, app.Dir, appCfg, appOpts)
+		if err != nil {
+			spinner.Stop()
+			_ = tracer.Flush(context.Background())
+			if errors.Is(err, context.Canceled) {
+				fmt.Fprintln(os.Stderr, "\nScan cancelled.")
+				return &ExitError{Code: ExitCanceled}
+			}
+			return &ExitError{Code: ExitUsage, Err: fmt.Errorf("%s: %w", app.Path, err)}
+		}
+		// Findings point into the repository, not the app.
+		for r := range scan.results {
+			for l := range scan.results[r].Locations {
+				loc := &scan.results[r].Locations[l]
+				loc.File = path.Join(app.Path, loc.File)
+			}
+		}
+		scans = append(scans, scan)
+	}
+	if err := tracer.Flush(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	spinner.Stop()
+
+	var all []checks.CheckResult
+	for _, s := range scans {
+		all = append(all, s.results...)
+	}
+
+	failOn := cfg.FailOn
+	if failOnFlag != "" {
+		failOn = failOnFlag
+	}
+
+	switch formatFlag {
+	case "json":
+		doc := monorepoJSON{Project: cfg.ProjectName, Summary: output.CalculateSummary(all)}
+		doc.Summary.EffortMinutes = output.RemainingEffortMinutes(all)
+		doc.Verdict = output.NewVerdict(all, failOn)
+		doc.Verdict.Blocking = []string{}
+		for _, s := range scans {
+			report := output.BuildJSONOutput(s.app.Path, s.results)
+			report.Skipped = s.skipped
+			report.Verdict = output.NewVerdict(s.results, failOn)
+			report.Verdict.Scan.Skipped = len(s.skipped)
+			for _, id := range report.Verdict.Blocking {
+				doc.Verdict.Blocking = append(doc.Verdict.Blocking, s.app.Path+":"+id)
+			}
+			doc.Verdict.Scan.Skipped += len(s.skipped)
+			report.Diagnostics = s.diagnostics
+			if s.own {
+				// Apps with their own preflight.yml keep their own trail.
+				report.Audit, _ = audit.Read(s.app.Dir)
+			}
+			doc.Apps = append(doc.Apps, monorepoAppJSON{Path: s.app.Path, Stack: s.cfg.Stack, JSONOutput: report})
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(doc); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+	case "junit":
+		groups := make([]output.ResultGroup, 0, len(scans))
+		for _, s := range scans {
+			groups = append(groups, output.ResultGroup{Name: s.app.Path, Results: s.results})
+		}
+		output.JUnitOutputter{}.OutputGroups(os.Stdout, groups)
+	case "markdown":
+		fmt.Printf("## ✈️ Preflight: %s (%d apps)\n\n", cfg.ProjectName, len(scans))
+		fmt.Println("| App | Stack | Passed | Warnings | Failed |")
+		fmt.Println("|---|---|---|---|---|")
+		for _, s := range scans {
+			sum := output.CalculateSummary(s.results)
+			fmt.Printf("| `%s` | %s | %d | %d | %d |\n", s.app.Path, s.cfg.Stack, sum.OK, sum.Warn, sum.Fail)
+		}
+		fmt.Println()
+		for _, s := range scans {
+			output.MarkdownOutputter{}.Output(os.Stdout, s.app.Path, s.results)
+			fmt.Println()
+		}
+	default:
+		for _, s := range scans {
+			output.HumanOutputter{Verbose: verboseFlag, Skipped: s.skipped, Diagnostics: s.diagnostics}.Output(os.Stdout, s.app.Path, s.results)
+		}
+		fmt.Println()
+		fmt.Printf("Monorepo: %d app(s)\n", len(scans))
+		width := 0
+		for _, s := range scans {
+			width = max(width, len(s.app.Path))
+		}
+		for _, s := range scans {
+			sum := output.CalculateSummary(s.results)
+			icon := "✓"
+			switch {
+			case sum.Fail > 0:
+				icon = "✗"
+			case sum.Warn > 0:
+				icon = "⚠"
+			}
+			fmt.Printf("  %s %-*s  %s, %d passed, %d warnings, %d failed\n", icon, width, s.app.Path, s.cfg.Stack, sum.OK, sum.Warn, sum.Fail)
+		}
+	}
+
+	if annotate {
+		annotationsOut := os.Stdout
+		if formatFlag != "human" {
+			annotationsOut = os.Stderr
+		}
+		output.WriteGitHubAnnotations(annotationsOut, all)
+	}
+
+	if exitCode := applyFailOn(determineExitCode(all), failOn); exitCode != 0 {
+		return &ExitError{Code: exitCode}
+	}
+	return nil
+}
+
+// monorepoAppConfig returns the configuration an app is scanned with: its
+// own preflight.yml when it has one (own is then set), otherwise the
+// root's. An inherited config takes the app's detected stack and keeps only
+// the declared services the app's code uses. The root's URLs describe one
+// deployed site and its custom checks and required files name root paths,
+// so none of them is inherited.
+func monorepoAppConfig(root *config.PreflightConfig, app workspace.App) (cfg *config.PreflightConfig, own bool, err error) {
+	if _, err := os.Stat(filepath.Join(app.Dir, "preflight.yml")); err == nil {
+		cfg, err := config.Load(app.Dir)
+		return cfg, true, err
+	}
+	appCfg := *root
+	appCfg.ProjectName = app.Path
+	if root.ProjectName != "" {
+		appCfg.ProjectName = root.ProjectName + " (" + app.Path + ")"
+	}
+	appCfg.Stack = config.DetectStack(app.Dir)
+	appCfg.Stacks = config.StackList{appCfg.Stack}
+	appCfg.URLs = config.URLConfig{}
+	appCfg.CustomChecks = nil
+	appCfg.Checks.RequiredFiles = nil
+	appCfg.Monorepo = nil
+	detected := config.DetectServices(app.Dir)
+	appCfg.Services = map[string]config.ServiceConfig{}
+	for id, svc := range root.Services {
+		if svc.Declared && detected[id] {
+			appCfg.Services[id] = svc
+		}
+	}
+	return &appCfg, false, nil
+}
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	remoteHostFlag   string
+	remotePathFlag   string
+	remoteBinaryFlag string
+	remoteSSHFlag    string
+	remoteFormatFlag string
+	remoteOnlyFlag   []string
+	remoteSkipFlag   []string
+	remoteFailOnFlag string
+)
+
+var remoteCmd = &cobra.Command{
+	Use:   "remote --host <user@server> --path <dir> [project]",
+	Short: "Run the filesystem checks against a deployed copy over SSH",
+	Long: `Audit what's actually on a server rather than what's in the repository.
+preflight copies itself and the local preflight.yml to a temporary directory
+on the host, runs the filesystem checks there against --path, and prints the
+results here. Nothing is installed; the temporary directory is removed
+afterwards.
+
+Only the filesystem checks run on the host. The checks that fetch the
+configured URLs are left out, since they'd test the same site a local scan
+does; run 'preflight scan' for those.
+
+The host needs a POSIX shell and is reached with the system ssh, so keys,
+agents and ~/.ssh/config apply as usual. When the host's OS or architecture
+differs from this machine's, pass a matching preflight build with --binary.
+
+Example:
+  preflight remote --host deploy@web1 --path /var/www/app
+  preflight remote --host web1 --path /srv/site --binary ./dist/preflight-linux-arm64
+  preflight remote --host web1 --path /srv/site --ssh "ssh -p 2222" --format json
+
+Exit codes match 'preflight scan'.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRemote,
+}
+
+func init() {
+	remoteCmd.Flags().StringVar(&remoteHostFlag, "host", "", "Host to audit, as ssh accepts it (user@server or a ~/.ssh/config alias)")
+	remoteCmd.Flags().StringVar(&remotePathFlag, "path", "", "Deployed project directory on the host")
+	remoteCmd.Flags().StringVar(&remoteBinaryFlag, "binary", "", "preflight build to run on the host, when its platform differs from this machine's")
+	remoteCmd.Flags().StringVar(&remoteSSHFlag, "ssh", "ssh", "ssh command to connect with, including any options")
+	remoteCmd.Flags().StringVar(&remoteFormatFlag, "format", "human", "Output format: human, json or markdown")
+	remoteCmd.Flags().StringSliceVar(&remoteOnlyFlag, "only", nil, "Run only these check/service IDs (comma-separated)")
+	remoteCmd.Flags().StringSliceVar(&remoteSkipFlag, "skip", nil, "Skip these check/service IDs (comma-separated)")
+	remoteCmd.Flags().StringVar(&remoteFailOnFlag, "fail-on", "", "Lowest severity that fails the run: warn (default) or error")
+	_ = remoteCmd.MarkFlagRequired("host")
+	_ = remoteCmd.MarkFlagRequired("path")
+	rootCmd.AddCommand(remoteCmd)
+}
+
+func runRemote(cmd *cobra.Command, args []string) error {
+	switch remoteFormatFlag {
+	case "human", "json", "markdown":
+	default:
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("unknown --format %q (want human, json or markdown)", remoteFormatFlag)}
+	}
+	if err := config.ValidateFailOn(remoteFailOnFlag); err != nil {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+	sshArgs := strings.Fields(remoteSSHFlag)
+	if len(sshArgs) == 0 {
+		return &ExitError{Code: ExitUsage, Err: errors.New("--ssh is empty")}
+	}
+
+	projectDir, err := resolveProjectDir(args)
+	if err != nil {
+		return err
+	}
+	// Load locally first so a broken preflight.yml is reported here, not
+	// as a failure on the host.
+	configPath := filepath.Join(projectDir, "preflight.yml")
+	cfg, err := config.Load(projectDir)
+	if err != nil {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+
+	spinner := output.NewSpinner()
+	spinner.Start("Connecting to " + remoteHostFlag + "...")
+	defer spinner.Stop()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	r := sshRunner{ssh: sshArgs, host: remoteHostFlag}
+
+	uname, err := r.run(ctx, nil, "uname -sm")
+	if err != nil {
+		return remoteError(ctx, fmt.Errorf("connecting to %s: %w", remoteHostFlag, err))
+	}
+	goos, goarch, ok := parseUname(uname)
+	if !ok {
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("%s: unsupported remote platform %q", remoteHostFlag, uname)}
+	}
+	binary := remoteBinaryFlag
+	if binary == "" {
+		if goos != runtime.GOOS || goarch != runtime.GOARCH {
+			return &ExitError{Code: ExitUsage, Err: fmt.Errorf("%s is %s/%s but this preflight is built for %s/%s; pass a %s/%s build with --binary",
+				remoteHostFlag, goos, goarch, runtime.GOOS, runtime.GOARCH, goos, goarch)}
+		}
+		if binary, err = os.Executable(); err != nil {
+			return &ExitError{Code: ExitUsage, Err: err}
+		}
+	}
+
+	tmp, err := r.run(ctx, nil, "mktemp -d /tmp/preflight.XXXXXX")
+	if err != nil {
+		return remoteError(ctx, fmt.Errorf("creating a temporary directory on %s: %w", remoteHostFlag, err))
+	}
+	defer func() {
+		// Not ctx: the directory should go even when the run was cancelled.
+		_, _ = r.run(context.Background(), nil, "rm -rf "+shellQuote(tmp))
+	}()
+
+	spinner.Update("Uploading preflight to " + remoteHostFlag + "...")
+	remoteBin, remoteConfig := tmp+"/preflight", tmp+"/preflight.yml"
+	if err := r.upload(ctx, binary, remoteBin, "700"); err != nil {
+		return remoteError(ctx, err)
+	}
+	if err := r.upload(ctx, configPath, remoteConfig, "600"); err != nil {
+		return remoteError(ctx, err)
+	}
+
+	spinner.Update("Scanning " + remoteHostFlag + ":" + remotePathFlag + "...")
+	scanArgs := []string{remoteBin, "scan", "--ci", "--format", "json", "--files-only", "--config", remoteConfig}
+	if len(remoteOnlyFlag) > 0 {
+		scanArgs = append(scanArgs, "--only", strings.Join(remoteOnlyFlag, ","))
+	}
+	if len(remoteSkipFlag) > 0 {
+		scanArgs = append(scanArgs, "--skip", strings.Join(remoteSkipFlag, ","))
+	}
+	scanArgs = append(scanArgs, remotePathFlag)
+	quoted := make([]string, len(scanArgs))
+	for i, a := range scanArgs {
+		quoted[i] = shellQuote(a)
+	}
+	// The remote scan exits 1 or 2 for findings like a local one does, so
+	// the report on stdout decides whether it ran, not the exit status.
+	out, scanErr := r.run(ctx, nil, strings.Join(quoted, " "))
+	spinner.Stop()
+	report, results, err := output.ReadJSONOutput(strings.NewReader(out))
+	if err != nil {
+		if scanErr != nil {
+			return remoteError(ctx, fmt.Errorf("scan on %s failed: %w", remoteHostFlag, scanErr))
+		}
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("scan on %s: %w", remoteHostFlag, err)}
+	}
+
+	switch remoteFormatFlag {
+	case "json":
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	case "markdown":
+		output.MarkdownOutputter{}.Output(os.Stdout, report.Project, results)
+	default:
+		fmt.Printf("Results from %s:%s\n", remoteHostFlag, remotePathFlag)
+		output.HumanOutputter{Diagnostics: report.Diagnostics}.Output(os.Stdout, report.Project, results)
+	}
+
+	failOn := cfg.FailOn
+	if remoteFailOnFlag != "" {
+		failOn = remoteFailOnFlag
+	}
+	if exitCode := applyFailOn(determineExitCode(results), failOn); exitCode != 0 {
+		return &ExitError{Code: exitCode}
+	}
+	return nil
+}
+
+// remoteError wraps a failed step for exit, mapping an interrupted ssh
+// session to the cancelled exit code.
+func remoteError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		fmt.Fprintln(os.Stderr, "\nRemote scan cancelled.")
+		return &ExitError{Code: ExitCanceled}
+	}
+	return &ExitError{Code: ExitUsage, Err: err}
+}
+
+// sshRunner runs shell commands on one host through the system ssh.
+type sshRunner struct {
+	ssh  []string // the ssh command and its options
+	host string
+}
+
+// run executes command in the host's shell and returns its trimmed stdout.
+// On failure the error carries the remote stderr.
+func (s sshRunner) run(ctx context.Context, stdin io.Reader, command string) (string, error) {
+	args := append(append([]string{}, s.ssh[1:]...), "-o", "BatchMode=yes", "--", s.host, command)
+	c := exec.CommandContext(ctx, s.ssh[0], args...)
+	c.Stdin = stdin
+	var stdout, stderr bytes.Buffer
+	c.Stdout, c.Stderr = &stdout, &stderr
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), fmt.Errorf("%w: %s", err, msg)
+		}
+		return stdout.String(), err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// upload copies a local file to dest on the host and sets its mode.
+func (s sshRunner) upload(ctx context.Context, local, dest, mode string) error {
+	f, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	q := shellQuote(dest)
+	if _, err := s.run(ctx, f, "cat > "+q+" && chmod "+mode+" "+q); err != nil {
+		return fmt.Errorf("uploading %s to %s: %w", filepath.Base(local), s.host, err)
+	}
+	return nil
+}
+
+// parseUname maps `uname -sm` output to a GOOS and GOARCH.
+func parseUname(s string) (goos, goarch string, ok bool) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return "", "", false
+	}
+	switch fields[0] {
+	case "Linux":
+		goos = "linux"
+	case "Darwin":
+		goos = "darwin"
+	case "FreeBSD":
+		goos = "freebsd"
+	default:
+		return "", "", false
+	}
+	switch fields[1] {
+	case "x86_64", "amd64":
+		goarch = "amd64"
+	case "aarch64", "arm64":
+		goarch = "arm64"
+	case "i386", "i686":
+		goarch = "386"
+	case "armv7l", "armv6l":
+		goarch = "arm"
+	default:
+		return "", "", false
+	}
+	return goos, goarch, true
+}
+
+// shellQuote quotes s as one word for a POSIX shell.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-./=:,@") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
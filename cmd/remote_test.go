@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestParseUname(t *testing.T) {
+	cases := []struct {
+		in           string
+		goos, goarch string
+		ok           bool
+	}{
+		{"Linux x86_64", "linux", "amd64", true},
+		{"Linux aarch64", "linux", "arm64", true},
+		{"Darwin arm64", "darwin", "arm64", true},
+		{"FreeBSD amd64", "freebsd", "amd64", true},
+		{"Linux armv7l", "linux", "arm", true},
+		{"Linux riscv64", "", "", false},
+		{"MINGW64_NT-10.0 x86_64", "", "", false},
+		{"Linux", "", "", false},
+	}
+	for _, c := range cases {
+		goos, goarch, ok := parseUname(c.in)
+		if goos != c.goos || goarch != c.goarch || ok != c.ok {
+			t.Errorf("parseUname(%q) = %q, %q, %v; want %q, %q, %v", c.in, goos, goarch, ok, c.goos, c.goarch, c.ok)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh")
+	}
+	for _, s := range []string{"/var/www/app", "", "it's", "a b", "$(rm -rf /)", "`id`", "x;y", `back\slash`} {
+		out, err := exec.Command("sh", "-c", "printf %s "+shellQuote(s)).Output()
+		if err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		if string(out) != s {
+			t.Errorf("shellQuote(%q) round-trips to %q", s, out)
+		}
+	}
+	if got := shellQuote("/srv/site-1"); got != "/srv/site-1" {
+		t.Errorf("plain path quoted as %s", got)
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/audit"
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var reportOutputFlag string
+
+var reportCmd = &cobra.Command{
+	Use:   "report [path]",
+	Short: "Write a shareable single-file HTML report",
+	Long: `Run all enabled checks and write the results as one self-contained HTML
+file: grouped sections, severity colors, suggestions, fix estimates and the
+project's audit log. It has no external assets, so it can be emailed or
+attached to a ticket for clients and stakeholders who won't read terminal
+output.
+
+Unlike scan, report exits 0 once the file is written, whatever the checks
+found; use scan to gate a build.
+
+Example:
+  preflight report
+  preflight report -o launch-review.html ./site`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().StringVarP(&reportOutputFlag, "output", "o", "preflight-report.html", "File to write the report to")
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	projectDir, err := resolveProjectDir(args)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(projectDir)
+	if err != nil {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+
+	spinner := output.NewSpinner()
+	spinner.Start("Preparing scan...")
+	defer spinner.Stop()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	results, err := scanProject(ctx, projectDir, cfg, scanOptions{Verbose: true, Progress: spinner.Update})
+	spinner.Stop()
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, "\nReport cancelled.")
+			return &ExitError{Code: ExitCanceled}
+		}
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+
+	auditLog, err := audit.Read(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not read audit log: %v\n", err)
+	}
+
+	var buf bytes.Buffer
+	output.HTMLOutputter{GeneratedAt: time.Now(), Audit: auditLog}.Output(&buf, cfg.ProjectName, results)
+	if err := os.WriteFile(reportOutputFlag, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	summary := output.CalculateSummary(results)
+	fmt.Printf("Wrote %s (%d passed, %d warnings, %d failed)\n", reportOutputFlag, summary.OK, summary.Warn, summary.Fail)
+	return nil
+}
@@ -8,8 +8,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// Set with -ldflags at release time; see .goreleaser.yml.
 var (
 	version = "dev"
+	commit  = ""
+	date    = ""
 )
 
 var rootCmd = &cobra.Command{
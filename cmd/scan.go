@@ -2,18 +2,27 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/preflightsh/preflight/internal/audit"
+	"github.com/preflightsh/preflight/internal/cache"
 	"github.com/preflightsh/preflight/internal/checks"
 	"github.com/preflightsh/preflight/internal/config"
 	"github.com/preflightsh/preflight/internal/netutil"
 	"github.com/preflightsh/preflight/internal/output"
+	"github.com/preflightsh/preflight/internal/stacks"
+	"github.com/preflightsh/preflight/internal/telemetry"
 	"github.com/spf13/cobra"
 )
 
@@ -24,40 +33,127 @@ var (
 	publishFlag bool
 	onlyFlag    []string
 	skipFlag    []string
+
+	githubAnnotationsFlag bool
+	writeSummaryFlag      string
+	checkProfileFlag      string
+	failOnFlag            string
+	maxDurationFlag       time.Duration
+	cacheKeyFlag          string
+	cacheURLFlag          string
+	profileChecksFlag     bool
+	assertBudgetsFlag     bool
+	configFlag            string
+	filesOnlyFlag         bool
+	monorepoFlag          bool
 )
 
 var scanCmd = &cobra.Command{
-	Use:   "scan [path]",
-	Short: "Scan your project for launch readiness",
+	Use:     "scan [path]",
+	Aliases: []string{"check"},
+	Short:   "Scan your project for launch readiness",
 	Long: `Run all enabled checks against your project and report results.
 If path is provided, scans that directory. Otherwise scans current directory.
 Exits 0 on success, 1 for warnings only, 2 when checks find errors,
-and 64 when preflight could not run (bad path or unreadable config).`,
+and 64 when preflight could not run (bad path or unreadable config).
+
+--profile narrows the run to one slice of the checklist: seo, security,
+compliance, performance or full (everything, the default). preflight.yml's
+profiles: section redefines these or adds its own; --only and --skip then
+narrow the profile further.
+
+--only and --skip take check IDs and tags: seo, security, files (checks
+that read the project), services (declared-service checks) and network
+(checks that make requests). --skip network runs offline.
+
+--max-duration time-boxes the scan for hooks that mustn't block: checks run
+most important first (secrets, vulnerabilities, SSL, env parity and debug
+statements lead), none starts once the budget is spent, and the ones left
+out are listed after the results (on stderr for machine formats, and as
+"skipped" in JSON).
+
+--cache-key lets the stages of a pipeline share one scan: the first stage
+to scan a commit stores its results in the --cache-url store (a directory,
+an http(s) URL, s3://bucket/prefix or gs://bucket/prefix), and later stages
+with the same key print those results instead of re-running the checks.
+
+--profile-checks measures each check: wall and CPU time, files read, bytes
+scanned and network calls. Human output lists the figures under each check,
+followed by the slowest checks; JSON adds a "profile" object to each result.
+
+--assert-budgets warns, on stderr, about each check that ran longer than its
+time budget: 10 seconds for most, more for the ones that walk the whole tree,
+run an audit tool or make many requests.
+
+--monorepo scans each app of a monorepo as a project of its own: the
+directories matched by monorepo.workspaces in preflight.yml, else the
+package.json or pnpm workspaces, else apps/*, packages/* and services/*.
+An app with its own preflight.yml is scanned with it; the others inherit
+the root's, minus its URLs and custom checks, with their own detected stack
+and only the declared services their code uses. The report is grouped by
+app and the exit code covers them all.
+
+--write-summary keeps the launch status in the repository: each scan
+updates a Markdown file (relative to the project, e.g. PREFLIGHT.md) with
+the date, readiness score and outstanding checks, so it shows in pull
+request diffs. Other content in the file is kept, and it isn't rewritten
+when only the date would change.
+
+--files-only leaves out the checks that fetch the configured URLs, and
+--config reads the configuration from another file; 'preflight remote' uses
+both to scan a deployed copy that has no preflight.yml of its own.
+
+Paths the scan can't read (permission errors) or won't enter (the walk
+limits in preflight.yml) are listed after the results, and under
+"diagnostics" in JSON, since findings in them may be missing.`,
 	RunE: runScan,
 }
 
 func init() {
 	rootCmd.AddCommand(scanCmd)
 	scanCmd.Flags().BoolVar(&ciMode, "ci", false, "Run in CI mode (no interactivity)")
-	scanCmd.Flags().StringVar(&formatFlag, "format", "human", "Output format: human or json")
+	scanCmd.Flags().StringVar(&formatFlag, "format", "human", "Output format: human, json, junit or markdown")
 	scanCmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show detailed information about each check")
 	scanCmd.Flags().BoolVar(&publishFlag, "publish", false, "Publish results to your Preflight dashboard (requires 'preflight auth login')")
-	scanCmd.Flags().StringSliceVar(&onlyFlag, "only", nil, "Run only these check/service IDs (comma-separated; see 'preflight checks')")
-	scanCmd.Flags().StringSliceVar(&skipFlag, "skip", nil, "Skip these check/service IDs for this run (comma-separated)")
+	scanCmd.Flags().StringSliceVar(&onlyFlag, "only", nil, "Run only these check/service IDs or tags: seo, security, files, services, network (comma-separated; see 'preflight checks')")
+	scanCmd.Flags().StringVar(&checkProfileFlag, "profile", "", "Run only the checks in this profile: seo, security, compliance, performance, full, or one from preflight.yml")
+	scanCmd.Flags().StringSliceVar(&skipFlag, "skip", nil, "Skip these check/service IDs or tags for this run (comma-separated)")
+	scanCmd.Flags().StringVar(&failOnFlag, "fail-on", "", "Lowest severity that fails the scan: warn (default) or error; overrides failOn in preflight.yml")
+	scanCmd.Flags().DurationVar(&maxDurationFlag, "max-duration", 0, "Stop starting checks after this long, e.g. 30s (most important checks run first)")
+	scanCmd.Flags().BoolVar(&profileChecksFlag, "profile-checks", false, "Report each check's wall and CPU time, files read, bytes scanned and network calls")
+	scanCmd.Flags().BoolVar(&assertBudgetsFlag, "assert-budgets", false, "Warn when a check runs longer than its time budget")
+	scanCmd.Flags().StringVar(&configFlag, "config", "", "Read configuration from this file instead of <path>/preflight.yml")
+	scanCmd.Flags().BoolVar(&monorepoFlag, "monorepo", false, "Scan each app of a monorepo (apps/*, packages/*, services/* or the configured workspaces) and group the report by app")
+	scanCmd.Flags().BoolVar(&filesOnlyFlag, "files-only", false, "Check project files only; make no requests to the configured URLs")
+	scanCmd.Flags().StringVar(&cacheKeyFlag, "cache-key", "", "Reuse results stored under this key (e.g. $GITHUB_SHA), or store them after scanning")
+	scanCmd.Flags().StringVar(&cacheURLFlag, "cache-url", "", "Cache store for --cache-key: a directory, http(s) URL, s3:// or gs:// (default $PREFLIGHT_CACHE_URL)")
+	scanCmd.Flags().BoolVar(&githubAnnotationsFlag, "github-annotations", false, "Emit GitHub Actions annotations for findings with file locations (default on when GITHUB_ACTIONS is set)")
+	scanCmd.Flags().StringVar(&writeSummaryFlag, "write-summary", "", "Write or update a launch status file in the project, e.g. PREFLIGHT.md")
 	_ = scanCmd.RegisterFlagCompletionFunc("only", completeCheckIDs)
 	_ = scanCmd.RegisterFlagCompletionFunc("skip", completeCheckIDs)
+	_ = scanCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
 }
 
-// completeCheckIDs offers every known check ID for --only / --skip shell
-// completion.
+// completeCheckIDs offers the tags and every known check ID for --only /
+// --skip shell completion.
 func completeCheckIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	ids := make([]string, 0, len(checks.Registry))
+	ids := append([]string(nil), checks.AllTags...)
 	for _, c := range checks.Registry {
 		ids = append(ids, c.ID())
 	}
 	return ids, cobra.ShellCompDirectiveNoFileComp
 }
 
+// completeProfiles offers the built-in profile names for --profile.
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names := make([]string, 0, len(checks.Profiles))
+	for name := range checks.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
 // filterChecksByFlags applies the one-off --only / --skip narrowing on top of
 // the config-driven enablement and ignore list. Unknown IDs are an error so a
 // typo doesn't silently scan nothing (or everything).
@@ -66,13 +162,10 @@ func filterChecksByFlags(enabled []checks.Check, only, skip []string) ([]checks.
 		return enabled, nil
 	}
 
-	known := make(map[string]bool, len(checks.Registry))
-	for _, c := range checks.Registry {
-		known[c.ID()] = true
-	}
+	known := knownCheckIDs(enabled)
 	for _, id := range append(append([]string(nil), only...), skip...) {
-		if !known[id] {
-			return nil, fmt.Errorf("unknown check ID %q (run 'preflight checks' to list IDs)", id)
+		if !known[id] && !checks.IsTag(id) {
+			return nil, fmt.Errorf("unknown check ID or tag %q (run 'preflight checks' to list them)", id)
 		}
 	}
 
@@ -85,12 +178,24 @@ func filterChecksByFlags(enabled []checks.Check, only, skip []string) ([]checks.
 		skipSet[id] = true
 	}
 
+	// A check matches a set by its ID or by any of its tags.
+	matches := func(set map[string]bool, c checks.Check) bool {
+		if set[c.ID()] {
+			return true
+		}
+		for _, tag := range checks.CheckTags(c) {
+			if set[tag] {
+				return true
+			}
+		}
+		return false
+	}
 	var filtered []checks.Check
 	for _, c := range enabled {
-		if len(onlySet) > 0 && !onlySet[c.ID()] {
+		if len(onlySet) > 0 && !matches(onlySet, c) {
 			continue
 		}
-		if skipSet[c.ID()] {
+		if matches(skipSet, c) {
 			continue
 		}
 		filtered = append(filtered, c)
@@ -101,70 +206,98 @@ func filterChecksByFlags(enabled []checks.Check, only, skip []string) ([]checks.
 	return filtered, nil
 }
 
+// filterChecksByProfile narrows enabled to the checks in the named profile
+// (--profile). Like --only, a profile naming an unknown check is an error,
+// and so is one that leaves nothing to run.
+func filterChecksByProfile(enabled []checks.Check, name string, custom map[string][]string) ([]checks.Check, error) {
+	ids, err := checks.ProfileCheckIDs(name, custom)
+	if err != nil || ids == nil {
+		return enabled, err
+	}
+	known := knownCheckIDs(enabled)
+	inProfile := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !known[id] {
+			return nil, fmt.Errorf("profile %q: unknown check ID %q (run 'preflight checks' to list IDs)", name, id)
+		}
+		inProfile[id] = true
+	}
+	var filtered []checks.Check
+	for _, c := range enabled {
+		if inProfile[c.ID()] {
+			filtered = append(filtered, c)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("no enabled checks in profile %q (the checks may not apply to this project's config)", name)
+	}
+	return filtered, nil
+}
+
+// knownCheckIDs is the set of IDs --only, --skip and profiles may name.
+// Registry IDs are always valid, even when the check doesn't apply to
+// this project; enabled adds the project's custom checks.
+func knownCheckIDs(enabled []checks.Check) map[string]bool {
+	known := make(map[string]bool, len(checks.Registry))
+	for _, c := range append(append([]checks.Check(nil), checks.Registry...), enabled...) {
+		known[c.ID()] = true
+	}
+	return known
+}
+
 func runScan(cmd *cobra.Command, args []string) error {
 	if !ciMode {
 		CheckForUpdates()
 	}
 
-	// Use provided path or current directory
-	var projectDir string
-	if len(args) > 0 {
-		projectDir = args[0]
-		// Validate the provided path
-		info, err := os.Stat(projectDir)
-		if err != nil {
-			return &ExitError{Code: ExitUsage, Err: fmt.Errorf("path does not exist: %s", projectDir)}
-		}
-		if !info.IsDir() {
-			return &ExitError{Code: ExitUsage, Err: fmt.Errorf("path is not a directory: %s", projectDir)}
-		}
-	} else {
-		var err error
-		projectDir, err = os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to get current directory: %w", err)
-		}
+	switch formatFlag {
+	case "human", "json", "junit", "markdown":
+	default:
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("unknown --format %q (want human, json, junit or markdown)", formatFlag)}
+	}
+	if err := config.ValidateFailOn(failOnFlag); err != nil {
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("--fail-on: %w", err)}
+	}
+	if maxDurationFlag < 0 {
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("--max-duration must be positive")}
+	}
+	// Machine-readable formats keep stdout free of anything but the report.
+	machineFormat := formatFlag != "human"
+
+	projectDir, err := resolveProjectDir(args)
+	if err != nil {
+		return err
 	}
 
 	// Load config
-	cfg, err := config.Load(projectDir)
+	var cfg *config.PreflightConfig
+	if configFlag != "" {
+		cfg, err = config.LoadFile(configFlag)
+	} else {
+		cfg, err = config.Load(projectDir)
+	}
 	if err != nil {
 		msg := fmt.Sprintf("Error: %v", err)
-		if !ciMode {
+		if !ciMode && configFlag == "" {
 			msg += "\nRun 'preflight init' to create a configuration file."
 		}
 		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("%s", msg)}
 	}
-
-	// Create HTTP client with timeout. SafeHTTPClient refuses to dial
-	// private/loopback/metadata IPs so a hostile preflight.yml cannot
-	// coerce checks into probing internal services.
-	//
-	// Configuring a local dev URL (localhost, *.local, *.test,
-	// *.ddev.site etc.) is a trusted-config workflow, so we exempt those
-	// targets, but only those exact host:port pairs. The scan reaches
-	// plenty of URLs the config never vouched for (og:image and
-	// twitter:image are taken verbatim from page content), so exempting
-	// per-target rather than swapping in a wide-open client keeps a
-	// local production URL from also unlocking the metadata endpoint or
-	// a Redis port for the rest of the run.
-	var localAddrs []string
-	for _, raw := range []string{cfg.URLs.Production, cfg.URLs.Staging} {
-		if raw == "" || !checks.IsLocalURL(raw) {
-			continue
-		}
-		if addr := netutil.AddrFromURL(raw); addr != "" {
-			localAddrs = append(localAddrs, addr)
+	if filesOnlyFlag {
+		// Without URLs the network checks skip themselves, as they do for
+		// a project that hasn't configured any.
+		cfg.URLs = config.URLConfig{}
+		if cfg.Checks.StripeWebhook != nil {
+			cfg.Checks.StripeWebhook.URL = ""
 		}
 	}
-	httpClient := netutil.SafeHTTPClientAllowing(2*time.Second, localAddrs)
 
 	// Spinner gives the user something to watch while checks run. Off in
-	// CI and JSON modes (which expect quiet/structured output) and on
-	// non-TTY stdout. The Spinner type handles its own no-op when
+	// CI and machine-readable modes (which expect quiet/structured output)
+	// and on non-TTY stdout. The Spinner type handles its own no-op when
 	// disabled, so we can call its methods unconditionally below.
 	var spinner *output.Spinner
-	if !ciMode && formatFlag != "json" {
+	if !ciMode && !machineFormat {
 		spinner = output.NewSpinner()
 		spinner.Start("Preparing scan...")
 		defer spinner.Stop()
@@ -179,16 +312,360 @@ func runScan(cmd *cobra.Command, args []string) error {
 	scanCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stopSignals()
 
+	// OpenTelemetry tracing is opt-in via the standard OTEL_EXPORTER_OTLP_*
+	// variables; tracer is nil (and every call on it a no-op) otherwise.
+	tracer := telemetry.FromEnv(version)
+
+	var budgetSkipped []string
+	var diagnostics []checks.Diagnostic
+	opts := scanOptions{
+		Verbose:      verboseFlag,
+		Only:         onlyFlag,
+		Skip:         skipFlag,
+		CheckProfile: checkProfileFlag,
+		Progress:     spinner.Update,
+		Tracer:       tracer,
+		MaxDuration:  maxDurationFlag,
+		OnSkipped:    func(ids []string) { budgetSkipped = ids },
+		Profile:      profileChecksFlag,
+		OnDiagnostics: func(diags []checks.Diagnostic) {
+			diagnostics = diags
+		},
+	}
+	var overBudget []string
+	if assertBudgetsFlag {
+		opts.OnOverBudget = func(id string, took, budget time.Duration) {
+			overBudget = append(overBudget, fmt.Sprintf("%s took %s, over its %s budget", id, took.Round(100*time.Millisecond), budget))
+		}
+	}
+
+	// Inline annotations for the pull request diff, written after the
+	// report.
+	annotate := os.Getenv("GITHUB_ACTIONS") == "true"
+	if cmd.Flags().Changed("github-annotations") {
+		annotate = githubAnnotationsFlag
+	}
+
+	if monorepoFlag || (cfg.Monorepo != nil && cfg.Monorepo.Enabled) {
+		if cacheKeyFlag != "" || publishFlag || writeSummaryFlag != "" {
+			return &ExitError{Code: ExitUsage, Err: fmt.Errorf("--cache-key, --publish and --write-summary can't be used with a monorepo scan")}
+		}
+		err := runMonorepoScan(scanCtx, projectDir, cfg, opts, spinner, tracer, annotate)
+		printOverBudget(overBudget)
+		return err
+	}
+
+	// A cache hit replaces the scan. Cache trouble never fails the run; the
+	// stage just scans for itself.
+	var store cache.Store
+	var cacheKey string
+	var results []checks.CheckResult
+	cached := false
+	scannedAt := time.Now()
+	if cacheKeyFlag != "" {
+		configPath := configFlag
+		if configPath == "" {
+			configPath = filepath.Join(projectDir, "preflight.yml")
+		}
+		if cacheKey, err = scanCacheKey(cacheKeyFlag, configPath, opts); err != nil {
+			return &ExitError{Code: ExitUsage, Err: fmt.Errorf("--cache-key: %w", err)}
+		}
+		cacheURL := cacheURLFlag
+		if cacheURL == "" {
+			cacheURL = os.Getenv("PREFLIGHT_CACHE_URL")
+		}
+		if store, err = cache.Open(cacheURL); err != nil {
+			return &ExitError{Code: ExitUsage, Err: fmt.Errorf("--cache-url: %w", err)}
+		}
+		spinner.Update("Checking result cache...")
+		entry, ok, err := loadCachedScan(scanCtx, store, cacheKey)
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "Warning: could not read result cache: %v\n", err)
+		case ok:
+			results, cached = entry.Results, true
+			scannedAt = entry.CreatedAt
+			spinner.Stop()
+			fmt.Fprintf(os.Stderr, "Using cached results for %s (scanned %s by preflight %s)\n",
+				cacheKeyFlag, entry.CreatedAt.Local().Format(time.DateTime), entry.Version)
+		}
+	}
+
+	if !cached {
+		results, err = scanProject(scanCtx, projectDir, cfg, opts)
+		// Export spans even for a cancelled scan; they show where it stopped.
+		if err := tracer.Flush(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if err != nil {
+			spinner.Stop()
+			if errors.Is(err, context.Canceled) {
+				fmt.Fprintln(os.Stderr, "\nScan cancelled.")
+				return &ExitError{Code: ExitCanceled}
+			}
+			return &ExitError{Code: ExitUsage, Err: err}
+		}
+		// A time-boxed scan that left checks out isn't the full result for
+		// the commit, so it isn't offered to later stages.
+		if store != nil && len(budgetSkipped) == 0 {
+			spinner.Update("Saving results to cache...")
+			if err := storeCachedScan(scanCtx, store, cacheKey, cfg.ProjectName, results); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not write result cache: %v\n", err)
+			}
+		}
+	}
+	spinner.Stop()
+	scanDuration := time.Since(scannedAt)
+
+	failOn := cfg.FailOn
+	if failOnFlag != "" {
+		failOn = failOnFlag
+	}
+
+	// Output results
+	var outputter output.Outputter
+	switch formatFlag {
+	case "json":
+		// The audit trail travels with the machine-readable report so
+		// reviewers see which checks were silenced and why.
+		auditLog, err := audit.Read(projectDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not read audit log: %v\n", err)
+		}
+		meta := output.ScanMeta{Version: version, ScannedAt: scannedAt.UTC().Format(time.RFC3339), Cached: cached}
+		if !cached {
+			meta.DurationMs = scanDuration.Milliseconds()
+		}
+		outputter = output.JSONOutputter{Audit: auditLog, Skipped: budgetSkipped, Diagnostics: diagnostics, FailOn: failOn, Scan: meta}
+	case "junit":
+		outputter = output.JUnitOutputter{}
+	case "markdown":
+		outputter = output.MarkdownOutputter{}
+	default:
+		outputter = output.HumanOutputter{Verbose: verboseFlag, Skipped: budgetSkipped, Diagnostics: diagnostics}
+	}
+
+	outputter.Output(os.Stdout, cfg.ProjectName, results)
+	if len(budgetSkipped) > 0 && formatFlag != "human" {
+		fmt.Fprintf(os.Stderr, "Time budget of %s reached; skipped %d check(s): %s\n", maxDurationFlag, len(budgetSkipped), strings.Join(budgetSkipped, ", "))
+	}
+	printOverBudget(overBudget)
+	if len(diagnostics) > 0 && (formatFlag == "junit" || formatFlag == "markdown") {
+		fmt.Fprintf(os.Stderr, "Warning: did not scan %d path(s); findings in them may be missing:\n", len(diagnostics))
+		for _, d := range diagnostics {
+			fmt.Fprintf(os.Stderr, "  %s (%s)\n", d.Path, d.Error)
+		}
+	}
+
+	// The runner reads workflow commands from either stream, so machine
+	// formats send annotations to stderr and keep stdout parseable.
+	if annotate {
+		annotationsOut := os.Stdout
+		if machineFormat {
+			annotationsOut = os.Stderr
+		}
+		output.WriteGitHubAnnotations(annotationsOut, results)
+	}
+
+	// The status file is a convenience; failing to write it is reported
+	// but leaves the exit code to the results.
+	if writeSummaryFlag != "" {
+		if err := writeScanSummary(projectDir, writeSummaryFlag, cfg.ProjectName, results); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not write %s: %v\n", writeSummaryFlag, err)
+		}
+	}
+
+	// Publish to the dashboard if requested. Best-effort: it never changes the
+	// scan's exit code and prints to stderr so JSON output stays clean.
+	if publishFlag {
+		_ = publishScanResults(cfg, projectDir, results)
+	}
+
+	// Show star message on first scan (only in human format)
+	if !machineFormat && isFirstRun("scan_done") {
+		fmt.Println()
+		showStarMessage()
+		markFirstRunComplete("scan_done")
+	}
+
+	// Determine exit code
+	exitCode := applyFailOn(determineExitCode(results), failOn)
+	if exitCode != 0 {
+		return &ExitError{Code: exitCode}
+	}
+
+	return nil
+}
+
+// writeScanSummary updates the --write-summary status file, resolving a
+// relative path against the project rather than the working directory.
+func writeScanSummary(projectDir, path, projectName string, results []checks.CheckResult) error {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectDir, path)
+	}
+	written, err := output.WriteStatusFile(path, output.StatusMarkdown(projectName, results, time.Now()))
+	if err != nil {
+		return err
+	}
+	if written {
+		fmt.Fprintf(os.Stderr, "Updated %s\n", path)
+	}
+	return nil
+}
+
+// resolveProjectDir returns the directory a command should operate on: the
+// first positional argument when given (which must be an existing
+// directory), otherwise the current working directory.
+func resolveProjectDir(args []string) (string, error) {
+	if len(args) == 0 {
+		projectDir, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+		return projectDir, nil
+	}
+	projectDir := args[0]
+	info, err := os.Stat(projectDir)
+	if err != nil {
+		return "", &ExitError{Code: ExitUsage, Err: fmt.Errorf("path does not exist: %s", projectDir)}
+	}
+	if !info.IsDir() {
+		return "", &ExitError{Code: ExitUsage, Err: fmt.Errorf("path is not a directory: %s", projectDir)}
+	}
+	return projectDir, nil
+}
+
+// scanOptions are the per-run knobs shared by every entry point that runs
+// the check suite (scan, serve).
+type scanOptions struct {
+	Verbose bool
+	Only    []string
+	Skip    []string
+	// CheckProfile names the profile (--profile) the run is narrowed to.
+	CheckProfile string
+	// Progress, when set, receives a short status line before each phase
+	// and each check. The spinner's Update method fits directly.
+	Progress func(msg string)
+	// Tracer, when set, records a span for the scan and one per check.
+	Tracer *telemetry.Tracer
+	// MaxDuration, when positive, time-boxes the scan (--max-duration).
+	MaxDuration time.Duration
+	// Deadline, when set along with MaxDuration, is when the budget runs
+	// out instead of MaxDuration from the start of this scan, so several
+	// scans can share one budget.
+	Deadline time.Time
+	// OnSkipped, when set, receives the IDs of the checks the time budget
+	// left out, in report order. They have no result: a check that didn't
+	// run hasn't passed.
+	OnSkipped func(ids []string)
+	// Profile records each check's resource usage in its result
+	// (--profile-checks).
+	Profile bool
+	// OnDiagnostics, when set, receives the paths the scan couldn't read
+	// (permission errors and the like), when there are any.
+	OnDiagnostics func(diags []checks.Diagnostic)
+	// OnOverBudget, when set, is called for each check that ran longer
+	// than its time budget (--assert-budgets).
+	OnOverBudget func(id string, took, budget time.Duration)
+	// Untrusted marks a project that came from someone else's machine (a
+	// serve --api upload). Its preflight.yml can't vouch for local URLs,
+	// a DoH server, a Tor proxy or stack packs, so the scan reaches no
+	// private address and uses the system resolver and built-in packs.
+	Untrusted bool
+}
+
+// printOverBudget lists the checks --assert-budgets caught on stderr.
+func printOverBudget(over []string) {
+	if len(over) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %d check(s) ran over their time budget:\n", len(over))
+	for _, line := range over {
+		fmt.Fprintf(os.Stderr, "  %s\n", line)
+	}
+}
+
+var scanMu sync.Mutex
+
+// scanProject runs every enabled check against projectDir and returns the
+// results in report order. It returns the --only/--skip validation error
+// unchanged so callers can map it to ExitUsage, and ctx.Err() when the scan
+// is cancelled between checks.
+func scanProject(scanCtx context.Context, projectDir string, cfg *config.PreflightConfig, opts scanOptions) ([]checks.CheckResult, error) {
+	// The walk policy, diagnostics and usage counters are process-wide,
+	// so scans take turns (serve can be asked for several at once).
+	scanMu.Lock()
+	defer scanMu.Unlock()
+
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(string) {}
+	}
+
+	// The time budget covers the whole scan, homepage fetches included.
+	// Checks see budgetCtx, so requests in flight when it runs out are
+	// abandoned rather than waited on.
+	budgetCtx := scanCtx
+	if opts.MaxDuration > 0 {
+		deadline := opts.Deadline
+		if deadline.IsZero() {
+			deadline = time.Now().Add(opts.MaxDuration)
+		}
+		var cancel context.CancelFunc
+		budgetCtx, cancel = context.WithDeadline(scanCtx, deadline)
+		defer cancel()
+	}
+
+	packs := stacks.Builtin()
+	if opts.Untrusted {
+		if err := cfg.ValidatePaths(); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if packs, err = stacks.Load(projectDir); err != nil {
+			return nil, fmt.Errorf("stack packs: %w", err)
+		}
+	}
+	checks.ConfigureStacks(packs)
+
+	// Set before any client dials: the safe clients resolve through it.
+	dns := cfg.DNS
+	if opts.Untrusted {
+		dns = config.DNSConfig{}
+	}
+	if err := netutil.ConfigureResolver(dns.DoH, dns.Mode); err != nil {
+		return nil, fmt.Errorf("dns: %w", err)
+	}
+	httpClient := newCheckClient(cfg)
+	if opts.Untrusted {
+		httpClient = netutil.SafeHTTPClient(2 * time.Second)
+		if m := cfg.Checks.Mirrors; m != nil && m.TorProxy != "" {
+			copied := *m
+			copied.TorProxy = ""
+			cfg.Checks.Mirrors = &copied
+		}
+	}
+	if opts.Profile {
+		checks.CountRequests(httpClient)
+	}
+
 	// Create check context. Pre-fetch the homepage once so checks that
 	// need to scan rendered HTML (OG/Twitter and favicon detection for
 	// CMS-driven sites) can share a single request.
 	ctx := checks.Context{
-		Ctx:     scanCtx,
+		Ctx:     budgetCtx,
 		RootDir: projectDir,
 		Config:  cfg,
 		Client:  httpClient,
-		Verbose: verboseFlag,
+		Verbose: opts.Verbose,
+		// Untrusted keeps git and package managers off an upload.
+		Untrusted: opts.Untrusted,
 	}
+	progress("Indexing files...")
+	checks.ResetDiagnostics()
+	checks.ConfigureWalk(cfg.Walk)
+	ctx.Files = checks.BuildFileIndex(projectDir)
 	// Fetch staging and production homepage HTML in parallel. Staging
 	// uses the chosen httpClient (which is the relaxed client when
 	// staging is a local dev URL like *.lndo.site). Production always
@@ -197,13 +674,13 @@ func runScan(cmd *cobra.Command, args []string) error {
 	// If the user has only configured production and it's a local URL,
 	// reuse the relaxed client for that too.
 	if cfg.URLs.Staging != "" || cfg.URLs.Production != "" {
-		spinner.Update("Fetching homepages...")
+		progress("Fetching homepages...")
 		var wg sync.WaitGroup
 		if cfg.URLs.Staging != "" {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				ctx.PageHTMLStaging = checks.FetchPageHTML(scanCtx, httpClient, cfg.URLs.Staging)
+				ctx.PageHTMLStaging = checks.FetchPageHTML(budgetCtx, httpClient, cfg.URLs.Staging)
 			}()
 		}
 		if cfg.URLs.Production != "" {
@@ -214,7 +691,7 @@ func runScan(cmd *cobra.Command, args []string) error {
 				if checks.IsLocalURL(cfg.URLs.Production) {
 					prodClient = httpClient
 				}
-				ctx.PageHTMLProduction = checks.FetchPageHTML(scanCtx, prodClient, cfg.URLs.Production)
+				ctx.PageHTMLProduction = checks.FetchPageHTML(budgetCtx, prodClient, cfg.URLs.Production)
 			}()
 		}
 		wg.Wait()
@@ -227,43 +704,103 @@ func runScan(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Build list of enabled checks
+	// Build list of enabled checks, built-in then the project's own
 	enabledChecks := buildEnabledChecks(cfg, projectDir)
+	customChecks, err := checks.NewCustomChecks(cfg.CustomChecks, serviceCheckIDs())
+	if err != nil {
+		return nil, err
+	}
+	enabledChecks = append(enabledChecks, customChecks...)
+
+	// Like a profile, a severity for a check that doesn't exist is a typo
+	// that would otherwise change nothing.
+	for id := range cfg.Severities {
+		if !knownResultID(id, cfg.CustomChecks) {
+			return nil, fmt.Errorf("severities: unknown check ID %q (run 'preflight checks' to list IDs)", id)
+		}
+	}
 
-	// Filter out ignored checks
-	if len(cfg.Ignore) > 0 {
+	// Filter out ignored and currently snoozed checks
+	now := time.Now()
+	if len(cfg.Ignore) > 0 || len(cfg.Snooze) > 0 {
 		ignoreMap := make(map[string]bool)
 		for _, id := range cfg.Ignore {
 			ignoreMap[id] = true
 		}
 		var filtered []checks.Check
 		for _, check := range enabledChecks {
-			if !ignoreMap[check.ID()] {
+			if !ignoreMap[check.ID()] && !cfg.IsSnoozed(check.ID(), now) {
 				filtered = append(filtered, check)
 			}
 		}
 		enabledChecks = filtered
 	}
 
-	// One-off narrowing via --only / --skip.
-	enabledChecks, err = filterChecksByFlags(enabledChecks, onlyFlag, skipFlag)
+	// One-off narrowing via --profile, then --only / --skip.
+	if opts.CheckProfile != "" {
+		enabledChecks, err = filterChecksByProfile(enabledChecks, opts.CheckProfile, cfg.Profiles)
+		if err != nil {
+			return nil, err
+		}
+	}
+	enabledChecks, err = filterChecksByFlags(enabledChecks, opts.Only, opts.Skip)
 	if err != nil {
-		return &ExitError{Code: ExitUsage, Err: err}
+		return nil, err
 	}
 
 	// Run all checks
-	var results []checks.CheckResult
-	for i, check := range enabledChecks {
+	scanSpan := opts.Tracer.Start("preflight.scan", nil,
+		telemetry.String("preflight.project", cfg.ProjectName),
+		telemetry.String("preflight.stack", cfg.Stack),
+		telemetry.Int("preflight.checks.enabled", len(enabledChecks)))
+	defer scanSpan.End()
+
+	// With a time budget the most important checks run first, and none
+	// starts once it's spent. Results still come back in report order.
+	runOrder := enabledChecks
+	if opts.MaxDuration > 0 {
+		runOrder = checks.SortByPriority(enabledChecks)
+	}
+	byID := make(map[string]checks.CheckResult, len(enabledChecks))
+	var ran []checks.CheckResult
+	for i, check := range runOrder {
 		// Honor Ctrl-C / SIGTERM between checks so a long scan can be
 		// stopped cleanly instead of being killed mid-request.
 		if scanCtx.Err() != nil {
-			spinner.Stop()
-			fmt.Fprintln(os.Stderr, "\nScan cancelled.")
-			return &ExitError{Code: ExitCanceled}
+			scanSpan.SetError("scan cancelled")
+			return nil, scanCtx.Err()
+		}
+		if budgetCtx.Err() != nil {
+			break
 		}
-		spinner.Update(fmt.Sprintf("Running %s (%d/%d)", check.Title(), i+1, len(enabledChecks)))
+		progress(fmt.Sprintf("Running %s (%d/%d)", check.Title(), i+1, len(runOrder)))
+		// The requirement groups are judged from everything before them.
+		if rc, ok := check.(checks.RequiredServicesCheck); ok {
+			rc.Prior = ran
+			check = rc
+		}
+		checkSpan := opts.Tracer.Start("check "+check.ID(), scanSpan,
+			telemetry.String("preflight.check.id", check.ID()))
+		var stopUsage func() checks.Usage
+		if opts.Profile {
+			stopUsage = checks.StartUsage()
+		}
+		started := time.Now()
 		result, err := check.Run(ctx)
+		if opts.OnOverBudget != nil {
+			if took, budget := time.Since(started), checks.TimeBudgetFor(check.ID()); took > budget {
+				opts.OnOverBudget(check.ID(), took, budget)
+			}
+		}
+		if budgetCtx.Err() != nil && scanCtx.Err() == nil {
+			// The budget ran out mid-check; whatever it returned was
+			// cut short, so count it with the ones that didn't run.
+			checkSpan.SetError("time budget exhausted")
+			checkSpan.End()
+			break
+		}
 		if err != nil {
+			checkSpan.SetError(err.Error())
 			// Convert error to failed check result
 			result = checks.CheckResult{
 				ID:       check.ID(),
@@ -273,40 +810,50 @@ func runScan(cmd *cobra.Command, args []string) error {
 				Message:  fmt.Sprintf("Check failed: %v", err),
 			}
 		}
-		results = append(results, result)
-	}
-	spinner.Stop()
-
-	// Output results
-	var outputter output.Outputter
-	if formatFlag == "json" {
-		outputter = output.JSONOutputter{}
-	} else {
-		outputter = output.HumanOutputter{Verbose: verboseFlag}
+		if severity, ok := cfg.Severities[check.ID()]; ok && !result.Passed {
+			result.Severity = checks.Severity(severity)
+		}
+		if stopUsage != nil {
+			usage := stopUsage()
+			result.Profile = &usage
+		}
+		checkSpan.SetAttributes(
+			telemetry.Bool("preflight.check.passed", result.Passed),
+			telemetry.String("preflight.check.severity", string(result.Severity)))
+		checkSpan.End()
+		byID[check.ID()] = result
+		ran = append(ran, result)
 	}
 
-	outputter.Output(os.Stdout, cfg.ProjectName, results)
-
-	// Publish to the dashboard if requested. Best-effort: it never changes the
-	// scan's exit code and prints to stderr so JSON output stays clean.
-	if publishFlag {
-		_ = publishScanResults(cfg, projectDir, results)
+	var results []checks.CheckResult
+	var skipped []string
+	for _, check := range enabledChecks {
+		if result, ok := byID[check.ID()]; ok {
+			results = append(results, result)
+		} else {
+			skipped = append(skipped, check.ID())
+		}
 	}
-
-	// Show star message on first scan (only in human format, not JSON)
-	if formatFlag != "json" && isFirstRun("scan_done") {
-		fmt.Println()
-		showStarMessage()
-		markFirstRunComplete("scan_done")
+	if len(skipped) > 0 {
+		scanSpan.SetAttributes(telemetry.Int("preflight.checks.skipped", len(skipped)))
+		if opts.OnSkipped != nil {
+			opts.OnSkipped(skipped)
+		}
 	}
 
-	// Determine exit code
-	exitCode := determineExitCode(results)
-	if exitCode != 0 {
-		return &ExitError{Code: exitCode}
+	if diags := checks.Diagnostics(projectDir); len(diags) > 0 {
+		scanSpan.SetAttributes(telemetry.Int("preflight.paths.unreadable", len(diags)))
+		if opts.OnDiagnostics != nil {
+			opts.OnDiagnostics(diags)
+		}
 	}
 
-	return nil
+	summary := output.CalculateSummary(results)
+	scanSpan.SetAttributes(
+		telemetry.Int("preflight.checks.ok", summary.OK),
+		telemetry.Int("preflight.checks.warn", summary.Warn),
+		telemetry.Int("preflight.checks.fail", summary.Fail))
+	return results, nil
 }
 
 // serviceChecks maps every declared-service check to its service ID, in
@@ -358,6 +905,11 @@ var serviceChecks = []struct {
 	{"amplitude", checks.AmplitudeCheck},
 	{"segment", checks.SegmentCheck},
 	{"hotjar", checks.HotjarCheck},
+	// Feature flags
+	{"launchdarkly", checks.LaunchDarklyCheck},
+	{"flagsmith", checks.FlagsmithCheck},
+	{"unleash", checks.UnleashCheck},
+	{"posthog_flags", checks.PostHogFlagsCheck},
 	// Infrastructure
 	{"redis", checks.RedisCheck{}},
 	{"sidekiq", checks.SidekiqCheck{}},
@@ -402,6 +954,16 @@ var serviceChecks = []struct {
 	{"iubenda", checks.IubendaCheck{}},
 }
 
+// serviceCheckIDs lists the service checks' IDs, which custom checks
+// can't take.
+func serviceCheckIDs() []string {
+	ids := make([]string, 0, len(serviceChecks))
+	for _, sc := range serviceChecks {
+		ids = append(ids, sc.id)
+	}
+	return ids
+}
+
 func buildEnabledChecks(cfg *config.PreflightConfig, rootDir string) []checks.Check {
 	var enabledChecks []checks.Check
 
@@ -419,7 +981,7 @@ func buildEnabledChecks(cfg *config.PreflightConfig, rootDir string) []checks.Ch
 	// === SEO & Social ===
 	// Auto-enable SEO checks if layout can be detected or explicitly configured
 	seoEnabled := (cfg.Checks.SEOMeta != nil && cfg.Checks.SEOMeta.Enabled) ||
-		canAutoDetectLayout(rootDir, cfg.Stack)
+		canAutoDetectLayout(rootDir, cfg.AllStacks())
 	if seoEnabled {
 		enabledChecks = append(enabledChecks, checks.SEOMetadataCheck{})
 		enabledChecks = append(enabledChecks, checks.CanonicalURLCheck{})
@@ -427,7 +989,14 @@ func buildEnabledChecks(cfg *config.PreflightConfig, rootDir string) []checks.Ch
 		enabledChecks = append(enabledChecks, checks.ViewportCheck{})
 		enabledChecks = append(enabledChecks, checks.LangAttributeCheck{})
 	}
+	if cfg.URLs.Production != "" {
+		enabledChecks = append(enabledChecks, checks.TwitterCardCheck{})
+		enabledChecks = append(enabledChecks, checks.HreflangCheck{})
+	}
 	enabledChecks = append(enabledChecks, checks.StructuredDataCheck{})
+	if seoEnabled || cfg.URLs.Production != "" {
+		enabledChecks = append(enabledChecks, checks.SearchConsoleCheck{})
+	}
 	if cfg.Checks.IndexNow != nil && cfg.Checks.IndexNow.Enabled {
 		enabledChecks = append(enabledChecks, checks.IndexNowCheck{})
 	}
@@ -443,9 +1012,23 @@ func buildEnabledChecks(cfg *config.PreflightConfig, rootDir string) []checks.Ch
 	if cfg.Checks.EmailAuth != nil && cfg.Checks.EmailAuth.Enabled && cfg.URLs.Production != "" {
 		enabledChecks = append(enabledChecks, checks.EmailAuthCheck{})
 	}
+	if cfg.Checks.BIMI != nil && cfg.Checks.BIMI.Enabled && cfg.URLs.Production != "" {
+		enabledChecks = append(enabledChecks, checks.BIMICheck{})
+	}
+	if cfg.Checks.IPv6 != nil && cfg.Checks.IPv6.Enabled && cfg.URLs.Production != "" {
+		enabledChecks = append(enabledChecks, checks.IPv6Check{})
+	}
+	if cfg.Checks.CloudflareZone != nil && cfg.Checks.CloudflareZone.Enabled && cfg.URLs.Production != "" {
+		enabledChecks = append(enabledChecks, checks.CloudflareZoneCheck{})
+	}
+	if cfg.Checks.AppLinks.Declared() && cfg.URLs.Production != "" {
+		enabledChecks = append(enabledChecks, checks.AppLinksCheck{})
+	}
 	if cfg.Checks.Secrets != nil && cfg.Checks.Secrets.Enabled {
 		enabledChecks = append(enabledChecks, checks.SecretScanCheck{})
 	}
+	enabledChecks = append(enabledChecks, checks.BotProtectionCheck{})
+	enabledChecks = append(enabledChecks, checks.RateLimitCheck{})
 
 	// === Environment & Health ===
 	if cfg.Checks.EnvParity != nil && cfg.Checks.EnvParity.Enabled {
@@ -456,6 +1039,41 @@ func buildEnabledChecks(cfg *config.PreflightConfig, rootDir string) []checks.Ch
 		cfg.URLs.Production != "" || cfg.URLs.Staging != "" {
 		enabledChecks = append(enabledChecks, checks.HealthCheck{})
 	}
+	if cfg.Checks.Smoke != nil && len(cfg.Checks.Smoke.Endpoints) > 0 &&
+		(cfg.URLs.Production != "" || cfg.URLs.Staging != "") {
+		enabledChecks = append(enabledChecks, checks.SmokeCheck{})
+	}
+	if cfg.Checks.Routes != nil && cfg.Checks.Routes.Enabled &&
+		(cfg.URLs.Production != "" || cfg.URLs.Staging != "") {
+		enabledChecks = append(enabledChecks, checks.RoutesCheck{})
+	}
+	if len(cfg.Checks.AuthRoutes) > 0 && (cfg.URLs.Production != "" || cfg.URLs.Staging != "") {
+		enabledChecks = append(enabledChecks, checks.AuthRoutesCheck{})
+	}
+	if cfg.Checks.Drift != nil && cfg.Checks.Drift.Enabled && cfg.URLs.Production != "" {
+		enabledChecks = append(enabledChecks, checks.DriftCheck{})
+	}
+	if cfg.Checks.Mirrors != nil && len(cfg.Checks.Mirrors.URLs) > 0 && cfg.URLs.Production != "" {
+		enabledChecks = append(enabledChecks, checks.MirrorsCheck{})
+	}
+	if cfg.Checks.OpsReadiness != nil && cfg.Checks.OpsReadiness.Enabled {
+		enabledChecks = append(enabledChecks, checks.OpsReadinessCheck{})
+	}
+	if cfg.Checks.Changelog != nil && cfg.Checks.Changelog.Enabled {
+		enabledChecks = append(enabledChecks, checks.ChangelogCheck{})
+	}
+	if cfg.Checks.DeployedVersion != nil && cfg.Checks.DeployedVersion.Enabled &&
+		(cfg.URLs.Production != "" || cfg.URLs.Staging != "") {
+		enabledChecks = append(enabledChecks, checks.DeployedVersionCheck{})
+	}
+	if cfg.Checks.Rollback != nil && cfg.Checks.Rollback.Enabled {
+		enabledChecks = append(enabledChecks, checks.RollbackCheck{})
+	}
+	if (cfg.Checks.StaleFlags != nil && cfg.Checks.StaleFlags.Enabled) ||
+		cfg.Services["launchdarkly"].Declared || cfg.Services["flagsmith"].Declared ||
+		cfg.Services["unleash"].Declared || cfg.Services["posthog_flags"].Declared {
+		enabledChecks = append(enabledChecks, checks.StaleFlagsCheck{})
+	}
 
 	// === Services ===
 	// A service check runs when its service is declared in preflight.yml and
@@ -472,17 +1090,54 @@ func buildEnabledChecks(cfg *config.PreflightConfig, rootDir string) []checks.Ch
 
 	// === Code Quality & Performance ===
 	enabledChecks = append(enabledChecks, checks.VulnerabilityCheck{})
+	if cfg.Checks.OSV != nil && cfg.Checks.OSV.Enabled {
+		enabledChecks = append(enabledChecks, checks.OSVCheck{})
+	}
+	enabledChecks = append(enabledChecks, checks.SupplyChainCheck{})
+	enabledChecks = append(enabledChecks, checks.DockerfileCheck{})
+	enabledChecks = append(enabledChecks, checks.ComposeCheck{})
+	enabledChecks = append(enabledChecks, checks.IaCCheck{})
+	enabledChecks = append(enabledChecks, checks.DependenciesCheck{})
+	if cfg.Checks.OutdatedDeps != nil && cfg.Checks.OutdatedDeps.Enabled {
+		enabledChecks = append(enabledChecks, checks.OutdatedDepsCheck{})
+	}
 	enabledChecks = append(enabledChecks, checks.DebugStatementsCheck{})
+	enabledChecks = append(enabledChecks, checks.DebugModeCheck{})
+	enabledChecks = append(enabledChecks, checks.LocalhostURLsCheck{})
+	enabledChecks = append(enabledChecks, checks.RedirectSSRFCheck{})
+	enabledChecks = append(enabledChecks, checks.SQLInjectionCheck{})
+	enabledChecks = append(enabledChecks, checks.LegacyArtifactsCheck{})
+	enabledChecks = append(enabledChecks, checks.AnalyticsIDsCheck{})
 	enabledChecks = append(enabledChecks, checks.ErrorPagesCheck{})
 	enabledChecks = append(enabledChecks, checks.ImageOptimizationCheck{})
+	enabledChecks = append(enabledChecks, checks.ImageAltCheck{})
+	enabledChecks = append(enabledChecks, checks.FontLoadingCheck{})
+	if cfg.Checks.Resilience != nil && cfg.Checks.Resilience.Enabled {
+		enabledChecks = append(enabledChecks, checks.ResilienceCheck{})
+	}
 
 	// === Legal & Compliance ===
 	enabledChecks = append(enabledChecks, checks.LegalPagesCheck{})
+	if cfg.URLs.Production != "" {
+		enabledChecks = append(enabledChecks, checks.CookieInventoryCheck{})
+	}
+	if len(cfg.Compliance) > 0 {
+		enabledChecks = append(enabledChecks, checks.RegulatedGatingCheck{})
+	}
+	if cfg.Checks.A11yStatement != nil && cfg.Checks.A11yStatement.Enabled {
+		enabledChecks = append(enabledChecks, checks.A11yStatementCheck{})
+	}
+	if slices.ContainsFunc(checks.PaymentServices, func(id string) bool { return cfg.Services[id].Declared }) {
+		enabledChecks = append(enabledChecks, checks.RefundPolicyCheck{})
+	}
 
 	// === Web Standard Files ===
 	enabledChecks = append(enabledChecks, checks.FaviconCheck{})
 	enabledChecks = append(enabledChecks, checks.RobotsTxtCheck{})
 	enabledChecks = append(enabledChecks, checks.SitemapCheck{})
+	if slices.ContainsFunc(cfg.AllStacks(), checks.IsStaticSiteStack) {
+		enabledChecks = append(enabledChecks, checks.SitemapCoverageCheck{})
+	}
 	enabledChecks = append(enabledChecks, checks.LLMsTxtCheck{})
 	if cfg.Checks.AdsTxt != nil && cfg.Checks.AdsTxt.Enabled {
 		enabledChecks = append(enabledChecks, checks.AdsTxtCheck{})
@@ -493,10 +1148,47 @@ func buildEnabledChecks(cfg *config.PreflightConfig, rootDir string) []checks.Ch
 	if cfg.Checks.License != nil && cfg.Checks.License.Enabled {
 		enabledChecks = append(enabledChecks, checks.LicenseCheck{})
 	}
+	if len(cfg.Checks.RequiredFiles) > 0 {
+		enabledChecks = append(enabledChecks, checks.RequiredFilesCheck{})
+	}
+
+	// === Required service groups ===
+	// Last, because it judges the service checks' results.
+	if len(cfg.Require) > 0 {
+		enabledChecks = append(enabledChecks, checks.RequiredServicesCheck{})
+	}
 
 	return enabledChecks
 }
 
+// newCheckClient returns the HTTP client checks make requests with. It has
+// a short timeout, and refuses private addresses other than the project's
+// own local dev URLs.
+func newCheckClient(cfg *config.PreflightConfig) *http.Client {
+	// SafeHTTPClient refuses to dial private/loopback/metadata IPs so a
+	// hostile preflight.yml cannot coerce checks into probing internal
+	// services.
+	//
+	// Configuring a local dev URL (localhost, *.local, *.test,
+	// *.ddev.site etc.) is a trusted-config workflow, so we exempt those
+	// targets, but only those exact host:port pairs. The scan reaches
+	// plenty of URLs the config never vouched for (og:image and
+	// twitter:image are taken verbatim from page content), so exempting
+	// per-target rather than swapping in a wide-open client keeps a
+	// local production URL from also unlocking the metadata endpoint or
+	// a Redis port for the rest of the run.
+	var localAddrs []string
+	for _, raw := range []string{cfg.URLs.Production, cfg.URLs.Staging} {
+		if raw == "" || !checks.IsLocalURL(raw) {
+			continue
+		}
+		if addr := netutil.AddrFromURL(raw); addr != "" {
+			localAddrs = append(localAddrs, addr)
+		}
+	}
+	return netutil.SafeHTTPClientAllowing(2*time.Second, localAddrs)
+}
+
 func determineExitCode(results []checks.CheckResult) int {
 	hasError := false
 	hasWarning := false
@@ -521,61 +1213,18 @@ func determineExitCode(results []checks.CheckResult) int {
 	return ExitOK
 }
 
-// canAutoDetectLayout checks if a layout file can be auto-detected for SEO checks
-func canAutoDetectLayout(rootDir, stack string) bool {
-	// Common layout files by stack
-	layoutsByStack := map[string][]string{
-		"next": {
-			"app/layout.tsx", "app/layout.js", "app/layout.jsx",
-			"src/app/layout.tsx", "src/app/layout.js", "src/app/layout.jsx",
-			"pages/_app.tsx", "pages/_app.js", "pages/_document.tsx", "pages/_document.js",
-		},
-		"react":   {"index.html", "public/index.html", "src/index.html"},
-		"vite":    {"index.html", "src/index.html"},
-		"vue":     {"index.html", "public/index.html", "src/App.vue"},
-		"svelte":  {"src/app.html", "index.html"},
-		"angular": {"src/index.html"},
-		"rails": {
-			"app/views/layouts/application.html.erb",
-			"app/views/layouts/base.html.erb",
-		},
-		"laravel": {
-			"resources/views/layouts/app.blade.php",
-			"resources/views/layouts/main.blade.php",
-		},
-		"django": {"templates/base.html", "templates/layout.html"},
-		"craft": {
-			"templates/_layout.twig",
-			"templates/_layouts/main.twig",
-			"templates/_layouts/base.twig",
-		},
-		"hugo":     {"layouts/_default/baseof.html"},
-		"jekyll":   {"_layouts/default.html", "_layouts/base.html"},
-		"gatsby":   {"src/components/layout.js", "src/components/Layout.js"},
-		"astro":    {"src/layouts/Layout.astro", "src/layouts/Base.astro"},
-		"eleventy": {"_includes/base.njk", "_includes/layout.njk"},
-	}
-
-	// Check stack-specific layouts
-	if layouts, ok := layoutsByStack[stack]; ok {
-		for _, layout := range layouts {
-			if _, err := os.Stat(filepath.Join(rootDir, layout)); err == nil {
-				return true
-			}
-		}
-	}
-
-	// Fallback: try common layouts
-	commonLayouts := []string{
-		"app/layout.tsx", "app/layout.js",
-		"src/app/layout.tsx", "src/app/layout.js",
-		"index.html", "public/index.html",
-	}
-	for _, layout := range commonLayouts {
-		if _, err := os.Stat(filepath.Join(rootDir, layout)); err == nil {
-			return true
-		}
+// applyFailOn lowers the exit code for findings below the failure
+// threshold. With "error", a warnings-only scan exits 0; errors still
+// exit 2 so the gate keeps its meaning.
+func applyFailOn(exitCode int, failOn string) int {
+	if failOn == "error" && exitCode == ExitWarn {
+		return ExitOK
 	}
+	return exitCode
+}
 
-	return false
+// canAutoDetectLayout checks if a layout file can be auto-detected for SEO
+// checks, the way they detect it: from the stack packs' layouts.
+func canAutoDetectLayout(rootDir string, stacks []string) bool {
+	return checks.DetectLayout(rootDir, stacks) != ""
 }
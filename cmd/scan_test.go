@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/preflightsh/preflight/internal/checks"
+	"github.com/preflightsh/preflight/internal/config"
 )
 
 func TestDetermineExitCode(t *testing.T) {
@@ -68,6 +75,26 @@ func TestDetermineExitCode(t *testing.T) {
 	}
 }
 
+func TestApplyFailOn(t *testing.T) {
+	cases := []struct {
+		exitCode int
+		failOn   string
+		want     int
+	}{
+		{ExitWarn, "", ExitWarn},
+		{ExitWarn, "warn", ExitWarn},
+		{ExitWarn, "error", ExitOK},
+		{ExitFail, "error", ExitFail},
+		{ExitOK, "error", ExitOK},
+		{ExitFail, "warn", ExitFail},
+	}
+	for _, tc := range cases {
+		if got := applyFailOn(tc.exitCode, tc.failOn); got != tc.want {
+			t.Errorf("applyFailOn(%d, %q) = %d, want %d", tc.exitCode, tc.failOn, got, tc.want)
+		}
+	}
+}
+
 // The codes are a published contract (README, scan --help) that CI
 // pipelines branch on. In particular ExitUsage must stay outside the
 // 0-2 range so "preflight could not run" is distinguishable from "your
@@ -97,3 +124,154 @@ func TestFilterChecksByFlagsRejectsUnknownID(t *testing.T) {
 		t.Error("filterChecksByFlags accepted an unknown --skip ID, want error")
 	}
 }
+
+func TestFilterChecksByFlagsAcceptsCustomCheck(t *testing.T) {
+	custom := checks.CustomCheck{Rule: config.CustomCheckConfig{ID: "privacy", File: "privacy.html"}}
+	enabled := []checks.Check{checks.SecretScanCheck{}, custom}
+	got, err := filterChecksByFlags(enabled, []string{"privacy"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID() != "privacy" {
+		t.Errorf("got %v, want only the custom check", got)
+	}
+}
+
+// A spent budget must leave checks out of the results, not pass them:
+// a partial scan that reported "Ready for launch" would be worse than none.
+func TestScanProjectMaxDuration(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.PreflightConfig{ProjectName: "x"}
+	cfg.Checks.Secrets = &config.SecretsConfig{Enabled: true}
+
+	var skipped []string
+	results, err := scanProject(context.Background(), dir, cfg, scanOptions{
+		Only:        []string{"debug_statements", "secrets"},
+		MaxDuration: time.Nanosecond,
+		OnSkipped:   func(ids []string) { skipped = ids },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("results = %v, want none once the budget is spent", results)
+	}
+	if want := []string{"secrets", "debug_statements"}; !slices.Equal(skipped, want) {
+		t.Errorf("skipped = %v, want %v in report order", skipped, want)
+	}
+
+	skipped = nil
+	results, err = scanProject(context.Background(), dir, cfg, scanOptions{
+		Only:        []string{"debug_statements"},
+		MaxDuration: time.Minute,
+		OnSkipped:   func(ids []string) { skipped = ids },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || skipped != nil {
+		t.Errorf("results = %v, skipped = %v, want the one check run", results, skipped)
+	}
+
+	// A shared deadline spent by an earlier scan leaves nothing to run,
+	// however long MaxDuration is.
+	skipped = nil
+	results, err = scanProject(context.Background(), dir, cfg, scanOptions{
+		Only:        []string{"debug_statements"},
+		MaxDuration: time.Minute,
+		Deadline:    time.Now().Add(-time.Second),
+		OnSkipped:   func(ids []string) { skipped = ids },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 || !slices.Equal(skipped, []string{"debug_statements"}) {
+		t.Errorf("results = %v, skipped = %v, want the check skipped", results, skipped)
+	}
+}
+
+func TestScanProjectSeverityOverride(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.js"), []byte("console.log('debug')\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.PreflightConfig{ProjectName: "x", Severities: map[string]string{"debug_statements": "error"}}
+	results, err := scanProject(context.Background(), dir, cfg, scanOptions{Only: []string{"debug_statements"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Passed || results[0].Severity != checks.SeverityError {
+		t.Fatalf("results = %+v, want one failure raised to error", results)
+	}
+	if code := determineExitCode(results); code != ExitFail {
+		t.Errorf("exit code = %d, want %d", code, ExitFail)
+	}
+
+	cfg.Severities = map[string]string{"debug_statement": "error"}
+	if _, err := scanProject(context.Background(), dir, cfg, scanOptions{Only: []string{"debug_statements"}}); err == nil || !strings.Contains(err.Error(), `"debug_statement"`) {
+		t.Errorf("err = %v, want an unknown check ID error", err)
+	}
+}
+
+// A custom check named like a service check would report a second result
+// under that ID.
+func TestScanProjectRejectsServiceCustomID(t *testing.T) {
+	cfg := &config.PreflightConfig{ProjectName: "x", CustomChecks: []config.CustomCheckConfig{{ID: "sentry", File: "app.js"}}}
+	if _, err := scanProject(context.Background(), t.TempDir(), cfg, scanOptions{}); err == nil || !strings.Contains(err.Error(), "customChecks.sentry") {
+		t.Errorf("err = %v, want the id rejected", err)
+	}
+}
+
+func TestFilterChecksByProfile(t *testing.T) {
+	enabled := []checks.Check{checks.SecretScanCheck{}, checks.SEOMetadataCheck{}, checks.SSLCheck{}}
+	got, err := filterChecksByProfile(enabled, "security", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ID() != "secrets" || got[1].ID() != "ssl" {
+		t.Errorf("security = %v, want secrets and ssl", got)
+	}
+	if got, err := filterChecksByProfile(enabled, "full", nil); err != nil || len(got) != 3 {
+		t.Errorf("full = %v, %v; want every enabled check", got, err)
+	}
+	if _, err := filterChecksByProfile(enabled, "mine", map[string][]string{"mine": {"secrets", "secret"}}); err == nil {
+		t.Error("accepted a profile naming an unknown check ID, want error")
+	}
+	if _, err := filterChecksByProfile(enabled, "mine", map[string][]string{"mine": {"license"}}); err == nil {
+		t.Error("accepted a profile with no enabled checks, want error")
+	}
+}
+
+func TestFilterChecksByFlagsTags(t *testing.T) {
+	custom := checks.CustomCheck{Rule: config.CustomCheckConfig{ID: "privacy", File: "privacy.html"}}
+	enabled := []checks.Check{checks.SecretScanCheck{}, checks.SEOMetadataCheck{}, checks.SSLCheck{}, checks.SentryCheck{}, custom}
+	ids := func(cs []checks.Check) []string {
+		var out []string
+		for _, c := range cs {
+			out = append(out, c.ID())
+		}
+		return out
+	}
+
+	got, err := filterChecksByFlags(enabled, []string{"security"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"secrets", "ssl"}; !slices.Equal(ids(got), want) {
+		t.Errorf("--only security = %v, want %v", ids(got), want)
+	}
+	got, err = filterChecksByFlags(enabled, nil, []string{"network", "services"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"secrets", "seoMeta", "privacy"}; !slices.Equal(ids(got), want) {
+		t.Errorf("--skip network,services = %v, want %v", ids(got), want)
+	}
+	got, err = filterChecksByFlags(enabled, []string{"seo", "secrets"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"secrets", "seoMeta"}; !slices.Equal(ids(got), want) {
+		t.Errorf("--only seo,secrets = %v, want %v", ids(got), want)
+	}
+}
@@ -0,0 +1,621 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/audit"
+	"github.com/preflightsh/preflight/internal/checks"
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveUI       bool
+	serveAPI      bool
+	serveAddr     string
+	serveAPIToken string
+)
+
+// snoozeDuration is how long the UI's Snooze button defers a check.
+const snoozeDuration = 7 * 24 * time.Hour
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [path]",
+	Short: "Serve scan results over HTTP",
+	Long: `Start a local HTTP server for the project at path (default: current directory).
+
+With --ui, serves a web page showing the latest scan, a trend of the scans run
+during this session, and per-check details with links into the offending
+files. Each failing check has Ignore and Snooze buttons that edit
+preflight.yml, so launch-week reviewers don't need a terminal.
+
+The readiness badge is served at /badge.svg and, in the shields.io endpoint
+format, at /badge.json.
+
+With --api, serves a JSON API for other services (a deployment dashboard
+checking a build before promoting it):
+
+  POST /scan           scan a directory under path, given as {"path": "apps/web"}
+                       with Content-Type: application/json, or a tar or tar.gz
+                       upload of the project (options as ?only=&skip=&failOn=)
+  GET  /results        the last 100 scans, newest first
+  GET  /results/{id}   one scan, with the full JSON report
+
+API requests need "Authorization: Bearer <token>". The token comes from
+--api-token or PREFLIGHT_API_TOKEN; without either, a random one is printed
+at startup.
+
+The server binds to localhost by default and the UI only answers requests
+addressed to a loopback host. The API and the two badge routes are the
+exception, so other machines can reach them when --addr exposes the server.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().BoolVar(&serveUI, "ui", false, "Serve the interactive results page")
+	serveCmd.Flags().BoolVar(&serveAPI, "api", false, "Serve the JSON scan API (POST /scan, GET /results)")
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:7070", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveAPIToken, "api-token", "", "Bearer token API requests must send (default $PREFLIGHT_API_TOKEN, else generated)")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	projectDir, err := resolveProjectDir(args)
+	if err != nil {
+		return err
+	}
+	if !serveUI && !serveAPI {
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("nothing to serve; pass --ui for the results page or --api for the scan API")}
+	}
+	// The API can scan any project under the served path, so only the UI
+	// needs one at the top.
+	if serveUI {
+		if _, err := config.Load(projectDir); err != nil {
+			return &ExitError{Code: ExitUsage, Err: fmt.Errorf("Error: %v\nRun 'preflight init' to create a configuration file.", err)}
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	root := http.NewServeMux()
+	if serveUI {
+		token, err := newUIToken()
+		if err != nil {
+			return err
+		}
+		ui := &uiServer{dir: projectDir, token: token, baseCtx: ctx}
+		mux := http.NewServeMux()
+		ui.routes(mux)
+		root.Handle("/", loopbackOnly(mux))
+		ui.badgeRoutes(root)
+	}
+	var apiToken string
+	var generatedToken bool
+	if serveAPI {
+		apiToken = serveAPIToken
+		if apiToken == "" {
+			apiToken = os.Getenv("PREFLIGHT_API_TOKEN")
+		}
+		if apiToken == "" {
+			if apiToken, err = newUIToken(); err != nil {
+				return err
+			}
+			generatedToken = true
+		}
+		api := &apiServer{root: projectDir, token: apiToken}
+		api.routes(root)
+	}
+
+	listener, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return &ExitError{Code: ExitUsage, Err: fmt.Errorf("cannot listen on %s: %w", serveAddr, err)}
+	}
+	srv := &http.Server{Handler: root, ReadHeaderTimeout: 10 * time.Second}
+
+	if serveUI {
+		fmt.Printf("Preflight UI for %s\n", projectDir)
+		fmt.Printf("  → http://%s/\n", listener.Addr())
+	}
+	if serveAPI {
+		fmt.Printf("Preflight scan API for %s\n", projectDir)
+		fmt.Printf("  → POST http://%s/scan\n", listener.Addr())
+		if generatedToken {
+			fmt.Printf("  Token: %s\n", apiToken)
+		}
+	}
+	fmt.Println("Press Ctrl-C to stop.")
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(listener) }()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+		return nil
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+// loopbackOnly rejects requests whose Host header isn't a loopback name.
+// The server is reachable from any page the user's browser loads, so
+// without this a DNS-rebinding page could read results or press the
+// Ignore button from another origin.
+func loopbackOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+		if host != "localhost" {
+			if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+				http.Error(w, "forbidden host", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newUIToken returns a random token the page embeds in its forms. POSTs
+// without it are refused, which stops a cross-site form from editing
+// preflight.yml.
+func newUIToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate session token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// uiRunSummary is one point on the session's trend chart.
+type uiRunSummary struct {
+	At      time.Time
+	Summary output.Summary
+}
+
+type uiServer struct {
+	dir     string
+	token   string
+	baseCtx context.Context
+
+	mu      sync.Mutex
+	project string
+	latest  []checks.CheckResult
+	scanned time.Time
+	scanErr error
+	history []uiRunSummary
+}
+
+func (s *uiServer) routes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /file", s.handleFile)
+	mux.HandleFunc("POST /rescan", s.handleRescan)
+	mux.HandleFunc("POST /ignore", s.handleIgnore)
+	mux.HandleFunc("POST /snooze", s.handleSnooze)
+}
+
+// badgeRoutes registers the badge endpoints. They sit outside loopbackOnly:
+// they're read-only and show nothing but the score, which is the point of
+// embedding them elsewhere.
+func (s *uiServer) badgeRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /badge.svg", s.handleBadge)
+	mux.HandleFunc("GET /badge.json", s.handleBadge)
+}
+
+// rescan reloads preflight.yml (the buttons edit it) and runs the suite.
+// Callers must hold s.mu.
+func (s *uiServer) rescan() {
+	s.scanned = time.Now()
+	cfg, err := config.Load(s.dir)
+	if err != nil {
+		s.scanErr = err
+		return
+	}
+	results, err := scanProject(s.baseCtx, s.dir, cfg, scanOptions{Verbose: true})
+	if err != nil {
+		s.scanErr = err
+		return
+	}
+	s.scanErr = nil
+	s.project = cfg.ProjectName
+	s.latest = results
+	s.history = append(s.history, uiRunSummary{At: s.scanned, Summary: output.CalculateSummary(results)})
+}
+
+func (s *uiServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.scanned.IsZero() {
+		s.rescan()
+	}
+	if err := uiPage.Execute(w, s.viewModel()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handleBadge serves the badge for the latest scan, scanning first if the
+// session hasn't yet. It never rescans on its own: shields.io polls, and
+// the page's Rescan button is what refreshes the results.
+func (s *uiServer) handleBadge(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	if s.scanned.IsZero() {
+		s.rescan()
+	}
+	scanErr, badge := s.scanErr, output.NewBadge(s.latest)
+	s.mu.Unlock()
+	if scanErr != nil {
+		http.Error(w, "scan failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "max-age=300")
+	if strings.HasSuffix(r.URL.Path, ".json") {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(badge)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	_, _ = w.Write(badge.SVG())
+}
+
+func (s *uiServer) handleRescan(w http.ResponseWriter, r *http.Request) {
+	if !s.checkToken(w, r) {
+		return
+	}
+	s.mu.Lock()
+	s.rescan()
+	s.mu.Unlock()
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (s *uiServer) handleIgnore(w http.ResponseWriter, r *http.Request) {
+	s.editConfig(w, r, func(cfg map[string]interface{}, id string) *audit.Entry {
+		if !addToIgnoreList(cfg, id) {
+			return nil
+		}
+		return &audit.Entry{Action: audit.ActionIgnore, CheckID: id}
+	})
+}
+
+func (s *uiServer) handleSnooze(w http.ResponseWriter, r *http.Request) {
+	until := time.Now().Add(snoozeDuration).Format(config.SnoozeDateLayout)
+	s.editConfig(w, r, func(cfg map[string]interface{}, id string) *audit.Entry {
+		setSnooze(cfg, id, until)
+		return &audit.Entry{Action: audit.ActionSnooze, CheckID: id, Until: until}
+	})
+}
+
+// editConfig applies edit to preflight.yml for the posted check ID, records
+// the audit entry it returns (nil when nothing changed), then rescans so the
+// page reflects the change.
+func (s *uiServer) editConfig(w http.ResponseWriter, r *http.Request, edit func(cfg map[string]interface{}, id string) *audit.Entry) {
+	if !s.checkToken(w, r) {
+		return
+	}
+	id := r.PostFormValue("id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var custom []config.CustomCheckConfig
+	if project, err := config.Load(s.dir); err == nil {
+		custom = project.CustomChecks
+	}
+	if !knownResultID(id, custom) {
+		http.Error(w, "unknown check ID", http.StatusBadRequest)
+		return
+	}
+	configPath := filepath.Join(s.dir, "preflight.yml")
+	cfg, err := readConfigMap(configPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	entry := edit(cfg, id)
+	if entry == nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	noteHandEdits(s.dir, "ui")
+	if err := writeConfigMap(configPath, cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	entry.Source = "ui"
+	if reason := r.PostFormValue("reason"); reason != "" {
+		entry.Reason = reason
+	}
+	recordAudit(s.dir, *entry)
+	s.rescan()
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (s *uiServer) checkToken(w http.ResponseWriter, r *http.Request) bool {
+	got := r.PostFormValue("token")
+	if subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+		http.Error(w, "invalid or missing session token; reload the page", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// knownResultID reports whether id is a check or service ID this binary
+// knows, or one of the project's custom checks: the IDs the buttons may
+// write, so they can't add arbitrary keys, and severities may name.
+func knownResultID(id string, custom []config.CustomCheckConfig) bool {
+	if id == "" {
+		return false
+	}
+	for _, c := range checks.Registry {
+		if c.ID() == id {
+			return true
+		}
+	}
+	for _, sc := range serviceChecks {
+		if sc.id == id {
+			return true
+		}
+	}
+	for _, cc := range custom {
+		if cc.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// maxUIFileSize caps what /file will render.
+const maxUIFileSize = 1 << 20
+
+// handleFile renders a project file with line numbers so finding links
+// (path:line) open at the offending line. Paths are resolved inside the
+// project directory only, after following symlinks.
+func (s *uiServer) handleFile(w http.ResponseWriter, r *http.Request) {
+	rel := r.URL.Query().Get("path")
+	full, ok := resolveInside(s.dir, rel)
+	if !ok {
+		http.Error(w, "file not found", http.StatusNotFound)
+		return
+	}
+	info, err := os.Stat(full)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > maxUIFileSize {
+		http.Error(w, "file not found", http.StatusNotFound)
+		return
+	}
+	data, err := os.ReadFile(full)
+	if err != nil {
+		http.Error(w, "file not readable", http.StatusInternalServerError)
+		return
+	}
+	line, _ := strconv.Atoi(r.URL.Query().Get("line"))
+	view := uiFileView{Path: filepath.ToSlash(rel), Highlight: line}
+	view.Lines = strings.Split(string(data), "\n")
+	if err := uiFilePage.Execute(w, view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// resolveInside joins rel onto root and reports whether the result, with
+// symlinks evaluated, still lies within root.
+func resolveInside(root, rel string) (string, bool) {
+	if rel == "" || filepath.IsAbs(rel) {
+		return "", false
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", false
+	}
+	full, err := filepath.EvalSymlinks(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		return "", false
+	}
+	inside, err := filepath.Rel(realRoot, full)
+	if err != nil || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return full, true
+}
+
+// findingLocation matches the "path:line" prefix checks use for findings
+// (e.g. "src/app.js:12 - console.log").
+var findingLocation = regexp.MustCompile(`^([^\s:]+\.[A-Za-z0-9]+):(\d+)\b`)
+
+type uiLine struct {
+	Text string
+	File string
+	Line int
+}
+
+func newUILine(text string) uiLine {
+	l := uiLine{Text: text}
+	if m := findingLocation.FindStringSubmatch(text); m != nil {
+		l.File = m[1]
+		l.Line, _ = strconv.Atoi(m[2])
+	}
+	return l
+}
+
+type uiCheck struct {
+	ID          string
+	Title       string
+	Status      string // "ok", "warn", "fail"
+	Message     string
+	Suggestions []uiLine
+	Details     []uiLine
+}
+
+type uiTrendPoint struct {
+	Label                   string
+	OK, Warn, Fail          int
+	OKPct, WarnPct, FailPct int
+}
+
+type uiView struct {
+	Project string
+	Dir     string
+	Token   string
+	Scanned string
+	Error   string
+	Summary output.Summary
+	Effort  string
+	Checks  []uiCheck
+	Trend   []uiTrendPoint
+}
+
+func (s *uiServer) viewModel() uiView {
+	v := uiView{
+		Project: s.project,
+		Dir:     s.dir,
+		Token:   s.token,
+		Scanned: s.scanned.Format("Jan 2 15:04:05"),
+		Summary: output.CalculateSummary(s.latest),
+	}
+	if minutes := output.RemainingEffortMinutes(s.latest); minutes > 0 {
+		v.Effort = output.FormatEffort(minutes)
+	}
+	if s.scanErr != nil {
+		v.Error = s.scanErr.Error()
+	}
+	// Failures first so the page opens on what needs attention.
+	for _, want := range []string{"fail", "warn", "ok"} {
+		for _, r := range s.latest {
+			status := "ok"
+			if !r.Passed {
+				status = "warn"
+				if r.Severity == checks.SeverityError {
+					status = "fail"
+				}
+			}
+			if status != want {
+				continue
+			}
+			c := uiCheck{ID: r.ID, Title: r.Title, Status: status, Message: r.Message}
+			for _, sug := range r.Suggestions {
+				c.Suggestions = append(c.Suggestions, newUILine(sug))
+			}
+			for _, d := range r.Details {
+				c.Details = append(c.Details, newUILine(d))
+			}
+			v.Checks = append(v.Checks, c)
+		}
+	}
+	for _, h := range s.history {
+		total := h.Summary.OK + h.Summary.Warn + h.Summary.Fail
+		if total == 0 {
+			total = 1
+		}
+		v.Trend = append(v.Trend, uiTrendPoint{
+			Label:   h.At.Format("15:04:05"),
+			OK:      h.Summary.OK,
+			Warn:    h.Summary.Warn,
+			Fail:    h.Summary.Fail,
+			OKPct:   h.Summary.OK * 100 / total,
+			WarnPct: h.Summary.Warn * 100 / total,
+			FailPct: h.Summary.Fail * 100 / total,
+		})
+	}
+	return v
+}
+
+type uiFileView struct {
+	Path      string
+	Highlight int
+	Lines     []string
+}
+
+const uiStyle = `
+body { font: 14px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 0; color: #1f2328; background: #f6f8fa; }
+header { background: #0d1117; color: #fff; padding: 16px 32px; }
+header h1 { margin: 0; font-size: 20px; }
+header p { margin: 4px 0 0; color: #8b949e; }
+main { padding: 24px 32px; max-width: 1100px; }
+.summary span { display: inline-block; margin-right: 16px; font-weight: 600; }
+.ok { color: #1a7f37; } .warn { color: #9a6700; } .fail { color: #cf222e; }
+.check { background: #fff; border: 1px solid #d0d7de; border-left-width: 4px; border-radius: 6px; margin: 12px 0; padding: 12px 16px; }
+.check.ok { border-left-color: #1a7f37; } .check.warn { border-left-color: #d4a72c; } .check.fail { border-left-color: #cf222e; }
+.check h3 { margin: 0; font-size: 15px; color: #1f2328; }
+.check .id { color: #57606a; font-family: ui-monospace, monospace; font-size: 12px; }
+.check ul { margin: 8px 0 0; padding-left: 20px; font-family: ui-monospace, monospace; font-size: 12px; }
+.actions { float: right; }
+.actions form { display: inline; }
+button { cursor: pointer; border: 1px solid #d0d7de; background: #f6f8fa; border-radius: 6px; padding: 3px 10px; }
+.trend td { padding: 2px 8px; font-size: 12px; }
+.bar { display: flex; width: 240px; height: 10px; border-radius: 3px; overflow: hidden; }
+.bar .ok { background: #2da44e; } .bar .warn { background: #d4a72c; } .bar .fail { background: #cf222e; }
+.error { background: #ffebe9; border: 1px solid #ff8182; padding: 8px 12px; border-radius: 6px; }
+pre { background: #fff; border: 1px solid #d0d7de; border-radius: 6px; padding: 8px 0; overflow-x: auto; }
+pre .ln { display: inline-block; width: 4em; text-align: right; padding-right: 1em; color: #8c959f; user-select: none; }
+pre .hl { background: #fff8c5; display: block; }
+`
+
+var uiPage = template.Must(template.New("ui").Parse(`<!doctype html>
+<html lang="en"><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Preflight · {{.Project}}</title><style>` + uiStyle + `</style></head>
+<body>
+<header><h1>✈ Preflight · {{.Project}}</h1><p>{{.Dir}} · last scanned {{.Scanned}}</p></header>
+<main>
+{{if .Error}}<p class="error">Scan failed: {{.Error}}</p>{{end}}
+<p class="summary">
+  <span class="ok">✓ {{.Summary.OK}} passed</span>
+  <span class="warn">⚠ {{.Summary.Warn}} warnings</span>
+  <span class="fail">✗ {{.Summary.Fail}} failed</span>
+  {{if .Effort}}<span>≈{{.Effort}} of fixes remaining</span>{{end}}
+  <form method="post" action="/rescan" style="display:inline"><input type="hidden" name="token" value="{{.Token}}"><button>Rescan</button></form>
+</p>
+{{if gt (len .Trend) 1}}
+<h2>This session</h2>
+<table class="trend">
+{{range .Trend}}<tr><td>{{.Label}}</td><td><div class="bar"><div class="ok" style="width:{{.OKPct}}%"></div><div class="warn" style="width:{{.WarnPct}}%"></div><div class="fail" style="width:{{.FailPct}}%"></div></div></td><td>{{.OK}} / {{.Warn}} / {{.Fail}}</td></tr>
+{{end}}</table>
+{{end}}
+<h2>Checks</h2>
+{{range .Checks}}
+<div class="check {{.Status}}">
+  {{if ne .Status "ok"}}<div class="actions">
+    <form method="post" action="/snooze"><input type="hidden" name="token" value="{{$.Token}}"><input type="hidden" name="id" value="{{.ID}}"><button title="Skip this check for 7 days">Snooze</button></form>
+    <form method="post" action="/ignore"><input type="hidden" name="token" value="{{$.Token}}"><input type="hidden" name="id" value="{{.ID}}"><button title="Add to the ignore list in preflight.yml">Ignore</button></form>
+  </div>{{end}}
+  <h3 class="{{.Status}}">{{.Title}} <span class="id">{{.ID}}</span></h3>
+  {{if .Message}}<div>{{.Message}}</div>{{end}}
+  {{if .Suggestions}}<ul>{{range .Suggestions}}<li>{{if .File}}<a href="/file?path={{.File}}&line={{.Line}}#L{{.Line}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}</li>{{end}}</ul>{{end}}
+  {{if .Details}}<details><summary>Details</summary><ul>{{range .Details}}<li>{{if .File}}<a href="/file?path={{.File}}&line={{.Line}}#L{{.Line}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}</li>{{end}}</ul></details>{{end}}
+</div>
+{{end}}
+</main></body></html>
+`))
+
+var uiFilePage = template.Must(template.New("file").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(`<!doctype html>
+<html lang="en"><head><meta charset="utf-8"><title>{{.Path}}</title><style>` + uiStyle + `</style></head>
+<body><header><h1>{{.Path}}</h1><p><a href="/" style="color:#8b949e">← back to results</a></p></header>
+<main><pre>{{range $i, $l := .Lines}}{{$n := inc $i}}<span id="L{{$n}}"{{if eq $n $.Highlight}} class="hl"{{end}}><span class="ln">{{$n}}</span>{{$l}}</span>
+{{end}}</pre></main></body></html>
+`))
@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/checks"
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/output"
+)
+
+const (
+	// maxAPIScans is how many scans GET /results remembers.
+	maxAPIScans = 100
+	// maxUploadSize caps an uploaded tarball, compressed or not, and the
+	// total size of the files it unpacks to.
+	maxUploadSize = 256 << 20
+	// maxUploadFiles caps how many entries an upload may unpack.
+	maxUploadFiles = 50000
+)
+
+// apiServer answers the scan API (--api): POST /scan runs the suite against
+// a directory under the served path or an uploaded tarball, and /results
+// lists recent scans. Every request needs the bearer token.
+type apiServer struct {
+	root  string
+	token string
+
+	mu    sync.Mutex
+	scans []*apiScan // oldest first
+}
+
+// apiScan is one scan as the API returns it. Report is the same document
+// 'scan --format json' prints.
+type apiScan struct {
+	ID        string            `json:"id"`
+	ScannedAt time.Time         `json:"scannedAt"`
+	Path      string            `json:"path,omitempty"` // relative to the served directory
+	Upload    bool              `json:"upload,omitempty"`
+	ExitCode  int               `json:"exitCode"`
+	Report    output.JSONOutput `json:"report"`
+}
+
+// apiScanRequest is the JSON body of POST /scan. Uploads pass the same
+// options as query parameters (?only=a,b&skip=c&failOn=error).
+type apiScanRequest struct {
+	Path   string   `json:"path"`
+	Only   []string `json:"only"`
+	Skip   []string `json:"skip"`
+	FailOn string   `json:"failOn"`
+}
+
+func (a *apiServer) routes(mux *http.ServeMux) {
+	mux.Handle("POST /scan", a.authorized(a.handleScan))
+	mux.Handle("GET /results", a.authorized(a.handleResults))
+	mux.Handle("GET /results/{id}", a.authorized(a.handleResult))
+}
+
+// authorized wraps h with the bearer token check. The API is meant to be
+// reached from other machines, so unlike the UI it isn't loopback-only.
+func (a *apiServer) authorized(h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(a.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="preflight"`)
+			apiError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		h(w, r)
+	})
+}
+
+func apiError(w http.ResponseWriter, status int, msg string) {
+	apiJSON(w, status, map[string]string{"error": msg})
+}
+
+func apiJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func (a *apiServer) handleScan(w http.ResponseWriter, r *http.Request) {
+	var req apiScanRequest
+	var dir, rel string
+	upload := !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+	if upload {
+		q := r.URL.Query()
+		req.Only = splitList(q.Get("only"))
+		req.Skip = splitList(q.Get("skip"))
+		req.FailOn = q.Get("failOn")
+		tmp, err := os.MkdirTemp("", "preflight-upload-")
+		if err != nil {
+			apiError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		defer os.RemoveAll(tmp)
+		if err := extractTarball(http.MaxBytesReader(w, r.Body, maxUploadSize), tmp); err != nil {
+			status := http.StatusBadRequest
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) || errors.Is(err, errUploadTooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			apiError(w, status, "upload: "+err.Error())
+			return
+		}
+		dir = uploadProjectDir(tmp)
+	} else {
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+			apiError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+			return
+		}
+		rel = path.Clean(filepath.ToSlash(req.Path))
+		full, ok := resolveInside(a.root, rel)
+		if info, err := os.Stat(full); !ok || err != nil || !info.IsDir() {
+			apiError(w, http.StatusBadRequest, fmt.Sprintf("path %q is not a directory under the served path", req.Path))
+			return
+		}
+		dir = full
+	}
+	if err := config.ValidateFailOn(req.FailOn); err != nil {
+		apiError(w, http.StatusBadRequest, "failOn: "+err.Error())
+		return
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var diagnostics []checks.Diagnostic
+	results, err := scanProject(r.Context(), dir, cfg, scanOptions{
+		Only:          req.Only,
+		Skip:          req.Skip,
+		OnDiagnostics: func(d []checks.Diagnostic) { diagnostics = d },
+		// The server may sit inside a network the uploader can't reach,
+		// and the upload's config mustn't change that.
+		Untrusted: upload,
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return // the client went away
+		}
+		apiError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := newUIToken()
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	failOn := cfg.FailOn
+	if req.FailOn != "" {
+		failOn = req.FailOn
+	}
+	scan := &apiScan{
+		ID:        id[:12],
+		ScannedAt: time.Now().UTC(),
+		Upload:    upload,
+		ExitCode:  applyFailOn(determineExitCode(results), failOn),
+		Report:    output.BuildJSONOutput(cfg.ProjectName, results),
+	}
+	scan.Report.Diagnostics = diagnostics
+	scan.Report.Verdict = output.NewVerdict(results, failOn)
+	scan.Report.Verdict.Scan.Version = version
+	scan.Report.Verdict.Scan.ScannedAt = scan.ScannedAt.Format(time.RFC3339)
+	if !upload {
+		scan.Path = rel
+	}
+
+	a.mu.Lock()
+	a.scans = append(a.scans, scan)
+	if len(a.scans) > maxAPIScans {
+		a.scans = a.scans[len(a.scans)-maxAPIScans:]
+	}
+	a.mu.Unlock()
+	apiJSON(w, http.StatusOK, scan)
+}
+
+// apiScanSummary is a scan's entry in GET /results.
+type apiScanSummary struct {
+	ID        string         `json:"id"`
+	ScannedAt time.Time      `json:"scannedAt"`
+	Project   string         `json:"project"`
+	Path      string         `json:"path,omitempty"`
+	Upload    bool           `json:"upload,omitempty"`
+	ExitCode  int            `json:"exitCode"`
+	Summary   output.Summary `json:"summary"`
+}
+
+func (a *apiServer) handleResults(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	list := make([]apiScanSummary, 0, len(a.scans))
+	for i := len(a.scans) - 1; i >= 0; i-- { // newest first
+		s := a.scans[i]
+		list = append(list, apiScanSummary{
+			ID: s.ID, ScannedAt: s.ScannedAt, Project: s.Report.Project,
+			Path: s.Path, Upload: s.Upload, ExitCode: s.ExitCode, Summary: s.Report.Summary,
+		})
+	}
+	a.mu.Unlock()
+	apiJSON(w, http.StatusOK, map[string]any{"scans": list})
+}
+
+func (a *apiServer) handleResult(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, s := range a.scans {
+		if s.ID == id {
+			apiJSON(w, http.StatusOK, s)
+			return
+		}
+	}
+	apiError(w, http.StatusNotFound, "no scan with id "+id)
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+var errUploadTooLarge = errors.New("archive unpacks to more than the upload limit")
+
+// extractTarball unpacks a tar archive, gzipped or not, into dest. Only
+// directories and regular files are written; links, devices and entries
+// whose names would land outside dest are rejected, so an upload can't
+// point the scan at the server's own files. Anything under a .git
+// directory is dropped.
+func extractTarball(r io.Reader, dest string) error {
+	br := bufio.NewReader(r)
+	var src io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		src = gz
+	}
+
+	tr := tar.NewReader(src)
+	var total int64
+	for n := 0; ; n++ {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			if n == 0 {
+				return errors.New("empty archive")
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("not a tar archive: %w", err)
+		}
+		if n >= maxUploadFiles {
+			return fmt.Errorf("archive has more than %d entries", maxUploadFiles)
+		}
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+		if name == "." {
+			continue
+		}
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("entry %q is outside the archive root", hdr.Name)
+		}
+		if slices.Contains(strings.Split(name, "/"), ".git") {
+			// A repository's config can name commands for git to run
+			// (core.fsmonitor, hooks), and no check needs its history.
+			continue
+		}
+		target := filepath.Join(dest, filepath.FromSlash(name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			total += hdr.Size
+			if total > maxUploadSize {
+				return errUploadTooLarge
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+			if err != nil {
+				return err
+			}
+			_, err = io.CopyN(f, tr, hdr.Size)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return err
+			}
+		case tar.TypeXGlobalHeader:
+			// pax metadata; nothing to write.
+		default:
+			return fmt.Errorf("entry %q: only files and directories are accepted", hdr.Name)
+		}
+	}
+}
+
+// uploadProjectDir finds the project in an unpacked upload: the archive
+// root, or its only directory when the archive was made from the outside
+// (tar czf app.tgz app/).
+func uploadProjectDir(dir string) string {
+	if _, err := os.Stat(filepath.Join(dir, "preflight.yml")); err == nil {
+		return dir
+	}
+	entries, err := os.ReadDir(dir)
+	if err == nil && len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name())
+	}
+	return dir
+}
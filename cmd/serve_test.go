@@ -0,0 +1,427 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/audit"
+	"github.com/preflightsh/preflight/internal/checks"
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// The UI edits preflight.yml on POST, so it must never answer a request
+// addressed to a non-loopback name (DNS rebinding).
+func TestLoopbackOnly(t *testing.T) {
+	h := loopbackOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	cases := map[string]int{
+		"localhost:7070":     http.StatusOK,
+		"127.0.0.1:7070":     http.StatusOK,
+		"[::1]:7070":         http.StatusOK,
+		"evil.example:7070":  http.StatusForbidden,
+		"localhost.evil.com": http.StatusForbidden,
+		"192.168.1.10:7070":  http.StatusForbidden,
+	}
+	for host, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = host
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("Host %q: status %d, want %d", host, rec.Code, want)
+		}
+	}
+}
+
+// /file links come from check output, but the query string is attacker
+// controlled; it must not escape the project directory.
+func TestResolveInside(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "app.js"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "secret"), filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	if _, ok := resolveInside(root, "app.js"); !ok {
+		t.Error("resolveInside rejected a file inside the project")
+	}
+	for _, rel := range []string{"", "../secret", "/etc/passwd", "link"} {
+		if _, ok := resolveInside(root, rel); ok {
+			t.Errorf("resolveInside(%q) escaped the project directory", rel)
+		}
+	}
+}
+
+// The badge routes are meant to be fetched from elsewhere (shields.io, a
+// dashboard), so they skip the loopback check the rest of the UI keeps.
+func TestBadgeRoutesSkipLoopbackCheck(t *testing.T) {
+	ui := &uiServer{dir: t.TempDir(), scanned: time.Now(), latest: []checks.CheckResult{{ID: "sitemap", Passed: true}}}
+	mux := http.NewServeMux()
+	ui.routes(mux)
+	root := http.NewServeMux()
+	root.Handle("/", loopbackOnly(mux))
+	ui.badgeRoutes(root)
+
+	cases := map[string]int{"/badge.json": http.StatusOK, "/badge.svg": http.StatusOK, "/": http.StatusForbidden}
+	for path, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Host = "dashboard.example.com"
+		rec := httptest.NewRecorder()
+		root.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("%s: status %d, want %d", path, rec.Code, want)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/badge.json", nil)
+	rec := httptest.NewRecorder()
+	root.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"message":"100%"`) {
+		t.Errorf("badge.json = %s", rec.Body.String())
+	}
+}
+
+// Custom check IDs are as ignorable from the UI as built-in ones; any
+// other ID is still refused.
+func TestUIIgnoreCustomCheck(t *testing.T) {
+	dir := t.TempDir()
+	yml := "projectName: app\nstack: static\ncustomChecks:\n  - id: support_email\n    file: index.html\n    mustMatch: support@example\\.com\n"
+	if err := os.WriteFile(filepath.Join(dir, "preflight.yml"), []byte(yml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ui := &uiServer{dir: dir, token: "tok", baseCtx: context.Background()}
+	mux := http.NewServeMux()
+	ui.routes(mux)
+
+	post := func(path, id string) int {
+		form := url.Values{"token": {"tok"}, "id": {id}}
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := post("/ignore", "support_email"); code != http.StatusSeeOther {
+		t.Fatalf("ignore support_email: status %d", code)
+	}
+	if code := post("/snooze", "support_email"); code != http.StatusSeeOther {
+		t.Fatalf("snooze support_email: status %d", code)
+	}
+	if code := post("/ignore", "no_such_check"); code != http.StatusBadRequest {
+		t.Errorf("ignore no_such_check: status %d, want 400", code)
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Ignore) != 1 || cfg.Ignore[0] != "support_email" || cfg.Snooze["support_email"] == "" {
+		t.Errorf("ignore = %v, snooze = %v", cfg.Ignore, cfg.Snooze)
+	}
+}
+
+// A scan is read-only: a hand edit to preflight.yml goes on record when
+// the next command that edits the config runs, ahead of its own entry.
+func TestHandEditsRecordedByEdits(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "preflight.yml")
+	if err := os.WriteFile(configPath, []byte("projectName: app\nstack: static\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := audit.Append(dir, audit.Entry{Action: audit.ActionIgnore, CheckID: "sitemap", User: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte("projectName: app\nstack: static\nignore: [sitemap]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scanProject(context.Background(), dir, cfg, scanOptions{Only: []string{"favicon"}}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := audit.Read(dir); len(entries) != 1 {
+		t.Fatalf("scan wrote to the audit log: %+v", entries)
+	}
+
+	ui := &uiServer{dir: dir, token: "tok", baseCtx: context.Background()}
+	mux := http.NewServeMux()
+	ui.routes(mux)
+	form := url.Values{"token": {"tok"}, "id": {"favicon"}}
+	req := httptest.NewRequest(http.MethodPost, "/ignore", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("ignore favicon: status %d", rec.Code)
+	}
+
+	entries, err := audit.Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Action+" "+e.CheckID+" "+e.Source)
+	}
+	want := "ignore sitemap |config_change  ui|ignore favicon ui"
+	if strings.Join(got, "|") != want {
+		t.Errorf("entries = %q, want %q", strings.Join(got, "|"), want)
+	}
+}
+
+func TestAPIScan(t *testing.T) {
+	root := t.TempDir()
+	app := filepath.Join(root, "apps", "web")
+	if err := os.MkdirAll(app, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(app, "preflight.yml"), []byte("projectName: web\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(app, "app.js"), []byte("console.log('debug')\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	api := &apiServer{root: root, token: "t0ken"}
+	mux := http.NewServeMux()
+	api.routes(mux)
+
+	do := func(method, target, contentType string, body io.Reader, auth bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, body)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if auth {
+			req.Header.Set("Authorization", "Bearer t0ken")
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodGet, "/results", "", nil, false); rec.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated GET /results: status %d, want 401", rec.Code)
+	}
+	for _, p := range []string{"../", "/etc", "apps/missing"} {
+		body := `{"path": "` + p + `"}`
+		if rec := do(http.MethodPost, "/scan", "application/json", strings.NewReader(body), true); rec.Code != http.StatusBadRequest {
+			t.Errorf("scan of %q: status %d, want 400", p, rec.Code)
+		}
+	}
+
+	rec := do(http.MethodPost, "/scan", "application/json", strings.NewReader(`{"path": "apps/web", "only": ["debug_statements"]}`), true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /scan: status %d: %s", rec.Code, rec.Body)
+	}
+	var scan apiScan
+	if err := json.Unmarshal(rec.Body.Bytes(), &scan); err != nil {
+		t.Fatal(err)
+	}
+	if scan.Path != "apps/web" || scan.Report.Project != "web" || len(scan.Report.Checks) != 1 || scan.ExitCode != ExitWarn {
+		t.Errorf("scan = %+v", scan)
+	}
+
+	// The same project as an upload, made from outside its directory.
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	for name, body := range map[string]string{"web/preflight.yml": "projectName: uploaded\n", "web/app.js": "export {}\n"} {
+		_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(body)), Typeflag: tar.TypeReg})
+		_, _ = tw.Write([]byte(body))
+	}
+	tw.Close()
+	gz.Close()
+	rec = do(http.MethodPost, "/scan?only=debug_statements", "application/gzip", &archive, true)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"project": "uploaded"`) {
+		t.Fatalf("upload scan: status %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = do(http.MethodGet, "/results", "", nil, true)
+	var list struct{ Scans []apiScanSummary }
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list.Scans) != 2 || list.Scans[0].Project != "uploaded" {
+		t.Errorf("GET /results = %s", rec.Body)
+	}
+	if rec := do(http.MethodGet, "/results/"+scan.ID, "", nil, true); rec.Code != http.StatusOK {
+		t.Errorf("GET /results/{id}: status %d", rec.Code)
+	}
+}
+
+// tarFiles makes a gzipped tarball of files.
+func tarFiles(files map[string]string) *bytes.Buffer {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	for name, body := range files {
+		_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(body)), Typeflag: tar.TypeReg})
+		_, _ = tw.Write([]byte(body))
+	}
+	tw.Close()
+	gz.Close()
+	return &archive
+}
+
+// postUpload sends archive to a fresh API server's POST /scan.
+func postUpload(t *testing.T, query string, archive io.Reader) *httptest.ResponseRecorder {
+	t.Helper()
+	api := &apiServer{root: t.TempDir(), token: "t0ken"}
+	mux := http.NewServeMux()
+	api.routes(mux)
+	req := httptest.NewRequest(http.MethodPost, "/scan?"+query, archive)
+	req.Header.Set("Content-Type", "application/x-tar")
+	req.Header.Set("Authorization", "Bearer t0ken")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+// An upload's preflight.yml is the uploader's, so it mustn't get the
+// server to reach its own loopback services, whether through a local
+// production URL, a DoH resolver or a stack pack.
+func TestAPIScanUploadStaysOffLocalNetwork(t *testing.T) {
+	var conns atomic.Int32
+	local := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	local.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	local.StartTLS()
+	defer local.Close()
+	defer netutil.ConfigureResolver("", "")
+
+	port := local.Listener.Addr().(*net.TCPAddr).Port
+	files := map[string]string{
+		"web/preflight.yml":                fmt.Sprintf("projectName: uploaded\nurls:\n  production: http://localhost:%d\ndns:\n  doh: %s\n  mode: doh\n", port, local.URL),
+		"web/.preflight/stacks/escape.yml": "name: x\nlayouts: [\"../../../etc/hostname\"]\n",
+		"web/app.js":                       "export {}\n",
+	}
+	rec := postUpload(t, "only=debug_statements", tarFiles(files))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("upload scan: status %d: %s", rec.Code, rec.Body)
+	}
+	if n := conns.Load(); n != 0 {
+		t.Errorf("upload scan made %d connections to a loopback server", n)
+	}
+}
+
+// An uploaded repository's .git/config can set core.fsmonitor, which git
+// runs; the secrets check asking git for tracked files mustn't run it.
+func TestAPIScanUploadDoesNotRunGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	project := t.TempDir()
+	marker := filepath.Join(t.TempDir(), "ran")
+	files := map[string]string{
+		"preflight.yml": "projectName: uploaded\nchecks:\n  secrets:\n    enabled: true\n",
+		"app.js":        "export {}\n",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(project, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"init", "-q"}, {"config", "core.fsmonitor", "touch " + marker}} {
+		if out, err := exec.Command("git", append([]string{"-C", project}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	err := filepath.WalkDir(project, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == project {
+			return err
+		}
+		rel, _ := filepath.Rel(project, p)
+		if d.IsDir() {
+			return tw.WriteHeader(&tar.Header{Name: filepath.ToSlash(rel) + "/", Mode: 0o755, Typeflag: tar.TypeDir})
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: filepath.ToSlash(rel), Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw.Close()
+
+	rec := postUpload(t, "only=secrets", &archive)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("upload scan: status %d: %s", rec.Code, rec.Body)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("the upload's core.fsmonitor ran")
+	}
+}
+
+// The files an upload's config names are read by the checks, so none may
+// lie outside the upload.
+func TestAPIScanUploadRejectsOutsidePaths(t *testing.T) {
+	cases := map[string]string{
+		"envParity":    "  envParity:\n    enabled: true\n    envFile: .env\n    exampleFile: ../../../../etc/passwd\n",
+		"seoMeta":      "  seoMeta:\n    enabled: true\n    mainLayout: /etc/hostname\n",
+		"changelog":    "  changelog:\n    enabled: true\n    file: ../CHANGELOG.md\n",
+		"opsReadiness": "  opsReadiness:\n    enabled: true\n    runbook: docs/../../runbook.md\n",
+	}
+	for name, checksYAML := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := postUpload(t, "", tarFiles(map[string]string{
+				"preflight.yml": "projectName: uploaded\nchecks:\n" + checksYAML,
+				"app.js":        "export {}\n",
+			}))
+			if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "checks."+name) || !strings.Contains(rec.Body.String(), "must be a path inside the project") {
+				t.Errorf("status %d: %s", rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+// Uploads must not be able to write outside the scratch directory or
+// plant links to the server's files.
+func TestExtractTarballRejectsEscapes(t *testing.T) {
+	for name, hdr := range map[string]*tar.Header{
+		"dotdot":  {Name: "../evil", Typeflag: tar.TypeReg},
+		"abs":     {Name: "/etc/evil", Typeflag: tar.TypeReg},
+		"symlink": {Name: "link", Linkname: "/etc/passwd", Typeflag: tar.TypeSymlink},
+	} {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		_ = tw.WriteHeader(hdr)
+		tw.Close()
+		if err := extractTarball(&buf, t.TempDir()); err == nil {
+			t.Errorf("%s: extracted without error", name)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/preflightsh/preflight/internal/buildinfo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	versionJSONFlag   bool
+	versionVerifyFlag bool
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show version information",
+	Long: `Show the version of this binary. --json adds the build metadata (commit,
+build date, Go version, platform and embedded checksum) for pipelines that
+record tool provenance.
+
+--verify checks the binary against the SHA-256 checksum Linux and Windows
+release builds embed in themselves, and exits 2 if it doesn't match. Source
+builds carry no checksum, and macOS releases rely on their code signature
+instead (codesign -v); for those --verify reports that and exits 1.`,
+	Args: cobra.NoArgs,
+	RunE: runVersion,
+}
+
+func init() {
+	versionCmd.Flags().BoolVar(&versionJSONFlag, "json", false, "Print build metadata as JSON")
+	versionCmd.Flags().BoolVar(&versionVerifyFlag, "verify", false, "Check the binary against its embedded checksum")
+	rootCmd.AddCommand(versionCmd)
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	if versionVerifyFlag {
+		return verifyBinary()
+	}
+	info := buildinfo.Read(version, commit, date)
+	if versionJSONFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+	fmt.Printf("preflight version %s\n", info.Version)
+	if info.Commit != "" {
+		fmt.Printf("  commit: %s\n", info.Commit)
+	}
+	if info.Date != "" {
+		fmt.Printf("  built:  %s\n", info.Date)
+	}
+	fmt.Printf("  go:     %s %s/%s\n", info.GoVersion, info.OS, info.Arch)
+	return nil
+}
+
+func verifyBinary() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot locate the running binary: %w", err)
+	}
+	sum, err := buildinfo.Verify(exe)
+	switch {
+	case errors.Is(err, buildinfo.ErrNoChecksum):
+		fmt.Printf("%s has no embedded checksum (source builds and macOS releases don't carry one)\n", exe)
+		return &ExitError{Code: ExitWarn}
+	case errors.Is(err, buildinfo.ErrMismatch):
+		return &ExitError{Code: ExitFail, Err: fmt.Errorf("%s failed verification: %v", exe, err)}
+	case err != nil:
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+	fmt.Printf("%s: OK (sha256 %s)\n", exe, sum)
+	return nil
+}
@@ -0,0 +1,246 @@
+// Package audit keeps an append-only record of the decisions that silence or
+// reshape checks (ignores, allowlist entries, snoozes, severity overrides
+// and hand edits to preflight.yml) in <project>/.preflight/audit.log, one
+// JSON object per line.
+//
+// The log is meant to be committed alongside preflight.yml so that "who
+// turned off the secrets check, and why" has an answer at review time.
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Actions recorded in the log.
+const (
+	ActionIgnore       = "ignore"
+	ActionUnignore     = "unignore"
+	ActionAllowlist    = "allowlist"
+	ActionSnooze       = "snooze"
+	ActionSeverity     = "severity"
+	ActionConfigChange = "config_change"
+)
+
+// Entry is one line of the audit log.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	User    string    `json:"user"`
+	Action  string    `json:"action"`
+	CheckID string    `json:"check,omitempty"`
+	// Path is the file an allowlist entry covers.
+	Path string `json:"path,omitempty"`
+	// Until is the last day a snooze applies (config.SnoozeDateLayout).
+	Until string `json:"until,omitempty"`
+	// From and To are a severity entry's old and new override; "" is the
+	// check's own severity.
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// Source is where the change was made: "cli" or "ui". A hand edit is
+	// recorded with the source of the command that noticed it.
+	Source string `json:"source,omitempty"`
+	// ConfigHash is the SHA-256 of preflight.yml after the change, which
+	// is how a hand edit is told from one already on record.
+	ConfigHash string `json:"configHash,omitempty"`
+	// Severities is the severities map in preflight.yml after the change,
+	// what the next hand edit's overrides are compared with.
+	Severities map[string]string `json:"severities,omitempty"`
+}
+
+// LogPath returns the audit log location for a project.
+func LogPath(projectDir string) string {
+	return filepath.Join(projectDir, ".preflight", "audit.log")
+}
+
+// Append writes e to the project's audit log, creating it if needed. A zero
+// Time is set to now, an empty User to CurrentUser, and an empty ConfigHash
+// and nil Severities to those of the current preflight.yml.
+func Append(projectDir string, e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if e.User == "" {
+		e.User = CurrentUser(projectDir)
+	}
+	if e.ConfigHash == "" {
+		e.ConfigHash = ConfigHash(projectDir)
+	}
+	if e.Severities == nil {
+		e.Severities = configSeverities(projectDir)
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	path := LogPath(projectDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Read returns every entry in the project's audit log, oldest first. A
+// missing log is not an error; malformed lines are skipped.
+func Read(projectDir string) ([]Entry, error) {
+	f, err := os.Open(LogPath(projectDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil && e.Action != "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// RecordConfigChange records hand edits to preflight.yml: when it no longer
+// matches the hash of the last entry, it appends a severity entry for each
+// override that changed since then, or a config_change entry when none did.
+// Commands that edit preflight.yml call it first, so an edit made by hand
+// is on record before theirs. It only acts on projects that already keep
+// an audit log, and reports whether anything was written.
+func RecordConfigChange(projectDir, source string) (bool, error) {
+	entries, err := Read(projectDir)
+	if err != nil || len(entries) == 0 {
+		return false, err
+	}
+	last := entries[len(entries)-1]
+	hash := ConfigHash(projectDir)
+	if hash == "" || hash == last.ConfigHash {
+		return false, nil
+	}
+
+	current := configSeverities(projectDir)
+	ids := map[string]bool{}
+	for id := range last.Severities {
+		ids[id] = true
+	}
+	for id := range current {
+		ids[id] = true
+	}
+	var changed []string
+	for id := range ids {
+		if last.Severities[id] != current[id] {
+			changed = append(changed, id)
+		}
+	}
+	sort.Strings(changed)
+	for _, id := range changed {
+		err := Append(projectDir, Entry{
+			Action:     ActionSeverity,
+			CheckID:    id,
+			From:       last.Severities[id],
+			To:         current[id],
+			Source:     source,
+			ConfigHash: hash,
+			Severities: current,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	if len(changed) > 0 {
+		return true, nil
+	}
+	err = Append(projectDir, Entry{
+		Action:     ActionConfigChange,
+		Source:     source,
+		Reason:     "preflight.yml changed outside preflight",
+		ConfigHash: hash,
+		Severities: current,
+	})
+	return err == nil, err
+}
+
+// ConfigHash returns the hex SHA-256 of the project's preflight.yml, or ""
+// when it can't be read.
+func ConfigHash(projectDir string) string {
+	data, err := os.ReadFile(filepath.Join(projectDir, "preflight.yml"))
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// configSeverities returns the severities map in the project's
+// preflight.yml, or nil when there is none or it can't be read.
+func configSeverities(projectDir string) map[string]string {
+	data, err := os.ReadFile(filepath.Join(projectDir, "preflight.yml"))
+	if err != nil {
+		return nil
+	}
+	var cfg struct {
+		Severities map[string]string `yaml:"severities"`
+	}
+	if yaml.Unmarshal(data, &cfg) != nil {
+		return nil
+	}
+	return cfg.Severities
+}
+
+// CurrentUser identifies who is making a change: the git identity
+// configured for the project ("Name <email>") when there is one, otherwise
+// the OS account name.
+func CurrentUser(projectDir string) string {
+	name := gitConfig(projectDir, "user.name")
+	email := gitConfig(projectDir, "user.email")
+	switch {
+	case name != "" && email != "":
+		return fmt.Sprintf("%s <%s>", name, email)
+	case email != "":
+		return email
+	case name != "":
+		return name
+	}
+	if u := os.Getenv("USER"); u != "" {
+		return u
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
+
+func gitConfig(dir, key string) string {
+	cmd := exec.Command("git", "config", "--get", key)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	dir := t.TempDir()
+	if entries, err := Read(dir); err != nil || entries != nil {
+		t.Fatalf("Read on a project without a log = %v, %v; want nil, nil", entries, err)
+	}
+	writeConfig(t, dir, "ignore: [secrets]\n")
+
+	if err := Append(dir, Entry{Action: ActionIgnore, CheckID: "secrets", Reason: "fixtures", User: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(dir, Entry{Action: ActionUnignore, CheckID: "secrets", User: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Read returned %d entries, want 2", len(entries))
+	}
+	first := entries[0]
+	if first.Action != ActionIgnore || first.Reason != "fixtures" || first.Time.IsZero() {
+		t.Errorf("first entry = %+v", first)
+	}
+	if first.ConfigHash != ConfigHash(dir) || first.ConfigHash == "" {
+		t.Errorf("ConfigHash = %q, want the hash of preflight.yml", first.ConfigHash)
+	}
+}
+
+func TestRecordConfigChange(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, "projectName: demo\n")
+
+	// No log yet: noting hand edits must not start one.
+	if wrote, err := RecordConfigChange(dir, "cli"); wrote || err != nil {
+		t.Fatalf("RecordConfigChange without a log = %v, %v", wrote, err)
+	}
+	if _, err := os.Stat(LogPath(dir)); !os.IsNotExist(err) {
+		t.Fatal("RecordConfigChange created an audit log")
+	}
+
+	if err := Append(dir, Entry{Action: ActionIgnore, CheckID: "sitemap", User: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	if wrote, _ := RecordConfigChange(dir, "cli"); wrote {
+		t.Error("RecordConfigChange logged an unchanged config")
+	}
+
+	writeConfig(t, dir, "projectName: demo\nignore: [secrets]\n")
+	if wrote, err := RecordConfigChange(dir, "cli"); !wrote || err != nil {
+		t.Fatalf("RecordConfigChange after a hand edit = %v, %v; want an entry", wrote, err)
+	}
+	if wrote, _ := RecordConfigChange(dir, "cli"); wrote {
+		t.Error("RecordConfigChange logged the same edit twice")
+	}
+
+	entries, _ := Read(dir)
+	if last := entries[len(entries)-1]; last.Action != ActionConfigChange || last.Source != "cli" {
+		t.Errorf("last entry = %+v, want a config_change from cli", last)
+	}
+}
+
+func TestRecordSeverityChange(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, "severities:\n  sitemap: info\n")
+	if err := Append(dir, Entry{Action: ActionIgnore, CheckID: "secrets", User: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+
+	writeConfig(t, dir, "severities:\n  sitemap: error\n  favicon: info\n")
+	if wrote, err := RecordConfigChange(dir, "cli"); !wrote || err != nil {
+		t.Fatalf("RecordConfigChange = %v, %v; want entries", wrote, err)
+	}
+	writeConfig(t, dir, "severities:\n  favicon: info\n")
+	if wrote, err := RecordConfigChange(dir, "ui"); !wrote || err != nil {
+		t.Fatalf("RecordConfigChange = %v, %v; want an entry", wrote, err)
+	}
+
+	entries, _ := Read(dir)
+	var got []string
+	for _, e := range entries[1:] {
+		got = append(got, fmt.Sprintf("%s %s %s->%s %s", e.Action, e.CheckID, e.From, e.To, e.Source))
+	}
+	want := []string{
+		"severity favicon ->info cli",
+		"severity sitemap info->error cli",
+		"severity sitemap error-> ui",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("entries:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func writeConfig(t *testing.T, dir, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "preflight.yml"), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
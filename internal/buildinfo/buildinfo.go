@@ -0,0 +1,158 @@
+// Package buildinfo describes the running binary: the version metadata
+// stamped in at link time, and the SHA-256 checksum release builds append
+// to the executable so a copy can vouch for its own integrity.
+//
+// The checksum can't be linked in, since it would change the bytes it
+// covers. Instead the release pipeline runs Embed on each built binary,
+// which appends a fixed-size trailer (a magic string followed by the
+// checksum of everything before it). Executable formats ignore trailing
+// data, so the binary runs unchanged. Mach-O binaries are left alone:
+// appending to them invalidates the code signature macOS requires, and
+// codesign already covers their integrity.
+package buildinfo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+)
+
+// Info is the build metadata `preflight version --json` prints.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"goVersion"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	// Checksum is the embedded SHA-256, when the binary carries one.
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// Read assembles Info. version, commit and date are the values stamped in
+// with -ldflags; commit and date fall back to the VCS stamp the Go
+// toolchain records in source builds.
+func Read(version, commit, date string) Info {
+	info := Info{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	if exe, err := os.Executable(); err == nil {
+		if data, err := os.ReadFile(exe); err == nil {
+			if _, sum, ok := splitTrailer(data); ok {
+				info.Checksum = hex.EncodeToString(sum)
+			}
+		}
+	}
+	return info
+}
+
+// trailerMagic opens the trailer Embed appends; the 32-byte checksum
+// follows it.
+var trailerMagic = []byte("PREFLIGHT-SHA256")
+
+const trailerSize = 16 + sha256.Size
+
+// ErrNoChecksum is returned by Verify for a binary without a trailer:
+// source builds, and macOS releases.
+var ErrNoChecksum = errors.New("no embedded checksum")
+
+// ErrMismatch is returned by Verify when the binary's bytes no longer
+// hash to its embedded checksum.
+var ErrMismatch = errors.New("checksum mismatch")
+
+func splitTrailer(data []byte) (body, sum []byte, ok bool) {
+	if len(data) < trailerSize {
+		return nil, nil, false
+	}
+	trailer := data[len(data)-trailerSize:]
+	if !bytes.Equal(trailer[:len(trailerMagic)], trailerMagic) {
+		return nil, nil, false
+	}
+	return data[:len(data)-trailerSize], trailer[len(trailerMagic):], true
+}
+
+// Verify checks the file at path against its embedded checksum and
+// returns the checksum in hex.
+func Verify(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	body, want, ok := splitTrailer(data)
+	if !ok {
+		return "", ErrNoChecksum
+	}
+	got := sha256.Sum256(body)
+	if !bytes.Equal(got[:], want) {
+		return hex.EncodeToString(want), fmt.Errorf("%w: binary hashes to %s", ErrMismatch, hex.EncodeToString(got[:]))
+	}
+	return hex.EncodeToString(want), nil
+}
+
+// Embed appends the checksum trailer to the binary at path. It reports
+// false, without writing, for Mach-O binaries and ones that already
+// carry a trailer.
+func Embed(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	if isMachO(data) {
+		return false, nil
+	}
+	if _, _, ok := splitTrailer(data); ok {
+		return false, nil
+	}
+	sum := sha256.Sum256(data)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return false, err
+	}
+	if _, err := f.Write(append(append([]byte(nil), trailerMagic...), sum[:]...)); err != nil {
+		f.Close()
+		return false, err
+	}
+	return true, f.Close()
+}
+
+// isMachO reports whether data starts with a Mach-O or universal binary
+// magic number.
+func isMachO(data []byte) bool {
+	if len(data) < 4 {
+		return false
+	}
+	switch [4]byte(data[:4]) {
+	case [4]byte{0xcf, 0xfa, 0xed, 0xfe}, [4]byte{0xce, 0xfa, 0xed, 0xfe}, // 64- and 32-bit, little-endian
+		[4]byte{0xfe, 0xed, 0xfa, 0xcf}, [4]byte{0xfe, 0xed, 0xfa, 0xce}, // big-endian
+		[4]byte{0xca, 0xfe, 0xba, 0xbe}: // universal
+		return true
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package buildinfo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeBinary(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "preflight")
+	if err := os.WriteFile(path, data, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEmbedAndVerify(t *testing.T) {
+	path := writeBinary(t, []byte("\x7fELF pretend this is a binary"))
+	if _, err := Verify(path); !errors.Is(err, ErrNoChecksum) {
+		t.Fatalf("Verify before Embed = %v, want ErrNoChecksum", err)
+	}
+	if embedded, err := Embed(path); err != nil || !embedded {
+		t.Fatalf("Embed = %v, %v", embedded, err)
+	}
+	if _, err := Verify(path); err != nil {
+		t.Fatalf("Verify after Embed = %v", err)
+	}
+	// A second run in the pipeline must not stack trailers.
+	if embedded, err := Embed(path); err != nil || embedded {
+		t.Errorf("second Embed = %v, %v, want a no-op", embedded, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[5] ^= 0xff
+	if err := os.WriteFile(path, data, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(path); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Verify of a modified binary = %v, want ErrMismatch", err)
+	}
+}
+
+func TestEmbedSkipsMachO(t *testing.T) {
+	original := []byte("\xcf\xfa\xed\xfe signed darwin binary")
+	path := writeBinary(t, original)
+	if embedded, err := Embed(path); err != nil || embedded {
+		t.Fatalf("Embed = %v, %v, want Mach-O skipped", embedded, err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != string(original) {
+		t.Error("Embed modified a Mach-O binary")
+	}
+}
+
+func TestReadFallsBackToBuildStamp(t *testing.T) {
+	info := Read("1.2.3", "abc123", "")
+	if info.Version != "1.2.3" || info.Commit != "abc123" || info.GoVersion == "" || info.OS == "" {
+		t.Errorf("Read = %+v", info)
+	}
+}
@@ -0,0 +1,244 @@
+// Package cache stores scan results under a caller-chosen key, so the
+// stages of one CI pipeline (lint, test, deploy) can share a single scan
+// of a commit instead of each running the suite.
+//
+// A store is named by URL:
+//
+//	/path/to/dir or file:///path/to/dir   a directory (a mounted cache volume)
+//	https://cache.example.com/preflight   GET and PUT <url>/<key>.json
+//	s3://bucket/prefix                  Amazon S3 or any S3-compatible store
+//	gs://bucket/prefix                  Google Cloud Storage
+//
+// Credentials come from the environment each service's own tooling uses;
+// see the Open* functions.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/checks"
+)
+
+// ErrMiss is returned by Get when nothing is stored under the key.
+var ErrMiss = errors.New("cache miss")
+
+// Store reads and writes cache entries by key.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// Entry is what a scan stores: enough to render any output format without
+// re-running the checks.
+type Entry struct {
+	Key       string               `json:"key"`
+	Version   string               `json:"version"` // of the preflight that ran the scan
+	CreatedAt time.Time            `json:"createdAt"`
+	Project   string               `json:"project"`
+	Results   []checks.CheckResult `json:"results"`
+}
+
+// httpTimeout bounds each cache request. A slow cache should cost a
+// pipeline a re-scan, not a hang.
+const httpTimeout = 30 * time.Second
+
+// The cache URL comes from the pipeline's own configuration, and caches
+// commonly live on private networks, so this is a plain client rather
+// than netutil.SafeHTTPClient.
+var httpClient = &http.Client{Timeout: httpTimeout}
+
+// maxEntrySize caps what Get reads back; real entries are a few hundred KB.
+const maxEntrySize = 32 << 20
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,199}$`)
+
+// ValidateKey rejects keys that wouldn't map cleanly onto a file name or
+// an object key. Commit SHAs, branch-safe names and build numbers pass.
+func ValidateKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("invalid cache key %q (use letters, digits, '.', '_' and '-', up to 200 characters)", key)
+	}
+	return nil
+}
+
+// Open returns the store rawURL names.
+func Open(rawURL string) (Store, error) {
+	if rawURL == "" {
+		return nil, errors.New("no cache URL set")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 { // a Windows drive letter is not a scheme
+		return DirStore{Dir: rawURL}, nil
+	}
+	switch u.Scheme {
+	case "file":
+		return DirStore{Dir: filepath.FromSlash(u.Path)}, nil
+	case "http", "https":
+		return OpenHTTP(u), nil
+	case "s3", "gs":
+		// Keep a failed open from surfacing as a non-nil Store.
+		var store *HTTPStore
+		if u.Scheme == "s3" {
+			store, err = OpenS3(u)
+		} else {
+			store, err = OpenGCS(u)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	}
+	return nil, fmt.Errorf("unsupported cache URL scheme %q (want a path, file, http, https, s3 or gs)", u.Scheme)
+}
+
+// objectName joins a key onto a URL path prefix.
+func objectName(prefix, key string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return key + ".json"
+	}
+	return prefix + "/" + key + ".json"
+}
+
+// DirStore keeps entries as files in a directory.
+type DirStore struct {
+	Dir string
+}
+
+func (d DirStore) Get(_ context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(d.Dir, key+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrMiss
+	}
+	return data, err
+}
+
+func (d DirStore) Put(_ context.Context, key string, data []byte) error {
+	if err := os.MkdirAll(d.Dir, 0o755); err != nil {
+		return err
+	}
+	// Write then rename, so a concurrent stage never reads half an entry.
+	tmp, err := os.CreateTemp(d.Dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(d.Dir, key+".json"))
+}
+
+// HTTPStore is a generic HTTP cache: entries live at <base>/<key>.json,
+// read with GET and written with PUT.
+type HTTPStore struct {
+	Base *url.URL
+	// Token, when set, is sent as a bearer token.
+	Token string
+	// sign, when set, authenticates each request instead of Token.
+	sign func(req *http.Request, payload []byte) error
+}
+
+// OpenHTTP returns the store for an http(s) URL. PREFLIGHT_CACHE_TOKEN,
+// when set, is sent as a bearer token.
+func OpenHTTP(base *url.URL) *HTTPStore {
+	return &HTTPStore{Base: base, Token: os.Getenv("PREFLIGHT_CACHE_TOKEN")}
+}
+
+func (h *HTTPStore) objectURL(key string) string {
+	u := *h.Base
+	u.Path = "/" + objectName(h.Base.Path, key)
+	u.RawPath = ""
+	return u.String()
+}
+
+func (h *HTTPStore) do(ctx context.Context, method, key string, payload []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, h.objectURL(key), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if h.sign != nil {
+		if err := h.sign(req, payload); err != nil {
+			return nil, err
+		}
+	} else if h.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.Token)
+	}
+	return httpClient.Do(req)
+}
+
+func (h *HTTPStore) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := h.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrMiss
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cache GET %s: %s", redact(h.objectURL(key)), resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxEntrySize))
+}
+
+func (h *HTTPStore) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := h.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("cache PUT %s: %s", redact(h.objectURL(key)), resp.Status)
+	}
+	return nil
+}
+
+// redact drops credentials and query strings (presigned URLs) from a URL
+// before it goes into an error message.
+func redact(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "cache URL"
+	}
+	u.User = nil
+	u.RawQuery = ""
+	return u.String()
+}
+
+// Encode serializes an entry for Put.
+func Encode(e Entry) ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// Decode parses an entry read with Get, checking it was stored under key.
+func Decode(data []byte, key string) (Entry, error) {
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Entry{}, fmt.Errorf("corrupt cache entry: %w", err)
+	}
+	if e.Key != key {
+		return Entry{}, fmt.Errorf("cache entry is for key %q, not %q", e.Key, key)
+	}
+	return e, nil
+}
@@ -0,0 +1,23 @@
+package cache
+
+import (
+	"errors"
+	"net/url"
+)
+
+// OpenGCS returns the store for gs://bucket/prefix, using the Cloud
+// Storage XML API with an OAuth access token from
+// CLOUDSDK_AUTH_ACCESS_TOKEN or GOOGLE_OAUTH_ACCESS_TOKEN. In CI, the
+// google-github-actions/auth action (or `gcloud auth print-access-token`)
+// provides one.
+func OpenGCS(u *url.URL) (*HTTPStore, error) {
+	if u.Host == "" {
+		return nil, errors.New("gs cache URL needs a bucket (gs://bucket/prefix)")
+	}
+	token := firstEnv("CLOUDSDK_AUTH_ACCESS_TOKEN", "GOOGLE_OAUTH_ACCESS_TOKEN")
+	if token == "" {
+		return nil, errors.New("gs cache needs an access token in CLOUDSDK_AUTH_ACCESS_TOKEN or GOOGLE_OAUTH_ACCESS_TOKEN")
+	}
+	base := &url.URL{Scheme: "https", Host: "storage.googleapis.com", Path: "/" + u.Host + u.Path}
+	return &HTTPStore{Base: base, Token: token}, nil
+}
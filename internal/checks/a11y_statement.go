@@ -0,0 +1,148 @@
+package checks
+
+import (
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// A11yStatementCheck looks for an accessibility statement: a page saying
+// which standard the site meets, where it falls short and how to report a
+// problem. Public-sector sites in the EU and UK must publish one, and
+// enterprise procurement increasingly asks for it. Like legal_pages, it
+// finds the page on the live site or in the project, then checks that the
+// shared layout links to it and that it offers a way to get in touch.
+type A11yStatementCheck struct{}
+
+func (c A11yStatementCheck) ID() string {
+	return "a11y_statement"
+}
+
+func (c A11yStatementCheck) Title() string {
+	return "Accessibility statement"
+}
+
+// a11yStatementURLs are the paths an accessibility statement is usually
+// served at; a11yStatementPatterns are the matching page files.
+var (
+	a11yStatementURLs = []string{
+		"/accessibility", "/accessibility-statement", "/accessibility-policy",
+		"/legal/accessibility", "/legal/accessibility-statement",
+		"/policies/accessibility", "/about/accessibility", "/a11y",
+	}
+	a11yStatementPatterns = []string{
+		"accessibility", "accessibility-statement", "accessibility_statement", "accessibility-policy",
+		"legal/accessibility", "legal/accessibility-statement",
+		"policies/accessibility", "about/accessibility", "a11y",
+	}
+)
+
+// a11yTarget matches a link target naming the accessibility statement.
+var a11yTarget = regexp.MustCompile(`accessib|\ba11y\b`)
+
+// a11yContact matches a way to report an accessibility problem: an email
+// address, a mailto: or tel: link, or a link to a contact or feedback page.
+var a11yContact = regexp.MustCompile(`(?i)mailto:|tel:|[\w.+-]+@[\w-]+\.[\w.-]{2,}|href\s*=\s*\{?\s*["'\x60][^"'\x60]*(contact|feedback|support)|contact (us|form)|feedback form`)
+
+func (c A11yStatementCheck) Run(ctx Context) (CheckResult, error) {
+	var where, content string
+	readable := false
+
+	baseURL := ctx.Config.URLs.Staging
+	if baseURL == "" {
+		baseURL = ctx.Config.URLs.Production
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if baseURL != "" && ctx.Client != nil {
+		if path := probeLegalPage(ctx, noRedirectClient(ctx.Client), baseURL, a11yStatementURLs, "accessib"); path != "" {
+			where = path + " (via HTTP)"
+			if resp, err := getWithContext(ctx.reqContext(), ctx.Client, baseURL+path); err == nil {
+				body, err := io.ReadAll(io.LimitReader(resp.Body, netutil.MaxResponseBody))
+				resp.Body.Close()
+				if err == nil && resp.StatusCode < 400 {
+					content, readable = string(body), true
+				}
+			}
+		}
+	}
+	if where == "" {
+		if path := findLegalPageFile(ctx.RootDir, a11yStatementPatterns); path != "" {
+			where = path
+			if data, err := readFile(filepath.Join(ctx.RootDir, path)); err == nil {
+				content, readable = string(data), true
+			}
+		}
+	}
+
+	if where == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  "No accessibility statement found",
+			Suggestions: []string{
+				"Publish an accessibility statement (e.g., /accessibility) naming the standard you target (WCAG 2.2 AA), known gaps and how to report a problem",
+				"The W3C generator drafts one: https://www.w3.org/WAI/planning/statements/generator/",
+			},
+		}, nil
+	}
+
+	var linkedFrom string
+	var searched []string
+	for _, src := range sharedMarkup(ctx) {
+		searched = append(searched, src.name)
+		for _, target := range linkTargets(src.content) {
+			if a11yTarget.MatchString(target) {
+				linkedFrom = src.name
+				break
+			}
+		}
+		if linkedFrom != "" {
+			break
+		}
+	}
+	if len(searched) > 0 && linkedFrom == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  "Accessibility statement at " + where + " isn't linked from the layout or footer",
+			Suggestions: []string{
+				"Link the accessibility statement from the footer every page shares",
+			},
+			Details: []string{"Looked in " + strings.Join(searched, ", ")},
+		}, nil
+	}
+
+	if readable && !a11yContact.MatchString(content) {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  "Accessibility statement at " + where + " gives no way to report a problem",
+			Suggestions: []string{
+				"Add an email address, phone number or contact form link for accessibility feedback",
+			},
+		}, nil
+	}
+
+	result := CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   true,
+		Message:  "Found accessibility statement at " + where,
+	}
+	if linkedFrom != "" {
+		result.Details = append(result.Details, "Linked from "+linkedFrom)
+	} else {
+		result.Details = append(result.Details, "No layout, footer or rendered homepage found to check for a link to it")
+	}
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestA11yStatementCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		passed  bool
+		message string
+	}{
+		{
+			name:    "missing",
+			files:   map[string]string{"index.html": `<a href="/privacy">Privacy</a>`},
+			passed:  false,
+			message: "No accessibility statement found",
+		},
+		{
+			name: "not linked",
+			files: map[string]string{
+				"index.html":         `<a href="/privacy">Privacy</a>`,
+				"accessibility.html": `<p>Email <a href="mailto:a11y@example.com">us</a></p>`,
+			},
+			passed:  false,
+			message: "Accessibility statement at accessibility.html isn't linked from the layout or footer",
+		},
+		{
+			name: "no contact",
+			files: map[string]string{
+				"index.html":         `<footer><a href="/accessibility.html">Accessibility</a></footer>`,
+				"accessibility.html": `<p>We aim for WCAG 2.2 AA.</p>`,
+			},
+			passed:  false,
+			message: "Accessibility statement at accessibility.html gives no way to report a problem",
+		},
+		{
+			name: "linked with contact",
+			files: map[string]string{
+				"index.html":         `<footer><a href="/accessibility.html">Accessibility</a></footer>`,
+				"accessibility.html": `<p>We aim for WCAG 2.2 AA. Problems? Write to access@example.org.</p>`,
+			},
+			passed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, tt.files)
+			result, err := A11yStatementCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{Stack: "static"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tt.passed {
+				t.Fatalf("passed = %v, want %v (%q)", result.Passed, tt.passed, result.Message)
+			}
+			if tt.message != "" && result.Message != tt.message {
+				t.Errorf("message = %q, want %q", result.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestA11yStatementCheckLive(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/accessibility":
+			http.Redirect(w, r, "/legal/accessibility-statement", http.StatusMovedPermanently)
+		case "/legal/accessibility-statement":
+			w.Write([]byte(`<p>We target WCAG 2.2 AA.</p><a href="/contact">Contact us</a>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	result, err := A11yStatementCheck{}.Run(Context{
+		RootDir:  t.TempDir(),
+		Config:   cfg,
+		Client:   srv.Client(),
+		PageHTML: `<footer><a href="/accessibility">Accessibility</a></footer>`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || result.Message != "Found accessibility statement at /accessibility (via HTTP)" {
+		t.Errorf("passed=%v message=%q", result.Passed, result.Message)
+	}
+}
@@ -1,9 +1,11 @@
 package checks
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 )
 
 // FathomCheck verifies Fathom Analytics is properly set up
@@ -36,7 +38,7 @@ func (c FathomCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`data-site=`),
 	}
 
-	found := searchForPatterns(ctx.RootDir, ctx.Config.Stack, patterns)
+	found := searchForPatterns(ctx, patterns)
 
 	if found {
 		return CheckResult{
@@ -94,7 +96,7 @@ func (c GoogleAnalyticsCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`UA-[0-9]+-[0-9]+`), // Universal Analytics
 	}
 
-	found := searchForPatterns(ctx.RootDir, ctx.Config.Stack, patterns)
+	found := searchForPatterns(ctx, patterns)
 
 	if found {
 		return CheckResult{
@@ -159,7 +161,7 @@ func (c RedisCheck) Run(ctx Context) (CheckResult, error) {
 	}
 
 	// First, do a codebase-wide search for Redis patterns
-	if match := searchForPatterns(ctx.RootDir, ctx.Config.Stack, configPatterns); match {
+	if match := searchForPatterns(ctx, configPatterns); match {
 		return CheckResult{
 			ID:       c.ID(),
 			Title:    c.Title(),
@@ -274,7 +276,7 @@ var dependencyManifests = []string{
 // content (manifests are JSON/text, not commented source).
 func scanDependencyManifests(rootDir string, patterns []*regexp.Regexp) (string, bool) {
 	for _, name := range dependencyManifests {
-		content, err := os.ReadFile(filepath.Join(rootDir, name))
+		content, err := readFile(filepath.Join(rootDir, name))
 		if err != nil {
 			continue
 		}
@@ -287,8 +289,8 @@ func scanDependencyManifests(rootDir string, patterns []*regexp.Regexp) (string,
 	return "", false
 }
 
-func searchForPatterns(rootDir, stack string, patterns []*regexp.Regexp) bool {
-	layoutFiles := getLayoutFilesForStack(stack)
+func searchForPatterns(ctx Context, patterns []*regexp.Regexp) bool {
+	rootDir, stack := ctx.RootDir, ctx.Config.Stack
 
 	// A declared dependency in a package manifest counts as the integration
 	// being present, since credentials are often managed outside the repo.
@@ -296,16 +298,18 @@ func searchForPatterns(rootDir, stack string, patterns []*regexp.Regexp) bool {
 		return true
 	}
 
-	for _, file := range layoutFiles {
-		path := filepath.Join(rootDir, file)
-		content, err := os.ReadFile(path)
+	// Layouts first, with every partial they include: the snippet often
+	// lives in a head partial rather than the layout itself.
+	for _, file := range layoutCandidates(ctx) {
+		graph, err := templateGraph(filepath.Join(rootDir, file), rootDir, stack)
 		if err != nil {
 			continue
 		}
-
-		for _, pattern := range patterns {
-			if pattern.Match(content) {
-				return true
+		for _, tpl := range graph {
+			for _, pattern := range patterns {
+				if pattern.MatchString(tpl.content) {
+					return true
+				}
 			}
 		}
 	}
@@ -366,7 +370,8 @@ func searchForPatterns(rootDir, stack string, patterns []*regexp.Regexp) bool {
 		}
 
 		found := false
-		_ = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		_ = walk(dirPath, func(path string, info os.FileInfo, err error) error {
+			recordUnreadable(path, err)
 			if err != nil || found {
 				return nil
 			}
@@ -397,7 +402,7 @@ func searchForPatterns(rootDir, stack string, patterns []*regexp.Regexp) bool {
 				return nil
 			}
 
-			content, err := os.ReadFile(path)
+			content, err := readFile(path)
 			if err != nil {
 				return nil
 			}
@@ -427,8 +432,8 @@ type SearchMatch struct {
 }
 
 // searchForPatternsWithDetails searches for patterns and returns details about the match
-func searchForPatternsWithDetails(rootDir, stack string, patterns []*regexp.Regexp) *SearchMatch {
-	layoutFiles := getLayoutFilesForStack(stack)
+func searchForPatternsWithDetails(ctx Context, patterns []*regexp.Regexp) *SearchMatch {
+	rootDir, stack := ctx.RootDir, ctx.Config.Stack
 
 	// A declared dependency in a package manifest counts as the integration
 	// being present, since credentials are often managed outside the repo.
@@ -436,22 +441,23 @@ func searchForPatternsWithDetails(rootDir, stack string, patterns []*regexp.Rege
 		return &SearchMatch{FilePath: name, Pattern: "dependency manifest"}
 	}
 
-	for _, file := range layoutFiles {
-		path := filepath.Join(rootDir, file)
-		content, err := os.ReadFile(path)
+	for _, file := range layoutCandidates(ctx) {
+		graph, err := templateGraph(filepath.Join(rootDir, file), rootDir, stack)
 		if err != nil {
 			continue
 		}
+		for _, tpl := range graph {
+			// Strip comments to avoid false positives on commented-out code
+			contentStr := stripComments(tpl.content)
 
-		// Strip comments to avoid false positives on commented-out code
-		contentStr := stripComments(string(content))
-
-		for _, pattern := range patterns {
-			if pattern.MatchString(contentStr) {
-				relPath := relPath(rootDir, path)
-				return &SearchMatch{
-					FilePath: relPath,
-					Pattern:  pattern.String(),
+			for _, pattern := range patterns {
+				if pattern.MatchString(contentStr) {
+					// Report the partial that matched, not the layout
+					// that included it.
+					return &SearchMatch{
+						FilePath: relPath(rootDir, tpl.path),
+						Pattern:  pattern.String(),
+					}
 				}
 			}
 		}
@@ -513,7 +519,8 @@ func searchForPatternsWithDetails(rootDir, stack string, patterns []*regexp.Rege
 			continue
 		}
 
-		_ = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		_ = walk(dirPath, func(path string, info os.FileInfo, err error) error {
+			recordUnreadable(path, err)
 			if err != nil || result != nil {
 				return nil
 			}
@@ -544,7 +551,7 @@ func searchForPatternsWithDetails(rootDir, stack string, patterns []*regexp.Rege
 				return nil
 			}
 
-			content, err := os.ReadFile(path)
+			content, err := readFile(path)
 			if err != nil {
 				return nil
 			}
@@ -574,53 +581,32 @@ func searchForPatternsWithDetails(rootDir, stack string, patterns []*regexp.Rege
 	return nil
 }
 
-func getLayoutFilesForStack(stack string) []string {
-	layouts := map[string][]string{
-		// Backend Frameworks
-		"rails":   {"app/views/layouts/application.html.erb", "app/views/layouts/application.html.haml"},
-		"laravel": {"resources/views/layouts/app.blade.php", "resources/views/app.blade.php"},
-		"django":  {"templates/base.html", "templates/layout.html", "templates/index.html"},
-		"python":  {"templates/base.html", "templates/layout.html", "templates/index.html"},
-		"go":      {"templates/base.html", "templates/layout.html", "views/base.html", "web/templates/base.html"},
-		"rust":    {"templates/base.html", "templates/layout.html"},
-		"node":    {"views/layout.ejs", "views/layout.pug", "views/layout.hbs", "views/layouts/main.hbs"},
-
-		// Frontend Frameworks
-		"next":    {"app/layout.tsx", "app/layout.js", "pages/_app.tsx", "pages/_app.js", "pages/_document.tsx", "pages/_document.js", "src/app/layout.tsx"},
-		"react":   {"src/App.tsx", "src/App.jsx", "src/App.js", "src/index.tsx", "src/index.jsx", "public/index.html"},
-		"vue":     {"src/App.vue", "src/main.ts", "src/main.js", "index.html", "public/index.html"},
-		"vite":    {"index.html", "src/App.tsx", "src/App.jsx", "src/App.vue", "src/App.svelte"},
-		"svelte":  {"src/App.svelte", "src/routes/+layout.svelte", "src/app.html"},
-		"angular": {"src/index.html", "src/app/app.component.ts", "src/app/app.component.html"},
-
-		// Traditional CMS
-		"wordpress": {"wp-content/themes/theme/header.php", "wp-content/themes/theme/functions.php", "header.php"},
-		"craft":     {"templates/_layout.twig", "templates/_layout.html", "templates/_partials/head.twig"},
-		"drupal":    {"themes/custom/theme/templates/html.html.twig", "web/themes/custom/theme/templates/html.html.twig"},
-		"ghost":     {"content/themes/casper/default.hbs", "default.hbs"},
-
-		// Static Site Generators
-		"hugo":     {"layouts/_default/baseof.html", "themes/theme/layouts/_default/baseof.html", "layouts/partials/head.html"},
-		"jekyll":   {"_layouts/default.html", "_includes/head.html", "_includes/header.html"},
-		"gatsby":   {"src/components/layout.js", "src/components/layout.tsx", "src/html.js", "gatsby-browser.js"},
-		"eleventy": {"_includes/layout.njk", "_includes/base.njk", "_includes/layout.liquid", "src/_includes/layout.njk"},
-		"astro":    {"src/layouts/Layout.astro", "src/layouts/BaseLayout.astro", "src/components/Head.astro"},
-
-		// Headless CMS (frontend detection)
-		"strapi":     {"src/index.js", "config/server.js"},
-		"sanity":     {"sanity.config.ts", "sanity.config.js"},
-		"contentful": {"src/templates/page.js", "src/App.js"},
-		"prismic":    {"src/components/Layout.js", "slicemachine.config.json"},
-
-		// Generic PHP
-		"php": {"header.php", "includes/header.php", "partials/header.php", "templates/header.php", "inc/header.php"},
-
-		// Static
-		"static": {"index.html", "public/index.html", "dist/index.html"},
+// layoutCandidates lists the layouts to search: the configured mainLayout
+// first, then the templates in each declared stack's pack. Globs in a
+// stack's list are expanded to the files they match.
+func layoutCandidates(ctx Context) []string {
+	var files []string
+	if cfg := ctx.Config.Checks.SEOMeta; cfg != nil && cfg.MainLayout != "" {
+		files = append(files, cfg.MainLayout)
 	}
-
-	if files, ok := layouts[stack]; ok {
-		return files
+	var stackFiles []string
+	for _, stack := range ctx.Config.AllStacks() {
+		stackFiles = append(stackFiles, getLayoutFilesForStack(stack)...)
+	}
+	for _, file := range stackFiles {
+		if !strings.Contains(file, "*") {
+			files = append(files, file)
+			continue
+		}
+		matches, _ := fs.Glob(os.DirFS(ctx.RootDir), file)
+		for _, m := range matches {
+			files = append(files, filepath.FromSlash(m))
+		}
 	}
-	return []string{"index.html", "public/index.html"}
+	return files
+}
+
+// getLayoutFilesForStack returns the templates in the stack's pack.
+func getLayoutFilesForStack(stack string) []string {
+	return stackPack(stack).Templates
 }
@@ -0,0 +1,158 @@
+package checks
+
+import (
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// AnalyticsIDsCheck looks for analytics snippets still carrying the
+// placeholder a tutorial or starter template shipped them with: a
+// UA-XXXXX-Y or G-XXXXXXXXXX tag, Plausible's data-domain="example.com",
+// a YOUR_DOMAIN left to fill in. The snippet loads fine and the site
+// looks instrumented, but nothing gets recorded after launch.
+type AnalyticsIDsCheck struct{}
+
+func (c AnalyticsIDsCheck) ID() string {
+	return "analytics_ids"
+}
+
+func (c AnalyticsIDsCheck) Title() string {
+	return "Analytics IDs"
+}
+
+// reAnalyticsPlaceholder matches the Google tag IDs tutorials and
+// templates ship with in place of a real one.
+var reAnalyticsPlaceholder = regexp.MustCompile(`\b(UA-(?:[Xx]{4,}|0{4,}|12345\d*)(?:-[XxYy0-9]+)?|G-(?:[Xx]{6,}|0{6,})|GTM-(?:[Xx]{5,}|0{5,})|AW-(?:[Xx]{6,}|0{6,}))\b|gtag\(\s*['"]config['"]\s*,\s*['"](GA_MEASUREMENT_ID|GA_TRACKING_ID)['"]`)
+
+// analyticsPlaceholders are the placeholders to look for, by provider.
+// The first submatch of each pattern is the placeholder itself.
+var analyticsPlaceholders = []struct {
+	provider string
+	pattern  *regexp.Regexp
+}{
+	{"Google", reAnalyticsPlaceholder},
+	{"Plausible", regexp.MustCompile(`data-domain=["']((?:www\.)?(?:example\.(?:com|org|net)|(?:your|my)-?(?:domain|site|website)\.com|domain\.com))["']`)},
+	{"Fathom", regexp.MustCompile(`data-site=["']([Xx]{4,}|ABCDEFG[A-Z]?|YOUR[_-]SITE[_-]ID)["']`)},
+	{"Umami", regexp.MustCompile(`data-website-id=["']((?:[Xx]{8}|0{8})-[Xx0]{4}-[Xx0]{4}-[Xx0]{4}-[Xx0]{12}|your-website-id)["']`)},
+	{"PostHog", regexp.MustCompile(`['"](phc_(?:[Xx]{6,}|YOUR[A-Z_]*|your[a-z_]*))['"]`)},
+	{"", regexp.MustCompile(`\b(YOUR[_-](?:DOMAIN|SITE[_-]ID|TRACKING[_-]ID|MEASUREMENT[_-]ID|WEBSITE[_-]ID|GA[_-]ID|GTM[_-]ID|PIXEL[_-]ID|PROJECT[_-](?:API[_-])?KEY))\b`)},
+}
+
+func (c AnalyticsIDsCheck) Run(ctx Context) (CheckResult, error) {
+	var findings []string
+	var locations []Location
+	seen := map[string]bool{}
+	report := func(loc Location) {
+		if key := loc.String(); !seen[key] {
+			seen[key] = true
+			findings = append(findings, key)
+			locations = append(locations, loc)
+		}
+	}
+
+	layouts := 0
+	for _, file := range layoutCandidates(ctx) {
+		graph, err := templateGraph(filepath.Join(ctx.RootDir, file), ctx.RootDir, ctx.Config.Stack)
+		if err != nil {
+			continue
+		}
+		layouts++
+		for _, tpl := range graph {
+			rel, err := filepath.Rel(ctx.RootDir, tpl.path)
+			if err != nil {
+				rel = tpl.path
+			}
+			for _, p := range analyticsPlaceholderMatches(ctx, tpl.content) {
+				report(Location{File: filepath.ToSlash(rel), Line: p.line, Message: p.message})
+			}
+		}
+	}
+	// The served page catches snippets injected by a CMS or a tag
+	// manager setting the repo doesn't show.
+	if ctx.PageHTML != "" {
+		for _, p := range analyticsPlaceholderMatches(ctx, ctx.PageHTML) {
+			if finding := "The homepage has a " + p.message; !seen[finding] {
+				seen[finding] = true
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	if len(findings) == 0 {
+		message := "No placeholder analytics IDs found"
+		if layouts == 0 && ctx.PageHTML == "" {
+			message = "No layout file found, skipping"
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  message,
+		}, nil
+	}
+
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  "Analytics snippet has a placeholder ID and will record nothing",
+		Suggestions: append([]string{
+			"Replace the placeholder with the ID from your analytics dashboard, or remove the snippet if the site doesn't use it",
+		}, limitFindings(findings, 8)...),
+		Locations: locations,
+	}, nil
+}
+
+// analyticsPlaceholder is one placeholder found in a template or page.
+type analyticsPlaceholder struct {
+	line    int
+	message string
+}
+
+// analyticsPlaceholderMatches finds placeholder IDs in content, leaving
+// out commented-out snippets. A data-domain naming the production host
+// is the real thing, whatever it looks like.
+func analyticsPlaceholderMatches(ctx Context, original string) []analyticsPlaceholder {
+	content := stripCodeComments(original)
+	var ownHosts []string
+	if host, err := extractDomain(ctx.Config.URLs.Production); err == nil && host != "" {
+		ownHosts = append(ownHosts, host)
+		if apex, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+			ownHosts = append(ownHosts, apex, "www."+apex)
+		}
+	}
+
+	var out []analyticsPlaceholder
+	for _, p := range analyticsPlaceholders {
+		for _, m := range p.pattern.FindAllStringSubmatchIndex(content, -1) {
+			id := ""
+			for i := 2; i+1 < len(m); i += 2 {
+				if m[i] >= 0 {
+					id = content[m[i]:m[i+1]]
+					break
+				}
+			}
+			if p.provider == "Plausible" && slices.ContainsFunc(ownHosts, func(h string) bool { return strings.EqualFold(h, id) }) {
+				continue
+			}
+			message := "placeholder analytics ID " + id
+			if p.provider != "" {
+				message = "placeholder " + p.provider + " ID " + id
+			}
+			// Stripping comments shifts lines, so find the match in the
+			// original to report where it is.
+			line := lineAt([]byte(content), m[0])
+			if at := strings.Index(original, content[m[0]:m[1]]); at >= 0 {
+				line = lineAt([]byte(original), at)
+			}
+			out = append(out, analyticsPlaceholder{line: line, message: message})
+		}
+	}
+	return out
+}
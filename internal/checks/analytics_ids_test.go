@@ -0,0 +1,56 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestAnalyticsIDsCheck(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"templates/base.html": "<html>\n<head>\n{% include \"partials/analytics.html\" %}\n" +
+			"<script defer data-domain=\"example.com\" src=\"https://plausible.io/js/script.js\"></script>\n" +
+			"<!-- <script>gtag('config', 'G-XXXXXXXXXX');</script> -->\n</head>",
+		"templates/partials/analytics.html": "<script>\n  gtag('config', 'GA_MEASUREMENT_ID');\n  posthog.init('phc_XXXXXXXXXX', {api_host: 'https://YOUR_DOMAIN'});\n</script>",
+	})
+	cfg := &config.PreflightConfig{Stack: "django"}
+	result, err := AnalyticsIDsCheck{}.Run(Context{RootDir: dir, Config: cfg, PageHTML: `<script async src="https://www.googletagmanager.com/gtag/js?id=G-XXXXXXX"></script>`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed {
+		t.Fatal("expected a warning")
+	}
+	joined := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{
+		"templates/base.html:4 - placeholder Plausible ID example.com",
+		"templates/partials/analytics.html:2 - placeholder Google ID GA_MEASUREMENT_ID",
+		"templates/partials/analytics.html:3 - placeholder PostHog ID phc_XXXXXXXXXX",
+		"templates/partials/analytics.html:3 - placeholder analytics ID YOUR_DOMAIN",
+		"The homepage has a placeholder Google ID G-XXXXXXX",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "G-XXXXXXXXXX") {
+		t.Errorf("commented-out snippet reported:\n%s", joined)
+	}
+	if len(result.Locations) != 4 {
+		t.Errorf("got %d locations, want 4", len(result.Locations))
+	}
+
+	// example.com is a real domain for the site that owns it.
+	cfg.URLs.Production = "https://www.example.com"
+	dir = writeFiles(t, map[string]string{
+		"templates/base.html": `<script defer data-domain="example.com" src="https://plausible.io/js/script.js"></script>`,
+	})
+	result, err = AnalyticsIDsCheck{}.Run(Context{RootDir: dir, Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed {
+		t.Errorf("expected pass, got %q %v", result.Message, result.Suggestions)
+	}
+}
@@ -0,0 +1,245 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// AppLinksCheck validates the .well-known files iOS universal links and
+// Android app links depend on: apple-app-site-association and
+// assetlinks.json must be served from production without a redirect, as
+// JSON, and list the declared apps. Neither OS says why a link opened in
+// the browser instead, so a broken file is hard to trace after launch.
+type AppLinksCheck struct{}
+
+func (c AppLinksCheck) ID() string {
+	return "app_links"
+}
+
+func (c AppLinksCheck) Title() string {
+	return "App links (.well-known)"
+}
+
+// maxAASASize is the largest apple-app-site-association Apple accepts.
+const maxAASASize = 128 * 1024
+
+// androidHandleAllURLs is the relation that verifies an Android app link.
+const androidHandleAllURLs = "delegate_permission/common.handle_all_urls"
+
+func (c AppLinksCheck) Run(ctx Context) (CheckResult, error) {
+	cfg := ctx.Config.Checks.AppLinks
+	if !cfg.Declared() || ctx.Config.URLs.Production == "" || ctx.Client == nil {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No apps declared, skipping",
+		}, nil
+	}
+
+	// Apple's CDN and Android's verifier both refuse redirects, so one
+	// is a failure rather than something to follow.
+	client := *ctx.Client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	base := strings.TrimSuffix(ctx.Config.URLs.Production, "/")
+
+	var problems, details []string
+	if cfg.IOS != nil && len(cfg.IOS.AppIDs) > 0 {
+		name := "apple-app-site-association"
+		body, problem := fetchWellKnown(ctx, &client, base+"/.well-known/"+name)
+		if problem == "" && len(body) > maxAASASize {
+			problem = fmt.Sprintf("is %d KB, over Apple's 128 KB limit", len(body)/1024)
+		}
+		if problem == "" {
+			found := aasaProblems(body, cfg.IOS.AppIDs)
+			if len(found) == 0 {
+				details = append(details, fmt.Sprintf("%s lists %s", name, strings.Join(cfg.IOS.AppIDs, ", ")))
+			}
+			problems = append(problems, found...)
+		} else {
+			problems = append(problems, name+" "+problem)
+		}
+	}
+	if cfg.Android != nil && len(cfg.Android.Packages) > 0 {
+		name := "assetlinks.json"
+		body, problem := fetchWellKnown(ctx, &client, base+"/.well-known/"+name)
+		if problem == "" {
+			found := assetLinksProblems(body, cfg.Android)
+			if len(found) == 0 {
+				details = append(details, fmt.Sprintf("%s lists %s", name, strings.Join(cfg.Android.Packages, ", ")))
+			}
+			problems = append(problems, found...)
+		} else {
+			problems = append(problems, name+" "+problem)
+		}
+	}
+
+	if len(problems) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "App association files are served and list the declared apps",
+			Details:  details,
+		}, nil
+	}
+	message := fmt.Sprintf("%d app association problems", len(problems))
+	if len(problems) == 1 {
+		message = problems[0]
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  message,
+		Suggestions: append([]string{
+			"Serve the files from /.well-known/ over HTTPS with a 200, Content-Type: application/json and no redirect",
+			"Test with Apple's swcutil / the AASA validator and Google's Statement List Generator and Tester",
+		}, limitFindings(problems, 8)...),
+	}, nil
+}
+
+// fetchWellKnown fetches url without following redirects. It returns the
+// body, or what stops the OS from reading it.
+func fetchWellKnown(ctx Context, client *http.Client, url string) ([]byte, string) {
+	resp, err := doGet(ctx.reqContext(), client, url)
+	if err != nil {
+		return nil, "couldn't be fetched: " + err.Error()
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		return nil, fmt.Sprintf("redirects to %s, which the OS won't follow", resp.Header.Get("Location"))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Sprintf("returns HTTP %d", resp.StatusCode)
+	}
+	if ct := strings.ToLower(resp.Header.Get("Content-Type")); !strings.Contains(ct, "application/json") {
+		if ct == "" {
+			ct = "no content type"
+		}
+		return nil, "is served as " + ct + ", not application/json"
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, netutil.MaxResponseBody))
+	if err != nil {
+		return nil, "couldn't be read: " + err.Error()
+	}
+	return body, ""
+}
+
+// aasaProblems validates an apple-app-site-association file and checks it
+// lists each of appIDs under applinks. Both the current format (appIDs and
+// components) and the older one (appID and paths) are accepted.
+func aasaProblems(body []byte, appIDs []string) []string {
+	const name = "apple-app-site-association"
+	var aasa struct {
+		AppLinks *struct {
+			Details []struct {
+				AppID      string            `json:"appID"`
+				AppIDs     []string          `json:"appIDs"`
+				Components []json.RawMessage `json:"components"`
+				Paths      []string          `json:"paths"`
+			} `json:"details"`
+		} `json:"applinks"`
+	}
+	if err := json.Unmarshal(body, &aasa); err != nil {
+		return []string{name + " isn't valid JSON: " + err.Error()}
+	}
+	if aasa.AppLinks == nil {
+		return []string{name + " has no applinks section"}
+	}
+	var problems []string
+	listed := map[string]bool{}
+	for i, d := range aasa.AppLinks.Details {
+		ids := d.AppIDs
+		if d.AppID != "" {
+			ids = append(ids, d.AppID)
+		}
+		if len(ids) == 0 {
+			problems = append(problems, fmt.Sprintf("%s applinks.details[%d] has no appIDs", name, i))
+		}
+		for _, id := range ids {
+			listed[id] = true
+			if !config.AppleAppIDPattern.MatchString(id) {
+				problems = append(problems, fmt.Sprintf("%s app ID %q isn't TEAMID.bundle.id", name, id))
+			}
+		}
+		if len(d.Components) == 0 && len(d.Paths) == 0 {
+			problems = append(problems, fmt.Sprintf("%s applinks.details[%d] has no components or paths", name, i))
+		}
+	}
+	for _, id := range appIDs {
+		if !listed[id] {
+			problems = append(problems, name+" doesn't list "+id)
+		}
+	}
+	return problems
+}
+
+// assetLinksProblems validates an assetlinks.json file and checks it
+// grants handle_all_urls to each declared package, signed with the
+// declared fingerprints.
+func assetLinksProblems(body []byte, android *config.AndroidAppLinksConfig) []string {
+	const name = "assetlinks.json"
+	var statements []struct {
+		Relation []string `json:"relation"`
+		Target   struct {
+			Namespace    string   `json:"namespace"`
+			PackageName  string   `json:"package_name"`
+			Fingerprints []string `json:"sha256_cert_fingerprints"`
+		} `json:"target"`
+	}
+	if err := json.Unmarshal(body, &statements); err != nil {
+		return []string{name + " isn't a valid JSON statement list: " + err.Error()}
+	}
+	var problems []string
+	fingerprints := map[string]map[string]bool{}
+	for _, s := range statements {
+		if s.Target.Namespace != "android_app" {
+			continue
+		}
+		handles := false
+		for _, r := range s.Relation {
+			handles = handles || r == androidHandleAllURLs
+		}
+		if !handles {
+			continue
+		}
+		pkg := s.Target.PackageName
+		if fingerprints[pkg] == nil {
+			fingerprints[pkg] = map[string]bool{}
+		}
+		if len(s.Target.Fingerprints) == 0 {
+			problems = append(problems, name+" lists "+pkg+" without sha256_cert_fingerprints")
+		}
+		for _, fp := range s.Target.Fingerprints {
+			fingerprints[pkg][strings.ToUpper(fp)] = true
+			if !config.CertFingerprintPattern.MatchString(strings.ToUpper(fp)) {
+				problems = append(problems, fmt.Sprintf("%s fingerprint %q for %s isn't a SHA-256 fingerprint", name, fp, pkg))
+			}
+		}
+	}
+	for _, pkg := range android.Packages {
+		listed, ok := fingerprints[pkg]
+		if !ok {
+			problems = append(problems, name+" doesn't grant "+androidHandleAllURLs+" to "+pkg)
+			continue
+		}
+		for _, fp := range android.Fingerprints {
+			if !listed[strings.ToUpper(fp)] {
+				problems = append(problems, fmt.Sprintf("%s doesn't list fingerprint %s… for %s", name, fp[:8], pkg))
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,89 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestAppLinksCheck(t *testing.T) {
+	const fingerprint = "14:6D:E9:83:C5:73:06:50:D8:EE:B9:95:2F:34:FC:64:16:A0:83:42:E6:1D:BE:A8:8A:04:96:B2:3F:CF:44:E5"
+	aasa := `{"applinks":{"details":[{"appIDs":["ABCDE12345.com.example.app"],"components":[{"/":"/orders/*"}]}]}}`
+	assetlinks := `[{"relation":["delegate_permission/common.handle_all_urls"],"target":{"namespace":"android_app","package_name":"com.example.app","sha256_cert_fingerprints":["` + fingerprint + `"]}}]`
+	contentType := "application/json"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/apple-app-site-association":
+			w.Header().Set("Content-Type", contentType)
+			w.Write([]byte(aasa))
+		case "/.well-known/assetlinks.json":
+			if assetlinks == "" {
+				http.Redirect(w, r, "/assetlinks.json", http.StatusMovedPermanently)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(assetlinks))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	cfg.Checks.AppLinks = &config.AppLinksConfig{
+		IOS:     &config.IOSAppLinksConfig{AppIDs: []string{"ABCDE12345.com.example.app"}},
+		Android: &config.AndroidAppLinksConfig{Packages: []string{"com.example.app"}, Fingerprints: []string{strings.ToLower(fingerprint)}},
+	}
+	run := func() CheckResult {
+		t.Helper()
+		result, err := AppLinksCheck{}.Run(Context{Config: cfg, Client: srv.Client()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	if result := run(); !result.Passed {
+		t.Errorf("expected pass, got %q %v", result.Message, result.Suggestions)
+	}
+
+	// The older appID/paths format still counts.
+	aasa = `{"applinks":{"apps":[],"details":[{"appID":"ABCDE12345.com.example.app","paths":["/orders/*"]}]}}`
+	if result := run(); !result.Passed {
+		t.Errorf("expected legacy format to pass, got %q %v", result.Message, result.Suggestions)
+	}
+
+	aasa = `{"applinks":{"details":[{"appIDs":["com.example.other"],"components":[]}]}}`
+	assetlinks = ""
+	result := run()
+	if result.Passed {
+		t.Fatal("expected a warning")
+	}
+	joined := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{
+		`app ID "com.example.other" isn't TEAMID.bundle.id`,
+		"applinks.details[0] has no components or paths",
+		"apple-app-site-association doesn't list ABCDE12345.com.example.app",
+		"assetlinks.json redirects to /assetlinks.json",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+
+	contentType = "text/html"
+	assetlinks = `[{"relation":["delegate_permission/common.get_login_creds"],"target":{"namespace":"android_app","package_name":"com.example.app","sha256_cert_fingerprints":["` + fingerprint + `"]}}]`
+	joined = strings.Join(run().Suggestions, "\n")
+	for _, want := range []string{
+		"apple-app-site-association is served as text/html, not application/json",
+		"assetlinks.json doesn't grant delegate_permission/common.handle_all_urls to com.example.app",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+}
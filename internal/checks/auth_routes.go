@@ -0,0 +1,105 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/routes"
+)
+
+// AuthRoutesCheck requests the paths listed under checks.authRoutes as an
+// anonymous visitor and expects each to turn them away: a redirect to a
+// login page, or a 401 or 403. An admin panel that answers 200 to anyone
+// is the failure this catches.
+type AuthRoutesCheck struct{}
+
+func (c AuthRoutesCheck) ID() string {
+	return "auth_routes"
+}
+
+func (c AuthRoutesCheck) Title() string {
+	return "Protected routes"
+}
+
+func (c AuthRoutesCheck) Run(ctx Context) (CheckResult, error) {
+	baseURL := routesBaseURL(ctx)
+	paths := ctx.Config.Checks.AuthRoutes
+	if baseURL == "" || len(paths) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Skipped (no protected routes or no URL to request them on)",
+		}, nil
+	}
+
+	var probes []*routeProbe
+	seen := map[string]bool{}
+	for _, path := range paths {
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		if !seen[path] {
+			seen[path] = true
+			probes = append(probes, &routeProbe{route: routes.Route{Path: path, Method: "GET", Auth: true}})
+		}
+	}
+	probeRoutes(ctx, baseURL, probes)
+
+	var errs, warns, details []string
+	for _, p := range probes {
+		path := p.route.Path
+		switch {
+		case p.err != nil:
+			warns = append(warns, fmt.Sprintf("%s - request failed: %v", path, p.err))
+		case p.status >= 200 && p.status < 300 && p.loginForm:
+			details = append(details, fmt.Sprintf("%s - %d with a sign-in form", path, p.status))
+		case p.status >= 200 && p.status < 300:
+			errs = append(errs, fmt.Sprintf("%s - returned %d to an anonymous visitor", path, p.status))
+		case p.status >= 300 && p.status < 400 && isLoginLocation(p.location):
+			details = append(details, fmt.Sprintf("%s - redirects to %s", path, p.location))
+		case p.status >= 300 && p.status < 400:
+			warns = append(warns, fmt.Sprintf("%s - redirects to %s, which doesn't look like a login page", path, valueOr(p.location, "(no Location)")))
+		case p.status == 401 || p.status == 403:
+			details = append(details, fmt.Sprintf("%s - %d", path, p.status))
+		case p.status == 404:
+			details = append(details, fmt.Sprintf("%s - 404; remove it from checks.authRoutes if the route is gone", path))
+		case p.status >= 500:
+			warns = append(warns, fmt.Sprintf("%s - returned %d", path, p.status))
+		default:
+			details = append(details, fmt.Sprintf("%s - %d", path, p.status))
+		}
+	}
+
+	if len(errs) == 0 && len(warns) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("%d protected route(s) turn away anonymous visitors", len(probes)),
+			Details:  details,
+		}, nil
+	}
+
+	severity := SeverityWarn
+	message := fmt.Sprintf("%d of %d protected route(s) couldn't be confirmed", len(warns), len(probes))
+	if len(errs) > 0 {
+		severity = SeverityError
+		message = fmt.Sprintf("%d of %d protected route(s) are public", len(errs), len(probes))
+	}
+	suggestions := limitFindings(append(errs, warns...), 10)
+	if len(errs) > 0 {
+		suggestions = append(suggestions, "Put these routes behind your auth middleware so signed-out visitors are redirected to login or get a 401/403")
+	}
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    severity,
+		Passed:      false,
+		Message:     message,
+		Suggestions: suggestions,
+		Details:     details,
+	}, nil
+}
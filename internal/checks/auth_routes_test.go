@@ -0,0 +1,76 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestAuthRoutesCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dashboard":
+			http.Redirect(w, r, "/login?next=/dashboard", http.StatusFound)
+		case "/api/admin":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/settings":
+			w.Write([]byte(`<form><input name="email"><input type="password" name="password"></form>`))
+		case "/admin":
+			w.Write([]byte("<h1>Users</h1>")) // left public
+		case "/billing":
+			http.Redirect(w, r, "/", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	cfg.Checks.AuthRoutes = []string{"/dashboard", "api/admin", "/settings", "/admin", "/billing", "/dashboard"}
+	result, err := AuthRoutesCheck{}.Run(Context{Config: cfg, Client: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed || result.Severity != SeverityError {
+		t.Fatalf("got passed=%v severity=%v, want an error", result.Passed, result.Severity)
+	}
+	if want := "1 of 5 protected route(s) are public"; result.Message != want {
+		t.Errorf("message = %q, want %q", result.Message, want)
+	}
+	joined := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{
+		"/admin - returned 200 to an anonymous visitor",
+		"/billing - redirects to /, which doesn't look like a login page",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+	for _, unwanted := range []string{"/dashboard", "/api/admin", "/settings"} {
+		if strings.Contains(joined, unwanted) {
+			t.Errorf("suggestions should not mention %q:\n%s", unwanted, joined)
+		}
+	}
+}
+
+func TestAuthRoutesCheckPasses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Staging = srv.URL
+	cfg.Checks.AuthRoutes = []string{"/admin"}
+	result, err := AuthRoutesCheck{}.Run(Context{Config: cfg, Client: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed {
+		t.Fatalf("expected pass, got %q %v", result.Message, result.Suggestions)
+	}
+}
@@ -0,0 +1,274 @@
+package checks
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// maxBIMILogoBytes is the largest logo the BIMI group recommends; some
+// mailbox providers refuse bigger ones.
+const maxBIMILogoBytes = 32 * 1024
+
+type BIMICheck struct{}
+
+func (c BIMICheck) ID() string {
+	return "bimi"
+}
+
+func (c BIMICheck) Title() string {
+	return "BIMI brand logo"
+}
+
+// Run checks what mailbox providers need before they show the brand logo
+// next to mail from the production domain: a BIMI record at
+// default._bimi, an SVG Tiny PS logo it points to, and a DMARC policy that
+// quarantines or rejects.
+func (c BIMICheck) Run(ctx Context) (CheckResult, error) {
+	if ctx.Config.URLs.Production == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Skipped (no production URL)",
+		}, nil
+	}
+	domain, err := extractDomain(ctx.Config.URLs.Production)
+	if err != nil {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Skipped (could not parse domain)",
+		}, nil
+	}
+
+	records, err := lookupTXTPrefix(ctx, "default._bimi."+domain, "v=bimi1")
+	if err != nil {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  fmt.Sprintf("DNS lookup error for default._bimi.%s: %v", domain, err),
+			Suggestions: []string{
+				"Check your network connection and DNS resolver",
+			},
+		}, nil
+	}
+	if len(records) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  "No BIMI record at default._bimi." + domain,
+			Suggestions: []string{
+				"Publish a TXT record at default._bimi." + domain + ": v=BIMI1; l=https://" + domain + "/logo.svg; a=https://" + domain + "/vmc.pem",
+			},
+		}, nil
+	}
+
+	var problems []string
+	if len(records) > 1 {
+		problems = append(problems, fmt.Sprintf("default._bimi.%s has %d BIMI records; providers ignore all of them", domain, len(records)))
+	}
+	tags := parseTagList(records[0])
+	logo := tags["l"]
+	switch {
+	case logo == "":
+		problems = append(problems, "The BIMI record has no l= logo URL")
+	case !strings.HasPrefix(strings.ToLower(logo), "https://"):
+		problems = append(problems, fmt.Sprintf("The logo URL %s must use HTTPS", logo))
+	default:
+		problems = append(problems, checkBIMILogo(ctx, logo)...)
+	}
+
+	dmarc, err := lookupTXTPrefix(ctx, "_dmarc."+domain, "v=dmarc1")
+	switch {
+	case err != nil:
+		problems = append(problems, fmt.Sprintf("DMARC lookup failed: %v", err))
+	case len(dmarc) == 0:
+		problems = append(problems, "No DMARC record at _dmarc."+domain+"; BIMI needs p=quarantine or p=reject")
+	default:
+		problems = append(problems, bimiDMARCProblems(parseTagList(dmarc[0]))...)
+	}
+
+	if len(problems) > 0 {
+		return CheckResult{
+			ID:          c.ID(),
+			Title:       c.Title(),
+			Severity:    SeverityWarn,
+			Passed:      false,
+			Message:     fmt.Sprintf("BIMI logo won't show for %s: %d problem(s)", domain, len(problems)),
+			Suggestions: limitFindings(problems, 8),
+		}, nil
+	}
+
+	result := CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   true,
+		Message:  "BIMI record, logo and DMARC enforcement in place for " + domain,
+	}
+	if tags["a"] == "" {
+		result.Details = []string{"No a= certificate (VMC or CMC): Gmail and Apple Mail only show logos backed by one"}
+	}
+	return result, nil
+}
+
+// parseTagList parses the "k=v; k=v" tag lists BIMI and DMARC records
+// are written in. Tag names are case-insensitive.
+func parseTagList(record string) map[string]string {
+	tags := map[string]string{}
+	for _, part := range strings.Split(record, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		tags[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+	return tags
+}
+
+// bimiDMARCProblems reports where a DMARC record falls short of BIMI's
+// requirement: an enforced policy applied to all mail, subdomains
+// included.
+func bimiDMARCProblems(tags map[string]string) []string {
+	var problems []string
+	policy := strings.ToLower(tags["p"])
+	if policy != "quarantine" && policy != "reject" {
+		problems = append(problems, fmt.Sprintf("DMARC p=%s; BIMI needs p=quarantine or p=reject", valueOr(policy, "(none set)")))
+	}
+	if sp := strings.ToLower(tags["sp"]); sp == "none" {
+		problems = append(problems, "DMARC sp=none; BIMI needs subdomains enforced too")
+	}
+	if pct := tags["pct"]; pct != "" && pct != "100" {
+		problems = append(problems, fmt.Sprintf("DMARC pct=%s; BIMI needs the policy applied to all mail (pct=100)", pct))
+	}
+	return problems
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// checkBIMILogo fetches the logo and checks it against the SVG Tiny
+// Portable/Secure profile BIMI requires.
+func checkBIMILogo(ctx Context, logoURL string) []string {
+	if _, err := url.Parse(logoURL); err != nil {
+		return []string{fmt.Sprintf("The logo URL %q isn't a valid URL", logoURL)}
+	}
+	resp, err := doGet(ctx.reqContext(), ctx.Client, logoURL)
+	if err != nil {
+		return []string{fmt.Sprintf("The logo at %s is unreachable: %v", logoURL, err)}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return []string{fmt.Sprintf("The logo at %s returns HTTP %d", logoURL, resp.StatusCode)}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, netutil.MaxResponseBody))
+	if err != nil {
+		return []string{fmt.Sprintf("The logo at %s couldn't be read: %v", logoURL, err)}
+	}
+
+	var problems []string
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(strings.ToLower(ct), "image/svg+xml") {
+		problems = append(problems, fmt.Sprintf("The logo is served as %s, not image/svg+xml", ct))
+	}
+	if len(body) > maxBIMILogoBytes {
+		problems = append(problems, fmt.Sprintf("The logo is %d KB; keep it under 32 KB", len(body)/1024))
+	}
+	return append(problems, svgTinyPSProblems(body)...)
+}
+
+// svgTinyPSProblems reports what keeps data from being an SVG Tiny PS
+// document: the root must declare version="1.2" and baseProfile="tiny-ps"
+// and carry a <title>, and the file may not run scripts, animate, or load
+// anything from outside itself.
+func svgTinyPSProblems(data []byte) []string {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	var problems []string
+	add := func(problem string) {
+		if !slices.Contains(problems, problem) {
+			problems = append(problems, problem)
+		}
+	}
+	root := true
+	hasTitle := false
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return append(problems, "The logo isn't well-formed XML: "+err.Error())
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			depth++
+			name := strings.ToLower(el.Name.Local)
+			if root {
+				root = false
+				if name != "svg" {
+					return []string{fmt.Sprintf("The logo's root element is <%s>, not <svg>", el.Name.Local)}
+				}
+				attrs := map[string]string{}
+				for _, a := range el.Attr {
+					attrs[strings.ToLower(a.Name.Local)] = a.Value
+				}
+				if attrs["baseprofile"] != "tiny-ps" {
+					problems = append(problems, `The logo's <svg> needs baseProfile="tiny-ps"`)
+				}
+				if attrs["version"] != "1.2" {
+					problems = append(problems, `The logo's <svg> needs version="1.2"`)
+				}
+				if attrs["x"] != "" || attrs["y"] != "" {
+					problems = append(problems, "The logo's <svg> may not set x or y")
+				}
+				continue
+			}
+			switch name {
+			case "title":
+				if depth == 2 {
+					hasTitle = true
+				}
+			case "script", "foreignobject":
+				add(fmt.Sprintf("The logo contains <%s>, which SVG Tiny PS forbids", el.Name.Local))
+			case "animate", "animatetransform", "animatemotion", "animatecolor", "set":
+				add("The logo is animated; SVG Tiny PS logos must be static")
+			case "image":
+				add("The logo embeds an <image>; draw it in vector shapes instead")
+			}
+			for _, a := range el.Attr {
+				if strings.EqualFold(a.Name.Local, "href") && !strings.HasPrefix(a.Value, "#") {
+					add(fmt.Sprintf("The logo references %s; SVG Tiny PS logos can't load external resources", truncate(a.Value, 60)))
+				}
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+	if root {
+		return []string{"The logo is empty"}
+	}
+	if !hasTitle {
+		add("The logo's <svg> needs a <title> naming the brand")
+	}
+	return problems
+}
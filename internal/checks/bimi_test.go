@@ -0,0 +1,82 @@
+package checks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+const tinyPSLogo = `<svg xmlns="http://www.w3.org/2000/svg" version="1.2" baseProfile="tiny-ps" viewBox="0 0 100 100"><title>Example</title><circle cx="50" cy="50" r="40"/></svg>`
+
+func TestSVGTinyPSProblems(t *testing.T) {
+	if problems := svgTinyPSProblems([]byte(tinyPSLogo)); len(problems) != 0 {
+		t.Errorf("valid logo: %q", problems)
+	}
+	got := svgTinyPSProblems([]byte(`<svg xmlns="http://www.w3.org/2000/svg" x="0"><script>alert(1)</script><animate/><animate/><image href="https://cdn.example.com/logo.png"/></svg>`))
+	want := []string{
+		`The logo's <svg> needs baseProfile="tiny-ps"`,
+		`The logo's <svg> needs version="1.2"`,
+		"The logo's <svg> may not set x or y",
+		"The logo contains <script>, which SVG Tiny PS forbids",
+		"The logo is animated; SVG Tiny PS logos must be static",
+		"The logo embeds an <image>; draw it in vector shapes instead",
+		"The logo references https://cdn.example.com/logo.png; SVG Tiny PS logos can't load external resources",
+		"The logo's <svg> needs a <title> naming the brand",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problems = %q\nwant %q", got, want)
+	}
+}
+
+func TestBIMICheck(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/logo.svg" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/svg+xml")
+		fmt.Fprint(w, tinyPSLogo)
+	}))
+	defer srv.Close()
+	defer netutil.ConfigureResolver("", "")
+
+	run := func(t *testing.T, bimi, dmarc string) CheckResult {
+		t.Helper()
+		netutil.SetResolver(fakeDNS{txt: map[string][]string{
+			"default._bimi.example.com": {bimi},
+			"_dmarc.example.com":        {dmarc},
+		}})
+		cfg := &config.PreflightConfig{}
+		cfg.URLs.Production = "https://example.com"
+		result, err := BIMICheck{}.Run(Context{Config: cfg, Client: srv.Client()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	result := run(t, "v=BIMI1; l="+srv.URL+"/logo.svg", "v=DMARC1; p=reject")
+	if !result.Passed || len(result.Details) != 1 {
+		t.Errorf("got passed=%v message=%q details=%q", result.Passed, result.Message, result.Details)
+	}
+
+	result = run(t, "v=BIMI1; l="+srv.URL+"/missing.svg", "v=DMARC1; p=quarantine; pct=50")
+	want := []string{
+		"The logo at " + srv.URL + "/missing.svg returns HTTP 404",
+		"DMARC pct=50; BIMI needs the policy applied to all mail (pct=100)",
+	}
+	if result.Passed || !reflect.DeepEqual(result.Suggestions, want) {
+		t.Errorf("got passed=%v suggestions=%q", result.Passed, result.Suggestions)
+	}
+
+	result = run(t, "v=spf1 -all", "v=DMARC1; p=none")
+	if result.Passed || !strings.HasPrefix(result.Message, "No BIMI record") {
+		t.Errorf("got passed=%v message=%q", result.Passed, result.Message)
+	}
+}
@@ -0,0 +1,161 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/routes"
+)
+
+// BotProtectionCheck finds the forms anyone can submit (signup, contact,
+// comments, newsletter) and looks for a bot-protection integration:
+// reCAPTCHA, hCaptcha, Turnstile, or a honeypot. Spam bots find a new
+// signup form within days of launch, and cleaning fake accounts out
+// afterwards is far more work than a challenge widget.
+type BotProtectionCheck struct{}
+
+func (c BotProtectionCheck) ID() string {
+	return "bot_protection"
+}
+
+func (c BotProtectionCheck) Title() string {
+	return "Bot protection on forms"
+}
+
+var (
+	// rePublicFormPath matches a path segment naming a form the public
+	// submits.
+	rePublicFormPath = regexp.MustCompile(`(?i)(?:^|/)(?:sign-?up|register|registration|contact(?:-us)?|comments?|newsletter|subscribe|feedback|wait-?list|enquiry|inquiry|guestbook)(?:/|\.|$)`)
+	// reFormAction captures a <form>'s action.
+	reFormAction = regexp.MustCompile(`(?is)<form\b[^>]*?\baction\s*=\s*["']([^"']+)["']`)
+)
+
+// botProtections are the integrations recognized, by the markup, SDK
+// names and env vars each leaves in a project.
+var botProtections = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"reCAPTCHA", regexp.MustCompile(`(?i)google\.com/recaptcha|g-recaptcha|grecaptcha|react-google-recaptcha|recaptcha_tags|verify_recaptcha|RECAPTCHA_(?:SITE|SECRET)|recaptcha-v3|NoCaptcha`)},
+	{"hCaptcha", regexp.MustCompile(`(?i)hcaptcha`)},
+	{"Turnstile", regexp.MustCompile(`(?i)challenges\.cloudflare\.com/turnstile|cf-turnstile|react-turnstile|turnstile[_-]?(?:site[_-]?key|secret)`)},
+	{"Friendly Captcha", regexp.MustCompile(`(?i)friendly-?captcha|frc-captcha`)},
+	{"Arcjet", regexp.MustCompile(`@arcjet/|arcjet\(`)},
+	{"a honeypot", regexp.MustCompile(`invisible_captcha|laravel-honeypot|<x-honeypot|@honeypot|django-honeypot`)},
+}
+
+// botProtectionManifests are dependency and env files that name the
+// integration when the code doesn't do so recognizably.
+var botProtectionManifests = map[string]bool{
+	"package.json": true, "Gemfile": true, "composer.json": true, "requirements.txt": true,
+	"pyproject.toml": true, "go.mod": true, ".env.example": true, ".env.sample": true,
+}
+
+func (c BotProtectionCheck) Run(ctx Context) (CheckResult, error) {
+	forms := map[string]string{}
+	var locations []Location
+	for _, r := range routes.Extract(ctx.RootDir) {
+		if r.Auth || !rePublicFormPath.MatchString(r.Path) {
+			continue
+		}
+		if _, ok := forms[r.Path]; !ok {
+			forms[r.Path] = r.Source
+		}
+	}
+
+	found := map[string]string{}
+	files := ctx.files()
+	for _, f := range files.Files {
+		if f.Ignored || f.Size > 500*1024 || f.inDir(templateSkipDirs) || isTestSource(f.Path) {
+			continue
+		}
+		template := hasExtension(f.Name, templateExts)
+		if !template && !appSourceExts[f.Ext] && !botProtectionManifests[f.Name] {
+			continue
+		}
+		if !botProtectionManifests[f.Name] && (strings.HasPrefix(f.Path, ".") || strings.Contains(f.Path, "/.")) {
+			continue
+		}
+		content, err := readFile(files.Abs(f))
+		if err != nil {
+			continue
+		}
+		for _, p := range botProtections {
+			if _, ok := found[p.name]; !ok && p.re.Match(content) {
+				found[p.name] = f.Path
+			}
+		}
+		if !template {
+			continue
+		}
+		for _, m := range reFormAction.FindAllSubmatchIndex(content, -1) {
+			action := string(content[m[2]:m[3]])
+			if strings.Contains(action, "://") || !rePublicFormPath.MatchString(strings.SplitN(action, "?", 2)[0]) {
+				continue
+			}
+			loc := Location{File: f.Path, Line: lineAt(content, m[0])}
+			if _, ok := forms[action]; !ok {
+				forms[action] = fmt.Sprintf("%s:%d", loc.File, loc.Line)
+				loc.Message = "form posts to " + action
+				locations = append(locations, loc)
+			}
+		}
+	}
+
+	if len(forms) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No public signup, contact or comment forms found",
+		}, nil
+	}
+
+	paths := make([]string, 0, len(forms))
+	for p := range forms {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	var listed []string
+	for _, p := range paths {
+		listed = append(listed, fmt.Sprintf("%s (%s)", p, forms[p]))
+	}
+
+	if len(found) > 0 {
+		var names, details []string
+		for _, p := range botProtections {
+			if file, ok := found[p.name]; ok {
+				names = append(names, p.name)
+				details = append(details, p.name+" found in "+file)
+			}
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("%s found for %d public forms", strings.Join(names, ", "), len(forms)),
+			Details:  append(details, limitFindings(listed, 5)...),
+		}, nil
+	}
+
+	message := fmt.Sprintf("%d public forms and no bot protection", len(forms))
+	if len(forms) == 1 {
+		message = "Public form " + paths[0] + " has no bot protection"
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  message,
+		Suggestions: append([]string{
+			"Add Cloudflare Turnstile, hCaptcha or reCAPTCHA to these forms and verify the token on the server",
+			"If spam is stopped elsewhere (a WAF challenge, a honeypot field), ignore bot_protection",
+		}, limitFindings(listed, 8)...),
+		Locations: locations,
+	}, nil
+}
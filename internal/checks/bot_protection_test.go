@@ -0,0 +1,88 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestBotProtectionCheck(t *testing.T) {
+	unprotected := map[string]string{
+		"app/page.tsx":                    "export default function Home() {}",
+		"app/pricing/page.tsx":            "",
+		"app/api/signup/route.ts":         "export async function POST(req) { await db.user.create() }",
+		"app/dashboard/comments/page.tsx": "",
+		"middleware.ts":                   "import { auth } from './auth'\nexport const config = { matcher: ['/dashboard/:path*'] }",
+		"site/footer.html":                "<footer>\n<form method=\"post\" action=\"/newsletter/subscribe\"><input name=email></form>\n<form action=\"https://formspree.io/f/contact\"></form>\n</footer>",
+		"site/search.html":                `<form action="/search"></form>`,
+	}
+	protected := map[string]string{"package.json": `{"dependencies": {"@marsidev/react-turnstile": "^1.0.0"}}`}
+	for name, body := range unprotected {
+		protected[name] = body
+	}
+
+	cases := []struct {
+		name      string
+		files     map[string]string
+		passed    bool
+		message   string
+		want      []string
+		unwanted  []string
+		locations []string
+	}{
+		{
+			// Forms behind the auth middleware, posting to a hosted form
+			// service or sending a GET aren't public write endpoints.
+			name:      "public forms without protection",
+			files:     unprotected,
+			want:      []string{"/api/signup (app/api/signup/route.ts)", "/newsletter/subscribe (site/footer.html:2)"},
+			unwanted:  []string{"/dashboard/comments", "formspree", "/search", "/pricing"},
+			locations: []string{"site/footer.html:2 - form posts to /newsletter/subscribe"},
+		},
+		{
+			name:    "captcha dependency",
+			files:   protected,
+			passed:  true,
+			message: "Turnstile found for 2 public forms",
+		},
+		{
+			name:   "no forms",
+			files:  map[string]string{"app/page.tsx": ""},
+			passed: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := BotProtectionCheck{}.Run(Context{RootDir: writeFiles(t, tc.files), Config: &config.PreflightConfig{}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tc.passed {
+				t.Fatalf("passed = %v, want %v: %q %v", result.Passed, tc.passed, result.Message, result.Suggestions)
+			}
+			if tc.message != "" && result.Message != tc.message {
+				t.Errorf("message = %q, want %q", result.Message, tc.message)
+			}
+			joined := strings.Join(result.Suggestions, "\n")
+			for _, want := range tc.want {
+				if !strings.Contains(joined, want) {
+					t.Errorf("suggestions missing %q:\n%s", want, joined)
+				}
+			}
+			for _, unwanted := range tc.unwanted {
+				if strings.Contains(joined, unwanted) {
+					t.Errorf("%s reported as a public form:\n%s", unwanted, joined)
+				}
+			}
+			var locations []string
+			for _, loc := range result.Locations {
+				locations = append(locations, loc.String())
+			}
+			if strings.Join(locations, "\n") != strings.Join(tc.locations, "\n") {
+				t.Errorf("locations = %v, want %v", locations, tc.locations)
+			}
+		})
+	}
+}
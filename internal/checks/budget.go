@@ -0,0 +1,63 @@
+package checks
+
+import "time"
+
+// A check's time budget is how long it should take on a typical project,
+// network round trips included. scan --assert-budgets warns when a check
+// runs over, so a slow check (or a slow site) shows up before it drags
+// every CI run. The budgets are generous: going over means something is
+// off, not that the check was a little slow.
+
+// defaultTimeBudget covers IDs without an entry in checkTimeBudgets: file
+// checks that read a handful of files, network checks that make a request
+// or two, and declared-service checks.
+const defaultTimeBudget = 10 * time.Second
+
+// checkTimeBudgets holds the checks that walk the whole tree, run an
+// external tool or make many requests. Add an entry when adding a check
+// that does too.
+var checkTimeBudgets = map[string]time.Duration{
+	"vulnerability":      2 * time.Minute, // one audit per package manager, each up to 60s
+	"routes":             time.Minute,
+	"drift":              30 * time.Second,
+	"deployed_version":   20 * time.Second,
+	"mirrors":            30 * time.Second,
+	"hreflang":           30 * time.Second,
+	"ops_readiness":      20 * time.Second,
+	"rollback":           20 * time.Second,
+	"stale_flags":        20 * time.Second,
+	"bot_protection":     20 * time.Second,
+	"rate_limit":         20 * time.Second,
+	"smoke":              30 * time.Second,
+	"secrets":            30 * time.Second,
+	"osv":                30 * time.Second,
+	"supply_chain":       30 * time.Second,
+	"dependencies":       30 * time.Second,
+	"outdated_deps":      time.Minute,      // a registry lookup per direct dependency
+	"email_auth":         30 * time.Second, // a few dozen DNS lookups
+	"ssl":                30 * time.Second, // three handshakes, two pinned to TLS 1.0/1.1
+	"debug_statements":   20 * time.Second,
+	"debug_mode":         10 * time.Second,
+	"localhost_urls":     20 * time.Second,
+	"redirect_ssrf":      20 * time.Second,
+	"sql_injection":      20 * time.Second,
+	"legacy_artifacts":   20 * time.Second,
+	"analytics_ids":      10 * time.Second,
+	"image_optimization": 20 * time.Second,
+	"image_alt":          20 * time.Second,
+	"sitemap":            20 * time.Second,
+	"sitemap_coverage":   20 * time.Second,
+	"ipv6":               20 * time.Second,
+	"cloudflare_zone":    20 * time.Second,
+	"app_links":          20 * time.Second,
+	"refund_policy":      20 * time.Second,
+	"ogTwitter":          20 * time.Second, // samples a page per sitemap section
+}
+
+// TimeBudgetFor returns the time budget of a check or service ID.
+func TimeBudgetFor(id string) time.Duration {
+	if d, ok := checkTimeBudgets[id]; ok {
+		return d
+	}
+	return defaultTimeBudget
+}
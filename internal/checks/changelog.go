@@ -0,0 +1,260 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// ChangelogCheck verifies the version being launched has release notes:
+// an entry in the changelog, or a GitHub release with a description. The
+// version comes from package.json or the latest git tag.
+type ChangelogCheck struct{}
+
+func (c ChangelogCheck) ID() string {
+	return "changelog"
+}
+
+func (c ChangelogCheck) Title() string {
+	return "Release notes"
+}
+
+// changelogFiles are the usual names of a changelog, in the order they're
+// looked for.
+var changelogFiles = []string{
+	"CHANGELOG.md", "CHANGELOG", "CHANGELOG.txt", "CHANGES.md", "CHANGES", "HISTORY.md",
+	"RELEASES.md", "RELEASE_NOTES.md", "NEWS.md", "docs/CHANGELOG.md", "docs/changelog.md",
+}
+
+// reVersionNumber pulls the version number out of a tag like v1.4.0,
+// app@1.4.0 or release-1.4.0.
+var reVersionNumber = regexp.MustCompile(`\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z.-]+)?`)
+
+// reChangelogVersionLine matches a line starting with a version, which
+// begins a section in changelogs that don't use markdown headings.
+var reChangelogVersionLine = regexp.MustCompile(`(?i)^\s*(?:\[?v?\d+\.\d+|(?:version|release)\s+v?\d)`)
+
+// githubAPIBase is the GitHub REST API root; tests point it elsewhere.
+var githubAPIBase = "https://api.github.com"
+
+func (c ChangelogCheck) Run(ctx Context) (CheckResult, error) {
+	cfg := ctx.Config.Checks.Changelog
+	if cfg == nil {
+		cfg = &config.ChangelogConfig{}
+	}
+
+	version, tag, source := launchVersion(ctx, cfg.VersionFrom)
+	if version == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  "No version to check release notes for",
+			Suggestions: []string{
+				"Set a version in package.json or tag the release in git (git tag v1.0.0)",
+				"Point checks.changelog.versionFrom at the source you use",
+			},
+		}, nil
+	}
+
+	file := cfg.File
+	if file == "" {
+		for _, name := range changelogFiles {
+			if _, err := os.Stat(filepath.Join(ctx.RootDir, name)); err == nil {
+				file = name
+				break
+			}
+		}
+	}
+	var problem string
+	if file != "" {
+		content, err := readFile(filepath.Join(ctx.RootDir, filepath.FromSlash(file)))
+		switch {
+		case os.IsNotExist(err):
+			problem = file + " not found"
+		case err != nil:
+			problem = file + " could not be read"
+		default:
+			found, empty := changelogEntry(string(content), version)
+			if found && !empty {
+				return CheckResult{
+					ID:       c.ID(),
+					Title:    c.Title(),
+					Severity: SeverityInfo,
+					Passed:   true,
+					Message:  fmt.Sprintf("%s has an entry for %s (from %s)", file, version, source),
+				}, nil
+			}
+			problem = fmt.Sprintf("%s has no entry for %s (from %s)", file, version, source)
+			if found {
+				problem = fmt.Sprintf("%s's entry for %s is empty", file, version)
+			}
+		}
+	}
+
+	var details []string
+	if cfg.GitHubRepo != "" && ctx.Client != nil {
+		body, found, err := githubRelease(ctx, cfg.GitHubRepo, version, tag)
+		switch {
+		case err != nil:
+			details = append(details, "GitHub release lookup failed: "+err.Error())
+		case found && strings.TrimSpace(body) != "":
+			return CheckResult{
+				ID:       c.ID(),
+				Title:    c.Title(),
+				Severity: SeverityInfo,
+				Passed:   true,
+				Message:  fmt.Sprintf("GitHub release for %s has notes (from %s)", version, source),
+			}, nil
+		case found:
+			details = append(details, "The GitHub release for "+version+" has no description")
+		default:
+			details = append(details, "No GitHub release for "+version+" in "+cfg.GitHubRepo)
+		}
+	}
+
+	message := problem
+	if file == "" {
+		message = fmt.Sprintf("No changelog with an entry for %s (from %s)", version, source)
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  message,
+		Suggestions: []string{
+			fmt.Sprintf("Add a \"## [%s]\" section to the changelog saying what changed for users", version),
+			"Or publish a GitHub release with notes and set checks.changelog.githubRepo",
+		},
+		Details: details,
+	}, nil
+}
+
+// launchVersion reads the version being launched from source
+// ("package.json" or "git"), or from package.json then git when source is
+// empty. It returns the version number, the git tag it came from (if
+// any) and a description of the source.
+func launchVersion(ctx Context, source string) (version, tag, from string) {
+	if source == "" || source == "package.json" {
+		if data, err := readFile(filepath.Join(ctx.RootDir, "package.json")); err == nil {
+			var pkg struct {
+				Version string `json:"version"`
+			}
+			if json.Unmarshal(data, &pkg) == nil && pkg.Version != "" && pkg.Version != "0.0.0" {
+				return strings.TrimPrefix(pkg.Version, "v"), "", "package.json"
+			}
+		}
+	}
+	if source == "" || source == "git" {
+		if out, err := ctx.git("describe", "--tags", "--abbrev=0"); err == nil {
+			tag = strings.TrimSpace(out)
+			if v := reVersionNumber.FindString(tag); v != "" {
+				return v, tag, "git tag " + tag
+			}
+		}
+	}
+	return "", "", ""
+}
+
+// changelogEntry reports whether changelog has a heading for version, and
+// whether the section under it is empty. Keep a Changelog ("## [1.2.0] -
+// 2026-01-05"), plain ("## v1.2.0", "Version 1.2.0") and setext/rst
+// headings with an underline all count.
+func changelogEntry(changelog, version string) (found, empty bool) {
+	heading := regexp.MustCompile(`(?i)^\s*(?:#{1,6}\s*)?(?:\[\s*)?(?:(?:version|release)\s+)?v?` + regexp.QuoteMeta(version) + `(?:\s*\])?(?:$|[\s(:,–—-])`)
+	lines := strings.Split(strings.ReplaceAll(changelog, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if !heading.MatchString(line) {
+			continue
+		}
+		level := markdownHeadingLevel(line)
+		for _, next := range lines[i+1:] {
+			// Subheadings (### Added) belong to the entry; the next
+			// heading at its level or above, or the next version, ends it.
+			if n := markdownHeadingLevel(next); n > 0 {
+				if level > 0 && n > level {
+					continue
+				}
+				break
+			}
+			if reChangelogVersionLine.MatchString(next) {
+				break
+			}
+			if strings.Trim(strings.TrimSpace(next), "=-~") != "" {
+				return true, false
+			}
+		}
+		return true, true
+	}
+	return false, false
+}
+
+// githubRelease looks up the release for version in repo, trying the tag
+// the version came from and then v<version> and <version>. It returns the
+// release's description and whether one exists. GITHUB_TOKEN, when set,
+// authenticates the request, which private repos need.
+func githubRelease(ctx Context, repo, version, tag string) (body string, found bool, err error) {
+	tags := []string{"v" + version, version}
+	if tag != "" && tag != tags[0] && tag != tags[1] {
+		tags = append([]string{tag}, tags...)
+	}
+	for _, t := range tags {
+		body, found, err = fetchGitHubRelease(ctx.reqContext(), ctx.Client, repo, t)
+		if err != nil || found {
+			return body, found, err
+		}
+	}
+	return "", false, nil
+}
+
+func fetchGitHubRelease(ctx context.Context, client *http.Client, repo, tag string) (string, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", githubAPIBase+"/repos/"+repo+"/releases/tags/"+tag, nil)
+	if err != nil {
+		return "", false, err
+	}
+	req.Header.Set("User-Agent", "Preflight/1.0")
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", false, nil
+	case resp.StatusCode != http.StatusOK:
+		return "", false, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	var release struct {
+		Body string `json:"body"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, netutil.MaxResponseBody)).Decode(&release); err != nil {
+		return "", false, err
+	}
+	return release.Body, true, nil
+}
+
+// markdownHeadingLevel returns the level of an ATX heading (## Title), or
+// 0 when line isn't one.
+func markdownHeadingLevel(line string) int {
+	trimmed := strings.TrimLeft(line, " ")
+	n := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+	if n == 0 || n > 6 || (len(trimmed) > n && trimmed[n] != ' ' && trimmed[n] != '\t') {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,122 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestChangelogEntry(t *testing.T) {
+	changelog := `# Changelog
+
+## [Unreleased]
+
+## [1.4.0] - 2026-10-01
+### Added
+- Team billing
+
+## v1.3.0
+
+## 1.2.0
+Fixed the signup form.
+
+Version 1.1.0
+-------------
+First public release.
+`
+	for _, tc := range []struct {
+		version      string
+		found, empty bool
+	}{
+		{"1.4.0", true, false},
+		{"1.3.0", true, true},
+		{"1.2.0", true, false},
+		{"1.1.0", true, false},
+		{"1.4", false, false},
+		{"2.0.0", false, false},
+	} {
+		found, empty := changelogEntry(changelog, tc.version)
+		if found != tc.found || empty != tc.empty {
+			t.Errorf("changelogEntry(%s) = %v, %v; want %v, %v", tc.version, found, empty, tc.found, tc.empty)
+		}
+	}
+}
+
+func TestChangelogCheck(t *testing.T) {
+	run := func(t *testing.T, dir string, cfg *config.ChangelogConfig, client *http.Client) CheckResult {
+		t.Helper()
+		pc := &config.PreflightConfig{}
+		pc.Checks.Changelog = cfg
+		result, err := ChangelogCheck{}.Run(Context{RootDir: dir, Config: pc, Client: client})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	t.Run("package.json version", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"package.json": `{"name": "web", "version": "1.4.0"}`,
+			"CHANGELOG.md": "## [1.4.0]\n- Team billing\n",
+		})
+		result := run(t, dir, &config.ChangelogConfig{Enabled: true}, nil)
+		if !result.Passed || result.Message != "CHANGELOG.md has an entry for 1.4.0 (from package.json)" {
+			t.Errorf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+	})
+
+	t.Run("git tag", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"package.json": `{"name": "web", "version": "1.4.0"}`,
+			"CHANGES.md":   "## [1.4.0]\n- Team billing\n",
+		})
+		initGitRepo(t, dir)
+		gitCommit(t, dir, ".")
+		if out, err := exec.Command("git", "-C", dir, "tag", "web@1.5.0").CombinedOutput(); err != nil {
+			t.Fatalf("git tag: %v\n%s", err, out)
+		}
+		result := run(t, dir, &config.ChangelogConfig{Enabled: true, VersionFrom: "git"}, nil)
+		if result.Passed || result.Message != "CHANGES.md has no entry for 1.5.0 (from git tag web@1.5.0)" {
+			t.Errorf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+	})
+
+	t.Run("github release", func(t *testing.T) {
+		var paths []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			if r.URL.Path != "/repos/acme/web/releases/tags/v2.0.0" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(`{"tag_name": "v2.0.0", "body": "Team billing is here."}`))
+		}))
+		defer srv.Close()
+		defer func(base string) { githubAPIBase = base }(githubAPIBase)
+		githubAPIBase = srv.URL
+
+		dir := writeFiles(t, map[string]string{"package.json": `{"version": "2.0.0"}`})
+		result := run(t, dir, &config.ChangelogConfig{Enabled: true, GitHubRepo: "acme/web"}, srv.Client())
+		if !result.Passed || !strings.Contains(result.Message, "GitHub release for 2.0.0 has notes") {
+			t.Errorf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+
+		dir = writeFiles(t, map[string]string{"package.json": `{"version": "2.1.0"}`})
+		result = run(t, dir, &config.ChangelogConfig{Enabled: true, GitHubRepo: "acme/web"}, srv.Client())
+		if result.Passed || result.Message != "No changelog with an entry for 2.1.0 (from package.json)" ||
+			!strings.Contains(strings.Join(result.Details, "\n"), "No GitHub release for 2.1.0 in acme/web") {
+			t.Errorf("got passed=%v message=%q details=%q (requests %q)", result.Passed, result.Message, result.Details, paths)
+		}
+	})
+
+	t.Run("no version", func(t *testing.T) {
+		result := run(t, writeFiles(t, map[string]string{"CHANGELOG.md": "## 1.0.0\n"}), &config.ChangelogConfig{Enabled: true, VersionFrom: "package.json"}, nil)
+		if result.Passed || result.Message != "No version to check release notes for" {
+			t.Errorf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+	})
+}
@@ -1,12 +1,15 @@
 package checks
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -26,6 +29,89 @@ func relPath(base, target string) string {
 	return rel
 }
 
+// FindPath returns the first of paths that exists under rootDir. A path
+// may be a glob, for stacks such as Phoenix that name a directory after
+// the app (lib/*_web); the match found is returned in its place.
+func FindPath(rootDir string, paths []string) (string, bool) {
+	fsys := os.DirFS(rootDir)
+	for _, p := range paths {
+		if !strings.ContainsAny(p, "*?[") {
+			if _, err := os.Stat(filepath.Join(rootDir, p)); err == nil {
+				return p, true
+			}
+			continue
+		}
+		if matches, _ := fs.Glob(fsys, p); len(matches) > 0 {
+			return filepath.FromSlash(matches[0]), true
+		}
+	}
+	return "", false
+}
+
+var (
+	// templateExts are the extensions of files that render markup.
+	templateExts = []string{
+		".html", ".htm", ".erb", ".haml", ".slim", ".blade.php", ".php", ".twig",
+		".jsx", ".tsx", ".vue", ".svelte", ".astro",
+		".njk", ".liquid", ".hbs", ".handlebars", ".mustache", ".ejs", ".pug",
+		".tmpl", ".gohtml",
+	}
+
+	// templateSkipDirs are dependency and build-output directories that
+	// template scans leave out, along with any hidden directory.
+	templateSkipDirs = map[string]bool{
+		"node_modules": true, "vendor": true, "dist": true, "build": true,
+		"out": true, "_site": true, "coverage": true, "storage": true, "tmp": true, "cpresources": true,
+	}
+)
+
+// walkTemplateFiles calls fn with the project-relative path (forward
+// slashes) and content of every file under rootDir whose name ends in one
+// of exts, skipping templateSkipDirs and hidden directories.
+func walkTemplateFiles(rootDir string, exts []string, fn func(rel string, content []byte)) {
+	_ = walkDir(rootDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			recordUnreadable(p, err)
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if p != rootDir && (templateSkipDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !hasExtension(d.Name(), exts) {
+			return nil
+		}
+		content, err := readFile(p)
+		if err != nil {
+			return nil
+		}
+		fn(filepath.ToSlash(relPath(rootDir, p)), content)
+		return nil
+	})
+}
+
+// hasExtension reports whether name ends in one of exts, ignoring case.
+// Suffix matching lets compound extensions like ".blade.php" work.
+func hasExtension(name string, exts []string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range exts {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// lineAt returns the 1-based line number of byte offset off in content.
+func lineAt(content []byte, off int) int {
+	return bytes.Count(content[:off], []byte("\n")) + 1
+}
+
 type Severity string
 
 const (
@@ -42,6 +128,35 @@ type CheckResult struct {
 	Message     string   `json:"message"`
 	Suggestions []string `json:"suggestions,omitempty"`
 	Details     []string `json:"details,omitempty"` // Verbose output details
+	// Locations pins findings to lines in the project, for reporters that
+	// can point at them (GitHub annotations). Optional: most checks have
+	// nothing line-shaped to report.
+	Locations []Location `json:"locations,omitempty"`
+	// Deliverability is email_auth's sub-report on the production
+	// domain's mail records.
+	Deliverability *Deliverability `json:"deliverability,omitempty"`
+	// Profile is set by --profile-checks.
+	Profile *Usage `json:"profile,omitempty"`
+}
+
+// Location is one finding's place in the project.
+type Location struct {
+	File    string `json:"file"` // relative to the project root, forward slashes
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// String renders the location in the "path:line - message" form the
+// human output uses for findings.
+func (l Location) String() string {
+	s := l.File
+	if l.Line > 0 {
+		s = fmt.Sprintf("%s:%d", s, l.Line)
+	}
+	if l.Message != "" {
+		s += " - " + l.Message
+	}
+	return s
 }
 
 type Context struct {
@@ -69,6 +184,22 @@ type Context struct {
 	// preferred). Convenience for env-agnostic checks like favicon
 	// detection that don't care which environment the markup came from.
 	PageHTML string
+	// Files is the project's file index, built once at scan start. Nil
+	// outside a scan; use files(), which builds one on demand.
+	Files *FileIndex
+	// Untrusted is set for a project from someone else's machine (a
+	// serve --api upload). Checks don't run git or the project's own
+	// tools on it, since its files can name commands for them to run.
+	Untrusted bool
+}
+
+// files returns the shared file index, walking the project now when the
+// caller (usually a test) didn't build one.
+func (c Context) files() *FileIndex {
+	if c.Files == nil {
+		return BuildFileIndex(c.RootDir)
+	}
+	return c.Files
 }
 
 // reqContext returns ctx.Ctx if set, otherwise context.Background(). Lets
@@ -91,6 +222,16 @@ type Check interface {
 var Registry = []Check{
 	EnvParityCheck{},
 	HealthCheck{},
+	RoutesCheck{},
+	AuthRoutesCheck{},
+	SmokeCheck{},
+	DriftCheck{},
+	MirrorsCheck{},
+	OpsReadinessCheck{},
+	ChangelogCheck{},
+	DeployedVersionCheck{},
+	RollbackCheck{},
+	StaleFlagsCheck{},
 	StripeWebhookCheck{},
 	SentryCheck{},
 	PlausibleCheck{},
@@ -100,27 +241,59 @@ var Registry = []Check{
 	SidekiqCheck{},
 	SEOMetadataCheck{},
 	OGTwitterCheck{},
+	TwitterCardCheck{},
+	HreflangCheck{},
 	SecurityHeadersCheck{},
 	SSLCheck{},
 	SecretScanCheck{},
+	BotProtectionCheck{},
+	RateLimitCheck{},
+	RedirectSSRFCheck{},
+	SQLInjectionCheck{},
 	VulnerabilityCheck{},
+	OSVCheck{},
+	SupplyChainCheck{},
+	DockerfileCheck{},
+	ComposeCheck{},
+	IaCCheck{},
+	DependenciesCheck{},
+	OutdatedDepsCheck{},
+	ResilienceCheck{},
 	FaviconCheck{},
 	RobotsTxtCheck{},
 	SitemapCheck{},
+	SitemapCoverageCheck{},
 	LLMsTxtCheck{},
 	AdsTxtCheck{},
 	LicenseCheck{},
+	RequiredFilesCheck{},
 	ErrorPagesCheck{},
 	CanonicalURLCheck{},
 	ViewportCheck{},
 	LangAttributeCheck{},
 	DebugStatementsCheck{},
+	DebugModeCheck{},
+	LocalhostURLsCheck{},
+	LegacyArtifactsCheck{},
+	AnalyticsIDsCheck{},
 	StructuredDataCheck{},
+	SearchConsoleCheck{},
 	ImageOptimizationCheck{},
+	ImageAltCheck{},
+	FontLoadingCheck{},
 	EmailAuthCheck{},
+	BIMICheck{},
+	IPv6Check{},
+	CloudflareZoneCheck{},
+	AppLinksCheck{},
 	HumansTxtCheck{},
 	WWWRedirectCheck{},
 	LegalPagesCheck{},
+	CookieInventoryCheck{},
+	RegulatedGatingCheck{},
+	A11yStatementCheck{},
+	RefundPolicyCheck{},
+	RequiredServicesCheck{},
 	IndexNowCheck{},
 	// Cookie Consent checks
 	CookieConsentJSCheck,
@@ -192,6 +365,11 @@ var Registry = []Check{
 	HotjarCheck,
 	AmplitudeCheck,
 	SegmentCheck,
+	// Feature Flags
+	LaunchDarklyCheck,
+	FlagsmithCheck,
+	UnleashCheck,
+	PostHogFlagsCheck,
 	// Error Tracking (extended)
 	BugsnagCheck,
 	RollbarCheck,
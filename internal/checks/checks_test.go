@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/stacks"
 )
 
 func TestIsLocalURL(t *testing.T) {
@@ -299,3 +300,82 @@ func TestRunPerEnv(t *testing.T) {
 		}
 	})
 }
+
+func TestSortByPriority(t *testing.T) {
+	in := []Check{RequiredServicesCheck{}, LLMsTxtCheck{}, SitemapCheck{}, SecretScanCheck{}, HumansTxtCheck{}}
+	var got []string
+	for _, c := range SortByPriority(in) {
+		got = append(got, c.ID())
+	}
+	// Blockers first, the unranked keep their order, required_services last.
+	want := []string{"secrets", "sitemap", "llmsTxt", "humansTxt", "required_services"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if in[0].ID() != "required_services" {
+		t.Error("SortByPriority reordered its input")
+	}
+}
+
+func TestFindPathGlob(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"lib/shop_web/controllers/error_html/404.html.heex": "<h1>Not found</h1>",
+		"app/root.tsx": "export default function App() {}",
+	})
+	paths404, _ := getErrorPagePaths("phoenix")
+	got, ok := FindPath(dir, paths404)
+	if !ok || filepath.ToSlash(got) != "lib/shop_web/controllers/error_html/404.html.heex" {
+		t.Errorf("FindPath(phoenix 404) = %q, %v", got, ok)
+	}
+	if got, ok := FindPath(dir, []string{"app/root.jsx", "app/root.tsx"}); !ok || got != "app/root.tsx" {
+		t.Errorf("FindPath(remix root) = %q, %v", got, ok)
+	}
+	if _, ok := FindPath(dir, []string{"lib/*_web/templates/error/500.html.eex"}); ok {
+		t.Error("FindPath matched a missing glob")
+	}
+}
+
+func TestMultipleStacks(t *testing.T) {
+	// A Rails API whose pages are served by a React frontend.
+	dir := writeFiles(t, map[string]string{
+		"Gemfile":                "source 'https://rubygems.org'",
+		"src/index.html":         "<html lang=\"en\"></html>",
+		"src/pages/NotFound.tsx": "export default function NotFound() {}",
+	})
+	stacks := []string{"rails", "react"}
+	if got := getLayoutFile(dir, stacks, ""); got != "src/index.html" {
+		t.Errorf("getLayoutFile(rails, react) = %q, want the React layout", got)
+	}
+
+	ctx := Context{RootDir: dir, Config: &config.PreflightConfig{Stack: "rails", Stacks: stacks}}
+	result, err := ErrorPagesCheck{}.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed {
+		t.Errorf("error_pages = %q, want the React 404 found", result.Message)
+	}
+}
+
+func TestProjectStackPacks(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".preflight/stacks/rails.yml":         "name: rails\nlayouts:\n  - app/views/layouts/site.html.erb\nsitemaps:\n  - app/views/pages/sitemap.xml.builder\n",
+		"app/views/layouts/site.html.erb":     "<html lang=\"en\"></html>",
+		"app/views/pages/sitemap.xml.builder": "xml.urlset",
+	})
+	packs, err := stacks.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ConfigureStacks(packs)
+	defer ConfigureStacks(nil)
+
+	if got := getLayoutFile(dir, []string{"rails"}, ""); got != "app/views/layouts/site.html.erb" {
+		t.Errorf("getLayoutFile = %q, want the pack's layout", got)
+	}
+	cfg := &config.PreflightConfig{Stack: "rails"}
+	result, err := SitemapCheck{}.Run(Context{RootDir: dir, Config: cfg})
+	if err != nil || result.Message != "sitemap.xml generated via app/views/pages/sitemap.xml.builder" {
+		t.Errorf("sitemap = %q, %v", result.Message, err)
+	}
+}
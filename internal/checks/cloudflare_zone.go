@@ -0,0 +1,299 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// CloudflareZoneCheck reads the production zone's settings through the
+// Cloudflare API: the site's DNS records are proxied, SSL is Full
+// (strict), a WAF ruleset is deployed, and development mode is off.
+// Development mode bypasses the cache, and it's easy to leave on after
+// the last fix before launch.
+type CloudflareZoneCheck struct{}
+
+func (c CloudflareZoneCheck) ID() string {
+	return "cloudflare_zone"
+}
+
+func (c CloudflareZoneCheck) Title() string {
+	return "Cloudflare zone settings"
+}
+
+// cloudflareAPIBase is the Cloudflare API root; tests point it elsewhere.
+var cloudflareAPIBase = "https://api.cloudflare.com/client/v4"
+
+// defaultCloudflareTokenEnv holds the API token unless tokenEnv says
+// otherwise.
+const defaultCloudflareTokenEnv = "CLOUDFLARE_API_TOKEN"
+
+// cloudflareSSLModes describes each SSL/TLS encryption mode short of
+// Full (strict).
+var cloudflareSSLModes = map[string]string{
+	"off":      "SSL is off, so visitors get plain HTTP",
+	"flexible": "SSL mode is Flexible, so Cloudflare talks to the origin over plain HTTP",
+	"full":     "SSL mode is Full, so the origin certificate isn't validated",
+}
+
+func (c CloudflareZoneCheck) Run(ctx Context) (CheckResult, error) {
+	tokenEnv := defaultCloudflareTokenEnv
+	zoneID := ""
+	if cfg := ctx.Config.Checks.CloudflareZone; cfg != nil {
+		if cfg.TokenEnv != "" {
+			tokenEnv = cfg.TokenEnv
+		}
+		zoneID = cfg.ZoneID
+	}
+	token := os.Getenv(tokenEnv)
+	if token == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  tokenEnv + " isn't set, so the Cloudflare zone can't be read",
+			Suggestions: []string{
+				"Create an API token with Zone:Read, DNS:Read and Zone WAF:Read for the zone and export it as " + tokenEnv,
+			},
+		}, nil
+	}
+	host, err := extractDomain(ctx.Config.URLs.Production)
+	if err != nil || host == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No production URL configured, skipping",
+		}, nil
+	}
+	api := cloudflareAPI{ctx: ctx.reqContext(), client: ctx.Client, token: token}
+
+	if zoneID == "" {
+		apex, err := publicsuffix.EffectiveTLDPlusOne(host)
+		if err != nil {
+			apex = host
+		}
+		var zones []struct {
+			ID string `json:"id"`
+		}
+		if _, err := api.get("/zones?name="+url.QueryEscape(apex), &zones); err != nil {
+			return cloudflareUnreadable(c, "Couldn't look up the Cloudflare zone: "+err.Error()), nil
+		}
+		if len(zones) == 0 {
+			return CheckResult{
+				ID:       c.ID(),
+				Title:    c.Title(),
+				Severity: SeverityWarn,
+				Passed:   false,
+				Message:  "No Cloudflare zone for " + apex + " is visible to the token",
+				Suggestions: []string{
+					"Add the zone to the token's resources, or set checks.cloudflareZone.zoneId",
+				},
+			}, nil
+		}
+		zoneID = zones[0].ID
+	}
+	zone := "/zones/" + url.PathEscape(zoneID)
+
+	var problems, details, unread []string
+	severity := SeverityWarn
+
+	var records []struct {
+		Type    string `json:"type"`
+		Name    string `json:"name"`
+		Proxied bool   `json:"proxied"`
+	}
+	if _, err := api.get(zone+"/dns_records?name="+url.QueryEscape(host), &records); err != nil {
+		unread = append(unread, "DNS records: "+err.Error())
+	} else {
+		found := false
+		for _, r := range records {
+			if r.Type != "A" && r.Type != "AAAA" && r.Type != "CNAME" {
+				continue
+			}
+			found = true
+			if !r.Proxied {
+				problems = append(problems, fmt.Sprintf("%s %s record is DNS only, not proxied", r.Name, r.Type))
+			}
+		}
+		if !found {
+			problems = append(problems, "no A, AAAA or CNAME record for "+host+" in the zone")
+		} else if len(problems) == 0 {
+			details = append(details, host+" is proxied")
+		}
+	}
+
+	var ssl struct {
+		Value string `json:"value"`
+	}
+	if _, err := api.get(zone+"/settings/ssl", &ssl); err != nil {
+		unread = append(unread, "SSL mode: "+err.Error())
+	} else if problem, ok := cloudflareSSLModes[ssl.Value]; ok {
+		problems = append(problems, problem)
+	} else {
+		details = append(details, "SSL mode is "+ssl.Value)
+	}
+
+	var devMode struct {
+		Value         string `json:"value"`
+		TimeRemaining int    `json:"time_remaining"`
+	}
+	if _, err := api.get(zone+"/settings/development_mode", &devMode); err != nil {
+		unread = append(unread, "development mode: "+err.Error())
+	} else if devMode.Value == "on" {
+		severity = SeverityError
+		problem := "development mode is on, bypassing the cache"
+		if devMode.TimeRemaining > 0 {
+			problem += fmt.Sprintf(" (%d minutes left)", (devMode.TimeRemaining+59)/60)
+		}
+		problems = append(problems, problem)
+	}
+
+	if waf, err := cloudflareWAF(api, zone); err != nil {
+		unread = append(unread, "WAF: "+err.Error())
+	} else if waf == "" {
+		problems = append(problems, "no WAF managed ruleset is deployed")
+	} else {
+		details = append(details, waf)
+	}
+
+	for _, u := range unread {
+		details = append(details, "Couldn't read "+u)
+	}
+	if len(problems) == 0 {
+		if len(unread) > 0 {
+			result := cloudflareUnreadable(c, fmt.Sprintf("Couldn't read %d Cloudflare zone settings", len(unread)))
+			result.Details = details
+			return result, nil
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Proxied, Full (strict), WAF on, development mode off",
+			Details:  details,
+		}, nil
+	}
+	message := fmt.Sprintf("%d Cloudflare zone problems", len(problems))
+	if len(problems) == 1 {
+		message = "Cloudflare: " + problems[0]
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: severity,
+		Passed:   false,
+		Message:  message,
+		Suggestions: append([]string{
+			"In the Cloudflare dashboard: proxy the record (orange cloud), set SSL/TLS to Full (strict), deploy the Cloudflare Managed Ruleset and turn off development mode",
+		}, problems...),
+		Details: details,
+	}, nil
+}
+
+// cloudflareWAF describes the WAF protection on zone, or returns "" when
+// there is none. It reads the managed ruleset phase and falls back to the
+// legacy WAF setting older zones still have.
+func cloudflareWAF(api cloudflareAPI, zone string) (string, error) {
+	var entrypoint struct {
+		Rules []struct {
+			Action  string `json:"action"`
+			Enabled *bool  `json:"enabled"`
+		} `json:"rules"`
+	}
+	found, err := api.get(zone+"/rulesets/phases/http_request_firewall_managed/entrypoint", &entrypoint)
+	if err != nil {
+		return "", err
+	}
+	if found {
+		n := 0
+		for _, r := range entrypoint.Rules {
+			if r.Action == "execute" && (r.Enabled == nil || *r.Enabled) {
+				n++
+			}
+		}
+		if n > 0 {
+			return fmt.Sprintf("%d managed WAF rulesets deployed", n), nil
+		}
+	}
+	var legacy struct {
+		Value string `json:"value"`
+	}
+	if ok, err := api.get(zone+"/settings/waf", &legacy); err == nil && ok && legacy.Value == "on" {
+		return "Legacy WAF is on", nil
+	}
+	return "", nil
+}
+
+// cloudflareUnreadable is the result when the API can't be read.
+func cloudflareUnreadable(c CloudflareZoneCheck, message string) CheckResult {
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  message,
+		Suggestions: []string{
+			"Check the token has Zone:Read, DNS:Read and Zone WAF:Read on the zone",
+		},
+	}
+}
+
+// cloudflareAPI makes authenticated GETs against the Cloudflare API.
+type cloudflareAPI struct {
+	ctx    context.Context
+	client *http.Client
+	token  string
+}
+
+// get fetches path and decodes the envelope's result into out. It reports
+// false, without an error, when the API answers 404.
+func (a cloudflareAPI) get(path string, out any) (bool, error) {
+	req, err := http.NewRequestWithContext(a.ctx, "GET", cloudflareAPIBase+path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("User-Agent", "Preflight/1.0")
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	var envelope struct {
+		Success bool `json:"success"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, netutil.MaxResponseBody)).Decode(&envelope); err != nil {
+		return false, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if !envelope.Success {
+		if len(envelope.Errors) > 0 {
+			return false, errors.New(strings.TrimSuffix(envelope.Errors[0].Message, "."))
+		}
+		return false, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(envelope.Result, out); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,89 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestCloudflareZoneCheck(t *testing.T) {
+	responses := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"success":false,"errors":[{"code":9109,"message":"Invalid access token."}]}`))
+			return
+		}
+		result, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success":false,"errors":[{"code":10000,"message":"not found"}]}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"errors":[],"result":` + result + `}`))
+	}))
+	defer srv.Close()
+	defer func(base string) { cloudflareAPIBase = base }(cloudflareAPIBase)
+	cloudflareAPIBase = srv.URL
+
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = "https://www.example.co.uk"
+	cfg.Checks.CloudflareZone = &config.CloudflareZoneConfig{Enabled: true}
+	run := func() CheckResult {
+		t.Helper()
+		result, err := CloudflareZoneCheck{}.Run(Context{Config: cfg, Client: srv.Client()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	t.Setenv("CLOUDFLARE_API_TOKEN", "")
+	if result := run(); result.Passed || !strings.Contains(result.Message, "CLOUDFLARE_API_TOKEN isn't set") {
+		t.Errorf("expected missing token warning, got %v %q", result.Passed, result.Message)
+	}
+
+	t.Setenv("CLOUDFLARE_API_TOKEN", "test-token")
+	responses["/zones?name=example.co.uk"] = `[{"id":"z1","name":"example.co.uk"}]`
+	responses["/zones/z1/dns_records?name=www.example.co.uk"] = `[{"type":"CNAME","name":"www.example.co.uk","proxied":true},{"type":"TXT","name":"www.example.co.uk","proxied":false}]`
+	responses["/zones/z1/settings/ssl"] = `{"id":"ssl","value":"strict"}`
+	responses["/zones/z1/settings/development_mode"] = `{"id":"development_mode","value":"off","time_remaining":0}`
+	responses["/zones/z1/rulesets/phases/http_request_firewall_managed/entrypoint"] = `{"rules":[{"action":"execute","enabled":true}]}`
+	if result := run(); !result.Passed {
+		t.Errorf("expected pass, got %q %v", result.Message, result.Suggestions)
+	}
+
+	responses["/zones/z1/dns_records?name=www.example.co.uk"] = `[{"type":"A","name":"www.example.co.uk","proxied":false}]`
+	responses["/zones/z1/settings/ssl"] = `{"id":"ssl","value":"flexible"}`
+	responses["/zones/z1/settings/development_mode"] = `{"id":"development_mode","value":"on","time_remaining":7000}`
+	responses["/zones/z1/rulesets/phases/http_request_firewall_managed/entrypoint"] = `{"rules":[{"action":"execute","enabled":false}]}`
+	result := run()
+	if result.Passed || result.Severity != SeverityError {
+		t.Fatalf("expected an error, got %v %s", result.Passed, result.Severity)
+	}
+	joined := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{
+		"www.example.co.uk A record is DNS only",
+		"SSL mode is Flexible",
+		"development mode is on, bypassing the cache (117 minutes left)",
+		"no WAF managed ruleset is deployed",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+
+	// A configured zone ID skips the lookup; a bad token can't read it.
+	cfg.Checks.CloudflareZone = &config.CloudflareZoneConfig{Enabled: true, ZoneID: "z1", TokenEnv: "CF_TOKEN"}
+	t.Setenv("CF_TOKEN", "wrong")
+	result = run()
+	if result.Passed || result.Message != "Couldn't read 4 Cloudflare zone settings" {
+		t.Errorf("expected unreadable warning, got %v %q", result.Passed, result.Message)
+	}
+	if details := strings.Join(result.Details, "\n"); !strings.Contains(details, "Couldn't read SSL mode: Invalid access token") {
+		t.Errorf("details missing API error: %q", details)
+	}
+}
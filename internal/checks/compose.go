@@ -0,0 +1,352 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ComposeCheck reviews the Compose files a project deploys with for the
+// settings that are fine on a laptop and wrong on a server: source code
+// bind-mounted over the image, database ports published on every
+// interface, default passwords in environment blocks, and services with
+// no restart policy. When a directory has a production Compose file
+// (compose.prod.yml, docker-compose.production.yaml) only that one is
+// read; otherwise the base file is. Other variants (override, dev, local)
+// are never read.
+type ComposeCheck struct{}
+
+func (c ComposeCheck) ID() string {
+	return "compose"
+}
+
+func (c ComposeCheck) Title() string {
+	return "Docker Compose"
+}
+
+var (
+	// reComposeFile matches compose.yml, docker-compose.yaml and their
+	// variants, capturing the variant (prod, override, ...).
+	reComposeFile = regexp.MustCompile(`^(?:docker-)?compose(?:[.-]([\w.-]+))?\.ya?ml$`)
+	reComposeProd = regexp.MustCompile(`(?i)^(?:prod|production|live|deploy)$`)
+	// reComposeDB matches the images of datastores that shouldn't be
+	// reachable from outside the host.
+	reComposeDB = regexp.MustCompile(`(?i)(postgres|postgis|mysql|mariadb|mongo|redis|valkey|keydb|memcached|elasticsearch|opensearch|rabbitmq|cassandra|couchdb|clickhouse|influxdb|neo4j)`)
+	// reComposePasswordKey matches environment names that hold a
+	// password or secret.
+	reComposePasswordKey = regexp.MustCompile(`(?i)(passw(or)?d|_pass$|secret)`)
+	// reComposeDefault reads the fallback out of ${VAR:-default}.
+	reComposeDefault = regexp.MustCompile(`^\$\{\w+:?-([^}]*)\}$`)
+)
+
+// composeDefaultPasswords are the values images document in their
+// examples and tutorials copy, compared lower-cased.
+var composeDefaultPasswords = map[string]bool{
+	"": true, "password": true, "pass": true, "passwd": true, "postgres": true, "mysql": true,
+	"root": true, "toor": true, "admin": true, "secret": true, "example": true, "test": true,
+	"default": true, "guest": true, "user": true, "123456": true, "12345678": true,
+	"qwerty": true, "letmein": true, "rabbitmq": true, "mongo": true, "redis": true,
+}
+
+func (c ComposeCheck) Run(ctx Context) (CheckResult, error) {
+	files := ctx.files()
+	byDir := map[string][]FileEntry{}
+	prodDirs := map[string]bool{}
+	var dirs []string
+	for _, f := range files.Files {
+		m := reComposeFile.FindStringSubmatch(f.Name)
+		if m == nil || f.Ignored || f.inDir(vendorSkipDirs) {
+			continue
+		}
+		dir := path.Dir(f.Path)
+		variant := m[1]
+		switch {
+		case reComposeProd.MatchString(variant):
+			if !prodDirs[dir] {
+				// A production file replaces the base ones found so far.
+				prodDirs[dir] = true
+				byDir[dir] = nil
+			}
+		case variant != "" || prodDirs[dir]:
+			continue
+		}
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], f)
+	}
+	var composeFiles []FileEntry
+	for _, dir := range dirs {
+		composeFiles = append(composeFiles, byDir[dir]...)
+	}
+	if len(composeFiles) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No Compose file found",
+		}, nil
+	}
+
+	var findings []string
+	var locations []Location
+	kinds := map[string]bool{}
+	report := func(kind string, loc Location) {
+		kinds[kind] = true
+		findings = append(findings, loc.String())
+		locations = append(locations, loc)
+	}
+	services := 0
+	for _, f := range composeFiles {
+		content, err := readFile(files.Abs(f))
+		if err != nil {
+			continue
+		}
+		var doc yaml.Node
+		if yaml.Unmarshal(content, &doc) != nil || len(doc.Content) == 0 {
+			continue
+		}
+		svcs := yamlMapGet(doc.Content[0], "services")
+		if svcs == nil || svcs.Kind != yaml.MappingNode {
+			continue
+		}
+		dir := path.Dir(f.Path)
+		for i := 0; i+1 < len(svcs.Content); i += 2 {
+			name, svc := svcs.Content[i].Value, svcs.Content[i+1]
+			if svc.Kind != yaml.MappingNode {
+				continue
+			}
+			services++
+			image := ""
+			if n := yamlMapGet(svc, "image"); n != nil {
+				image = n.Value
+			}
+
+			if vols := yamlMapGet(svc, "volumes"); vols != nil {
+				for _, v := range vols.Content {
+					if src := composeBindSource(v); src != "" && composeSourceCode(files, path.Join(dir, src)) {
+						report("bind_mount", Location{File: f.Path, Line: v.Line, Message: fmt.Sprintf("%s bind-mounts %s over the image", name, src)})
+					}
+				}
+			}
+
+			if ports := yamlMapGet(svc, "ports"); ports != nil && reComposeDB.MatchString(image) {
+				for _, p := range ports.Content {
+					if composePublicPort(p) {
+						report("exposed_db", Location{File: f.Path, Line: p.Line, Message: fmt.Sprintf("%s (%s) publishes its port on every interface", name, image)})
+					}
+				}
+			}
+
+			for _, kv := range composeEnvironment(yamlMapGet(svc, "environment")) {
+				key, value := kv.key, kv.value
+				if m := reComposeDefault.FindStringSubmatch(value); m != nil {
+					value = m[1]
+				} else if strings.HasPrefix(value, "$") {
+					continue
+				}
+				switch {
+				case strings.EqualFold(key, "POSTGRES_HOST_AUTH_METHOD") && strings.EqualFold(value, "trust"),
+					strings.HasSuffix(strings.ToUpper(key), "ALLOW_EMPTY_PASSWORD") && composeTruthy(value):
+					report("default_password", Location{File: f.Path, Line: kv.line, Message: fmt.Sprintf("%s lets anyone in with %s=%s", name, key, value)})
+				case reComposePasswordKey.MatchString(key) && !strings.HasSuffix(strings.ToUpper(key), "_FILE") &&
+					(composeDefaultPasswords[strings.ToLower(value)] || envPlaceholder.MatchString(value)):
+					report("default_password", Location{File: f.Path, Line: kv.line, Message: fmt.Sprintf("%s sets %s to a default (%q)", name, key, value)})
+				}
+			}
+
+			// Services behind a profile only start when asked for, like
+			// a one-off migration.
+			if yamlMapGet(svc, "restart") == nil && yamlMapGet(yamlMapGet(svc, "deploy"), "restart_policy") == nil &&
+				yamlMapGet(svc, "profiles") == nil {
+				report("restart", Location{File: f.Path, Line: svcs.Content[i].Line, Message: name + " has no restart policy"})
+			}
+		}
+	}
+
+	if len(findings) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("%d Compose service(s) restart on failure, with no source mounts, public database ports or default passwords", services),
+		}, nil
+	}
+
+	severity := SeverityWarn
+	var found, suggestions []string
+	if kinds["default_password"] {
+		severity = SeverityError
+		found = append(found, "default passwords")
+		suggestions = append(suggestions, "Set passwords from the environment (POSTGRES_PASSWORD: ${POSTGRES_PASSWORD}) or Compose secrets, and generate a strong one for production")
+	}
+	if kinds["exposed_db"] {
+		found = append(found, "database ports published on every interface")
+		suggestions = append(suggestions, "Drop the ports: entry so only other services reach the database, or bind it to 127.0.0.1 (\"127.0.0.1:5432:5432\")")
+	}
+	if kinds["bind_mount"] {
+		found = append(found, "bind-mounted source code")
+		suggestions = append(suggestions, "Run the code built into the image; keep source bind mounts in compose.override.yml for development")
+	}
+	if kinds["restart"] {
+		found = append(found, "services without a restart policy")
+		suggestions = append(suggestions, "Add restart: unless-stopped (or deploy.restart_policy) so a crash or reboot doesn't leave the service down")
+	}
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    severity,
+		Passed:      false,
+		Message:     "Found " + strings.Join(found, ", "),
+		Suggestions: append(suggestions, limitFindings(findings, 8)...),
+		Locations:   locations,
+	}, nil
+}
+
+// yamlMapGet returns the value of key in a YAML mapping, following
+// aliases and << merge keys, or nil.
+func yamlMapGet(n *yaml.Node, key string) *yaml.Node {
+	for n != nil && n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		switch n.Content[i].Value {
+		case key:
+			v := n.Content[i+1]
+			for v.Kind == yaml.AliasNode {
+				v = v.Alias
+			}
+			return v
+		case "<<":
+			merges = append(merges, n.Content[i+1])
+		}
+	}
+	for _, m := range merges {
+		if m.Kind == yaml.SequenceNode {
+			for _, item := range m.Content {
+				if v := yamlMapGet(item, key); v != nil {
+					return v
+				}
+			}
+		} else if v := yamlMapGet(m, key); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// composeBindSource returns the relative host path a volume entry
+// bind-mounts, in the short (./src:/app) or long (type: bind) syntax, or
+// "" for named volumes and absolute paths.
+func composeBindSource(v *yaml.Node) string {
+	src := ""
+	switch v.Kind {
+	case yaml.ScalarNode:
+		src, _, _ = strings.Cut(v.Value, ":")
+	case yaml.MappingNode:
+		if t := yamlMapGet(v, "type"); t == nil || t.Value != "bind" {
+			return ""
+		}
+		if s := yamlMapGet(v, "source"); s != nil {
+			src = s.Value
+		}
+	}
+	if src == "." || src == ".." || strings.HasPrefix(src, "./") || strings.HasPrefix(src, "../") {
+		return src
+	}
+	return ""
+}
+
+// composeSourceCode reports whether the project-relative path p is the
+// project itself, or holds or is application source. Data directories
+// and config files mounted read-only are left alone.
+func composeSourceCode(files *FileIndex, p string) bool {
+	p = path.Clean(p)
+	if p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return true
+	}
+	for _, f := range files.Files {
+		if (f.Path == p || strings.HasPrefix(f.Path, p+"/")) && !f.Ignored && appSourceExts[f.Ext] {
+			return true
+		}
+	}
+	return false
+}
+
+// composePublicPort reports whether a ports entry publishes on the
+// host's public interfaces: anything not bound to a loopback address.
+func composePublicPort(p *yaml.Node) bool {
+	switch p.Kind {
+	case yaml.ScalarNode:
+		spec, _, _ := strings.Cut(p.Value, "/")
+		host := ""
+		if strings.HasPrefix(spec, "[") {
+			host, _, _ = strings.Cut(spec[1:], "]")
+		} else if parts := strings.Split(spec, ":"); len(parts) == 3 {
+			host = parts[0]
+		}
+		return !isLoopbackHost(host)
+	case yaml.MappingNode:
+		if ip := yamlMapGet(p, "host_ip"); ip != nil {
+			return !isLoopbackHost(ip.Value)
+		}
+		return true
+	}
+	return false
+}
+
+func isLoopbackHost(host string) bool {
+	return host == "localhost" || host == "::1" || strings.HasPrefix(host, "127.")
+}
+
+func composeTruthy(value string) bool {
+	switch strings.ToLower(value) {
+	case "yes", "true", "1", "y", "on":
+		return true
+	}
+	return false
+}
+
+// composeEnvVar is one entry of a service's environment block.
+type composeEnvVar struct {
+	key, value string
+	line       int
+}
+
+// composeEnvironment reads an environment block in either the mapping
+// or the KEY=value list form.
+func composeEnvironment(n *yaml.Node) []composeEnvVar {
+	if n == nil {
+		return nil
+	}
+	var vars []composeEnvVar
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			// KEY: with no value passes the host's through.
+			if n.Content[i+1].Tag == "!!null" {
+				continue
+			}
+			vars = append(vars, composeEnvVar{n.Content[i].Value, n.Content[i+1].Value, n.Content[i].Line})
+		}
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			// A bare KEY passes the host's through too.
+			key, value, ok := strings.Cut(item.Value, "=")
+			if ok {
+				vars = append(vars, composeEnvVar{key, value, item.Line})
+			}
+		}
+	}
+	return vars
+}
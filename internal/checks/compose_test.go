@@ -0,0 +1,166 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestComposeCheck(t *testing.T) {
+	cases := []struct {
+		name     string
+		files    map[string]string
+		passed   bool
+		message  string
+		want     []string
+		unwanted []string
+	}{
+		{
+			// Development settings belong in the override file, and a
+			// service behind a profile is a one-off tool.
+			name: "production-ready base file",
+			files: map[string]string{
+				"app.js": "require('http')\n",
+				"docker-compose.yml": `x-defaults: &defaults
+  restart: unless-stopped
+services:
+  web:
+    <<: *defaults
+    build: .
+    volumes:
+      - ./nginx.conf:/etc/nginx/nginx.conf:ro
+      - uploads:/app/uploads
+    ports:
+      - "3000:3000"
+  db:
+    image: postgres:16
+    restart: always
+    ports:
+      - "127.0.0.1:5432:5432"
+    environment:
+      POSTGRES_PASSWORD: ${POSTGRES_PASSWORD}
+      POSTGRES_USER:
+  migrate:
+    build: .
+    profiles: [tools]
+volumes:
+  uploads:
+`,
+				"docker-compose.override.yml": "services:\n  web:\n    volumes:\n      - .:/app\n",
+			},
+			passed: true,
+		},
+		{
+			// A prod variant replaces the base file in its directory.
+			name: "prod variant with every problem",
+			files: map[string]string{
+				"src/index.ts":       "export {}\n",
+				"docker-compose.yml": "services:\n  web:\n    image: node:22\n",
+				"compose.prod.yml": `services:
+  web:
+    build: .
+    volumes:
+      - ./src:/app/src
+      - type: bind
+        source: .
+        target: /app
+  db:
+    image: mysql:8
+    restart: always
+    ports:
+      - "3306:3306"
+    environment:
+      - MYSQL_ROOT_PASSWORD=${MYSQL_ROOT_PASSWORD:-root}
+      - MYSQL_PASSWORD_FILE=/run/secrets/db
+  cache:
+    image: redis:7
+    restart: always
+    ports:
+      - target: 6379
+        published: 6379
+    environment:
+      ALLOW_EMPTY_PASSWORD: "yes"
+`,
+			},
+			want: []string{
+				"compose.prod.yml:5 - web bind-mounts ./src over the image",
+				"compose.prod.yml:6 - web bind-mounts . over the image",
+				"compose.prod.yml:13 - db (mysql:8) publishes its port on every interface",
+				`compose.prod.yml:15 - db sets MYSQL_ROOT_PASSWORD to a default ("root")`,
+				"compose.prod.yml:21 - cache (redis:7) publishes its port on every interface",
+				"compose.prod.yml:24 - cache lets anyone in with ALLOW_EMPTY_PASSWORD=yes",
+				"compose.prod.yml:2 - web has no restart policy",
+			},
+			unwanted: []string{"docker-compose.yml", "PASSWORD_FILE"},
+		},
+		{
+			name:    "no Compose file",
+			files:   map[string]string{"README.md": "# app\n"},
+			passed:  true,
+			message: "No Compose file found",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ComposeCheck{}.Run(Context{RootDir: writeFiles(t, tc.files)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tc.passed {
+				t.Fatalf("passed = %v, want %v: %q %v", result.Passed, tc.passed, result.Message, result.Suggestions)
+			}
+			if !tc.passed && result.Severity != SeverityError {
+				t.Errorf("severity = %v, want error", result.Severity)
+			}
+			if tc.message != "" && result.Message != tc.message {
+				t.Errorf("message = %q, want %q", result.Message, tc.message)
+			}
+			var got []string
+			for _, loc := range result.Locations {
+				got = append(got, loc.String())
+			}
+			joined := strings.Join(got, "\n")
+			for _, want := range tc.want {
+				if !strings.Contains(joined, want) {
+					t.Errorf("locations missing %q:\n%s", want, joined)
+				}
+			}
+			for _, unwanted := range tc.unwanted {
+				if strings.Contains(joined, unwanted) {
+					t.Errorf("%s reported:\n%s", unwanted, joined)
+				}
+			}
+		})
+	}
+}
+
+func TestComposePublicPort(t *testing.T) {
+	tests := map[string]bool{
+		"5432":                true,
+		"5432:5432":           true,
+		"0.0.0.0:5432:5432":   true,
+		"127.0.0.1:5432:5432": false,
+		"[::1]:5432:5432":     false,
+		"6379:6379/tcp":       true,
+	}
+	for spec, want := range tests {
+		n := parseYAMLNode(t, `"`+spec+`"`)
+		if got := composePublicPort(n); got != want {
+			t.Errorf("composePublicPort(%q) = %v, want %v", spec, got, want)
+		}
+	}
+	if composePublicPort(parseYAMLNode(t, "{target: 5432, host_ip: 127.0.0.1}")) {
+		t.Error("long syntax on 127.0.0.1 reported as public")
+	}
+}
+
+func parseYAMLNode(t *testing.T, src string) *yaml.Node {
+	t.Helper()
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(src), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc.Content[0]
+}
@@ -105,7 +105,7 @@ func (c CookiebotCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`cookiebot`),
 	}
 
-	found := searchForPatterns(ctx.RootDir, ctx.Config.Stack, patterns)
+	found := searchForPatterns(ctx, patterns)
 
 	if found {
 		if liveURL != "" {
@@ -214,7 +214,7 @@ func (c OneTrustCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`optanon`),
 	}
 
-	found := searchForPatterns(ctx.RootDir, ctx.Config.Stack, patterns)
+	found := searchForPatterns(ctx, patterns)
 
 	if found {
 		if liveURL != "" {
@@ -319,7 +319,7 @@ func (c TermlyCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`termly`),
 	}
 
-	found := searchForPatterns(ctx.RootDir, ctx.Config.Stack, patterns)
+	found := searchForPatterns(ctx, patterns)
 
 	if found {
 		if liveURL != "" {
@@ -425,7 +425,7 @@ func (c CookieYesCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`CookieYes`),
 	}
 
-	found := searchForPatterns(ctx.RootDir, ctx.Config.Stack, patterns)
+	found := searchForPatterns(ctx, patterns)
 
 	if found {
 		if liveURL != "" {
@@ -531,7 +531,7 @@ func (c IubendaCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`_iub`),
 	}
 
-	found := searchForPatterns(ctx.RootDir, ctx.Config.Stack, patterns)
+	found := searchForPatterns(ctx, patterns)
 
 	if found {
 		if liveURL != "" {
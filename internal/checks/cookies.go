@@ -0,0 +1,212 @@
+package checks
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CookieInventoryCheck loads the production homepage the way a first-time
+// visitor would, with no consent given, and lists the cookies its
+// responses set. ePrivacy rules (and GDPR's definition of consent) allow
+// only strictly necessary cookies before the visitor agrees, so tracking
+// cookies here are a compliance problem no banner can fix.
+//
+// Only Set-Cookie headers are seen, on the homepage and the redirects
+// leading to it. Cookies that scripts set in the browser need a headless
+// browser to observe and aren't covered.
+type CookieInventoryCheck struct{}
+
+func (c CookieInventoryCheck) ID() string {
+	return "cookies"
+}
+
+func (c CookieInventoryCheck) Title() string {
+	return "Cookies set before consent"
+}
+
+// trackingCookie matches the names analytics and advertising tools give
+// their cookies. Mostly set from JavaScript, they reach Set-Cookie when a
+// server-side tag manager or proxy sets them first-party.
+var trackingCookie = regexp.MustCompile(`^(_ga|_ga_\w+|_gid|_gat\w*|_gcl_\w+|__utm[a-z]|_fbp|_fbc|fr|_hj\w+|_clck|_clsk|_uet(sid|vid)|_ttp|_tt_enable_cookie|li_fat_id|_pin_unauth|hubspotutk|__hs\w+|ajs_(anonymous|user)_id|mp_\w+_mixpanel|amp_\w+|AMP_\w+|ph_\w+_posthog|_pk_(id|ses)\.\w+|IDE|NID|_rdt_uuid|_lr_\w+|_scid|_sctr)$`)
+
+// maxCookieLifetime is the longest a consent-exempt cookie is expected to
+// last. Regulators (the CNIL, for one) cap tracker lifetimes at 13 months.
+const maxCookieLifetime = 13 * 30 * 24 * time.Hour
+
+// maxCookieRedirects bounds the redirect chain followed to the homepage.
+const maxCookieRedirects = 5
+
+func (c CookieInventoryCheck) Run(ctx Context) (CheckResult, error) {
+	base := ctx.Config.URLs.Production
+	if base == "" || ctx.Client == nil {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No production URL configured, skipping",
+		}, nil
+	}
+
+	cookies, pageURL, err := fetchSetCookies(ctx, base)
+	if err != nil {
+		// healthEndpoint reports a site that's down; this check has
+		// nothing to say about one.
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("Could not reach %s to inspect cookies", base),
+			Details:  []string{err.Error()},
+		}, nil
+	}
+	if len(cookies) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "The homepage sets no cookies before consent",
+			Details:  []string{"Only Set-Cookie headers were inspected; cookies set by scripts in the browser aren't covered"},
+		}, nil
+	}
+
+	https := pageURL != nil && pageURL.Scheme == "https"
+	var tracking, problems, details []string
+	for _, ck := range cookies {
+		details = append(details, describeCookie(ck))
+		if trackingCookie.MatchString(ck.Name) {
+			tracking = append(tracking, ck.Name)
+		}
+		if https && !ck.Secure {
+			problems = append(problems, fmt.Sprintf("%s is set without Secure, so it is also sent over plain HTTP", ck.Name))
+		}
+		if ck.SameSite == http.SameSiteNoneMode && !ck.Secure {
+			problems = append(problems, fmt.Sprintf("%s has SameSite=None without Secure; browsers reject it", ck.Name))
+		}
+		if life := cookieLifetime(ck); life > maxCookieLifetime {
+			problems = append(problems, fmt.Sprintf("%s lasts %d months; keep cookies to 13 months or less", ck.Name, int(life.Hours()/24/30)))
+		}
+	}
+
+	if len(tracking) == 0 && len(problems) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("%d cookie(s) before consent, none for tracking", len(cookies)),
+			Details:  details,
+		}, nil
+	}
+
+	var suggestions []string
+	message := fmt.Sprintf("%d cookie(s) before consent with attribute problems", len(cookies))
+	if len(tracking) > 0 {
+		message = fmt.Sprintf("%d tracking cookie(s) set before consent: %s", len(tracking), strings.Join(tracking, ", "))
+		suggestions = append(suggestions,
+			"Set analytics and advertising cookies only after the visitor opts in (load the tags from your consent manager)")
+	}
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    SeverityWarn,
+		Passed:      false,
+		Message:     message,
+		Suggestions: append(suggestions, limitFindings(problems, 8)...),
+		Details:     details,
+	}, nil
+}
+
+// fetchSetCookies GETs rawURL without a cookie jar and returns the cookies
+// set along the redirect chain, deduplicated by name (the last one wins),
+// with the URL the page was finally served from.
+func fetchSetCookies(ctx Context, rawURL string) ([]*http.Cookie, *url.URL, error) {
+	client := *ctx.Client
+	client.Jar = nil
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	byName := map[string]*http.Cookie{}
+	resp, _, err := tryURL(ctx.reqContext(), &client, rawURL)
+	for hops := 0; ; hops++ {
+		if err != nil {
+			return nil, nil, err
+		}
+		resp.Body.Close()
+		for _, ck := range resp.Cookies() {
+			if ck.MaxAge < 0 {
+				delete(byName, ck.Name) // Max-Age=0 clears the cookie
+				continue
+			}
+			byName[ck.Name] = ck
+		}
+		loc, locErr := resp.Location()
+		if locErr != nil || resp.StatusCode < 300 || resp.StatusCode >= 400 || hops >= maxCookieRedirects {
+			break
+		}
+		resp, err = doGet(ctx.reqContext(), &client, loc.String())
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	cookies := make([]*http.Cookie, 0, len(names))
+	for _, name := range names {
+		cookies = append(cookies, byName[name])
+	}
+	return cookies, resp.Request.URL, nil
+}
+
+// cookieLifetime is how long ck persists, or 0 for a session cookie.
+func cookieLifetime(ck *http.Cookie) time.Duration {
+	if ck.MaxAge > 0 {
+		return time.Duration(ck.MaxAge) * time.Second
+	}
+	if !ck.Expires.IsZero() {
+		return time.Until(ck.Expires)
+	}
+	return 0
+}
+
+// describeCookie renders one line of the inventory.
+func describeCookie(ck *http.Cookie) string {
+	var attrs []string
+	if ck.Domain != "" {
+		attrs = append(attrs, "Domain="+ck.Domain)
+	}
+	if ck.Secure {
+		attrs = append(attrs, "Secure")
+	}
+	if ck.HttpOnly {
+		attrs = append(attrs, "HttpOnly")
+	}
+	switch ck.SameSite {
+	case http.SameSiteLaxMode:
+		attrs = append(attrs, "SameSite=Lax")
+	case http.SameSiteStrictMode:
+		attrs = append(attrs, "SameSite=Strict")
+	case http.SameSiteNoneMode:
+		attrs = append(attrs, "SameSite=None")
+	default:
+		attrs = append(attrs, "no SameSite (Lax)")
+	}
+	expiry := "session"
+	if life := cookieLifetime(ck); life > 0 {
+		if days := int(life.Hours() / 24); days > 0 {
+			expiry = fmt.Sprintf("expires in %d days", days)
+		} else {
+			expiry = "expires within a day"
+		}
+	}
+	return fmt.Sprintf("%s - %s, %s", ck.Name, strings.Join(attrs, ", "), expiry)
+}
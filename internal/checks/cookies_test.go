@@ -0,0 +1,66 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestCookieInventoryCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			// Cookies set on a redirect count: the browser keeps them.
+			http.SetCookie(w, &http.Cookie{Name: "_ga", Value: "GA1.1.1", MaxAge: 400 * 24 * 3600})
+			http.Redirect(w, r, "/home", http.StatusFound)
+		case "/home":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "x", HttpOnly: true, SameSite: http.SameSiteLaxMode})
+			http.SetCookie(w, &http.Cookie{Name: "prefs", Value: "y", SameSite: http.SameSiteNoneMode})
+			w.Write([]byte("<html></html>"))
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	result, err := CookieInventoryCheck{}.Run(Context{Config: cfg, Client: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed || result.Severity != SeverityWarn {
+		t.Fatalf("got passed=%v severity=%v, want a warning", result.Passed, result.Severity)
+	}
+	if result.Message != "1 tracking cookie(s) set before consent: _ga" {
+		t.Errorf("message = %q", result.Message)
+	}
+	suggestions := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{"prefs has SameSite=None without Secure", "_ga lasts 13 months"} {
+		if !strings.Contains(suggestions, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, suggestions)
+		}
+	}
+	details := strings.Join(result.Details, "\n")
+	if !strings.Contains(details, "session - HttpOnly, SameSite=Lax, session") {
+		t.Errorf("details missing the session cookie:\n%s", details)
+	}
+}
+
+func TestCookieInventoryCheckNoCookies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html></html>"))
+	}))
+	defer srv.Close()
+
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	result, err := CookieInventoryCheck{}.Run(Context{Config: cfg, Client: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || result.Message != "The homepage sets no cookies before consent" {
+		t.Errorf("got passed=%v message=%q", result.Passed, result.Message)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package checks
+
+import (
+	"syscall"
+	"time"
+)
+
+// processCPUTime returns the user and system CPU time used so far by this
+// process and the children it has waited for.
+func processCPUTime() time.Duration {
+	var total time.Duration
+	for _, who := range []int{syscall.RUSAGE_SELF, syscall.RUSAGE_CHILDREN} {
+		var ru syscall.Rusage
+		if syscall.Getrusage(who, &ru) == nil {
+			total += time.Duration(ru.Utime.Nano()) + time.Duration(ru.Stime.Nano())
+		}
+	}
+	return total
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package checks
+
+import (
+	"syscall"
+	"time"
+)
+
+// processCPUTime returns the user and kernel CPU time used so far by this
+// process. Windows doesn't account child processes to the parent.
+func processCPUTime() time.Duration {
+	var creation, exit, kernel, user syscall.Filetime
+	h, err := syscall.GetCurrentProcess()
+	if err != nil || syscall.GetProcessTimes(h, &creation, &exit, &kernel, &user) != nil {
+		return 0
+	}
+	// Filetime counts 100ns intervals.
+	ticks := func(ft syscall.Filetime) int64 { return int64(ft.HighDateTime)<<32 | int64(ft.LowDateTime) }
+	return time.Duration((ticks(kernel) + ticks(user)) * 100)
+}
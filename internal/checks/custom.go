@@ -0,0 +1,153 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+// CustomCheck runs one rule from the customChecks list in preflight.yml:
+// a file that must exist and, optionally, must or must not match a
+// regular expression.
+type CustomCheck struct {
+	Rule config.CustomCheckConfig
+}
+
+func (c CustomCheck) ID() string {
+	return c.Rule.ID
+}
+
+func (c CustomCheck) Title() string {
+	if c.Rule.Title != "" {
+		return c.Rule.Title
+	}
+	return c.Rule.ID
+}
+
+// NewCustomChecks turns the configured rules into checks. Rules are
+// validated when preflight.yml is loaded; what's left to catch here is an
+// id that would shadow a built-in check, one of the reserved IDs the
+// caller runs besides the Registry (the service checks), or a tag, which
+// --only and --skip would read as the tag.
+func NewCustomChecks(rules []config.CustomCheckConfig, reserved []string) ([]Check, error) {
+	builtin := make(map[string]bool, len(Registry)+len(reserved))
+	for _, check := range Registry {
+		builtin[check.ID()] = true
+	}
+	for _, id := range reserved {
+		builtin[id] = true
+	}
+	var out []Check
+	for _, r := range rules {
+		if builtin[r.ID] {
+			return nil, fmt.Errorf("customChecks.%s: id is already used by a built-in check", r.ID)
+		}
+		if IsTag(r.ID) {
+			return nil, fmt.Errorf("customChecks.%s: id is a tag name (%s)", r.ID, strings.Join(AllTags, ", "))
+		}
+		out = append(out, CustomCheck{Rule: r})
+	}
+	return out, nil
+}
+
+func (c CustomCheck) Run(ctx Context) (CheckResult, error) {
+	severity := SeverityWarn
+	if c.Rule.Severity == "error" {
+		severity = SeverityError
+	}
+	fail := func(message string, findings []string) (CheckResult, error) {
+		var suggestions []string
+		if c.Rule.Suggestion != "" {
+			suggestions = append(suggestions, c.Rule.Suggestion)
+		}
+		return CheckResult{
+			ID:          c.ID(),
+			Title:       c.Title(),
+			Severity:    severity,
+			Passed:      false,
+			Message:     message,
+			Suggestions: append(suggestions, limitFindings(findings, 5)...),
+		}, nil
+	}
+
+	pattern := filepath.ToSlash(c.Rule.File)
+	files, err := doublestar.Glob(os.DirFS(ctx.RootDir), pattern, doublestar.WithFilesOnly())
+	if err != nil {
+		return fail(fmt.Sprintf("Invalid file pattern %q: %v", c.Rule.File, err), nil)
+	}
+	if len(files) == 0 {
+		return fail(fmt.Sprintf("%s not found", c.Rule.File), nil)
+	}
+
+	mustMatch, err := compileOptional(c.Rule.MustMatch)
+	if err != nil {
+		return fail(fmt.Sprintf("Invalid mustMatch pattern: %v", err), nil)
+	}
+	mustNotMatch, err := compileOptional(c.Rule.MustNotMatch)
+	if err != nil {
+		return fail(fmt.Sprintf("Invalid mustNotMatch pattern: %v", err), nil)
+	}
+
+	if mustMatch == nil && mustNotMatch == nil {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("%s found", c.Rule.File),
+		}, nil
+	}
+
+	var findings []string
+	broken := 0
+	for _, rel := range files {
+		before := len(findings)
+		content, err := readFile(filepath.Join(ctx.RootDir, filepath.FromSlash(rel)))
+		if err != nil {
+			findings = append(findings, fmt.Sprintf("%s - could not be read", rel))
+		} else {
+			if mustMatch != nil && !mustMatch.Match(content) {
+				findings = append(findings, fmt.Sprintf("%s - does not match %s", rel, c.Rule.MustMatch))
+			}
+			if mustNotMatch != nil {
+				if loc := mustNotMatch.FindIndex(content); loc != nil {
+					findings = append(findings, fmt.Sprintf("%s:%d - matches %s", rel, lineAt(content, loc[0]), c.Rule.MustNotMatch))
+				}
+			}
+		}
+		if len(findings) > before {
+			broken++
+		}
+	}
+
+	if len(findings) == 1 {
+		return fail(findings[0], nil)
+	}
+	if len(findings) > 0 {
+		return fail(fmt.Sprintf("%d of %d files break the rule", broken, len(files)), findings)
+	}
+	message := fmt.Sprintf("%d files follow the rule", len(files))
+	if len(files) == 1 {
+		message = files[0] + " follows the rule"
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   true,
+		Message:  message,
+	}, nil
+}
+
+// compileOptional compiles expr, or returns nil when it's empty.
+func compileOptional(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	return regexp.Compile(expr)
+}
@@ -0,0 +1,118 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestCustomCheck(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"app/privacy/page.tsx": "export default function Privacy() {}",
+		"app/layout.tsx":       "<html lang=\"en\"><head><title>Acme</title></head></html>",
+		"app/pricing/page.tsx": "// TODO: real prices\nexport default function Pricing() {}",
+		"app/about/page.tsx":   "export default function About() {}",
+	})
+
+	cases := []struct {
+		name     string
+		rule     config.CustomCheckConfig
+		passed   bool
+		severity Severity
+		message  string
+	}{
+		{
+			name:    "file exists",
+			rule:    config.CustomCheckConfig{ID: "privacy", File: "app/privacy/page.tsx"},
+			passed:  true,
+			message: "app/privacy/page.tsx found",
+		},
+		{
+			name:     "file missing",
+			rule:     config.CustomCheckConfig{ID: "terms", File: "app/terms/page.tsx", Severity: "error"},
+			severity: SeverityError,
+			message:  "app/terms/page.tsx not found",
+		},
+		{
+			name:    "must match",
+			rule:    config.CustomCheckConfig{ID: "title", File: "app/layout.tsx", MustMatch: `<title>`},
+			passed:  true,
+			message: "app/layout.tsx follows the rule",
+		},
+		{
+			name:     "must match fails",
+			rule:     config.CustomCheckConfig{ID: "analytics", File: "app/layout.tsx", MustMatch: `plausible`},
+			severity: SeverityWarn,
+			message:  "app/layout.tsx - does not match plausible",
+		},
+		{
+			name:     "must not match across a glob",
+			rule:     config.CustomCheckConfig{ID: "todos", File: "app/**/page.tsx", MustNotMatch: `TODO`},
+			severity: SeverityWarn,
+			message:  "app/pricing/page.tsx:1 - matches TODO",
+		},
+		{
+			name:    "glob all good",
+			rule:    config.CustomCheckConfig{ID: "exports", File: "app/**/page.tsx", MustMatch: `export default`},
+			passed:  true,
+			message: "3 files follow the rule",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := CustomCheck{Rule: tc.rule}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tc.passed {
+				t.Fatalf("passed = %v, want %v (%s)", result.Passed, tc.passed, result.Message)
+			}
+			if !tc.passed && result.Severity != tc.severity {
+				t.Errorf("severity = %s, want %s", result.Severity, tc.severity)
+			}
+			if result.Message != tc.message {
+				t.Errorf("message = %q, want %q", result.Message, tc.message)
+			}
+			if result.ID != tc.rule.ID || result.Title != tc.rule.ID {
+				t.Errorf("ID/Title = %q/%q, want the rule id", result.ID, result.Title)
+			}
+		})
+	}
+}
+
+func TestCustomCheckListsBrokenFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"pages/a.html": "<h1>A</h1>",
+		"pages/b.html": "<p>B</p>",
+		"pages/c.html": "<p>C</p>",
+	})
+	rule := config.CustomCheckConfig{ID: "h1", Title: "Every page has an h1", File: "pages/*.html", MustMatch: `<h1`, Suggestion: "Give each page one <h1>"}
+	result, err := CustomCheck{Rule: rule}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed || result.Message != "2 of 3 files break the rule" || result.Title != "Every page has an h1" {
+		t.Fatalf("got %+v", result)
+	}
+	joined := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{"Give each page one <h1>", "pages/b.html - does not match <h1", "pages/c.html"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+}
+
+func TestNewCustomChecksRejectsBuiltinID(t *testing.T) {
+	// secrets is in the Registry, sentry a reserved service check and seo
+	// a tag; each would make --only, ignore and snooze ambiguous.
+	for _, id := range []string{"secrets", "sentry", "seo"} {
+		if _, err := NewCustomChecks([]config.CustomCheckConfig{{ID: id, File: "x"}}, []string{"sentry"}); err == nil {
+			t.Errorf("%s: expected an error for an id that shadows a built-in check", id)
+		}
+	}
+	got, err := NewCustomChecks([]config.CustomCheckConfig{{ID: "privacy", File: "x"}}, []string{"sentry"})
+	if err != nil || len(got) != 1 || got[0].ID() != "privacy" {
+		t.Errorf("got %v, %v", got, err)
+	}
+}
@@ -0,0 +1,158 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DebugModeCheck looks for framework debug switches left on in
+// production configuration: APP_DEBUG in a Laravel or Symfony env file,
+// DEBUG = True in Django settings, consider_all_requests_local in Rails'
+// production.rb, and WP_DEBUG in wp-config.php. Each one makes error pages
+// print stack traces, source and often environment variables to anyone
+// who triggers an exception, so any of them is an error. Unlike
+// debug_statements, this reads configuration, not code.
+type DebugModeCheck struct{}
+
+func (c DebugModeCheck) ID() string {
+	return "debug_mode"
+}
+
+func (c DebugModeCheck) Title() string {
+	return "Debug mode"
+}
+
+var (
+	// reDjangoDebug matches DEBUG set to True outright or as the fallback
+	// when the environment doesn't say (env.bool("DEBUG", default=True),
+	// os.environ.get("DEBUG", "True")).
+	reDjangoDebug = regexp.MustCompile(`(?m)^DEBUG\s*=\s*(?:True\b|.*(?:default\s*=\s*True\b|,\s*(?:True|["'](?:True|true|1)["'])\s*\)))`)
+	reRailsLocal  = regexp.MustCompile(`(?m)^\s*config\.consider_all_requests_local\s*=\s*true\b`)
+	reWPDebug     = regexp.MustCompile(`(?mi)^\s*define\s*\(\s*["']WP_DEBUG["']\s*,\s*true\s*\)`)
+	// reLaravelDebug matches a debug flag hardcoded on in config/app.php
+	// instead of read from APP_DEBUG.
+	reLaravelDebug = regexp.MustCompile(`(?m)^\s*["']debug["']\s*=>\s*(?:true\b|\(bool\)\s*env\(\s*["']APP_DEBUG["']\s*,\s*true\s*\))`)
+	// reDjangoDevSettings matches settings modules meant for development.
+	reDjangoDevSettings  = regexp.MustCompile(`(?i)(?:^|[_.-])(?:dev|development|local|test|testing|ci)\.py$`)
+	reDjangoProdSettings = regexp.MustCompile(`(?i)(?:^|[_.-])(?:prod|production|live)\.py$`)
+)
+
+func (c DebugModeCheck) Run(ctx Context) (CheckResult, error) {
+	files := ctx.files()
+	var findings []string
+	var locations []Location
+	report := func(loc Location) {
+		findings = append(findings, loc.String())
+		locations = append(locations, loc)
+	}
+	match := func(f FileEntry, re *regexp.Regexp, message string) {
+		content, err := readFile(files.Abs(f))
+		if err != nil {
+			return
+		}
+		if m := re.FindIndex(content); m != nil {
+			report(Location{File: f.Path, Line: lineAt(content, m[0]), Message: message})
+		}
+	}
+
+	// A project with settings/production.py deploys with that, so its
+	// base settings.py may leave DEBUG on for development.
+	var djangoSettings []FileEntry
+	prodSettings := map[string]bool{}
+	for _, f := range files.Files {
+		if f.inDir(vendorSkipDirs) || isTestSource(f.Path) {
+			continue
+		}
+		switch {
+		case f.Name == ".env" || f.Name == ".env.production" || f.Name == ".env.prod":
+			if loc, ok := envDebugOn(files, f); ok {
+				report(loc)
+			}
+		case f.Path == "config/environments/production.rb" || strings.HasSuffix(f.Path, "/config/environments/production.rb"):
+			match(f, reRailsLocal, "consider_all_requests_local is on in production")
+		case f.Name == "wp-config.php":
+			match(f, reWPDebug, "WP_DEBUG is on")
+		case f.Path == "config/app.php" || strings.HasSuffix(f.Path, "/config/app.php"):
+			match(f, reLaravelDebug, "debug is hardcoded on")
+		case f.Ext == ".py" && (f.Name == "settings.py" || path.Base(path.Dir(f.Path)) == "settings"):
+			if reDjangoDevSettings.MatchString(f.Name) {
+				continue
+			}
+			if reDjangoProdSettings.MatchString(f.Name) {
+				prodSettings[path.Dir(f.Path)] = true
+			}
+			djangoSettings = append(djangoSettings, f)
+		}
+	}
+	for _, f := range djangoSettings {
+		if prodSettings[path.Dir(f.Path)] && !reDjangoProdSettings.MatchString(f.Name) {
+			continue
+		}
+		match(f, reDjangoDebug, "DEBUG is True")
+	}
+
+	if len(findings) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No framework debug mode on in production config",
+		}, nil
+	}
+
+	message := fmt.Sprintf("Debug mode on in %d production config setting(s)", len(findings))
+	if len(findings) == 1 {
+		message = "Debug mode on: " + findings[0]
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityError,
+		Passed:   false,
+		Message:  message,
+		Suggestions: append([]string{
+			"Turn debug off in production: APP_DEBUG=false, DEBUG = False (or read it from the environment with a False default), config.consider_all_requests_local = false, define('WP_DEBUG', false)",
+			"Debug error pages show stack traces, source and environment variables to anyone who triggers an error",
+		}, limitFindings(findings, 8)...),
+		Locations: locations,
+	}, nil
+}
+
+// envDebugOn reports APP_DEBUG=true in a Laravel or Symfony env file.
+// In .env it only counts when APP_ENV is production or unset: Laravel
+// treats a missing APP_ENV as production, and a local .env with
+// APP_ENV=local is meant to have debug on.
+func envDebugOn(files *FileIndex, f FileEntry) (Location, bool) {
+	content, err := readFile(files.Abs(f))
+	if err != nil {
+		return Location{}, false
+	}
+	env, debug, line := "", false, 0
+	for i, raw := range strings.Split(string(content), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(raw), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		switch strings.TrimSpace(strings.TrimPrefix(key, "export ")) {
+		case "APP_ENV":
+			env = strings.ToLower(envValue(value))
+		case "APP_DEBUG":
+			switch strings.ToLower(envValue(value)) {
+			case "true", "1", "on", "yes":
+				debug, line = true, i+1
+			default:
+				debug = false
+			}
+		}
+	}
+	if !debug {
+		return Location{}, false
+	}
+	if f.Name == ".env" && env != "" && env != "production" && env != "prod" {
+		return Location{}, false
+	}
+	return Location{File: f.Path, Line: line, Message: "APP_DEBUG is true"}, true
+}
@@ -0,0 +1,76 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDebugModeCheck(t *testing.T) {
+	cases := []struct {
+		name    string
+		files   map[string]string
+		message string
+		want    []string
+	}{
+		{
+			// A local .env is meant to have debug on, and with
+			// settings/production.py deploying, base.py may too.
+			name: "debug off in production",
+			files: map[string]string{
+				".env":                               "APP_ENV=local\nAPP_DEBUG=true\n",
+				".env.production":                    "APP_ENV=production\nAPP_DEBUG=false\n",
+				"config/app.php":                     "<?php\nreturn [\n    'debug' => (bool) env('APP_DEBUG', false),\n];\n",
+				"config/environments/production.rb":  "Rails.application.configure do\n  config.consider_all_requests_local = false\nend\n",
+				"config/environments/development.rb": "Rails.application.configure do\n  config.consider_all_requests_local = true\nend\n",
+				"wp-config.php":                      "<?php\ndefine( 'WP_DEBUG', false );\n",
+				"mysite/settings/base.py":            "DEBUG = True\n",
+				"mysite/settings/local.py":           "DEBUG = True\n",
+				"mysite/settings/production.py":      "from .base import *\nDEBUG = env.bool(\"DEBUG\", default=False)\n",
+			},
+		},
+		{
+			name: "debug on everywhere",
+			files: map[string]string{
+				".env":                              "APP_KEY=base64:abc\nAPP_DEBUG=true\n",
+				".env.production":                   "APP_ENV=production\nAPP_DEBUG=\"true\"\n",
+				"config/app.php":                    "<?php\nreturn [\n    'debug' => true,\n];\n",
+				"config/environments/production.rb": "Rails.application.configure do\n  config.consider_all_requests_local = true\nend\n",
+				"wp-config.php":                     "<?php\ndefine('WP_DEBUG', true);\n",
+				"mysite/settings.py":                "import os\nDEBUG = os.environ.get(\"DEBUG\", \"True\") == \"True\"\n",
+			},
+			message: "Debug mode on in 6 production config setting(s)",
+			want: []string{
+				".env:2 - APP_DEBUG is true",
+				".env.production:2 - APP_DEBUG is true",
+				"config/app.php:3 - debug is hardcoded on",
+				"config/environments/production.rb:2 - consider_all_requests_local is on in production",
+				"wp-config.php:2 - WP_DEBUG is on",
+				"mysite/settings.py:2 - DEBUG is True",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := DebugModeCheck{}.Run(Context{RootDir: writeFiles(t, tc.files)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.message == "" {
+				if !result.Passed {
+					t.Errorf("expected pass, got %q %v", result.Message, result.Suggestions)
+				}
+				return
+			}
+			if result.Passed || result.Severity != SeverityError || result.Message != tc.message {
+				t.Fatalf("got %v %v %q, want error %q", result.Passed, result.Severity, result.Message, tc.message)
+			}
+			joined := strings.Join(result.Suggestions, "\n")
+			for _, want := range tc.want {
+				if !strings.Contains(joined, want) {
+					t.Errorf("suggestions missing %q:\n%s", want, joined)
+				}
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package checks
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -21,7 +20,7 @@ func (c DebugStatementsCheck) Title() string {
 }
 
 func (c DebugStatementsCheck) Run(ctx Context) (CheckResult, error) {
-	findings := scanForDebugStatements(ctx.RootDir, ctx.Config.Ignore)
+	findings := scanForDebugStatements(ctx.files(), ctx.Config.Ignore)
 
 	if len(findings) == 0 {
 		return CheckResult{
@@ -43,7 +42,7 @@ func (c DebugStatementsCheck) Run(ctx Context) (CheckResult, error) {
 			suggestions = append(suggestions, fmt.Sprintf("... and %d more", len(findings)-maxFindings))
 			break
 		}
-		suggestions = append(suggestions, finding)
+		suggestions = append(suggestions, finding.String())
 	}
 
 	return CheckResult{
@@ -53,6 +52,7 @@ func (c DebugStatementsCheck) Run(ctx Context) (CheckResult, error) {
 		Passed:      false,
 		Message:     message,
 		Suggestions: suggestions,
+		Locations:   findings,
 	}, nil
 }
 
@@ -62,8 +62,8 @@ type debugPattern struct {
 	extensions  []string // file extensions to check (empty = all supported)
 }
 
-func scanForDebugStatements(rootDir string, ignore []string) []string {
-	var findings []string
+func scanForDebugStatements(files *FileIndex, ignore []string) []Location {
+	var findings []Location
 
 	// Debug patterns by language
 	patterns := []debugPattern{
@@ -289,68 +289,58 @@ func scanForDebugStatements(rootDir string, ignore []string) []string {
 		"stimulus",
 	}
 
-	// Walk the project
-	_ = filepath.WalkDir(rootDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			if d != nil && d.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		// Skip directories
-		if d.IsDir() {
-			if skipDirs[d.Name()] {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		// Skip symlinks, devices, pipes — same path-traversal / DoS
-		// concern as the secrets walker (e.g. a symlink to /dev/zero
-		// would let os.ReadFile bypass the 500KB size cap below).
-		if !d.Type().IsRegular() {
-			return nil
+	for _, f := range files.Files {
+		// Gitignored files are generated or local-only; debug calls in
+		// them never ship from source control.
+		if f.Ignored || f.inDir(skipDirs) {
+			continue
 		}
 
 		// Honor user-configured ignore globs (the top-level `ignore` list in
 		// preflight.yml), so build tooling, vendored code, or files that only
 		// mention debug calls in strings/docs can be excluded.
-		if rel, relErr := filepath.Rel(rootDir, path); relErr == nil {
-			rel = filepath.ToSlash(rel)
-			for _, g := range ignore {
-				if ok, _ := doublestar.Match(filepath.ToSlash(g), rel); ok {
-					return nil
-				}
+		ignored := false
+		for _, g := range ignore {
+			if ok, _ := doublestar.Match(filepath.ToSlash(g), f.Path); ok {
+				ignored = true
+				break
 			}
 		}
+		if ignored {
+			continue
+		}
 
 		// Check if file should be skipped
-		filename := strings.ToLower(d.Name())
+		filename := strings.ToLower(f.Name)
+		skipped := false
 		for _, skip := range skipFiles {
 			if strings.Contains(filename, skip) {
-				return nil
+				skipped = true
+				break
 			}
 		}
+		if skipped {
+			continue
+		}
 
 		// Get file extension
-		ext := strings.ToLower(filepath.Ext(path))
+		ext := f.Ext
 
 		// Handle .blade.php
-		if strings.HasSuffix(path, ".blade.php") {
+		if strings.HasSuffix(filename, ".blade.php") {
 			ext = ".blade.php"
 		}
 
-		// Skip files larger than 500KB
-		info, err := d.Info()
-		if err != nil || info.Size() > 500*1024 {
-			return nil
+		// Skip files larger than 500KB. The index holds regular files
+		// only, so a symlink to /dev/zero can't get around this cap.
+		if f.Size > 500*1024 {
+			continue
 		}
 
 		// Read file content
-		content, err := os.ReadFile(path)
+		content, err := readFile(files.Abs(f))
 		if err != nil {
-			return nil
+			continue
 		}
 
 		// Check each line for patterns
@@ -394,15 +384,12 @@ func scanForDebugStatements(rootDir string, ignore []string) []string {
 
 				if p.pattern.MatchString(line) {
 					if !isDevGuarded(lines, lineNum) && !isInCodeExample(lines, lineNum) {
-						relPath := relPath(rootDir, path)
-						findings = append(findings, fmt.Sprintf("%s:%d - %s", relPath, lineNum+1, p.description))
+						findings = append(findings, Location{File: f.Path, Line: lineNum + 1, Message: p.description})
 					}
 				}
 			}
 		}
-
-		return nil
-	})
+	}
 
 	return findings
 }
@@ -67,10 +67,25 @@ func TestScanForDebugStatements(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := scanForDebugStatements(writeSrc(t, tc.file, tc.body), nil)
+			got := scanForDebugStatements(BuildFileIndex(writeSrc(t, tc.file, tc.body)), nil)
 			if gotAny := len(got) > 0; gotAny != tc.wantAny {
 				t.Errorf("scanForDebugStatements found %v, want any=%v", got, tc.wantAny)
 			}
 		})
 	}
 }
+
+// Generated bundles are usually gitignored rather than living in one of
+// the hardcoded build directories, and their debug calls never ship from
+// source control.
+func TestScanForDebugStatementsSkipsGitignored(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".gitignore":          "/generated\n",
+		"generated/client.js": "console.log('generated')\n",
+		"src/app.js":          "console.log('real')\n",
+	})
+	got := scanForDebugStatements(BuildFileIndex(dir), nil)
+	if len(got) != 1 || got[0].File != "src/app.js" {
+		t.Errorf("findings = %v, want only src/app.js", got)
+	}
+}
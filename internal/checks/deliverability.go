@@ -0,0 +1,431 @@
+package checks
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"math"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// Deliverability is email_auth's answer to "will my emails land?": one
+// element per DNS record receiving servers look at, and a score out of 100
+// weighing them. Elements that couldn't be judged are left out of the
+// score.
+type Deliverability struct {
+	Domain   string                  `json:"domain"`
+	Score    int                     `json:"score"`
+	Elements []DeliverabilityElement `json:"elements"`
+}
+
+// DeliverabilityElement is one record of the report. Found describes what
+// the lookup turned up; Fix is the first thing to do when it fails.
+type DeliverabilityElement struct {
+	Name    string `json:"name"`
+	Weight  int    `json:"weight"`
+	Passed  bool   `json:"passed"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Found   string `json:"found,omitempty"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+// Element weights, out of 100. SPF, DKIM and DMARC are what Gmail and
+// Yahoo require of bulk senders; MX and reverse DNS count for less.
+const (
+	weightSPF        = 25
+	weightDKIM       = 25
+	weightDMARC      = 25
+	weightMX         = 15
+	weightReverseDNS = 10
+)
+
+// commonDKIMSelectors are the selectors mail providers publish keys under
+// by default: Google Workspace, Microsoft 365, Mailchimp, SendGrid,
+// Mailgun, Amazon SES (via CNAME), Zoho, Fastmail, Proton, Resend, and
+// the generic names self-hosted servers use.
+var commonDKIMSelectors = []string{
+	"google", "selector1", "selector2", "k1", "k2", "s1", "s2", "smtp", "mg", "mta",
+	"zoho", "zmail", "fm1", "protonmail", "resend", "mandrill", "default", "dkim", "mail",
+}
+
+// maxReverseDNSHosts bounds how many MX hosts get a reverse DNS check.
+const maxReverseDNSHosts = 3
+
+func newDeliverability(domain string, elements []DeliverabilityElement) *Deliverability {
+	var earned, possible int
+	for _, e := range elements {
+		if e.Skipped {
+			continue
+		}
+		possible += e.Weight
+		if e.Passed {
+			earned += e.Weight
+		}
+	}
+	score := 100
+	if possible > 0 {
+		score = int(math.Round(float64(earned) * 100 / float64(possible)))
+	}
+	return &Deliverability{Domain: domain, Score: score, Elements: elements}
+}
+
+// spfElement looks for exactly one SPF record on domain that doesn't let
+// every server on the internet send as it.
+func spfElement(ctx Context, domain string) (DeliverabilityElement, error) {
+	e := DeliverabilityElement{Name: "SPF", Weight: weightSPF}
+	records, err := lookupTXTPrefix(ctx, domain, "v=spf1")
+	if err != nil {
+		return e, err
+	}
+	switch {
+	case len(records) == 0:
+		e.Found = "no SPF record"
+		e.Fix = "Add an SPF TXT record listing your mail providers: v=spf1 include:<provider> ~all"
+	case len(records) > 1:
+		e.Found = fmt.Sprintf("%d SPF records, which receivers treat as none", len(records))
+		e.Fix = "Merge them into a single v=spf1 record"
+	case strings.HasSuffix(strings.ToLower(strings.TrimSpace(records[0])), "+all"):
+		e.Found = "+all lets any server send as the domain"
+		e.Fix = "End the SPF record with ~all or -all"
+	default:
+		e.Passed = true
+		e.Found = truncate(records[0], 60)
+	}
+	return e, nil
+}
+
+// dmarcElement looks for a DMARC record at _dmarc.domain.
+func dmarcElement(ctx Context, domain string) (DeliverabilityElement, error) {
+	e := DeliverabilityElement{Name: "DMARC", Weight: weightDMARC}
+	records, err := lookupTXTPrefix(ctx, "_dmarc."+domain, "v=dmarc1")
+	if err != nil {
+		return e, err
+	}
+	if len(records) == 0 {
+		e.Found = "no record at _dmarc." + domain
+		e.Fix = "Add a DMARC TXT record at _dmarc." + domain + " (v=DMARC1; p=none; rua=mailto:dmarc@" + domain + "), then raise p= once the reports look clean"
+		return e, nil
+	}
+	e.Passed = true
+	e.Found = truncate(records[0], 60)
+	return e, nil
+}
+
+// providerDKIMSelectors are the selectors declared mail services publish
+// their keys under by default. Postmark and Amazon SES generate
+// selectors per domain, which have to be listed under
+// checks.emailAuth.dkimSelectors.
+var providerDKIMSelectors = map[string][]string{
+	"postmark":  {"pm"},
+	"sendgrid":  {"s1", "s2", "smtpapi"},
+	"mailgun":   {"mx", "smtp", "k1", "krs", "mailo", "pic"},
+	"resend":    {"resend"},
+	"mailchimp": {"k1", "k2", "k3"},
+}
+
+// minDKIMKeyBits is the shortest RSA key receivers accept (RFC 8301).
+const minDKIMKeyBits = 1024
+
+// dkimSelectors lists the selectors to look keys up at: the configured
+// ones, then those of declared mail services, then commonDKIMSelectors.
+func dkimSelectors(ctx Context) (selectors []string, configured int) {
+	add := func(selector string) {
+		selector = strings.ToLower(strings.TrimSpace(selector))
+		if selector != "" && !slices.Contains(selectors, selector) {
+			selectors = append(selectors, selector)
+		}
+	}
+	if cfg := ctx.Config.Checks.EmailAuth; cfg != nil {
+		for _, selector := range cfg.DKIMSelectors {
+			add(selector)
+		}
+	}
+	configured = len(selectors)
+	for _, id := range slices.Sorted(maps.Keys(providerDKIMSelectors)) {
+		if ctx.Config.Services[id].Declared {
+			for _, selector := range providerDKIMSelectors[id] {
+				add(selector)
+			}
+		}
+	}
+	for _, selector := range commonDKIMSelectors {
+		add(selector)
+	}
+	return selectors, configured
+}
+
+// dkimKey is what one selector's lookup turned up.
+type dkimKey struct {
+	found   bool   // a DKIM record is published
+	problem string // why the key in it can't verify mail, if it can't
+	bits    int    // RSA key size
+	err     error  // a lookup failure other than no such name
+}
+
+// dkimElement looks for a DKIM key at each selector dkimSelectors lists
+// and checks that it can verify signatures. Selectors configured in
+// preflight.yml must all hold a working key. Otherwise one working key
+// is enough, since a provider's custom selector can't be guessed: a miss
+// may be a key under a name preflight doesn't know.
+func dkimElement(ctx Context, domain string) DeliverabilityElement {
+	e := DeliverabilityElement{Name: "DKIM", Weight: weightDKIM}
+	selectors, configured := dkimSelectors(ctx)
+	keys := make([]dkimKey, len(selectors))
+
+	const workers = 4
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				records, err := dnsLookupTXT(ctx, selectors[i]+"._domainkey."+domain)
+				if err != nil {
+					if !isDNSNotFound(err) {
+						keys[i].err = err
+					}
+					continue
+				}
+				for _, record := range records {
+					lower := strings.ToLower(record)
+					if strings.Contains(lower, "v=dkim1") || strings.Contains(lower, "p=") {
+						keys[i].found = true
+						keys[i].bits, keys[i].problem = dkimKeyProblem(record)
+						break
+					}
+				}
+			}
+		}()
+	}
+	for i := range selectors {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Missing or broken keys at configured selectors fail outright. At
+	// the guessed ones a broken key fails only when no key works: a
+	// revoked one left behind by key rotation is harmless.
+	var valid, broken, missing []string
+	var lookupErr error
+	for i, selector := range selectors {
+		k := keys[i]
+		switch {
+		case k.found && k.problem == "":
+			if k.bits > 0 && k.bits < 2048 {
+				selector += fmt.Sprintf(" (%d-bit)", k.bits)
+			}
+			valid = append(valid, selector)
+		case k.found && i < configured:
+			missing = append(missing, selector+": "+k.problem)
+		case k.found:
+			broken = append(broken, selector+": "+k.problem)
+		case i < configured:
+			if k.err != nil {
+				missing = append(missing, selector+": lookup failed")
+			} else {
+				missing = append(missing, selector+": no key")
+			}
+		}
+		if lookupErr == nil && k.err != nil {
+			lookupErr = k.err
+		}
+	}
+	switch {
+	case len(missing) > 0:
+		e.Found = strings.Join(missing, "; ")
+		e.Fix = "Publish the DKIM key your mail provider gives you at each configured selector (<selector>._domainkey." + domain + ")"
+	case len(valid) > 0:
+		e.Passed = true
+		e.Found = "key at selector " + strings.Join(valid, ", ")
+	case len(broken) > 0:
+		e.Found = "broken key at " + strings.Join(broken, "; ")
+		e.Fix = "Republish the DKIM record exactly as your mail provider shows it; a truncated or mistyped p= value fails every signature"
+	case lookupErr != nil:
+		e.Found = "lookup failed: " + lookupErr.Error()
+		e.Fix = "Check your network connection and DNS resolver"
+	default:
+		e.Found = "no key at the common selectors"
+		e.Fix = "Publish the DKIM key your mail provider gives you (a TXT or CNAME record at <selector>._domainkey." + domain + "), and list its selector under checks.emailAuth.dkimSelectors"
+	}
+	return e
+}
+
+// dkimKeyProblem checks that a DKIM record's p= tag holds a public key
+// that can verify signatures. It returns the RSA key size, and why the
+// key can't be used when it can't.
+func dkimKeyProblem(record string) (bits int, problem string) {
+	tags := parseTagList(record)
+	if v, ok := tags["v"]; ok && !strings.EqualFold(v, "DKIM1") {
+		return 0, fmt.Sprintf("v=%s, want DKIM1", v)
+	}
+	p, ok := tags["p"]
+	if !ok {
+		return 0, "no p= public key"
+	}
+	p = strings.Join(strings.Fields(p), "")
+	if p == "" {
+		return 0, "key revoked (empty p=)"
+	}
+	der, err := base64.StdEncoding.DecodeString(p)
+	if err != nil {
+		return 0, "p= isn't valid base64, likely truncated"
+	}
+	switch keyType := strings.ToLower(valueOr(tags["k"], "rsa")); keyType {
+	case "rsa":
+		key, err := x509.ParsePKIXPublicKey(der)
+		if err != nil {
+			if rsaKey, pkcs1Err := x509.ParsePKCS1PublicKey(der); pkcs1Err == nil {
+				key, err = rsaKey, nil
+			}
+		}
+		rsaKey, isRSA := key.(*rsa.PublicKey)
+		if err != nil || !isRSA {
+			return 0, "p= isn't an RSA public key, likely truncated"
+		}
+		bits = rsaKey.N.BitLen()
+		if bits < minDKIMKeyBits {
+			return bits, fmt.Sprintf("%d-bit RSA key; receivers reject keys under %d bits", bits, minDKIMKeyBits)
+		}
+		return bits, ""
+	case "ed25519":
+		if len(der) != ed25519.PublicKeySize {
+			return 0, "p= isn't a 32-byte Ed25519 key"
+		}
+		return 0, ""
+	default:
+		return 0, fmt.Sprintf("unknown key type k=%s", keyType)
+	}
+}
+
+// mxElement looks up domain's MX records, returning the hosts for the
+// reverse DNS check. A null MX (RFC 7505) says the domain takes no mail,
+// so receivers reject mail from it too: bounces and replies have nowhere
+// to go.
+func mxElement(ctx Context, domain string) (DeliverabilityElement, []string) {
+	e := DeliverabilityElement{Name: "MX", Weight: weightMX}
+	lookupCtx, cancel := context.WithTimeout(ctx.reqContext(), dnsLookupTimeout)
+	defer cancel()
+	countNetworkCall()
+	records, err := netutil.LookupMX(lookupCtx, domain)
+	if err != nil && !isDNSNotFound(err) {
+		e.Found = "lookup failed: " + err.Error()
+		e.Fix = "Check your network connection and DNS resolver"
+		return e, nil
+	}
+
+	var hosts, listed []string
+	for _, mx := range records {
+		host := strings.TrimSuffix(mx.Host, ".")
+		if host == "" {
+			continue
+		}
+		hosts = append(hosts, host)
+		listed = append(listed, fmt.Sprintf("%s (%d)", host, mx.Pref))
+	}
+	switch {
+	case len(records) > 0 && len(hosts) == 0:
+		e.Found = "null MX: the domain accepts no mail"
+		e.Fix = "Replace the null MX with your mail provider's MX hosts so replies and bounces have somewhere to go"
+	case len(hosts) == 0:
+		e.Found = "no MX records"
+		e.Fix = "Add the MX records your mail provider lists, so replies and bounces reach you"
+	default:
+		e.Passed = true
+		e.Found = strings.Join(limitFindings(listed, maxReverseDNSHosts), ", ")
+	}
+	return e, hosts
+}
+
+// reverseDNSElement checks that the first addresses of the MX hosts have
+// PTR records resolving back to them (forward-confirmed reverse DNS).
+// Receivers check this for the sending IP, which DNS doesn't reveal; the
+// MX hosts are usually the same provider's.
+func reverseDNSElement(ctx Context, hosts []string) DeliverabilityElement {
+	e := DeliverabilityElement{Name: "reverse DNS", Weight: weightReverseDNS}
+	if len(hosts) == 0 {
+		e.Skipped = true
+		e.Found = "no MX hosts to check"
+		return e
+	}
+	if len(hosts) > maxReverseDNSHosts {
+		hosts = hosts[:maxReverseDNSHosts]
+	}
+
+	var confirmed, problems []string
+	for _, host := range hosts {
+		ip, err := firstIP(ctx, host)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s doesn't resolve", host))
+			continue
+		}
+		name, err := confirmPTR(ctx, ip)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s (%s) %v", host, ip, err))
+			continue
+		}
+		confirmed = append(confirmed, fmt.Sprintf("%s → %s", ip, name))
+	}
+	if len(problems) > 0 {
+		e.Found = strings.Join(problems, "; ")
+		e.Fix = "Ask the mail host's provider for a PTR record that resolves back to the same address"
+		return e
+	}
+	e.Passed = true
+	e.Found = strings.Join(confirmed, ", ")
+	return e
+}
+
+func firstIP(ctx Context, host string) (net.IP, error) {
+	lookupCtx, cancel := context.WithTimeout(ctx.reqContext(), dnsLookupTimeout)
+	defer cancel()
+	countNetworkCall()
+	ips, err := netutil.LookupIPAddr(lookupCtx, host)
+	if err != nil {
+		return nil, err
+	}
+	for _, ip := range ips {
+		if ip.IP.To4() != nil {
+			return ip.IP, nil
+		}
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no addresses for %s", host)
+	}
+	return ips[0].IP, nil
+}
+
+// confirmPTR returns the PTR name of ip that resolves back to ip.
+func confirmPTR(ctx Context, ip net.IP) (string, error) {
+	lookupCtx, cancel := context.WithTimeout(ctx.reqContext(), dnsLookupTimeout)
+	defer cancel()
+	countNetworkCall()
+	names, err := netutil.LookupAddr(lookupCtx, ip.String())
+	if err != nil || len(names) == 0 {
+		return "", fmt.Errorf("has no PTR record")
+	}
+	for _, name := range names {
+		name = strings.TrimSuffix(name, ".")
+		countNetworkCall()
+		addrs, err := netutil.LookupIPAddr(lookupCtx, name)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if addr.IP.Equal(ip) {
+				return name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("has PTR %s, which doesn't resolve back to it", strings.TrimSuffix(names[0], "."))
+}
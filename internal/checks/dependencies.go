@@ -0,0 +1,292 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// DependenciesCheck reports how many packages the project pulls in,
+// direct and in total, and how much they take up installed. It's
+// informational: past the thresholds it fails at info severity, a nudge
+// to prune before the first production deploy rather than a blocker.
+// Every dependency is code that ships, gets audited and gets updated.
+type DependenciesCheck struct{}
+
+func (c DependenciesCheck) ID() string {
+	return "dependencies"
+}
+
+func (c DependenciesCheck) Title() string {
+	return "Dependency footprint"
+}
+
+// Default thresholds; see config.DependenciesConfig.
+const (
+	defaultMaxDirectDeps = 100
+	defaultMaxTotalDeps  = 1500
+	defaultMaxInstallMB  = 500
+)
+
+// dependencyInstallDirs are where packages are installed, by ecosystem.
+var dependencyInstallDirs = []string{"node_modules", "vendor", ".venv"}
+
+var (
+	reGemfileGem    = regexp.MustCompile(`(?m)^\s*gem\s+["']`)
+	reGemLockSpec   = regexp.MustCompile(`(?m)^    [^\s(]+ \(`)
+	reTOMLPackage   = regexp.MustCompile(`(?m)^\[\[package\]\]`)
+	reRequirement   = regexp.MustCompile(`(?m)^\s*[A-Za-z0-9][\w.-]*\s*(?:\[|[=<>!~;]|$)`)
+	rePnpmLockEntry = regexp.MustCompile(`^  ['"]?/?[^\s'"]+['"]?:$`)
+)
+
+// depCount is one ecosystem's tally.
+type depCount struct {
+	ecosystem     string
+	direct, total int
+}
+
+func (c DependenciesCheck) Run(ctx Context) (CheckResult, error) {
+	maxDirect, maxTotal, maxMB := defaultMaxDirectDeps, defaultMaxTotalDeps, defaultMaxInstallMB
+	if cfg := ctx.Config.Checks.Dependencies; cfg != nil {
+		if cfg.MaxDirect > 0 {
+			maxDirect = cfg.MaxDirect
+		}
+		if cfg.MaxTotal > 0 {
+			maxTotal = cfg.MaxTotal
+		}
+		if cfg.MaxInstallMB > 0 {
+			maxMB = cfg.MaxInstallMB
+		}
+	}
+
+	counts := countDependencies(ctx.RootDir)
+	if len(counts) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No dependency manifests found",
+		}, nil
+	}
+
+	var summary, details, over []string
+	for _, n := range counts {
+		var parts []string
+		if n.direct > 0 || n.total == 0 {
+			parts = append(parts, fmt.Sprintf("%d direct", n.direct))
+		}
+		if n.total > 0 {
+			parts = append(parts, fmt.Sprintf("%d total", n.total))
+		}
+		summary = append(summary, n.ecosystem+": "+strings.Join(parts, ", "))
+		if n.direct > maxDirect {
+			over = append(over, fmt.Sprintf("%s has %d direct dependencies (threshold %d)", n.ecosystem, n.direct, maxDirect))
+		}
+		if n.total > maxTotal {
+			over = append(over, fmt.Sprintf("%s installs %d packages (threshold %d)", n.ecosystem, n.total, maxTotal))
+		}
+	}
+	var installed int64
+	for _, dir := range dependencyInstallDirs {
+		size, ok := dirSize(filepath.Join(ctx.RootDir, dir))
+		if !ok {
+			continue
+		}
+		installed += size
+		details = append(details, fmt.Sprintf("%s/ is %s", dir, formatSize(size)))
+	}
+	if installed > int64(maxMB)*1024*1024 {
+		over = append(over, fmt.Sprintf("installed dependencies take %s (threshold %dMB)", formatSize(installed), maxMB))
+	}
+
+	message := strings.Join(summary, "; ")
+	if installed > 0 {
+		message += "; " + formatSize(installed) + " installed"
+	}
+	if len(over) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  message,
+			Details:  details,
+		}, nil
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   false,
+		Message:  message,
+		Suggestions: append(over,
+			"Remove unused packages (npx knip or depcheck, bundle clean, composer unused) and move build-only ones to dev dependencies",
+			"Raise checks.dependencies.maxDirect, maxTotal or maxInstallMB if the footprint is expected",
+		),
+		Details: details,
+	}, nil
+}
+
+// countDependencies tallies each ecosystem with a manifest at root: the
+// direct dependencies it declares and, when there's a lockfile, every
+// package the lockfile resolves.
+func countDependencies(root string) []depCount {
+	read := func(name string) []byte {
+		data, err := readFile(filepath.Join(root, name))
+		if err != nil {
+			return nil
+		}
+		return data
+	}
+	var counts []depCount
+
+	if data := read("package.json"); data != nil {
+		var pkg struct {
+			Dependencies    map[string]string `json:"dependencies"`
+			DevDependencies map[string]string `json:"devDependencies"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			counts = append(counts, depCount{"npm", len(pkg.Dependencies) + len(pkg.DevDependencies), npmLockCount(read)})
+		}
+	}
+	if data := read("Gemfile"); data != nil {
+		counts = append(counts, depCount{"bundler", len(reGemfileGem.FindAll(data, -1)), len(reGemLockSpec.FindAll(read("Gemfile.lock"), -1))})
+	}
+	if data := read("composer.json"); data != nil {
+		var manifest struct {
+			Require    map[string]string `json:"require"`
+			RequireDev map[string]string `json:"require-dev"`
+		}
+		if json.Unmarshal(data, &manifest) == nil {
+			direct := 0
+			for _, deps := range []map[string]string{manifest.Require, manifest.RequireDev} {
+				for name := range deps {
+					// php and extensions are platform requirements, not packages.
+					if strings.Contains(name, "/") {
+						direct++
+					}
+				}
+			}
+			var lock struct {
+				Packages    []json.RawMessage `json:"packages"`
+				PackagesDev []json.RawMessage `json:"packages-dev"`
+			}
+			_ = json.Unmarshal(read("composer.lock"), &lock)
+			counts = append(counts, depCount{"composer", direct, len(lock.Packages) + len(lock.PackagesDev)})
+		}
+	}
+	if data := read("go.mod"); data != nil {
+		if f, err := modfile.ParseLax("go.mod", data, nil); err == nil {
+			direct := 0
+			for _, r := range f.Require {
+				if !r.Indirect {
+					direct++
+				}
+			}
+			counts = append(counts, depCount{"go", direct, len(f.Require)})
+		}
+	}
+	if data := read("requirements.txt"); data != nil {
+		counts = append(counts, depCount{"pip", len(reRequirement.FindAll(data, -1)), pythonLockCount(read)})
+	} else if read("pyproject.toml") != nil || read("Pipfile") != nil {
+		// Direct dependencies live in TOML tables here; the lockfile
+		// total is what's worth reporting.
+		if total := pythonLockCount(read); total > 0 {
+			counts = append(counts, depCount{"python", 0, total})
+		}
+	}
+	return counts
+}
+
+// npmLockCount counts the packages in whichever JavaScript lockfile is
+// present, or returns 0 without one.
+func npmLockCount(read func(string) []byte) int {
+	if data := read("package-lock.json"); data != nil {
+		var lock struct {
+			Packages     map[string]json.RawMessage `json:"packages"`
+			Dependencies map[string]json.RawMessage `json:"dependencies"`
+		}
+		if json.Unmarshal(data, &lock) == nil {
+			if len(lock.Packages) > 0 {
+				n := 0
+				for key := range lock.Packages {
+					if strings.Contains(key, "node_modules/") {
+						n++
+					}
+				}
+				return n
+			}
+			return len(lock.Dependencies)
+		}
+	}
+	if data := read("pnpm-lock.yaml"); data != nil {
+		n, inPackages := 0, false
+		for _, line := range strings.Split(string(data), "\n") {
+			if !strings.HasPrefix(line, " ") && line != "" {
+				inPackages = line == "packages:"
+				continue
+			}
+			if inPackages && rePnpmLockEntry.MatchString(line) {
+				n++
+			}
+		}
+		return n
+	}
+	if data := read("yarn.lock"); data != nil {
+		n := 0
+		for _, line := range strings.Split(string(data), "\n") {
+			if line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "#") &&
+				strings.HasSuffix(line, ":") && line != "__metadata:" {
+				n++
+			}
+		}
+		return n
+	}
+	return 0
+}
+
+// pythonLockCount counts the packages in poetry.lock, uv.lock or
+// Pipfile.lock, or returns 0 without one.
+func pythonLockCount(read func(string) []byte) int {
+	for _, name := range []string{"poetry.lock", "uv.lock"} {
+		if data := read(name); data != nil {
+			return len(reTOMLPackage.FindAll(data, -1))
+		}
+	}
+	if data := read("Pipfile.lock"); data != nil {
+		var lock struct {
+			Default map[string]json.RawMessage `json:"default"`
+			Develop map[string]json.RawMessage `json:"develop"`
+		}
+		if json.Unmarshal(data, &lock) == nil {
+			return len(lock.Default) + len(lock.Develop)
+		}
+	}
+	return 0
+}
+
+// dirSize returns the total size of the regular files under dir, and
+// false when dir doesn't exist.
+func dirSize(dir string) (int64, bool) {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return 0, false
+	}
+	var total int64
+	_ = walkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, true
+}
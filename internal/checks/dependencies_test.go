@@ -0,0 +1,78 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestDependenciesCheck(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"package.json": `{"dependencies": {"next": "15.0.0", "react": "19.0.0"}, "devDependencies": {"typescript": "5.6.0"}}`,
+		"package-lock.json": `{"lockfileVersion": 3, "packages": {
+			"": {"name": "app"},
+			"node_modules/next": {}, "node_modules/react": {}, "node_modules/typescript": {},
+			"node_modules/next/node_modules/postcss": {}}}`,
+		"Gemfile":                          "source \"https://rubygems.org\"\ngem \"rails\"\ngem 'pg'\n# gem \"redis\"\n",
+		"Gemfile.lock":                     "GEM\n  remote: https://rubygems.org/\n  specs:\n    pg (1.5.4)\n    rails (7.1.0)\n      actionpack (= 7.1.0)\n    actionpack (7.1.0)\n\nDEPENDENCIES\n  pg\n  rails\n",
+		"go.mod":                           "module example.com/app\n\ngo 1.22\n\nrequire (\n\tgithub.com/spf13/cobra v1.8.0\n\tgithub.com/spf13/pflag v1.0.5 // indirect\n)\n",
+		"node_modules/next/dist/server.js": strings.Repeat("x", 2048),
+	})
+	run := func(cfg *config.DependenciesConfig) CheckResult {
+		t.Helper()
+		result, err := DependenciesCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{Checks: config.ChecksConfig{Dependencies: cfg}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	result := run(nil)
+	if !result.Passed || result.Severity != SeverityInfo {
+		t.Fatalf("expected an info pass, got %v %q %v", result.Passed, result.Message, result.Suggestions)
+	}
+	if want := "npm: 3 direct, 4 total; bundler: 2 direct, 3 total; go: 1 direct, 2 total; 2KB installed"; result.Message != want {
+		t.Errorf("message = %q, want %q", result.Message, want)
+	}
+	if len(result.Details) != 1 || result.Details[0] != "node_modules/ is 2KB" {
+		t.Errorf("details = %v", result.Details)
+	}
+
+	result = run(&config.DependenciesConfig{MaxDirect: 2, MaxTotal: 3})
+	if result.Passed || result.Severity != SeverityInfo {
+		t.Fatalf("expected an info failure, got %v %v", result.Passed, result.Severity)
+	}
+	suggestions := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{
+		"npm has 3 direct dependencies (threshold 2)",
+		"npm installs 4 packages (threshold 3)",
+	} {
+		if !strings.Contains(suggestions, want) {
+			t.Errorf("missing %q in %v", want, result.Suggestions)
+		}
+	}
+	if strings.Contains(suggestions, "bundler") || strings.Contains(suggestions, "go has") {
+		t.Errorf("flagged an ecosystem within thresholds: %v", result.Suggestions)
+	}
+}
+
+func TestCountDependenciesLockfiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"package.json": `{"dependencies": {"vue": "3.4.0"}}`,
+		"yarn.lock": "# yarn lockfile v1\n\n\"vue@^3.4.0\":\n  version \"3.4.0\"\n  dependencies:\n    \"@vue/shared\" \"3.4.0\"\n\n" +
+			"\"@vue/shared@3.4.0\":\n  version \"3.4.0\"\n",
+		"composer.json":    `{"require": {"php": "^8.2", "ext-json": "*", "laravel/framework": "^11.0"}, "require-dev": {"phpunit/phpunit": "^11.0"}}`,
+		"composer.lock":    `{"packages": [{"name": "laravel/framework"}, {"name": "symfony/console"}], "packages-dev": [{"name": "phpunit/phpunit"}]}`,
+		"requirements.txt": "# web\nDjango>=5.0\nrequests[socks]==2.32.0\n-r base.txt\n\ngunicorn\n",
+	})
+	got := map[string]depCount{}
+	for _, n := range countDependencies(dir) {
+		got[n.ecosystem] = n
+	}
+	for _, want := range []depCount{{"npm", 1, 2}, {"composer", 2, 3}, {"pip", 3, 0}} {
+		if got[want.ecosystem] != want {
+			t.Errorf("%s = %+v, want %+v", want.ecosystem, got[want.ecosystem], want)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+// DeployedVersionCheck compares the version or commit staging and
+// production report from a version or health endpoint with the local
+// checkout's, catching a launch announced for v2.3 while production
+// still runs v2.1.
+type DeployedVersionCheck struct{}
+
+func (c DeployedVersionCheck) ID() string {
+	return "deployed_version"
+}
+
+func (c DeployedVersionCheck) Title() string {
+	return "Deployed version"
+}
+
+// deployedVersionPaths are the endpoints tried when none is configured,
+// in order; health endpoints often include the build.
+var deployedVersionPaths = []string{"/version", "/api/version", "/version.json", "/health", "/healthz", "/api/health", "/status"}
+
+// deployedVersionKeys are the JSON keys, compared case-insensitively, that
+// hold the deployed version or commit.
+var deployedVersionKeys = map[string]bool{
+	"version": true, "appversion": true, "app_version": true, "release": true, "build": true,
+	"commit": true, "sha": true, "gitcommit": true, "git_commit": true, "gitsha": true,
+	"git_sha": true, "commit_sha": true, "revision": true,
+}
+
+// reDeployedCommit matches an abbreviated or full git commit hash.
+var reDeployedCommit = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+
+// deployedBuild is what an environment reports running.
+type deployedBuild struct {
+	version string
+	commit  string
+}
+
+func (c DeployedVersionCheck) Run(ctx Context) (CheckResult, error) {
+	cfg := ctx.Config.Checks.DeployedVersion
+	if cfg == nil {
+		cfg = &config.DeployedVersionConfig{}
+	}
+
+	local := map[string]string{}
+	if v, _, _ := launchVersion(ctx, "package.json"); v != "" {
+		local[v] = "package.json"
+	}
+	if v, tag, _ := launchVersion(ctx, "git"); v != "" {
+		if _, ok := local[v]; !ok {
+			local[v] = "git tag " + tag
+		}
+	}
+	var head string
+	if out, err := ctx.git("rev-parse", "HEAD"); err == nil {
+		head = strings.TrimSpace(out)
+	}
+	if len(local) == 0 && head == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No local version or git commit to compare against, skipping",
+		}, nil
+	}
+
+	paths := deployedVersionPaths
+	if cfg.Path != "" {
+		paths = []string{"/" + strings.TrimPrefix(cfg.Path, "/")}
+	}
+
+	var mismatches, unreported, details []string
+	for _, env := range []struct{ name, base string }{
+		{"staging", ctx.Config.URLs.Staging},
+		{"production", ctx.Config.URLs.Production},
+	} {
+		if env.base == "" {
+			continue
+		}
+		build, url := c.fetchBuild(ctx, strings.TrimSuffix(env.base, "/"), paths, cfg.Field)
+		if build == (deployedBuild{}) {
+			unreported = append(unreported, env.name)
+			continue
+		}
+		if problem := compareBuild(build, local, head); problem != "" {
+			mismatches = append(mismatches, env.name+" "+problem)
+		} else {
+			details = append(details, fmt.Sprintf("%s runs %s (%s)", env.name, build, url))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  strings.Join(mismatches, "; "),
+			Suggestions: []string{
+				"Deploy the release before announcing it, or check the deploy pipeline finished",
+				"Tag the release locally (git tag) if it's already deployed from another checkout",
+			},
+			Details: details,
+		}, nil
+	}
+	if len(unreported) > 0 {
+		where := "at " + paths[0]
+		if len(paths) > 1 {
+			where = "on any of " + strings.Join(paths, ", ")
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  fmt.Sprintf("No version or commit reported by %s %s", strings.Join(unreported, " or "), where),
+			Suggestions: []string{
+				`Serve the build from /version, e.g. {"version": "2.3.0", "commit": "<git sha>"}`,
+				"Set checks.deployedVersion.path and field if the endpoint lives elsewhere",
+			},
+			Details: details,
+		}, nil
+	}
+	message := "Deployed version matches the local checkout"
+	if len(details) == 1 {
+		message = strings.ToUpper(details[0][:1]) + details[0][1:]
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   true,
+		Message:  message,
+		Details:  details,
+	}, nil
+}
+
+// fetchBuild returns what the first of paths under base that answers 200
+// with a version or commit reports, and the URL it came from.
+func (c DeployedVersionCheck) fetchBuild(ctx Context, base string, paths []string, field string) (deployedBuild, string) {
+	if ctx.Client == nil {
+		return deployedBuild{}, ""
+	}
+	for _, path := range paths {
+		resp, url, err := tryURL(ctx.reqContext(), ctx.Client, base+path)
+		if err != nil {
+			continue
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK {
+			continue
+		}
+		if build := parseDeployedBuild(body, field); build != (deployedBuild{}) {
+			return build, url
+		}
+	}
+	return deployedBuild{}, ""
+}
+
+// parseDeployedBuild reads the version and commit from an endpoint's
+// response: the value at field, the version-like keys of a JSON body, or
+// a short plain-text body such as "v2.3.0" or a commit hash.
+func parseDeployedBuild(body []byte, field string) deployedBuild {
+	var values []string
+	var doc any
+	if json.Unmarshal(body, &doc) == nil {
+		if field != "" {
+			if v, ok := jsonPathValue(doc, field); ok {
+				values = append(values, fmt.Sprint(v))
+			}
+		} else {
+			values = deployedVersionValues(doc, 0)
+		}
+	} else if text := strings.TrimSpace(string(body)); len(text) <= 200 && !strings.Contains(text, "<") {
+		values = []string{text}
+	}
+
+	var build deployedBuild
+	for _, v := range values {
+		if build.commit == "" {
+			if sha := reDeployedCommit.FindString(strings.ToLower(v)); sha != "" && strings.Trim(sha, "0123456789") != "" {
+				build.commit = sha
+				continue
+			}
+		}
+		if build.version == "" {
+			build.version = reVersionNumber.FindString(v)
+		}
+	}
+	return build
+}
+
+// deployedVersionValues collects the values of version-like keys in doc,
+// nested objects included, with shallower keys first.
+func deployedVersionValues(doc any, depth int) []string {
+	obj, ok := doc.(map[string]any)
+	if !ok || depth > 3 {
+		return nil
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var values, nested []string
+	for _, k := range keys {
+		switch v := obj[k].(type) {
+		case string:
+			if deployedVersionKeys[strings.ToLower(k)] {
+				values = append(values, v)
+			}
+		case float64:
+			if deployedVersionKeys[strings.ToLower(k)] {
+				values = append(values, fmt.Sprint(v))
+			}
+		case map[string]any:
+			nested = append(nested, deployedVersionValues(v, depth+1)...)
+		}
+	}
+	return append(values, nested...)
+}
+
+// compareBuild says how build differs from the local versions and HEAD
+// commit, or returns "" when it matches. A matching commit or version is
+// enough; the other may be missing or formatted differently.
+func compareBuild(build deployedBuild, local map[string]string, head string) string {
+	if build.commit != "" && head != "" && strings.HasPrefix(head, build.commit) {
+		return ""
+	}
+	if build.version != "" {
+		if _, ok := local[build.version]; ok {
+			return ""
+		}
+	}
+	if build.version != "" && len(local) > 0 {
+		versions := make([]string, 0, len(local))
+		for v, from := range local {
+			versions = append(versions, v+" ("+from+")")
+		}
+		sort.Strings(versions)
+		return fmt.Sprintf("reports %s, local is %s", build.version, strings.Join(versions, ", "))
+	}
+	if build.commit != "" && head != "" {
+		return fmt.Sprintf("runs commit %s, local HEAD is %s", build.commit, head[:min(len(build.commit), len(head))])
+	}
+	// Nothing local to compare the reported value against.
+	return ""
+}
+
+func (b deployedBuild) String() string {
+	switch {
+	case b.version != "" && b.commit != "":
+		return b.version + " at " + b.commit
+	case b.version != "":
+		return b.version
+	}
+	return "commit " + b.commit
+}
@@ -0,0 +1,90 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestParseDeployedBuild(t *testing.T) {
+	for _, tc := range []struct {
+		body, field     string
+		version, commit string
+	}{
+		{`{"version": "v2.3.0", "commit": "4f2a9c1"}`, "", "2.3.0", "4f2a9c1"},
+		{`{"status": "ok", "build": {"gitSha": "4F2A9C1D"}}`, "", "", "4f2a9c1d"},
+		{`{"status": "ok", "uptime": 1234}`, "", "", ""},
+		{`{"meta": {"release": "web@1.4.0"}}`, "meta.release", "1.4.0", ""},
+		{`{"version": "2.3.0"}`, "meta.release", "", ""},
+		{"v2.1.0\n", "", "2.1.0", ""},
+		{"<html><body>2.1.0</body></html>", "", "", ""},
+	} {
+		got := parseDeployedBuild([]byte(tc.body), tc.field)
+		if got.version != tc.version || got.commit != tc.commit {
+			t.Errorf("parseDeployedBuild(%q, %q) = %+v, want version %q commit %q", tc.body, tc.field, got, tc.version, tc.commit)
+		}
+	}
+}
+
+func TestDeployedVersionCheck(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"README.md": "app"})
+	initGitRepo(t, dir)
+	gitCommit(t, dir, "README.md")
+	if out, err := exec.Command("git", "-C", dir, "tag", "v2.3.0").CombinedOutput(); err != nil {
+		t.Fatalf("git tag: %v\n%s", err, out)
+	}
+	head, _ := runGit(dir, "rev-parse", "HEAD")
+	head = strings.TrimSpace(head)
+
+	responses := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.Host+r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	cfg.Checks.DeployedVersion = &config.DeployedVersionConfig{Enabled: true}
+	run := func() CheckResult {
+		t.Helper()
+		result, err := DeployedVersionCheck{}.Run(Context{RootDir: dir, Config: cfg, Client: srv.Client()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	// A health endpoint without the build is passed over for the next.
+	responses[host+"/health"] = `{"status": "ok"}`
+	responses[host+"/healthz"] = `{"version": "2.1.0"}`
+	result := run()
+	if result.Passed || !strings.Contains(result.Message, "production reports 2.1.0, local is 2.3.0 (git tag v2.3.0)") {
+		t.Errorf("expected a version mismatch, got %v %q", result.Passed, result.Message)
+	}
+
+	responses[host+"/healthz"] = `{"version": "2.1.0", "commit": "` + head[:12] + `"}`
+	if result := run(); !result.Passed {
+		t.Errorf("expected the matching commit to pass, got %q", result.Message)
+	}
+
+	cfg.Checks.DeployedVersion.Path = "/internal/build"
+	result = run()
+	if result.Passed || !strings.Contains(result.Message, "No version or commit reported by production at /internal/build") {
+		t.Errorf("expected a missing endpoint warning, got %v %q", result.Passed, result.Message)
+	}
+
+	responses[host+"/internal/build"] = "v2.3.0"
+	if result := run(); !result.Passed || result.Message != "Production runs 2.3.0 ("+srv.URL+"/internal/build)" {
+		t.Errorf("expected pass, got %v %q", result.Passed, result.Message)
+	}
+}
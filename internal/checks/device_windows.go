@@ -0,0 +1,12 @@
+//go:build windows
+
+package checks
+
+import "io/fs"
+
+// deviceID reports no filesystem ID on Windows, where walk.crossMounts has
+// no effect: drives are separate roots, and mounted folders are rare in
+// project trees.
+func deviceID(fs.FileInfo) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,92 @@
+package checks
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Diagnostic is a path the scan couldn't read, or that the walk policy
+// kept it out of. Checks skip such paths and carry on, so a scan with
+// diagnostics may have missed findings in them.
+type Diagnostic struct {
+	Path  string `json:"path"`  // relative to the project root, forward slashes
+	Error string `json:"error"` // e.g. "permission denied"
+}
+
+// unreadable collects the paths walks and reads failed on during a scan,
+// keyed by absolute path so a directory every check walks is reported once.
+var unreadable struct {
+	sync.Mutex
+	paths map[string]string
+}
+
+// recordUnreadable notes that path couldn't be read. Nil errors and
+// missing files are ignored: checks probe for plenty of files that
+// needn't exist, and a file deleted mid-scan isn't a visibility problem.
+func recordUnreadable(path string, err error) {
+	if err == nil || errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	msg := err.Error()
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		msg = pathErr.Err.Error()
+	}
+	unreadable.Lock()
+	defer unreadable.Unlock()
+	if unreadable.paths == nil {
+		unreadable.paths = map[string]string{}
+	}
+	if _, seen := unreadable.paths[path]; !seen {
+		unreadable.paths[path] = msg
+	}
+}
+
+// ResetDiagnostics clears what earlier scans in this process recorded.
+// Scans run one at a time; call it before each.
+func ResetDiagnostics() {
+	unreadable.Lock()
+	defer unreadable.Unlock()
+	unreadable.paths = nil
+}
+
+// Diagnostics returns the paths recorded since ResetDiagnostics, relative
+// to rootDir and sorted.
+func Diagnostics(rootDir string) []Diagnostic {
+	unreadable.Lock()
+	defer unreadable.Unlock()
+	out := make([]Diagnostic, 0, len(unreadable.paths))
+	for abs, msg := range unreadable.paths {
+		rel := filepath.ToSlash(relPath(rootDir, abs))
+		if rel == "." {
+			rel = "./"
+		}
+		out = append(out, Diagnostic{Path: rel, Error: msg})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+
+	// A file inside an unreadable directory (a .gitignore probed before
+	// the directory listing failed) adds nothing to the directory's entry.
+	listed := make(map[string]bool, len(out))
+	kept := out[:0]
+	for _, d := range out {
+		listed[d.Path] = true
+		if !underListed(d.Path, listed) {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+func underListed(p string, listed map[string]bool) bool {
+	for dir := path.Dir(p); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if listed[dir] {
+			return true
+		}
+	}
+	return false
+}
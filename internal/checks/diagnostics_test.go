@@ -0,0 +1,39 @@
+package checks
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiagnostics(t *testing.T) {
+	ResetDiagnostics()
+	defer ResetDiagnostics()
+	root := t.TempDir()
+	denied := func(rel string) error {
+		return &fs.PathError{Op: "open", Path: filepath.Join(root, rel), Err: fs.ErrPermission}
+	}
+
+	recordUnreadable(filepath.Join(root, "locked", ".gitignore"), denied("locked/.gitignore"))
+	recordUnreadable(filepath.Join(root, "locked"), denied("locked"))
+	recordUnreadable(filepath.Join(root, "locked"), denied("locked"))
+	recordUnreadable(filepath.Join(root, "a.env"), fmt.Errorf("read: %w", os.ErrNotExist))
+	recordUnreadable(filepath.Join(root, "z.html"), denied("z.html"))
+	recordUnreadable(filepath.Join(root, "ok.html"), nil)
+
+	want := []Diagnostic{
+		{Path: "locked", Error: "permission denied"},
+		{Path: "z.html", Error: "permission denied"},
+	}
+	if got := Diagnostics(root); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diagnostics = %+v, want %+v", got, want)
+	}
+
+	ResetDiagnostics()
+	if got := Diagnostics(root); len(got) != 0 {
+		t.Errorf("Diagnostics after reset = %+v", got)
+	}
+}
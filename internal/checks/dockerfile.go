@@ -0,0 +1,375 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DockerfileCheck lints the project's Dockerfiles for what matters once
+// the image runs in production: the final stage runs as a non-root user,
+// has a HEALTHCHECK and leaves dev dependencies out; base images are
+// pinned to a tag rather than latest; no secret is baked in through ENV
+// or ARG; and a .dockerignore keeps .env and .git out of the build
+// context. Builder stages are only checked for base image tags, since
+// nothing else in them ships.
+type DockerfileCheck struct{}
+
+func (c DockerfileCheck) ID() string {
+	return "dockerfile"
+}
+
+func (c DockerfileCheck) Title() string {
+	return "Dockerfile"
+}
+
+var (
+	// reDockerSecretKey matches ENV and ARG names that hold credentials.
+	// Names ending in _FILE or _PATH point at a mounted secret instead.
+	reDockerSecretKey = regexp.MustCompile(`(?i)(secret|passw(or)?d|token|api_?key|private_?key|access_?key|credentials?)`)
+	reDockerSecretRef = regexp.MustCompile(`(?i)_(file|path|dir)$`)
+	// reDockerDevInstall matches installs that pull in dev dependencies
+	// unless told otherwise.
+	reDockerDevInstall = regexp.MustCompile(`\b(npm (?:ci|install|i)|yarn(?: install)?|pnpm (?:install|i)|bundle install|composer install)\b`)
+	// reBareYarn matches yarn run on its own, which installs.
+	reBareYarn = regexp.MustCompile(`\byarn\s*(?:$|&&|;|--)`)
+)
+
+// dockerInstruction is one instruction, with continuation lines joined.
+type dockerInstruction struct {
+	cmd  string // upper case
+	args string
+	line int
+}
+
+// dockerStage is a FROM and the instructions after it.
+type dockerStage struct {
+	image, alias string
+	line         int
+	instructions []dockerInstruction
+}
+
+func (c DockerfileCheck) Run(ctx Context) (CheckResult, error) {
+	files := ctx.files()
+	ignoreFiles := map[string]bool{}
+	var dockerfiles []FileEntry
+	for _, f := range files.Files {
+		if f.Name == ".dockerignore" || strings.HasSuffix(f.Name, ".dockerignore") {
+			ignoreFiles[f.Path] = true
+		}
+		if f.Ignored || f.inDir(vendorSkipDirs) || !isDockerfile(f.Name) {
+			continue
+		}
+		dockerfiles = append(dockerfiles, f)
+	}
+	if len(dockerfiles) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No Dockerfile found",
+		}, nil
+	}
+
+	var findings []string
+	var locations []Location
+	kinds := map[string]bool{}
+	report := func(kind string, loc Location) {
+		kinds[kind] = true
+		findings = append(findings, loc.String())
+		locations = append(locations, loc)
+	}
+	for _, f := range dockerfiles {
+		content, err := readFile(files.Abs(f))
+		if err != nil {
+			continue
+		}
+		stages := parseDockerfile(string(content))
+		if len(stages) == 0 {
+			continue
+		}
+		// The build context is usually the Dockerfile's directory or the
+		// project root; BuildKit also reads <Dockerfile>.dockerignore.
+		dir := path.Dir(f.Path)
+		if !ignoreFiles[".dockerignore"] && !ignoreFiles[path.Join(dir, ".dockerignore")] && !ignoreFiles[f.Path+".dockerignore"] {
+			report("dockerignore", Location{File: f.Path, Message: "no .dockerignore, so .env, .git and node_modules go into the build context"})
+		}
+
+		aliases := map[string]int{}
+		for i, s := range stages {
+			if tag := untaggedImage(s.image, aliases); tag != "" {
+				report("latest", Location{File: f.Path, Line: s.line, Message: tag})
+			}
+			if s.alias != "" {
+				aliases[strings.ToLower(s.alias)] = i
+			}
+			for _, in := range s.instructions {
+				if in.cmd != "ENV" && in.cmd != "ARG" {
+					continue
+				}
+				for _, kv := range dockerKeyValues(in.cmd, in.args) {
+					if !reDockerSecretKey.MatchString(kv[0]) || reDockerSecretRef.MatchString(kv[0]) {
+						continue
+					}
+					// A literal value is a secret baked in; an ARG, or an ENV
+					// copying one, puts whatever the build passes in the
+					// image history. Short, numeric and placeholder values
+					// (TOKEN_TTL=3600) are settings, not secrets.
+					value := kv[1]
+					switch {
+					case (value == "" && in.cmd == "ARG") || strings.HasPrefix(value, "$"):
+						report("secret", Location{File: f.Path, Line: in.line, Message: in.cmd + " " + kv[0] + " is recorded in the image history; use a build secret (RUN --mount=type=secret) or set it at runtime"})
+					case len(value) >= 8 && strings.Trim(value, "0123456789") != "" && !envPlaceholder.MatchString(value):
+						report("secret_value", Location{File: f.Path, Line: in.line, Message: in.cmd + " " + kv[0] + " sets a secret in the image"})
+					}
+				}
+			}
+		}
+
+		// A final stage built FROM an earlier one inherits its USER,
+		// HEALTHCHECK and installs, so walk the chain from its root.
+		final := stages[len(stages)-1]
+		chain := []dockerStage{final}
+		for at := len(stages) - 1; ; {
+			i, ok := aliases[strings.ToLower(chain[0].image)]
+			if !ok || i >= at {
+				break
+			}
+			chain = append([]dockerStage{stages[i]}, chain...)
+			at = i
+		}
+		var instructions []dockerInstruction
+		for _, s := range chain {
+			instructions = append(instructions, s.instructions...)
+		}
+		user, healthcheck, prodEnv := "", false, false
+		for _, in := range instructions {
+			switch in.cmd {
+			case "USER":
+				user = strings.Fields(in.args + " ")[0]
+			case "HEALTHCHECK":
+				healthcheck = true
+			case "ENV":
+				for _, kv := range dockerKeyValues(in.cmd, in.args) {
+					if (kv[0] == "NODE_ENV" && kv[1] == "production") || kv[0] == "BUNDLE_WITHOUT" || kv[0] == "BUNDLE_DEPLOYMENT" {
+						prodEnv = true
+					}
+				}
+			case "RUN":
+				if dev := devInstall(in.args, prodEnv); dev != "" {
+					report("dev_deps", Location{File: f.Path, Line: in.line, Message: dev + " in the final stage installs dev dependencies"})
+				}
+			}
+		}
+		if runsAsRoot(user, chain[0].image) {
+			report("root", Location{File: f.Path, Line: final.line, Message: "the final stage runs as root"})
+		}
+		if !healthcheck {
+			report("healthcheck", Location{File: f.Path, Line: final.line, Message: "the final stage has no HEALTHCHECK"})
+		}
+	}
+
+	if len(findings) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("%d Dockerfile(s) run as non-root with a HEALTHCHECK, pinned bases and no baked-in secrets", len(dockerfiles)),
+		}, nil
+	}
+
+	severity := SeverityWarn
+	var found, suggestions []string
+	if kinds["secret_value"] {
+		severity = SeverityError
+		found = append(found, "secrets set by ENV or ARG")
+		suggestions = append(suggestions, "Remove secrets from the Dockerfile and rotate them: anyone who can pull the image can read its ENV and ARG values")
+	}
+	if kinds["root"] {
+		found = append(found, "a final stage running as root")
+		suggestions = append(suggestions, "Add a USER instruction for an unprivileged user (USER node, or create one with adduser) before the CMD")
+	}
+	if kinds["healthcheck"] {
+		found = append(found, "no HEALTHCHECK")
+		suggestions = append(suggestions, "Add a HEALTHCHECK that requests your health endpoint, so the runtime restarts a container that's up but not serving")
+	}
+	if kinds["latest"] {
+		found = append(found, "unpinned base images")
+		suggestions = append(suggestions, "Pin base images to a version tag (node:22-alpine), or a digest, so rebuilds don't change under you")
+	}
+	if kinds["secret"] {
+		found = append(found, "secret names in ENV or ARG")
+		suggestions = append(suggestions, "Pass build-time secrets with RUN --mount=type=secret, and runtime ones through the platform's secret store")
+	}
+	if kinds["dev_deps"] {
+		found = append(found, "dev dependencies in the final stage")
+		suggestions = append(suggestions, "Install production dependencies only in the final stage (npm ci --omit=dev, composer install --no-dev, BUNDLE_WITHOUT=development:test)")
+	}
+	if kinds["dockerignore"] {
+		found = append(found, "no .dockerignore")
+		suggestions = append(suggestions, "Add a .dockerignore listing .env*, .git and node_modules")
+	}
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    severity,
+		Passed:      false,
+		Message:     "Found " + strings.Join(found, ", "),
+		Suggestions: append(suggestions, limitFindings(findings, 8)...),
+		Locations:   locations,
+	}, nil
+}
+
+// isDockerfile reports whether name is a Dockerfile: Dockerfile,
+// Dockerfile.prod, app.dockerfile or a Podman Containerfile.
+func isDockerfile(name string) bool {
+	lower := strings.ToLower(name)
+	return lower == "dockerfile" || lower == "containerfile" || strings.HasPrefix(lower, "dockerfile.") ||
+		strings.HasSuffix(lower, ".dockerfile")
+}
+
+// parseDockerfile splits a Dockerfile into stages, joining continuation
+// lines and dropping comments. Instructions before the first FROM (a
+// global ARG) are left out.
+func parseDockerfile(content string) []dockerStage {
+	var stages []dockerStage
+	var current strings.Builder
+	start := 0
+	flush := func() {
+		text := strings.TrimSpace(current.String())
+		current.Reset()
+		if text == "" {
+			return
+		}
+		cmd, args, _ := strings.Cut(text, " ")
+		in := dockerInstruction{cmd: strings.ToUpper(cmd), args: strings.TrimSpace(args), line: start}
+		if in.cmd == "FROM" {
+			s := dockerStage{line: in.line}
+			fields := strings.Fields(in.args)
+			for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+				fields = fields[1:] // --platform=...
+			}
+			if len(fields) > 0 {
+				s.image = fields[0]
+			}
+			if len(fields) == 3 && strings.EqualFold(fields[1], "as") {
+				s.alias = fields[2]
+			}
+			stages = append(stages, s)
+			return
+		}
+		if len(stages) > 0 {
+			last := &stages[len(stages)-1]
+			last.instructions = append(last.instructions, in)
+		}
+	}
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if current.Len() == 0 {
+			start = i + 1
+		}
+		if cont, ok := strings.CutSuffix(trimmed, `\`); ok {
+			current.WriteString(cont + " ")
+			continue
+		}
+		current.WriteString(trimmed)
+		flush()
+	}
+	flush()
+	return stages
+}
+
+// untaggedImage describes a FROM image that floats, without a tag or on
+// latest, or returns "". Earlier stages, scratch, digests and images
+// named by an ARG are left alone.
+func untaggedImage(image string, aliases map[string]int) string {
+	lower := strings.ToLower(image)
+	if _, ok := aliases[lower]; ok {
+		return ""
+	}
+	if image == "" || lower == "scratch" || strings.Contains(image, "$") || strings.Contains(image, "@") {
+		return ""
+	}
+	name := image
+	if i := strings.LastIndex(image, "/"); i >= 0 {
+		name = image[i+1:]
+	}
+	_, tag, ok := strings.Cut(name, ":")
+	if !ok {
+		return image + " has no tag, so it's latest"
+	}
+	if tag == "latest" {
+		return image + " is unpinned"
+	}
+	return ""
+}
+
+// dockerKeyValues reads the names and values an ENV or ARG sets, in
+// either the KEY=value or the legacy ENV KEY value form.
+func dockerKeyValues(cmd, args string) [][2]string {
+	if cmd == "ENV" && !strings.Contains(strings.Fields(args + " ")[0], "=") {
+		key, value, _ := strings.Cut(args, " ")
+		return [][2]string{{key, strings.Trim(strings.TrimSpace(value), `"'`)}}
+	}
+	var kvs [][2]string
+	for _, field := range strings.Fields(args) {
+		key, value, _ := strings.Cut(field, "=")
+		kvs = append(kvs, [2]string{key, strings.Trim(value, `"'`)})
+	}
+	return kvs
+}
+
+// devInstall returns the install command in a RUN that also installs dev
+// dependencies, or "". prodEnv is set when the stage's ENV already says
+// production (NODE_ENV=production, BUNDLE_WITHOUT).
+func devInstall(run string, prodEnv bool) string {
+	m := reDockerDevInstall.FindString(run)
+	if m == "" || prodEnv {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(m, "npm"):
+		if strings.Contains(run, "--omit=dev") || strings.Contains(run, "--production") || strings.Contains(run, "--only=prod") {
+			return ""
+		}
+	case strings.HasPrefix(m, "yarn"):
+		if strings.Contains(run, "--production") || strings.Contains(run, "workspaces focus") {
+			return ""
+		}
+		// yarn add, yarn build and the like aren't installs.
+		if m == "yarn" && !reBareYarn.MatchString(run) {
+			return ""
+		}
+	case strings.HasPrefix(m, "pnpm"):
+		if strings.Contains(run, "--prod") || strings.Contains(run, " -P") {
+			return ""
+		}
+	case strings.HasPrefix(m, "bundle"):
+		// bundle install --without, or bundle config set without first.
+		if strings.Contains(run, "without") || strings.Contains(run, "deployment") {
+			return ""
+		}
+	case strings.HasPrefix(m, "composer"):
+		if strings.Contains(run, "--no-dev") {
+			return ""
+		}
+	}
+	return m
+}
+
+// runsAsRoot reports whether the final stage's last USER leaves it as
+// root. Without a USER the image's own default applies: distroless
+// :nonroot images and the like set one.
+func runsAsRoot(user, image string) bool {
+	if user == "" {
+		return !strings.Contains(strings.ToLower(image), "nonroot")
+	}
+	name, _, _ := strings.Cut(user, ":")
+	return name == "root" || name == "0"
+}
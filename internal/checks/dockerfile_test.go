@@ -0,0 +1,149 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDockerfileCheck(t *testing.T) {
+	clean := `# syntax=docker/dockerfile:1
+ARG NODE_VERSION=22
+FROM node:${NODE_VERSION}-alpine AS deps
+RUN npm ci
+
+FROM deps AS build
+RUN npm run build
+
+FROM node:22-alpine
+ENV NODE_ENV=production \
+    TOKEN_TTL=3600
+COPY --from=build /app/dist ./dist
+RUN npm ci --omit=dev
+USER node
+HEALTHCHECK CMD wget -qO- http://localhost:3000/up || exit 1
+CMD ["node", "dist/server.js"]
+`
+	cases := []struct {
+		name     string
+		files    map[string]string
+		passed   bool
+		severity Severity
+		message  []string
+		// locations is the number of locations, and line the line of
+		// the fourth one, the hardcoded secret.
+		locations, line int
+	}{
+		{
+			name:   "clean multi-stage build",
+			files:  map[string]string{"Dockerfile": clean, ".dockerignore": ".env\n.git\n"},
+			passed: true,
+		},
+		{
+			name: "every problem",
+			files: map[string]string{"Dockerfile": `FROM node
+ARG NPM_TOKEN
+ENV API_KEY=sk_live_abcdef123456
+RUN npm ci
+CMD ["node", "server.js"]
+`},
+			severity:  SeverityError,
+			message:   []string{"secrets set by ENV or ARG", "running as root", "no HEALTHCHECK", "unpinned base images", "secret names", "dev dependencies", "no .dockerignore"},
+			locations: 7,
+			line:      3,
+		},
+		{
+			// The final stage inherits USER and HEALTHCHECK from the stage
+			// it's built on, and a distroless nonroot image needs no USER.
+			name: "inherited from an earlier stage",
+			files: map[string]string{
+				"docker/Dockerfile.prod": `FROM ruby:3.3-slim AS base
+USER rails
+HEALTHCHECK CMD curl -f http://localhost/up
+FROM base AS app
+CMD ["bin/rails", "server"]
+`,
+				"docker/.dockerignore":           ".env\n",
+				"worker.dockerfile":              "FROM gcr.io/distroless/static:nonroot\nHEALTHCHECK NONE\n",
+				"worker.dockerfile.dockerignore": ".git\n",
+			},
+			passed: true,
+		},
+		{
+			name:    "no Dockerfile",
+			files:   map[string]string{"README.md": "# app\n"},
+			passed:  true,
+			message: []string{"No Dockerfile found"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := DockerfileCheck{}.Run(Context{RootDir: writeFiles(t, tc.files)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tc.passed {
+				t.Fatalf("passed = %v, want %v: %q %v", result.Passed, tc.passed, result.Message, result.Suggestions)
+			}
+			if !tc.passed && result.Severity != tc.severity {
+				t.Errorf("severity = %v, want %v", result.Severity, tc.severity)
+			}
+			for _, want := range tc.message {
+				if !strings.Contains(result.Message, want) {
+					t.Errorf("message %q missing %q", result.Message, want)
+				}
+			}
+			if tc.locations > 0 && (len(result.Locations) != tc.locations || result.Locations[3].Line != tc.line) {
+				t.Errorf("locations = %+v", result.Locations)
+			}
+		})
+	}
+}
+
+func TestUntaggedImage(t *testing.T) {
+	aliases := map[string]int{"builder": 0}
+	tests := map[string]bool{
+		"node":                      true,
+		"node:latest":               true,
+		"ghcr.io/acme/app":          true,
+		"localhost:5000/app":        true,
+		"localhost:5000/app:1.2":    false,
+		"node:22-alpine":            false,
+		"node@sha256:abc":           false,
+		"scratch":                   false,
+		"builder":                   false,
+		"${BASE_IMAGE}":             false,
+		"python:3.12-slim-bookworm": false,
+	}
+	for image, want := range tests {
+		if got := untaggedImage(image, aliases) != ""; got != want {
+			t.Errorf("untaggedImage(%q) = %v, want %v", image, got, want)
+		}
+	}
+}
+
+func TestDevInstall(t *testing.T) {
+	tests := []struct {
+		run     string
+		prodEnv bool
+		want    string
+	}{
+		{"npm ci", false, "npm ci"},
+		{"npm ci", true, ""},
+		{"npm ci --omit=dev && npm cache clean --force", false, ""},
+		{"yarn install --frozen-lockfile", false, "yarn install"},
+		{"yarn && yarn build", false, "yarn"},
+		{"yarn build", false, ""},
+		{"pnpm install --prod --frozen-lockfile", false, ""},
+		{"bundle config set without 'development test' && bundle install", false, ""},
+		{"bundle install", false, "bundle install"},
+		{"composer install --no-dev --optimize-autoloader", false, ""},
+		{"composer install", false, "composer install"},
+		{"apt-get install -y curl", false, ""},
+	}
+	for _, tt := range tests {
+		if got := devInstall(tt.run, tt.prodEnv); got != tt.want {
+			t.Errorf("devInstall(%q, %v) = %q, want %q", tt.run, tt.prodEnv, got, tt.want)
+		}
+	}
+}
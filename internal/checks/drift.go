@@ -0,0 +1,194 @@
+package checks
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// DriftCheck compares files in the project with what the production URL
+// serves for them, so a deploy that didn't go out (or a hotfix made on the
+// server and never committed) shows up before launch. Only files with a
+// copy on disk are compared; robots.txt generated by a CMS plugin, say,
+// has nothing to drift from.
+type DriftCheck struct{}
+
+func (c DriftCheck) ID() string {
+	return "drift"
+}
+
+func (c DriftCheck) Title() string {
+	return "Deployed files match the repo"
+}
+
+// driftDefaultPaths are compared when preflight.yml doesn't list paths:
+// the static files deploys copy verbatim.
+var driftDefaultPaths = []string{
+	"/robots.txt", "/sitemap.xml", "/llms.txt", "/ads.txt", "/humans.txt",
+	"/.well-known/security.txt",
+}
+
+// driftWebRoots are where a served file's local copy lives, after the
+// stack's build output.
+var driftWebRoots = []string{"public", "static", "web", "www", "dist", "build", "_site", "out", ""}
+
+func (c DriftCheck) Run(ctx Context) (CheckResult, error) {
+	base := strings.TrimSuffix(ctx.Config.URLs.Production, "/")
+	if base == "" || ctx.Client == nil {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No production URL configured, skipping",
+		}, nil
+	}
+
+	paths := driftDefaultPaths
+	if cfg := ctx.Config.Checks.Drift; cfg != nil && len(cfg.Paths) > 0 {
+		paths = cfg.Paths
+	} else if slices.ContainsFunc(ctx.Config.AllStacks(), IsStaticSiteStack) {
+		// Static builds render the homepage deterministically, so it's
+		// worth comparing too. Server-rendered pages carry tokens and
+		// timestamps that would always differ.
+		paths = append([]string{"/"}, paths...)
+	}
+	roots := append(staticOutputDirsFor(ctx.Config.AllStacks()), driftWebRoots...)
+
+	var drifted, details []string
+	compared, unreachable := 0, 0
+	for _, p := range paths {
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		localPath, local, ok := findServedFile(ctx.RootDir, roots, p)
+		if !ok {
+			continue
+		}
+		rel := filepath.ToSlash(relPath(ctx.RootDir, localPath))
+
+		resp, err := doGet(ctx.reqContext(), ctx.Client, base+p)
+		if err != nil {
+			unreachable++
+			details = append(details, fmt.Sprintf("%s - could not fetch: %v", p, err))
+			continue
+		}
+		remote, err := io.ReadAll(io.LimitReader(resp.Body, netutil.MaxResponseBody))
+		resp.Body.Close()
+		if err != nil {
+			unreachable++
+			details = append(details, fmt.Sprintf("%s - could not read response: %v", p, err))
+			continue
+		}
+		compared++
+		if resp.StatusCode != http.StatusOK {
+			drifted = append(drifted, fmt.Sprintf("%s - %s is in the repo but production returns %d", p, rel, resp.StatusCode))
+			continue
+		}
+		if line, ours, theirs, same := firstDifference(local, remote); !same {
+			drifted = append(drifted, fmt.Sprintf("%s - differs from %s at line %d (repo %q, production %q)",
+				p, rel, line, truncate(ours, 60), truncate(theirs, 60)))
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s - matches %s", p, rel))
+	}
+
+	if compared == 0 {
+		msg := "No deployable files found locally to compare"
+		if unreachable > 0 {
+			msg = fmt.Sprintf("Could not reach %s to compare files", base)
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  msg,
+			Details:  details,
+		}, nil
+	}
+	if len(drifted) > 0 {
+		return CheckResult{
+			ID:          c.ID(),
+			Title:       c.Title(),
+			Severity:    SeverityWarn,
+			Passed:      false,
+			Message:     fmt.Sprintf("%d of %d file(s) differ from production", len(drifted), compared),
+			Suggestions: append(drifted, "Redeploy, or commit the changes made on the server"),
+			Details:     details,
+		}, nil
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   true,
+		Message:  fmt.Sprintf("%d file(s) match production", compared),
+		Details:  details,
+	}, nil
+}
+
+// findServedFile finds the local copy of URL path p under the first of
+// roots that has one. Directory paths map to their index.html, and a path
+// with enough .. segments to climb out of the web root has none.
+func findServedFile(rootDir string, roots []string, p string) (string, []byte, bool) {
+	if rel := path.Clean(strings.TrimLeft(p, "/")); rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", nil, false
+	}
+	var names []string
+	if strings.HasSuffix(p, "/") {
+		names = []string{p + "index.html"}
+	} else if path.Ext(p) == "" {
+		names = []string{p + "/index.html", p + ".html"}
+	} else {
+		names = []string{p}
+	}
+	for _, root := range roots {
+		for _, name := range names {
+			full := filepath.Join(rootDir, root, filepath.FromSlash(strings.TrimPrefix(name, "/")))
+			if data, err := readFile(full); err == nil {
+				return full, data, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// firstDifference compares two files line by line, ignoring line endings
+// and trailing whitespace, which deploy pipelines and servers rewrite
+// freely. It returns the first line that differs.
+func firstDifference(a, b []byte) (line int, lineA, lineB string, same bool) {
+	la, lb := normalizedLines(a), normalizedLines(b)
+	for i := 0; i < len(la) || i < len(lb); i++ {
+		var x, y string
+		if i < len(la) {
+			x = la[i]
+		}
+		if i < len(lb) {
+			y = lb[i]
+		}
+		if i >= len(la) || i >= len(lb) || x != y {
+			return i + 1, x, y, false
+		}
+	}
+	return 0, "", "", true
+}
+
+func normalizedLines(data []byte) []string {
+	data = bytes.TrimRight(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")), " \t\r\n")
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.Split(string(data), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t\r")
+	}
+	return lines
+}
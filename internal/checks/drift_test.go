@@ -0,0 +1,124 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestDriftCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.Write([]byte("User-agent: *\r\nDisallow:   \r\n")) // same, modulo line endings
+		case "/llms.txt":
+			w.Write([]byte("# Demo\nOld summary\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	dir := writeFiles(t, map[string]string{
+		"public/robots.txt": "User-agent: *\nDisallow:\n",
+		"public/llms.txt":   "# Demo\nNew summary\n",
+		"public/ads.txt":    "google.com, pub-1, DIRECT\n",
+	})
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	cfg.Checks.Drift = &config.DriftConfig{Enabled: true}
+	result, err := DriftCheck{}.Run(Context{RootDir: dir, Config: cfg, Client: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed || result.Severity != SeverityWarn {
+		t.Fatalf("got passed=%v severity=%v, want a warning", result.Passed, result.Severity)
+	}
+	if result.Message != "2 of 3 file(s) differ from production" {
+		t.Errorf("message = %q", result.Message)
+	}
+	joined := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{
+		`/llms.txt - differs from public/llms.txt at line 2 (repo "New summary", production "Old summary")`,
+		"/ads.txt - public/ads.txt is in the repo but production returns 404",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "robots.txt") || strings.Contains(joined, "sitemap") {
+		t.Errorf("matching and missing files should not be reported:\n%s", joined)
+	}
+}
+
+func TestDriftCheckStaticHomepage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			w.Write([]byte("<h1>Hello</h1>\n"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	dir := writeFiles(t, map[string]string{"_site/index.html": "<h1>Hello</h1>"})
+	cfg := &config.PreflightConfig{Stack: "jekyll"}
+	cfg.URLs.Production = srv.URL
+	cfg.Checks.Drift = &config.DriftConfig{Enabled: true}
+	result, err := DriftCheck{}.Run(Context{RootDir: dir, Config: cfg, Client: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || result.Message != "1 file(s) match production" {
+		t.Errorf("got passed=%v message=%q", result.Passed, result.Message)
+	}
+}
+
+// A configured path can't climb out of the web root: the failure message
+// would quote the file it reached.
+func TestDriftCheckStaysInWebRoot(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("nope\n"))
+	}))
+	defer srv.Close()
+
+	dir := writeFiles(t, map[string]string{
+		"public/robots.txt": "User-agent: *\n",
+		"secret.txt":        "token=hunter2\n",
+	})
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	cfg.Checks.Drift = &config.DriftConfig{Enabled: true, Paths: []string{"/../secret.txt", "/robots.txt/../../secret.txt", "/robots.txt"}}
+	result, err := DriftCheck{}.Run(Context{RootDir: dir, Config: cfg, Client: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Message != "1 of 1 file(s) differ from production" {
+		t.Errorf("message = %q", result.Message)
+	}
+	if joined := strings.Join(result.Suggestions, "\n"); strings.Contains(joined, "secret") {
+		t.Errorf("a file outside the web root was compared:\n%s", joined)
+	}
+}
+
+func TestFirstDifference(t *testing.T) {
+	cases := []struct {
+		a, b string
+		line int
+		same bool
+	}{
+		{"a\nb\n", "a\r\nb", 0, true},
+		{"a\nb", "a\nc", 2, false},
+		{"a", "a\nmore", 2, false},
+		{"", "\n\n", 0, true},
+	}
+	for _, c := range cases {
+		line, _, _, same := firstDifference([]byte(c.a), []byte(c.b))
+		if line != c.line || same != c.same {
+			t.Errorf("firstDifference(%q, %q) = %d, %v; want %d, %v", c.a, c.b, line, same, c.line, c.same)
+		}
+	}
+}
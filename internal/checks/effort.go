@@ -0,0 +1,104 @@
+package checks
+
+// Effort is a rough estimate of the work needed to fix a failing check,
+// shown in the scan summary so teams can size the last pre-launch sprint.
+// The numbers are deliberately coarse: they describe the typical fix, not
+// the worst case.
+type Effort struct {
+	Minutes    int    `json:"minutes"`
+	Difficulty string `json:"difficulty"` // "easy", "medium" or "hard"
+}
+
+// defaultEffort covers IDs without an entry in checkEfforts, which in
+// practice means declared-service checks: wiring up an env var or an SDK
+// init call is usually a short, unremarkable fix.
+var defaultEffort = Effort{Minutes: 20, Difficulty: "medium"}
+
+// checkEfforts holds per-check estimates. Add an entry when adding a
+// check whose typical fix differs noticeably from defaultEffort.
+var checkEfforts = map[string]Effort{
+	// SEO & Social
+	"seoMeta":         {15, "easy"},
+	"canonical":       {10, "easy"},
+	"ogTwitter":       {30, "easy"},
+	"twitter_card":    {15, "easy"},
+	"hreflang":        {30, "medium"},
+	"viewport":        {5, "easy"},
+	"lang":            {5, "easy"},
+	"structured_data": {30, "medium"},
+	"indexNow":        {10, "easy"},
+	"search_console":  {15, "easy"},
+	// Security & Infrastructure
+	"securityHeaders": {45, "medium"},
+	"ssl":             {30, "medium"},
+	"www_redirect":    {15, "easy"},
+	"email_auth":      {30, "medium"},
+	"bimi":            {120, "hard"},
+	"ipv6":            {60, "medium"},
+	"cloudflare_zone": {15, "easy"},
+	"app_links":       {30, "medium"},
+	"bot_protection":  {30, "medium"},
+	"rate_limit":      {45, "medium"},
+	"secrets":         {60, "hard"}, // rotating a leaked key, not just deleting it
+	// Environment & Health
+	"envParity":        {10, "easy"},
+	"healthEndpoint":   {30, "medium"},
+	"routes":           {30, "medium"},
+	"auth_routes":      {30, "medium"},
+	"smoke":            {30, "medium"},
+	"drift":            {15, "easy"}, // usually a redeploy
+	"mirrors":          {30, "medium"},
+	"ops_readiness":    {60, "medium"},
+	"changelog":        {20, "easy"},
+	"deployed_version": {15, "easy"},
+	"rollback":         {30, "medium"},
+	"stale_flags":      {60, "medium"},
+	"stripe":           {30, "medium"},
+	// Code Quality & Performance
+	"vulnerability":      {60, "hard"},
+	"osv":                {60, "hard"},
+	"supply_chain":       {30, "medium"},
+	"dockerfile":         {30, "medium"},
+	"compose":            {20, "easy"},
+	"iac":                {45, "medium"},
+	"dependencies":       {60, "medium"},
+	"outdated_deps":      {120, "hard"},
+	"debug_statements":   {15, "easy"},
+	"debug_mode":         {5, "easy"},
+	"localhost_urls":     {15, "easy"},
+	"redirect_ssrf":      {60, "medium"},
+	"sql_injection":      {60, "medium"},
+	"legacy_artifacts":   {20, "easy"},
+	"analytics_ids":      {10, "easy"},
+	"error_pages":        {30, "medium"},
+	"image_optimization": {20, "easy"},
+	"image_alt":          {30, "easy"},
+	"fonts":              {20, "easy"},
+	"resilience":         {30, "medium"},
+	"cookies":            {45, "medium"},
+	"regulated_gating":   {120, "hard"},
+	"a11y_statement":     {60, "medium"},
+	"refund_policy":      {30, "medium"},
+	// Services
+	"required_services": {60, "medium"}, // integrating a missing provider
+	// Legal & Compliance
+	"legal_pages": {60, "medium"},
+	// Web Standard Files
+	"favicon":          {20, "easy"},
+	"robotsTxt":        {5, "easy"},
+	"sitemap":          {20, "medium"},
+	"sitemap_coverage": {15, "easy"},
+	"llmsTxt":          {10, "easy"},
+	"adsTxt":           {5, "easy"},
+	"humansTxt":        {5, "easy"},
+	"license":          {5, "easy"},
+	"required_files":   {30, "medium"},
+}
+
+// EffortFor returns the fix estimate for a check or service ID.
+func EffortFor(id string) Effort {
+	if e, ok := checkEfforts[id]; ok {
+		return e
+	}
+	return defaultEffort
+}
@@ -8,6 +8,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/preflightsh/preflight/internal/netutil"
 )
 
 type EmailAuthCheck struct{}
@@ -17,9 +19,12 @@ func (c EmailAuthCheck) ID() string {
 }
 
 func (c EmailAuthCheck) Title() string {
-	return "Email authentication (SPF/DMARC)"
+	return "Email deliverability (SPF/DKIM/DMARC)"
 }
 
+// Run looks up the records receiving servers judge mail from the
+// production domain by and reports them together, with a score, since a
+// gap in any one of them sends mail to spam.
 func (c EmailAuthCheck) Run(ctx Context) (CheckResult, error) {
 	if ctx.Config.URLs.Production == "" {
 		return CheckResult{
@@ -42,8 +47,8 @@ func (c EmailAuthCheck) Run(ctx Context) (CheckResult, error) {
 		}, nil
 	}
 
-	hasSPF, spfRecord, spfErr := checkSPF(domain)
-	hasDMARC, dmarcRecord, dmarcErr := checkDMARC(domain)
+	spf, spfErr := spfElement(ctx, domain)
+	dmarc, dmarcErr := dmarcElement(ctx, domain)
 
 	// If DNS lookups failed, report the error instead of claiming records are missing
 	if spfErr != nil || dmarcErr != nil {
@@ -67,43 +72,49 @@ func (c EmailAuthCheck) Run(ctx Context) (CheckResult, error) {
 		}, nil
 	}
 
-	var missing []string
-	if !hasSPF {
-		missing = append(missing, "SPF")
-	}
-	if !hasDMARC {
-		missing = append(missing, "DMARC")
+	mx, mxHosts := mxElement(ctx, domain)
+	report := newDeliverability(domain, []DeliverabilityElement{
+		spf,
+		dkimElement(ctx, domain),
+		dmarc,
+		mx,
+		reverseDNSElement(ctx, mxHosts),
+	})
+
+	var failing, suggestions, details []string
+	for _, e := range report.Elements {
+		switch {
+		case e.Skipped:
+			details = append(details, fmt.Sprintf("%s: skipped (%s)", e.Name, e.Found))
+		case e.Passed:
+			details = append(details, fmt.Sprintf("%s: ok (%s)", e.Name, e.Found))
+		default:
+			details = append(details, fmt.Sprintf("%s: failing (%s)", e.Name, e.Found))
+			failing = append(failing, e.Name)
+			suggestions = append(suggestions, e.Name+": "+e.Fix)
+		}
 	}
 
-	if len(missing) == 0 {
+	if len(failing) == 0 {
 		return CheckResult{
-			ID:       c.ID(),
-			Title:    c.Title(),
-			Severity: SeverityInfo,
-			Passed:   true,
-			Message:  fmt.Sprintf("SPF and DMARC configured for %s", domain),
+			ID:             c.ID(),
+			Title:          c.Title(),
+			Severity:       SeverityInfo,
+			Passed:         true,
+			Message:        fmt.Sprintf("Deliverability %d/100 for %s: SPF, DKIM, DMARC, MX and reverse DNS in place", report.Score, domain),
+			Details:        details,
+			Deliverability: report,
 		}, nil
 	}
-
-	var suggestions []string
-	if !hasSPF {
-		suggestions = append(suggestions, "Add SPF record: v=spf1 include:... ~all")
-	} else {
-		suggestions = append(suggestions, fmt.Sprintf("SPF: %s", truncate(spfRecord, 60)))
-	}
-	if !hasDMARC {
-		suggestions = append(suggestions, "Add DMARC record at _dmarc."+domain)
-	} else {
-		suggestions = append(suggestions, fmt.Sprintf("DMARC: %s", truncate(dmarcRecord, 60)))
-	}
-
 	return CheckResult{
-		ID:          c.ID(),
-		Title:       c.Title(),
-		Severity:    SeverityWarn,
-		Passed:      false,
-		Message:     fmt.Sprintf("Missing: %s", strings.Join(missing, ", ")),
-		Suggestions: suggestions,
+		ID:             c.ID(),
+		Title:          c.Title(),
+		Severity:       SeverityWarn,
+		Passed:         false,
+		Message:        fmt.Sprintf("Deliverability %d/100 for %s: %s need attention", report.Score, domain, strings.Join(failing, ", ")),
+		Suggestions:    suggestions,
+		Details:        details,
+		Deliverability: report,
 	}, nil
 }
 
@@ -118,71 +129,42 @@ func extractDomain(rawURL string) (string, error) {
 	return parsed.Hostname(), nil
 }
 
-const fallbackDNSServer = "1.1.1.1:53"
+// dnsLookupTimeout bounds each lookup the email checks make.
+const dnsLookupTimeout = 10 * time.Second
 
-func dnsLookupTXT(name string) ([]string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// dnsLookupTXT looks name up with the resolver preflight.yml's dns
+// section configures (see netutil.ConfigureResolver).
+func dnsLookupTXT(ctx Context, name string) ([]string, error) {
+	lookupCtx, cancel := context.WithTimeout(ctx.reqContext(), dnsLookupTimeout)
 	defer cancel()
 
-	records, err := net.DefaultResolver.LookupTXT(ctx, name)
-	if err == nil {
-		return records, nil
-	}
-	// Domain or record does not exist. Return nil so callers can't
-	// accidentally consume a partial slice alongside the non-nil error.
-	var dnsErr *net.DNSError
-	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
-		return nil, err
-	}
-
-	// System resolver failed (timeout, refused, server error). Retry against
-	// a public resolver so a flaky local resolver doesn't produce false WARNs.
-	fallback := &net.Resolver{
-		PreferGo: true,
-		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
-			d := net.Dialer{Timeout: 5 * time.Second}
-			return d.DialContext(ctx, network, fallbackDNSServer)
-		},
-	}
-	fbCtx, fbCancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer fbCancel()
-	return fallback.LookupTXT(fbCtx, name)
+	countNetworkCall()
+	return netutil.LookupTXT(lookupCtx, name)
 }
 
-func checkSPF(domain string) (bool, string, error) {
-	records, err := dnsLookupTXT(domain)
+// lookupTXTPrefix returns the TXT records of name that start with prefix,
+// ignoring case. A name with no records has none; that isn't an error.
+func lookupTXTPrefix(ctx Context, name, prefix string) ([]string, error) {
+	records, err := dnsLookupTXT(ctx, name)
 	if err != nil {
-		var dnsErr *net.DNSError
-		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
-			return false, "", nil
+		if isDNSNotFound(err) {
+			return nil, nil
 		}
-		return false, "", err
+		return nil, err
 	}
-
+	var matching []string
 	for _, record := range records {
-		if strings.HasPrefix(strings.ToLower(record), "v=spf1") {
-			return true, record, nil
+		if strings.HasPrefix(strings.ToLower(record), prefix) {
+			matching = append(matching, record)
 		}
 	}
-	return false, "", nil
+	return matching, nil
 }
 
-func checkDMARC(domain string) (bool, string, error) {
-	records, err := dnsLookupTXT("_dmarc." + domain)
-	if err != nil {
-		var dnsErr *net.DNSError
-		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
-			return false, "", nil
-		}
-		return false, "", err
-	}
-
-	for _, record := range records {
-		if strings.HasPrefix(strings.ToLower(record), "v=dmarc1") {
-			return true, record, nil
-		}
-	}
-	return false, "", nil
+// isDNSNotFound reports whether err says the name has no records.
+func isDNSNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
 }
 
 func truncate(s string, max int) string {
@@ -0,0 +1,215 @@
+package checks
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// fakeDNS answers lookups from fixed tables. Names missing from a table
+// don't exist.
+type fakeDNS struct {
+	ips map[string][]string
+	txt map[string][]string
+	mx  map[string][]*net.MX
+	ptr map[string][]string
+}
+
+func notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (f fakeDNS) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	addrs, ok := f.ips[host]
+	if !ok {
+		return nil, notFound(host)
+	}
+	var ips []net.IPAddr
+	for _, a := range addrs {
+		ips = append(ips, net.IPAddr{IP: net.ParseIP(a)})
+	}
+	return ips, nil
+}
+
+func (f fakeDNS) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if records, ok := f.txt[name]; ok {
+		return records, nil
+	}
+	return nil, notFound(name)
+}
+
+func (f fakeDNS) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	if records, ok := f.mx[name]; ok {
+		return records, nil
+	}
+	return nil, notFound(name)
+}
+
+func (f fakeDNS) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	if names, ok := f.ptr[addr]; ok {
+		return names, nil
+	}
+	return nil, notFound(addr)
+}
+
+func TestEmailAuthDeliverability(t *testing.T) {
+	defer netutil.ConfigureResolver("", "")
+	run := func(t *testing.T, dns fakeDNS, modify ...func(*config.PreflightConfig)) CheckResult {
+		t.Helper()
+		netutil.SetResolver(dns)
+		cfg := &config.PreflightConfig{}
+		cfg.URLs.Production = "https://example.com"
+		for _, m := range modify {
+			m(cfg)
+		}
+		result, err := EmailAuthCheck{}.Run(Context{Config: cfg})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	t.Run("all in place", func(t *testing.T) {
+		result := run(t, fakeDNS{
+			txt: map[string][]string{
+				"example.com":                   {"google-site-verification=x", "v=spf1 include:_spf.google.com ~all"},
+				"_dmarc.example.com":            {"v=DMARC1; p=quarantine"},
+				"google._domainkey.example.com": {testDKIMRecord(t, 2048)},
+			},
+			mx:  map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+			ips: map[string][]string{"mx.example.com": {"192.0.2.10"}},
+			ptr: map[string][]string{"192.0.2.10": {"mx.example.com."}},
+		})
+		if !result.Passed || result.Deliverability == nil || result.Deliverability.Score != 100 {
+			t.Fatalf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+		if dkim := result.Deliverability.Elements[1]; dkim.Found != "key at selector google" {
+			t.Errorf("DKIM found = %q", dkim.Found)
+		}
+	})
+
+	t.Run("gaps", func(t *testing.T) {
+		result := run(t, fakeDNS{
+			txt: map[string][]string{
+				"example.com": {"v=spf1 include:a.example ~all", "v=spf1 include:b.example ~all"},
+			},
+			mx:  map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+			ips: map[string][]string{"mx.example.com": {"192.0.2.10"}},
+			ptr: map[string][]string{"192.0.2.10": {"host.isp.example."}},
+		})
+		// MX passes: 15 of 100.
+		if result.Passed || result.Message != "Deliverability 15/100 for example.com: SPF, DKIM, DMARC, reverse DNS need attention" {
+			t.Fatalf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+		suggestions := strings.Join(result.Suggestions, "\n")
+		for _, want := range []string{
+			"SPF: Merge them into a single v=spf1 record",
+			"DMARC: Add a DMARC TXT record at _dmarc.example.com",
+			"reverse DNS: Ask the mail host's provider for a PTR record",
+		} {
+			if !strings.Contains(suggestions, want) {
+				t.Errorf("suggestions missing %q:\n%s", want, suggestions)
+			}
+		}
+	})
+
+	t.Run("truncated key", func(t *testing.T) {
+		result := run(t, fakeDNS{txt: map[string][]string{
+			"example.com":                   {"v=spf1 -all"},
+			"_dmarc.example.com":            {"v=DMARC1; p=reject"},
+			"google._domainkey.example.com": {"v=DKIM1; k=rsa; p=MIIBIjAN"},
+		}})
+		dkim := result.Deliverability.Elements[1]
+		if dkim.Passed || !strings.Contains(dkim.Found, "broken key at google: p= isn't an RSA public key") {
+			t.Errorf("DKIM = %v %q", dkim.Passed, dkim.Found)
+		}
+	})
+
+	t.Run("provider and configured selectors", func(t *testing.T) {
+		dns := fakeDNS{txt: map[string][]string{
+			"example.com":               {"v=spf1 include:spf.mtasv.net -all"},
+			"_dmarc.example.com":        {"v=DMARC1; p=reject"},
+			"pm._domainkey.example.com": {testDKIMRecord(t, 2048)},
+		}}
+		result := run(t, dns, func(cfg *config.PreflightConfig) {
+			cfg.Services = map[string]config.ServiceConfig{"postmark": {Declared: true}}
+		})
+		if dkim := result.Deliverability.Elements[1]; !dkim.Passed || dkim.Found != "key at selector pm" {
+			t.Errorf("DKIM = %v %q", dkim.Passed, dkim.Found)
+		}
+
+		// A configured selector without a key fails even though pm works.
+		result = run(t, dns, func(cfg *config.PreflightConfig) {
+			cfg.Services = map[string]config.ServiceConfig{"postmark": {Declared: true}}
+			cfg.Checks.EmailAuth = &config.EmailAuthConfig{Enabled: true, DKIMSelectors: []string{"20240101pm"}}
+		})
+		if dkim := result.Deliverability.Elements[1]; dkim.Passed || dkim.Found != "20240101pm: no key" {
+			t.Errorf("DKIM = %v %q", dkim.Passed, dkim.Found)
+		}
+	})
+
+	t.Run("no mail hosts", func(t *testing.T) {
+		result := run(t, fakeDNS{txt: map[string][]string{
+			"example.com":               {"v=spf1 -all"},
+			"_dmarc.example.com":        {"v=DMARC1; p=reject"},
+			"s1._domainkey.example.com": {testDKIMRecord(t, 2048)},
+		}})
+		// Reverse DNS is skipped, so MX is 15 of the remaining 90.
+		if result.Passed || result.Deliverability.Score != 83 || !result.Deliverability.Elements[4].Skipped {
+			t.Errorf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+	})
+}
+
+// testDKIMRecord returns a DKIM record holding a fresh RSA key.
+func testDKIMRecord(t *testing.T, bits int) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der)
+}
+
+func TestDKIMKeyProblem(t *testing.T) {
+	edKey, _, _ := ed25519.GenerateKey(rand.Reader)
+	// crypto/rsa no longer generates keys this short, so make one up.
+	short, err := x509.MarshalPKIXPublicKey(&rsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), 511), E: 65537})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name, record, want string
+		bits               int
+	}{
+		{"rsa 2048", testDKIMRecord(t, 2048), "", 2048},
+		{"rsa 1024", testDKIMRecord(t, 1024), "", 1024},
+		{"rsa 512", "v=DKIM1; p=" + base64.StdEncoding.EncodeToString(short), "512-bit RSA key", 512},
+		{"ed25519", "v=DKIM1; k=ed25519; p=" + base64.StdEncoding.EncodeToString(edKey), "", 0},
+		{"revoked", "v=DKIM1; p=", "key revoked", 0},
+		{"no key", "v=DKIM1; k=rsa", "no p= public key", 0},
+		{"bad base64", "v=DKIM1; p=MIIB!jAN", "isn't valid base64", 0},
+		{"truncated", "v=DKIM1; p=MIGfMA0G", "isn't an RSA public key", 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bits, problem := dkimKeyProblem(tc.record)
+			if bits != tc.bits || !strings.Contains(problem, tc.want) || (tc.want == "") != (problem == "") {
+				t.Errorf("dkimKeyProblem = %d, %q; want %d, %q", bits, problem, tc.bits, tc.want)
+			}
+		})
+	}
+}
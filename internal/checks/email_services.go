@@ -49,7 +49,7 @@ func (c PostmarkCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`ServerClient`),
 	}
 
-	found := searchForPatterns(ctx.RootDir, ctx.Config.Stack, patterns)
+	found := searchForPatterns(ctx, patterns)
 
 	if found {
 		return CheckResult{
@@ -123,7 +123,7 @@ func (c SendGridCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`SendGrid`),
 	}
 
-	found := searchForPatterns(ctx.RootDir, ctx.Config.Stack, patterns)
+	found := searchForPatterns(ctx, patterns)
 
 	if found {
 		return CheckResult{
@@ -197,7 +197,7 @@ func (c MailgunCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`Mailgun`),
 	}
 
-	found := searchForPatterns(ctx.RootDir, ctx.Config.Stack, patterns)
+	found := searchForPatterns(ctx, patterns)
 
 	if found {
 		return CheckResult{
@@ -271,7 +271,7 @@ func (c ResendCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`Resend\(`),
 	}
 
-	found := searchForPatterns(ctx.RootDir, ctx.Config.Stack, patterns)
+	found := searchForPatterns(ctx, patterns)
 
 	if found {
 		return CheckResult{
@@ -347,7 +347,7 @@ func (c AWSSESCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`craft-amazon-ses`),
 	}
 
-	found := searchForPatterns(ctx.RootDir, ctx.Config.Stack, patterns)
+	found := searchForPatterns(ctx, patterns)
 
 	if found {
 		return CheckResult{
@@ -400,7 +400,7 @@ func hasEnvVar(rootDir, prefix string) bool {
 // prefix (uppercased). Lives in its own function so defer can close the
 // file even if scanning panics on a pathological line.
 func envFileHasPrefix(path, prefix string) bool {
-	file, err := os.Open(path)
+	file, err := openFile(path)
 	if err != nil {
 		return false
 	}
@@ -456,7 +456,7 @@ func hasEnvVarReference(rootDir string, prefixes ...string) (string, bool) {
 		if err != nil || fi.IsDir() || fi.Size() > maxEnvRefScanBytes {
 			return false
 		}
-		content, err := os.ReadFile(path)
+		content, err := readFile(path)
 		if err != nil {
 			return false
 		}
@@ -481,7 +481,8 @@ func hasEnvVarReference(rootDir string, prefixes ...string) (string, bool) {
 	// usual config file types.
 	found := ""
 	configDir := filepath.Join(rootDir, "config")
-	_ = filepath.Walk(configDir, func(path string, fi os.FileInfo, err error) error {
+	_ = walk(configDir, func(path string, fi os.FileInfo, err error) error {
+		recordUnreadable(path, err)
 		if err != nil || found != "" {
 			return nil
 		}
@@ -2,12 +2,20 @@ package checks
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 )
 
+// EnvParityCheck compares the variables each environment defines against
+// the example file that documents them: the local env file, the
+// production env file, and the env sections of deploy configs (fly.toml,
+// app.json, vercel.json). It reports variables one defines that another
+// lacks, and obvious placeholders (changeme, xxx) in the production ones.
 type EnvParityCheck struct{}
 
 func (c EnvParityCheck) ID() string {
@@ -18,6 +26,23 @@ func (c EnvParityCheck) Title() string {
 	return "Environment variables"
 }
 
+// envSource is one place variables are defined.
+type envSource struct {
+	name string
+	vars map[string]string
+	// complete sources should define everything the example documents.
+	// fly.toml and vercel.json only carry the non-secret part of the
+	// environment (secrets are set with fly secrets or the dashboard), and
+	// a stack's .env.local overrides a few variables, so those are only
+	// checked for variables the example doesn't document.
+	complete bool
+	// production sources are checked for placeholder values.
+	production bool
+}
+
+// envPlaceholder matches values left from a template rather than set.
+var envPlaceholder = regexp.MustCompile(`(?i)^(?:change[-_ ]?me|change[-_ ]?this|x{3,}|todo|tbd|fixme|replace[-_ ]?me|placeholder|dummy|example|your[-_ ].+|<[^>]+>|\.{3})$|^(?:sk|pk|rk)_test_`)
+
 func (c EnvParityCheck) Run(ctx Context) (CheckResult, error) {
 	cfg := ctx.Config.Checks.EnvParity
 	if cfg == nil {
@@ -30,11 +55,7 @@ func (c EnvParityCheck) Run(ctx Context) (CheckResult, error) {
 		}, nil
 	}
 
-	envPath := filepath.Join(ctx.RootDir, cfg.EnvFile)
-	examplePath := filepath.Join(ctx.RootDir, cfg.ExampleFile)
-
-	// Check if .env.example exists first
-	exampleKeys, exampleErr := parseEnvFile(examplePath)
+	exampleVars, exampleErr := parseEnvFile(filepath.Join(ctx.RootDir, cfg.ExampleFile))
 	if exampleErr != nil {
 		// No .env.example - that's fine, skip this check
 		return CheckResult{
@@ -46,77 +67,155 @@ func (c EnvParityCheck) Run(ctx Context) (CheckResult, error) {
 		}, nil
 	}
 
-	// .env.example exists - now check if .env exists
-	envKeys, envErr := parseEnvFile(envPath)
-	if envErr != nil {
-		// .env.example exists but .env doesn't - this is expected for repos
-		// Just note that .env.example documents the required vars
+	var stackFiles []string
+	for _, stack := range ctx.Config.AllStacks() {
+		stackFiles = append(stackFiles, stackPack(stack).Env.Files...)
+	}
+	sources := envSources(ctx.RootDir, cfg.EnvFile, cfg.ProductionFile, stackFiles)
+	if len(sources) == 0 {
+		// Only the example is committed, which is how it should be: it
+		// documents the required vars.
 		return CheckResult{
 			ID:       c.ID(),
 			Title:    c.Title(),
 			Severity: SeverityInfo,
 			Passed:   true,
-			Message:  cfg.ExampleFile + " documents " + fmt.Sprintf("%d", len(exampleKeys)) + " required variables",
+			Message:  cfg.ExampleFile + " documents " + fmt.Sprintf("%d", len(exampleVars)) + " required variables",
 		}, nil
 	}
 
-	// Find keys in .env but not in .env.example
-	var missingInExample []string
-	for key := range envKeys {
-		if _, exists := exampleKeys[key]; !exists {
-			missingInExample = append(missingInExample, key)
+	var messages, suggestions, compared []string
+	undocumented := map[string][]string{}
+	var placeholders []string
+	for _, src := range sources {
+		compared = append(compared, src.name)
+		var missing []string
+		for key := range exampleVars {
+			if _, ok := src.vars[key]; !ok && src.complete {
+				missing = append(missing, key)
+			}
+		}
+		sort.Strings(missing)
+		if len(missing) > 0 {
+			messages = append(messages, "Missing in "+src.name+": "+strings.Join(missing, ", "))
+			suggestions = append(suggestions, "Add "+strings.Join(missing, ", ")+" to "+src.name)
+		}
+		for key, value := range src.vars {
+			if _, ok := exampleVars[key]; !ok {
+				undocumented[key] = append(undocumented[key], src.name)
+			}
+			if src.production && envPlaceholder.MatchString(strings.TrimSpace(value)) {
+				placeholders = append(placeholders, fmt.Sprintf("%s in %s (%q)", key, src.name, value))
+			}
 		}
 	}
 
-	// Find keys in .env.example but not in .env
-	var missingInEnv []string
-	for key := range exampleKeys {
-		if _, exists := envKeys[key]; !exists {
-			missingInEnv = append(missingInEnv, key)
+	if len(undocumented) > 0 {
+		keys := make([]string, 0, len(undocumented))
+		for key := range undocumented {
+			keys = append(keys, key)
 		}
+		sort.Strings(keys)
+		var listed []string
+		for _, key := range keys {
+			listed = append(listed, key+" ("+strings.Join(undocumented[key], ", ")+")")
+		}
+		messages = append([]string{"Missing in " + cfg.ExampleFile + ": " + strings.Join(listed, ", ")}, messages...)
+		suggestions = append([]string{"Add " + strings.Join(keys, ", ") + " to " + cfg.ExampleFile}, suggestions...)
+	}
+
+	severity := SeverityWarn
+	if len(placeholders) > 0 {
+		sort.Strings(placeholders)
+		severity = SeverityError
+		messages = append([]string{"Placeholder values in production: " + strings.Join(placeholders, ", ")}, messages...)
+		suggestions = append([]string{"Set real values for the placeholders before deploying"}, suggestions...)
 	}
 
-	if len(missingInExample) == 0 && len(missingInEnv) == 0 {
+	details := []string{"Compared " + cfg.ExampleFile + " with " + strings.Join(compared, ", ")}
+	if len(messages) == 0 {
 		return CheckResult{
 			ID:       c.ID(),
 			Title:    c.Title(),
 			Severity: SeverityInfo,
 			Passed:   true,
 			Message:  "All environment variables are documented",
+			Details:  details,
 		}, nil
 	}
 
-	var messages []string
-	var suggestions []string
-
-	if len(missingInExample) > 0 {
-		messages = append(messages, "Missing in "+cfg.ExampleFile+": "+strings.Join(missingInExample, ", "))
-		suggestions = append(suggestions, "Add "+strings.Join(missingInExample, ", ")+" to "+cfg.ExampleFile)
-	}
-
-	if len(missingInEnv) > 0 {
-		messages = append(messages, "Missing in "+cfg.EnvFile+": "+strings.Join(missingInEnv, ", "))
-		suggestions = append(suggestions, "Add "+strings.Join(missingInEnv, ", ")+" to "+cfg.EnvFile)
-	}
-
 	return CheckResult{
 		ID:          c.ID(),
 		Title:       c.Title(),
-		Severity:    SeverityWarn,
+		Severity:    severity,
 		Passed:      false,
 		Message:     strings.Join(messages, "; "),
 		Suggestions: suggestions,
+		Details:     details,
 	}, nil
 }
 
-func parseEnvFile(path string) (map[string]bool, error) {
-	file, err := os.Open(path)
+// envSources reads every environment definition present besides the
+// example file. stackFiles are the extra env files the stacks load (see
+// stacks.Env), which like fly.toml only override part of the environment.
+func envSources(rootDir, envFile, productionFile string, stackFiles []string) []envSource {
+	var sources []envSource
+	if vars, err := parseEnvFile(filepath.Join(rootDir, envFile)); err == nil {
+		sources = append(sources, envSource{name: envFile, vars: vars, complete: true})
+	}
+	for _, file := range stackFiles {
+		if file == envFile || file == productionFile || slices.ContainsFunc(sources, func(s envSource) bool { return s.name == file }) {
+			continue
+		}
+		if vars, err := parseEnvFile(filepath.Join(rootDir, file)); err == nil {
+			sources = append(sources, envSource{name: file, vars: vars})
+		}
+	}
+	if productionFile != "" && productionFile != envFile {
+		if vars, err := parseEnvFile(filepath.Join(rootDir, productionFile)); err == nil {
+			sources = append(sources, envSource{name: productionFile, vars: vars, complete: true, production: true})
+		}
+	}
+	if data, err := readFile(filepath.Join(rootDir, "fly.toml")); err == nil {
+		if vars := flyTOMLEnv(string(data)); len(vars) > 0 {
+			sources = append(sources, envSource{name: "fly.toml [env]", vars: vars, production: true})
+		}
+	}
+	if data, err := readFile(filepath.Join(rootDir, "app.json")); err == nil {
+		if vars, ok := appJSONEnv(data); ok {
+			sources = append(sources, envSource{name: "app.json env", vars: vars, complete: true, production: true})
+		}
+	}
+	if data, err := readFile(filepath.Join(rootDir, "vercel.json")); err == nil {
+		var v struct {
+			Env   map[string]string `json:"env"`
+			Build struct {
+				Env map[string]string `json:"env"`
+			} `json:"build"`
+		}
+		if json.Unmarshal(data, &v) == nil && len(v.Env)+len(v.Build.Env) > 0 {
+			vars := map[string]string{}
+			for _, env := range []map[string]string{v.Build.Env, v.Env} {
+				for key, value := range env {
+					vars[key] = value
+				}
+			}
+			sources = append(sources, envSource{name: "vercel.json env", vars: vars, production: true})
+		}
+	}
+	return sources
+}
+
+// parseEnvFile reads a dotenv file's variables and their values, with
+// quotes and trailing comments removed.
+func parseEnvFile(path string) (map[string]string, error) {
+	file, err := openFile(path)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	keys := make(map[string]bool)
+	vars := make(map[string]string)
 	scanner := bufio.NewScanner(file)
 
 	for scanner.Scan() {
@@ -129,10 +228,69 @@ func parseEnvFile(path string) (map[string]bool, error) {
 
 		// Extract key (everything before =)
 		if idx := strings.Index(line, "="); idx > 0 {
-			key := strings.TrimSpace(line[:idx])
-			keys[key] = true
+			key := strings.TrimSpace(strings.TrimPrefix(line[:idx], "export "))
+			vars[key] = envValue(line[idx+1:])
 		}
 	}
 
-	return keys, scanner.Err()
+	return vars, scanner.Err()
+}
+
+// envValue unquotes a dotenv or TOML value, dropping a trailing comment
+// from an unquoted one.
+func envValue(raw string) string {
+	v := strings.TrimSpace(raw)
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') {
+		if end := strings.IndexByte(v[1:], v[0]); end >= 0 {
+			return v[1 : end+1]
+		}
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = strings.TrimSpace(v[:i])
+	}
+	return v
+}
+
+// flyTOMLEnv returns the variables in fly.toml's [env] table.
+func flyTOMLEnv(content string) map[string]string {
+	vars := map[string]string{}
+	inEnv := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inEnv = line == "[env]"
+			continue
+		}
+		if !inEnv || line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			vars[strings.Trim(strings.TrimSpace(key), `"'`)] = envValue(value)
+		}
+	}
+	return vars
+}
+
+// appJSONEnv returns the variables a Heroku app.json declares, with their
+// values when set. Each is a string or an object with a value.
+func appJSONEnv(data []byte) (map[string]string, bool) {
+	var manifest struct {
+		Env map[string]json.RawMessage `json:"env"`
+	}
+	if json.Unmarshal(data, &manifest) != nil || len(manifest.Env) == 0 {
+		return nil, false
+	}
+	vars := map[string]string{}
+	for key, raw := range manifest.Env {
+		var value string
+		if json.Unmarshal(raw, &value) != nil {
+			var obj struct {
+				Value string `json:"value"`
+			}
+			_ = json.Unmarshal(raw, &obj)
+			value = obj.Value
+		}
+		vars[key] = value
+	}
+	return vars, true
 }
@@ -0,0 +1,72 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestEnvParityComparesSources(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		passed   bool
+		severity Severity
+		message  string
+	}{
+		{
+			name:     "only the example",
+			files:    map[string]string{".env.example": "DATABASE_URL=\nSECRET_KEY=\n"},
+			passed:   true,
+			severity: SeverityInfo,
+			message:  ".env.example documents 2 required variables",
+		},
+		{
+			name: "in parity",
+			files: map[string]string{
+				".env.example":    "DATABASE_URL=\nSECRET_KEY=\n",
+				".env":            "export DATABASE_URL=postgres://localhost/app\nSECRET_KEY='dev' # local only\n",
+				".env.production": "DATABASE_URL=postgres://db/app\nSECRET_KEY=3f9a1c\n",
+				"fly.toml":        "app = \"acme\"\n\n[env]\n  DATABASE_URL = \"postgres://db/app\"\n\n[http_service]\n  internal_port = 8080\n",
+			},
+			passed:   true,
+			severity: SeverityInfo,
+			message:  "All environment variables are documented",
+		},
+		{
+			name: "gaps both ways",
+			files: map[string]string{
+				".env.example": "DATABASE_URL=\nSECRET_KEY=\n",
+				".env":         "DATABASE_URL=postgres://localhost/app\nREDIS_URL=redis://localhost\n",
+				"vercel.json":  `{"env": {"REDIS_URL": "redis://cache"}, "build": {"env": {"SENTRY_DSN": "https://sentry.io/1"}}}`,
+			},
+			passed:   false,
+			severity: SeverityWarn,
+			message:  "Missing in .env.example: REDIS_URL (.env, vercel.json env), SENTRY_DSN (vercel.json env); Missing in .env: SECRET_KEY",
+		},
+		{
+			name: "placeholders in production",
+			files: map[string]string{
+				".env.example":    "SECRET_KEY=\nSTRIPE_SECRET_KEY=\n",
+				".env.production": "SECRET_KEY=changeme\nSTRIPE_SECRET_KEY=sk_test_123\n",
+				"app.json":        `{"env": {"SECRET_KEY": {"generator": "secret"}, "STRIPE_SECRET_KEY": {"value": "xxx"}}}`,
+			},
+			passed:   false,
+			severity: SeverityError,
+			message:  `Placeholder values in production: SECRET_KEY in .env.production ("changeme"), STRIPE_SECRET_KEY in .env.production ("sk_test_123"), STRIPE_SECRET_KEY in app.json env ("xxx")`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.PreflightConfig{}
+			cfg.Checks.EnvParity = &config.EnvParityConfig{Enabled: true, EnvFile: ".env", ExampleFile: ".env.example", ProductionFile: ".env.production"}
+			result, err := EnvParityCheck{}.Run(Context{RootDir: writeFiles(t, tt.files), Config: cfg})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tt.passed || result.Severity != tt.severity || result.Message != tt.message {
+				t.Errorf("passed=%v severity=%v message=%q\nwant %v %v %q", result.Passed, result.Severity, result.Message, tt.passed, tt.severity, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package checks
+
+import (
+	"errors"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// EnvReference is an environment variable the code reads, with the files
+// that read it in walk order.
+type EnvReference struct {
+	Name  string
+	Files []string
+}
+
+// envReadPatterns match reading a variable by name, across the languages
+// preflight knows. The first group is the name. Only constant names are
+// matched: process.env[key] with a computed key can't be documented.
+var envReadPatterns = []*regexp.Regexp{
+	// JavaScript and TypeScript: process.env.X, process.env["X"],
+	// import.meta.env.X (Vite, Astro), Deno.env.get("X"), Bun.env.X
+	regexp.MustCompile(`\b(?:process\.env|import\.meta\.env|Bun\.env)\.([A-Z_][A-Z0-9_]*)\b`),
+	regexp.MustCompile(`\b(?:process\.env|import\.meta\.env)\[\s*["'` + "`" + `]([A-Z_][A-Z0-9_]*)["'` + "`" + `]\s*\]`),
+	regexp.MustCompile(`\bDeno\.env\.get\(\s*["']([A-Z_][A-Z0-9_]*)["']`),
+	// Ruby: ENV["X"], ENV.fetch("X")
+	regexp.MustCompile(`\bENV(?:\[\s*|\.fetch\(\s*)["']([A-Z_][A-Z0-9_]*)["']`),
+	// Go: os.Getenv("X"), os.LookupEnv("X")
+	regexp.MustCompile(`\bos\.(?:Getenv|LookupEnv)\(\s*"([A-Z_][A-Z0-9_]*)"`),
+	// PHP: env('X') (Laravel, Craft), getenv('X'), $_ENV['X'], $_SERVER['X']
+	// is left out, since most of its keys come from the web server.
+	regexp.MustCompile(`(?:\benv|\bgetenv|\$_ENV\[)\(?\s*["']([A-Z_][A-Z0-9_]*)["']`),
+	// Python: os.environ["X"], os.environ.get("X"), os.getenv("X")
+	regexp.MustCompile(`\bos\.(?:environ(?:\[\s*|\.get\(\s*)|getenv\(\s*)["']([A-Z_][A-Z0-9_]*)["']`),
+	// Rust: env::var("X"); Elixir: System.get_env("X")
+	regexp.MustCompile(`\b(?:env::var(?:_os)?|System\.(?:get_env|fetch_env!?))\(\s*"([A-Z_][A-Z0-9_]*)"`),
+}
+
+// envReadExtensions are the source files searched for env reads.
+var envReadExtensions = map[string]bool{
+	".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true, ".cjs": true,
+	".vue": true, ".svelte": true, ".astro": true,
+	".rb": true, ".rake": true, ".erb": true,
+	".go": true, ".php": true, ".py": true, ".rs": true, ".ex": true, ".exs": true,
+}
+
+// envReadSkipDirs hold dependencies and build output, whose env reads
+// aren't the app's.
+var envReadSkipDirs = map[string]bool{
+	"vendor": true, "dist": true, "build": true, ".next": true, ".nuxt": true, ".svelte-kit": true,
+	"out": true, "coverage": true, "tmp": true, ".cache": true, "__pycache__": true, ".venv": true,
+}
+
+// envProvided are set by the OS, the runtime or the host rather than the
+// app's configuration, so they don't belong in the example file.
+var (
+	envProvided         = map[string]bool{"NODE_ENV": true, "PATH": true, "HOME": true, "PWD": true, "USER": true, "SHELL": true, "TMPDIR": true, "CI": true, "HOSTNAME": true}
+	envProvidedPrefixes = []string{"VERCEL_", "NETLIFY_", "GITHUB_", "RAILWAY_", "FLY_", "RENDER_", "HEROKU_"}
+)
+
+// FindEnvReferences lists the environment variables the project's source
+// reads, sorted by name. Gitignored files, tests, dependencies and build
+// output are left out, and so are variables the platform sets itself.
+func FindEnvReferences(files *FileIndex) []EnvReference {
+	byName := map[string]*EnvReference{}
+	for _, f := range files.Files {
+		name := strings.ToLower(f.Name)
+		if f.Ignored || f.inDir(envReadSkipDirs) || !envReadExtensions[f.Ext] || f.Size > maxEnvRefScanBytes ||
+			strings.Contains(name, ".test.") || strings.Contains(name, ".spec.") || strings.HasSuffix(name, "_test.go") ||
+			strings.HasSuffix(name, ".min.js") {
+			continue
+		}
+		content, err := readFile(files.Abs(f))
+		if err != nil {
+			continue
+		}
+		for _, re := range envReadPatterns {
+			for _, m := range re.FindAllSubmatch(content, -1) {
+				key := string(m[1])
+				if envIsProvided(key) {
+					continue
+				}
+				ref := byName[key]
+				if ref == nil {
+					ref = &EnvReference{Name: key}
+					byName[key] = ref
+				}
+				if len(ref.Files) == 0 || ref.Files[len(ref.Files)-1] != f.Path {
+					ref.Files = append(ref.Files, f.Path)
+				}
+			}
+		}
+	}
+
+	refs := make([]EnvReference, 0, len(byName))
+	for _, ref := range byName {
+		refs = append(refs, *ref)
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Name < refs[j].Name })
+	return refs
+}
+
+func envIsProvided(key string) bool {
+	if envProvided[key] {
+		return true
+	}
+	for _, p := range envProvidedPrefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvScaffold is the result of ScaffoldEnvExample.
+type EnvScaffold struct {
+	// Content is the updated example file.
+	Content []byte
+	// Added are the variables appended to it; Unused are the ones it
+	// already documents that no code reads.
+	Added, Unused []string
+}
+
+// ScaffoldEnvExample adds the variables in refs that the example file at
+// path doesn't document yet, each with an empty value and a comment naming
+// where it's read. What's in the file already is kept as written, values
+// and comments included, and nothing is removed: a variable only a
+// framework reads by convention looks unused from here.
+func ScaffoldEnvExample(path string, refs []EnvReference) (EnvScaffold, error) {
+	documented := map[string]string{}
+	existing, err := readFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return EnvScaffold{}, err
+	}
+	if err == nil {
+		if documented, err = parseEnvFile(path); err != nil {
+			return EnvScaffold{}, err
+		}
+	}
+
+	var s EnvScaffold
+	content := string(existing)
+	referenced := map[string]bool{}
+	var block strings.Builder
+	for _, ref := range refs {
+		referenced[ref.Name] = true
+		if _, ok := documented[ref.Name]; ok {
+			continue
+		}
+		s.Added = append(s.Added, ref.Name)
+		where := ref.Files
+		if len(where) > 3 {
+			where = append(where[:3:3], "...")
+		}
+		block.WriteString("# " + strings.Join(where, ", ") + "\n")
+		block.WriteString(ref.Name + "=\n")
+	}
+	for key := range documented {
+		if !referenced[key] {
+			s.Unused = append(s.Unused, key)
+		}
+	}
+	sort.Strings(s.Unused)
+
+	if len(s.Added) > 0 {
+		if strings.TrimSpace(content) != "" {
+			content = strings.TrimRight(content, "\n") + "\n\n"
+		}
+		content += "# Added by preflight env scaffold\n" + block.String()
+	}
+	s.Content = []byte(content)
+	return s, nil
+}
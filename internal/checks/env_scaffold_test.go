@@ -0,0 +1,68 @@
+package checks
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindEnvReferences(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"src/db.ts":        "const url = process.env.DATABASE_URL\nconst mode = process.env.NODE_ENV\nconst key = process.env['STRIPE_SECRET_KEY']\n",
+		"src/client.ts":    "fetch(import.meta.env.VITE_API_URL)\nconsole.log(process.env.DATABASE_URL)\n",
+		"config/app.php":   "'key' => env('APP_KEY'),\n'region' => getenv(\"AWS_REGION\"),\n",
+		"main.go":          "addr := os.Getenv(\"LISTEN_ADDR\")\nsha := os.Getenv(\"VERCEL_GIT_COMMIT_SHA\")\n",
+		"config/mailer.rb": "ENV.fetch(\"SMTP_HOST\")\nENV[\"SMTP_PORT\"]\n",
+		"app/settings.py":  "SECRET = os.environ[\"DJANGO_SECRET\"]\nDEBUG = os.getenv(\"DJANGO_DEBUG\")\n",
+		"src/db.test.ts":   "process.env.TEST_ONLY = '1'\n",
+		"dist/bundle.js":   "process.env.BUILT\n",
+		"vendor/lib/x.php": "env('VENDORED')\n",
+		"src/dynamic.ts":   "process.env[name]\n",
+		"docs/setup.md":    "Set process.env.IN_DOCS\n",
+	})
+	var got []string
+	var dbFiles []string
+	for _, ref := range FindEnvReferences(BuildFileIndex(dir)) {
+		got = append(got, ref.Name)
+		if ref.Name == "DATABASE_URL" {
+			dbFiles = ref.Files
+		}
+	}
+	want := []string{"APP_KEY", "AWS_REGION", "DATABASE_URL", "DJANGO_DEBUG", "DJANGO_SECRET", "LISTEN_ADDR", "SMTP_HOST", "SMTP_PORT", "STRIPE_SECRET_KEY", "VITE_API_URL"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("names = %v\nwant  %v", got, want)
+	}
+	if len(dbFiles) != 2 {
+		t.Errorf("DATABASE_URL files = %v", dbFiles)
+	}
+}
+
+func TestScaffoldEnvExample(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".env.example": "# Database\nDATABASE_URL=postgres://localhost/app\nLEGACY_FLAG=\n",
+	})
+	refs := []EnvReference{
+		{Name: "DATABASE_URL", Files: []string{"src/db.ts"}},
+		{Name: "REDIS_URL", Files: []string{"src/cache.ts", "src/queue.ts"}},
+	}
+	s, err := ScaffoldEnvExample(filepath.Join(dir, ".env.example"), refs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Database\nDATABASE_URL=postgres://localhost/app\nLEGACY_FLAG=\n\n# Added by preflight env scaffold\n# src/cache.ts, src/queue.ts\nREDIS_URL=\n"
+	if string(s.Content) != want {
+		t.Errorf("content =\n%s\nwant\n%s", s.Content, want)
+	}
+	if !reflect.DeepEqual(s.Added, []string{"REDIS_URL"}) || !reflect.DeepEqual(s.Unused, []string{"LEGACY_FLAG"}) {
+		t.Errorf("added = %v, unused = %v", s.Added, s.Unused)
+	}
+
+	// No file yet: it's created with just the block.
+	s, err = ScaffoldEnvExample(filepath.Join(dir, ".env.sample"), refs[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(s.Content) != "# Added by preflight env scaffold\n# src/db.ts\nDATABASE_URL=\n" {
+		t.Errorf("new file content =\n%s", s.Content)
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/preflightsh/preflight/internal/netutil"
+	"github.com/preflightsh/preflight/internal/workspace"
 )
 
 type ErrorPagesCheck struct{}
@@ -21,10 +23,16 @@ func (c ErrorPagesCheck) Title() string {
 }
 
 func (c ErrorPagesCheck) Run(ctx Context) (CheckResult, error) {
-	stack := ctx.Config.Stack
+	stacks := ctx.Config.AllStacks()
 
-	// Get expected error page paths for this stack
-	paths404, paths500 := getErrorPagePaths(stack)
+	// Get expected error page paths for each declared stack
+	var paths404, paths500 []string
+	for _, stack := range stacks {
+		p404, p500 := getErrorPagePaths(stack)
+		paths404 = append(paths404, p404...)
+		paths500 = append(paths500, p500...)
+	}
+	jsApp := slices.Contains(stacks, "next") || slices.Contains(stacks, "react")
 
 	// Also check common web roots for static error pages
 	webRoots := []string{"public", "static", "web", "www", "dist", "build", "_site", "out", ""}
@@ -34,21 +42,12 @@ func (c ErrorPagesCheck) Run(ctx Context) (CheckResult, error) {
 	found404 := ""
 
 	// Check stack-specific paths first
-	for _, path := range paths404 {
-		fullPath := filepath.Join(ctx.RootDir, path)
-		if _, err := os.Stat(fullPath); err == nil {
-			has404 = true
-			found404 = path
-			break
-		}
+	if path, ok := FindPath(ctx.RootDir, paths404); ok {
+		has404 = true
+		found404 = path
 	}
-
-	for _, path := range paths500 {
-		fullPath := filepath.Join(ctx.RootDir, path)
-		if _, err := os.Stat(fullPath); err == nil {
-			has500 = true
-			break
-		}
+	if _, ok := FindPath(ctx.RootDir, paths500); ok {
+		has500 = true
 	}
 
 	// Check web roots for static error pages if not found
@@ -90,7 +89,7 @@ func (c ErrorPagesCheck) Run(ctx Context) (CheckResult, error) {
 	}
 
 	// Check monorepo paths for Next.js
-	if !has404 && (stack == "next" || stack == "react") {
+	if !has404 && jsApp {
 		monorepo404 := findMonorepoErrorPages(ctx.RootDir, "404")
 		if len(monorepo404) > 0 {
 			has404 = true
@@ -99,7 +98,7 @@ func (c ErrorPagesCheck) Run(ctx Context) (CheckResult, error) {
 		}
 	}
 
-	if !has500 && (stack == "next" || stack == "react") {
+	if !has500 && jsApp {
 		monorepo500 := findMonorepoErrorPages(ctx.RootDir, "500")
 		if len(monorepo500) > 0 {
 			has500 = true
@@ -139,7 +138,14 @@ func (c ErrorPagesCheck) Run(ctx Context) (CheckResult, error) {
 	}
 
 	// Missing 404 page - this is a warning
-	suggestions := getErrorPageSuggestions(stack)
+	var suggestions []string
+	for _, stack := range stacks {
+		for _, s := range getErrorPageSuggestions(stack) {
+			if !slices.Contains(suggestions, s) {
+				suggestions = append(suggestions, s)
+			}
+		}
+	}
 
 	return CheckResult{
 		ID:          c.ID(),
@@ -189,222 +195,22 @@ func probeCustom404OverHTTP(ctx Context) bool {
 		strings.Contains(lower, "<!doctype html")
 }
 
-// getErrorPagePaths returns the expected paths for 404 and 500 error pages based on stack
+// getErrorPagePaths returns the expected paths for 404 and 500 error
+// pages in the stack's pack.
 func getErrorPagePaths(stack string) (paths404 []string, paths500 []string) {
-	switch stack {
-	case "rails":
-		paths404 = []string{"public/404.html"}
-		paths500 = []string{"public/500.html", "public/422.html"}
-
-	case "laravel":
-		paths404 = []string{
-			"resources/views/errors/404.blade.php",
-			"resources/views/errors/404.html",
-		}
-		paths500 = []string{
-			"resources/views/errors/500.blade.php",
-			"resources/views/errors/500.html",
-		}
-
-	case "next":
-		// Pages Router
-		paths404 = []string{
-			"pages/404.tsx", "pages/404.js", "pages/404.jsx",
-			"src/pages/404.tsx", "src/pages/404.js", "src/pages/404.jsx",
-			// App Router
-			"app/not-found.tsx", "app/not-found.js", "app/not-found.jsx",
-			"src/app/not-found.tsx", "src/app/not-found.js", "src/app/not-found.jsx",
-		}
-		paths500 = []string{
-			"pages/500.tsx", "pages/500.js", "pages/500.jsx",
-			"pages/_error.tsx", "pages/_error.js", "pages/_error.jsx",
-			"src/pages/500.tsx", "src/pages/500.js", "src/pages/500.jsx",
-			// App Router
-			"app/error.tsx", "app/error.js", "app/error.jsx",
-			"app/global-error.tsx", "app/global-error.js", "app/global-error.jsx",
-			"src/app/error.tsx", "src/app/error.js", "src/app/error.jsx",
-		}
-
-	case "django":
-		paths404 = []string{"templates/404.html", "templates/errors/404.html"}
-		paths500 = []string{"templates/500.html", "templates/errors/500.html"}
-
-	case "wordpress":
-		// WordPress uses 404.php in theme directory
-		paths404 = []string{
-			"404.php",
-			"wp-content/themes/theme/404.php",
-		}
-		paths500 = []string{}
-
-	case "craft":
-		paths404 = []string{
-			"templates/404.twig",
-			"templates/404.html",
-			"templates/error.twig",
-			"templates/errors/404.twig",
-			"templates/errors/404.html",
-		}
-		paths500 = []string{
-			"templates/500.twig",
-			"templates/500.html",
-			"templates/error.twig",
-			"templates/errors/500.twig",
-		}
-
-	case "drupal":
-		paths404 = []string{
-			"themes/custom/theme/templates/page--404.html.twig",
-			"web/themes/custom/theme/templates/page--404.html.twig",
-		}
-		paths500 = []string{}
-
-	case "hugo":
-		paths404 = []string{
-			"layouts/404.html",
-			"themes/theme/layouts/404.html",
-		}
-		paths500 = []string{}
-
-	case "jekyll":
-		paths404 = []string{"404.html", "404.md", "_pages/404.html", "_pages/404.md"}
-		paths500 = []string{}
-
-	case "gatsby":
-		paths404 = []string{
-			"src/pages/404.js", "src/pages/404.tsx", "src/pages/404.jsx",
-		}
-		paths500 = []string{}
-
-	case "astro":
-		paths404 = []string{
-			"src/pages/404.astro",
-			"src/pages/404.md",
-		}
-		paths500 = []string{
-			"src/pages/500.astro",
-		}
-
-	case "eleventy":
-		paths404 = []string{
-			"404.html", "404.md", "404.njk", "404.liquid",
-			"src/404.html", "src/404.md", "src/404.njk",
-		}
-		paths500 = []string{}
-
-	case "ghost":
-		paths404 = []string{
-			"content/themes/casper/error.hbs",
-			"content/themes/casper/error-404.hbs",
-		}
-		paths500 = []string{}
-
-	case "vue", "vite", "react", "angular", "svelte":
-		// SPAs typically handle routing client-side
-		// Check for common patterns
-		paths404 = []string{
-			"src/pages/404.vue", "src/views/404.vue", "src/pages/NotFound.vue",
-			"src/pages/404.tsx", "src/pages/404.jsx", "src/pages/NotFound.tsx",
-			"src/routes/404.svelte", "src/pages/404.svelte",
-			"public/404.html",
-		}
-		paths500 = []string{}
-
-	case "php":
-		// Basic PHP sites often put error pages in public/errors/ or just public/
-		paths404 = []string{
-			"public/errors/404.php", "public/404.php", "errors/404.php", "404.php",
-			"public/errors/404.html", "public/404.html", "public/errors/404.htm", "public/404.htm",
-		}
-		paths500 = []string{
-			"public/errors/500.php", "public/500.php", "errors/500.php", "500.php",
-			"public/errors/500.html", "public/500.html", "public/errors/500.htm", "public/500.htm",
-		}
-
-	case "go", "rust", "node", "python":
-		// These typically handle errors in code, check for static fallbacks
-		paths404 = []string{"public/404.html", "static/404.html", "templates/404.html"}
-		paths500 = []string{"public/500.html", "static/500.html", "templates/500.html"}
-
-	case "static":
-		paths404 = []string{"404.html"}
-		paths500 = []string{"500.html"}
-
-	default:
-		paths404 = []string{"404.html", "public/404.html"}
-		paths500 = []string{"500.html", "public/500.html"}
-	}
-
-	return paths404, paths500
+	pages := stackPack(stack).ErrorPages
+	return pages.NotFound, pages.ServerError
 }
 
-// getErrorPageSuggestions returns helpful suggestions based on stack
+// getErrorPageSuggestions returns the suggestions in the stack's pack.
 func getErrorPageSuggestions(stack string) []string {
-	switch stack {
-	case "rails":
-		return []string{"Add custom public/404.html and public/500.html"}
-
-	case "laravel":
-		return []string{
-			"Run: php artisan vendor:publish --tag=laravel-errors",
-			"Or create resources/views/errors/404.blade.php",
-		}
-
-	case "next":
-		return []string{
-			"Create pages/404.tsx (Pages Router)",
-			"Or create app/not-found.tsx (App Router)",
-		}
-
-	case "django":
-		return []string{"Create templates/404.html and templates/500.html"}
-
-	case "wordpress":
-		return []string{"Create 404.php in your theme directory"}
-
-	case "craft":
-		return []string{"Create templates/404.twig for custom 404 page"}
-
-	case "php":
-		return []string{"Create public/errors/404.php or public/404.php"}
-
-	case "hugo":
-		return []string{"Create layouts/404.html for custom 404 page"}
-
-	case "jekyll":
-		return []string{"Create 404.html or 404.md in project root"}
-
-	case "gatsby":
-		return []string{"Create src/pages/404.js for custom 404 page"}
-
-	case "astro":
-		return []string{"Create src/pages/404.astro for custom 404 page"}
-
-	case "eleventy":
-		return []string{"Create 404.md or 404.njk in project root"}
-
-	case "vue", "vite":
-		return []string{
-			"Create src/pages/404.vue or handle in router",
-			"Add public/404.html for server-side fallback",
-		}
-
-	case "react":
-		return []string{
-			"Handle 404 in your router (e.g., React Router's '*' route)",
-			"Add public/404.html for server-side fallback",
-		}
-
-	default:
-		return []string{"Add a custom 404.html page"}
-	}
+	return stackPack(stack).ErrorPages.Suggestions
 }
 
 // findMonorepoErrorPages searches monorepo structures for error pages
 func findMonorepoErrorPages(rootDir string, errorType string) []string {
 	var paths []string
 
-	monorepoRoots := []string{"apps", "packages", "services"}
 	extensions := []string{".tsx", ".ts", ".js", ".jsx"}
 
 	var filenames []string
@@ -414,40 +220,14 @@ func findMonorepoErrorPages(rootDir string, errorType string) []string {
 		filenames = []string{"500", "error", "global-error"}
 	}
 
-	for _, monoRoot := range monorepoRoots {
-		monoDir := filepath.Join(rootDir, monoRoot)
-		entries, err := os.ReadDir(monoDir)
-		if err != nil {
-			continue
-		}
-
-		for _, entry := range entries {
-			if !entry.IsDir() {
-				continue
-			}
-
-			// Check pages directory (Pages Router)
-			for _, filename := range filenames {
-				for _, ext := range extensions {
-					pagesPath := filepath.Join(monoDir, entry.Name(), "pages", filename+ext)
-					if _, err := os.Stat(pagesPath); err == nil {
-						paths = append(paths, pagesPath)
-					}
-
-					srcPagesPath := filepath.Join(monoDir, entry.Name(), "src", "pages", filename+ext)
-					if _, err := os.Stat(srcPagesPath); err == nil {
-						paths = append(paths, srcPagesPath)
-					}
-
-					// Check app directory (App Router)
-					appPath := filepath.Join(monoDir, entry.Name(), "app", filename+ext)
-					if _, err := os.Stat(appPath); err == nil {
-						paths = append(paths, appPath)
-					}
-
-					srcAppPath := filepath.Join(monoDir, entry.Name(), "src", "app", filename+ext)
-					if _, err := os.Stat(srcAppPath); err == nil {
-						paths = append(paths, srcAppPath)
+	for _, dir := range workspace.Dirs(rootDir, recordUnreadable) {
+		// Pages Router (pages/, src/pages/) and App Router (app/, src/app/)
+		for _, filename := range filenames {
+			for _, ext := range extensions {
+				for _, sub := range []string{"pages", filepath.Join("src", "pages"), "app", filepath.Join("src", "app")} {
+					p := filepath.Join(dir, sub, filename+ext)
+					if _, err := os.Stat(p); err == nil {
+						paths = append(paths, p)
 					}
 				}
 			}
@@ -1,10 +1,19 @@
 package checks
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+	"github.com/preflightsh/preflight/internal/workspace"
 )
 
 type FaviconCheck struct{}
@@ -20,21 +29,11 @@ func (c FaviconCheck) Title() string {
 func (c FaviconCheck) Run(ctx Context) (CheckResult, error) {
 	var found []string
 	var missing []string
+	// The icon files found, opened below to check they're usable.
+	var faviconFile, appleIconFile string
+	files := ctx.files()
 
-	// Common web root directories across frameworks
-	webRoots := []string{
-		"public",  // Laravel, Rails, many Node.js
-		"static",  // Hugo, some SSGs
-		"web",     // Craft CMS, Symfony
-		"www",     // Some PHP apps
-		"dist",    // Built static sites
-		"build",   // Build outputs
-		"_site",   // Jekyll
-		"out",     // Next.js static export
-		"app",     // Next.js App Router (pages)
-		"src/app", // Next.js App Router (standard)
-		"",        // Root directory
-	}
+	webRoots := iconWebRoots
 
 	// Also check monorepo structures for Next.js App Router
 	monorepoFaviconPaths := findMonorepoAppRouterPaths(ctx.RootDir, "favicon.ico")
@@ -69,6 +68,7 @@ func (c FaviconCheck) Run(ctx Context) (CheckResult, error) {
 		fullPath := filepath.Join(ctx.RootDir, path)
 		if _, err := os.Stat(fullPath); err == nil {
 			hasFavicon = true
+			faviconFile = fullPath
 			found = append(found, path)
 			break
 		}
@@ -79,6 +79,7 @@ func (c FaviconCheck) Run(ctx Context) (CheckResult, error) {
 		for _, path := range monorepoFaviconPaths {
 			if _, err := os.Stat(path); err == nil {
 				hasFavicon = true
+				faviconFile = path
 				// Make path relative for display
 				relPath := relPath(ctx.RootDir, path)
 				found = append(found, relPath)
@@ -87,45 +88,15 @@ func (c FaviconCheck) Run(ctx Context) (CheckResult, error) {
 		}
 	}
 
-	// Flexible search: walk app directories for dynamic icon files (Next.js icon.tsx, etc.)
+	// Flexible search: look through app directories for dynamic icon files (Next.js icon.tsx, etc.)
 	if !hasFavicon {
-		flexIconDirs := []string{"app", "src/app"}
-		for _, dir := range flexIconDirs {
-			if hasFavicon {
-				break
-			}
-			dirPath := filepath.Join(ctx.RootDir, dir)
-			if _, err := os.Stat(dirPath); err != nil {
-				continue
-			}
-			_ = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					if info != nil && info.IsDir() {
-						return filepath.SkipDir
-					}
-					return nil
-				}
-				if hasFavicon {
-					return nil
-				}
-				if info.IsDir() {
-					name := info.Name()
-					if name == "node_modules" || name == ".git" {
-						return filepath.SkipDir
-					}
-					return nil
-				}
-				nameLower := strings.ToLower(info.Name())
-				// Match icon.tsx, icon.ts, icon.jsx, icon.js, favicon.tsx, etc.
-				if nameLower == "icon.tsx" || nameLower == "icon.ts" || nameLower == "icon.jsx" || nameLower == "icon.js" ||
-					nameLower == "favicon.tsx" || nameLower == "favicon.ts" || nameLower == "favicon.jsx" || nameLower == "favicon.js" {
-					hasFavicon = true
-					relPath := relPath(ctx.RootDir, path)
-					found = append(found, relPath)
-					return nil
-				}
-				return nil
-			})
+		if rel := findAppDirFile(files, func(nameLower string) bool {
+			// Match icon.tsx, icon.ts, icon.jsx, icon.js, favicon.tsx, etc.
+			return nameLower == "icon.tsx" || nameLower == "icon.ts" || nameLower == "icon.jsx" || nameLower == "icon.js" ||
+				nameLower == "favicon.tsx" || nameLower == "favicon.ts" || nameLower == "favicon.jsx" || nameLower == "favicon.js"
+		}); rel != "" {
+			hasFavicon = true
+			found = append(found, rel)
 		}
 	}
 
@@ -161,6 +132,7 @@ func (c FaviconCheck) Run(ctx Context) (CheckResult, error) {
 		fullPath := filepath.Join(ctx.RootDir, path)
 		if _, err := os.Stat(fullPath); err == nil {
 			hasAppleIcon = true
+			appleIconFile = fullPath
 			found = append(found, path)
 			break
 		}
@@ -168,12 +140,13 @@ func (c FaviconCheck) Run(ctx Context) (CheckResult, error) {
 
 	// Also check HTML/templates for apple-touch-icon link
 	if !hasAppleIcon {
-		// Check configured layout first
+		// Check configured layout first, along with the partials it
+		// includes (the link usually lives in a head partial)
 		cfg := ctx.Config.Checks.SEOMeta
 		if cfg != nil && cfg.MainLayout != "" {
 			layoutPath := filepath.Join(ctx.RootDir, cfg.MainLayout)
-			if content, err := os.ReadFile(layoutPath); err == nil {
-				if regexp.MustCompile(`(?i)apple-touch-icon`).Match(content) {
+			if content, err := readWithIncludes(layoutPath, ctx.RootDir, ctx.Config.Stack); err == nil {
+				if regexp.MustCompile(`(?i)apple-touch-icon`).MatchString(content) {
 					hasAppleIcon = true
 					found = append(found, "apple-touch-icon (in HTML)")
 				}
@@ -197,8 +170,8 @@ func (c FaviconCheck) Run(ctx Context) (CheckResult, error) {
 			}
 			for _, tplPath := range templatePaths {
 				fullPath := filepath.Join(ctx.RootDir, tplPath)
-				if content, err := os.ReadFile(fullPath); err == nil {
-					if regexp.MustCompile(`(?i)apple-touch-icon`).Match(content) {
+				if content, err := readWithIncludes(fullPath, ctx.RootDir, ctx.Config.Stack); err == nil {
+					if regexp.MustCompile(`(?i)apple-touch-icon`).MatchString(content) {
 						hasAppleIcon = true
 						found = append(found, "apple-touch-icon (in HTML)")
 						break
@@ -227,7 +200,7 @@ func (c FaviconCheck) Run(ctx Context) (CheckResult, error) {
 
 			for _, layoutPath := range allLayoutPaths {
 				fullPath := filepath.Join(ctx.RootDir, layoutPath)
-				if content, err := os.ReadFile(fullPath); err == nil {
+				if content, err := readFile(fullPath); err == nil {
 					// Check for Next.js metadata icons with apple property
 					if regexp.MustCompile(`(?i)icons\s*[:=]\s*\{[^}]*apple\s*:`).Match(content) {
 						hasAppleIcon = true
@@ -239,44 +212,14 @@ func (c FaviconCheck) Run(ctx Context) (CheckResult, error) {
 		}
 	}
 
-	// Flexible search: walk app directories for dynamic apple-icon files
+	// Flexible search: look through app directories for dynamic apple-icon files
 	if !hasAppleIcon {
-		flexAppleDirs := []string{"app", "src/app"}
-		for _, dir := range flexAppleDirs {
-			if hasAppleIcon {
-				break
-			}
-			dirPath := filepath.Join(ctx.RootDir, dir)
-			if _, err := os.Stat(dirPath); err != nil {
-				continue
-			}
-			_ = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					if info != nil && info.IsDir() {
-						return filepath.SkipDir
-					}
-					return nil
-				}
-				if hasAppleIcon {
-					return nil
-				}
-				if info.IsDir() {
-					name := info.Name()
-					if name == "node_modules" || name == ".git" {
-						return filepath.SkipDir
-					}
-					return nil
-				}
-				nameLower := strings.ToLower(info.Name())
-				// Match apple-icon.tsx, apple-icon.ts, etc.
-				if strings.HasPrefix(nameLower, "apple-icon.") && (strings.HasSuffix(nameLower, ".tsx") || strings.HasSuffix(nameLower, ".ts") || strings.HasSuffix(nameLower, ".jsx") || strings.HasSuffix(nameLower, ".js")) {
-					hasAppleIcon = true
-					relPath := relPath(ctx.RootDir, path)
-					found = append(found, relPath)
-					return nil
-				}
-				return nil
-			})
+		if rel := findAppDirFile(files, func(nameLower string) bool {
+			// Match apple-icon.tsx, apple-icon.ts, etc.
+			return strings.HasPrefix(nameLower, "apple-icon.") && (strings.HasSuffix(nameLower, ".tsx") || strings.HasSuffix(nameLower, ".ts") || strings.HasSuffix(nameLower, ".jsx") || strings.HasSuffix(nameLower, ".js"))
+		}); rel != "" {
+			hasAppleIcon = true
+			found = append(found, rel)
 		}
 	}
 
@@ -336,44 +279,14 @@ func (c FaviconCheck) Run(ctx Context) (CheckResult, error) {
 		}
 	}
 
-	// Flexible search: walk app directories for dynamic manifest files
+	// Flexible search: look through app directories for dynamic manifest files
 	if !hasManifest {
-		flexManifestDirs := []string{"app", "src/app"}
-		for _, dir := range flexManifestDirs {
-			if hasManifest {
-				break
-			}
-			dirPath := filepath.Join(ctx.RootDir, dir)
-			if _, err := os.Stat(dirPath); err != nil {
-				continue
-			}
-			_ = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					if info != nil && info.IsDir() {
-						return filepath.SkipDir
-					}
-					return nil
-				}
-				if hasManifest {
-					return nil
-				}
-				if info.IsDir() {
-					name := info.Name()
-					if name == "node_modules" || name == ".git" {
-						return filepath.SkipDir
-					}
-					return nil
-				}
-				nameLower := strings.ToLower(info.Name())
-				// Match manifest.ts, manifest.tsx, manifest.js, manifest.jsx, webmanifest files
-				if nameLower == "manifest.ts" || nameLower == "manifest.tsx" || nameLower == "manifest.js" || nameLower == "manifest.jsx" {
-					hasManifest = true
-					relPath := relPath(ctx.RootDir, path)
-					found = append(found, relPath)
-					return nil
-				}
-				return nil
-			})
+		if rel := findAppDirFile(files, func(nameLower string) bool {
+			// Match manifest.ts, manifest.tsx, manifest.js, manifest.jsx, webmanifest files
+			return nameLower == "manifest.ts" || nameLower == "manifest.tsx" || nameLower == "manifest.js" || nameLower == "manifest.jsx"
+		}); rel != "" {
+			hasManifest = true
+			found = append(found, rel)
 		}
 	}
 
@@ -411,14 +324,53 @@ func (c FaviconCheck) Run(ctx Context) (CheckResult, error) {
 		}
 	}
 
+	// A favicon that's present but empty, corrupt or 16x16 only is as
+	// good as missing on most screens.
+	var details, problems []string
+	for _, icon := range []struct {
+		path  string
+		apple bool
+	}{{faviconFile, false}, {appleIconFile, true}} {
+		if icon.path == "" {
+			continue
+		}
+		detail, problem := inspectIcon(icon.path, relPath(ctx.RootDir, icon.path), icon.apple)
+		if detail != "" {
+			details = append(details, detail)
+		}
+		if problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	problems = append(problems, iconRefProblems(ctx)...)
+
 	// Determine result
-	if len(missing) == 0 {
+	if len(missing) == 0 && len(problems) == 0 {
 		return CheckResult{
 			ID:       c.ID(),
 			Title:    c.Title(),
 			Severity: SeverityInfo,
 			Passed:   true,
 			Message:  "All icons and manifest present",
+			Details:  details,
+		}, nil
+	}
+
+	if len(missing) == 0 {
+		message := fmt.Sprintf("%d icon problems", len(problems))
+		if len(problems) == 1 {
+			message = problems[0]
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  message,
+			Suggestions: append([]string{
+				"Regenerate the icons: favicon.ico with 16x16, 32x32 and 48x48 images, and a 180x180 apple-touch-icon.png",
+			}, problems...),
+			Details: details,
 		}, nil
 	}
 
@@ -430,10 +382,11 @@ func (c FaviconCheck) Run(ctx Context) (CheckResult, error) {
 			Severity: SeverityWarn,
 			Passed:   false,
 			Message:  "Missing: " + strings.Join(missing, ", "),
-			Suggestions: []string{
+			Suggestions: append([]string{
 				"Add apple-touch-icon.png (180x180px) for iOS",
 				"Add manifest.json for PWA support",
-			},
+			}, problems...),
+			Details: details,
 		}, nil
 	}
 
@@ -450,35 +403,126 @@ func (c FaviconCheck) Run(ctx Context) (CheckResult, error) {
 	}, nil
 }
 
-// findMonorepoAppRouterPaths searches for a file in common monorepo structures
-// with Next.js App Router convention (apps/*/src/app/, packages/*/src/app/)
-func findMonorepoAppRouterPaths(rootDir, filename string) []string {
-	var paths []string
+// pngSignature starts every PNG file, including PNGs named favicon.ico.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
 
-	// Common monorepo directory names
-	monorepoRoots := []string{"apps", "packages", "services"}
+// inspectIcon opens the icon at path and returns its dimensions for the
+// details, and what's wrong with it as a favicon or, with apple set, an
+// apple-touch-icon. rel names the file in both.
+func inspectIcon(path, rel string, apple bool) (detail, problem string) {
+	f, err := openFile(path)
+	if err != nil {
+		return "", ""
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, netutil.MaxResponseBody))
+	if err != nil {
+		return "", ""
+	}
+	if len(data) == 0 {
+		return "", rel + " is empty (0 bytes)"
+	}
 
-	for _, monoRoot := range monorepoRoots {
-		monoDir := filepath.Join(rootDir, monoRoot)
-		entries, err := os.ReadDir(monoDir)
+	switch ext := strings.ToLower(filepath.Ext(path)); {
+	case ext == ".svg":
+		if apple {
+			return rel + ": SVG", rel + " is an SVG, which iOS doesn't use for home screen icons; add a 180x180 PNG"
+		}
+		if !bytes.Contains(bytes.ToLower(data[:min(len(data), 4096)]), []byte("<svg")) {
+			return "", rel + " has no <svg> element"
+		}
+		return rel + ": SVG", ""
+	case ext == ".ico" && !bytes.HasPrefix(data, pngSignature):
+		sizes, err := icoSizes(data)
 		if err != nil {
-			continue
+			return "", rel + " doesn't decode: " + err.Error()
+		}
+		has32 := false
+		var listed []string
+		for _, sz := range sizes {
+			has32 = has32 || sz == [2]int{32, 32}
+			listed = append(listed, fmt.Sprintf("%dx%d", sz[0], sz[1]))
 		}
+		detail = rel + ": " + strings.Join(listed, ", ")
+		if !has32 {
+			problem = rel + " has no 32x32 image (only " + strings.Join(listed, ", ") + ")"
+		}
+		return detail, problem
+	}
 
-		for _, entry := range entries {
-			if !entry.IsDir() {
-				continue
-			}
+	img, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", rel + " doesn't decode: " + err.Error()
+	}
+	detail = fmt.Sprintf("%s: %dx%d", rel, img.Width, img.Height)
+	switch {
+	case apple && (img.Width != img.Height || img.Width < 160 || img.Width > 200):
+		problem = fmt.Sprintf("%s is %dx%d; iOS expects 180x180", rel, img.Width, img.Height)
+	case !apple && (img.Width < 32 || img.Height < 32):
+		problem = fmt.Sprintf("%s is %dx%d; browsers want at least 32x32", rel, img.Width, img.Height)
+	}
+	return detail, problem
+}
 
-			// Check src/app/ pattern (standard Next.js App Router)
-			srcAppPath := filepath.Join(monoDir, entry.Name(), "src", "app", filename)
-			paths = append(paths, srcAppPath)
+// icoSizes reads the image directory of an ICO file and returns each
+// image's width and height, checking every image lies within the file
+// and holds PNG or BMP data.
+func icoSizes(data []byte) ([][2]int, error) {
+	if len(data) < 6 || binary.LittleEndian.Uint16(data[0:]) != 0 || binary.LittleEndian.Uint16(data[2:]) != 1 {
+		return nil, errors.New("not an ICO file")
+	}
+	n := int(binary.LittleEndian.Uint16(data[4:]))
+	if n == 0 {
+		return nil, errors.New("the ICO file holds no images")
+	}
+	if len(data) < 6+16*n {
+		return nil, errors.New("the ICO directory is truncated")
+	}
+	sizes := make([][2]int, 0, n)
+	for i := range n {
+		entry := data[6+16*i:]
+		w, h := int(entry[0]), int(entry[1])
+		if w == 0 {
+			w = 256
+		}
+		if h == 0 {
+			h = 256
+		}
+		size := uint64(binary.LittleEndian.Uint32(entry[8:]))
+		offset := uint64(binary.LittleEndian.Uint32(entry[12:]))
+		if size == 0 || offset+size > uint64(len(data)) {
+			return nil, fmt.Errorf("image %d (%dx%d) runs past the end of the file", i+1, w, h)
+		}
+		img := data[offset : offset+size]
+		if !bytes.HasPrefix(img, pngSignature) && (len(img) < 4 || binary.LittleEndian.Uint32(img) != 40) {
+			return nil, fmt.Errorf("image %d (%dx%d) isn't PNG or BMP data", i+1, w, h)
+		}
+		sizes = append(sizes, [2]int{w, h})
+	}
+	return sizes, nil
+}
 
-			// Check app/ pattern (alternative)
-			appPath := filepath.Join(monoDir, entry.Name(), "app", filename)
-			paths = append(paths, appPath)
+// findMonorepoAppRouterPaths searches for a file in common monorepo structures
+// with Next.js App Router convention (apps/*/src/app/, packages/*/src/app/)
+// findAppDirFile returns the project-relative path of the first file under
+// app/ or src/app/ whose lowercased name satisfies match, or "" if none
+// does.
+func findAppDirFile(files *FileIndex, match func(nameLower string) bool) string {
+	for _, dir := range []string{"app", "src/app"} {
+		for _, f := range files.Under(dir) {
+			if match(strings.ToLower(f.Name)) {
+				return f.Path
+			}
 		}
 	}
+	return ""
+}
 
+func findMonorepoAppRouterPaths(rootDir, filename string) []string {
+	var paths []string
+	for _, dir := range workspace.Dirs(rootDir, recordUnreadable) {
+		// src/app/ (standard Next.js App Router), then app/
+		paths = append(paths, filepath.Join(dir, "src", "app", filename), filepath.Join(dir, "app", filename))
+	}
 	return paths
 }
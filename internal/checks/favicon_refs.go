@@ -0,0 +1,189 @@
+package checks
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// iconWebRoots are the directories a site's static files are served
+// from, across frameworks; a file at <root>/favicon.png is served at
+// /favicon.png.
+var iconWebRoots = []string{
+	"public",  // Laravel, Rails, many Node.js
+	"static",  // Hugo, some SSGs
+	"web",     // Craft CMS, Symfony
+	"www",     // Some PHP apps
+	"dist",    // Built static sites
+	"build",   // Build outputs
+	"_site",   // Jekyll
+	"out",     // Next.js static export
+	"app",     // Next.js App Router (pages)
+	"src/app", // Next.js App Router (standard)
+	"",        // Root directory
+}
+
+var (
+	reLinkRels = regexp.MustCompile(`(?i)\brel\s*=\s*["']([^"']*)`)
+	// reIconFileName matches the files favicon generators write.
+	reIconFileName = regexp.MustCompile(`(?i)^(?:favicon|apple-touch-icon|android-chrome|mstile|safari-pinned-tab|icon)[\w.-]*\.(?:ico|png|svg|webp)$|^(?:site|manifest)\.webmanifest$`)
+	reXMLSrc       = regexp.MustCompile(`(?i)\bsrc\s*=\s*["']([^"']+)`)
+)
+
+// iconLinkRels are the rel values of links to an icon or the manifest.
+var iconLinkRels = map[string]bool{
+	"icon": true, "apple-touch-icon": true, "apple-touch-icon-precomposed": true, "manifest": true, "mask-icon": true,
+}
+
+// conventionalIcons are fetched from the web root by name when no link
+// says otherwise, so they're in use without being referenced.
+var conventionalIcons = map[string]bool{
+	"favicon.ico": true, "apple-touch-icon.png": true, "apple-touch-icon-precomposed.png": true,
+}
+
+// iconRefProblems cross-checks the icon and manifest links in the shared
+// markup against the web roots: a link to a file that isn't there is a
+// broken icon, and an icon file nothing links to (or lists in the
+// manifest) is probably a stale one the generator left behind. The second
+// needs at least one link to go on, or every icon would count.
+func iconRefProblems(ctx Context) []string {
+	hosts := map[string]bool{}
+	for _, raw := range []string{ctx.Config.URLs.Production, ctx.Config.URLs.Staging} {
+		if u, err := url.Parse(raw); err == nil && u.Host != "" {
+			hosts[strings.ToLower(u.Hostname())] = true
+		}
+	}
+
+	var problems []string
+	referenced := map[string]bool{}
+	links := 0
+	for _, src := range sharedMarkup(ctx) {
+		// The rendered page may link build output (hashed assets) that
+		// isn't in the tree; it only counts toward what's referenced.
+		rendered := ctx.PageHTML != "" && src.content == ctx.PageHTML
+		for _, tag := range reLinkTag.FindAllString(src.content, -1) {
+			rels := reLinkRels.FindStringSubmatch(tag)
+			m := reHrefAttr.FindStringSubmatch(tag)
+			if rels == nil || m == nil {
+				continue
+			}
+			rel := false
+			for _, r := range strings.Fields(strings.ToLower(rels[1])) {
+				rel = rel || iconLinkRels[r]
+			}
+			if !rel {
+				continue
+			}
+			p, ok := iconRefPath(m[1], hosts)
+			if !ok || referenced[p] {
+				continue
+			}
+			links++
+			referenced[p] = true
+			if !rendered && iconServedFile(ctx.RootDir, p) == "" {
+				problems = append(problems, src.name+" links /"+p+", which isn't in the web root")
+			}
+		}
+	}
+	if links == 0 {
+		return problems
+	}
+
+	// Icons listed in the manifests and browserconfig.xml are referenced
+	// too, relative to the file that lists them.
+	listings := []string{"site.webmanifest", "manifest.json", "manifest.webmanifest", "browserconfig.xml"}
+	for p := range referenced {
+		listings = append(listings, p)
+	}
+	for _, p := range listings {
+		file := iconServedFile(ctx.RootDir, p)
+		if file == "" {
+			continue
+		}
+		data, err := readFile(file)
+		if err != nil {
+			continue
+		}
+		var srcs []string
+		if strings.HasSuffix(p, ".xml") {
+			for _, m := range reXMLSrc.FindAllStringSubmatch(string(data), -1) {
+				srcs = append(srcs, m[1])
+			}
+		} else if strings.HasSuffix(p, ".webmanifest") || strings.HasSuffix(p, ".json") {
+			var manifest struct {
+				Icons []struct {
+					Src string `json:"src"`
+				} `json:"icons"`
+			}
+			if json.Unmarshal(data, &manifest) != nil {
+				continue
+			}
+			for _, icon := range manifest.Icons {
+				srcs = append(srcs, icon.Src)
+			}
+		}
+		for _, s := range srcs {
+			if !strings.HasPrefix(s, "/") && !strings.Contains(s, "://") {
+				s = "/" + path.Join(path.Dir(p), s)
+			}
+			if ref, ok := iconRefPath(s, hosts); ok {
+				referenced[ref] = true
+			}
+		}
+	}
+
+	files := ctx.files()
+	seen := map[string]bool{}
+	for _, root := range iconWebRoots {
+		// Next.js links the icon files in app/ itself, and a project
+		// root holds too much else to judge.
+		if root == "" || root == "app" || root == "src/app" {
+			continue
+		}
+		for _, f := range files.Under(root) {
+			p := strings.TrimPrefix(f.Path, root+"/")
+			if f.Ignored || !reIconFileName.MatchString(f.Name) || strings.Count(p, "/") > 1 || seen[p] {
+				continue
+			}
+			seen[p] = true
+			if !referenced[p] && !conventionalIcons[p] {
+				problems = append(problems, f.Path+" isn't linked from the layout or listed in the manifest")
+			}
+		}
+	}
+	return problems
+}
+
+// iconRefPath returns the web-root-relative path an icon link's href
+// points at, or false for a template expression, a data: URI or another
+// host.
+func iconRefPath(href string, hosts map[string]bool) (string, bool) {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.ContainsAny(href, "{}<>$@()") {
+		return "", false
+	}
+	u, err := url.Parse(href)
+	if err != nil || (u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https") {
+		return "", false
+	}
+	if u.Host != "" && !hosts[strings.ToLower(u.Hostname())] {
+		return "", false
+	}
+	p := strings.TrimPrefix(path.Clean("/"+u.Path), "/")
+	return p, p != "" && p != "."
+}
+
+// iconServedFile returns the file serving web-root path p, or "".
+func iconServedFile(rootDir, p string) string {
+	for _, root := range iconWebRoots {
+		file := filepath.Join(rootDir, root, filepath.FromSlash(p))
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file
+		}
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package checks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+// testPNG encodes a blank w×h PNG.
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testICO builds an ICO file holding a square PNG of each size.
+func testICO(t *testing.T, sizes ...int) string {
+	t.Helper()
+	var dir, images bytes.Buffer
+	header := []uint16{0, 1, uint16(len(sizes))}
+	binary.Write(&dir, binary.LittleEndian, header)
+	offset := 6 + 16*len(sizes)
+	for _, size := range sizes {
+		data := testPNG(t, size, size)
+		binary.Write(&dir, binary.LittleEndian, struct {
+			W, H, Colors, Reserved uint8
+			Planes, BPP            uint16
+			Size, Offset           uint32
+		}{uint8(size % 256), uint8(size % 256), 0, 0, 1, 32, uint32(len(data)), uint32(offset)})
+		images.Write(data)
+		offset += len(data)
+	}
+	return dir.String() + images.String()
+}
+
+func TestFaviconInspectsIcons(t *testing.T) {
+	cases := []struct {
+		name  string
+		files map[string]string
+		// want is a problem the check should report, or for a passing
+		// case the details it should list.
+		want   []string
+		passed bool
+	}{
+		{
+			name:   "sized icons",
+			files:  map[string]string{"public/favicon.ico": testICO(t, 16, 32, 48), "public/apple-touch-icon.png": string(testPNG(t, 180, 180))},
+			want:   []string{"public/favicon.ico: 16x16, 32x32, 48x48", "public/apple-touch-icon.png: 180x180"},
+			passed: true,
+		},
+		{name: "empty", files: map[string]string{"public/favicon.ico": ""}, want: []string{"public/favicon.ico is empty (0 bytes)"}},
+		{name: "16 only", files: map[string]string{"public/favicon.ico": testICO(t, 16)}, want: []string{"public/favicon.ico has no 32x32 image (only 16x16)"}},
+		{name: "truncated", files: map[string]string{"public/favicon.ico": testICO(t, 32)[:40]}, want: []string{"runs past the end of the file"}},
+		{name: "small png", files: map[string]string{"public/favicon.png": string(testPNG(t, 16, 16))}, want: []string{"public/favicon.png is 16x16; browsers want at least 32x32"}},
+		{name: "corrupt", files: map[string]string{"public/favicon.png": "not a png"}, want: []string{"public/favicon.png doesn't decode"}},
+		{name: "old apple", files: map[string]string{"public/favicon.ico": testICO(t, 32), "public/apple-touch-icon.png": string(testPNG(t, 57, 57))}, want: []string{"public/apple-touch-icon.png is 57x57; iOS expects 180x180"}},
+		{name: "svg apple", files: map[string]string{"public/favicon.svg": "<svg/>", "public/apple-touch-icon.svg": "<svg/>"}, want: []string{"iOS doesn't use for home screen icons"}},
+		{name: "png as ico", files: map[string]string{"public/favicon.ico": string(testPNG(t, 48, 48)), "public/apple-touch-icon.png": string(testPNG(t, 180, 180))}, passed: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.files["public/site.webmanifest"] = "{}"
+			result, err := FaviconCheck{}.Run(Context{RootDir: writeFiles(t, tc.files), Config: &config.PreflightConfig{}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tc.passed {
+				t.Fatalf("passed = %v, want %v: %q %v", result.Passed, tc.passed, result.Message, result.Suggestions)
+			}
+			joined := result.Message + "\n" + strings.Join(result.Suggestions, "\n")
+			if tc.passed {
+				joined = strings.Join(result.Details, "\n")
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(joined, want) {
+					t.Errorf("want %q, got %s", want, joined)
+				}
+			}
+		})
+	}
+}
+
+func TestFaviconCrossChecksIconLinks(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"public/favicon.ico":            testICO(t, 16, 32),
+		"public/apple-touch-icon.png":   string(testPNG(t, 180, 180)),
+		"public/favicon-32x32.png":      string(testPNG(t, 32, 32)),
+		"public/favicon-16x16.png":      string(testPNG(t, 16, 16)),
+		"public/android-chrome-192.png": string(testPNG(t, 192, 192)),
+		"public/site.webmanifest":       `{"icons": [{"src": "android-chrome-192.png", "sizes": "192x192"}]}`,
+		"views/layout.html": `<head>
+<link rel="icon" type="image/png" sizes="32x32" href="/favicon-32x32.png">
+<link rel="icon" href="/icons/favicon.svg">
+<link rel="icon" href="{{ asset('favicon.png') }}">
+<link rel="manifest" href="/site.webmanifest">
+<link rel="icon" href="https://cdn.example.net/favicon.png">
+</head>`,
+	})
+	cfg := &config.PreflightConfig{Stack: "node"}
+	cfg.Checks.SEOMeta = &config.SEOMetaConfig{MainLayout: "views/layout.html"}
+	result, err := FaviconCheck{}.Run(Context{RootDir: dir, Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed || result.Message != "2 icon problems" {
+		t.Fatalf("passed=%v message=%q", result.Passed, result.Message)
+	}
+	suggestions := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{
+		"views/layout.html links /icons/favicon.svg, which isn't in the web root",
+		"public/favicon-16x16.png isn't linked from the layout or listed in the manifest",
+	} {
+		if !strings.Contains(suggestions, want) {
+			t.Errorf("missing %q in %v", want, result.Suggestions)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package checks
+
+import (
+	"regexp"
+)
+
+// Feature flag provider checks. All follow the standard ServiceCheck shape:
+// declared → env var → SDK initialization in code.
+
+// LaunchDarklyCheck verifies LaunchDarkly is properly set up.
+var LaunchDarklyCheck = ServiceCheck{
+	CheckID:     "launchdarkly",
+	CheckTitle:  "LaunchDarkly",
+	EnvPrefixes: []string{"LAUNCHDARKLY_", "LD_SDK_KEY", "LD_CLIENT_SIDE_ID", "NEXT_PUBLIC_LD_", "NEXT_PUBLIC_LAUNCHDARKLY"},
+	CodePatterns: []*regexp.Regexp{
+		regexp.MustCompile(`@launchdarkly/`),
+		regexp.MustCompile(`launchdarkly-(?:js-client-sdk|node-server-sdk|react-client-sdk|server-sdk)`),
+		regexp.MustCompile(`LDClient\.(?:initialize|init)|LaunchDarkly\.init`),
+		regexp.MustCompile(`withLDProvider|asyncWithLDProvider|LDProvider`),
+		regexp.MustCompile(`import\s+ldclient`),
+		regexp.MustCompile(`LaunchDarkly::LDClient`),
+		regexp.MustCompile(`launchdarkly/go-server-sdk`),
+	},
+	EnvFoundMsg:  "LaunchDarkly SDK key found in environment",
+	CodeFoundMsg: "LaunchDarkly SDK initialization found",
+	NotFoundMsg:  "LaunchDarkly is declared but SDK not found",
+	NotFoundSuggestions: []string{
+		"Add LAUNCHDARKLY_SDK_KEY (server) or the client-side ID to environment",
+		"Initialize the LaunchDarkly client in your application",
+	},
+}
+
+// FlagsmithCheck verifies Flagsmith is properly set up.
+var FlagsmithCheck = ServiceCheck{
+	CheckID:     "flagsmith",
+	CheckTitle:  "Flagsmith",
+	EnvPrefixes: []string{"FLAGSMITH_", "NEXT_PUBLIC_FLAGSMITH"},
+	CodePatterns: []*regexp.Regexp{
+		regexp.MustCompile(`from\s+["']flagsmith(?:/[\w-]+)?["']`),
+		regexp.MustCompile(`require\s*\(\s*["']flagsmith(?:-nodejs)?["']\)`),
+		regexp.MustCompile(`flagsmith\.init\(|new Flagsmith\(|Flagsmith\(\s*(?:environment_key|\{)`),
+		regexp.MustCompile(`FlagsmithProvider`),
+		regexp.MustCompile(`from\s+flagsmith\s+import`),
+		regexp.MustCompile(`Flagsmith::Client`),
+		regexp.MustCompile(`flagsmith\.com/api`),
+	},
+	EnvFoundMsg:  "Flagsmith environment key found in environment",
+	CodeFoundMsg: "Flagsmith SDK initialization found",
+	NotFoundMsg:  "Flagsmith is declared but SDK not found",
+	NotFoundSuggestions: []string{
+		"Add FLAGSMITH_ENVIRONMENT_KEY to environment",
+		"Initialize Flagsmith in your application",
+	},
+}
+
+// UnleashCheck verifies Unleash is properly set up.
+var UnleashCheck = ServiceCheck{
+	CheckID:     "unleash",
+	CheckTitle:  "Unleash",
+	EnvPrefixes: []string{"UNLEASH_", "NEXT_PUBLIC_UNLEASH"},
+	CodePatterns: []*regexp.Regexp{
+		regexp.MustCompile(`@unleash/`),
+		regexp.MustCompile(`unleash-(?:client|proxy-client)`),
+		regexp.MustCompile(`new UnleashClient\(|initialize\(\s*\{[^}]*appName|startUnleash\(`),
+		regexp.MustCompile(`from\s+UnleashClient\s+import`),
+		regexp.MustCompile(`Unleash::Client`),
+		regexp.MustCompile(`Unleash/unleash-client-go`),
+	},
+	EnvFoundMsg:  "Unleash API URL or token found in environment",
+	CodeFoundMsg: "Unleash SDK initialization found",
+	NotFoundMsg:  "Unleash is declared but SDK not found",
+	NotFoundSuggestions: []string{
+		"Add UNLEASH_URL and UNLEASH_API_TOKEN to environment",
+		"Initialize the Unleash client in your application",
+	},
+}
+
+// PostHogFlagsCheck verifies PostHog feature flags are evaluated somewhere.
+// The posthog check covers the SDK itself; this one looks for the flag
+// calls, since flags declared in PostHog but never read do nothing.
+var PostHogFlagsCheck = ServiceCheck{
+	CheckID:     "posthog_flags",
+	CheckTitle:  "PostHog Feature Flags",
+	EnvPrefixes: []string{"POSTHOG_PERSONAL_API_KEY", "POSTHOG_FEATURE_FLAGS_SECURE_API_KEY"},
+	CodePatterns: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)posthog\.(?:isFeatureEnabled|getFeatureFlag|getFeatureFlagPayload|onFeatureFlags|getAllFlags|feature_enabled|get_feature_flag)\(`),
+		regexp.MustCompile(`useFeatureFlagEnabled|useFeatureFlagVariantKey|useFeatureFlagPayload|PostHogFeature`),
+	},
+	EnvFoundMsg:  "PostHog key for local flag evaluation found in environment",
+	CodeFoundMsg: "PostHog feature flag evaluation found",
+	NotFoundMsg:  "PostHog feature flags are declared but no flag is read in code",
+	NotFoundSuggestions: []string{
+		"Read flags with posthog.isFeatureEnabled() or the useFeatureFlagEnabled() React hook",
+		"Check PostHog docs on feature flags for your framework",
+	},
+}
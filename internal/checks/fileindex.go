@@ -0,0 +1,142 @@
+package checks
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/gitignore"
+)
+
+// indexSkipDirs are left out of the file index entirely. Every check that
+// queries the index skips them anyway, and on a JavaScript project
+// node_modules alone is most of the tree.
+var indexSkipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+}
+
+// FileIndex lists the project's regular files, collected in one walk at
+// scan start. Checks that look for files by name, extension or size query
+// it instead of each walking the tree again, which on a large repository
+// is most of a scan's run time.
+//
+// Only regular files are listed: symlinks, devices and pipes are dropped
+// at build time, so no check can be tricked into reading outside the
+// project root or from /dev/zero through the index. The one way out of
+// the root is walk.followSymlinks, which lists the files of symlinked
+// directories under the link's path.
+type FileIndex struct {
+	Root  string
+	Files []FileEntry // in walk order
+	// Unreadable counts the directories and files the walk could not
+	// read, for checks that report how complete their scan was.
+	Unreadable int
+}
+
+// FileEntry is one file in the index.
+type FileEntry struct {
+	Path string // relative to the project root, forward slashes
+	Name string // base name
+	Ext  string // lowercased filepath.Ext, "" when the name has none
+	Size int64
+	// Ignored is set when the project's .gitignore files (or
+	// .git/info/exclude) exclude the file, directly or through one of its
+	// directories. Ignored files stay in the index: inside a repository a
+	// tracked file can still be listed in .gitignore, and the secrets
+	// check needs to see it.
+	Ignored bool
+}
+
+// BuildFileIndex walks rootDir once and records every regular file
+// outside indexSkipDirs, reading each directory's .gitignore as it goes.
+func BuildFileIndex(rootDir string) *FileIndex {
+	ix := &FileIndex{Root: rootDir}
+	var ignores gitignore.Matcher
+	if content, err := readFile(filepath.Join(rootDir, ".git", "info", "exclude")); err == nil {
+		ignores.Add("", content)
+	}
+	ignoredDirs := map[string]bool{}
+	ignored := func(rel string, isDir bool) bool {
+		return ignoredDirs[path.Dir(rel)] || ignores.Match(rel, isDir)
+	}
+	_ = walkDir(rootDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			recordUnreadable(p, err)
+			ix.Unreadable++
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel := filepath.ToSlash(relPath(rootDir, p))
+		if d.IsDir() {
+			if p == rootDir {
+				rel = ""
+			} else if indexSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			} else if ignored(rel, true) {
+				ignoredDirs[rel] = true
+			}
+			// Rules for this directory's entries have to be in place
+			// before the walk reaches them.
+			if content, err := readFile(filepath.Join(p, ".gitignore")); err == nil {
+				ignores.Add(rel, content)
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			recordUnreadable(p, err)
+			ix.Unreadable++
+			return nil
+		}
+		ix.Files = append(ix.Files, FileEntry{
+			Path:    rel,
+			Name:    d.Name(),
+			Ext:     strings.ToLower(filepath.Ext(d.Name())),
+			Size:    info.Size(),
+			Ignored: ignored(rel, false),
+		})
+		return nil
+	})
+	return ix
+}
+
+// Under returns the entries beneath dir, a project-relative directory.
+// An empty dir or "." returns every entry.
+func (ix *FileIndex) Under(dir string) []FileEntry {
+	dir = strings.Trim(filepath.ToSlash(filepath.Clean(dir)), "/")
+	if dir == "" || dir == "." {
+		return ix.Files
+	}
+	prefix := dir + "/"
+	var out []FileEntry
+	for _, f := range ix.Files {
+		if strings.HasPrefix(f.Path, prefix) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Abs returns the entry's path on disk.
+func (ix *FileIndex) Abs(f FileEntry) string {
+	return filepath.Join(ix.Root, filepath.FromSlash(f.Path))
+}
+
+// inDir reports whether any directory on the entry's path is named in
+// dirs, the index's stand-in for returning filepath.SkipDir from a walk.
+func (f FileEntry) inDir(dirs map[string]bool) bool {
+	parts := strings.Split(f.Path, "/")
+	for _, part := range parts[:len(parts)-1] {
+		if dirs[part] {
+			return true
+		}
+	}
+	return false
+}
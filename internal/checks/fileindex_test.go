@@ -0,0 +1,117 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBuildFileIndex(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"app/page.tsx":                  "export default function Page() {}",
+		"app/icon.TSX":                  "",
+		"src/app/layout.tsx":            "",
+		"apps/web/index.js":             "", // shares a prefix with app/
+		".env.production":               "KEY=1",
+		".git/config":                   "",
+		"node_modules/react/index.js":   "",
+		"packages/ui/node_modules/x.js": "",
+	})
+	if err := os.Symlink(filepath.Join(dir, "app", "page.tsx"), filepath.Join(dir, "app", "link.tsx")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	ix := BuildFileIndex(dir)
+
+	var paths []string
+	for _, f := range ix.Files {
+		paths = append(paths, f.Path)
+	}
+	want := []string{".env.production", "app/icon.TSX", "app/page.tsx", "apps/web/index.js", "src/app/layout.tsx"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("indexed %v, want %v", paths, want)
+	}
+
+	var under []string
+	for _, f := range ix.Under("app") {
+		under = append(under, f.Path)
+	}
+	if want := []string{"app/icon.TSX", "app/page.tsx"}; !reflect.DeepEqual(under, want) {
+		t.Errorf("Under(app) = %v, want %v", under, want)
+	}
+	if got := len(ix.Under("")); got != len(ix.Files) {
+		t.Errorf("Under(\"\") returned %d entries, want all %d", got, len(ix.Files))
+	}
+
+	for _, f := range ix.Files {
+		switch f.Path {
+		case "app/icon.TSX":
+			if f.Ext != ".tsx" || f.Name != "icon.TSX" {
+				t.Errorf("icon entry = %+v, want lowercased ext and original name", f)
+			}
+		case "app/page.tsx":
+			if f.Size != int64(len("export default function Page() {}")) {
+				t.Errorf("page.tsx size = %d", f.Size)
+			}
+			if ix.Abs(f) != filepath.Join(dir, "app", "page.tsx") {
+				t.Errorf("Abs = %s", ix.Abs(f))
+			}
+		}
+	}
+}
+
+func TestFileEntryInDir(t *testing.T) {
+	skip := map[string]bool{"vendor": true}
+	for path, want := range map[string]bool{
+		"vendor/lib.rb":     true,
+		"app/vendor/lib.rb": true,
+		"vendor.rb":         false,
+		"app/models/x.rb":   false,
+	} {
+		if got := (FileEntry{Path: path}).inDir(skip); got != want {
+			t.Errorf("inDir(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestBuildFileIndexGitignore(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".gitignore":             "generated/\n*.gen.js\n",
+		"web/.gitignore":         ".output\n!keep.gen.js\n",
+		"generated/client.js":    "",
+		"generated/nested/a.js":  "",
+		"src/api.gen.js":         "",
+		"src/app.js":             "",
+		"web/.output/server.js":  "",
+		"web/keep.gen.js":        "",
+		".git/info/exclude":      "scratch.js\n",
+		"scratch.js":             "",
+		"other/.output/index.js": "", // web/.gitignore doesn't reach here
+	})
+
+	ignored := map[string]bool{}
+	for _, f := range BuildFileIndex(dir).Files {
+		ignored[f.Path] = f.Ignored
+	}
+	for path, want := range map[string]bool{
+		"generated/client.js":    true,
+		"generated/nested/a.js":  true,
+		"src/api.gen.js":         true,
+		"src/app.js":             false,
+		"web/.output/server.js":  true,
+		"web/keep.gen.js":        false,
+		"scratch.js":             true,
+		"other/.output/index.js": false,
+		".gitignore":             false,
+	} {
+		got, ok := ignored[path]
+		if !ok {
+			t.Errorf("%s missing from the index", path)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: Ignored = %v, want %v", path, got, want)
+		}
+	}
+}
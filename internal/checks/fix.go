@@ -0,0 +1,471 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Fix is one change `preflight fix` can make to close a failing check:
+// a file to create, or an existing file's new content.
+type Fix struct {
+	CheckID string
+	Path    string // relative to the project root, forward slashes
+	Summary string
+	Create  bool   // Path doesn't exist yet
+	Content []byte // the full new content of Path
+	Notes   []string
+}
+
+// fixers holds the checks `preflight fix` knows how to repair. Each one
+// plans from the check's result and the tree; nothing is written until
+// the caller applies the fixes.
+var fixers = map[string]func(ctx Context, result CheckResult) ([]Fix, error){
+	"robotsTxt":        fixRobotsTxt,
+	"favicon":          fixFavicon,
+	"error_pages":      fixErrorPages,
+	"debug_statements": fixDebugStatements,
+}
+
+// FixableCheckIDs lists the checks that have an automatic fix, in
+// Registry order.
+func FixableCheckIDs() []string {
+	var ids []string
+	for _, check := range Registry {
+		if _, ok := fixers[check.ID()]; ok {
+			ids = append(ids, check.ID())
+		}
+	}
+	return ids
+}
+
+// PlanFixes returns the fixes for the failed results it knows how to
+// repair. Passing results and checks without a fixer are skipped.
+func PlanFixes(ctx Context, results []CheckResult) ([]Fix, error) {
+	var fixes []Fix
+	for _, r := range results {
+		fixer, ok := fixers[r.ID]
+		if !ok || r.Passed {
+			continue
+		}
+		planned, err := fixer(ctx, r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.ID, err)
+		}
+		fixes = append(fixes, planned...)
+	}
+	return fixes, nil
+}
+
+// Apply writes the fix under rootDir. A fix that creates a file refuses
+// to overwrite one that has appeared since it was planned.
+func (f Fix) Apply(rootDir string) error {
+	path := filepath.Join(rootDir, filepath.FromSlash(f.Path))
+	if f.Create {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(f.Content); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, f.Content, info.Mode().Perm())
+}
+
+// webRootFor picks the directory static files are served from: the first
+// conventional web root that exists, else the stack's usual one, else the
+// project root. Build output directories are never picked, since anything
+// written there is lost on the next build.
+func webRootFor(ctx Context) string {
+	for _, dir := range []string{"public", "static", "web", "www"} {
+		if info, err := os.Stat(filepath.Join(ctx.RootDir, dir)); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	switch ctx.Config.Stack {
+	case "hugo":
+		return "static"
+	case "craft", "symfony":
+		return "web"
+	case "", "static", "jekyll", "eleventy", "wordpress":
+		return ""
+	}
+	return "public"
+}
+
+func joinRel(dir, name string) string {
+	if dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
+
+func fixRobotsTxt(ctx Context, _ CheckResult) ([]Fix, error) {
+	content := "User-agent: *\nAllow: /\n"
+	if prod := strings.TrimSuffix(ctx.Config.URLs.Production, "/"); prod != "" {
+		content += "\nSitemap: " + prod + "/sitemap.xml\n"
+	}
+	path := joinRel(webRootFor(ctx), "robots.txt")
+	return []Fix{{
+		CheckID: "robotsTxt",
+		Path:    path,
+		Summary: "create " + path + " allowing all crawlers",
+		Create:  true,
+		Content: []byte(content),
+	}}, nil
+}
+
+// fixFavicon covers what the favicon check reports as missing besides the
+// favicon itself, which is an image only the project can supply.
+func fixFavicon(ctx Context, result CheckResult) ([]Fix, error) {
+	missing := result.Message
+	if !strings.HasPrefix(missing, "Missing: ") {
+		return nil, nil
+	}
+	root := webRootFor(ctx)
+
+	var fixes []Fix
+	var links []string
+	var notes []string
+	if strings.Contains(missing, "web manifest") {
+		manifestPath := joinRel(root, "site.webmanifest")
+		name := ctx.Config.ProjectName
+		if name == "" {
+			name = filepath.Base(ctx.RootDir)
+		}
+		manifest, err := json.MarshalIndent(map[string]any{
+			"name":             name,
+			"short_name":       name,
+			"start_url":        "/",
+			"display":          "standalone",
+			"background_color": "#ffffff",
+			"theme_color":      "#ffffff",
+			"icons":            []any{},
+		}, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		fixes = append(fixes, Fix{
+			CheckID: "favicon",
+			Path:    manifestPath,
+			Summary: "create " + manifestPath,
+			Create:  true,
+			Content: append(manifest, '\n'),
+			Notes:   []string{"Add your app icons to the manifest's icons list"},
+		})
+		links = append(links, `<link rel="manifest" href="/site.webmanifest">`)
+	}
+	if strings.Contains(missing, "apple-touch-icon") {
+		links = append(links, `<link rel="apple-touch-icon" sizes="180x180" href="/apple-touch-icon.png">`)
+		if _, err := os.Stat(filepath.Join(ctx.RootDir, filepath.FromSlash(joinRel(root, "apple-touch-icon.png")))); err != nil {
+			where := "the project root"
+			if root != "" {
+				where = root + "/"
+			}
+			notes = append(notes, "Add a 180x180 apple-touch-icon.png to "+where)
+		}
+	}
+	if len(links) == 0 {
+		return fixes, nil
+	}
+
+	layout, content := layoutWithHead(ctx)
+	if layout == "" {
+		return fixes, nil
+	}
+	edited, ok := insertBeforeHeadClose(content, links)
+	if !ok {
+		return fixes, nil
+	}
+	return append(fixes, Fix{
+		CheckID: "favicon",
+		Path:    layout,
+		Summary: fmt.Sprintf("add %d icon link tag(s) to %s", len(links), layout),
+		Content: []byte(edited),
+		Notes:   notes,
+	}), nil
+}
+
+// layoutWithHead returns the first layout candidate, configured mainLayout
+// first, that closes a <head> element, along with its content. JSX and
+// other layouts without a literal </head> have nowhere to put a tag.
+func layoutWithHead(ctx Context) (string, string) {
+	for _, candidate := range layoutCandidates(ctx) {
+		content, err := readFile(filepath.Join(ctx.RootDir, filepath.FromSlash(candidate)))
+		if err != nil {
+			continue
+		}
+		if headClosePattern.Match(content) {
+			return filepath.ToSlash(candidate), string(content)
+		}
+	}
+	return "", ""
+}
+
+var headClosePattern = regexp.MustCompile(`(?i)</head\s*>`)
+
+// insertBeforeHeadClose adds lines just above </head>, indented to match
+// the line before it.
+func insertBeforeHeadClose(content string, lines []string) (string, bool) {
+	loc := headClosePattern.FindStringIndex(content)
+	if loc == nil {
+		return content, false
+	}
+	lineStart := strings.LastIndex(content[:loc[0]], "\n") + 1
+	closeIndent := content[lineStart:loc[0]]
+	if strings.TrimSpace(closeIndent) != "" {
+		// </head> shares its line with other markup; insert inline.
+		return content[:loc[0]] + strings.Join(lines, "") + content[loc[0]:], true
+	}
+	indent := closeIndent + "  "
+	if prevEnd := lineStart - 1; prevEnd > 0 {
+		prevStart := strings.LastIndex(content[:prevEnd], "\n") + 1
+		prev := content[prevStart:prevEnd]
+		indent = prev[:len(prev)-len(strings.TrimLeft(prev, " \t"))]
+	}
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(indent + l + "\n")
+	}
+	return content[:lineStart] + b.String() + content[lineStart:], true
+}
+
+func fixErrorPages(ctx Context, _ CheckResult) ([]Fix, error) {
+	path := errorPageTarget(ctx)
+	content := notFoundPage(path, ctx.Config.Stack)
+	if content == "" {
+		return nil, nil
+	}
+	return []Fix{{
+		CheckID: "error_pages",
+		Path:    path,
+		Summary: "create a 404 page at " + path,
+		Create:  true,
+		Content: []byte(content),
+		Notes:   []string{"Style " + path + " to match the rest of the site"},
+	}}, nil
+}
+
+// errorPageTarget returns the first of the stack's 404 paths whose
+// directory already exists, so a Next.js App Router project gets
+// app/not-found.tsx rather than a pages/ directory it doesn't use. Failing
+// that it takes the stack's first path, and without one a static 404.html
+// goes in the web root.
+func errorPageTarget(ctx Context) string {
+	paths404, _ := getErrorPagePaths(ctx.Config.Stack)
+	first := ""
+	for _, p := range paths404 {
+		if notFoundPage(p, ctx.Config.Stack) == "" {
+			continue
+		}
+		if first == "" {
+			first = p
+		}
+		dir := filepath.Dir(filepath.FromSlash(p))
+		if dir == "." {
+			return p
+		}
+		if info, err := os.Stat(filepath.Join(ctx.RootDir, dir)); err == nil && info.IsDir() {
+			return p
+		}
+	}
+	if first != "" {
+		return first
+	}
+	return joinRel(webRootFor(ctx), "404.html")
+}
+
+const notFoundHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>Page not found</title>
+</head>
+<body>
+  <main>
+    <h1>Page not found</h1>
+    <p>Sorry, we couldn't find the page you were looking for.</p>
+    <p><a href="/">Go to the homepage</a></p>
+  </main>
+</body>
+</html>
+`
+
+// notFoundPage returns a starter 404 page for path's file type, or "" for
+// types it can't write one for (such as Markdown).
+func notFoundPage(path, stack string) string {
+	switch {
+	case strings.HasSuffix(path, ".tsx"), strings.HasSuffix(path, ".jsx"), strings.HasSuffix(path, ".js"):
+		return `export default function NotFound() {
+  return (
+    <main>
+      <h1>Page not found</h1>
+      <p>Sorry, we couldn't find the page you were looking for.</p>
+      <a href="/">Go to the homepage</a>
+    </main>
+  )
+}
+`
+	case strings.HasSuffix(path, ".vue"):
+		return `<template>
+  <main>
+    <h1>Page not found</h1>
+    <p>Sorry, we couldn't find the page you were looking for.</p>
+    <a href="/">Go to the homepage</a>
+  </main>
+</template>
+`
+	case strings.HasSuffix(path, ".php") && stack == "wordpress":
+		return `<?php get_header(); ?>
+<main>
+  <h1>Page not found</h1>
+  <p>Sorry, we couldn't find the page you were looking for.</p>
+  <p><a href="<?php echo esc_url(home_url('/')); ?>">Go to the homepage</a></p>
+</main>
+<?php get_footer(); ?>
+`
+	case hasExtension(path, []string{".html", ".htm", ".php", ".twig", ".njk", ".liquid", ".hbs", ".astro", ".svelte"}):
+		return notFoundHTML
+	}
+	return ""
+}
+
+// removableDebugLine matches a line that is nothing but one debug call,
+// so deleting it can't take real code with it. Calls split across lines
+// or sharing a line with other code are left for a person to remove.
+var removableDebugLine = regexp.MustCompile(`^(?:console\.(?:log|debug|info|trace|dir|table)|var_dump|print_r|dd|dump|ray|pdb\.set_trace|ipdb\.set_trace|breakpoint|spew\.Dump|dbg!|System\.out\.println|System\.out\.print|IO\.inspect)\s*\(.*\)\s*;?$|^(?:debugger|binding\.pry|binding\.irb|byebug|IEx\.pry|import i?pdb)\s*;?$`)
+
+func fixDebugStatements(ctx Context, result CheckResult) ([]Fix, error) {
+	byFile := map[string]map[int]bool{}
+	var order []string
+	for _, loc := range result.Locations {
+		if loc.Line == 0 {
+			continue
+		}
+		if byFile[loc.File] == nil {
+			byFile[loc.File] = map[int]bool{}
+			order = append(order, loc.File)
+		}
+		byFile[loc.File][loc.Line] = true
+	}
+
+	var fixes []Fix
+	for _, rel := range order {
+		path := filepath.Join(ctx.RootDir, filepath.FromSlash(rel))
+		content, err := readFile(path)
+		if err != nil {
+			continue
+		}
+		lines := strings.SplitAfter(string(content), "\n")
+		remove := map[int]bool{}
+		for i, line := range lines {
+			if byFile[rel][i+1] && isRemovableDebugLine(line) && !isBlockBody(lines, i) {
+				remove[i] = true
+			}
+		}
+		// An import goes only with the last use of what it imports.
+		for i := range remove {
+			if m := reDebugImport.FindStringSubmatch(strings.TrimSpace(lines[i])); m != nil && usedAfterFix(lines, remove, m[1]) {
+				delete(remove, i)
+			}
+		}
+		removed := len(remove)
+		if removed == 0 {
+			continue
+		}
+		var kept []string
+		for i, line := range lines {
+			if !remove[i] {
+				kept = append(kept, line)
+			}
+		}
+		left := len(byFile[rel]) - removed
+		fix := Fix{
+			CheckID: "debug_statements",
+			Path:    rel,
+			Summary: fmt.Sprintf("remove %d debug statement(s) from %s", removed, rel),
+			Content: []byte(strings.Join(kept, "")),
+		}
+		if left > 0 {
+			fix.Notes = []string{fmt.Sprintf("%d more in %s span lines, share them with other code or are all a block holds; remove those by hand", left, rel)}
+		}
+		fixes = append(fixes, fix)
+	}
+	return fixes, nil
+}
+
+var (
+	// reBracelessHeader matches a control statement whose body is the
+	// next line alone: if (debug) with no brace, else, or an arrow
+	// function's =>.
+	reBracelessHeader = regexp.MustCompile(`^(?:\}\s*)?(?:(?:else\s+)?if|for|foreach|while)\b.*\)$|^(?:\}\s*)?else$|=>$`)
+	// reColonHeader matches a Python block header, comment and all.
+	reColonHeader = regexp.MustCompile(`:\s*(?:#.*)?$`)
+	reDebugImport = regexp.MustCompile(`^import (i?pdb)\s*;?$`)
+)
+
+// isBlockBody reports whether line i is the body of the statement before
+// it: after a braceless control header it's all the condition guards, so
+// deleting it would hand the guard the next line instead, and after a
+// Python header ending in : it may be all a block holds.
+func isBlockBody(lines []string, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		prev := strings.TrimSpace(stripCodeComments(lines[j]))
+		if prev == "" || reCommentLine.MatchString(prev) {
+			continue
+		}
+		return reColonHeader.MatchString(prev) || reBracelessHeader.MatchString(prev)
+	}
+	return false
+}
+
+// usedAfterFix reports whether a line the fix keeps still refers to module.
+func usedAfterFix(lines []string, remove map[int]bool, module string) bool {
+	use := regexp.MustCompile(`\b` + module + `\.`)
+	for i, line := range lines {
+		if !remove[i] && use.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRemovableDebugLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if !removableDebugLine.MatchString(trimmed) {
+		return false
+	}
+	// The call's opening parenthesis has to close at the end of the line,
+	// or `console.log(a); save()` would go with it.
+	open := strings.IndexByte(trimmed, '(')
+	if open < 0 {
+		return true
+	}
+	depth := 0
+	for i := open; i < len(trimmed); i++ {
+		switch trimmed[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(trimmed[i+1:]), ";")) == ""
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestFixRobotsTxt(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"public/index.html": "<html></html>"})
+	cfg := &config.PreflightConfig{Stack: "static"}
+	cfg.URLs.Production = "https://example.com/"
+	fixes, err := PlanFixes(Context{RootDir: dir, Config: cfg}, []CheckResult{{ID: "robotsTxt"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixes) != 1 || fixes[0].Path != "public/robots.txt" || !fixes[0].Create {
+		t.Fatalf("fixes = %+v", fixes)
+	}
+	if !strings.Contains(string(fixes[0].Content), "Sitemap: https://example.com/sitemap.xml") {
+		t.Errorf("content = %q", fixes[0].Content)
+	}
+	if err := fixes[0].Apply(dir); err != nil {
+		t.Fatal(err)
+	}
+	result, err := RobotsTxtCheck{}.Run(Context{RootDir: dir, Config: cfg})
+	if err != nil || !result.Passed {
+		t.Errorf("robotsTxt still failing after the fix: %+v, %v", result, err)
+	}
+	// Planned twice, applied twice: the second write must not clobber
+	// what's there now.
+	if err := fixes[0].Apply(dir); err == nil {
+		t.Error("Apply overwrote an existing robots.txt")
+	}
+}
+
+func TestFixFaviconAddsLinksToLayout(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"public/favicon.ico": testICO(t, 16, 32),
+		"views/layout.html":  "<html>\n  <head>\n    <title>x</title>\n  </head>\n</html>\n",
+	})
+	cfg := &config.PreflightConfig{ProjectName: "Acme", Stack: "node"}
+	cfg.Checks.SEOMeta = &config.SEOMetaConfig{MainLayout: "views/layout.html"}
+	ctx := Context{RootDir: dir, Config: cfg}
+
+	result, err := FaviconCheck{}.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixes, err := PlanFixes(ctx, []CheckResult{result})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixes) != 2 {
+		t.Fatalf("fixes = %+v", fixes)
+	}
+	if fixes[0].Path != "public/site.webmanifest" || !strings.Contains(string(fixes[0].Content), `"name": "Acme"`) {
+		t.Errorf("manifest fix = %s: %s", fixes[0].Path, fixes[0].Content)
+	}
+	want := "    <title>x</title>\n" +
+		"    <link rel=\"manifest\" href=\"/site.webmanifest\">\n" +
+		"    <link rel=\"apple-touch-icon\" sizes=\"180x180\" href=\"/apple-touch-icon.png\">\n" +
+		"  </head>"
+	if fixes[1].Path != "views/layout.html" || !strings.Contains(string(fixes[1].Content), want) {
+		t.Errorf("layout fix = %s:\n%s", fixes[1].Path, fixes[1].Content)
+	}
+
+	for _, f := range fixes {
+		if err := f.Apply(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The fix links the apple-touch-icon and leaves adding the image to
+	// its note, so until then the link is broken.
+	if result, _ := (FaviconCheck{}).Run(ctx); result.Passed || result.Message != "views/layout.html links /apple-touch-icon.png, which isn't in the web root" {
+		t.Errorf("after the fix: passed=%v %s", result.Passed, result.Message)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "public", "apple-touch-icon.png"), testPNG(t, 180, 180), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := (FaviconCheck{}).Run(ctx); !result.Passed {
+		t.Errorf("favicon still failing after adding the icon: %s", result.Message)
+	}
+}
+
+func TestFixErrorPagesUsesExistingDirectory(t *testing.T) {
+	cases := []struct {
+		name  string
+		stack string
+		tree  map[string]string
+		want  string
+	}{
+		{"next app router", "next", map[string]string{"app/layout.tsx": ""}, "app/not-found.tsx"},
+		{"rails", "rails", map[string]string{"public/robots.txt": "x"}, "public/404.html"},
+		{"laravel", "laravel", map[string]string{"resources/views/welcome.blade.php": ""}, "resources/views/errors/404.blade.php"},
+		{"django without templates", "django", map[string]string{"manage.py": ""}, "templates/404.html"},
+		{"unknown stack", "", map[string]string{"index.html": ""}, "404.html"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeFiles(t, tc.tree)
+			cfg := &config.PreflightConfig{Stack: tc.stack}
+			fixes, err := PlanFixes(Context{RootDir: dir, Config: cfg}, []CheckResult{{ID: "error_pages"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fixes) != 1 || fixes[0].Path != tc.want {
+				t.Fatalf("fixes = %+v, want one at %s", fixes, tc.want)
+			}
+		})
+	}
+}
+
+func TestFixDebugStatementsRemovesWholeLinesOnly(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"src/app.js": "function a() {\n  console.log(\"hi\");\n  console.log(\n    big\n  )\n  save(); console.log(1)\n  debugger;\n  return 1\n}\n",
+	})
+	cfg := &config.PreflightConfig{}
+	ctx := Context{RootDir: dir, Config: cfg}
+	findings := scanForDebugStatements(BuildFileIndex(dir), nil)
+	fixes, err := PlanFixes(ctx, []CheckResult{{ID: "debug_statements", Locations: findings}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixes) != 1 {
+		t.Fatalf("fixes = %+v", fixes)
+	}
+	want := "function a() {\n  console.log(\n    big\n  )\n  save(); console.log(1)\n  return 1\n}\n"
+	if got := string(fixes[0].Content); got != want {
+		t.Errorf("content =\n%s\nwant\n%s", got, want)
+	}
+	if len(fixes[0].Notes) != 1 || !strings.Contains(fixes[0].Notes[0], "2 more") {
+		t.Errorf("notes = %v", fixes[0].Notes)
+	}
+	if err := fixes[0].Apply(dir); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(filepath.Join(dir, "src", "app.js"))
+	if string(got) != want {
+		t.Errorf("file after Apply =\n%s", got)
+	}
+}
+
+// A debug line that is all a condition or block holds can't go on its
+// own, and neither can an import whose module is still used.
+func TestFixDebugStatementsKeepsSurroundingCode(t *testing.T) {
+	cases := []struct {
+		name string
+		file string
+		body string
+		want string // "" when nothing can be removed
+	}{
+		{
+			name: "braceless if",
+			file: "app.js",
+			body: "if (debug)\n  console.log(x)\nsave()\n",
+		},
+		{
+			name: "guarded by an else",
+			file: "app.js",
+			body: "if (ok) save()\nelse\n  console.log(x)\nrender()\n",
+		},
+		{
+			name: "only statement in a Python if",
+			file: "app.py",
+			body: "if DEBUG:  # local only\n    breakpoint()\nrun()\n",
+		},
+		{
+			name: "only statement in a def after a comment",
+			file: "app.py",
+			body: "def debug():\n    # drop into the debugger\n    breakpoint()\n",
+		},
+		{
+			name: "import still used on a shared line",
+			file: "app.py",
+			body: "import pdb\nx = 1; pdb.set_trace()\n",
+		},
+		{
+			name: "import goes with its last use",
+			file: "app.py",
+			body: "import pdb\ndef f():\n    x = 1\n    pdb.set_trace()\n    return x\n",
+			want: "def f():\n    x = 1\n    return x\n",
+		},
+		{
+			name: "braced if",
+			file: "app.js",
+			body: "if (debug) {\n  console.log(x)\n}\nsave()\n",
+			want: "if (debug) {\n}\nsave()\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{tc.file: tc.body})
+			findings := scanForDebugStatements(BuildFileIndex(dir), nil)
+			if len(findings) == 0 {
+				t.Fatal("no debug statements found")
+			}
+			fixes, err := PlanFixes(Context{RootDir: dir, Config: &config.PreflightConfig{}}, []CheckResult{{ID: "debug_statements", Locations: findings}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(fixes) != 0 {
+					t.Errorf("fix removes code it shouldn't:\n%s", fixes[0].Content)
+				}
+				return
+			}
+			if len(fixes) != 1 || string(fixes[0].Content) != tc.want {
+				t.Errorf("fixes = %+v, want content\n%s", fixes, tc.want)
+			}
+		})
+	}
+}
+
+func TestPlanFixesSkipsPassingAndUnfixable(t *testing.T) {
+	dir := t.TempDir()
+	fixes, err := PlanFixes(Context{RootDir: dir, Config: &config.PreflightConfig{}}, []CheckResult{
+		{ID: "robotsTxt", Passed: true},
+		{ID: "secrets"},
+	})
+	if err != nil || len(fixes) != 0 {
+		t.Errorf("fixes = %+v, %v", fixes, err)
+	}
+}
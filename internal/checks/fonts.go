@@ -0,0 +1,186 @@
+package checks
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FontLoadingCheck looks for the web font mistakes that cause invisible
+// text (FOIT), flashes of fallback text and layout shift at launch:
+// @font-face rules and Google Fonts URLs without font-display, hosted font
+// services used without a preconnect hint, and self-hosted fonts served
+// without a woff2 file.
+type FontLoadingCheck struct{}
+
+func (c FontLoadingCheck) ID() string {
+	return "fonts"
+}
+
+func (c FontLoadingCheck) Title() string {
+	return "Web font loading"
+}
+
+var (
+	reFontFace    = regexp.MustCompile(`(?is)@font-face\s*\{[^}]*\}`)
+	reFontDisplay = regexp.MustCompile(`(?i)font-display\s*:`)
+	reCSSURL      = regexp.MustCompile(`(?i)url\(\s*["']?([^"')]+)["']?\s*\)`)
+	reLinkTag     = regexp.MustCompile(`(?is)<link\b[^>]*>`)
+	reRelAttr     = regexp.MustCompile(`(?i)\brel\s*=\s*["']?([^"'\s>]+)`)
+	reHrefAttr    = regexp.MustCompile(`(?i)\bhref\s*=\s*\{?\s*["']([^"']+)["']`)
+	// A font service stylesheet referenced anywhere: <link href>, CSS
+	// @import, or a URL string in a component.
+	reFontServiceURL = regexp.MustCompile(`(?i)(?:https?:)?//(fonts\.googleapis\.com|fonts\.bunny\.net|use\.typekit\.net)/[^"'\s)]*`)
+
+	fontStyleExts = []string{".css", ".scss", ".sass", ".less", ".pcss", ".styl"}
+)
+
+// fontServiceOrigins maps a font service's stylesheet host to the origin
+// its font files come from, which is the connection worth warming up.
+var fontServiceOrigins = map[string]string{
+	"fonts.googleapis.com": "fonts.gstatic.com",
+	"fonts.bunny.net":      "fonts.bunny.net",
+	"use.typekit.net":      "use.typekit.net",
+}
+
+type fontServiceUse struct {
+	host string
+	loc  Location
+}
+
+func (c FontLoadingCheck) Run(ctx Context) (CheckResult, error) {
+	var findings []Location
+	var services []fontServiceUse
+	preconnected := map[string]bool{}
+	fontFaces := 0
+
+	exts := append(append([]string{}, templateExts...), fontStyleExts...)
+	walkTemplateFiles(ctx.RootDir, exts, func(rel string, content []byte) {
+		text := string(content)
+
+		for _, loc := range reFontFace.FindAllStringIndex(text, -1) {
+			fontFaces++
+			block := text[loc[0]:loc[1]]
+			line := lineAt(content, loc[0])
+			if !reFontDisplay.MatchString(block) {
+				findings = append(findings, Location{File: rel, Line: line, Message: "@font-face without font-display"})
+			}
+			if selfHostedWithoutWoff2(block) {
+				findings = append(findings, Location{File: rel, Line: line, Message: "self-hosted font without a woff2 file"})
+			}
+		}
+
+		for _, loc := range reFontServiceURL.FindAllStringSubmatchIndex(text, -1) {
+			raw := text[loc[0]:loc[1]]
+			host := strings.ToLower(text[loc[2]:loc[3]])
+			l := Location{File: rel, Line: lineAt(content, loc[0])}
+			services = append(services, fontServiceUse{host: host, loc: l})
+			if host == "fonts.googleapis.com" && !googleFontsHasDisplay(raw) {
+				l.Message = "Google Fonts URL without display= parameter"
+				findings = append(findings, l)
+			}
+		}
+
+		for _, tag := range reLinkTag.FindAllString(text, -1) {
+			relAttr := reRelAttr.FindStringSubmatch(tag)
+			href := reHrefAttr.FindStringSubmatch(tag)
+			if relAttr == nil || href == nil || !strings.EqualFold(relAttr[1], "preconnect") {
+				continue
+			}
+			if u, err := url.Parse(href[1]); err == nil && u.Host != "" {
+				preconnected[strings.ToLower(u.Host)] = true
+			}
+		}
+	})
+
+	if fontFaces == 0 && len(services) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No web fonts found, skipping",
+		}, nil
+	}
+
+	// One preconnect finding per service, at its first use.
+	reported := map[string]bool{}
+	for _, s := range services {
+		origin := fontServiceOrigins[s.host]
+		if preconnected[origin] || reported[origin] {
+			continue
+		}
+		reported[origin] = true
+		s.loc.Message = fmt.Sprintf("no <link rel=\"preconnect\"> for https://%s", origin)
+		findings = append(findings, s.loc)
+	}
+
+	if len(findings) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Web fonts set font-display, preconnect and woff2",
+		}, nil
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	shown := make([]string, len(findings))
+	for i, f := range findings {
+		shown[i] = f.String()
+	}
+
+	suggestions := []string{
+		"Add font-display: swap (or optional) to each @font-face, and &display=swap to Google Fonts URLs",
+		"Add <link rel=\"preconnect\" href=\"https://fonts.gstatic.com\" crossorigin> for hosted fonts",
+		"Serve woff2 first in @font-face src; it is the smallest format every current browser supports",
+	}
+
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    SeverityWarn,
+		Passed:      false,
+		Message:     fmt.Sprintf("Found %d font loading issue(s)", len(findings)),
+		Suggestions: append(suggestions, limitFindings(shown, 5)...),
+		Locations:   findings,
+	}, nil
+}
+
+// selfHostedWithoutWoff2 reports whether an @font-face block loads font
+// files from the site itself but offers no woff2 among them.
+func selfHostedWithoutWoff2(block string) bool {
+	selfHosted, woff2 := false, false
+	for _, m := range reCSSURL.FindAllStringSubmatch(block, -1) {
+		src := strings.TrimSpace(m[1])
+		lower := strings.ToLower(src)
+		if strings.HasPrefix(lower, "data:") || strings.HasPrefix(lower, "http://") ||
+			strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "//") {
+			continue
+		}
+		selfHosted = true
+		if i := strings.IndexAny(lower, "?#"); i >= 0 {
+			lower = lower[:i]
+		}
+		if strings.HasSuffix(lower, ".woff2") {
+			woff2 = true
+		}
+	}
+	return selfHosted && !woff2
+}
+
+func googleFontsHasDisplay(raw string) bool {
+	u, err := url.Parse(strings.ReplaceAll(raw, "&amp;", "&"))
+	if err != nil {
+		return true
+	}
+	return u.Query().Get("display") != ""
+}
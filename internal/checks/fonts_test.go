@@ -0,0 +1,58 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestFontLoadingCheck(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"src/styles/fonts.css": `@font-face {
+  font-family: "Brand";
+  src: url("/fonts/brand.woff") format("woff");
+}
+@font-face {
+  font-family: "Body";
+  font-display: swap;
+  src: url("/fonts/body.woff2") format("woff2"), url("/fonts/body.woff") format("woff");
+}`,
+		"src/layouts/Base.astro": `<link href="https://fonts.googleapis.com/css2?family=Inter" rel="stylesheet">`,
+	})
+	result, err := FontLoadingCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed || result.Severity != SeverityWarn {
+		t.Fatalf("got %+v, want a warning", result)
+	}
+	var got []string
+	for _, l := range result.Locations {
+		got = append(got, l.String())
+	}
+	want := []string{
+		"src/layouts/Base.astro:1 - Google Fonts URL without display= parameter",
+		`src/layouts/Base.astro:1 - no <link rel="preconnect"> for https://fonts.gstatic.com`,
+		"src/styles/fonts.css:1 - @font-face without font-display",
+		"src/styles/fonts.css:1 - self-hosted font without a woff2 file",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestFontLoadingCheckClean(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"index.html": `<link rel="preconnect" href="https://fonts.gstatic.com" crossorigin>
+<link href="https://fonts.googleapis.com/css2?family=Inter&amp;display=swap" rel="stylesheet">`,
+		"styles.css": `@font-face { font-family: X; font-display: optional; src: url(x.woff2?v=2) format("woff2"); }`,
+	})
+	result, err := FontLoadingCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed {
+		t.Errorf("got %+v, want a pass", result)
+	}
+}
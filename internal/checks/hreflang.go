@@ -0,0 +1,224 @@
+package checks
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// HreflangCheck validates the hreflang annotations on the production
+// homepage: well-formed language-region codes, absolute URLs, an
+// x-default, a self reference, and alternates that link back. Google
+// ignores annotations that aren't reciprocal, so one broken alternate
+// quietly drops the whole set.
+type HreflangCheck struct{}
+
+func (c HreflangCheck) ID() string {
+	return "hreflang"
+}
+
+func (c HreflangCheck) Title() string {
+	return "hreflang"
+}
+
+// reHreflangCode matches an ISO 639-1 language, optionally with an ISO
+// 15924 script and an ISO 3166-1 region (or UN M.49 area code).
+var reHreflangCode = regexp.MustCompile(`^[a-z]{2,3}(?:-[a-z]{4})?(?:-(?:[a-z]{2}|\d{3}))?$`)
+
+// hreflangMistakes are country codes commonly used in place of the
+// language code, with the code meant.
+var hreflangMistakes = map[string]string{
+	"jp": "ja", "cn": "zh", "kr": "ko", "dk": "da", "gr": "el", "cz": "cs", "ua": "uk", "at": "de",
+}
+
+// hreflangRegionMistakes are region codes that aren't ISO 3166-1, with the
+// code meant.
+var hreflangRegionMistakes = map[string]string{"uk": "gb", "eu": ""}
+
+// maxHreflangFetches caps how many alternates are fetched for the
+// reciprocity check.
+const maxHreflangFetches = 12
+
+func (c HreflangCheck) Run(ctx Context) (CheckResult, error) {
+	var expected []string
+	if cfg := ctx.Config.Checks.Hreflang; cfg != nil {
+		expected = cfg.Locales
+	}
+	if ctx.PageHTMLProduction == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  "Couldn't fetch the production homepage to read its hreflang links",
+		}, nil
+	}
+	links := parseRenderedHTML(ctx.PageHTMLProduction).hreflang
+	if len(links) == 0 {
+		if len(expected) == 0 {
+			return CheckResult{
+				ID:       c.ID(),
+				Title:    c.Title(),
+				Severity: SeverityInfo,
+				Passed:   true,
+				Message:  "No hreflang annotations, skipping",
+			}, nil
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  fmt.Sprintf("Production homepage has no hreflang links for the %d expected locales", len(expected)),
+			Suggestions: []string{
+				`Add <link rel="alternate" hreflang="..." href="https://..."> for each locale, plus hreflang="x-default", to every localized page`,
+				"Or remove checks.hreflang.locales if the site isn't multilingual",
+			},
+		}, nil
+	}
+
+	home := hreflangNormalizeURL(ctx.Config.URLs.Production)
+	var problems, details []string
+	byLang := map[string]string{}
+	selfRef := false
+	var alternates []hreflangLink
+	for _, l := range links {
+		code := strings.ToLower(l.lang)
+		if problem := hreflangCodeProblem(l.lang); problem != "" {
+			problems = append(problems, problem)
+		}
+		if prev, ok := byLang[code]; ok {
+			if prev != l.href {
+				problems = append(problems, fmt.Sprintf("hreflang %q points at both %s and %s", l.lang, prev, l.href))
+			}
+			continue
+		}
+		byLang[code] = l.href
+		u, err := url.Parse(l.href)
+		if err != nil || !u.IsAbs() {
+			problems = append(problems, fmt.Sprintf("hreflang %q href isn't an absolute URL: %s", l.lang, l.href))
+			continue
+		}
+		if hreflangNormalizeURL(l.href) == home {
+			selfRef = true
+			continue
+		}
+		alternates = append(alternates, l)
+	}
+	if _, ok := byLang["x-default"]; !ok {
+		problems = append(problems, "no x-default")
+	}
+	if !selfRef {
+		problems = append(problems, "the homepage isn't among its own alternates")
+	}
+	for _, locale := range expected {
+		if _, ok := byLang[strings.ToLower(locale)]; !ok {
+			problems = append(problems, "no hreflang for "+locale)
+		}
+	}
+
+	// An alternate has to list the homepage back for Google to trust
+	// either annotation.
+	checked := map[string]bool{}
+	for _, l := range alternates {
+		target := hreflangNormalizeURL(l.href)
+		if checked[target] || ctx.Client == nil {
+			continue
+		}
+		if len(checked) == maxHreflangFetches {
+			details = append(details, fmt.Sprintf("Checked the first %d alternates for return links", maxHreflangFetches))
+			break
+		}
+		checked[target] = true
+		body, _, ok := fetchBody(ctx, l.href)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s alternate %s couldn't be fetched", l.lang, l.href))
+			continue
+		}
+		linksBack := false
+		for _, back := range parseRenderedHTML(string(body)).hreflang {
+			if hreflangNormalizeURL(back.href) == home {
+				linksBack = true
+				break
+			}
+		}
+		if !linksBack {
+			problems = append(problems, fmt.Sprintf("%s alternate %s doesn't link back to the homepage", l.lang, l.href))
+		} else {
+			details = append(details, l.lang+" links back: "+l.href)
+		}
+	}
+
+	if len(problems) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("%d hreflang alternates, reciprocal, with x-default", len(byLang)),
+			Details:  details,
+		}, nil
+	}
+	message := fmt.Sprintf("%d hreflang problems on the production homepage", len(problems))
+	if len(problems) == 1 {
+		message = "hreflang: " + problems[0]
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  message,
+		Suggestions: append([]string{
+			"Every localized page should list all alternates, itself and an x-default with absolute URLs; Google drops annotations that aren't returned",
+		}, limitFindings(problems, 8)...),
+		Details: details,
+	}, nil
+}
+
+// hreflangCodeProblem says what's wrong with an hreflang value, or
+// returns "" when it's valid.
+func hreflangCodeProblem(code string) string {
+	lower := strings.ToLower(code)
+	if lower == "x-default" {
+		return ""
+	}
+	if strings.Contains(lower, "_") {
+		return fmt.Sprintf("hreflang %q uses an underscore; use %q", code, strings.ReplaceAll(code, "_", "-"))
+	}
+	if !reHreflangCode.MatchString(lower) {
+		return fmt.Sprintf("hreflang %q isn't a language or language-region code", code)
+	}
+	parts := strings.Split(lower, "-")
+	if want, ok := hreflangMistakes[parts[0]]; ok {
+		return fmt.Sprintf("hreflang %q starts with a country code; the language is %q", code, want)
+	}
+	region := parts[len(parts)-1]
+	if len(parts) > 1 && len(region) == 2 {
+		if want, ok := hreflangRegionMistakes[region]; ok {
+			if want == "" {
+				return fmt.Sprintf("hreflang %q uses %q, which isn't a country code", code, strings.ToUpper(region))
+			}
+			return fmt.Sprintf("hreflang %q uses region %q; the country code is %q", code, strings.ToUpper(region), strings.ToUpper(want))
+		}
+	}
+	return ""
+}
+
+// hreflangNormalizeURL reduces a URL to the form alternates are compared
+// in: lowercase scheme and host, no fragment, and no trailing slash.
+func hreflangNormalizeURL(raw string) string {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u.String()
+}
@@ -0,0 +1,91 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestHreflangCodeProblem(t *testing.T) {
+	for code, want := range map[string]string{
+		"en":        "",
+		"en-US":     "",
+		"zh-Hant":   "",
+		"es-419":    "",
+		"x-default": "",
+		"en_US":     `use "en-US"`,
+		"en-UK":     `the country code is "GB"`,
+		"jp":        `the language is "ja"`,
+		"english":   "isn't a language or language-region code",
+		"de-EU":     "isn't a country code",
+	} {
+		got := hreflangCodeProblem(code)
+		if (want == "") != (got == "") || !strings.Contains(got, want) {
+			t.Errorf("hreflangCodeProblem(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestHreflangCheck(t *testing.T) {
+	var srv *httptest.Server
+	pages := map[string]string{}
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(strings.ReplaceAll(page, "SITE", srv.URL)))
+	}))
+	defer srv.Close()
+	links := `<link rel="alternate" hreflang="en" href="SITE/">` +
+		`<link rel="alternate" hreflang="fr" href="SITE/fr/">` +
+		`<link rel="alternate" hreflang="x-default" href="SITE/">`
+	pages["/fr/"] = "<head>" + links + "</head>"
+
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	run := func(home string) CheckResult {
+		t.Helper()
+		result, err := HreflangCheck{}.Run(Context{Config: cfg, Client: srv.Client(), PageHTMLProduction: strings.ReplaceAll(home, "SITE", srv.URL)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	if result := run("<head><title>Home</title></head>"); !result.Passed || result.Message != "No hreflang annotations, skipping" {
+		t.Errorf("expected skip, got %v %q", result.Passed, result.Message)
+	}
+
+	if result := run("<head>" + links + "</head>"); !result.Passed {
+		t.Errorf("expected pass, got %q %v", result.Message, result.Suggestions)
+	}
+
+	cfg.Checks.Hreflang = &config.HreflangConfig{Locales: []string{"en", "fr", "de-DE"}}
+	pages["/fr/"] = `<head><link rel="alternate" hreflang="fr" href="SITE/fr/"></head>`
+	result := run(`<head><link rel="alternate" hreflang="en_US" href="SITE/">` +
+		`<link rel="alternate" hreflang="fr" href="SITE/fr/">` +
+		`<link rel="alternate" hreflang="es" href="/es/">` +
+		`<link rel="alternate" hreflang="it" href="SITE/it/"></head>`)
+	if result.Passed {
+		t.Fatal("expected a warning")
+	}
+	joined := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{
+		`hreflang "en_US" uses an underscore`,
+		`hreflang "es" href isn't an absolute URL: /es/`,
+		"no x-default",
+		"no hreflang for en",
+		"no hreflang for de-DE",
+		"fr alternate " + srv.URL + "/fr/ doesn't link back to the homepage",
+		"it alternate " + srv.URL + "/it/ couldn't be fetched",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+}
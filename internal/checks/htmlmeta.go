@@ -15,11 +15,18 @@ type renderedDoc struct {
 	metaName     map[string]string   // <meta name=K content=V>, keys lowercased
 	metaProperty map[string]string   // <meta property=K content=V>, keys lowercased
 	linkRels     map[string][]string // rel -> hrefs, rel tokens lowercased
+	hreflang     []hreflangLink      // <link rel=alternate hreflang=L href=H>, in page order
 	title        string              // trimmed text of the first non-empty <title>
 	htmlLang     string              // lang attribute on <html>
 	hasJSONLD    bool                // <script type="application/ld+json"> present
 }
 
+// hreflangLink is one language alternate a page declares.
+type hreflangLink struct {
+	lang string // as written
+	href string
+}
+
 // parseRenderedHTML tokenizes doc and collects the head-level signals the
 // checks care about. The tokenizer is tolerant of broken markup and never
 // fails; on garbage input the result is simply empty.
@@ -66,6 +73,9 @@ func parseRenderedHTML(doc string) renderedDoc {
 				// (e.g. rel="shortcut icon").
 				for _, rel := range strings.Fields(strings.ToLower(attrs["rel"])) {
 					d.linkRels[rel] = append(d.linkRels[rel], attrs["href"])
+					if lang, ok := attrs["hreflang"]; ok && rel == "alternate" {
+						d.hreflang = append(d.hreflang, hreflangLink{strings.TrimSpace(lang), strings.TrimSpace(attrs["href"])})
+					}
 				}
 			case "html":
 				if d.htmlLang == "" {
@@ -100,6 +110,17 @@ func (d renderedDoc) hasMeta(name string) bool {
 	return ok
 }
 
+// metaContent returns the content of the meta tag with the given name
+// or property, and whether there is one.
+func (d renderedDoc) metaContent(name string) (string, bool) {
+	key := strings.ToLower(name)
+	if v, ok := d.metaName[key]; ok {
+		return v, true
+	}
+	v, ok := d.metaProperty[key]
+	return v, ok
+}
+
 // hasLinkRel reports whether any <link> carries the given rel token.
 func (d renderedDoc) hasLinkRel(rel string) bool {
 	return len(d.linkRels[strings.ToLower(rel)]) > 0
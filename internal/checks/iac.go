@@ -0,0 +1,372 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IaCCheck reviews the project's Terraform, OpenTofu and Pulumi code,
+// which can leak as much as the application: state files committed to the
+// repository (state holds every resource attribute, passwords included, in
+// plain text), credentials hardcoded in .tf, .tfvars or Pulumi stack
+// config, and root modules whose backend leaves state unencrypted.
+type IaCCheck struct{}
+
+func (c IaCCheck) ID() string {
+	return "iac"
+}
+
+func (c IaCCheck) Title() string {
+	return "Infrastructure as code"
+}
+
+var (
+	// reIaCSecretKey matches argument and variable names that hold
+	// credentials. It's anchored at the end so secret_name and
+	// password_policy, which name one, don't match.
+	reIaCSecretKey = regexp.MustCompile(`(?i)(passw(or)?d|secret|token|access_?key|secret_?key|api_?key|private_?key|credentials?)$`)
+	// reHCLAssignment matches name = "literal" in HCL, and "name": "literal"
+	// in .tf.json. Values with an interpolation aren't literals.
+	reHCLAssignment  = regexp.MustCompile(`^\s*"?([\w-]+)"?\s*[=:]\s*"([^"$]*)"\s*,?\s*$`)
+	reAWSAccessKeyID = regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)
+	// reHCLBlock matches the header of a block (backend "s3" {, variable
+	// "db_password" {, terraform {).
+	reHCLBlock   = regexp.MustCompile(`(?m)^\s*(backend|cloud|encryption|provider|variable|terraform)\s*(?:"([^"]*)"\s*)?\{`)
+	reHCLDefault = regexp.MustCompile(`(?m)^\s*default\s*=\s*"([^"$]*)"`)
+	reS3Encrypt  = regexp.MustCompile(`(?m)^\s*(?:encrypt\s*=\s*true\b|kms_key_id\s*=)`)
+)
+
+// iacSkipDirs hold provider plugins and module copies, not the
+// project's own code.
+var iacSkipDirs = map[string]bool{".terraform": true, ".terragrunt-cache": true}
+
+// encryptedBackends store state encrypted at rest without being asked.
+var encryptedBackends = map[string]bool{"gcs": true, "azurerm": true, "remote": true, "cloud": true}
+
+func (c IaCCheck) Run(ctx Context) (CheckResult, error) {
+	files := ctx.files()
+	var iacFiles []FileEntry
+	for _, f := range files.Files {
+		if f.inDir(iacSkipDirs) || f.inDir(vendorSkipDirs) {
+			continue
+		}
+		if f.Ext == ".tf" || f.Ext == ".tfvars" || f.Ext == ".tfstate" || strings.HasSuffix(f.Name, ".tf.json") ||
+			strings.HasSuffix(f.Name, ".tfstate.backup") || strings.HasSuffix(f.Name, ".tfvars.json") ||
+			isPulumiFile(f) {
+			iacFiles = append(iacFiles, f)
+		}
+	}
+	if len(iacFiles) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No Terraform or Pulumi files found",
+		}, nil
+	}
+
+	var findings []string
+	var locations []Location
+	kinds := map[string]bool{}
+	report := func(kind string, loc Location) {
+		kinds[kind] = true
+		findings = append(findings, loc.String())
+		locations = append(locations, loc)
+	}
+
+	// As in the secrets check, git decides what's committed: a file
+	// that's ignored and untracked never will be, so a local
+	// terraform.tfstate or secrets.auto.tfvars is fine.
+	git := loadGitStatus(ctx)
+	committed := func(f FileEntry) (string, bool) {
+		if git.inRepo {
+			if git.tracked[f.Path] {
+				return "is committed", true
+			}
+			return "isn't ignored, so git add . would commit it", !git.ignored[f.Path]
+		}
+		return "isn't ignored", !f.Ignored
+	}
+
+	// A module is a directory of .tf files; only a root module has
+	// state of its own.
+	type module struct {
+		root      bool
+		file      string
+		line      int
+		backend   string
+		encrypted bool
+	}
+	modules := map[string]*module{}
+	var moduleDirs []string
+	for _, f := range iacFiles {
+		state, inScope := committed(f)
+		if f.Ext == ".tfstate" || strings.HasSuffix(f.Name, ".tfstate.backup") || (isPulumiFile(f) && f.Ext == ".json") {
+			if inScope {
+				report("state", Location{File: f.Path, Message: "state file " + state})
+			}
+			continue
+		}
+		content, err := readFile(files.Abs(f))
+		if err != nil {
+			continue
+		}
+
+		if isPulumiFile(f) {
+			if inScope && strings.HasPrefix(f.Name, "Pulumi.") && f.Name != "Pulumi.yaml" && f.Name != "Pulumi.yml" {
+				for _, loc := range pulumiPlaintextSecrets(f.Path, content) {
+					report("credential", loc)
+				}
+			}
+			continue
+		}
+
+		text := stripHCLComments(string(content))
+		if inScope {
+			for i, line := range strings.Split(text, "\n") {
+				if m := reHCLAssignment.FindStringSubmatch(line); m != nil && iacSecretValue(m[1], m[2]) {
+					report("credential", Location{File: f.Path, Line: i + 1, Message: m[1] + " is set to a literal credential"})
+				} else if reAWSAccessKeyID.MatchString(line) {
+					report("credential", Location{File: f.Path, Line: i + 1, Message: "AWS access key ID"})
+				}
+			}
+		}
+
+		if f.Ext == ".tfvars" || strings.HasSuffix(f.Name, ".tfvars.json") || strings.HasSuffix(f.Name, ".tf.json") {
+			continue
+		}
+		dir := path.Dir(f.Path)
+		mod := modules[dir]
+		if mod == nil {
+			mod = &module{}
+			modules[dir] = mod
+			moduleDirs = append(moduleDirs, dir)
+		}
+		blocks := hclBlocks(text)
+		// backend, cloud and encryption only configure state inside a
+		// terraform block; resources have encryption blocks of their own.
+		inTerraform := func(b hclBlock) bool {
+			for _, t := range blocks {
+				if t.kind == "terraform" && t.start < b.start && b.end <= t.end {
+					return true
+				}
+			}
+			return false
+		}
+		for _, b := range blocks {
+			switch b.kind {
+			case "provider":
+				// Reusable modules inherit providers; only a root module
+				// configures one.
+				mod.root = true
+				if mod.file == "" {
+					mod.file, mod.line = f.Path, b.line
+				}
+			case "backend", "cloud":
+				if !inTerraform(b) {
+					continue
+				}
+				mod.backend, mod.file, mod.line = b.label, f.Path, b.line
+				if b.kind == "cloud" {
+					mod.backend = "cloud"
+				}
+				if b.label == "s3" && reS3Encrypt.MatchString(b.body) {
+					mod.encrypted = true
+				}
+			case "encryption":
+				// OpenTofu's client-side state encryption.
+				if inTerraform(b) {
+					mod.encrypted = true
+				}
+			case "variable":
+				if m := reHCLDefault.FindStringSubmatch(b.body); m != nil && inScope && iacSecretValue(b.label, m[1]) {
+					report("credential", Location{File: f.Path, Line: b.line, Message: fmt.Sprintf("variable %q has a literal default", b.label)})
+				}
+			}
+		}
+	}
+
+	for _, dir := range moduleDirs {
+		mod := modules[dir]
+		if !mod.root || mod.encrypted || encryptedBackends[mod.backend] {
+			continue
+		}
+		loc := Location{File: mod.file, Line: mod.line}
+		switch mod.backend {
+		case "":
+			loc.Message = "no backend, so state is kept unencrypted in a local terraform.tfstate"
+		case "local":
+			loc.Message = "the local backend keeps state unencrypted on disk"
+		case "s3":
+			loc.Message = "the s3 backend doesn't set encrypt = true"
+		default:
+			loc.Message = fmt.Sprintf("the %s backend has no state encryption configured", mod.backend)
+		}
+		report("backend", loc)
+	}
+
+	if len(findings) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("%d IaC file(s): no committed state or hardcoded credentials, and state is encrypted", len(iacFiles)),
+		}, nil
+	}
+
+	severity := SeverityWarn
+	var found, suggestions []string
+	if kinds["state"] {
+		severity = SeverityError
+		found = append(found, "committed state files")
+		suggestions = append(suggestions, "Remove the state from git (git rm --cached), add *.tfstate* to .gitignore, move state to a remote backend, and rotate any secrets it held")
+	}
+	if kinds["credential"] {
+		severity = SeverityError
+		found = append(found, "hardcoded credentials")
+		suggestions = append(suggestions, "Pass credentials through TF_VAR_ environment variables or a secrets manager, mark the variables sensitive, and use pulumi config set --secret for Pulumi")
+	}
+	if kinds["backend"] {
+		found = append(found, "unencrypted state backends")
+		suggestions = append(suggestions, "Keep state in a remote backend with encryption at rest (s3 with encrypt = true, gcs, azurerm, HCP Terraform), or turn on OpenTofu state encryption")
+	}
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    severity,
+		Passed:      false,
+		Message:     "Found " + strings.Join(found, ", "),
+		Suggestions: append(suggestions, limitFindings(findings, 8)...),
+		Locations:   locations,
+	}, nil
+}
+
+// isPulumiFile reports whether f is a Pulumi project or stack file
+// (Pulumi.yaml, Pulumi.prod.yaml), or a stack checkpoint saved by the
+// file:// backend under .pulumi/stacks.
+func isPulumiFile(f FileEntry) bool {
+	if strings.HasPrefix(f.Path, ".pulumi/stacks/") || strings.Contains(f.Path, "/.pulumi/stacks/") {
+		return f.Ext == ".json"
+	}
+	return strings.HasPrefix(f.Name, "Pulumi.") && (f.Ext == ".yaml" || f.Ext == ".yml")
+}
+
+// iacSecretValue reports whether name = value hardcodes a credential:
+// a secret-named argument set to anything but a placeholder.
+func iacSecretValue(name, value string) bool {
+	if !reIaCSecretKey.MatchString(name) || value == "" || envPlaceholder.MatchString(value) {
+		return false
+	}
+	// token_ttl = "3600", password_length = "16": settings, not secrets.
+	return strings.Trim(value, "0123456789") != ""
+}
+
+// pulumiPlaintextSecrets returns the secret-named keys a Pulumi stack
+// file sets in plain text rather than as secure: values.
+func pulumiPlaintextSecrets(file string, content []byte) []Location {
+	var doc yaml.Node
+	if yaml.Unmarshal(content, &doc) != nil || len(doc.Content) == 0 {
+		return nil
+	}
+	cfg := yamlMapGet(doc.Content[0], "config")
+	if cfg == nil || cfg.Kind != yaml.MappingNode {
+		return nil
+	}
+	var locs []Location
+	for i := 0; i+1 < len(cfg.Content); i += 2 {
+		key, value := cfg.Content[i], cfg.Content[i+1]
+		// Keys are namespaced: aws:secretKey, myapp:dbPassword.
+		if value.Kind == yaml.ScalarNode && iacSecretValue(key.Value, value.Value) {
+			locs = append(locs, Location{File: file, Line: key.Line, Message: key.Value + " is set in plain text"})
+		}
+	}
+	return locs
+}
+
+// hclBlock is one block of interest in a Terraform file.
+type hclBlock struct {
+	kind, label string
+	body        string
+	line        int
+	start, end  int // offsets of the header and the closing brace
+}
+
+// hclBlocks finds the backend, cloud, encryption, provider, variable and
+// terraform blocks in comment-stripped HCL, with the body up to the
+// matching brace. Nested blocks are found too, so a backend inside
+// terraform { } is listed on its own.
+func hclBlocks(text string) []hclBlock {
+	var blocks []hclBlock
+	for _, m := range reHCLBlock.FindAllStringSubmatchIndex(text, -1) {
+		b := hclBlock{kind: text[m[2]:m[3]], line: strings.Count(text[:m[0]], "\n") + 1}
+		if m[4] >= 0 {
+			b.label = text[m[4]:m[5]]
+		}
+		depth, end := 1, len(text)
+		inString := false
+		for i := m[1]; i < len(text); i++ {
+			switch c := text[i]; {
+			case c == '"' && (i == 0 || text[i-1] != '\\'):
+				inString = !inString
+			case inString:
+			case c == '{':
+				depth++
+			case c == '}':
+				depth--
+			}
+			if depth == 0 {
+				end = i
+				break
+			}
+		}
+		b.body, b.start, b.end = text[m[1]:end], m[0], end
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// stripHCLComments blanks #, // and /* */ comments outside strings,
+// keeping line breaks so line numbers still match.
+func stripHCLComments(text string) string {
+	var b strings.Builder
+	inString, inBlock := false, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case inBlock:
+			if c == '*' && i+1 < len(text) && text[i+1] == '/' {
+				inBlock = false
+				i++
+			} else if c == '\n' {
+				b.WriteByte('\n')
+			}
+			continue
+		case inString:
+			if c == '"' && text[i-1] != '\\' || c == '\n' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '#' || (c == '/' && i+1 < len(text) && text[i+1] == '/'):
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+			if i < len(text) {
+				b.WriteByte('\n')
+			}
+			continue
+		case c == '/' && i+1 < len(text) && text[i+1] == '*':
+			inBlock = true
+			i++
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
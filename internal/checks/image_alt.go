@@ -0,0 +1,152 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+)
+
+// ImageAltCheck measures how many <img> tags in the project's templates
+// carry an alt attribute, per template directory. On its own it only
+// reports the numbers; with checks.imageAlt.minCoverage set, a directory
+// below that percentage turns it into a warning.
+type ImageAltCheck struct{}
+
+func (c ImageAltCheck) ID() string {
+	return "image_alt"
+}
+
+func (c ImageAltCheck) Title() string {
+	return "Image alt text"
+}
+
+var (
+	// <img ...> plus the Next.js/Astro/Nuxt image components. Case matters:
+	// SVG's <image> takes no alt.
+	reImgTag = regexp.MustCompile(`(?s)<(?:img|IMG|Img|Image|NuxtImg|NuxtPicture)\b[^>]*>`)
+	// alt="" is deliberate (decorative image) and counts as covered. A
+	// JSX spread may pass alt through, so it gets the benefit of the doubt,
+	// as does Svelte's {alt} shorthand.
+	reImgAlt = regexp.MustCompile(`(?i)\s(?::|v-bind:)?alt\s*=|\{\s*(?:\.\.\.|alt\s*\})`)
+)
+
+// altCoverage counts images and images with alt text in one directory.
+type altCoverage struct {
+	dir     string
+	images  int
+	withAlt int
+}
+
+func (a altCoverage) percent() int {
+	if a.images == 0 {
+		return 100
+	}
+	return a.withAlt * 100 / a.images
+}
+
+func (c ImageAltCheck) Run(ctx Context) (CheckResult, error) {
+	byDir, missing := scanImageAlt(ctx.RootDir)
+
+	var total altCoverage
+	dirs := make([]altCoverage, 0, len(byDir))
+	for _, d := range byDir {
+		total.images += d.images
+		total.withAlt += d.withAlt
+		dirs = append(dirs, *d)
+	}
+	if total.images == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No images found in templates, skipping",
+		}, nil
+	}
+	// Worst directories first, then by name for stable output.
+	sort.Slice(dirs, func(i, j int) bool {
+		if dirs[i].percent() != dirs[j].percent() {
+			return dirs[i].percent() < dirs[j].percent()
+		}
+		return dirs[i].dir < dirs[j].dir
+	})
+
+	var details []string
+	for _, d := range dirs {
+		details = append(details, fmt.Sprintf("%s - %d/%d images (%d%%)", d.dir, d.withAlt, d.images, d.percent()))
+	}
+
+	minCoverage := 0
+	if cfg := ctx.Config.Checks.ImageAlt; cfg != nil {
+		minCoverage = cfg.MinCoverage
+	}
+	message := fmt.Sprintf("%d%% of %d images have alt text", total.percent(), total.images)
+
+	var below []altCoverage
+	if minCoverage > 0 {
+		message += fmt.Sprintf(" (minimum %d%%)", minCoverage)
+		for _, d := range dirs {
+			if d.percent() < minCoverage {
+				below = append(below, d)
+			}
+		}
+	}
+	if len(below) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  message,
+			Details:  details,
+		}, nil
+	}
+
+	suggestions := []string{
+		"Describe what each image shows in its alt attribute; use alt=\"\" for purely decorative images",
+	}
+	for i, d := range below {
+		if i >= 5 {
+			suggestions = append(suggestions, fmt.Sprintf("... and %d more directories", len(below)-5))
+			break
+		}
+		suggestions = append(suggestions, fmt.Sprintf("%s - %d%% (%d of %d images missing alt)", d.dir, d.percent(), d.images-d.withAlt, d.images))
+	}
+
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    SeverityWarn,
+		Passed:      false,
+		Message:     message,
+		Suggestions: suggestions,
+		Details:     details,
+		Locations:   missing,
+	}, nil
+}
+
+// scanImageAlt walks the project's templates, counting images per
+// directory and returning the location of each image without alt text.
+func scanImageAlt(rootDir string) (map[string]*altCoverage, []Location) {
+	byDir := map[string]*altCoverage{}
+	var missing []Location
+
+	walkTemplateFiles(rootDir, templateExts, func(rel string, content []byte) {
+		dir := path.Dir(rel)
+		cov := byDir[dir]
+		for _, loc := range reImgTag.FindAllIndex(content, -1) {
+			if cov == nil {
+				cov = &altCoverage{dir: dir}
+				byDir[dir] = cov
+			}
+			cov.images++
+			if reImgAlt.Match(content[loc[0]:loc[1]]) {
+				cov.withAlt++
+				continue
+			}
+			missing = append(missing, Location{File: rel, Line: lineAt(content, loc[0]), Message: "Image without alt text"})
+		}
+	})
+	return byDir, missing
+}
@@ -0,0 +1,62 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestImageAltCheck(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"app/views/home/index.html.erb": `<img src="a.png" alt="Team photo">
+<img src="b.png">
+<img src="spacer.gif" alt="">`,
+		"src/components/Hero.tsx": `export const Hero = (p) => <Image src="/hero.png" alt="Hero" />
+const Logo = (props) => <img {...props} />`,
+		"src/components/Icon.svelte":  `<img {alt} src={src}>`,
+		"assets/logo.svg":             `<svg><image href="x.png"/></svg>`,
+		"node_modules/pkg/index.html": `<img src="x.png">`,
+	})
+
+	cfg := &config.PreflightConfig{}
+	result, err := ImageAltCheck{}.Run(Context{RootDir: dir, Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || result.Severity != SeverityInfo {
+		t.Fatalf("without a minimum the check only reports, got %+v", result)
+	}
+	if result.Message != "83% of 6 images have alt text" {
+		t.Errorf("Message = %q", result.Message)
+	}
+	if len(result.Details) == 0 || result.Details[0] != "app/views/home - 2/3 images (66%)" {
+		t.Errorf("Details = %v, want the worst directory first", result.Details)
+	}
+
+	cfg.Checks.ImageAlt = &config.ImageAltConfig{MinCoverage: 90}
+	result, err = ImageAltCheck{}.Run(Context{RootDir: dir, Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed || result.Severity != SeverityWarn {
+		t.Fatalf("below the minimum the check should warn, got %+v", result)
+	}
+	if !strings.Contains(strings.Join(result.Suggestions, "\n"), "app/views/home - 66% (1 of 3 images missing alt)") {
+		t.Errorf("Suggestions = %v", result.Suggestions)
+	}
+	if len(result.Locations) != 1 || result.Locations[0].File != "app/views/home/index.html.erb" || result.Locations[0].Line != 2 {
+		t.Errorf("Locations = %+v", result.Locations)
+	}
+}
+
+func TestImageAltCheckNoImages(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"index.html": "<html><body>hi</body></html>"})
+	result, err := ImageAltCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || !strings.Contains(result.Message, "skipping") {
+		t.Errorf("got %+v, want a skipped pass", result)
+	}
+}
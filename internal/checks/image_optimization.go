@@ -77,8 +77,9 @@ func findLargeImages(rootDir string, threshold int64) []largeImage {
 			continue
 		}
 
-		_ = filepath.WalkDir(rootPath, func(path string, d os.DirEntry, err error) error {
+		_ = walkDir(rootPath, func(path string, d os.DirEntry, err error) error {
 			if err != nil {
+				recordUnreadable(path, err)
 				if d != nil && d.IsDir() {
 					return filepath.SkipDir
 				}
@@ -0,0 +1,252 @@
+package checks
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+	"github.com/preflightsh/preflight/internal/routes"
+)
+
+// DiscoveredURL is one entry of a project's URL inventory.
+type DiscoveredURL struct {
+	Path string `json:"path"`
+	// URL is Path on the configured site, when one is configured.
+	URL string `json:"url,omitempty"`
+	// Sources lists how the path was found: "route" (the framework's
+	// route definitions), "sitemap" (a local or served sitemap) and
+	// "crawl" (linked from a page on the live site).
+	Sources []string `json:"sources"`
+}
+
+// maxSitemapFetches caps the child sitemaps a sitemap index can send the
+// inventory to.
+const maxSitemapFetches = 20
+
+// pageExcludedExts are link targets that aren't pages, left out of the
+// inventory and never crawled.
+var pageExcludedExts = map[string]bool{
+	".css": true, ".js": true, ".mjs": true, ".map": true, ".json": true, ".xml": true, ".txt": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".avif": true, ".svg": true, ".ico": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true,
+	".pdf": true, ".zip": true, ".gz": true, ".mp4": true, ".webm": true, ".mp3": true,
+}
+
+// DiscoverURLs lists the page paths a project serves, from its route
+// definitions, its sitemaps (built and, when a URL is configured, served)
+// and a breadth-first crawl of up to crawlLimit pages of the live site.
+// Dynamic routes are left out: they can't be requested as written.
+func DiscoverURLs(ctx Context, crawlLimit int) []DiscoveredURL {
+	found := map[string]map[string]bool{}
+	add := func(p, source string) {
+		if p == "" {
+			p = "/"
+		}
+		if !strings.HasPrefix(p, "/") || pageExcludedExts[strings.ToLower(path.Ext(p))] {
+			return
+		}
+		if found[p] == nil {
+			found[p] = map[string]bool{}
+		}
+		found[p][source] = true
+	}
+
+	for _, r := range routes.Extract(ctx.RootDir) {
+		if r.Requestable() {
+			add(r.Path, "route")
+		}
+	}
+
+	roots := append(staticOutputDirsFor(ctx.Config.AllStacks()), driftWebRoots...)
+	if outDir, sitemapPath := findBuiltSitemap(ctx.RootDir, roots); outDir != "" {
+		if listed, err := readSitemapPaths(sitemapPath, outDir, map[string]bool{}); err == nil {
+			for _, loc := range listed {
+				add(urlPath(loc), "sitemap")
+			}
+		}
+	}
+
+	var baseURL *url.URL
+	if base := configuredProbeBaseURL(ctx); base != "" {
+		if !strings.Contains(base, "://") {
+			// Bare local dev hosts (localhost:3000) are usually plain HTTP.
+			if IsLocalURL(base) {
+				base = "http://" + base
+			} else {
+				base = "https://" + base
+			}
+		}
+		if u, err := url.Parse(strings.TrimSuffix(base, "/")); err == nil && u.Host != "" {
+			baseURL = u
+		}
+	}
+	if baseURL != nil && ctx.Client != nil {
+		for _, loc := range fetchSitemapLocs(ctx, baseURL) {
+			add(loc, "sitemap")
+		}
+		for _, p := range crawlSite(ctx, baseURL, crawlLimit) {
+			add(p, "crawl")
+		}
+	}
+
+	out := make([]DiscoveredURL, 0, len(found))
+	for p, sources := range found {
+		d := DiscoveredURL{Path: p}
+		if baseURL != nil {
+			d.URL = baseURL.String() + p
+		}
+		for _, s := range []string{"route", "sitemap", "crawl"} {
+			if sources[s] {
+				d.Sources = append(d.Sources, s)
+			}
+		}
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+// fetchSitemapLocs returns the paths the site's served /sitemap.xml lists,
+// following a sitemap index to children on the same host.
+func fetchSitemapLocs(ctx Context, base *url.URL) []string {
+	var paths []string
+	queue := []string{base.String() + "/sitemap.xml"}
+	seen := map[string]bool{}
+	for fetches := 0; len(queue) > 0 && fetches < maxSitemapFetches; fetches++ {
+		next := queue[0]
+		queue = queue[1:]
+		if seen[next] {
+			continue
+		}
+		seen[next] = true
+		body, _, ok := fetchBody(ctx, next)
+		if !ok {
+			continue
+		}
+		var set sitemapURLSet
+		if xml.Unmarshal(body, &set) != nil {
+			continue
+		}
+		for _, u := range set.URLs {
+			if p, ok := sameHostPath(base, strings.TrimSpace(u.Loc)); ok {
+				paths = append(paths, p)
+			}
+		}
+		for _, s := range set.Sitemaps {
+			loc := strings.TrimSpace(s.Loc)
+			if _, ok := sameHostPath(base, loc); ok {
+				queue = append(queue, loc)
+			}
+		}
+	}
+	return paths
+}
+
+// crawlSite follows same-host links breadth-first from the homepage,
+// fetching at most limit pages, and returns every page path it saw linked.
+func crawlSite(ctx Context, base *url.URL, limit int) []string {
+	if limit <= 0 {
+		return nil
+	}
+	seen := map[string]bool{"/": true}
+	queue := []string{"/"}
+	for fetched := 0; len(queue) > 0 && fetched < limit; fetched++ {
+		if ctx.reqContext().Err() != nil {
+			break
+		}
+		current := queue[0]
+		queue = queue[1:]
+		body, pageURL, ok := fetchBody(ctx, base.String()+current)
+		if !ok || !looksLikeHTML(body) {
+			continue
+		}
+		for _, href := range pageLinks(string(body)) {
+			ref, err := pageURL.Parse(href)
+			if err != nil {
+				continue
+			}
+			p, ok := sameHostPath(base, ref.String())
+			if !ok || seen[p] || pageExcludedExts[strings.ToLower(path.Ext(p))] {
+				continue
+			}
+			seen[p] = true
+			queue = append(queue, p)
+		}
+	}
+	paths := make([]string, 0, len(seen))
+	for p := range seen {
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// fetchBody GETs rawURL and returns its body when the response is a 200,
+// along with the URL it was finally served from.
+func fetchBody(ctx Context, rawURL string) ([]byte, *url.URL, bool) {
+	resp, err := doGet(ctx.reqContext(), ctx.Client, rawURL)
+	if err != nil {
+		return nil, nil, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, false
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, netutil.MaxResponseBody))
+	if err != nil {
+		return nil, nil, false
+	}
+	return body, resp.Request.URL, true
+}
+
+func looksLikeHTML(body []byte) bool {
+	head := strings.ToLower(strings.TrimSpace(string(body[:min(len(body), 512)])))
+	return strings.HasPrefix(head, "<!doctype html") || strings.Contains(head, "<html")
+}
+
+// sameHostPath returns the path of rawURL when it's on base's host (with
+// or without www.), with the query and fragment dropped.
+func sameHostPath(base *url.URL, rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", false
+	}
+	bareHost := func(h string) string { return strings.TrimPrefix(strings.ToLower(h), "www.") }
+	if bareHost(u.Host) != bareHost(base.Host) {
+		return "", false
+	}
+	if u.Path == "" {
+		return "/", true
+	}
+	return u.Path, true
+}
+
+// pageLinks returns the href of every <a> in doc.
+func pageLinks(doc string) []string {
+	var hrefs []string
+	z := html.NewTokenizer(strings.NewReader(doc))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return hrefs
+		case html.StartTagToken:
+			name, hasAttr := z.TagName()
+			if string(name) != "a" {
+				continue
+			}
+			for hasAttr {
+				var k, v []byte
+				k, v, hasAttr = z.TagAttr()
+				if strings.EqualFold(string(k), "href") {
+					hrefs = append(hrefs, strings.TrimSpace(string(v)))
+				}
+			}
+		}
+	}
+}
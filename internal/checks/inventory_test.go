@@ -0,0 +1,71 @@
+package checks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestDiscoverURLs(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<!doctype html><a href="/about">About</a><a href="blog/">Blog</a>
+				<a href="https://elsewhere.example/">Out</a><a href="/logo.png">Logo</a><a href="mailto:x@y">Mail</a>`)
+		case "/blog/":
+			fmt.Fprint(w, `<!doctype html><a href="first-post?ref=blog#top">First</a>`)
+		case "/sitemap.xml":
+			fmt.Fprintf(w, `<sitemapindex><sitemap><loc>%s/pages.xml</loc></sitemap></sitemapindex>`, srv.URL)
+		case "/pages.xml":
+			fmt.Fprintf(w, `<urlset><url><loc>%s/pricing</loc></url><url><loc>%s/about</loc></url></urlset>`, srv.URL, srv.URL)
+		case "/about":
+			fmt.Fprint(w, `<!doctype html>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	dir := writeFiles(t, map[string]string{
+		"app/page.tsx":             "",
+		"app/contact/page.tsx":     "",
+		"app/blog/[slug]/page.tsx": "",
+		"public/sitemap.xml":       `<urlset><url><loc>https://demo.example/contact</loc></url></urlset>`,
+	})
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	got := DiscoverURLs(Context{RootDir: dir, Config: cfg, Client: srv.Client()}, 10)
+
+	var lines []string
+	for _, u := range got {
+		lines = append(lines, u.Path+" "+strings.Join(u.Sources, ";"))
+		if u.URL != srv.URL+u.Path {
+			t.Errorf("%s: URL = %q", u.Path, u.URL)
+		}
+	}
+	want := []string{
+		"/ route;crawl",
+		"/about sitemap;crawl",
+		"/blog/ crawl",
+		"/blog/first-post crawl",
+		"/contact route;sitemap",
+		"/pricing sitemap",
+	}
+	if !slices.Equal(lines, want) {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Without a URL nothing is fetched and paths stay relative.
+	cfg.URLs.Production = ""
+	for _, u := range DiscoverURLs(Context{RootDir: dir, Config: cfg, Client: srv.Client()}, 10) {
+		if u.URL != "" || slices.Contains(u.Sources, "crawl") {
+			t.Errorf("offline inventory has %+v", u)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// ipv6OnlyNetworks are kinds of network where clients have no IPv4 of
+// their own. They reach IPv4-only sites through NAT64 or 464XLAT gateways,
+// which add latency and break clients that hard-code IPv4.
+var ipv6OnlyNetworks = []string{
+	"Mobile carriers running IPv6-only cores (T-Mobile US, Reliance Jio, EE and others)",
+	"IPv6-only cloud subnets and Kubernetes clusters (AWS, GCP, Azure), where IPv4 egress costs extra",
+	"Apple's App Review network, which requires apps to work on IPv6-only (NAT64) networks",
+	"Newer broadband and campus networks short of IPv4 addresses",
+}
+
+// ipv6ProbeAddr is dialed over UDP to learn whether the scanning host has
+// an IPv6 route. Connecting a UDP socket sends nothing.
+const ipv6ProbeAddr = "[2001:4860:4860::8888]:53"
+
+type IPv6Check struct{}
+
+func (c IPv6Check) ID() string {
+	return "ipv6"
+}
+
+func (c IPv6Check) Title() string {
+	return "IPv6 readiness"
+}
+
+// Run looks up the production domain's AAAA records and, when the
+// scanning host has IPv6 itself, requests the homepage over each address
+// until one answers.
+func (c IPv6Check) Run(ctx Context) (CheckResult, error) {
+	prod := ctx.Config.URLs.Production
+	if prod == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Skipped (no production URL)",
+		}, nil
+	}
+	if !strings.HasPrefix(prod, "http") {
+		prod = "https://" + prod
+	}
+	prodURL, err := url.Parse(prod)
+	if err != nil || prodURL.Hostname() == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Skipped (could not parse domain)",
+		}, nil
+	}
+	domain := prodURL.Hostname()
+	if net.ParseIP(domain) != nil || domain == "localhost" || strings.HasSuffix(domain, ".localhost") {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Skipped (production URL isn't a public domain)",
+		}, nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx.reqContext(), 10*time.Second)
+	defer cancel()
+	countNetworkCall()
+	ips, err := netutil.LookupIPAddr(lookupCtx, domain)
+	var v6 []net.IP
+	for _, ip := range ips {
+		if ip.IP.To4() == nil {
+			v6 = append(v6, ip.IP)
+		}
+	}
+	if err != nil && !isDNSNotFound(err) {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  fmt.Sprintf("DNS lookup error for %s: %v", domain, err),
+			Suggestions: []string{
+				"Check your network connection and DNS resolver",
+			},
+		}, nil
+	}
+
+	if len(v6) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  fmt.Sprintf("No AAAA record for %s: the site has no IPv6 address", domain),
+			Suggestions: []string{
+				"Turn on IPv6 at your CDN or host (Cloudflare, CloudFront, Fastly and Vercel serve it when enabled) and publish its AAAA record",
+				"Clients on IPv6-only networks then reach you directly instead of through a NAT64 gateway",
+			},
+			Details: append([]string{"IPv6-only networks:"}, ipv6OnlyNetworks...),
+		}, nil
+	}
+
+	for _, ip := range v6 {
+		if netutil.IsPrivateIP(ip) {
+			return CheckResult{
+				ID:       c.ID(),
+				Title:    c.Title(),
+				Severity: SeverityWarn,
+				Passed:   false,
+				Message:  fmt.Sprintf("AAAA record for %s points at a non-public address (%s)", domain, ip),
+				Suggestions: []string{
+					"Replace the AAAA record with your host's public IPv6 address, or remove it: clients that try it first fail to connect",
+				},
+			}, nil
+		}
+	}
+
+	if !hostHasIPv6() {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("AAAA record for %s (%s)", domain, v6[0]),
+			Details:  []string{"Reachability over IPv6 not tested: this host has no IPv6 route"},
+		}, nil
+	}
+
+	var failures []string
+	for _, ip := range v6 {
+		status, err := probeIPv6(ctx, prodURL, ip)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", ip, err))
+			continue
+		}
+		if status >= 500 {
+			return CheckResult{
+				ID:       c.ID(),
+				Title:    c.Title(),
+				Severity: SeverityWarn,
+				Passed:   false,
+				Message:  fmt.Sprintf("%s returns HTTP %d over IPv6 (%s)", domain, status, ip),
+				Suggestions: []string{
+					"Check that the server behind the AAAA record serves the same site as the IPv4 one",
+				},
+			}, nil
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("Reachable over IPv6 at %s (HTTP %d)", ip, status),
+		}, nil
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  fmt.Sprintf("%s has an AAAA record but isn't reachable over IPv6", domain),
+		Suggestions: []string{
+			"Check that the AAAA record points at your current host or CDN",
+			"Make sure the server and firewall accept IPv6 connections (in nginx, listen [::]:443)",
+		},
+		Details: limitFindings(failures, 5),
+	}, nil
+}
+
+// hostHasIPv6 reports whether the scanning host can route to the IPv6
+// internet, without which a failed probe says nothing about the site.
+func hostHasIPv6() bool {
+	conn, err := net.Dial("udp6", ipv6ProbeAddr)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// probeIPv6 requests u with every connection dialed to ip, and returns the
+// response status. Redirects aren't followed: any answer shows the site
+// is reachable.
+func probeIPv6(ctx Context, u *url.URL, ip net.IP) (int, error) {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(dctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(dctx, "tcp6", net.JoinHostPort(ip.String(), port))
+			},
+			TLSHandshakeTimeout: 5 * time.Second,
+			DisableKeepAlives:   true,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx.reqContext(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "Preflight/1.0")
+	countNetworkCall()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,37 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+func TestIPv6Check(t *testing.T) {
+	netutil.SetResolver(fakeDNS{ips: map[string][]string{
+		"v4only.example.com":  {"93.184.215.14"},
+		"private.example.com": {"93.184.215.14", "fd00::1"},
+	}})
+	defer netutil.ConfigureResolver("", "")
+
+	for _, tc := range []struct {
+		production string
+		passed     bool
+		message    string
+	}{
+		{"https://v4only.example.com", false, "No AAAA record for v4only.example.com"},
+		{"https://private.example.com", false, "AAAA record for private.example.com points at a non-public address (fd00::1)"},
+		{"http://localhost:3000", true, "Skipped (production URL isn't a public domain)"},
+	} {
+		cfg := &config.PreflightConfig{}
+		cfg.URLs.Production = tc.production
+		result, err := IPv6Check{}.Run(Context{Config: cfg})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Passed != tc.passed || !strings.HasPrefix(result.Message, tc.message) {
+			t.Errorf("%s: got passed=%v message=%q", tc.production, result.Passed, result.Message)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package checks
 
 import (
-	"os"
 	"path/filepath"
 	"regexp"
 )
@@ -24,7 +23,7 @@ func (c LangAttributeCheck) Run(ctx Context) (CheckResult, error) {
 	if cfg != nil {
 		configuredLayout = cfg.MainLayout
 	}
-	layoutFile := getLayoutFile(ctx.RootDir, ctx.Config.Stack, configuredLayout)
+	layoutFile := getLayoutFile(ctx.RootDir, ctx.Config.AllStacks(), configuredLayout)
 
 	if layoutFile == "" {
 		return CheckResult{
@@ -37,7 +36,7 @@ func (c LangAttributeCheck) Run(ctx Context) (CheckResult, error) {
 	}
 
 	layoutPath := filepath.Join(ctx.RootDir, layoutFile)
-	content, err := os.ReadFile(layoutPath)
+	content, err := readFile(layoutPath)
 	if err != nil {
 		return CheckResult{
 			ID:       c.ID(),
@@ -61,13 +60,13 @@ func (c LangAttributeCheck) Run(ctx Context) (CheckResult, error) {
 		}, nil
 	}
 
-	// Check included template files
-	for _, includePath := range resolveTemplateIncludes(contentStr, ctx.RootDir, ctx.Config.Stack) {
-		includeContent, err := os.ReadFile(includePath)
-		if err != nil {
+	// Check included template files, following includes of includes
+	graph, _ := templateGraph(layoutPath, ctx.RootDir, ctx.Config.Stack)
+	for _, included := range graph {
+		if included.path == layoutPath {
 			continue
 		}
-		if hasLangAttribute(string(includeContent), ctx.Config.Stack) {
+		if hasLangAttribute(included.content, ctx.Config.Stack) {
 			return CheckResult{
 				ID:       c.ID(),
 				Title:    c.Title(),
@@ -232,7 +231,7 @@ func checkLangPartials(rootDir, stack string) bool {
 
 	for _, layoutPath := range layoutPaths {
 		fullPath := filepath.Join(rootDir, layoutPath)
-		content, err := os.ReadFile(fullPath)
+		content, err := readFile(fullPath)
 		if err != nil {
 			continue
 		}
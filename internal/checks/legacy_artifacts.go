@@ -0,0 +1,171 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LegacyArtifactsCheck finds leftovers a starter template or an old
+// version of the site brought along: AMP pages nobody maintains, copies
+// of jQuery from before its XSS fixes, and analytics snippets that can't
+// be collecting anything, because their ID is a placeholder or a
+// Universal Analytics property Google has shut down.
+type LegacyArtifactsCheck struct{}
+
+func (c LegacyArtifactsCheck) ID() string {
+	return "legacy_artifacts"
+}
+
+func (c LegacyArtifactsCheck) Title() string {
+	return "Leftover template artifacts"
+}
+
+var (
+	// reAMP matches an AMP document or an app opting pages into AMP.
+	reAMP = regexp.MustCompile(`<html[^>]*\s(amp|⚡)[\s>=]|cdn\.ampproject\.org|\bwithAmp\(|\buseAmp\(|\bconfig\s*=\s*\{[^}]*\bamp:\s*(true|['"]hybrid['"])`)
+	// reJQueryName and reJQueryBanner read a jQuery copy's version from
+	// its file name or license banner.
+	reJQueryName   = regexp.MustCompile(`(?i)^jquery[-.]?(\d+)\.(\d+)(?:\.(\d+))?(?:\.min|\.slim|\.slim\.min)?\.js$`)
+	reJQueryBanner = regexp.MustCompile(`jQuery (?:JavaScript Library )?v(\d+)\.(\d+)\.(\d+)`)
+	// reUniversalAnalytics matches a Universal Analytics property ID.
+	reUniversalAnalytics = regexp.MustCompile(`['"]UA-\d{4,10}-\d{1,4}['"]`)
+)
+
+// legacyArtifactExts are the templates and scripts the artifacts live in.
+var legacyArtifactExts = map[string]bool{
+	".html": true, ".htm": true, ".erb": true, ".php": true, ".twig": true, ".liquid": true,
+	".hbs": true, ".njk": true, ".vue": true, ".svelte": true, ".astro": true, ".haml": true,
+	".slim": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true,
+}
+
+// jQuery 3.5.0 fixed the htmlPrefilter XSS (CVE-2020-11022, -11023).
+var minJQueryVersion = [3]int{3, 5, 0}
+
+func (c LegacyArtifactsCheck) Run(ctx Context) (CheckResult, error) {
+	files := ctx.files()
+	var findings []string
+	var locations []Location
+	kinds := map[string]bool{}
+	report := func(kind string, loc Location) {
+		kinds[kind] = true
+		findings = append(findings, loc.String())
+		locations = append(locations, loc)
+	}
+
+	ampFiles := 0
+	for _, f := range files.Files {
+		if f.Ignored || !legacyArtifactExts[f.Ext] || f.Size > 2*1024*1024 || f.inDir(vendorSkipDirs) || isTestSource(f.Path) {
+			continue
+		}
+		if strings.HasPrefix(f.Path, ".") || strings.Contains(f.Path, "/.") {
+			continue
+		}
+
+		if f.Ext == ".js" && strings.HasPrefix(strings.ToLower(f.Name), "jquery") {
+			content, err := readFile(files.Abs(f))
+			if err != nil {
+				continue
+			}
+			if version, ok := jqueryVersion(f.Name, content); ok && versionLess(version, minJQueryVersion) {
+				report("jquery", Location{File: f.Path, Message: fmt.Sprintf("jQuery %d.%d.%d predates the 3.5 XSS fixes", version[0], version[1], version[2])})
+			}
+			continue
+		}
+		// Build output repeats what the templates say.
+		if f.Size > 500*1024 || f.inDir(templateSkipDirs) {
+			continue
+		}
+		content, err := readFile(files.Abs(f))
+		if err != nil {
+			continue
+		}
+		if loc := reAMP.FindIndex(content); loc != nil {
+			ampFiles++
+			if ampFiles <= 3 {
+				report("amp", Location{File: f.Path, Line: lineAt(content, loc[0]), Message: "AMP page or AMP opt-in"})
+			}
+		}
+		if loc := reUniversalAnalytics.FindIndex(content); loc != nil {
+			id := strings.Trim(string(content[loc[0]:loc[1]]), `'"`)
+			if !reAnalyticsPlaceholder.MatchString(id) {
+				report("ua", Location{File: f.Path, Line: lineAt(content, loc[0]), Message: "Universal Analytics property " + id + ", which stopped collecting data in July 2023"})
+			}
+		}
+	}
+	if ampFiles > 3 {
+		findings = append(findings, fmt.Sprintf("... and %d more AMP files", ampFiles-3))
+	}
+	if ctx.PageHTML != "" && len(parseRenderedHTML(ctx.PageHTML).linkRels["amphtml"]) > 0 && !kinds["amp"] {
+		kinds["amp"] = true
+		findings = append(findings, "The homepage links an AMP version (rel=amphtml)")
+	}
+
+	if len(findings) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No AMP pages, outdated jQuery or Universal Analytics snippets found",
+		}, nil
+	}
+
+	var found, suggestions []string
+	if kinds["amp"] {
+		found = append(found, "AMP pages")
+		suggestions = append(suggestions, "Remove AMP pages and the amphtml links to them: Google no longer favors AMP in search, and the copies drift from the real pages")
+	}
+	if kinds["jquery"] {
+		found = append(found, "outdated jQuery")
+		suggestions = append(suggestions, "Delete unused jQuery copies, or upgrade to jQuery 3.5 or later")
+	}
+	if kinds["ua"] {
+		found = append(found, "Universal Analytics snippets")
+		suggestions = append(suggestions, "Remove Universal Analytics snippets, or replace them with your GA4 measurement ID (G-...)")
+	}
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    SeverityWarn,
+		Passed:      false,
+		Message:     "Found " + strings.Join(found, ", "),
+		Suggestions: append(suggestions, limitFindings(findings, 8)...),
+		Locations:   locations,
+	}, nil
+}
+
+// vendorSkipDirs hold installed dependencies rather than the project's
+// own copies, which keep whatever jQuery their packages need.
+var vendorSkipDirs = map[string]bool{"node_modules": true, "vendor": true, "bower_components": true}
+
+// jqueryVersion reads a jQuery file's version from its license banner,
+// or failing that its name (jquery-1.12.4.min.js).
+func jqueryVersion(name string, content []byte) ([3]int, bool) {
+	head := content
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	m := reJQueryBanner.FindSubmatch(head)
+	if m == nil {
+		m = reJQueryName.FindSubmatch([]byte(name))
+	}
+	if m == nil {
+		return [3]int{}, false
+	}
+	var v [3]int
+	for i := range v {
+		v[i], _ = strconv.Atoi(string(m[i+1]))
+	}
+	return v, true
+}
+
+func versionLess(a, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
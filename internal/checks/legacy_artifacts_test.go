@@ -0,0 +1,62 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestLegacyArtifactsCheck(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"public/js/jquery-1.12.4.min.js": "/*! jQuery v1.12.4 | (c) jQuery Foundation | jquery.org/license */",
+		"public/js/jquery.min.js":        "/*! jQuery v3.7.1 | (c) OpenJS Foundation and other contributors */",
+		"node_modules/jquery/jquery.js":  "/*! jQuery v1.4.2 */",
+		"templates/amp/post.html":        "<!doctype html>\n<html ⚡ lang=\"en\">",
+		"src/analytics.js":               "ga('create', 'UA-48213377-2', 'auto');",
+		"src/analytics.test.js":          "ga('create', 'UA-48213377-9', 'auto');",
+		"templates/partials/footer.html": "<script>gtag('config', 'GA_MEASUREMENT_ID');</script>",
+		"app/views/layouts/app.html.erb": "<%= javascript_include_tag 'application' %>",
+	})
+	result, err := LegacyArtifactsCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed {
+		t.Fatal("expected a warning")
+	}
+	if want := "Found AMP pages, outdated jQuery, Universal Analytics snippets"; result.Message != want {
+		t.Errorf("message = %q, want %q", result.Message, want)
+	}
+	joined := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{
+		"public/js/jquery-1.12.4.min.js - jQuery 1.12.4 predates the 3.5 XSS fixes",
+		"templates/amp/post.html:2 - AMP page or AMP opt-in",
+		"src/analytics.js:1 - Universal Analytics property UA-48213377-2",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+	for _, unwanted := range []string{"jquery.min.js", "node_modules", "analytics.test.js", "GA_MEASUREMENT_ID"} {
+		if strings.Contains(joined, unwanted) {
+			t.Errorf("suggestions should not mention %q:\n%s", unwanted, joined)
+		}
+	}
+	if len(result.Locations) != 3 {
+		t.Errorf("got %d locations, want 3", len(result.Locations))
+	}
+}
+
+func TestLegacyArtifactsCheckClean(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"templates/base.html": "<script>gtag('config', 'G-8QX2LMN4PZ');</script>",
+	})
+	result, err := LegacyArtifactsCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed {
+		t.Errorf("expected pass, got %q %v", result.Message, result.Suggestions)
+	}
+}
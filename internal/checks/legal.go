@@ -2,11 +2,16 @@ package checks
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+	"github.com/preflightsh/preflight/internal/routes"
 )
 
 // getWithContext is a context-aware GET that, unlike doGet, does not set
@@ -38,6 +43,8 @@ func (c LegalPagesCheck) Run(ctx Context) (CheckResult, error) {
 	hasPrivacy := false
 	hasTerms := false
 	var privacyPath, termsPath string
+	// privacyContent is the policy itself, when it could be read.
+	var privacyContent string
 
 	// First, try to check via HTTP if URLs are configured (handles CMS-generated pages)
 	baseURL := ctx.Config.URLs.Staging
@@ -50,15 +57,7 @@ func (c LegalPagesCheck) Run(ctx Context) (CheckResult, error) {
 	baseURL = strings.TrimSuffix(baseURL, "/")
 
 	if baseURL != "" {
-		// Reuse ctx.Client (which already handles the local-vs-safe choice
-		// based on the configured URLs) but override CheckRedirect so 3xx
-		// is treated as "page exists" rather than followed. Copy the
-		// client by value so we don't mutate the shared one.
-		clientCopy := *ctx.Client
-		clientCopy.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		}
-		client := &clientCopy
+		client := noRedirectClient(ctx.Client)
 
 		privacyURLs := []string{
 			"/privacy", "/privacy-policy", "/privacypolicy",
@@ -67,25 +66,14 @@ func (c LegalPagesCheck) Run(ctx Context) (CheckResult, error) {
 			"/privacy-notice", "/privacy-statement",
 			"/info/privacy", "/about/privacy",
 		}
-		for _, path := range privacyURLs {
-			if hasPrivacy {
-				break
-			}
-			resp, err := getWithContext(ctx.reqContext(), client, baseURL+path)
-			if err == nil {
+		if path := probeLegalPage(ctx, client, baseURL, privacyURLs, "privacy"); path != "" {
+			hasPrivacy = true
+			privacyPath = path + " (via HTTP)"
+			if resp, err := getWithContext(ctx.reqContext(), ctx.Client, baseURL+path); err == nil {
+				body, err := io.ReadAll(io.LimitReader(resp.Body, netutil.MaxResponseBody))
 				resp.Body.Close()
-				if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-					hasPrivacy = true
-					privacyPath = path + " (via HTTP)"
-				} else if resp.StatusCode >= 300 && resp.StatusCode < 400 {
-					// Count a redirect as "found" only if it stays on the same
-					// domain, isn't a login/auth bounce, and actually lands on a
-					// privacy-looking URL (not a path-clean or homepage bounce).
-					loc := resp.Header.Get("Location")
-					if isSameDomainRedirect(baseURL, loc) && !isAuthRedirect(loc) && redirectMentions(loc, "privacy") {
-						hasPrivacy = true
-						privacyPath = path + " (via HTTP)"
-					}
+				if err == nil && resp.StatusCode < 400 {
+					privacyContent = string(body)
 				}
 			}
 		}
@@ -97,36 +85,11 @@ func (c LegalPagesCheck) Run(ctx Context) (CheckResult, error) {
 			"/terms-and-conditions", "/terms-conditions",
 			"/info/terms", "/about/terms", "/eula",
 		}
-		for _, path := range termsURLs {
-			if hasTerms {
-				break
-			}
-			resp, err := getWithContext(ctx.reqContext(), client, baseURL+path)
-			if err == nil {
-				resp.Body.Close()
-				if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-					hasTerms = true
-					termsPath = path + " (via HTTP)"
-				} else if resp.StatusCode >= 300 && resp.StatusCode < 400 {
-					loc := resp.Header.Get("Location")
-					if isSameDomainRedirect(baseURL, loc) && !isAuthRedirect(loc) && redirectMentions(loc, "terms", "tos", "eula") {
-						hasTerms = true
-						termsPath = path + " (via HTTP)"
-					}
-				}
-			}
+		if path := probeLegalPage(ctx, client, baseURL, termsURLs, "terms", "tos", "eula"); path != "" {
+			hasTerms = true
+			termsPath = path + " (via HTTP)"
 		}
 
-		// If we found both via HTTP, return early
-		if hasPrivacy && hasTerms {
-			return CheckResult{
-				ID:       c.ID(),
-				Title:    c.Title(),
-				Severity: SeverityInfo,
-				Passed:   true,
-				Message:  "Found privacy at " + privacyPath + ", terms at " + termsPath,
-			}, nil
-		}
 	}
 
 	// Common privacy policy paths/filenames
@@ -140,100 +103,16 @@ func (c LegalPagesCheck) Run(ctx Context) (CheckResult, error) {
 		"privacy-notice", "privacy-statement",
 	}
 
-	// Common terms paths/filenames
-	termsPatterns := []string{
-		"terms", "terms-of-service", "terms_of_service", "tos", "termsofservice",
-		"legal/terms", "legal/terms-of-service", "legal/tos",
-		"pages/terms", "pages/terms-of-service",
-		"policies/terms", "policies/terms-of-service",
-		"legalese/terms", "legalese/terms-of-service",
-		"info/terms", "about/terms",
-		"terms-and-conditions", "terms-conditions", "eula",
-	}
-
-	// Extensions to check
-	extensions := []string{
-		"", ".html", ".htm", ".php", ".md", ".mdx",
-		".tsx", ".jsx", ".js", ".ts", ".vue", ".svelte",
-		".erb", ".erb.html", ".html.erb",
-		".blade.php", ".twig", ".njk", ".liquid",
-		".astro",
-	}
-
-	// Directories to search
-	searchDirs := []string{
-		"",
-		"app",
-		"src/app",
-		"src/pages",
-		"pages",
-		"views",
-		"resources/views",
-		"templates",
-		"content",
-		"public",
-		"static",
-		"web",
-		"www",
-		"htdocs",
-		"public_html",
-	}
-
-	// Check for privacy policy
-	for _, dir := range searchDirs {
-		if hasPrivacy {
-			break
-		}
-		for _, pattern := range privacyPatterns {
-			if hasPrivacy {
-				break
-			}
-			for _, ext := range extensions {
-				checkPath := filepath.Join(ctx.RootDir, dir, pattern+ext)
-				if _, err := os.Stat(checkPath); err == nil {
-					hasPrivacy = true
-					privacyPath = filepath.Join(dir, pattern+ext)
-					break
-				}
-				// Also check with /page pattern for Next.js app router
-				if dir == "app" || dir == "src/app" {
-					pagePath := filepath.Join(ctx.RootDir, dir, pattern, "page"+ext)
-					if _, err := os.Stat(pagePath); err == nil {
-						hasPrivacy = true
-						privacyPath = filepath.Join(dir, pattern, "page"+ext)
-						break
-					}
-				}
-			}
+	if !hasPrivacy {
+		if path := findLegalPageFile(ctx.RootDir, privacyPatterns); path != "" {
+			hasPrivacy = true
+			privacyPath = path
 		}
 	}
-
-	// Check for terms
-	for _, dir := range searchDirs {
-		if hasTerms {
-			break
-		}
-		for _, pattern := range termsPatterns {
-			if hasTerms {
-				break
-			}
-			for _, ext := range extensions {
-				checkPath := filepath.Join(ctx.RootDir, dir, pattern+ext)
-				if _, err := os.Stat(checkPath); err == nil {
-					hasTerms = true
-					termsPath = filepath.Join(dir, pattern+ext)
-					break
-				}
-				// Also check with /page pattern for Next.js app router
-				if dir == "app" || dir == "src/app" {
-					pagePath := filepath.Join(ctx.RootDir, dir, pattern, "page"+ext)
-					if _, err := os.Stat(pagePath); err == nil {
-						hasTerms = true
-						termsPath = filepath.Join(dir, pattern, "page"+ext)
-						break
-					}
-				}
-			}
+	if !hasTerms {
+		if path := findLegalPageFile(ctx.RootDir, legalTermsPatterns); path != "" {
+			hasTerms = true
+			termsPath = path
 		}
 	}
 
@@ -249,7 +128,8 @@ func (c LegalPagesCheck) Run(ctx Context) (CheckResult, error) {
 			if _, err := os.Stat(dirPath); err != nil {
 				continue
 			}
-			_ = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+			_ = walk(dirPath, func(path string, info os.FileInfo, err error) error {
+				recordUnreadable(path, err)
 				if err != nil || (hasPrivacy && hasTerms) {
 					return nil
 				}
@@ -309,6 +189,27 @@ func (c LegalPagesCheck) Run(ctx Context) (CheckResult, error) {
 		}
 	}
 
+	// Routes defined in code (Rails routes.rb, Laravel route files) serve
+	// pages no file is named after.
+	if !hasPrivacy {
+		if r, ok := findLegalRoute(ctx.RootDir, privacyRoute); ok {
+			hasPrivacy = true
+			privacyPath = r.Path + " (route in " + r.Source + ")"
+		}
+	}
+	if !hasTerms {
+		if r, ok := findLegalRoute(ctx.RootDir, termsTarget); ok {
+			hasTerms = true
+			termsPath = r.Path + " (route in " + r.Source + ")"
+		}
+	}
+
+	if hasPrivacy && privacyContent == "" && !strings.HasSuffix(privacyPath, "(via HTTP)") {
+		if data, err := readFile(filepath.Join(ctx.RootDir, privacyPath)); err == nil {
+			privacyContent = string(data)
+		}
+	}
+
 	// Check layout and common partials for links to privacy/terms
 	if !hasPrivacy || !hasTerms {
 		filesToCheck := []string{}
@@ -318,30 +219,14 @@ func (c LegalPagesCheck) Run(ctx Context) (CheckResult, error) {
 			filesToCheck = append(filesToCheck, ctx.Config.Checks.SEOMeta.MainLayout)
 		}
 
-		// Common footer/partial files that often contain legal links
-		commonPartials := []string{
-			"footer.php", "includes/footer.php", "inc/footer.php", "partials/footer.php",
-			"_footer.php", "_includes/footer.php",
-			"footer.html", "includes/footer.html", "_includes/footer.html",
-			"components/Footer.tsx", "components/Footer.jsx", "components/footer.tsx",
-			"src/components/Footer.tsx", "src/components/Footer.jsx",
-			"app/components/Footer.tsx", "app/components/footer.tsx",
-			"templates/_footer.twig", "templates/partials/footer.twig",
-			"templates/_partials/footer.twig", "templates/footer.twig",
-			"resources/views/partials/footer.blade.php",
-			"resources/views/layouts/partials/footer.blade.php",
-			"app/views/layouts/_footer.html.erb", "app/views/shared/_footer.html.erb",
-			"_includes/footer.html", "layouts/partials/footer.html",
-			"index.php", "index.html", "public/index.html",
-		}
-		filesToCheck = append(filesToCheck, commonPartials...)
+		filesToCheck = append(filesToCheck, legalFooterPartials...)
 
 		for _, file := range filesToCheck {
 			if hasPrivacy && hasTerms {
 				break
 			}
 			filePath := filepath.Join(ctx.RootDir, file)
-			if content, err := os.ReadFile(filePath); err == nil {
+			if content, err := readFile(filePath); err == nil {
 				contentLower := strings.ToLower(string(content))
 				if !hasPrivacy && (strings.Contains(contentLower, "/privacy") ||
 					strings.Contains(contentLower, "privacy-policy") ||
@@ -361,6 +246,13 @@ func (c LegalPagesCheck) Run(ctx Context) (CheckResult, error) {
 		}
 	}
 
+	// A template nobody filled in tells users less than no policy at all,
+	// so the policy's content is reviewed too.
+	var review privacyReview
+	if privacyContent != "" {
+		review = reviewPrivacyPolicy(privacyContent)
+	}
+
 	if hasPrivacy && hasTerms {
 		msg := "Found"
 		if strings.HasPrefix(privacyPath, "linked in") {
@@ -373,12 +265,58 @@ func (c LegalPagesCheck) Run(ctx Context) (CheckResult, error) {
 		} else {
 			msg += ", terms at " + termsPath
 		}
+
+		// A page nobody can find doesn't inform anyone: the shared layout
+		// or footer has to link to both.
+		links := findLegalLinks(ctx)
+		if !links.checked && review.ok() {
+			return CheckResult{
+				ID:       c.ID(),
+				Title:    c.Title(),
+				Severity: SeverityInfo,
+				Passed:   true,
+				Message:  msg,
+				Details:  []string{"No layout, footer or rendered homepage found to check for links to them"},
+			}, nil
+		}
+		var unlinked []string
+		if links.checked && !links.privacy {
+			unlinked = append(unlinked, "privacy policy")
+		}
+		if links.checked && !links.terms {
+			unlinked = append(unlinked, "terms of service")
+		}
+		if len(unlinked) > 0 {
+			return CheckResult{
+				ID:       c.ID(),
+				Title:    c.Title(),
+				Severity: SeverityWarn,
+				Passed:   false,
+				Message:  "Not linked from the layout or footer: " + strings.Join(unlinked, ", "),
+				Suggestions: append([]string{
+					"Link the privacy policy and terms from the footer every page shares",
+				}, review.suggestions()...),
+				Details: append([]string{msg}, "Looked in "+strings.Join(links.searched, ", ")),
+			}, nil
+		}
+		if !review.ok() {
+			return CheckResult{
+				ID:          c.ID(),
+				Title:       c.Title(),
+				Severity:    SeverityWarn,
+				Passed:      false,
+				Message:     review.message(privacyPath),
+				Suggestions: review.suggestions(),
+				Details:     append([]string{msg}, links.where...),
+			}, nil
+		}
 		return CheckResult{
 			ID:       c.ID(),
 			Title:    c.Title(),
 			Severity: SeverityInfo,
 			Passed:   true,
 			Message:  msg,
+			Details:  links.where,
 		}, nil
 	}
 
@@ -396,13 +334,237 @@ func (c LegalPagesCheck) Run(ctx Context) (CheckResult, error) {
 		Severity: SeverityWarn,
 		Passed:   false,
 		Message:  "Missing: " + strings.Join(missing, ", "),
-		Suggestions: []string{
+		Suggestions: append([]string{
 			"Add a privacy policy page (e.g., /privacy)",
 			"Add terms of service page (e.g., /terms)",
-		},
+		}, review.suggestions()...),
 	}, nil
 }
 
+// noRedirectClient copies client (which already makes the local-vs-safe
+// choice for the configured URLs) so that a 3xx comes back as the
+// response instead of being followed; a redirect can then count as the
+// page existing.
+func noRedirectClient(client *http.Client) *http.Client {
+	c := *client
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &c
+}
+
+// probeLegalPage requests each of paths on baseURL and returns the first
+// that serves a page, or "". A redirect counts only if it stays on the
+// same domain, isn't a login bounce and lands on a URL mentioning one of
+// keywords (not a path-clean or homepage bounce).
+func probeLegalPage(ctx Context, client *http.Client, baseURL string, paths []string, keywords ...string) string {
+	for _, path := range paths {
+		resp, err := getWithContext(ctx.reqContext(), client, baseURL+path)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return path
+		}
+		if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+			loc := resp.Header.Get("Location")
+			if isSameDomainRedirect(baseURL, loc) && !isAuthRedirect(loc) && redirectMentions(loc, keywords...) {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// legalTermsPatterns are the paths and filenames terms of service are
+// usually found at.
+var legalTermsPatterns = []string{
+	"terms", "terms-of-service", "terms_of_service", "tos", "termsofservice",
+	"legal/terms", "legal/terms-of-service", "legal/tos",
+	"pages/terms", "pages/terms-of-service",
+	"policies/terms", "policies/terms-of-service",
+	"legalese/terms", "legalese/terms-of-service",
+	"info/terms", "about/terms",
+	"terms-and-conditions", "terms-conditions", "eula",
+}
+
+// legalPageExtensions are the page and template extensions a legal page
+// file may have ("" for a directory or extensionless file).
+var legalPageExtensions = []string{
+	"", ".html", ".htm", ".php", ".md", ".mdx",
+	".tsx", ".jsx", ".js", ".ts", ".vue", ".svelte",
+	".erb", ".erb.html", ".html.erb",
+	".blade.php", ".twig", ".njk", ".liquid",
+	".astro",
+}
+
+// legalPageDirs are the directories legal pages are looked for in.
+var legalPageDirs = []string{
+	"",
+	"app",
+	"src/app",
+	"src/pages",
+	"pages",
+	"views",
+	"resources/views",
+	"templates",
+	"content",
+	"public",
+	"static",
+	"web",
+	"www",
+	"htdocs",
+	"public_html",
+}
+
+// findLegalPageFile returns the first page file named by one of patterns
+// (privacy, legal/privacy) in legalPageDirs, or "". Under app/ and
+// src/app/ a Next.js <pattern>/page file counts too.
+func findLegalPageFile(rootDir string, patterns []string) string {
+	for _, dir := range legalPageDirs {
+		for _, pattern := range patterns {
+			for _, ext := range legalPageExtensions {
+				if _, err := os.Stat(filepath.Join(rootDir, dir, pattern+ext)); err == nil {
+					return filepath.Join(dir, pattern+ext)
+				}
+				if dir == "app" || dir == "src/app" {
+					if _, err := os.Stat(filepath.Join(rootDir, dir, pattern, "page"+ext)); err == nil {
+						return filepath.Join(dir, pattern, "page"+ext)
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// legalFooterPartials are the footer and partial files that usually hold
+// a site's legal links.
+var legalFooterPartials = []string{
+	"footer.php", "includes/footer.php", "inc/footer.php", "partials/footer.php",
+	"_footer.php", "_includes/footer.php",
+	"footer.html", "includes/footer.html", "_includes/footer.html",
+	"components/Footer.tsx", "components/Footer.jsx", "components/footer.tsx",
+	"src/components/Footer.tsx", "src/components/Footer.jsx",
+	"app/components/Footer.tsx", "app/components/footer.tsx",
+	"templates/_footer.twig", "templates/partials/footer.twig",
+	"templates/_partials/footer.twig", "templates/footer.twig",
+	"resources/views/partials/footer.blade.php",
+	"resources/views/layouts/partials/footer.blade.php",
+	"app/views/layouts/_footer.html.erb", "app/views/shared/_footer.html.erb",
+	"layouts/partials/footer.html",
+	"index.php", "index.html", "public/index.html",
+}
+
+// linkTarget captures where a link points: an href or to attribute (JSX
+// expressions and Vue bindings included), a Rails path helper, a Laravel
+// route() or url() call, or a Django {% url %} tag.
+var linkTarget = regexp.MustCompile(`(?i)(?:\bhref|\bto)\s*=\s*\{?\s*["'\x60]([^"'\x60]*)|\b(\w+)_(?:path|url)\b|\b(?:route|url)\s*\(\s*["']([^"']+)|\{%\s*url\s+["']([^"']+)`)
+
+// linkTargets returns the lowercased targets of the links in content.
+func linkTargets(content string) []string {
+	var targets []string
+	for _, m := range linkTarget.FindAllStringSubmatch(content, -1) {
+		targets = append(targets, strings.ToLower(strings.Join(m[1:], "")))
+	}
+	return targets
+}
+
+// termsTarget matches a link target naming terms of service.
+var termsTarget = regexp.MustCompile(`terms|\btos\b|\beula\b`)
+
+// privacyRoute matches a route path serving the privacy policy.
+var privacyRoute = regexp.MustCompile(`privacy`)
+
+// findLegalRoute returns the first static page route whose path matches
+// target.
+func findLegalRoute(rootDir string, target *regexp.Regexp) (routes.Route, bool) {
+	for _, r := range routes.Extract(rootDir) {
+		if r.Dynamic || r.API || (r.Method != "GET" && r.Method != "ANY") {
+			continue
+		}
+		if target.MatchString(strings.ToLower(r.Path)) {
+			return r, true
+		}
+	}
+	return routes.Route{}, false
+}
+
+// markupSource is one piece of markup every page shares.
+type markupSource struct {
+	name    string
+	content string
+}
+
+// sharedMarkup returns what every page shares, for checks that want a
+// site-wide link: the rendered homepage, the main layout with the
+// templates it includes, and the usual footer partials.
+func sharedMarkup(ctx Context) []markupSource {
+	var sources []markupSource
+	if ctx.PageHTML != "" {
+		sources = append(sources, markupSource{"the homepage", ctx.PageHTML})
+	}
+	seen := map[string]bool{}
+	for _, file := range layoutCandidates(ctx) {
+		path := filepath.Join(ctx.RootDir, file)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if content, err := readWithIncludes(path, ctx.RootDir, ctx.Config.Stack); err == nil {
+			sources = append(sources, markupSource{file, content})
+		}
+	}
+	for _, file := range legalFooterPartials {
+		path := filepath.Join(ctx.RootDir, file)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if content, err := readFile(path); err == nil {
+			sources = append(sources, markupSource{file, string(content)})
+		}
+	}
+	return sources
+}
+
+// legalLinks is what findLegalLinks saw.
+type legalLinks struct {
+	privacy, terms bool
+	// checked is set when at least one layout, partial or rendered page
+	// could be read.
+	checked bool
+	// where names the file (or the homepage) each link was found in.
+	where []string
+	// searched lists what was read.
+	searched []string
+}
+
+// findLegalLinks looks for links to the privacy policy and the terms in
+// the markup every page shares.
+func findLegalLinks(ctx Context) legalLinks {
+	var links legalLinks
+	for _, src := range sharedMarkup(ctx) {
+		if links.privacy && links.terms {
+			break
+		}
+		links.checked = true
+		links.searched = append(links.searched, src.name)
+		for _, target := range linkTargets(src.content) {
+			if !links.privacy && strings.Contains(target, "privacy") {
+				links.privacy = true
+				links.where = append(links.where, "Privacy policy linked from "+src.name)
+			}
+			if !links.terms && termsTarget.MatchString(target) {
+				links.terms = true
+				links.where = append(links.where, "Terms linked from "+src.name)
+			}
+		}
+	}
+	return links
+}
+
 // isSameDomainRedirect checks if a redirect Location stays on the same domain
 func isSameDomainRedirect(baseURL, location string) bool {
 	if location == "" {
@@ -0,0 +1,122 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// privacyElement is something a privacy policy has to cover, with the
+// pattern that shows it does and the suggestion when it doesn't.
+type privacyElement struct {
+	name       string
+	pattern    *regexp.Regexp
+	suggestion string
+}
+
+var privacyElements = []privacyElement{
+	{
+		name:       "contact details",
+		pattern:    regexp.MustCompile(a11yContact.String() + `|(?i)data protection officer|\bDPO\b`),
+		suggestion: "Say how to reach you about privacy: an email address or contact page, and your data protection officer if you have one",
+	},
+	{
+		name:       "the data collected",
+		pattern:    regexp.MustCompile(`(?i)(?:information|data) (?:that |which )?we (?:collect|gather|process|receive)|we (?:may |also )?(?:collect|gather|process)|personal (?:data|information)|categories of (?:personal )?(?:data|information)`),
+		suggestion: "List the personal data you collect and why: account details, analytics, payment and support data",
+	},
+	{
+		name:       "privacy rights",
+		pattern:    regexp.MustCompile(`(?i)\bGDPR\b|\bCCPA\b|\bCPRA\b|general data protection regulation|california consumer privacy|data subject|right to (?:access|erasure|be forgotten|rectification|deletion|delete|object|restrict|data portability|portability|opt[ -]?out|know|correct)|do not sell`),
+		suggestion: "Describe users' rights: access, correction and deletion under GDPR, and opting out of sale or sharing under CCPA",
+	},
+	{
+		name:       "a last-updated date",
+		pattern:    regexp.MustCompile(`(?i)last (?:updated|modified|revised|reviewed|changed)|effective (?:date|as of|from|on)|(?:updated|revised) (?:on|as of)\b`),
+		suggestion: `Add a "Last updated" date, and change it whenever the policy does`,
+	},
+}
+
+// privacyPlaceholderWords are what a policy template leaves in brackets
+// for the site to fill in ("[Company Name]", "[INSERT EMAIL]").
+var privacyPlaceholderWords = []string{
+	"Company", "Business", "Website", "Site", "App", "Product", "Organization", "Organisation",
+	"Legal", "Contact", "Email", "Address", "Phone", "Date", "Name", "URL", "Country", "State", "Jurisdiction",
+}
+
+// rePrivacyPlaceholder matches placeholders left from a policy template.
+// Bracketed placeholders must be capitalized, so code indexing a variable
+// (rows[company]) doesn't count.
+var rePrivacyPlaceholder = func() *regexp.Regexp {
+	var words []string
+	for _, w := range privacyPlaceholderWords {
+		words = append(words, w, strings.ToUpper(w))
+	}
+	return regexp.MustCompile(`\[(?:(?:Your|YOUR|Insert|INSERT|Enter|ENTER)\s+)?(?:` + strings.Join(words, "|") + `)\b[^\]\n]{0,30}\]` +
+		`|(?i)lorem ipsum|company name here|\byour-?(?:company|domain|website)\.com\b`)
+}()
+
+// privacyMinWords is how much text a policy needs before the elements it
+// lacks are reported. A page file holding less probably renders its copy
+// from a CMS or another file, which isn't followed.
+const privacyMinWords = 150
+
+var reMarkupTag = regexp.MustCompile(`<[^>]*>`)
+
+// privacyReview is what reviewPrivacyPolicy found wrong with a policy.
+type privacyReview struct {
+	placeholders []string
+	missing      []privacyElement
+}
+
+func (r privacyReview) ok() bool {
+	return len(r.placeholders) == 0 && len(r.missing) == 0
+}
+
+// message describes the review for the policy at where.
+func (r privacyReview) message(where string) string {
+	if len(r.placeholders) > 0 {
+		return "Privacy policy at " + where + " still has template placeholders"
+	}
+	var names []string
+	for _, e := range r.missing {
+		names = append(names, e.name)
+	}
+	return "Privacy policy at " + where + " doesn't cover: " + strings.Join(names, ", ")
+}
+
+func (r privacyReview) suggestions() []string {
+	var suggestions []string
+	if len(r.placeholders) > 0 {
+		suggestions = append(suggestions, fmt.Sprintf("Replace the template placeholders (%s) with your own details; a policy that was never filled in is worse than none",
+			strings.Join(r.placeholders, ", ")))
+	}
+	for _, e := range r.missing {
+		suggestions = append(suggestions, e.suggestion)
+	}
+	return suggestions
+}
+
+// reviewPrivacyPolicy reads a privacy policy's page or source for left-in
+// template placeholders and for the elements every policy covers. Like
+// the rest of legal_pages it matches English wording.
+func reviewPrivacyPolicy(content string) privacyReview {
+	var r privacyReview
+	seen := map[string]bool{}
+	for _, m := range rePrivacyPlaceholder.FindAllString(content, -1) {
+		quoted := fmt.Sprintf("%q", m)
+		if !seen[strings.ToLower(quoted)] && len(r.placeholders) < 3 {
+			seen[strings.ToLower(quoted)] = true
+			r.placeholders = append(r.placeholders, quoted)
+		}
+	}
+	if len(strings.Fields(reMarkupTag.ReplaceAllString(content, " "))) < privacyMinWords {
+		return r
+	}
+	for _, e := range privacyElements {
+		if !e.pattern.MatchString(content) {
+			r.missing = append(r.missing, e)
+		}
+	}
+	return r
+}
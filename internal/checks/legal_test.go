@@ -0,0 +1,153 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestLegalPagesCheckLinks(t *testing.T) {
+	pages := map[string]string{
+		"app/privacy/page.tsx": "export default function Privacy() {}\n",
+		"app/terms/page.tsx":   "export default function Terms() {}\n",
+	}
+	tests := []struct {
+		name    string
+		files   map[string]string
+		html    string
+		passed  bool
+		message string
+	}{
+		{
+			name: "footer links both",
+			files: map[string]string{
+				"app/layout.tsx":        "import Footer from '@/components/Footer'\n",
+				"components/Footer.tsx": `<Link href="/privacy">Privacy</Link> <Link href={"/terms"}>Terms</Link>`,
+			},
+			passed: true,
+		},
+		{
+			name: "layout without links",
+			files: map[string]string{
+				"app/layout.tsx": "import { privacyBanner } from '@/lib/privacy'\n<a href=\"/about\">About</a>\n",
+			},
+			passed:  false,
+			message: "Not linked from the layout or footer: privacy policy, terms of service",
+		},
+		{
+			name:    "rendered homepage links terms only",
+			files:   map[string]string{"app/layout.tsx": "<body>{children}</body>"},
+			html:    `<footer><a href="https://example.com/legal/terms-of-service">Terms</a></footer>`,
+			passed:  false,
+			message: "Not linked from the layout or footer: privacy policy",
+		},
+		{
+			name:   "nothing to inspect",
+			passed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{}
+			for k, v := range pages {
+				files[k] = v
+			}
+			for k, v := range tt.files {
+				files[k] = v
+			}
+			dir := writeFiles(t, files)
+			result, err := LegalPagesCheck{}.Run(Context{
+				RootDir:  dir,
+				Config:   &config.PreflightConfig{Stack: "next"},
+				PageHTML: tt.html,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tt.passed {
+				t.Fatalf("passed = %v, want %v (%q)", result.Passed, tt.passed, result.Message)
+			}
+			if tt.message != "" && result.Message != tt.message {
+				t.Errorf("message = %q, want %q", result.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestFindLegalLinksTemplateHelpers(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"app/views/layouts/application.html.erb": `<%= link_to "Privacy", privacy_path %> <%= link_to "Photos", photos_path %>`,
+	})
+	links := findLegalLinks(Context{RootDir: dir, Config: &config.PreflightConfig{Stack: "rails"}})
+	if !links.privacy || links.terms {
+		t.Errorf("privacy=%v terms=%v, want only privacy", links.privacy, links.terms)
+	}
+	if !strings.Contains(strings.Join(links.where, "\n"), "app/views/layouts/application.html.erb") {
+		t.Errorf("where = %v", links.where)
+	}
+}
+
+func TestLegalPagesReviewsPrivacyContent(t *testing.T) {
+	filler := strings.Repeat("We use cookies and similar technologies to run the service and keep it secure. ", 12)
+	complete := "# Privacy Policy\n\nLast updated: March 3, 2026\n\n" + filler +
+		"\n\n## Information we collect\n\nWe collect your name, email address and billing details." +
+		"\n\n## Your rights\n\nUnder the GDPR you have the right to access, correct and delete your personal data." +
+		"\n\nContact us at privacy@example.com.\n"
+	tests := []struct {
+		name    string
+		privacy string
+		passed  bool
+		want    []string
+	}{
+		{name: "complete", privacy: complete, passed: true},
+		{
+			name:    "template placeholders",
+			privacy: strings.Replace(complete, "privacy@example.com", "[Company Email]", 1) + "\n[COMPANY NAME] Lorem ipsum dolor sit amet.\n",
+			want:    []string{"still has template placeholders", `"[Company Email]"`, `"[COMPANY NAME]"`, `"Lorem ipsum"`},
+		},
+		{
+			name:    "missing elements",
+			privacy: "# Privacy Policy\n\n" + filler + "\n\nWe collect your email address.\n",
+			want:    []string{"doesn't cover: contact details, privacy rights, a last-updated date"},
+		},
+		{name: "too short to judge", privacy: "<PrivacyContent slug=\"privacy\" />\n", passed: true},
+		{name: "code indexing", privacy: "const label = labels[company]\n" + "<PolicyBody />\n", passed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{
+				"content/privacy.md":    tt.privacy,
+				"content/terms.md":      "# Terms\n",
+				"components/Footer.tsx": `<a href="/privacy">Privacy</a> <a href="/terms">Terms</a>`,
+			})
+			result, err := LegalPagesCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tt.passed {
+				t.Fatalf("passed = %v, want %v (%q %v)", result.Passed, tt.passed, result.Message, result.Suggestions)
+			}
+			joined := result.Message + "\n" + strings.Join(result.Suggestions, "\n")
+			for _, w := range tt.want {
+				if !strings.Contains(joined, w) {
+					t.Errorf("missing %q in %s", w, joined)
+				}
+			}
+		})
+	}
+}
+
+func TestLegalPagesFindsRoutes(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config/routes.rb": "Rails.application.routes.draw do\n  get \"/privacy\", to: \"pages#privacy\"\n  get \"/terms\", to: \"pages#terms\"\nend\n",
+	})
+	result, err := LegalPagesCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{Stack: "rails"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Found privacy at /privacy (route in config/routes.rb:2), terms at /terms (route in config/routes.rb:3)"
+	if !result.Passed || result.Message != want {
+		t.Errorf("passed=%v message=%q, want %q", result.Passed, result.Message, want)
+	}
+}
@@ -0,0 +1,177 @@
+package checks
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// LocalhostURLsCheck finds URLs pointing at a developer's machine that
+// were left in source and config: http://localhost and 127.0.0.1, local
+// TLDs and private addresses, and URLs on dev-server ports like :3000
+// and :8000. They work on the laptop they were written on and break in
+// production, most often in a fetch call or an OAuth callback URL.
+type LocalhostURLsCheck struct{}
+
+func (c LocalhostURLsCheck) ID() string {
+	return "localhost_urls"
+}
+
+func (c LocalhostURLsCheck) Title() string {
+	return "Hardcoded localhost URLs"
+}
+
+var (
+	reHardcodedURL = regexp.MustCompile(`\b(?:https?|wss?)://[^\s"'` + "`" + `<>)\]},;]+`)
+	// reEnvFallback matches an environment read the URL is only the
+	// fallback for (process.env.API_URL || "http://localhost:3000").
+	reEnvFallback = regexp.MustCompile(`process\.env\.\w+|ENV(?:\.fetch)?\s*[\[(]|getenv\(|os\.environ|\benv\(|import\.meta\.env|System\.getenv|Environment\.GetEnvironmentVariable`)
+	// reLogCall matches a line that logs the URL rather than requests it
+	// ("Listening on http://localhost:3000").
+	reLogCall = regexp.MustCompile(`(?i)\b(?:console\.\w+|log(?:ger)?\.\w+|puts|print(?:ln|f)?|echo|fmt\.Print\w*|Log\.\w+)\s*\(?`)
+	// reDevConfigName matches files meant for development, by a dev,
+	// local or test segment in the name (settings_local.py,
+	// development.rb, appsettings.Development.json).
+	reDevConfigName = regexp.MustCompile(`(?i)(?:^|[_.-])(?:dev|development|local|test|testing|ci|example|sample)(?:[_.-]|$)`)
+)
+
+// localhostURLExts are the config formats read along with app source and
+// templates.
+var localhostURLExts = map[string]bool{".json": true, ".yml": true, ".yaml": true, ".toml": true, ".xml": true, ".ini": true}
+
+// localhostSkipFiles are dev tooling configs, where localhost is the
+// point: dev-server proxies, test runners and lockfiles.
+var localhostSkipFiles = []string{
+	"vite.config", "webpack.config", "playwright.config", "cypress.config", "jest.config",
+	"vitest.config", "karma.conf", "nodemon.json", "package.json", "package-lock.json",
+	"composer.json", "composer.lock", "tsconfig", "launch.json", ".eslintrc",
+}
+
+// devServerPorts are the default ports of local dev servers (Rails, Next,
+// Vite, Django, Flask, Angular, Laravel's artisan serve and others).
+var devServerPorts = map[string]bool{
+	"3000": true, "3001": true, "4000": true, "4200": true, "5000": true, "5173": true,
+	"8000": true, "8080": true, "8888": true, "9000": true,
+}
+
+func (c LocalhostURLsCheck) Run(ctx Context) (CheckResult, error) {
+	files := ctx.files()
+	var findings []Location
+	for _, f := range files.Files {
+		if f.Ignored || f.Size > 500*1024 || f.inDir(templateSkipDirs) || isTestSource(f.Path) {
+			continue
+		}
+		// .env.production is deployed config; every other .env file,
+		// hidden directory and dotfile is local or CI tooling.
+		production := f.Name == ".env.production" || f.Name == ".env.prod"
+		if !production && (strings.HasPrefix(f.Path, ".") || strings.Contains(f.Path, "/.")) {
+			continue
+		}
+		if !production && !appSourceExts[f.Ext] && !localhostURLExts[f.Ext] && !hasExtension(f.Name, templateExts) {
+			continue
+		}
+		if !production && reDevConfigName.MatchString(strings.TrimSuffix(f.Name, filepath.Ext(f.Name))+".") {
+			continue
+		}
+		// Compose healthchecks request localhost inside the container.
+		if reComposeFile.MatchString(f.Name) || localhostSkipFile(f.Name) || ignoredByConfig(ctx, f.Path) {
+			continue
+		}
+		content, err := readFile(files.Abs(f))
+		if err != nil {
+			continue
+		}
+		lines := strings.Split(string(content), "\n")
+		for i, line := range lines {
+			if reCommentLine.MatchString(line) {
+				continue
+			}
+			line = stripCodeComments(line)
+			for _, m := range reHardcodedURL.FindAllStringIndex(line, -1) {
+				raw := line[m[0]:m[1]]
+				if !isDevServerURL(raw) {
+					continue
+				}
+				before := line[:m[0]]
+				if reEnvFallback.MatchString(before) || reLogCall.MatchString(before) || isDevGuarded(lines, i) {
+					continue
+				}
+				findings = append(findings, Location{File: f.Path, Line: i + 1, Message: raw})
+				break
+			}
+		}
+	}
+
+	if len(findings) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No hardcoded localhost URLs found",
+		}, nil
+	}
+
+	var listed []string
+	for _, loc := range findings {
+		listed = append(listed, loc.String())
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  fmt.Sprintf("Found %d hardcoded localhost URL(s)", len(findings)),
+		Suggestions: append([]string{
+			"Read the URL from the environment (process.env.API_URL, ENV.fetch(\"APP_URL\")) or use a relative path",
+			"Register production OAuth callback URLs with the provider and set them per environment",
+		}, limitFindings(listed, 8)...),
+		Locations: findings,
+	}, nil
+}
+
+// isDevServerURL reports whether raw points at a developer's machine: a
+// loopback or local-TLD host, a private address, or a dev-server port.
+func isDevServerURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	if IsLocalURL("http://" + u.Host) {
+		return true
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && ip.IsPrivate() {
+		return true
+	}
+	// A template placeholder (http://{{host}}:3000) is set per environment.
+	return devServerPorts[u.Port()] && !strings.ContainsAny(u.Host, "{$%")
+}
+
+func localhostSkipFile(name string) bool {
+	lower := strings.ToLower(name)
+	for _, skip := range localhostSkipFiles {
+		if strings.HasPrefix(lower, skip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredByConfig reports whether the top-level ignore globs in
+// preflight.yml cover the project-relative path p.
+func ignoredByConfig(ctx Context, p string) bool {
+	if ctx.Config == nil {
+		return false
+	}
+	for _, g := range ctx.Config.Ignore {
+		if ok, _ := doublestar.Match(filepath.ToSlash(g), p); ok {
+			return true
+		}
+	}
+	return false
+}
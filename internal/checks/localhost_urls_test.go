@@ -0,0 +1,99 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestLocalhostURLsCheck(t *testing.T) {
+	files := map[string]string{
+		"src/api.ts": "const base = process.env.API_URL || 'http://localhost:4000'\n" +
+			"// fetch('http://localhost:3000/old')\n" +
+			"export const load = () => fetch('http://localhost:3000/api/items')\n" +
+			"app.listen(3000, () => console.log('Listening on http://localhost:3000'))\n" +
+			"const docs = 'https://example.com/docs'\n",
+		"config/initializers/omniauth.rb":    "provider :github, redirect_uri: \"http://127.0.0.1:3000/auth/github/callback\"\n",
+		"config/environments/development.rb": "config.action_mailer.default_url_options = { host: \"http://localhost:3000\" }\n",
+		"config/app.yml":                     "api: http://192.168.1.20/v1\nsite: https://acme.com\n",
+		".env.production":                    "APP_URL=http://acme.test:8000\n",
+		".env":                               "APP_URL=http://localhost:8000\n",
+		".env.example":                       "APP_URL=http://localhost:8000\n",
+		"tests/api.test.ts":                  "fetch('http://localhost:3000/api')\n",
+		"vite.config.ts":                     "export default { server: { proxy: { '/api': 'http://localhost:8000' } } }\n",
+		"docker-compose.yml":                 "services:\n  web:\n    healthcheck:\n      test: curl -f http://localhost:3000/up\n",
+		"package.json":                       `{"proxy": "http://localhost:5000"}`,
+	}
+
+	cases := []struct {
+		name   string
+		ignore []string
+		want   []string
+	}{
+		{
+			// Env fallbacks, log lines, comments, dev configs, tests,
+			// dev tooling and compose healthchecks aren't reported.
+			name: "hardcoded URLs",
+			want: []string{
+				".env.production:1 - http://acme.test:8000",
+				"config/app.yml:1 - http://192.168.1.20/v1",
+				"config/initializers/omniauth.rb:1 - http://127.0.0.1:3000/auth/github/callback",
+				"src/api.ts:3 - http://localhost:3000/api/items",
+			},
+		},
+		{
+			name:   "ignored paths",
+			ignore: []string{"src/**", "config/**", ".env.production"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := LocalhostURLsCheck{}.Run(Context{RootDir: writeFiles(t, files), Config: &config.PreflightConfig{Ignore: tc.ignore}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tc.want) == 0 {
+				if !result.Passed {
+					t.Errorf("expected pass, got %q %v", result.Message, result.Suggestions)
+				}
+				return
+			}
+			if message := fmt.Sprintf("Found %d hardcoded localhost URL(s)", len(tc.want)); result.Passed || result.Message != message {
+				t.Fatalf("got %v %q, want %q: %v", result.Passed, result.Message, message, result.Suggestions)
+			}
+			var got []string
+			for _, loc := range result.Locations {
+				got = append(got, loc.String())
+			}
+			joined := strings.Join(got, "\n")
+			for _, want := range tc.want {
+				if !strings.Contains(joined, want) {
+					t.Errorf("locations missing %q:\n%s", want, joined)
+				}
+			}
+		})
+	}
+}
+
+func TestIsDevServerURL(t *testing.T) {
+	tests := map[string]bool{
+		"http://localhost":              true,
+		"http://[::1]:8080/":            true,
+		"ws://0.0.0.0:5173":             true,
+		"https://app.local/callback":    true,
+		"http://10.0.0.5/api":           true,
+		"https://api.example.com:8080/": true,
+		"https://api.example.com/":      false,
+		"https://example.com:443/":      false,
+		"http://{{host}}:3000/":         false,
+		"http://localhost.acme.com/":    false,
+	}
+	for raw, want := range tests {
+		if got := isDevServerURL(raw); got != want {
+			t.Errorf("isDevServerURL(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
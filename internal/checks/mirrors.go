@@ -0,0 +1,256 @@
+package checks
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// MirrorsCheck compares the places a site is served from besides
+// production (a docs mirror, regional domains, an .onion address) with
+// production itself. Mirrors that drift apart split search ranking
+// between copies, or keep serving last month's pricing.
+type MirrorsCheck struct{}
+
+func (c MirrorsCheck) ID() string {
+	return "mirrors"
+}
+
+func (c MirrorsCheck) Title() string {
+	return "Mirror consistency"
+}
+
+// mirrorFetch is one page fetched from production or a mirror.
+type mirrorFetch struct {
+	url    string
+	status int
+	body   string
+	err    error
+}
+
+func fetchMirrorPage(ctx Context, client *http.Client, pageURL string) mirrorFetch {
+	fetch := mirrorFetch{url: pageURL}
+	resp, err := doGet(ctx.reqContext(), client, pageURL)
+	if err != nil {
+		fetch.err = err
+		return fetch
+	}
+	defer resp.Body.Close()
+	fetch.status = resp.StatusCode
+	body, err := io.ReadAll(io.LimitReader(resp.Body, netutil.MaxResponseBody))
+	if err != nil {
+		fetch.err = err
+		return fetch
+	}
+	fetch.body = string(body)
+	return fetch
+}
+
+// Run fetches robots.txt and each page from production and every mirror,
+// and reports mirrors whose robots.txt differs, whose pages lack a
+// canonical tag pointing at production, and whose pages' text differs
+// from production's.
+func (c MirrorsCheck) Run(ctx Context) (CheckResult, error) {
+	cfg := ctx.Config.Checks.Mirrors
+	primary := strings.TrimSuffix(ctx.Config.URLs.Production, "/")
+	primaryURL, err := url.Parse(primary)
+	if cfg == nil || len(cfg.URLs) == 0 || primary == "" || err != nil || ctx.Client == nil {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Skipped (no production URL or mirrors)",
+		}, nil
+	}
+	primaryHost := strings.ToLower(primaryURL.Hostname())
+
+	pages := []string{"/"}
+	for _, p := range cfg.Pages {
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		if !slices.Contains(pages, p) {
+			pages = append(pages, p)
+		}
+	}
+	primaryRobots := fetchMirrorPage(ctx, ctx.Client, primary+"/robots.txt")
+	primaryPages := make([]mirrorFetch, len(pages))
+	for i, p := range pages {
+		primaryPages[i] = fetchMirrorPage(ctx, ctx.Client, primary+p)
+	}
+
+	var problems, details []string
+	checked := 0
+	for _, mirror := range cfg.URLs {
+		mirror = strings.TrimSuffix(mirror, "/")
+		mirrorURL, err := url.Parse(mirror)
+		if err != nil || mirrorURL.Hostname() == "" {
+			problems = append(problems, fmt.Sprintf("%s isn't a valid URL", mirror))
+			continue
+		}
+		client := ctx.Client
+		if strings.HasSuffix(strings.ToLower(mirrorURL.Hostname()), ".onion") {
+			if cfg.TorProxy == "" {
+				details = append(details, mirror+" - skipped: set checks.mirrors.torProxy to fetch .onion mirrors through Tor")
+				continue
+			}
+			client = torClient(cfg.TorProxy)
+		}
+		checked++
+
+		robots := fetchMirrorPage(ctx, client, mirror+"/robots.txt")
+		if primaryRobots.err == nil && robots.err == nil && primaryRobots.status == http.StatusOK {
+			switch {
+			case robots.status != http.StatusOK:
+				problems = append(problems, fmt.Sprintf("%s/robots.txt returns HTTP %d; production serves one", mirror, robots.status))
+			case robotsRules(robots.body) != robotsRules(primaryRobots.body):
+				problems = append(problems, fmt.Sprintf("%s/robots.txt has different rules from production's", mirror))
+			}
+		}
+
+		for i, p := range pages {
+			page := fetchMirrorPage(ctx, client, mirror+p)
+			if page.err != nil {
+				problems = append(problems, fmt.Sprintf("%s%s is unreachable: %v", mirror, p, page.err))
+				continue
+			}
+			if page.status != http.StatusOK {
+				problems = append(problems, fmt.Sprintf("%s%s returns HTTP %d", mirror, p, page.status))
+				continue
+			}
+			problems = append(problems, mirrorCanonicalProblems(page, primaryHost)...)
+			theirs := primaryPages[i]
+			if theirs.err == nil && theirs.status == http.StatusOK {
+				if pageTextHash(page.body) != pageTextHash(theirs.body) {
+					problems = append(problems, fmt.Sprintf("%s%s has different content from %s%s", mirror, p, primary, p))
+				} else {
+					details = append(details, fmt.Sprintf("%s%s - matches production", mirror, p))
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return CheckResult{
+			ID:          c.ID(),
+			Title:       c.Title(),
+			Severity:    SeverityWarn,
+			Passed:      false,
+			Message:     fmt.Sprintf("%d mirror problem(s)", len(problems)),
+			Suggestions: append(limitFindings(problems, 10), "Deploy mirrors from the same build as production, with canonical tags pointing at "+primaryHost),
+			Details:     details,
+		}, nil
+	}
+	if checked == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No mirrors could be checked",
+			Details:  details,
+		}, nil
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   true,
+		Message:  fmt.Sprintf("%d mirror(s) match production on %d page(s)", checked, len(pages)),
+		Details:  details,
+	}, nil
+}
+
+// mirrorCanonicalProblems checks that a mirror page names a production
+// URL as canonical, so search engines rank production rather than
+// splitting the page's ranking between copies.
+func mirrorCanonicalProblems(page mirrorFetch, primaryHost string) []string {
+	hrefs := parseRenderedHTML(page.body).linkRels["canonical"]
+	if len(hrefs) == 0 {
+		return []string{fmt.Sprintf("%s has no canonical tag pointing at %s", page.url, primaryHost)}
+	}
+	base, _ := url.Parse(page.url)
+	canonical, err := base.Parse(strings.TrimSpace(hrefs[0]))
+	if err != nil {
+		return []string{fmt.Sprintf("%s has an invalid canonical URL %q", page.url, hrefs[0])}
+	}
+	if !strings.EqualFold(canonical.Hostname(), primaryHost) {
+		return []string{fmt.Sprintf("%s has canonical %s, not a %s URL", page.url, canonical, primaryHost)}
+	}
+	return nil
+}
+
+// robotsRules is robots.txt reduced to its rules: comments, blank lines
+// and the Sitemap and Host lines, which name each mirror's own host, are
+// dropped, and fields are lowercased.
+func robotsRules(body string) string {
+	var rules []string
+	for _, line := range strings.Split(body, "\n") {
+		if hash := strings.IndexByte(line, '#'); hash >= 0 {
+			line = line[:hash]
+		}
+		field, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "sitemap" || field == "host" {
+			continue
+		}
+		rules = append(rules, field+":"+strings.TrimSpace(value))
+	}
+	return strings.Join(rules, "\n")
+}
+
+// pageTextHash hashes a page's visible text with whitespace collapsed, so
+// markup differences between mirrors (asset hosts, nonces, build IDs)
+// don't count as different content.
+func pageTextHash(doc string) string {
+	var words []string
+	z := html.NewTokenizer(strings.NewReader(doc))
+	skip := 0
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(words, " "))))
+		case html.StartTagToken:
+			switch name, _ := z.TagName(); string(name) {
+			case "script", "style", "noscript", "template":
+				skip++
+			}
+		case html.EndTagToken:
+			switch name, _ := z.TagName(); string(name) {
+			case "script", "style", "noscript", "template":
+				if skip > 0 {
+					skip--
+				}
+			}
+		case html.TextToken:
+			if skip == 0 {
+				words = append(words, strings.Fields(string(z.Text()))...)
+			}
+		}
+	}
+}
+
+// torClient fetches through the Tor SOCKS5 proxy at addr, which resolves
+// .onion names itself.
+func torClient(addr string) *http.Client {
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			Proxy:             http.ProxyURL(&url.URL{Scheme: "socks5", Host: addr}),
+			DisableKeepAlives: true,
+		},
+	}
+}
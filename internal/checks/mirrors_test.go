@@ -0,0 +1,66 @@
+package checks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestMirrorsCheck(t *testing.T) {
+	site := func(robots, page string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/robots.txt":
+				fmt.Fprint(w, robots)
+			case "/":
+				fmt.Fprint(w, page)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+	}
+	primary := site("User-agent: *\nDisallow: /admin\nSitemap: https://example.com/sitemap.xml\n",
+		`<html><head><link rel="canonical" href="https://example.com/"><script src="/app.js?v=1"></script></head><body><h1>Pricing</h1> <p>From $10</p></body></html>`)
+	defer primary.Close()
+
+	run := func(t *testing.T, mirror *httptest.Server) CheckResult {
+		t.Helper()
+		cfg := &config.PreflightConfig{}
+		cfg.URLs.Production = primary.URL
+		cfg.Checks.Mirrors = &config.MirrorsConfig{URLs: []string{mirror.URL, "http://examplemirror.onion"}}
+		result, err := MirrorsCheck{}.Run(Context{Config: cfg, Client: primary.Client()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	t.Run("consistent", func(t *testing.T) {
+		mirror := site("# mirror\nUser-agent: *\nDisallow: /admin\nSitemap: https://mirror.example.com/sitemap.xml\n",
+			`<html><head><link rel="canonical" href="`+primary.URL+`/"><script src="https://cdn.example.com/app.js?v=2"></script></head><body><h1>Pricing</h1><p>From   $10</p></body></html>`)
+		defer mirror.Close()
+		result := run(t, mirror)
+		if !result.Passed || result.Message != "1 mirror(s) match production on 1 page(s)" {
+			t.Errorf("got passed=%v message=%q suggestions=%q", result.Passed, result.Message, result.Suggestions)
+		}
+	})
+
+	t.Run("drifted", func(t *testing.T) {
+		mirror := site("User-agent: *\nDisallow: /\n",
+			`<html><head></head><body><h1>Pricing</h1><p>From $8</p></body></html>`)
+		defer mirror.Close()
+		result := run(t, mirror)
+		want := []string{
+			mirror.URL + "/robots.txt has different rules from production's",
+			mirror.URL + "/ has no canonical tag pointing at 127.0.0.1",
+			mirror.URL + "/ has different content from " + primary.URL + "/",
+		}
+		if result.Passed || !reflect.DeepEqual(result.Suggestions[:len(want)], want) {
+			t.Errorf("got passed=%v suggestions=%q", result.Passed, result.Suggestions)
+		}
+	})
+}
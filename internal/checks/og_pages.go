@@ -0,0 +1,143 @@
+package checks
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ogSampleSections bounds how many sitemap sections (blog, products,
+// docs...) contribute a page to the per-page OG sample.
+const ogSampleSections = 4
+
+const sharedOGMetaSuggestion = "Set og:title and og:description per page from its own title and summary, not just in the layout"
+
+// ogSamplePage is one page of the sample and the OG text it set.
+type ogSamplePage struct {
+	url         string
+	title       string
+	description string
+}
+
+// sharedOGMeta samples the homepage and a page from each of the largest
+// sections of the live sitemap, and reports og:title and og:description
+// values every sampled page shares. Identical text on a blog post, a
+// product page and the homepage means only the layout's defaults were
+// ever set, so every shared link previews the same. Sampled pages are
+// returned as details.
+func sharedOGMeta(ctx Context) (problems, details []string) {
+	base := ctx.Config.URLs.Production
+	home := ctx.PageHTMLProduction
+	if base == "" {
+		base, home = ctx.Config.URLs.Staging, ctx.PageHTMLStaging
+	}
+	if base == "" || ctx.Client == nil {
+		return nil, nil
+	}
+	if !strings.HasPrefix(base, "http://") && !strings.HasPrefix(base, "https://") {
+		base = "https://" + base
+	}
+	base = strings.TrimSuffix(base, "/")
+
+	sitemapURL, data, _ := findLiveSitemap(ctx, base)
+	if sitemapURL == "" {
+		return nil, nil
+	}
+	doc, _, err := parseSitemap(data)
+	if err != nil {
+		return nil, nil
+	}
+	locs, _, _ := sitemapLocs(ctx, doc)
+	sample := sampleSectionPages(locs, ogSampleSections)
+	if len(sample) < 2 {
+		return nil, nil
+	}
+
+	if home == "" {
+		home = FetchPageHTML(ctx.reqContext(), ctx.Client, base+"/")
+	}
+	var pages []ogSamplePage
+	for i, pageURL := range append([]string{base + "/"}, sample...) {
+		page := home
+		if i > 0 {
+			page = FetchPageHTML(ctx.reqContext(), ctx.Client, pageURL)
+		}
+		if page == "" {
+			continue
+		}
+		rendered := parseRenderedHTML(page)
+		pages = append(pages, ogSamplePage{
+			url:         pageURL,
+			title:       strings.TrimSpace(rendered.metaProperty["og:title"]),
+			description: strings.TrimSpace(rendered.metaProperty["og:description"]),
+		})
+	}
+	// The homepage and at least two pages of other types.
+	if len(pages) < 3 {
+		return nil, nil
+	}
+	for _, p := range pages {
+		details = append(details, fmt.Sprintf("Sampled %s: og:title %q", p.url, truncate(p.title, 60)))
+	}
+
+	for _, field := range []struct {
+		name  string
+		value func(ogSamplePage) string
+	}{
+		{"og:title", func(p ogSamplePage) string { return p.title }},
+		{"og:description", func(p ogSamplePage) string { return p.description }},
+	} {
+		first := field.value(pages[0])
+		shared := first != ""
+		for _, p := range pages[1:] {
+			if field.value(p) != first {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			problems = append(problems, fmt.Sprintf("%s is %q on all %d sampled pages", field.name, truncate(first, 60), len(pages)))
+		}
+	}
+	return problems, details
+}
+
+// sampleSectionPages picks one URL from each of the n largest sections
+// of the site, a section being the first path segment (/blog, /products).
+// The largest sections are the templated ones (posts, products, docs)
+// where per-page metadata gets forgotten. Within a section, a page below
+// its root (/blog/some-post) is preferred to the root, an index page
+// more likely to have been given its own metadata by hand.
+func sampleSectionPages(locs []string, n int) []string {
+	var order []string
+	counts := map[string]int{}
+	picked := map[string]string{}
+	for _, loc := range locs {
+		u, err := url.Parse(loc)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if segments[0] == "" {
+			continue // the homepage is always sampled
+		}
+		section := segments[0]
+		if counts[section] == 0 {
+			order = append(order, section)
+			picked[section] = loc
+		} else if len(segments) > 1 && !strings.Contains(strings.Trim(urlPath(picked[section]), "/"), "/") {
+			picked[section] = loc
+		}
+		counts[section]++
+	}
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	if len(order) > n {
+		order = order[:n]
+	}
+	sample := make([]string, 0, len(order))
+	for _, section := range order {
+		sample = append(sample, picked[section])
+	}
+	return sample
+}
@@ -49,7 +49,7 @@ func (c OGTwitterCheck) Run(ctx Context) (CheckResult, error) {
 	if cfg != nil {
 		configuredLayout = cfg.MainLayout
 	}
-	layoutFile := getLayoutFile(ctx.RootDir, ctx.Config.Stack, configuredLayout)
+	layoutFile := getLayoutFile(ctx.RootDir, ctx.Config.AllStacks(), configuredLayout)
 
 	if layoutFile == "" {
 		return CheckResult{
@@ -62,7 +62,7 @@ func (c OGTwitterCheck) Run(ctx Context) (CheckResult, error) {
 	}
 
 	layoutPath := filepath.Join(ctx.RootDir, layoutFile)
-	content, err := os.ReadFile(layoutPath)
+	content, err := readFile(layoutPath)
 	if err != nil {
 		return CheckResult{
 			ID:       c.ID(),
@@ -83,8 +83,9 @@ func (c OGTwitterCheck) Run(ctx Context) (CheckResult, error) {
 		generateMetadataPattern := regexp.MustCompile(`(?s)export\s+(async\s+)?function\s+generateMetadata`)
 		metadataExportPattern := regexp.MustCompile(`(?s)export\s+(const|let|var)\s+metadata\s*[=:]`)
 
-		_ = filepath.Walk(appDir, func(path string, info os.FileInfo, err error) error {
+		_ = walk(appDir, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
+				recordUnreadable(path, err)
 				if info != nil && info.IsDir() {
 					return filepath.SkipDir
 				}
@@ -105,7 +106,7 @@ func (c OGTwitterCheck) Run(ctx Context) (CheckResult, error) {
 				!strings.HasSuffix(nameLower, ".jsx") && !strings.HasSuffix(nameLower, ".js") {
 				return nil
 			}
-			fileContent, err := os.ReadFile(path)
+			fileContent, err := readFile(path)
 			if err != nil {
 				return nil
 			}
@@ -116,12 +117,27 @@ func (c OGTwitterCheck) Run(ctx Context) (CheckResult, error) {
 		})
 
 		if hasMetadataInApp {
+			// A root layout's metadata export is the classic case of
+			// every page sharing one set of OG tags.
+			shared, sampled := sharedOGMeta(ctx)
+			if len(shared) > 0 {
+				return CheckResult{
+					ID:          c.ID(),
+					Title:       c.Title(),
+					Severity:    SeverityWarn,
+					Passed:      false,
+					Message:     "OG metadata configured via Next.js Metadata API, but " + strings.Join(shared, "; "),
+					Suggestions: []string{sharedOGMetaSuggestion},
+					Details:     sampled,
+				}, nil
+			}
 			return CheckResult{
 				ID:       c.ID(),
 				Title:    c.Title(),
 				Severity: SeverityInfo,
 				Passed:   true,
 				Message:  "OG and Twitter metadata configured via Next.js Metadata API",
+				Details:  sampled,
 			}, nil
 		}
 	}
@@ -263,8 +279,9 @@ func (c OGTwitterCheck) Run(ctx Context) (CheckResult, error) {
 		if _, err := os.Stat(dirPath); err != nil {
 			continue
 		}
-		_ = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		_ = walk(dirPath, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
+				recordUnreadable(path, err)
 				if info != nil && info.IsDir() {
 					return filepath.SkipDir
 				}
@@ -382,8 +399,12 @@ func (c OGTwitterCheck) Run(ctx Context) (CheckResult, error) {
 		}
 	}
 
+	// Per-page overrides, judged on the live site
+	shared, sampled := sharedOGMeta(ctx)
+	details = append(details, sampled...)
+
 	// Build result
-	if len(missing) == 0 && len(dimensionWarnings) == 0 {
+	if len(missing) == 0 && len(dimensionWarnings) == 0 && len(shared) == 0 {
 		msg := "OG and Twitter card metadata configured"
 		if perEnvSummary != "" {
 			msg = perEnvSummary
@@ -407,6 +428,7 @@ func (c OGTwitterCheck) Run(ctx Context) (CheckResult, error) {
 	if len(dimensionWarnings) > 0 {
 		messages = append(messages, dimensionWarnings...)
 	}
+	messages = append(messages, shared...)
 
 	severity := SeverityWarn
 	suggestions := []string{}
@@ -419,6 +441,9 @@ func (c OGTwitterCheck) Run(ctx Context) (CheckResult, error) {
 	if len(dimensionWarnings) > 0 {
 		suggestions = append(suggestions, fmt.Sprintf("Use %dx%d for OG images, %dx%d for Twitter", ogRecommendedWidth, ogRecommendedHeight, twitterRecommendedWidth, twitterRecommendedHeight))
 	}
+	if len(shared) > 0 {
+		suggestions = append(suggestions, sharedOGMetaSuggestion)
+	}
 
 	return CheckResult{
 		ID:          c.ID(),
@@ -625,7 +650,7 @@ func fetchImageDimensions(ctx Context, url string) (width, height int, err error
 
 // getLocalImageDimensions reads a local image file and returns its dimensions
 func getLocalImageDimensions(path string) (width, height int, err error) {
-	f, err := os.Open(path)
+	f, err := openFile(path)
 	if err != nil {
 		return 0, 0, fmt.Errorf("open %s: %w", path, err)
 	}
@@ -6,7 +6,11 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
 )
 
 // WebP is the reason golang.org/x/image is a dependency, and it is decoded
@@ -157,3 +161,67 @@ func TestResolveImageURLKeepsAbsoluteAfterStrip(t *testing.T) {
 		t.Errorf("resolveImageURL = %q, want %q (absolute URL must not be rebased)", got, want)
 	}
 }
+
+func TestSharedOGMeta(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		perPage  bool
+		problems int
+	}{
+		{name: "layout defaults only", problems: 2},
+		{name: "per-page overrides", perPage: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var srv *httptest.Server
+			srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/sitemap.xml" {
+					w.Header().Set("Content-Type", "application/xml")
+					w.Write([]byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` +
+						`<url><loc>` + srv.URL + `/</loc></url>` +
+						`<url><loc>` + srv.URL + `/blog</loc></url>` +
+						`<url><loc>` + srv.URL + `/blog/launch</loc></url>` +
+						`<url><loc>` + srv.URL + `/products/widget</loc></url>` +
+						`</urlset>`))
+					return
+				}
+				title, desc := "Acme", "Widgets for everyone"
+				if tc.perPage {
+					title, desc = "Acme "+r.URL.Path, "About "+r.URL.Path
+				}
+				w.Write([]byte(`<html><head><meta property="og:title" content="` + title + `">` +
+					`<meta property="og:description" content="` + desc + `"></head></html>`))
+			}))
+			defer srv.Close()
+
+			cfg := &config.PreflightConfig{}
+			cfg.URLs.Production = srv.URL
+			problems, details := sharedOGMeta(Context{Config: cfg, Client: srv.Client()})
+			if len(problems) != tc.problems {
+				t.Fatalf("problems = %q, want %d", problems, tc.problems)
+			}
+			if tc.problems > 0 && !strings.Contains(problems[0], `og:title is "Acme" on all 3 sampled pages`) {
+				t.Errorf("problems[0] = %q", problems[0])
+			}
+			if len(details) != 3 || !strings.Contains(strings.Join(details, "\n"), "/blog/launch") {
+				t.Errorf("details = %q, want the homepage, /blog/launch and /products/widget", details)
+			}
+		})
+	}
+}
+
+func TestSampleSectionPages(t *testing.T) {
+	locs := []string{
+		"https://example.com/",
+		"https://example.com/about",
+		"https://example.com/blog",
+		"https://example.com/blog/one",
+		"https://example.com/blog/two",
+		"https://example.com/docs/install",
+		"https://example.com/docs/config",
+	}
+	got := sampleSectionPages(locs, 2)
+	want := []string{"https://example.com/blog/one", "https://example.com/docs/install"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sampleSectionPages = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+package checks
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// OpsReadinessCheck looks for what the team will reach for when
+// production breaks after launch: a runbook or incident-response doc, a
+// reference to the on-call schedule or paging service, and rollback
+// steps in the deploy docs. None of it shows up in the site, so nothing
+// else in the scan notices it's missing.
+type OpsReadinessCheck struct{}
+
+func (c OpsReadinessCheck) ID() string {
+	return "ops_readiness"
+}
+
+func (c OpsReadinessCheck) Title() string {
+	return "Operational readiness"
+}
+
+var (
+	// reRunbookPath matches the path of a runbook or incident-response doc.
+	reRunbookPath = regexp.MustCompile(`(?i)runbook|playbook|incident|on-?call|postmortem`)
+	// reDeployDocPath matches the path of a deploy or release doc.
+	reDeployDocPath = regexp.MustCompile(`(?i)deploy|releas|rollout|shipping`)
+	// reDeploySection matches a deploy heading in a README.
+	reDeploySection = regexp.MustCompile(`(?im)^#{1,4}\s.*\bdeploy`)
+	// reRollback matches instructions for undoing a deploy.
+	reRollback = regexp.MustCompile(`(?i)\broll(?:ing)?[- ]?back\b|\brevert(?:ing)? (?:the |a )?(?:deploy|release)|\b(?:redeploy|promote) (?:the )?previous\b|\bprevious (?:release|deploy(?:ment)?)\b`)
+	// reOnCall matches a paging service or an on-call schedule.
+	reOnCall = regexp.MustCompile(`(?i)pagerduty|opsgenie|incident\.io|rootly\.com|firehydrant|squadcast|victorops|splunk on-?call|grafana on-?call|on-?call (?:schedule|rotation|roster)`)
+)
+
+// opsDocExts are the documentation formats searched.
+var opsDocExts = map[string]bool{".md": true, ".mdx": true, ".markdown": true, ".txt": true, ".rst": true, ".adoc": true}
+
+// opsConfigExts are the config and infrastructure files searched for a
+// paging service's integration.
+var opsConfigExts = map[string]bool{".yml": true, ".yaml": true, ".json": true, ".toml": true, ".tf": true, ".hcl": true}
+
+func (c OpsReadinessCheck) Run(ctx Context) (CheckResult, error) {
+	cfg := ctx.Config.Checks.OpsReadiness
+	var runbookCfg, deployCfg string
+	if cfg != nil {
+		runbookCfg, deployCfg = cfg.Runbook, cfg.DeployDoc
+	}
+
+	files := ctx.files()
+	var runbook, onCall, rollback string
+	var deployDocs, problems []string
+	read := func(rel string) []byte {
+		content, err := readFile(filepath.Join(ctx.RootDir, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil
+		}
+		return content
+	}
+
+	if runbookCfg != "" {
+		if problem := requiredFileProblem(filepath.Join(ctx.RootDir, filepath.FromSlash(runbookCfg))); problem != "" {
+			problems = append(problems, "Runbook "+runbookCfg+" "+problem)
+		} else {
+			runbook = runbookCfg
+		}
+	}
+	if deployCfg != "" {
+		if problem := requiredFileProblem(filepath.Join(ctx.RootDir, filepath.FromSlash(deployCfg))); problem != "" {
+			problems = append(problems, "Deploy doc "+deployCfg+" "+problem)
+		} else {
+			deployDocs = append(deployDocs, deployCfg)
+		}
+	}
+
+	for _, f := range files.Files {
+		if f.Ignored || f.Size > 1024*1024 || f.inDir(templateSkipDirs) {
+			continue
+		}
+		if strings.HasPrefix(f.Path, ".") && !strings.HasPrefix(f.Path, ".github/") {
+			continue
+		}
+		isDoc := opsDocExts[f.Ext]
+		if !isDoc && !opsConfigExts[f.Ext] {
+			continue
+		}
+		content := read(f.Path)
+		if len(bytes.TrimSpace(content)) == 0 {
+			continue
+		}
+		if onCall == "" {
+			if m := reOnCall.Find(content); m != nil {
+				onCall = string(m) + " in " + f.Path
+			}
+		}
+		if !isDoc {
+			continue
+		}
+		if runbook == "" && runbookCfg == "" && reRunbookPath.MatchString(f.Path) {
+			runbook = f.Path
+		}
+		if deployCfg == "" && (reDeployDocPath.MatchString(f.Name) ||
+			(strings.EqualFold(strings.TrimSuffix(f.Name, f.Ext), "readme") && reDeploySection.Match(content))) {
+			deployDocs = append(deployDocs, f.Path)
+		}
+	}
+
+	// Rollback steps kept in the runbook count too.
+	for _, doc := range append(deployDocs, runbook) {
+		if doc != "" && reRollback.Match(read(doc)) {
+			rollback = doc
+			break
+		}
+	}
+
+	var missing, suggestions, details []string
+	if runbook == "" {
+		missing = append(missing, "runbook")
+		if runbookCfg == "" {
+			suggestions = append(suggestions, "Write a runbook (docs/runbook.md) covering how to tell the site is down, where the logs and dashboards are, and who to call")
+		}
+	} else {
+		details = append(details, "Runbook: "+runbook)
+	}
+	if onCall == "" {
+		missing = append(missing, "on-call reference")
+		suggestions = append(suggestions, "Link the on-call schedule or paging service (PagerDuty, Opsgenie, incident.io) from the runbook, so an alert reaches someone after launch")
+	} else {
+		details = append(details, "On-call: "+onCall)
+	}
+	switch {
+	case rollback != "":
+		details = append(details, "Rollback steps: "+rollback)
+	case len(deployDocs) == 0 && deployCfg == "":
+		missing = append(missing, "rollback instructions")
+		suggestions = append(suggestions, "Document how to deploy, and how to roll back to the previous release, in docs/deploy.md or a Deploy section of the README")
+	default:
+		missing = append(missing, "rollback instructions")
+		if len(deployDocs) > 0 {
+			suggestions = append(suggestions, "Add rollback steps to "+deployDocs[0]+": the command or dashboard action that puts the previous release back")
+		}
+	}
+
+	if len(missing) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Runbook, on-call reference and rollback steps in place",
+			Details:  details,
+		}, nil
+	}
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    SeverityWarn,
+		Passed:      false,
+		Message:     "Missing " + strings.Join(missing, ", "),
+		Suggestions: append(problems, suggestions...),
+		Details:     details,
+	}, nil
+}
@@ -0,0 +1,63 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestOpsReadinessCheck(t *testing.T) {
+	run := func(t *testing.T, files map[string]string, cfg *config.OpsReadinessConfig) CheckResult {
+		t.Helper()
+		pc := &config.PreflightConfig{}
+		pc.Checks.OpsReadiness = cfg
+		result, err := OpsReadinessCheck{}.Run(Context{RootDir: writeFiles(t, files), Config: pc})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	t.Run("all in place", func(t *testing.T) {
+		result := run(t, map[string]string{
+			"docs/runbook.md":          "# Runbook\n\nAlerts go to PagerDuty, which pages whoever is on call.\n",
+			"README.md":                "# App\n\n## Deploying\n\nPush to main. To roll back, redeploy the previous release from the dashboard.\n",
+			"node_modules/x/DEPLOY.md": "",
+		}, &config.OpsReadinessConfig{Enabled: true})
+		if !result.Passed {
+			t.Fatalf("got %q %v", result.Message, result.Suggestions)
+		}
+		want := []string{"Runbook: docs/runbook.md", "On-call: PagerDuty in docs/runbook.md", "Rollback steps: README.md"}
+		if strings.Join(result.Details, "\n") != strings.Join(want, "\n") {
+			t.Errorf("details = %q, want %q", result.Details, want)
+		}
+	})
+
+	t.Run("gaps", func(t *testing.T) {
+		result := run(t, map[string]string{
+			"docs/deployment.md": "Run `make deploy`.",
+			"ops/alerts.yml":     "receivers: [email]",
+		}, &config.OpsReadinessConfig{Enabled: true})
+		if result.Passed || result.Message != "Missing runbook, on-call reference, rollback instructions" {
+			t.Fatalf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+		if !strings.Contains(strings.Join(result.Suggestions, "\n"), "Add rollback steps to docs/deployment.md") {
+			t.Errorf("suggestions = %q", result.Suggestions)
+		}
+	})
+
+	t.Run("configured paths", func(t *testing.T) {
+		result := run(t, map[string]string{
+			"handbook/ops.md": "Rollback: `fly releases rollback`.",
+			"infra/paging.tf": `resource "opsgenie_team" "web" {}`,
+			"docs/runbook.md": "ignored, a runbook is configured",
+		}, &config.OpsReadinessConfig{Enabled: true, Runbook: "handbook/missing.md", DeployDoc: "handbook/ops.md"})
+		if result.Passed || result.Message != "Missing runbook" {
+			t.Fatalf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+		if result.Suggestions[0] != "Runbook handbook/missing.md not found" {
+			t.Errorf("suggestions = %q", result.Suggestions)
+		}
+	})
+}
@@ -0,0 +1,294 @@
+package checks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// OSVCheck looks up every locked dependency in OSV.dev, the open
+// vulnerability database that aggregates the GitHub, Go, PyPI, RubyGems,
+// crates.io and Packagist advisories. It reads the lockfiles itself, so
+// unlike the vulnerability check it needs no audit tool installed and
+// covers each ecosystem the same way. Only package names and versions are
+// sent, and only with checks.osv.enabled, since they may name private
+// packages.
+type OSVCheck struct{}
+
+func (c OSVCheck) ID() string {
+	return "osv"
+}
+
+func (c OSVCheck) Title() string {
+	return "Known vulnerabilities (OSV)"
+}
+
+// osvAPIBase is the OSV API root; tests point it elsewhere.
+var osvAPIBase = "https://api.osv.dev/v1"
+
+// osvBatchSize is the most queries OSV takes in one querybatch request.
+const osvBatchSize = 1000
+
+// osvPackage is one locked dependency, in OSV's ecosystem naming.
+type osvPackage struct {
+	Ecosystem string
+	Name      string
+	Version   string
+	Lockfile  string
+}
+
+var (
+	reGemLockVersion  = regexp.MustCompile(`(?m)^    ([^\s(]+) \(([^)]+)\)$`)
+	rePinnedRequire   = regexp.MustCompile(`(?m)^\s*([A-Za-z0-9][\w.-]*)(?:\[[^\]]*\])?\s*==\s*([\w.+!-]+)`)
+	reCargoLockPkg    = regexp.MustCompile(`(?m)^name = "([^"]+)"\nversion = "([^"]+)"\nsource = "registry`)
+	reGoSumModVersion = regexp.MustCompile(`(?m)^(\S+) (v[^\s/]+) h1:`)
+)
+
+func (c OSVCheck) Run(ctx Context) (CheckResult, error) {
+	pkgs := osvLockfilePackages(ctx.RootDir)
+	if len(pkgs) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No lockfiles to look up in OSV",
+		}, nil
+	}
+
+	// Like a missing audit tool, an unreachable OSV says nothing about
+	// the dependencies, so an offline run passes with a note.
+	vulns, err := c.query(ctx, pkgs)
+	if err != nil {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Couldn't query OSV, skipping: " + err.Error(),
+			Suggestions: []string{
+				"Check that api.osv.dev is reachable from here",
+			},
+		}, nil
+	}
+
+	var affected []string
+	var locations []Location
+	total := 0
+	for i, ids := range vulns {
+		if len(ids) == 0 {
+			continue
+		}
+		p := pkgs[i]
+		total += len(ids)
+		affected = append(affected, fmt.Sprintf("%s %s (%s)", p.Name, p.Version, strings.Join(ids, ", ")))
+		locations = append(locations, Location{File: p.Lockfile, Message: fmt.Sprintf("%s %s: %s", p.Name, p.Version, strings.Join(ids, ", "))})
+	}
+	if total == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("No known vulnerabilities in %d locked packages", len(pkgs)),
+		}, nil
+	}
+
+	shown := affected
+	if len(shown) > 5 {
+		shown = shown[:5]
+	}
+	packages := "packages"
+	if len(affected) == 1 {
+		packages = "package"
+	}
+	message := fmt.Sprintf("%d known vulnerabilities in %d %s: %s", total, len(affected), packages, strings.Join(shown, "; "))
+	if total == 1 {
+		message = "1 known vulnerability: " + affected[0]
+	}
+	if len(affected) > len(shown) {
+		message += fmt.Sprintf(" (and %d more)", len(affected)-len(shown))
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  message,
+		Suggestions: []string{
+			"Upgrade the affected packages to a fixed version",
+			"Look up each advisory at https://osv.dev/vulnerability/<id>",
+		},
+		Locations: locations,
+	}, nil
+}
+
+// query asks OSV which vulnerabilities affect each package, returning
+// their IDs in pkgs order.
+func (c OSVCheck) query(ctx Context, pkgs []osvPackage) ([][]string, error) {
+	type query struct {
+		Package struct {
+			Name      string `json:"name"`
+			Ecosystem string `json:"ecosystem"`
+		} `json:"package"`
+		Version string `json:"version"`
+	}
+	vulns := make([][]string, 0, len(pkgs))
+	for start := 0; start < len(pkgs); start += osvBatchSize {
+		batch := pkgs[start:min(start+osvBatchSize, len(pkgs))]
+		queries := make([]query, len(batch))
+		for i, p := range batch {
+			queries[i].Package.Name = p.Name
+			queries[i].Package.Ecosystem = p.Ecosystem
+			queries[i].Version = p.Version
+		}
+		body, err := json.Marshal(map[string]any{"queries": queries})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx.reqContext(), "POST", osvAPIBase+"/querybatch", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", "Preflight/1.0")
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := ctx.Client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var out struct {
+			Results []struct {
+				Vulns []struct {
+					ID string `json:"id"`
+				} `json:"vulns"`
+			} `json:"results"`
+		}
+		err = json.NewDecoder(io.LimitReader(resp.Body, netutil.MaxResponseBody)).Decode(&out)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(out.Results) != len(batch) {
+			return nil, fmt.Errorf("got %d results for %d packages", len(out.Results), len(batch))
+		}
+		for _, r := range out.Results {
+			ids := make([]string, len(r.Vulns))
+			for i, v := range r.Vulns {
+				ids[i] = v.ID
+			}
+			vulns = append(vulns, ids)
+		}
+	}
+	return vulns, nil
+}
+
+// osvLockfilePackages reads the packages pinned by the project's
+// lockfiles, or pinned requirements.txt, at the root. Packages appear
+// once per ecosystem, name and version, sorted.
+func osvLockfilePackages(root string) []osvPackage {
+	read := func(name string) []byte {
+		data, err := readFile(filepath.Join(root, name))
+		if err != nil {
+			return nil
+		}
+		return data
+	}
+	seen := map[osvPackage]bool{}
+	var pkgs []osvPackage
+	add := func(ecosystem, name, version, lockfile string) {
+		p := osvPackage{ecosystem, name, version, lockfile}
+		if name == "" || version == "" || seen[p] {
+			return
+		}
+		seen[p] = true
+		pkgs = append(pkgs, p)
+	}
+
+	if data := read("package-lock.json"); data != nil {
+		var lock struct {
+			Packages map[string]struct {
+				Version string `json:"version"`
+				Link    bool   `json:"link"`
+			} `json:"packages"`
+			Dependencies map[string]struct {
+				Version string `json:"version"`
+			} `json:"dependencies"`
+		}
+		if json.Unmarshal(data, &lock) == nil {
+			for key, p := range lock.Packages {
+				i := strings.LastIndex(key, "node_modules/")
+				if i < 0 || p.Link {
+					continue
+				}
+				add("npm", key[i+len("node_modules/"):], p.Version, "package-lock.json")
+			}
+			// lockfileVersion 1 has no packages map.
+			if len(lock.Packages) == 0 {
+				for name, p := range lock.Dependencies {
+					add("npm", name, p.Version, "package-lock.json")
+				}
+			}
+		}
+	}
+	for _, m := range reGemLockVersion.FindAllSubmatch(read("Gemfile.lock"), -1) {
+		add("RubyGems", string(m[1]), string(m[2]), "Gemfile.lock")
+	}
+	if data := read("composer.lock"); data != nil {
+		var lock struct {
+			Packages    []struct{ Name, Version string } `json:"packages"`
+			PackagesDev []struct{ Name, Version string } `json:"packages-dev"`
+		}
+		if json.Unmarshal(data, &lock) == nil {
+			for _, p := range append(lock.Packages, lock.PackagesDev...) {
+				add("Packagist", p.Name, strings.TrimPrefix(p.Version, "v"), "composer.lock")
+			}
+		}
+	}
+	// A requirements.txt names exact versions only for the lines pinned
+	// with ==; a range can't be looked up.
+	for _, m := range rePinnedRequire.FindAllSubmatch(read("requirements.txt"), -1) {
+		add("PyPI", string(m[1]), string(m[2]), "requirements.txt")
+	}
+	// Crates without a registry source are the workspace's own.
+	for _, m := range reCargoLockPkg.FindAllSubmatch(bytes.ReplaceAll(read("Cargo.lock"), []byte("\r\n"), []byte("\n")), -1) {
+		add("crates.io", string(m[1]), string(m[2]), "Cargo.lock")
+	}
+	// go.sum keeps a hash for every version the module graph has
+	// mentioned. Those with only a /go.mod hash were never built, and of
+	// the rest, minimal version selection builds the highest.
+	goVersions := map[string]string{}
+	for _, m := range reGoSumModVersion.FindAllSubmatch(read("go.sum"), -1) {
+		mod, version := string(m[1]), string(m[2])
+		if cur, ok := goVersions[mod]; !ok || semver.Compare(version, cur) > 0 {
+			goVersions[mod] = version
+		}
+	}
+	for mod, version := range goVersions {
+		add("Go", mod, strings.TrimPrefix(version, "v"), "go.sum")
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool {
+		a, b := pkgs[i], pkgs[j]
+		if a.Ecosystem != b.Ecosystem {
+			return a.Ecosystem < b.Ecosystem
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+	return pkgs
+}
@@ -0,0 +1,106 @@
+package checks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOSVLockfilePackages(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"package-lock.json": `{"lockfileVersion":3,"packages":{"":{"name":"app"},"node_modules/lodash":{"version":"4.17.15"},"node_modules/a/node_modules/@scope/b":{"version":"1.0.0"},"node_modules/local":{"link":true}}}`,
+		"Gemfile.lock":      "GEM\n  remote: https://rubygems.org/\n  specs:\n    rack (2.2.3)\n      webrick (>= 1.0)\n    nokogiri (1.13.1-x86_64-linux)\n",
+		"composer.lock":     `{"packages":[{"name":"symfony/http-kernel","version":"v5.4.0"}],"packages-dev":[{"name":"phpunit/phpunit","version":"9.5.0"}]}`,
+		"requirements.txt":  "django==3.2.0\nrequests>=2.0\ncelery[redis]==5.2.1  # worker\n",
+		"Cargo.lock":        "[[package]]\nname = \"app\"\nversion = \"0.1.0\"\n\n[[package]]\nname = \"smallvec\"\nversion = \"1.6.0\"\nsource = \"registry+https://github.com/rust-lang/crates.io-index\"\n",
+		"go.sum":            "golang.org/x/net v0.1.0 h1:aaa=\ngolang.org/x/net v0.1.0/go.mod h1:bbb=\ngolang.org/x/net v0.7.0 h1:ccc=\ngolang.org/x/text v0.9.0/go.mod h1:ddd=\n",
+	})
+	var got []string
+	for _, p := range osvLockfilePackages(dir) {
+		got = append(got, p.Ecosystem+":"+p.Name+"@"+p.Version)
+	}
+	want := []string{
+		"Go:golang.org/x/net@0.7.0",
+		"Packagist:phpunit/phpunit@9.5.0",
+		"Packagist:symfony/http-kernel@5.4.0",
+		"PyPI:celery@5.2.1",
+		"PyPI:django@3.2.0",
+		"RubyGems:nokogiri@1.13.1-x86_64-linux",
+		"RubyGems:rack@2.2.3",
+		"crates.io:smallvec@1.6.0",
+		"npm:@scope/b@1.0.0",
+		"npm:lodash@4.17.15",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("packages =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestOSVCheck(t *testing.T) {
+	vulnerable := map[string][]string{"lodash": {"GHSA-p6mc-m468-83gw", "GHSA-35jh-r3h4-6jhm"}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/querybatch" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var req struct {
+			Queries []struct {
+				Package struct{ Name, Ecosystem string } `json:"package"`
+				Version string                           `json:"version"`
+			} `json:"queries"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		type vuln struct {
+			ID string `json:"id"`
+		}
+		results := make([]map[string][]vuln, len(req.Queries))
+		for i, q := range req.Queries {
+			results[i] = map[string][]vuln{}
+			for _, id := range vulnerable[q.Package.Name] {
+				results[i]["vulns"] = append(results[i]["vulns"], vuln{id})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"results": results})
+	}))
+	defer srv.Close()
+	defer func(base string) { osvAPIBase = base }(osvAPIBase)
+	osvAPIBase = srv.URL
+
+	run := func(dir string) CheckResult {
+		t.Helper()
+		result, err := OSVCheck{}.Run(Context{RootDir: dir, Client: srv.Client()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	dir := writeFiles(t, map[string]string{
+		"package-lock.json": `{"packages":{"node_modules/lodash":{"version":"4.17.15"},"node_modules/left-pad":{"version":"1.3.0"}}}`,
+	})
+	result := run(dir)
+	if result.Passed || result.Message != "2 known vulnerabilities in 1 package: lodash 4.17.15 (GHSA-p6mc-m468-83gw, GHSA-35jh-r3h4-6jhm)" {
+		t.Errorf("vulnerable: %v %q", result.Passed, result.Message)
+	}
+	if len(result.Locations) != 1 || result.Locations[0].File != "package-lock.json" {
+		t.Errorf("locations = %+v", result.Locations)
+	}
+
+	delete(vulnerable, "lodash")
+	if result := run(dir); !result.Passed || result.Message != "No known vulnerabilities in 2 locked packages" {
+		t.Errorf("clean: %v %q", result.Passed, result.Message)
+	}
+	if result := run(t.TempDir()); !result.Passed || !strings.Contains(result.Message, "No lockfiles") {
+		t.Errorf("no lockfiles: %v %q", result.Passed, result.Message)
+	}
+
+	osvAPIBase = srv.URL + "/v2"
+	if result := run(dir); !result.Passed || result.Severity != SeverityInfo || result.Message != "Couldn't query OSV, skipping: HTTP 404" {
+		t.Errorf("API error: %v %q", result.Passed, result.Message)
+	}
+}
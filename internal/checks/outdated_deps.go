@@ -0,0 +1,441 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// OutdatedDepsCheck flags a framework or runtime past its end of life,
+// and direct dependencies that have fallen several major versions behind
+// the latest release. Neither is broken today, but an EOL framework gets
+// no security fixes, and a dependency majors behind is an upgrade that
+// only grows. Opt-in: looking up the latest versions means a request to
+// the package registry per direct dependency.
+type OutdatedDepsCheck struct{}
+
+func (c OutdatedDepsCheck) ID() string {
+	return "outdated_deps"
+}
+
+func (c OutdatedDepsCheck) Title() string {
+	return "Outdated dependencies"
+}
+
+// defaultMajorsBehind is how many majors behind the latest a dependency
+// can be before it's flagged; see config.OutdatedDepsConfig.
+const defaultMajorsBehind = 2
+
+// eolCycle is one release line and the day its security support ends.
+type eolCycle struct {
+	cycle string // "7.1" matches 7.1.x; "20" matches 20.x
+	eol   string // YYYY-MM-DD
+}
+
+// eolFramework is where to find a framework's version and its release
+// lines, newest first. A version older than the oldest line listed is
+// past its end of life too.
+type eolFramework struct {
+	name      string
+	ecosystem string // the direct dependency it's locked as; "" for Node
+	pkg       string
+	cycles    []eolCycle
+}
+
+// frameworkEOL holds the end-of-life dates the frameworks' maintainers
+// publish (see endoflife.date). Lines not listed yet are treated as
+// supported.
+var frameworkEOL = []eolFramework{
+	{"Rails", "bundler", "rails", []eolCycle{
+		{"8.1", "2027-10-10"}, {"8.0", "2026-11-07"}, {"7.2", "2026-08-09"}, {"7.1", "2025-10-01"},
+		{"7.0", "2025-04-01"}, {"6.1", "2024-10-01"}, {"6.0", "2023-06-01"}, {"5.2", "2022-06-01"},
+	}},
+	{"Laravel", "composer", "laravel/framework", []eolCycle{
+		{"12", "2027-02-24"}, {"11", "2026-03-12"}, {"10", "2025-02-07"}, {"9", "2024-02-06"}, {"8", "2023-01-24"},
+	}},
+	{"Django", "pip", "django", []eolCycle{
+		{"6.0", "2027-04-30"}, {"5.2", "2028-04-30"}, {"5.1", "2025-12-31"}, {"5.0", "2025-04-30"},
+		{"4.2", "2026-04-30"}, {"4.1", "2023-12-01"}, {"4.0", "2023-04-01"}, {"3.2", "2024-04-01"},
+	}},
+	{"Node.js", "", "", []eolCycle{
+		{"25", "2026-06-01"}, {"24", "2028-04-30"}, {"23", "2025-06-01"}, {"22", "2027-04-30"},
+		{"21", "2024-06-01"}, {"20", "2026-04-30"}, {"19", "2023-06-01"}, {"18", "2025-04-30"},
+		{"17", "2022-06-01"}, {"16", "2023-09-11"},
+	}},
+}
+
+// directDep is a dependency the project declares itself, at the version
+// its lockfile pins.
+type directDep struct {
+	ecosystem string
+	name      string
+	version   string
+	file      string
+}
+
+// registryBases are the package registries' API roots; tests point them
+// elsewhere.
+var registryBases = map[string]string{
+	"npm":      "https://registry.npmjs.org",
+	"bundler":  "https://rubygems.org",
+	"composer": "https://repo.packagist.org",
+	"pip":      "https://pypi.org",
+}
+
+var (
+	reGemfileGemName = regexp.MustCompile(`(?m)^\s*gem\s+["']([^"']+)["']`)
+	reLeadingVersion = regexp.MustCompile(`\d+(?:\.\d+)*`)
+)
+
+func (c OutdatedDepsCheck) Run(ctx Context) (CheckResult, error) {
+	behind := defaultMajorsBehind
+	if cfg := ctx.Config.Checks.OutdatedDeps; cfg != nil && cfg.MajorsBehind > 0 {
+		behind = cfg.MajorsBehind
+	}
+	deps := directDependencies(ctx.RootDir)
+	issues := frameworkEOLIssues(ctx.RootDir, deps, time.Now())
+
+	latest, failed := latestVersions(ctx, deps)
+	var stale []string
+	var locations []Location
+	for i, dep := range deps {
+		newest, ok := latest[i]
+		if !ok {
+			continue
+		}
+		have, want := majorVersion(dep.version), majorVersion(newest)
+		if have < 0 || want-have < behind {
+			continue
+		}
+		stale = append(stale, fmt.Sprintf("%s %s (latest %s)", dep.name, dep.version, newest))
+		locations = append(locations, Location{File: dep.file, Message: fmt.Sprintf("%s is %d major versions behind %s", dep.name, want-have, newest)})
+	}
+
+	if len(issues) == 0 && len(stale) == 0 {
+		message := fmt.Sprintf("No end-of-life frameworks, and no direct dependencies %d or more majors behind", behind)
+		if failed > 0 {
+			message += fmt.Sprintf(" (%d couldn't be looked up)", failed)
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  message,
+		}, nil
+	}
+
+	var suggestions []string
+	if len(issues) > 0 {
+		suggestions = append(suggestions, "Upgrade to a supported release line before launch; an end-of-life framework gets no security fixes")
+	}
+	if len(stale) > 0 {
+		if len(stale) > 5 {
+			stale = append(stale[:5], fmt.Sprintf("and %d more", len(stale)-5))
+		}
+		issues = append(issues, "Majors behind: "+strings.Join(stale, ", "))
+		suggestions = append(suggestions, "Plan the major upgrades one at a time, reading each changelog for breaking changes")
+	}
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    SeverityWarn,
+		Passed:      false,
+		Message:     strings.Join(issues, "; "),
+		Suggestions: suggestions,
+		Locations:   locations,
+	}, nil
+}
+
+// frameworkEOLIssues describes each framework in frameworkEOL the project
+// runs on a release line past its end of life as of now.
+func frameworkEOLIssues(root string, deps []directDep, now time.Time) []string {
+	var issues []string
+	for _, fw := range frameworkEOL {
+		version := ""
+		if fw.ecosystem == "" {
+			version = nodeVersion(root)
+		}
+		for _, dep := range deps {
+			if dep.ecosystem == fw.ecosystem && strings.EqualFold(dep.name, fw.pkg) {
+				version = dep.version
+			}
+		}
+		if version == "" {
+			continue
+		}
+		if eol, ok := releaseEOL(fw.cycles, version); ok && now.After(eol) {
+			issues = append(issues, fmt.Sprintf("%s %s reached end of life on %s", fw.name, version, eol.Format("2006-01-02")))
+		}
+	}
+	return issues
+}
+
+// releaseEOL returns when version's release line stops getting security
+// fixes, and false for a line newer than any listed.
+func releaseEOL(cycles []eolCycle, version string) (time.Time, bool) {
+	for _, c := range cycles {
+		if version == c.cycle || strings.HasPrefix(version, c.cycle+".") {
+			eol, err := time.Parse("2006-01-02", c.eol)
+			return eol, err == nil
+		}
+	}
+	oldest := cycles[len(cycles)-1]
+	if compareVersions(version, oldest.cycle) < 0 {
+		eol, err := time.Parse("2006-01-02", oldest.eol)
+		return eol, err == nil
+	}
+	return time.Time{}, false
+}
+
+// nodeVersion returns the Node.js version the project pins in .nvmrc,
+// .node-version or an exact engines.node, or "" when it doesn't pin one.
+// A range like >=18 only says what works, not what's deployed.
+func nodeVersion(root string) string {
+	for _, name := range []string{".nvmrc", ".node-version"} {
+		data, err := readFile(filepath.Join(root, name))
+		if err != nil {
+			continue
+		}
+		if v := strings.TrimPrefix(strings.TrimSpace(string(data)), "v"); v != "" && v[0] >= '0' && v[0] <= '9' {
+			return reLeadingVersion.FindString(v)
+		}
+	}
+	data, err := readFile(filepath.Join(root, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Engines struct {
+			Node string `json:"node"`
+		} `json:"engines"`
+	}
+	if json.Unmarshal(data, &pkg) != nil || strings.ContainsAny(pkg.Engines.Node, "<>|*") {
+		return ""
+	}
+	return reLeadingVersion.FindString(pkg.Engines.Node)
+}
+
+// directDependencies lists the runtime dependencies the project declares
+// in package.json, Gemfile, composer.json and requirements.txt, at the
+// versions the lockfiles pin. Development dependencies are left out:
+// they don't ship.
+func directDependencies(root string) []directDep {
+	read := func(name string) []byte {
+		data, err := readFile(filepath.Join(root, name))
+		if err != nil {
+			return nil
+		}
+		return data
+	}
+	var deps []directDep
+
+	if data := read("package.json"); data != nil {
+		var pkg struct {
+			Dependencies map[string]string `json:"dependencies"`
+		}
+		var lock struct {
+			Packages map[string]struct {
+				Version string `json:"version"`
+			} `json:"packages"`
+		}
+		_ = json.Unmarshal(read("package-lock.json"), &lock)
+		if json.Unmarshal(data, &pkg) == nil {
+			for name, spec := range pkg.Dependencies {
+				version := lock.Packages["node_modules/"+name].Version
+				if version == "" {
+					version = reLeadingVersion.FindString(spec)
+				}
+				deps = append(deps, directDep{"npm", name, version, "package.json"})
+			}
+		}
+	}
+	if data := read("Gemfile"); data != nil {
+		locked := map[string]string{}
+		for _, m := range reGemLockVersion.FindAllSubmatch(read("Gemfile.lock"), -1) {
+			locked[string(m[1])] = string(m[2])
+		}
+		// Gems in a development or test group, as a block or inline,
+		// don't ship.
+		devGroup := false
+		for _, line := range strings.Split(string(data), "\n") {
+			trimmed := strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(trimmed, "group ") && strings.HasSuffix(trimmed, " do"):
+				devGroup = !strings.Contains(trimmed, ":production") && !strings.Contains(trimmed, ":default")
+			case trimmed == "end":
+				devGroup = false
+			}
+			m := reGemfileGemName.FindStringSubmatch(line)
+			if m == nil || devGroup || strings.Contains(line, ":development") || strings.Contains(line, ":test") {
+				continue
+			}
+			deps = append(deps, directDep{"bundler", m[1], locked[m[1]], "Gemfile"})
+		}
+	}
+	if data := read("composer.json"); data != nil {
+		var manifest struct {
+			Require map[string]string `json:"require"`
+		}
+		var lock struct {
+			Packages []struct{ Name, Version string } `json:"packages"`
+		}
+		_ = json.Unmarshal(read("composer.lock"), &lock)
+		locked := map[string]string{}
+		for _, p := range lock.Packages {
+			locked[p.Name] = strings.TrimPrefix(p.Version, "v")
+		}
+		if json.Unmarshal(data, &manifest) == nil {
+			for name := range manifest.Require {
+				// php and extensions are platform requirements.
+				if strings.Contains(name, "/") {
+					deps = append(deps, directDep{"composer", name, locked[name], "composer.json"})
+				}
+			}
+		}
+	}
+	for _, m := range rePinnedRequire.FindAllSubmatch(read("requirements.txt"), -1) {
+		deps = append(deps, directDep{"pip", strings.ToLower(string(m[1])), string(m[2]), "requirements.txt"})
+	}
+
+	// Versions are compared by their numbers alone: a platform suffix
+	// (1.13.1-x86_64-linux) or pre-release tag doesn't change the release
+	// line, and a branch (dev-main) has none to compare.
+	kept := deps[:0]
+	for _, d := range deps {
+		if d.version = reLeadingVersion.FindString(d.version); d.version != "" {
+			kept = append(kept, d)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		if kept[i].ecosystem != kept[j].ecosystem {
+			return kept[i].ecosystem < kept[j].ecosystem
+		}
+		return kept[i].name < kept[j].name
+	})
+	return kept
+}
+
+// latestVersions looks up the latest release of each dependency, keyed
+// by its index in deps, and counts the lookups that failed.
+func latestVersions(ctx Context, deps []directDep) (map[int]string, int) {
+	latest := map[int]string{}
+	failed := 0
+	var mu sync.Mutex
+
+	const workers = 8
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				version, err := latestVersion(ctx, deps[i])
+				mu.Lock()
+				if err != nil {
+					failed++
+				} else if version != "" {
+					latest[i] = version
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range deps {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return latest, failed
+}
+
+// latestVersion asks dep's registry for its latest stable release.
+func latestVersion(ctx Context, dep directDep) (string, error) {
+	base := registryBases[dep.ecosystem]
+	var url string
+	switch dep.ecosystem {
+	case "npm":
+		url = base + "/" + dep.name + "/latest"
+	case "bundler":
+		url = base + "/api/v1/versions/" + dep.name + "/latest.json"
+	case "composer":
+		url = base + "/p2/" + dep.name + ".json"
+	case "pip":
+		url = base + "/pypi/" + dep.name + "/json"
+	}
+	resp, err := doGet(ctx.reqContext(), ctx.Client, url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	var body struct {
+		Version string `json:"version"` // npm, RubyGems
+		Info    struct {
+			Version string `json:"version"`
+		} `json:"info"` // PyPI
+		Packages map[string][]struct {
+			Version string `json:"version"`
+		} `json:"packages"` // Packagist, newest first
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, netutil.MaxResponseBody)).Decode(&body); err != nil {
+		return "", err
+	}
+	switch dep.ecosystem {
+	case "pip":
+		return body.Info.Version, nil
+	case "composer":
+		for _, v := range body.Packages[dep.name] {
+			version := strings.TrimPrefix(v.Version, "v")
+			if reLeadingVersion.FindString(version) == version {
+				return version, nil
+			}
+		}
+		return "", nil
+	}
+	return body.Version, nil
+}
+
+// majorVersion returns the leading number of version, or -1.
+func majorVersion(version string) int {
+	n, err := strconv.Atoi(strings.SplitN(reLeadingVersion.FindString(version), ".", 2)[0])
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// compareVersions orders two dotted versions numerically.
+func compareVersions(a, b string) int {
+	as := strings.Split(reLeadingVersion.FindString(a), ".")
+	bs := strings.Split(reLeadingVersion.FindString(b), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
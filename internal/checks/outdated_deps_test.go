@@ -0,0 +1,125 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestFrameworkEOLIssues(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".nvmrc": "v18.19.0\n",
+	})
+	deps := []directDep{
+		{"bundler", "rails", "7.0.8", "Gemfile"},
+		{"composer", "laravel/framework", "12.1.0", "composer.json"},
+		{"pip", "django", "2.2.28", "requirements.txt"},
+	}
+	now := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	want := []string{
+		"Rails 7.0.8 reached end of life on 2025-04-01",
+		// Older than any line listed.
+		"Django 2.2.28 reached end of life on 2024-04-01",
+		"Node.js 18.19.0 reached end of life on 2025-04-30",
+	}
+	if got := frameworkEOLIssues(dir, deps, now); !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %q\nwant %q", got, want)
+	}
+
+	// A line newer than the table isn't reported.
+	if got := frameworkEOLIssues(t.TempDir(), []directDep{{"bundler", "rails", "9.0.0", "Gemfile"}}, now); len(got) != 0 {
+		t.Errorf("rails 9 issues = %q", got)
+	}
+	// An engines range says what works, not what's deployed.
+	dir = writeFiles(t, map[string]string{"package.json": `{"engines":{"node":">=16"}}`})
+	if got := nodeVersion(dir); got != "" {
+		t.Errorf("nodeVersion(>=16) = %q", got)
+	}
+}
+
+func TestDirectDependencies(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"package.json":      `{"dependencies":{"react":"^17.0.0","lodash":"^4.17.0"},"devDependencies":{"jest":"^26.0.0"}}`,
+		"package-lock.json": `{"packages":{"node_modules/react":{"version":"17.0.2"}}}`,
+		"Gemfile":           "source \"https://rubygems.org\"\ngem \"rails\", \"~> 7.0\"\ngem \"bootsnap\", require: false\ngroup :development, :test do\n  gem \"rspec-rails\"\nend\ngem \"pry\", group: :development\n",
+		"Gemfile.lock":      "GEM\n  specs:\n    bootsnap (1.16.0)\n    nokogiri (1.15.0-x86_64-linux)\n    rails (7.0.8)\n    rspec-rails (6.0.0)\n",
+		"composer.json":     `{"require":{"php":"^8.1","laravel/framework":"^9.0","acme/fork":"dev-main"}}`,
+		"composer.lock":     `{"packages":[{"name":"laravel/framework","version":"v9.52.0"},{"name":"acme/fork","version":"dev-main"}]}`,
+	})
+	var got []string
+	for _, d := range directDependencies(dir) {
+		got = append(got, d.ecosystem+":"+d.name+"@"+d.version)
+	}
+	want := []string{
+		"bundler:bootsnap@1.16.0",
+		"bundler:rails@7.0.8",
+		"composer:laravel/framework@9.52.0",
+		"npm:lodash@4.17.0",
+		"npm:react@17.0.2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("deps = %q\nwant %q", got, want)
+	}
+}
+
+func TestOutdatedDepsCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/react/latest":
+			w.Write([]byte(`{"name":"react","version":"19.1.0"}`))
+		case "/lodash/latest":
+			w.Write([]byte(`{"name":"lodash","version":"4.17.21"}`))
+		case "/p2/monolog/monolog.json":
+			w.Write([]byte(`{"packages":{"monolog/monolog":[{"version":"4.0.0-beta1"},{"version":"3.8.0"}]}}`))
+		case "/pypi/requests/json":
+			w.Write([]byte(`{"info":{"version":"2.32.3"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	defer func(bases map[string]string) { registryBases = bases }(registryBases)
+	registryBases = map[string]string{"npm": srv.URL, "composer": srv.URL, "pip": srv.URL, "bundler": srv.URL}
+
+	run := func(dir string, cfg *config.OutdatedDepsConfig) CheckResult {
+		t.Helper()
+		c := &config.PreflightConfig{}
+		c.Checks.OutdatedDeps = cfg
+		result, err := OutdatedDepsCheck{}.Run(Context{RootDir: dir, Config: c, Client: srv.Client()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	dir := writeFiles(t, map[string]string{
+		"package.json":     `{"dependencies":{"react":"^16.14.0","lodash":"^4.17.0"}}`,
+		"requirements.txt": "requests==2.31.0\nleftpad==1.0.0\n",
+	})
+	result := run(dir, &config.OutdatedDepsConfig{Enabled: true})
+	if result.Passed || result.Message != "Majors behind: react 16.14.0 (latest 19.1.0)" {
+		t.Errorf("default threshold: %v %q", result.Passed, result.Message)
+	}
+	if len(result.Locations) != 1 || result.Locations[0].File != "package.json" {
+		t.Errorf("locations = %+v", result.Locations)
+	}
+
+	result = run(dir, &config.OutdatedDepsConfig{Enabled: true, MajorsBehind: 4})
+	if !result.Passed || !strings.Contains(result.Message, "4 or more majors behind (1 couldn't be looked up)") {
+		t.Errorf("majorsBehind 4: %v %q", result.Passed, result.Message)
+	}
+
+	// Packagist's pre-releases aren't the latest release.
+	dir = writeFiles(t, map[string]string{
+		"composer.json": `{"require":{"monolog/monolog":"^2.9"}}`,
+		"composer.lock": `{"packages":[{"name":"monolog/monolog","version":"2.9.3"}]}`,
+	})
+	if result := run(dir, nil); !result.Passed {
+		t.Errorf("monolog 2 vs 3: %q", result.Message)
+	}
+}
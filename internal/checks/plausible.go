@@ -1,10 +1,9 @@
 package checks
 
 import (
-	"os"
 	"path/filepath"
 	"regexp"
-	"strings"
+	"slices"
 )
 
 type PlausibleCheck struct{}
@@ -38,8 +37,15 @@ func (c PlausibleCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`@plausible/tracker`),
 	}
 
-	// Templates and layouts to check based on stack
-	filesToCheck := getLayoutFiles(ctx.Config.Stack)
+	// Templates and layouts to check: the configured mainLayout, then
+	// the stack's usual layouts
+	var filesToCheck []string
+	if cfg := ctx.Config.Checks.SEOMeta; cfg != nil && cfg.MainLayout != "" {
+		filesToCheck = append(filesToCheck, cfg.MainLayout)
+	}
+	for _, stack := range ctx.Config.AllStacks() {
+		filesToCheck = append(filesToCheck, getLayoutFiles(stack)...)
+	}
 
 	// Also check common locations
 	filesToCheck = append(filesToCheck,
@@ -51,14 +57,15 @@ func (c PlausibleCheck) Run(ctx Context) (CheckResult, error) {
 	found := false
 
 	for _, file := range filesToCheck {
+		// The script tag is often in a head partial the layout includes.
 		path := filepath.Join(ctx.RootDir, file)
-		content, err := os.ReadFile(path)
+		content, err := readWithIncludes(path, ctx.RootDir, ctx.Config.Stack)
 		if err != nil {
 			continue
 		}
 
 		for _, pattern := range patterns {
-			if pattern.Match(content) {
+			if pattern.MatchString(content) {
 				found = true
 				break
 			}
@@ -74,47 +81,28 @@ func (c PlausibleCheck) Run(ctx Context) (CheckResult, error) {
 		searchDirs := []string{"src", "app", "components"}
 		extensions := []string{".tsx", ".jsx", ".js", ".ts"}
 
+		files := ctx.files()
 		for _, dir := range searchDirs {
-			dirPath := filepath.Join(ctx.RootDir, dir)
-			if _, err := os.Stat(dirPath); os.IsNotExist(err) {
-				continue
-			}
-
-			_ = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-				if err != nil || info.IsDir() || found {
-					return nil
+			for _, f := range files.Under(dir) {
+				if f.Ignored || !slices.Contains(extensions, f.Ext) {
+					continue
 				}
 
-				if strings.Contains(path, "node_modules") {
-					return filepath.SkipDir
-				}
-
-				ext := filepath.Ext(path)
-				validExt := false
-				for _, e := range extensions {
-					if ext == e {
-						validExt = true
-						break
-					}
-				}
-				if !validExt {
-					return nil
-				}
-
-				content, err := os.ReadFile(path)
+				content, err := readFile(files.Abs(f))
 				if err != nil {
-					return nil
+					continue
 				}
 
 				for _, pattern := range patterns {
 					if pattern.Match(content) {
 						found = true
-						return filepath.SkipAll
+						break
 					}
 				}
-
-				return nil
-			})
+				if found {
+					break
+				}
+			}
 
 			if found {
 				break
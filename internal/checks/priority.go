@@ -0,0 +1,59 @@
+package checks
+
+import "sort"
+
+// checkPriorities ranks checks for time-boxed scans (--max-duration),
+// which run the most important ones first so a short budget still covers
+// what blocks a launch. Lower runs earlier; IDs without an entry,
+// including declared-service and custom checks, get defaultPriority.
+var checkPriorities = map[string]int{
+	// Launch blockers
+	"secrets":          0,
+	"vulnerability":    0,
+	"osv":              0,
+	"ssl":              0,
+	"envParity":        0,
+	"debug_statements": 0,
+	"debug_mode":       0,
+	"securityHeaders":  0,
+	"healthEndpoint":   0,
+	"smoke":            0,
+	"auth_routes":      0,
+	"regulated_gating": 0,
+	// Visible on day one
+	"seoMeta":      1,
+	"error_pages":  1,
+	"favicon":      1,
+	"robotsTxt":    1,
+	"sitemap":      1,
+	"canonical":    1,
+	"viewport":     1,
+	"lang":         1,
+	"legal_pages":  1,
+	"supply_chain": 1,
+	"email_auth":   1,
+	"www_redirect": 1,
+	"routes":       1,
+	// Judged from the results before it, so it always comes last.
+	"required_services": 3,
+}
+
+const defaultPriority = 2
+
+// Priority returns the rank a time-boxed scan runs the check at.
+func Priority(id string) int {
+	if p, ok := checkPriorities[id]; ok {
+		return p
+	}
+	return defaultPriority
+}
+
+// SortByPriority orders checks by Priority, keeping their relative order
+// within a rank.
+func SortByPriority(list []Check) []Check {
+	sorted := append([]Check(nil), list...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return Priority(sorted[i].ID()) < Priority(sorted[j].ID())
+	})
+	return sorted
+}
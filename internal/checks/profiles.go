@@ -0,0 +1,57 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FullProfile runs every enabled check, as a scan without --profile does.
+const FullProfile = "full"
+
+// Profiles are the named slices of the checklist that scan --profile
+// narrows a run to, so the person looking after SEO isn't wading through
+// dependency audits. preflight.yml's profiles: replaces these by name or
+// adds new ones.
+var Profiles = map[string][]string{
+	"seo": {
+		"seoMeta", "canonical", "ogTwitter", "twitter_card", "structured_data", "sitemap", "sitemap_coverage",
+		"robotsTxt", "llmsTxt", "indexNow", "lang", "viewport", "www_redirect", "favicon",
+		"image_alt", "error_pages", "mirrors", "search_console", "hreflang",
+	},
+	"security": {
+		"securityHeaders", "ssl", "secrets", "vulnerability", "osv", "outdated_deps", "supply_chain", "dockerfile", "compose", "iac", "debug_statements", "debug_mode",
+		"envParity", "email_auth", "auth_routes", "cloudflare_zone", "bot_protection", "rate_limit", "redirect_ssrf", "sql_injection",
+	},
+	"compliance": {
+		"legal_pages", "cookies", "regulated_gating", "a11y_statement", "refund_policy", "license", "image_alt",
+		"cookieconsent", "cookiebot", "onetrust", "termly", "cookieyes", "iubenda",
+	},
+	"performance": {
+		"image_optimization", "fonts", "healthEndpoint", "routes", "smoke", "resilience",
+	},
+	FullProfile: nil,
+}
+
+// ProfileCheckIDs returns the check IDs in profile name, looking in custom
+// (preflight.yml's profiles:) before the built-in Profiles. A nil result
+// with no error means every check.
+func ProfileCheckIDs(name string, custom map[string][]string) ([]string, error) {
+	if ids, ok := custom[name]; ok {
+		return ids, nil
+	}
+	if ids, ok := Profiles[name]; ok {
+		return ids, nil
+	}
+	names := make([]string, 0, len(Profiles)+len(custom))
+	for n := range Profiles {
+		names = append(names, n)
+	}
+	for n := range custom {
+		if _, builtin := Profiles[n]; !builtin {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown profile %q (want %s)", name, strings.Join(names, ", "))
+}
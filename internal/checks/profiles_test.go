@@ -0,0 +1,39 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+)
+
+// A built-in profile naming a check that was renamed or removed would make
+// every scan with it fail, so each ID must still be registered.
+func TestProfilesNameRegisteredChecks(t *testing.T) {
+	registered := make(map[string]bool, len(Registry))
+	for _, c := range Registry {
+		registered[c.ID()] = true
+	}
+	for name, ids := range Profiles {
+		for _, id := range ids {
+			if !registered[id] {
+				t.Errorf("profile %q: %q is not in Registry", name, id)
+			}
+		}
+	}
+}
+
+func TestProfileCheckIDs(t *testing.T) {
+	custom := map[string][]string{"seo": {"seoMeta"}, "launch": {"secrets", "ssl"}}
+	if ids, err := ProfileCheckIDs("seo", custom); err != nil || len(ids) != 1 {
+		t.Errorf("seo = %v, %v; want the preflight.yml override", ids, err)
+	}
+	if ids, err := ProfileCheckIDs("launch", custom); err != nil || len(ids) != 2 {
+		t.Errorf("launch = %v, %v", ids, err)
+	}
+	if ids, err := ProfileCheckIDs(FullProfile, nil); err != nil || ids != nil {
+		t.Errorf("full = %v, %v; want nil (every check)", ids, err)
+	}
+	_, err := ProfileCheckIDs("seo2", custom)
+	if err == nil || !strings.Contains(err.Error(), "compliance, full, launch, performance, security, seo") {
+		t.Errorf("err = %v, want it to list the profile names", err)
+	}
+}
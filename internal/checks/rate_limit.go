@@ -0,0 +1,207 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/routes"
+)
+
+// RateLimitCheck finds the endpoints attackers hammer on launch day
+// (sign-in, password reset, token and API routes) and looks for rate
+// limiting in front of them: middleware such as rack-attack,
+// express-rate-limit or Laravel's throttle, or limit_req in a proxy config.
+// Without it, credential stuffing gets unlimited guesses and one scraper
+// can take the API down.
+type RateLimitCheck struct{}
+
+func (c RateLimitCheck) ID() string {
+	return "rate_limit"
+}
+
+func (c RateLimitCheck) Title() string {
+	return "Rate limiting"
+}
+
+// reAuthPath matches a path segment naming an endpoint that checks or
+// issues credentials.
+var reAuthPath = regexp.MustCompile(`(?i)(?:^|/)(?:log-?in|sign-?in|sign_in|sessions?|passwords?|forgot(?:-password)?|reset(?:-password)?|auth|oauth|token|otp|2fa|mfa|magic-?link|sign-?up|register)(?:/|\.|$)`)
+
+// rateLimiters are the rate-limiting libraries, framework features and
+// proxy directives recognized, by what each leaves in a project.
+var rateLimiters = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"rack-attack", regexp.MustCompile(`rack-attack|Rack::Attack`)},
+	{"Rails rate_limit", regexp.MustCompile(`(?m)^\s*rate_limit\s+to:`)},
+	{"express-rate-limit", regexp.MustCompile(`express-rate-limit|express-slow-down`)},
+	{"rate-limiter-flexible", regexp.MustCompile(`rate-limiter-flexible`)},
+	{"Upstash Ratelimit", regexp.MustCompile(`@upstash/ratelimit`)},
+	{"@fastify/rate-limit", regexp.MustCompile(`@fastify/rate-limit|fastify-rate-limit`)},
+	{"@nestjs/throttler", regexp.MustCompile(`@nestjs/throttler`)},
+	{"koa-ratelimit", regexp.MustCompile(`koa-ratelimit`)},
+	{"hono-rate-limiter", regexp.MustCompile(`hono-rate-limiter`)},
+	{"Arcjet", regexp.MustCompile(`@arcjet/`)},
+	{"Laravel throttle", regexp.MustCompile(`throttle:|RateLimiter::|ThrottleRequests|->throttleApi\(`)},
+	{"Django REST framework throttling", regexp.MustCompile(`DEFAULT_THROTTLE_(?:CLASSES|RATES)|throttle_classes`)},
+	{"django-ratelimit", regexp.MustCompile(`django-ratelimit|django_ratelimit|@ratelimit\(`)},
+	{"django-axes", regexp.MustCompile(`django-axes|axes\.middleware`)},
+	{"Flask-Limiter", regexp.MustCompile(`(?i)flask[-_]limiter`)},
+	{"slowapi", regexp.MustCompile(`\bslowapi\b`)},
+	{"Go rate limiter", regexp.MustCompile(`golang\.org/x/time/rate|github\.com/ulule/limiter|github\.com/didip/tollbooth|github\.com/go-chi/httprate`)},
+	{"nginx limit_req", regexp.MustCompile(`\blimit_req(?:_zone)?\s`)},
+	{"Caddy rate_limit", regexp.MustCompile(`(?m)^\s*rate_limit\s*\{`)},
+	{"Traefik rateLimit", regexp.MustCompile(`(?i)\bratelimit\b\s*:|middlewares\.[\w-]+\.ratelimit`)},
+}
+
+// limiterFiles restricts a limiter to the files it can be configured in,
+// for patterns that would match unrelated application code.
+var limiterFiles = map[string]func(FileEntry) bool{
+	"Traefik rateLimit": traefikConfig,
+}
+
+// rateLimitManifests are the dependency and proxy config files that name
+// a limiter when the application code doesn't do so recognizably.
+var rateLimitManifests = map[string]bool{
+	"package.json": true, "Gemfile": true, "composer.json": true, "requirements.txt": true,
+	"pyproject.toml": true, "go.mod": true, "Caddyfile": true, "nginx.conf": true,
+	"traefik.yml": true, "traefik.yaml": true, "traefik.toml": true,
+}
+
+// credentialServices are dependencies that serve sign-in or an API from
+// the application itself, for the routes a framework extractor can't see
+// (devise_for, an Express app).
+var credentialServices = []struct {
+	name, manifest string
+	re             *regexp.Regexp
+}{
+	{"Devise", "Gemfile", regexp.MustCompile(`(?m)^\s*gem\s+["']devise["']`)},
+	{"an auth library", "package.json", regexp.MustCompile(`"(?:next-auth|@auth/core|better-auth|lucia|passport)"\s*:`)},
+	{"an API server", "package.json", regexp.MustCompile(`"(?:express|fastify|koa|hono|@nestjs/core)"\s*:`)},
+	{"Laravel auth", "composer.json", regexp.MustCompile(`"laravel/(?:fortify|breeze|jetstream|sanctum|passport|ui)"\s*:`)},
+	{"a Python API", "requirements.txt", regexp.MustCompile(`(?im)^(?:fastapi|flask|djangorestframework|django-allauth)\b`)},
+}
+
+func (c RateLimitCheck) Run(ctx Context) (CheckResult, error) {
+	endpoints := map[string]string{}
+	for _, r := range routes.Extract(ctx.RootDir) {
+		if !r.API && !reAuthPath.MatchString(r.Path) {
+			continue
+		}
+		if _, ok := endpoints[r.Path]; !ok {
+			endpoints[r.Path] = r.Source
+		}
+	}
+	var locations []Location
+	for _, s := range credentialServices {
+		content, err := readFile(filepath.Join(ctx.RootDir, s.manifest))
+		if err != nil {
+			continue
+		}
+		if m := s.re.FindIndex(content); m != nil {
+			loc := Location{File: s.manifest, Line: lineAt(content, m[0]), Message: "depends on " + s.name}
+			locations = append(locations, loc)
+			endpoints[s.name] = loc.File
+		}
+	}
+	if len(endpoints) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No API or sign-in endpoints found",
+		}, nil
+	}
+
+	found := map[string]string{}
+	files := ctx.files()
+	for _, f := range files.Files {
+		if f.Ignored || f.Size > 500*1024 || f.inDir(templateSkipDirs) || isTestSource(f.Path) {
+			continue
+		}
+		// nginx and Caddy configs often sit in a hidden .docker or
+		// deploy directory, so they're read wherever they are.
+		proxy := rateLimitManifests[f.Name] || f.Ext == ".conf" || traefikConfig(f)
+		if !proxy && !appSourceExts[f.Ext] {
+			continue
+		}
+		if !proxy && (strings.HasPrefix(f.Path, ".") || strings.Contains(f.Path, "/.")) {
+			continue
+		}
+		content, err := readFile(files.Abs(f))
+		if err != nil {
+			continue
+		}
+		for _, l := range rateLimiters {
+			if only, ok := limiterFiles[l.name]; ok && !only(f) {
+				continue
+			}
+			if _, ok := found[l.name]; !ok && l.re.Match(content) {
+				found[l.name] = f.Path
+			}
+		}
+	}
+
+	names := make([]string, 0, len(endpoints))
+	for p := range endpoints {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+	var listed []string
+	for _, p := range names {
+		listed = append(listed, fmt.Sprintf("%s (%s)", p, endpoints[p]))
+	}
+
+	if len(found) > 0 {
+		var limiters, details []string
+		for _, l := range rateLimiters {
+			if file, ok := found[l.name]; ok {
+				limiters = append(limiters, l.name)
+				details = append(details, l.name+" found in "+file)
+			}
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("%s found for %d API and sign-in endpoints", strings.Join(limiters, ", "), len(endpoints)),
+			Details:  append(details, limitFindings(listed, 5)...),
+		}, nil
+	}
+
+	message := fmt.Sprintf("%d API and sign-in endpoints and no rate limiting", len(endpoints))
+	if len(endpoints) == 1 {
+		message = "No rate limiting found for " + names[0]
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  message,
+		Suggestions: append([]string{
+			"Limit sign-in, password reset and API requests per IP and per account: rack-attack, express-rate-limit, Laravel's throttle middleware, or limit_req in nginx",
+			"If requests are limited at the edge (Cloudflare rate limiting rules, an API gateway), ignore rate_limit",
+		}, limitFindings(listed, 8)...),
+		Locations: locations,
+	}, nil
+}
+
+// traefikConfig reports whether f is Traefik configuration: a static or
+// dynamic config file, or a Compose file whose labels configure Traefik
+// middlewares. A rateLimit key anywhere else is application code.
+func traefikConfig(f FileEntry) bool {
+	if reComposeFile.MatchString(f.Name) {
+		return true
+	}
+	if f.Ext != ".yml" && f.Ext != ".yaml" && f.Ext != ".toml" {
+		return false
+	}
+	return strings.HasPrefix(strings.ToLower(f.Name), "traefik") || f.inDir(map[string]bool{"traefik": true})
+}
@@ -0,0 +1,132 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRateLimitCheck(t *testing.T) {
+	unlimited := map[string]string{
+		"app/page.tsx":               "export default function Home() {}",
+		"app/login/page.tsx":         "",
+		"app/api/projects/route.ts":  "export async function GET() {}",
+		"app/pricing/page.tsx":       "",
+		"package.json":               `{"dependencies": {"next": "15.0.0", "next-auth": "^5.0.0"}}`,
+		"test/rate_limit.test.ts":    "import rateLimit from 'express-rate-limit'",
+		".docker/nginx/default.conf": "server { listen 80; }",
+	}
+	// A proxy in a hidden directory counts.
+	proxied := map[string]string{
+		".docker/nginx/default.conf": "limit_req_zone $binary_remote_addr zone=login:10m rate=5r/m;\nserver { location /login { limit_req zone=login; } }",
+	}
+	for name, body := range unlimited {
+		if _, ok := proxied[name]; !ok {
+			proxied[name] = body
+		}
+	}
+	devise := map[string]string{
+		"Gemfile":                        "source \"https://rubygems.org\"\ngem \"rails\"\ngem \"devise\"\n",
+		"app/controllers/application.rb": "class ApplicationController < ActionController::Base\nend\n",
+	}
+	throttled := map[string]string{
+		"app/controllers/sessions_controller.rb": "class SessionsController < ApplicationController\n  rate_limit to: 10, within: 3.minutes, only: :create\nend\n",
+	}
+	for name, body := range devise {
+		throttled[name] = body
+	}
+	// A rateLimit key in application code isn't Traefik; a middleware in
+	// Compose labels is.
+	appKey := map[string]string{"src/client.ts": "export const options = { rateLimit: false, retries: 3 }\n"}
+	labelled := map[string]string{
+		"docker-compose.yml": "services:\n  web:\n    labels:\n      - traefik.http.middlewares.login-limit.ratelimit.average=5\n",
+	}
+	for name, body := range devise {
+		appKey[name] = body
+		labelled[name] = body
+	}
+
+	cases := []struct {
+		name      string
+		files     map[string]string
+		passed    bool
+		message   string
+		want      []string
+		unwanted  []string
+		locations []string
+	}{
+		{
+			name:      "endpoints without a limiter",
+			files:     unlimited,
+			message:   "3 API and sign-in endpoints and no rate limiting",
+			want:      []string{"/api/projects (app/api/projects/route.ts)", "/login (app/login/page.tsx)", "an auth library (package.json)"},
+			unwanted:  []string{"/pricing"},
+			locations: []string{"package.json:1 - depends on an auth library"},
+		},
+		{
+			name:    "nginx limit_req",
+			files:   proxied,
+			passed:  true,
+			message: "nginx limit_req found for 3 API and sign-in endpoints",
+		},
+		{
+			name:      "Devise without a limiter",
+			files:     devise,
+			message:   "No rate limiting found for Devise",
+			locations: []string{"Gemfile:3 - depends on Devise"},
+		},
+		{
+			name:    "Rails rate_limit",
+			files:   throttled,
+			passed:  true,
+			message: "Rails rate_limit found for 1 API and sign-in endpoints",
+		},
+		{
+			name:      "rateLimit key in application code",
+			files:     appKey,
+			message:   "No rate limiting found for Devise",
+			locations: []string{"Gemfile:3 - depends on Devise"},
+		},
+		{
+			name:    "Traefik middleware in Compose labels",
+			files:   labelled,
+			passed:  true,
+			message: "Traefik rateLimit found for 1 API and sign-in endpoints",
+		},
+		{
+			name:    "no endpoints",
+			files:   map[string]string{"app/page.tsx": "", "app/about/page.tsx": ""},
+			passed:  true,
+			message: "No API or sign-in endpoints found",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := RateLimitCheck{}.Run(Context{RootDir: writeFiles(t, tc.files)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tc.passed || result.Message != tc.message {
+				t.Fatalf("got %v %q, want %v %q", result.Passed, result.Message, tc.passed, tc.message)
+			}
+			joined := strings.Join(result.Suggestions, "\n")
+			for _, want := range tc.want {
+				if !strings.Contains(joined, want) {
+					t.Errorf("suggestions missing %q:\n%s", want, joined)
+				}
+			}
+			for _, unwanted := range tc.unwanted {
+				if strings.Contains(joined, unwanted) {
+					t.Errorf("%s reported:\n%s", unwanted, joined)
+				}
+			}
+			var locations []string
+			for _, loc := range result.Locations {
+				locations = append(locations, loc.String())
+			}
+			if strings.Join(locations, "\n") != strings.Join(tc.locations, "\n") {
+				t.Errorf("locations = %v, want %v", locations, tc.locations)
+			}
+		})
+	}
+}
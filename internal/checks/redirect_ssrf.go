@@ -0,0 +1,143 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RedirectSSRFCheck flags the obvious cases of a request parameter going
+// straight into a redirect (open redirect) or into an outbound HTTP call
+// (SSRF). It's a line-by-line heuristic, not taint analysis: a value
+// copied into a variable first isn't followed, and a match may already be
+// validated elsewhere. Findings are for review before launch.
+type RedirectSSRFCheck struct{}
+
+func (c RedirectSSRFCheck) ID() string {
+	return "redirect_ssrf"
+}
+
+func (c RedirectSSRFCheck) Title() string {
+	return "Open redirect & SSRF patterns"
+}
+
+type urlSinkPattern struct {
+	pattern     *regexp.Regexp
+	description string
+	ssrf        bool
+	extensions  []string
+}
+
+var (
+	jsSinkExts  = []string{".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs"}
+	phpSinkExts = []string{".php"}
+	rbSinkExts  = []string{".rb"}
+	pySinkExts  = []string{".py"}
+	goSinkExts  = []string{".go"}
+)
+
+// urlSinkPatterns match request input passed directly to a redirect or a
+// fetch. Each input expression is the framework's usual way of reading a
+// query or body parameter.
+var urlSinkPatterns = []urlSinkPattern{
+	// Open redirects
+	{regexp.MustCompile(`\bres\.redirect\(\s*(?:\d{3}\s*,\s*)?req\.(?:query|body|params)\b`), "res.redirect(req.query...)", false, jsSinkExts},
+	{regexp.MustCompile(`\b(?:redirect|NextResponse\.redirect|permanentRedirect)\(\s*(?:new URL\(\s*)?(?:searchParams\.get\(|req\.query\b|request\.nextUrl\.searchParams\.get\(|url\.searchParams\.get\()`), "redirect(searchParams.get(...))", false, jsSinkExts},
+	{regexp.MustCompile(`\b(?:window\.)?location(?:\.href)?\s*=\s*(?:new URLSearchParams\([^)]*\)\.get\(|(?:url)?[sS]earchParams\.get\(|params\.get\(|router\.query\.)`), "location = query parameter", false, jsSinkExts},
+	{regexp.MustCompile(`\bredirect_to\s*\(?\s*params\[`), "redirect_to params[...]", false, rbSinkExts},
+	{regexp.MustCompile(`\b(?:redirect\(\)->(?:to|away)|redirect|Redirect::(?:to|away))\(\s*(?:\$request->(?:input|query|get)\(|request\(\s*['"]|Input::get\()`), "redirect($request->input(...))", false, phpSinkExts},
+	{regexp.MustCompile(`\bheader\(\s*["']Location:\s*["']\s*\.\s*\$_(?:GET|POST|REQUEST)\[`), `header("Location: " . $_GET[...])`, false, phpSinkExts},
+	{regexp.MustCompile(`\b(?:redirect|HttpResponseRedirect|RedirectResponse)\(\s*request\.(?:args|GET|POST|form|values|query_params)\b`), "redirect(request.args...)", false, pySinkExts},
+	{regexp.MustCompile(`\bhttp\.Redirect\([^,]+,[^,]+,\s*r\.(?:URL\.Query\(\)\.Get|FormValue|PostFormValue)\(`), "http.Redirect(w, r, r.FormValue(...))", false, goSinkExts},
+
+	// Server-side requests to user-supplied URLs
+	{regexp.MustCompile(`\b(?:fetch|axios(?:\.(?:get|post|head|request))?|got(?:\.(?:get|post))?|ky(?:\.get)?|needle\.get|superagent\.get)\(\s*(?:req\.(?:query|body|params)\b|searchParams\.get\(|request\.nextUrl\.searchParams\.get\()`), "fetch(req.query...)", true, jsSinkExts},
+	{regexp.MustCompile(`\b(?:Net::HTTP\.get(?:_response)?|URI\.open|HTTParty\.(?:get|post)|Faraday\.(?:get|post)|RestClient\.(?:get|post)|Typhoeus\.get)\(\s*(?:URI(?:\.parse)?\(\s*)?params\[`), "HTTP request to params[...]", true, rbSinkExts},
+	{regexp.MustCompile(`\b(?:file_get_contents|curl_init|fopen|Http::(?:get|post|head))\(\s*(?:\$_(?:GET|POST|REQUEST)\[|\$request->(?:input|query|get)\(|request\(\s*['"])`), "HTTP request to $request->input(...)", true, phpSinkExts},
+	{regexp.MustCompile(`\b(?:requests|httpx)\.(?:get|post|head|request)\(\s*request\.(?:args|GET|POST|form|json|values|query_params)\b|\burlopen\(\s*request\.(?:args|GET|POST|form)\b`), "requests.get(request.args...)", true, pySinkExts},
+	{regexp.MustCompile(`\bhttp\.(?:Get|Post|Head)\(\s*r\.(?:URL\.Query\(\)\.Get|FormValue|PostFormValue)\(`), "http.Get(r.FormValue(...))", true, goSinkExts},
+}
+
+// reURLGuard matches validation on the same line, which makes a match
+// likely safe.
+var reURLGuard = regexp.MustCompile(`(?i)allow(?:ed|list)|safe_?url|is_?safe|url_has_allowed_host_and_scheme|same_?origin|startsWith\(\s*["']/["']`)
+
+func (c RedirectSSRFCheck) Run(ctx Context) (CheckResult, error) {
+	files := ctx.files()
+	var findings []Location
+	var listed []string
+	redirects, fetches := 0, 0
+	for _, f := range files.Files {
+		if f.Ignored || f.Size > 500*1024 || f.inDir(templateSkipDirs) || isTestSource(f.Path) {
+			continue
+		}
+		var patterns []urlSinkPattern
+		for _, p := range urlSinkPatterns {
+			if hasExtension(f.Name, p.extensions) {
+				patterns = append(patterns, p)
+			}
+		}
+		if len(patterns) == 0 {
+			continue
+		}
+		content, err := readFile(files.Abs(f))
+		if err != nil {
+			continue
+		}
+		for i, line := range strings.Split(string(content), "\n") {
+			if reCommentLine.MatchString(line) {
+				continue
+			}
+			line = stripCodeComments(line)
+			if reURLGuard.MatchString(line) {
+				continue
+			}
+			for _, p := range patterns {
+				if !p.pattern.MatchString(line) {
+					continue
+				}
+				kind := "possible open redirect"
+				if p.ssrf {
+					kind = "possible SSRF"
+					fetches++
+				} else {
+					redirects++
+				}
+				loc := Location{File: f.Path, Line: i + 1, Message: kind + ": " + p.description}
+				findings = append(findings, loc)
+				listed = append(listed, loc.String())
+				break
+			}
+		}
+	}
+
+	if len(findings) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No request parameters passed straight to a redirect or fetch",
+		}, nil
+	}
+
+	var parts []string
+	if redirects > 0 {
+		parts = append(parts, fmt.Sprintf("%d redirects", redirects))
+	}
+	if fetches > 0 {
+		parts = append(parts, fmt.Sprintf("%d server-side fetches", fetches))
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  fmt.Sprintf("To review: %s built from request input", strings.Join(parts, " and ")),
+		Suggestions: append([]string{
+			"Review each: redirect only to relative paths or an allowlist of hosts",
+			"Fetch user-supplied URLs only after checking the host against an allowlist and refusing private and metadata addresses",
+		}, limitFindings(listed, 8)...),
+		Locations: findings,
+	}, nil
+}
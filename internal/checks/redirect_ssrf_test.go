@@ -0,0 +1,57 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestRedirectSSRFCheck(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"server/auth.js": "app.get('/login/done', (req, res) => {\n" +
+			"  res.redirect(req.query.next)\n" +
+			"})\n" +
+			"app.get('/go', (req, res) => res.redirect(isAllowedHost(req.query.to) ? req.query.to : '/'))\n" +
+			"// res.redirect(req.query.old)\n",
+		"app/api/preview/route.ts": "const res = await fetch(searchParams.get('url'))\n",
+		"app/controllers/sessions_controller.rb": "def create\n  redirect_to params[:return_to]\nend\n" +
+			"def proxy\n  HTTParty.get(params[:url])\nend\n",
+		"app/Http/Controllers/ImportController.php": "$body = file_get_contents($_GET['feed']);\nreturn redirect()->to($request->input('back'));\n",
+		"views.py":              "def go(request):\n    return redirect(request.GET.get('next'))\n",
+		"handlers/fetch.go":     "resp, err := http.Get(r.URL.Query().Get(\"url\"))\n",
+		"server/auth.test.js":   "res.redirect(req.query.next)\n",
+		"node_modules/x/app.js": "res.redirect(req.query.next)\n",
+		"server/safe.js":        "res.redirect('/dashboard')\n",
+	})
+	result, err := RedirectSSRFCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed {
+		t.Fatal("expected findings")
+	}
+	if result.Message != "To review: 4 redirects and 4 server-side fetches built from request input" {
+		t.Errorf("message = %q", result.Message)
+	}
+	got := map[string]bool{}
+	for _, loc := range result.Locations {
+		got[loc.String()] = true
+	}
+	for _, want := range []string{
+		"server/auth.js:2 - possible open redirect: res.redirect(req.query...)",
+		"app/api/preview/route.ts:1 - possible SSRF: fetch(req.query...)",
+		"app/controllers/sessions_controller.rb:2 - possible open redirect: redirect_to params[...]",
+		"app/controllers/sessions_controller.rb:5 - possible SSRF: HTTP request to params[...]",
+		"app/Http/Controllers/ImportController.php:1 - possible SSRF: HTTP request to $request->input(...)",
+		"app/Http/Controllers/ImportController.php:2 - possible open redirect: redirect($request->input(...))",
+		"views.py:2 - possible open redirect: redirect(request.args...)",
+		"handlers/fetch.go:1 - possible SSRF: http.Get(r.FormValue(...))",
+	} {
+		if !got[want] {
+			t.Errorf("missing %q in %v", want, result.Locations)
+		}
+	}
+	if len(result.Locations) != 8 {
+		t.Errorf("got %d locations, want 8: %v", len(result.Locations), result.Locations)
+	}
+}
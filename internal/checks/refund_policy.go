@@ -0,0 +1,170 @@
+package checks
+
+import (
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// RefundPolicyCheck looks for a refund or cancellation policy when a
+// payments service is declared. Stripe, Paddle, PayPal and the rest want
+// one (along with terms of service, which legal_pages covers) before they
+// enable live payments, and card networks expect it linked where
+// customers can find it. Like a11y_statement, it finds the page on the
+// live site or in the project, then checks the shared layout links to it.
+// A terms page with a refunds section counts too.
+type RefundPolicyCheck struct{}
+
+func (c RefundPolicyCheck) ID() string {
+	return "refund_policy"
+}
+
+func (c RefundPolicyCheck) Title() string {
+	return "Refund & cancellation policy"
+}
+
+// PaymentServices are the declarable services that take payments.
+var PaymentServices = []string{"stripe", "paypal", "braintree", "paddle", "lemonsqueezy"}
+
+// refundPolicyURLs are the paths a refund policy is usually served at;
+// refundPolicyPatterns are the matching page files.
+var (
+	refundPolicyURLs = []string{
+		"/refunds", "/refund", "/refund-policy", "/returns", "/return-policy",
+		"/cancellation", "/cancellation-policy", "/legal/refunds", "/legal/refund-policy",
+		"/policies/refund-policy", "/policies/refunds", "/legal/cancellation",
+	}
+	refundPolicyPatterns = []string{
+		"refunds", "refund", "refund-policy", "refund_policy", "returns", "return-policy",
+		"cancellation", "cancellation-policy", "legal/refunds", "legal/refund-policy",
+		"policies/refund-policy", "policies/refunds", "legal/cancellation",
+	}
+)
+
+// refundTarget matches a link target or route naming the refund policy.
+var refundTarget = regexp.MustCompile(`refund|returns?\b|return-policy|cancell?ation`)
+
+// refundTermsSection matches a refunds or cancellation section in terms
+// of service.
+var refundTermsSection = regexp.MustCompile(`(?im)(?:<h[1-6][^>]*>|^\s*#{1,6}\s|^\s*\d+\.\s)[^\n<]*(?:refund|cancell?ation)`)
+
+func (c RefundPolicyCheck) Run(ctx Context) (CheckResult, error) {
+	var declared []string
+	for _, id := range PaymentServices {
+		if ctx.Config.Services[id].Declared {
+			declared = append(declared, id)
+		}
+	}
+
+	var where string
+	baseURL := ctx.Config.URLs.Staging
+	if baseURL == "" {
+		baseURL = ctx.Config.URLs.Production
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if baseURL != "" && ctx.Client != nil {
+		if path := probeLegalPage(ctx, noRedirectClient(ctx.Client), baseURL, refundPolicyURLs, "refund", "return", "cancel"); path != "" {
+			where = path + " (via HTTP)"
+		}
+	}
+	if where == "" {
+		where = findLegalPageFile(ctx.RootDir, refundPolicyPatterns)
+	}
+	if where == "" {
+		if r, ok := findLegalRoute(ctx.RootDir, refundTarget); ok {
+			where = r.Path + " (route in " + r.Source + ")"
+		}
+	}
+	if where == "" {
+		if terms := refundSectionInTerms(ctx, baseURL); terms != "" {
+			return CheckResult{
+				ID:       c.ID(),
+				Title:    c.Title(),
+				Severity: SeverityInfo,
+				Passed:   true,
+				Message:  "Refunds covered in the terms at " + terms,
+			}, nil
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  "No refund or cancellation policy found (" + strings.Join(declared, ", ") + " declared)",
+			Suggestions: []string{
+				"Publish a refund and cancellation policy (e.g., /refunds): how to cancel, which charges are refundable and how long refunds take",
+				"Payment processors review it, with the terms of service, before enabling live mode",
+			},
+		}, nil
+	}
+
+	var linkedFrom string
+	var searched []string
+	for _, src := range sharedMarkup(ctx) {
+		searched = append(searched, src.name)
+		for _, target := range linkTargets(src.content) {
+			if refundTarget.MatchString(target) {
+				linkedFrom = src.name
+				break
+			}
+		}
+		if linkedFrom != "" {
+			break
+		}
+	}
+	if len(searched) > 0 && linkedFrom == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  "Refund policy at " + where + " isn't linked from the layout or footer",
+			Suggestions: []string{
+				"Link the refund policy from the footer every page shares, next to the terms",
+			},
+			Details: []string{"Looked in " + strings.Join(searched, ", ")},
+		}, nil
+	}
+
+	result := CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   true,
+		Message:  "Found refund policy at " + where,
+	}
+	if linkedFrom != "" {
+		result.Details = append(result.Details, "Linked from "+linkedFrom)
+	} else {
+		result.Details = append(result.Details, "No layout, footer or rendered homepage found to check for a link to it")
+	}
+	return result, nil
+}
+
+// refundSectionInTerms returns where the terms of service are when they
+// have a refunds or cancellation section, or "".
+func refundSectionInTerms(ctx Context, baseURL string) string {
+	if path := findLegalPageFile(ctx.RootDir, legalTermsPatterns); path != "" {
+		if data, err := readFile(filepath.Join(ctx.RootDir, path)); err == nil && refundTermsSection.Match(data) {
+			return path
+		}
+	}
+	if baseURL == "" || ctx.Client == nil {
+		return ""
+	}
+	for _, path := range []string{"/terms", "/terms-of-service", "/legal/terms"} {
+		resp, err := getWithContext(ctx.reqContext(), ctx.Client, baseURL+path)
+		if err != nil {
+			continue
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, netutil.MaxResponseBody))
+		resp.Body.Close()
+		if err == nil && resp.StatusCode < 300 && refundTermsSection.Match(body) {
+			return path + " (via HTTP)"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestRefundPolicyCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		passed  bool
+		message string
+	}{
+		{
+			name:    "missing",
+			files:   map[string]string{"index.html": `<a href="/terms">Terms</a>`, "terms.md": "# Terms\n\n## Payment\n\nYou'll be billed monthly.\n"},
+			passed:  false,
+			message: "No refund or cancellation policy found (stripe declared)",
+		},
+		{
+			name: "not linked",
+			files: map[string]string{
+				"index.html":   `<a href="/terms">Terms</a>`,
+				"refunds.html": `<p>Cancel any time from your account page.</p>`,
+			},
+			passed:  false,
+			message: "Refund policy at refunds.html isn't linked from the layout or footer",
+		},
+		{
+			name: "linked",
+			files: map[string]string{
+				"index.html":         `<footer><a href="/terms">Terms</a> <a href="/refund-policy">Refunds</a></footer>`,
+				"refund-policy.html": `<p>Cancel any time from your account page.</p>`,
+			},
+			passed:  true,
+			message: "Found refund policy at refund-policy.html",
+		},
+		{
+			name:    "section in terms",
+			files:   map[string]string{"terms.md": "# Terms\n\n## 4. Cancellation and refunds\n\nCancel any time.\n"},
+			passed:  true,
+			message: "Refunds covered in the terms at terms.md",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.PreflightConfig{Stack: "static", Services: map[string]config.ServiceConfig{"stripe": {Declared: true}}}
+			result, err := RefundPolicyCheck{}.Run(Context{RootDir: writeFiles(t, tt.files), Config: cfg})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tt.passed || result.Message != tt.message {
+				t.Errorf("passed=%v message=%q, want %v %q", result.Passed, result.Message, tt.passed, tt.message)
+			}
+		})
+	}
+}
+
+func TestRefundPolicyCheckLive(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/refunds":
+			http.Redirect(w, r, "/legal/refund-policy", http.StatusMovedPermanently)
+		case "/legal/refund-policy":
+			w.Write([]byte(`<h1>Refund policy</h1>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.PreflightConfig{Services: map[string]config.ServiceConfig{"paddle": {Declared: true}}}
+	cfg.URLs.Production = srv.URL
+	result, err := RefundPolicyCheck{}.Run(Context{
+		RootDir:  t.TempDir(),
+		Config:   cfg,
+		Client:   srv.Client(),
+		PageHTML: `<footer><a href="/refunds">Refunds</a></footer>`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || result.Message != "Found refund policy at /refunds (via HTTP)" {
+		t.Errorf("passed=%v message=%q", result.Passed, result.Message)
+	}
+}
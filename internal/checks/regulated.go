@@ -0,0 +1,168 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RegulatedGatingCheck looks for the gates a regulated product has to put
+// in front of visitors: an age gate for alcohol, tobacco, cannabis and
+// gambling, a region block for gambling (licences are per jurisdiction),
+// and an age screen or parental consent flow for a site directed at
+// children under COPPA. It runs when preflight.yml's compliance: names at
+// least one of them.
+//
+// Like the cookie consent checks, it goes by provider SDKs and scripts
+// plus the names gating code usually carries. It can show a gate is
+// missing, not that one works.
+type RegulatedGatingCheck struct{}
+
+func (c RegulatedGatingCheck) ID() string {
+	return "regulated_gating"
+}
+
+func (c RegulatedGatingCheck) Title() string {
+	return "Age & region gating"
+}
+
+// gatingControl is one kind of gate and how to recognise it.
+type gatingControl struct {
+	name string
+	// live patterns are matched against the rendered homepage, code
+	// patterns against dependency manifests, layouts and source.
+	live, code []*regexp.Regexp
+	suggestion string
+}
+
+// ageVerificationProviders are age and identity verification services
+// (AgeChecker.Net, Yoti, Veriff, Onfido, Jumio, Persona, Trulioo,
+// AgeChecked, AgeVerif) by their SDK packages and script hosts.
+var ageVerificationProviders = regexp.MustCompile(`(?i)agechecker\.net|\b(get)?yoti\b|@veriff/|veriff\.(com|me)|onfido|\bjumio\b|withpersona\.com|persona-(react|inquiry)|\btrulioo\b|agechecked|ageverif`)
+
+var ageGateControl = gatingControl{
+	name: "age gate",
+	live: []*regexp.Regexp{
+		ageVerificationProviders,
+		regexp.MustCompile(`(?i)age[-_]?(gate|verif|check)`),
+		regexp.MustCompile(`(?i)(are you|i am|i'm) (at least |over )?(18|19|21)\b`),
+		regexp.MustCompile(`(?i)of legal (drinking |smoking |gambling )?age`),
+	},
+	code: []*regexp.Regexp{
+		ageVerificationProviders,
+		regexp.MustCompile(`(?i)age[-_ ]?gate`),
+		regexp.MustCompile(`(?i)age[-_ ]?verif(y|ied|ication)|verify[-_ ]?age`),
+		regexp.MustCompile(`(?i)(are you|i am|i'm) (at least |over )?(18|19|21)\b`),
+		regexp.MustCompile(`(?i)of legal (drinking |smoking |gambling )?age`),
+	},
+	suggestion: "Add an age gate that asks for a date of birth before showing the site, or an age verification provider (AgeChecker.Net, Yoti, Veriff)",
+}
+
+var regionBlockControl = gatingControl{
+	name: "region block",
+	live: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)geocomply|xpoint`),
+	},
+	code: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)geocomply|xpoint`),
+		regexp.MustCompile(`(?i)geo[-_ ]?(block|fenc|restrict)`),
+		regexp.MustCompile(`(?i)(blocked|allowed|restricted|permitted)[-_ ]?(countries|regions|states|jurisdictions)`),
+		regexp.MustCompile(`(?i)cf-ipcountry|x-vercel-ip-country|request\.geo\b|cloudfront-viewer-country`),
+		regexp.MustCompile(`(?i)\bgeoip2?\b|maxmind|\bgeoip-lite\b`),
+	},
+	suggestion: "Block or redirect visitors from jurisdictions you aren't licensed in (a geolocation provider such as GeoComply, or the country header your CDN adds)",
+}
+
+var childConsentControl = gatingControl{
+	name: "age screen or parental consent",
+	live: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)parental[-_ ]?(consent|permission)`),
+		regexp.MustCompile(`(?i)\bprivo\b|superawesome|kidswebservices`),
+		regexp.MustCompile(`(?i)age[-_]?(gate|verif|check)`),
+	},
+	code: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)parental[-_ ]?(consent|permission)`),
+		regexp.MustCompile(`(?i)\bprivo\b|superawesome|kidswebservices`),
+		regexp.MustCompile(`(?i)age[-_ ]?(gate|screen|verif)`),
+		regexp.MustCompile(`(?i)date[-_ ]?of[-_ ]?birth|birth[-_ ]?date`),
+		ageVerificationProviders,
+	},
+	suggestion: "Ask for age neutrally before collecting personal data, and get verifiable parental consent for under-13s (a provider such as PRIVO or SuperAwesome)",
+}
+
+// regulatedVerticals maps each of config.ComplianceRegimes to the gates it
+// needs.
+var regulatedVerticals = map[string][]gatingControl{
+	"coppa":    {childConsentControl},
+	"gambling": {ageGateControl, regionBlockControl},
+	"alcohol":  {ageGateControl},
+	"tobacco":  {ageGateControl},
+	"cannabis": {ageGateControl},
+}
+
+func (c RegulatedGatingCheck) Run(ctx Context) (CheckResult, error) {
+	if len(ctx.Config.Compliance) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No regulated verticals declared, skipping",
+		}, nil
+	}
+
+	// Several regimes share a gate, so each is looked for once and a
+	// missing one lists every regime that needs it.
+	var order []gatingControl
+	needs := map[string][]string{}
+	for _, regime := range ctx.Config.Compliance {
+		for _, control := range regulatedVerticals[regime] {
+			if _, ok := needs[control.name]; !ok {
+				order = append(order, control)
+			}
+			needs[control.name] = append(needs[control.name], regime)
+		}
+	}
+	var details, missing, suggestions []string
+	for _, control := range order {
+		if where := findGatingControl(ctx, control); where != "" {
+			details = append(details, fmt.Sprintf("%s found %s", control.name, where))
+			continue
+		}
+		missing = append(missing, fmt.Sprintf("%s (%s)", control.name, strings.Join(needs[control.name], ", ")))
+		suggestions = append(suggestions, control.suggestion)
+	}
+
+	if len(missing) > 0 {
+		return CheckResult{
+			ID:          c.ID(),
+			Title:       c.Title(),
+			Severity:    SeverityWarn,
+			Passed:      false,
+			Message:     "No " + strings.Join(missing, ", ") + " found",
+			Suggestions: suggestions,
+			Details:     details,
+		}, nil
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   true,
+		Message:  "Gating found for " + strings.Join(ctx.Config.Compliance, ", "),
+		Details:  details,
+	}, nil
+}
+
+// findGatingControl returns where control was seen, or "" when it wasn't.
+func findGatingControl(ctx Context, control gatingControl) string {
+	for _, pattern := range control.live {
+		if ctx.PageHTML != "" && pattern.MatchString(ctx.PageHTML) {
+			return "on the live site"
+		}
+	}
+	if match := searchForPatternsWithDetails(ctx, control.code); match != nil {
+		return "in " + match.FilePath
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package checks
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestRegulatedGatingCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		compliance []string
+		files      map[string]string
+		html       string
+		passed     bool
+		message    string
+	}{
+		{
+			name:       "alcohol with an age gate component",
+			compliance: []string{"alcohol"},
+			files: map[string]string{
+				"src/components/AgeGate.tsx": "export function AgeGate() { return <p>Are you 21 or over?</p> }\n",
+			},
+			passed: true,
+		},
+		{
+			name:       "gambling missing a region block",
+			compliance: []string{"gambling", "alcohol"},
+			files: map[string]string{
+				"package.json": `{"dependencies": {"@getyoti/react-face-capture": "1.0.0"}}`,
+			},
+			passed:  false,
+			message: "No region block (gambling) found",
+		},
+		{
+			name:       "gambling gated by the CDN country header",
+			compliance: []string{"gambling"},
+			files: map[string]string{
+				"middleware.ts": "const country = req.headers.get('cf-ipcountry')\nif (blockedCountries.includes(country)) {}\n",
+			},
+			html:   `<script src="https://agechecker.net/static/popup/v1/popup.js"></script>`,
+			passed: true,
+		},
+		{
+			name:       "nothing found lists every vertical",
+			compliance: []string{"tobacco", "cannabis", "coppa"},
+			files:      map[string]string{"index.js": "// age gate goes here\nconsole.log('hi')\n"},
+			passed:     false,
+			message:    "No age gate (tobacco, cannabis), age screen or parental consent (coppa) found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, tt.files)
+			result, err := RegulatedGatingCheck{}.Run(Context{
+				RootDir:  dir,
+				Config:   &config.PreflightConfig{Compliance: tt.compliance},
+				PageHTML: tt.html,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tt.passed {
+				t.Fatalf("passed = %v, want %v (%q)", result.Passed, tt.passed, result.Message)
+			}
+			if tt.message != "" && result.Message != tt.message {
+				t.Errorf("message = %q, want %q", result.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestRegulatedVerticalsCoverConfig(t *testing.T) {
+	for _, regime := range config.ComplianceRegimes {
+		if len(regulatedVerticals[regime]) == 0 {
+			t.Errorf("compliance regime %q has no gates", regime)
+		}
+	}
+	for regime := range regulatedVerticals {
+		if !slices.Contains(config.ComplianceRegimes, regime) {
+			t.Errorf("%q isn't in config.ComplianceRegimes", regime)
+		}
+	}
+}
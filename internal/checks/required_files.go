@@ -0,0 +1,90 @@
+package checks
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RequiredFilesCheck verifies the files listed under
+// checks.requiredFiles exist and have content, for launch requirements a
+// team keeps in the repo: a runbook, SECURITY.md, an on-call doc. Each
+// entry fails with its own severity; the check reports the most severe.
+type RequiredFilesCheck struct{}
+
+func (c RequiredFilesCheck) ID() string {
+	return "required_files"
+}
+
+func (c RequiredFilesCheck) Title() string {
+	return "Required files"
+}
+
+func (c RequiredFilesCheck) Run(ctx Context) (CheckResult, error) {
+	files := ctx.Config.Checks.RequiredFiles
+	var findings []string
+	severity := SeverityWarn
+	for _, f := range files {
+		problem := requiredFileProblem(filepath.Join(ctx.RootDir, filepath.FromSlash(f.Path)))
+		if problem == "" {
+			continue
+		}
+		if f.Severity == "error" {
+			severity = SeverityError
+		}
+		findings = append(findings, f.Path+" - "+problem)
+	}
+
+	if len(findings) == 0 {
+		message := fmt.Sprintf("All %d required files present", len(files))
+		if len(files) == 1 {
+			message = files[0].Path + " present"
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  message,
+		}, nil
+	}
+
+	message := fmt.Sprintf("%d of %d required files missing or empty", len(findings), len(files))
+	if len(findings) == 1 {
+		message = strings.Replace(findings[0], " - ", " ", 1)
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: severity,
+		Passed:   false,
+		Message:  message,
+		Suggestions: append([]string{
+			"Write the missing files before launch, or drop entries that no longer apply from checks.requiredFiles",
+		}, limitFindings(findings, 8)...),
+	}, nil
+}
+
+// requiredFileProblem says why the file at path doesn't meet the
+// requirement, or returns "" when it does.
+func requiredFileProblem(path string) string {
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		return "not found"
+	case err != nil:
+		return "could not be read"
+	case info.IsDir():
+		return "is a directory, not a file"
+	}
+	content, err := readFile(path)
+	if err != nil {
+		return "could not be read"
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return "is empty"
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestRequiredFilesCheck(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"docs/runbook.md": "# Runbook\n\nRestart with `make restart`.\n",
+		"ops/oncall.md":   "  \n\n",
+		"SECURITY.md/x":   "not a file",
+	})
+	run := func(files ...config.RequiredFile) CheckResult {
+		t.Helper()
+		cfg := &config.PreflightConfig{}
+		cfg.Checks.RequiredFiles = files
+		result, err := RequiredFilesCheck{}.Run(Context{RootDir: dir, Config: cfg})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	if result := run(config.RequiredFile{Path: "docs/runbook.md"}); !result.Passed || result.Message != "docs/runbook.md present" {
+		t.Errorf("got passed=%v message=%q", result.Passed, result.Message)
+	}
+
+	result := run(
+		config.RequiredFile{Path: "docs/runbook.md"},
+		config.RequiredFile{Path: "ops/oncall.md"},
+		config.RequiredFile{Path: "docs/launch.md"},
+	)
+	if result.Passed || result.Severity != SeverityWarn || result.Message != "2 of 3 required files missing or empty" {
+		t.Errorf("got passed=%v severity=%s message=%q", result.Passed, result.Severity, result.Message)
+	}
+	joined := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{"ops/oncall.md - is empty", "docs/launch.md - not found"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+
+	result = run(config.RequiredFile{Path: "ops/oncall.md"}, config.RequiredFile{Path: "SECURITY.md", Severity: "error"})
+	if result.Severity != SeverityError {
+		t.Errorf("severity = %s, want error", result.Severity)
+	}
+	if !strings.Contains(strings.Join(result.Suggestions, "\n"), "SECURITY.md - is a directory") {
+		t.Errorf("suggestions = %q", result.Suggestions)
+	}
+}
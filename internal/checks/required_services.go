@@ -0,0 +1,102 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RequiredServicesCheck enforces the `require:` groups in preflight.yml:
+// each group names interchangeable providers (error_tracking: [sentry,
+// bugsnag]) and is satisfied when at least one of them is declared and its
+// own integration check did not fail. It judges the results of the checks
+// that ran before it, so the scan runs it last with Prior filled in.
+type RequiredServicesCheck struct {
+	// Prior holds every result produced earlier in the scan.
+	Prior []CheckResult
+}
+
+func (c RequiredServicesCheck) ID() string {
+	return "required_services"
+}
+
+func (c RequiredServicesCheck) Title() string {
+	return "Required services"
+}
+
+func (c RequiredServicesCheck) Run(ctx Context) (CheckResult, error) {
+	groups := ctx.Config.Require
+	if len(groups) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No required service groups configured, skipping",
+		}, nil
+	}
+
+	priorByID := make(map[string]CheckResult, len(c.Prior))
+	for _, r := range c.Prior {
+		priorByID[r.ID] = r
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var covered, missing, suggestions, details []string
+	for _, name := range names {
+		providers := groups[name]
+		var satisfiedBy string
+		var failing []string
+		for _, id := range providers {
+			if !ctx.Config.Services[id].Declared {
+				continue
+			}
+			// A declared provider whose check didn't run this time
+			// (ignored, --skip, or no integration check exists) is
+			// taken at its declaration.
+			if r, ran := priorByID[id]; ran && !r.Passed {
+				failing = append(failing, id)
+				details = append(details, fmt.Sprintf("%s: %s is declared but its check failed: %s", name, id, r.Message))
+				continue
+			}
+			satisfiedBy = id
+			break
+		}
+
+		if satisfiedBy != "" {
+			covered = append(covered, fmt.Sprintf("%s: %s", name, satisfiedBy))
+			continue
+		}
+		missing = append(missing, name)
+		if len(failing) > 0 {
+			suggestions = append(suggestions, fmt.Sprintf("%s: fix the %s integration (see its check above)", name, strings.Join(failing, "/")))
+		} else {
+			suggestions = append(suggestions, fmt.Sprintf("%s: integrate one of %s and declare it under services:", name, strings.Join(providers, ", ")))
+		}
+	}
+
+	if len(missing) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "All required groups covered (" + strings.Join(covered, ", ") + ")",
+		}, nil
+	}
+
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    SeverityError,
+		Passed:      false,
+		Message:     fmt.Sprintf("No working provider for %d required group(s): %s", len(missing), strings.Join(missing, ", ")),
+		Suggestions: suggestions,
+		Details:     details,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestRequiredServicesCheck(t *testing.T) {
+	cfg := &config.PreflightConfig{
+		Services: map[string]config.ServiceConfig{
+			"sentry":    {Declared: true},
+			"bugsnag":   {Declared: true},
+			"plausible": {Declared: true},
+		},
+		Require: map[string][]string{
+			// sentry's check failed, bugsnag's passed: covered.
+			"error_tracking": {"sentry", "bugsnag"},
+			// plausible never ran (e.g. --skip): taken at its declaration.
+			"analytics": {"fathom", "plausible"},
+		},
+	}
+	prior := []CheckResult{
+		{ID: "sentry", Passed: false, Message: "DSN not found"},
+		{ID: "bugsnag", Passed: true},
+	}
+
+	result, err := RequiredServicesCheck{Prior: prior}.Run(Context{Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed {
+		t.Fatalf("expected pass, got %q %v", result.Message, result.Suggestions)
+	}
+	if !strings.Contains(result.Message, "error_tracking: bugsnag") || !strings.Contains(result.Message, "analytics: plausible") {
+		t.Errorf("message = %q, want each group's provider", result.Message)
+	}
+
+	// Only sentry declared, and failing; no payments provider at all.
+	cfg.Services = map[string]config.ServiceConfig{"sentry": {Declared: true}}
+	cfg.Require = map[string][]string{
+		"error_tracking": {"sentry", "rollbar"},
+		"payments":       {"stripe", "paddle"},
+	}
+	result, _ = RequiredServicesCheck{Prior: prior}.Run(Context{Config: cfg})
+	if result.Passed || result.Severity != SeverityError {
+		t.Fatalf("expected an error, got passed=%v severity=%s", result.Passed, result.Severity)
+	}
+	if !strings.Contains(result.Message, "2 required group(s): error_tracking, payments") {
+		t.Errorf("message = %q", result.Message)
+	}
+	joined := strings.Join(result.Suggestions, "\n")
+	if !strings.Contains(joined, "fix the sentry integration") || !strings.Contains(joined, "integrate one of stripe, paddle") {
+		t.Errorf("suggestions = %v", result.Suggestions)
+	}
+}
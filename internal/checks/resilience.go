@@ -0,0 +1,203 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ResilienceCheck inventories the payment, auth and email APIs the code
+// calls at runtime and looks for timeout and retry handling around them.
+// A provider outage should cost a checkout or a signup a fast error, not a
+// request that hangs until the load balancer gives up. The search is a
+// heuristic: an integration counts as guarded when a file that sets up or
+// calls the client also mentions a timeout.
+type ResilienceCheck struct{}
+
+func (c ResilienceCheck) ID() string {
+	return "resilience"
+}
+
+func (c ResilienceCheck) Title() string {
+	return "Third-party call timeouts"
+}
+
+// runtimeDependency is a third-party API the app calls while serving
+// requests. patterns match client construction, SDK imports and raw
+// calls to the API host.
+type runtimeDependency struct {
+	id       string
+	category string
+	patterns *regexp.Regexp
+	// hint is how the provider's SDK takes a timeout, for the suggestion.
+	hint string
+}
+
+var runtimeDependencies = []runtimeDependency{
+	// Payments
+	{"stripe", "payments", regexp.MustCompile(`new Stripe\(|require\(['"]stripe['"]\)|from ['"]stripe['"]|\bStripe::|\\Stripe\\|stripe\.api_key|stripe-go|api\.stripe\.com`),
+		"new Stripe(key, { timeout: 10000, maxNetworkRetries: 2 })"},
+	{"paypal", "payments", regexp.MustCompile(`@paypal/(checkout-server-sdk|paypal-server-sdk)|paypalrestsdk|PayPalHttpClient|api-m\.(sandbox\.)?paypal\.com`),
+		"pass a timeout to the PayPal client's HTTP options"},
+	{"braintree", "payments", regexp.MustCompile(`braintree\.BraintreeGateway|new braintree\.|Braintree::Gateway|Braintree\\Gateway`),
+		"set timeout on the Braintree gateway configuration"},
+	{"paddle", "payments", regexp.MustCompile(`@paddle/paddle-node-sdk|new Paddle\(|api\.paddle\.com`),
+		"wrap Paddle calls with an AbortSignal.timeout()"},
+	{"lemonsqueezy", "payments", regexp.MustCompile(`@lemonsqueezy/lemonsqueezy\.js|api\.lemonsqueezy\.com`),
+		"wrap Lemon Squeezy calls with an AbortSignal.timeout()"},
+	// Auth
+	{"auth0", "auth", regexp.MustCompile(`new (ManagementClient|AuthenticationClient)\(|Auth0\\|\.auth0\.com/(oauth|api)`),
+		"pass timeoutDuration to the Auth0 client"},
+	{"clerk", "auth", regexp.MustCompile(`createClerkClient\(|@clerk/backend|api\.clerk\.(com|dev)`),
+		"wrap Clerk backend calls with an AbortSignal.timeout()"},
+	{"workos", "auth", regexp.MustCompile(`new WorkOS\(|WorkOS::|api\.workos\.com`),
+		"set timeout on the WorkOS client"},
+	{"firebase", "auth", regexp.MustCompile(`firebase-admin|firebase_admin`),
+		"set httpAgent timeouts on the Firebase Admin app"},
+	{"supabase", "auth", regexp.MustCompile(`@supabase/supabase-js|supabase-py|\.supabase\.co\b`),
+		"pass a fetch with AbortSignal.timeout() in the Supabase client options"},
+	// Email
+	{"postmark", "email", regexp.MustCompile(`new (postmark\.)?ServerClient\(|Postmark::ApiClient|postmarker|api\.postmarkapp\.com`),
+		"set timeout on the Postmark client"},
+	{"sendgrid", "email", regexp.MustCompile(`@sendgrid/mail|SendGridAPIClient|SendGrid::API|api\.sendgrid\.com`),
+		"call setTimeout() on the SendGrid client"},
+	{"mailgun", "email", regexp.MustCompile(`mailgun\.client\(|mailgun\.js|Mailgun::Client|api\.(eu\.)?mailgun\.net`),
+		"set timeout on the Mailgun client"},
+	{"resend", "email", regexp.MustCompile(`new Resend\(|Resend\.api_key|api\.resend\.com`),
+		"wrap Resend calls with an AbortSignal.timeout()"},
+	{"aws_ses", "email", regexp.MustCompile(`\bSES(v2)?Client\(|Aws::SES|client\(['"]ses['"]`),
+		"set requestHandler / http_options timeouts on the SES client"},
+}
+
+var (
+	// The global setTimeout is a UI timer, not a request timeout, so a
+	// bare "Timeout" only counts after a word boundary or underscore
+	// (timeout:, read_timeout, Timeout:), or as a client method.
+	reTimeoutHandling = regexp.MustCompile(`(?i)(\b|_)timeout|\.setTimeout\(|WithTimeout|WithDeadline|AbortSignal|AbortController`)
+	reRetryHandling   = regexp.MustCompile(`(?i)retr(y|ies)|backoff|circuit.?breaker|fallback|tenacity`)
+)
+
+// resilienceExts are the source files runtime API calls live in.
+var resilienceExts = map[string]bool{
+	".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true, ".cjs": true,
+	".py": true, ".rb": true, ".php": true, ".go": true, ".java": true, ".kt": true,
+	".ex": true, ".exs": true, ".cs": true, ".rs": true,
+}
+
+// dependencyUse is what the scan saw of one provider.
+type dependencyUse struct {
+	dep     runtimeDependency
+	first   Location
+	timeout bool
+	retry   bool
+}
+
+func (c ResilienceCheck) Run(ctx Context) (CheckResult, error) {
+	files := ctx.files()
+	uses := map[string]*dependencyUse{}
+	for _, f := range files.Files {
+		if f.Ignored || !resilienceExts[f.Ext] || f.Size > 500*1024 || f.inDir(templateSkipDirs) || isTestSource(f.Path) {
+			continue
+		}
+		// Hidden directories hold build caches (.next, .svelte-kit) whose
+		// bundles inline the SDKs themselves.
+		if strings.HasPrefix(f.Path, ".") || strings.Contains(f.Path, "/.") {
+			continue
+		}
+		content, err := readFile(files.Abs(f))
+		if err != nil {
+			continue
+		}
+		var code string // comments stripped, computed once a provider matches
+		for _, dep := range runtimeDependencies {
+			loc := dep.patterns.FindIndex(content)
+			if loc == nil {
+				continue
+			}
+			if code == "" {
+				code = stripComments(string(content))
+			}
+			use := uses[dep.id]
+			if use == nil {
+				use = &dependencyUse{dep: dep, first: Location{File: f.Path, Line: lineAt(content, loc[0])}}
+				uses[dep.id] = use
+			}
+			use.timeout = use.timeout || reTimeoutHandling.MatchString(code)
+			use.retry = use.retry || reRetryHandling.MatchString(code)
+		}
+	}
+
+	if len(uses) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No payment, auth or email API clients found",
+		}, nil
+	}
+
+	ids := make([]string, 0, len(uses))
+	for id := range uses {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var unguarded, suggestions, details []string
+	var locations []Location
+	for _, id := range ids {
+		use := uses[id]
+		retry := "retries or fallback seen"
+		if !use.retry {
+			retry = "no retries or fallback seen"
+		}
+		if use.timeout {
+			details = append(details, fmt.Sprintf("%s (%s) - %s: timeout set, %s", id, use.dep.category, use.first.File, retry))
+			continue
+		}
+		unguarded = append(unguarded, id)
+		suggestions = append(suggestions, fmt.Sprintf("%s (%s) - %s: no timeout, %s; e.g. %s", id, use.dep.category, use.first.String(), retry, use.dep.hint))
+		loc := use.first
+		loc.Message = id + " client calls have no visible timeout"
+		locations = append(locations, loc)
+	}
+
+	if len(unguarded) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("All %d third-party integration(s) set a timeout", len(ids)),
+			Details:  details,
+		}, nil
+	}
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    SeverityWarn,
+		Passed:      false,
+		Message:     fmt.Sprintf("%d of %d third-party integration(s) have no visible timeout: %s", len(unguarded), len(ids), strings.Join(unguarded, ", ")),
+		Suggestions: limitFindings(suggestions, 8),
+		Details:     details,
+		Locations:   locations,
+	}, nil
+}
+
+// isTestSource reports whether a project path is a test or fixture,
+// where client calls are mocked and timeouts don't matter.
+func isTestSource(p string) bool {
+	lower := strings.ToLower(p)
+	for _, part := range strings.Split(lower, "/") {
+		switch part {
+		case "test", "tests", "__tests__", "spec", "specs", "fixtures", "__mocks__", "e2e":
+			return true
+		}
+	}
+	for _, marker := range []string{".test.", ".spec.", "_test.", "_spec."} {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestResilienceCheck(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"lib/stripe.ts": "import Stripe from 'stripe'\n" +
+			"export const stripe = new Stripe(process.env.STRIPE_KEY, { timeout: 10000, maxNetworkRetries: 2 })\n",
+		"lib/mail.js": "const sgMail = require('@sendgrid/mail')\n" +
+			"// TODO: add a timeout\n" +
+			"setTimeout(flushQueue, 1000)\n" +
+			"sgMail.send(msg)\n",
+		"tests/auth.test.ts":         "const auth0 = new ManagementClient({ domain })\n",
+		".next/server/chunks/app.js": "new Resend(key)\n",
+	})
+	result, err := ResilienceCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed || result.Severity != SeverityWarn {
+		t.Fatalf("got passed=%v severity=%v, want a warning", result.Passed, result.Severity)
+	}
+	if result.Message != "1 of 2 third-party integration(s) have no visible timeout: sendgrid" {
+		t.Errorf("message = %q", result.Message)
+	}
+	if len(result.Locations) != 1 || result.Locations[0].File != "lib/mail.js" || result.Locations[0].Line != 1 {
+		t.Errorf("locations = %+v", result.Locations)
+	}
+	details := strings.Join(result.Details, "\n")
+	if !strings.Contains(details, "stripe (payments) - lib/stripe.ts: timeout set, retries or fallback seen") {
+		t.Errorf("details missing guarded stripe client:\n%s", details)
+	}
+	if strings.Contains(details, "auth0") || strings.Contains(details, "resend") {
+		t.Errorf("tests and build caches should be skipped:\n%s", details)
+	}
+}
+
+func TestResilienceCheckNoDependencies(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"index.js": "console.log('hi')\n"})
+	result, err := ResilienceCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed {
+		t.Errorf("expected pass with no integrations, got %q", result.Message)
+	}
+}
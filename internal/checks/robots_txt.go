@@ -0,0 +1,252 @@
+package checks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// maxRobotsSitemaps bounds how many Sitemap: lines are fetched.
+const maxRobotsSitemaps = 5
+
+// robotsGroup is one User-agent group of a robots.txt: the agents its
+// consecutive User-agent lines name, and the rules that follow them.
+type robotsGroup struct {
+	agents   []string
+	allow    []string
+	disallow []string
+}
+
+// robotsTxt is a parsed robots.txt. problems lists the lines crawlers
+// would ignore or misread, with their line numbers.
+type robotsTxt struct {
+	groups   []robotsGroup
+	sitemaps []string
+	problems []string
+}
+
+// robotsExtensions are directives outside RFC 9309 that some crawlers
+// honour (Yandex's Host and Clean-param, Cloudflare's Content-Signal),
+// so they aren't reported as unknown.
+var robotsExtensions = map[string]bool{
+	"host": true, "clean-param": true, "request-rate": true, "visit-time": true,
+	"content-signal": true, "noindex": true,
+}
+
+// parseRobotsTxt parses body by RFC 9309's rules: a group starts at a
+// User-agent line following a rule, and fields are case-insensitive.
+func parseRobotsTxt(body string) robotsTxt {
+	var robots robotsTxt
+	var group *robotsGroup
+	rules := false
+	for i, line := range strings.Split(strings.TrimPrefix(body, "\ufeff"), "\n") {
+		n := i + 1
+		if hash := strings.IndexByte(line, '#'); hash >= 0 {
+			line = line[:hash]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			robots.problems = append(robots.problems, fmt.Sprintf("line %d: %q isn't a directive (want Field: value)", n, line))
+			continue
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		value = strings.TrimSpace(value)
+
+		switch field {
+		case "user-agent":
+			if group == nil || rules {
+				robots.groups = append(robots.groups, robotsGroup{})
+				group = &robots.groups[len(robots.groups)-1]
+				rules = false
+			}
+			if value == "" {
+				robots.problems = append(robots.problems, fmt.Sprintf("line %d: User-agent has no value", n))
+				continue
+			}
+			group.agents = append(group.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			if group == nil {
+				robots.problems = append(robots.problems, fmt.Sprintf("line %d: %s comes before any User-agent line, so crawlers ignore it", n, line))
+				continue
+			}
+			rules = true
+			if value != "" && !strings.HasPrefix(value, "/") && !strings.HasPrefix(value, "*") {
+				robots.problems = append(robots.problems, fmt.Sprintf("line %d: %s path %q should start with /", n, field, value))
+			}
+			if field == "allow" {
+				group.allow = append(group.allow, value)
+			} else {
+				group.disallow = append(group.disallow, value)
+			}
+		case "sitemap":
+			if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				robots.problems = append(robots.problems, fmt.Sprintf("line %d: Sitemap must be a full URL, got %q", n, value))
+				continue
+			}
+			robots.sitemaps = append(robots.sitemaps, value)
+		case "crawl-delay":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				robots.problems = append(robots.problems, fmt.Sprintf("line %d: Crawl-delay %q isn't a number", n, value))
+			}
+		default:
+			if !robotsExtensions[field] {
+				robots.problems = append(robots.problems, fmt.Sprintf("line %d: unknown directive %q", n, field))
+			}
+		}
+	}
+	return robots
+}
+
+// blockedCrawlers returns the agents among *, Googlebot and Bingbot whose
+// group disallows the whole site with nothing allowed back. Blocking
+// only AI or SEO-tool crawlers this way is common and intended.
+func (r robotsTxt) blockedCrawlers() []string {
+	var blocked []string
+	for _, g := range r.groups {
+		if len(g.allow) > 0 || !(slices.Contains(g.disallow, "/") || slices.Contains(g.disallow, "/*")) {
+			continue
+		}
+		for _, agent := range g.agents {
+			switch agent {
+			case "*", "googlebot", "bingbot":
+				blocked = append(blocked, agent)
+			}
+		}
+	}
+	return blocked
+}
+
+// robotsFetch is the production robots.txt response.
+type robotsFetch struct {
+	url    string
+	status int
+	html   bool
+	body   string
+}
+
+func fetchRobotsTxt(ctx Context, robotsURL string) (robotsFetch, error) {
+	resp, actualURL, err := tryURL(ctx.reqContext(), ctx.Client, robotsURL)
+	if err != nil {
+		return robotsFetch{}, err
+	}
+	defer resp.Body.Close()
+	fetch := robotsFetch{url: actualURL, status: resp.StatusCode}
+	if resp.StatusCode != http.StatusOK {
+		return fetch, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, netutil.MaxResponseBody))
+	if err != nil {
+		return robotsFetch{}, err
+	}
+	fetch.body = string(body)
+	lower := strings.ToLower(strings.TrimSpace(fetch.body))
+	fetch.html = strings.Contains(strings.ToLower(resp.Header.Get("Content-Type")), "text/html") ||
+		strings.HasPrefix(lower, "<!doctype html") || strings.HasPrefix(lower, "<html")
+	return fetch, nil
+}
+
+// checkLive fetches robots.txt from the production site at base and
+// validates it: a file in the repo doesn't mean the deployed one is sane.
+// It reports a file production doesn't serve, a Disallow: / that hides
+// the site from search engines, lines crawlers would misread, and Sitemap
+// URLs that don't resolve. repo is the result of the repository search,
+// returned as is when production can't be reached.
+func (c RobotsTxtCheck) checkLive(ctx Context, base string, repo CheckResult) CheckResult {
+	robotsURL := strings.TrimSuffix(base, "/") + "/robots.txt"
+	fetch, err := fetchRobotsTxt(ctx, robotsURL)
+	if err != nil {
+		// healthEndpoint reports a site that's down.
+		repo.Details = append(repo.Details, "Could not fetch the production robots.txt: "+err.Error())
+		return repo
+	}
+
+	if fetch.status != http.StatusOK || fetch.html {
+		message := fmt.Sprintf("robots.txt not served on production (%s returned HTTP %d)", robotsURL, fetch.status)
+		if fetch.html {
+			message = fmt.Sprintf("robots.txt not served on production (%s returned an HTML page)", robotsURL)
+		}
+		suggestions := []string{"Add robots.txt to public/ directory"}
+		if repo.Passed {
+			suggestions = []string{"Check that the build deploys it (" + repo.Message + ")"}
+		}
+		return CheckResult{
+			ID:          c.ID(),
+			Title:       c.Title(),
+			Severity:    SeverityWarn,
+			Passed:      false,
+			Message:     message,
+			Suggestions: suggestions,
+		}
+	}
+
+	robots := parseRobotsTxt(fetch.body)
+	problems := append([]string(nil), robots.problems...)
+	sitemaps := robots.sitemaps
+	if len(sitemaps) > maxRobotsSitemaps {
+		sitemaps = sitemaps[:maxRobotsSitemaps]
+	}
+	for _, sitemap := range sitemaps {
+		if problem := checkRobotsSitemap(ctx, sitemap); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	if blocked := robots.blockedCrawlers(); len(blocked) > 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityError,
+			Passed:   false,
+			Message:  fmt.Sprintf("Production robots.txt blocks the whole site (Disallow: / for %s)", strings.Join(blocked, ", ")),
+			Suggestions: append([]string{
+				"Remove Disallow: / from the robots.txt production serves; a staging robots.txt is often deployed by mistake",
+			}, limitFindings(problems, 8)...),
+		}
+	}
+	if len(problems) > 0 {
+		return CheckResult{
+			ID:          c.ID(),
+			Title:       c.Title(),
+			Severity:    SeverityWarn,
+			Passed:      false,
+			Message:     fmt.Sprintf("Production robots.txt has %d problem(s)", len(problems)),
+			Suggestions: limitFindings(problems, 8),
+		}
+	}
+
+	message := "robots.txt served at " + fetch.url
+	if len(sitemaps) > 0 {
+		message += fmt.Sprintf(", %d sitemap(s) reachable", len(sitemaps))
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   true,
+		Message:  message,
+	}
+}
+
+// checkRobotsSitemap fetches a Sitemap: URL and describes what's wrong
+// with it, or returns "".
+func checkRobotsSitemap(ctx Context, sitemapURL string) string {
+	resp, err := doGet(ctx.reqContext(), ctx.Client, sitemapURL)
+	if err != nil {
+		return fmt.Sprintf("Sitemap %s is unreachable: %v", sitemapURL, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Sprintf("Sitemap %s returns HTTP %d", sitemapURL, resp.StatusCode)
+	}
+	return ""
+}
@@ -0,0 +1,252 @@
+package checks
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RollbackCheck reads the deploy configuration for a way back from a bad
+// release: previous releases kept by Capistrano, Deployer or Kamal, a
+// platform with instant rollback (Vercel, Netlify, Heroku, Fly.io), or a
+// Kubernetes Deployment with revision history. Configs keeping no previous
+// release, and scripts deploying in place with git pull or rsync, are
+// one-way.
+type RollbackCheck struct{}
+
+func (c RollbackCheck) ID() string {
+	return "rollback"
+}
+
+func (c RollbackCheck) Title() string {
+	return "Deploy rollback"
+}
+
+var (
+	// reKeepReleases matches Capistrano's and Deployer's keep_releases
+	// setting, in Ruby, PHP and YAML.
+	reKeepReleases = regexp.MustCompile(`(?m)^[^#\n]*\bkeep_releases['"]?\s*[,:=]\s*(-?\d+)`)
+	// reKamalRetain matches Kamal's retain_containers setting.
+	reKamalRetain = regexp.MustCompile(`(?m)^\s*retain_containers:\s*(\d+)`)
+	// reManifestKind matches the kind of a Kubernetes manifest that
+	// rolls out pods.
+	reManifestKind = regexp.MustCompile(`(?m)^kind:\s*(Deployment|Rollout)\s*$`)
+	// reRevisionHistory matches a Deployment's revisionHistoryLimit.
+	reRevisionHistory = regexp.MustCompile(`(?m)^\s*revisionHistoryLimit:\s*(\d+)`)
+	// reRecreateStrategy matches a Deployment replacing every pod at once.
+	reRecreateStrategy = regexp.MustCompile(`(?m)^\s*type:\s*["']?Recreate\b`)
+	// reRolloutStrategy matches an Argo Rollouts blue/green or canary
+	// strategy.
+	reRolloutStrategy = regexp.MustCompile(`(?m)^\s*(blueGreen|canary):`)
+	// reInPlaceDeploy matches a deploy step that overwrites the running
+	// release: pulling into the live checkout or syncing files over it.
+	reInPlaceDeploy = regexp.MustCompile(`(?m)^[^#\n]*\b(git pull|git reset --hard origin|rsync\b[^\n]*--delete|scp -r)\b`)
+	// reReleaseDirs matches the releases-plus-symlink layout that keeps
+	// the previous release on disk.
+	reReleaseDirs = regexp.MustCompile(`releases/|ln -s?fn|ln -nfs|\bcurrent\b.*->|mv -T`)
+)
+
+// rollbackPlatforms are the hosting configs whose platform keeps earlier
+// deploys to go back to, with how.
+var rollbackPlatforms = []struct {
+	file, name, how string
+}{
+	{"vercel.json", "Vercel", "instant rollback to an earlier deployment"},
+	{".vercel/project.json", "Vercel", "instant rollback to an earlier deployment"},
+	{"netlify.toml", "Netlify", "publish an earlier deploy"},
+	{"heroku.yml", "Heroku", "heroku rollback"},
+	{"fly.toml", "Fly.io", "fly deploy --image with the previous release"},
+	{"render.yaml", "Render", "rollback to an earlier deploy"},
+}
+
+// rollbackFinding is one deploy mechanism found.
+type rollbackFinding struct {
+	loc    Location
+	oneWay bool
+}
+
+func (c RollbackCheck) Run(ctx Context) (CheckResult, error) {
+	var found []rollbackFinding
+	add := func(file string, line int, oneWay bool, message string) {
+		found = append(found, rollbackFinding{Location{File: file, Line: line, Message: message}, oneWay})
+	}
+	read := func(rel string) []byte {
+		content, err := readFile(filepath.Join(ctx.RootDir, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil
+		}
+		return content
+	}
+
+	for _, p := range rollbackPlatforms {
+		if content := read(p.file); content != nil {
+			add(p.file, 0, false, p.name+" ("+p.how+")")
+		}
+	}
+	if capfile := read("config/deploy.rb"); capfile != nil {
+		c.keptReleases(add, "config/deploy.rb", capfile, reKeepReleases, "Capistrano", 5, "cap deploy:rollback")
+	}
+	for _, name := range []string{"deploy.php", "deploy.yaml", "deploy.yml"} {
+		if content := read(name); content != nil && (name == "deploy.php" || bytes.Contains(content, []byte("recipe/"))) {
+			c.keptReleases(add, name, content, reKeepReleases, "Deployer", 10, "dep rollback")
+		}
+	}
+	if kamal := read("config/deploy.yml"); bytes.Contains(kamal, []byte("service:")) && bytes.Contains(kamal, []byte("image:")) {
+		c.keptReleases(add, "config/deploy.yml", kamal, reKamalRetain, "Kamal", 5, "kamal rollback")
+	}
+
+	for _, f := range ctx.files().Files {
+		if f.Ignored || f.Size > 1024*1024 || f.inDir(vendorSkipDirs) {
+			continue
+		}
+		switch {
+		case f.Ext == ".yml" || f.Ext == ".yaml":
+			if strings.HasPrefix(f.Path, ".github/workflows/") {
+				c.inPlaceDeploy(add, f.Path, read(f.Path))
+				continue
+			}
+			c.kubernetesRollback(add, f.Path, read(f.Path))
+		case isDeployScript(f):
+			c.inPlaceDeploy(add, f.Path, read(f.Path))
+		}
+	}
+
+	if len(found) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No deploy configuration found, skipping",
+		}, nil
+	}
+
+	var ways, findings, details []string
+	var locations []Location
+	for _, f := range found {
+		if f.oneWay {
+			findings = append(findings, f.loc.String())
+			locations = append(locations, f.loc)
+			continue
+		}
+		ways = append(ways, f.loc.Message)
+		details = append(details, f.loc.String())
+	}
+	if len(findings) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Rollback available: " + strings.Join(dedupe(ways), ", "),
+			Details:  details,
+		}, nil
+	}
+
+	message := fmt.Sprintf("%d deploy configs can't roll back", len(findings))
+	if len(findings) == 1 {
+		message = "Deploys are one-way: " + locations[0].Message
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  message,
+		Suggestions: append([]string{
+			"Keep at least the previous release around (keep_releases, retain_containers, revisionHistoryLimit) so a bad deploy can be undone in one step",
+			"Deploy to a new release directory and switch a symlink, instead of pulling into the live checkout",
+		}, limitFindings(findings, 8)...),
+		Details:   details,
+		Locations: locations,
+	}, nil
+}
+
+// keptReleases records a release-directory deploy tool, one-way when its
+// setting (or the tool's default) keeps fewer than two releases.
+func (c RollbackCheck) keptReleases(add func(string, int, bool, string), file string, content []byte, setting *regexp.Regexp, tool string, defaultKept int, command string) {
+	kept, line := defaultKept, 0
+	if m := setting.FindSubmatchIndex(content); m != nil {
+		kept, _ = strconv.Atoi(string(content[m[2]:m[3]]))
+		line = lineAt(content, m[2])
+	}
+	switch {
+	case kept == -1:
+		add(file, line, false, tool+" (every release kept, "+command+")")
+	case kept < 2:
+		add(file, line, true, tool+" keeps only the current release, leaving "+command+" nothing to go back to")
+	default:
+		add(file, line, false, fmt.Sprintf("%s (%d releases kept, %s)", tool, kept, command))
+	}
+}
+
+// kubernetesRollback records the Deployments and Argo Rollouts in a
+// manifest, one-way when revisionHistoryLimit is 0.
+func (c RollbackCheck) kubernetesRollback(add func(string, int, bool, string), file string, content []byte) {
+	offset := 0
+	for _, doc := range bytes.Split(content, []byte("\n---")) {
+		start := offset
+		offset += len(doc) + len("\n---")
+		kind := reManifestKind.FindSubmatch(doc)
+		if kind == nil {
+			continue
+		}
+		if string(kind[1]) == "Rollout" {
+			strategy := "progressive delivery"
+			if m := reRolloutStrategy.FindSubmatch(doc); m != nil {
+				strategy = map[string]string{"blueGreen": "blue/green", "canary": "canary"}[string(m[1])]
+			}
+			add(file, 0, false, "Argo Rollouts ("+strategy+")")
+			continue
+		}
+		if m := reRevisionHistory.FindSubmatchIndex(doc); m != nil && string(doc[m[2]:m[3]]) == "0" {
+			add(file, lineAt(content, start+m[2]), true, "revisionHistoryLimit: 0 leaves kubectl rollout undo nothing to go back to")
+			continue
+		}
+		how := "kubectl rollout undo"
+		if reRecreateStrategy.Match(doc) {
+			how += ", Recreate strategy takes the old pods down first"
+		}
+		add(file, 0, false, "Kubernetes Deployment ("+how+")")
+	}
+}
+
+// inPlaceDeploy records a deploy script or workflow that overwrites the
+// running release, unless it also keeps release directories or has
+// rollback steps.
+func (c RollbackCheck) inPlaceDeploy(add func(string, int, bool, string), file string, content []byte) {
+	m := reInPlaceDeploy.FindSubmatchIndex(content)
+	if m == nil || reReleaseDirs.Match(content) || reRollback.Match(content) {
+		return
+	}
+	if strings.HasPrefix(file, ".github/workflows/") && !bytes.Contains(bytes.ToLower(content), []byte("deploy")) {
+		return
+	}
+	add(file, lineAt(content, m[2]), true, "deploys in place with "+string(content[m[2]:m[3]])+", so there's no previous release to switch back to")
+}
+
+// isDeployScript reports whether f is a shell script or Makefile-style
+// entry point named for deploys.
+func isDeployScript(f FileEntry) bool {
+	base := strings.ToLower(strings.TrimSuffix(f.Name, f.Ext))
+	if !strings.HasPrefix(base, "deploy") {
+		return false
+	}
+	return f.Ext == ".sh" || f.Ext == "" && (strings.HasPrefix(f.Path, "bin/") || strings.HasPrefix(f.Path, "scripts/"))
+}
+
+// dedupe drops repeated strings, keeping the first of each.
+func dedupe(values []string) []string {
+	seen := map[string]bool{}
+	out := values[:0:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
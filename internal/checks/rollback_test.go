@@ -0,0 +1,87 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestRollbackCheck(t *testing.T) {
+	cases := []struct {
+		name      string
+		files     map[string]string
+		passed    bool
+		message   []string
+		want      []string
+		unwanted  []string
+		locations int
+	}{
+		{
+			name:    "no deploy configuration",
+			files:   map[string]string{"README.md": "app"},
+			passed:  true,
+			message: []string{"No deploy configuration found, skipping"},
+		},
+		{
+			name: "platforms and strategies that keep releases",
+			files: map[string]string{
+				"vercel.json":      `{"framework": "nextjs"}`,
+				"config/deploy.rb": "set :application, 'shop'\nset :keep_releases, 3\n",
+				"k8s/web.yaml":     "apiVersion: apps/v1\nkind: Deployment\nspec:\n  strategy:\n    type: Recreate\n---\napiVersion: argoproj.io/v1alpha1\nkind: Rollout\nspec:\n  strategy:\n    blueGreen:\n      activeService: web\n",
+			},
+			passed:  true,
+			message: []string{"Vercel", "Capistrano (3 releases kept", "Recreate strategy", "Argo Rollouts (blue/green)"},
+		},
+		{
+			// A script that links a new release directory can roll back
+			// by relinking, so only the git pull deploy counts.
+			name: "one-way deploys",
+			files: map[string]string{
+				"deploy.php":   "<?php\nnamespace Deployer;\nrequire 'recipe/laravel.php';\nset('keep_releases', 1);\n",
+				"k8s/api.yaml": "kind: Service\n---\nkind: Deployment\nspec:\n  revisionHistoryLimit: 0\n",
+				"bin/deploy":   "#!/bin/sh\nssh web 'cd /srv/app && git pull && systemctl restart app'\n",
+				"scripts/deploy.sh": "#!/bin/sh\n# git pull into a new release\n" +
+					"rsync -a --delete build/ web:/srv/releases/$TS/\nln -sfn /srv/releases/$TS /srv/current\n",
+			},
+			want: []string{
+				"deploy.php:4 - Deployer keeps only the current release",
+				"k8s/api.yaml:5 - revisionHistoryLimit: 0",
+				"bin/deploy:2 - deploys in place with git pull",
+			},
+			unwanted:  []string{"scripts/deploy.sh"},
+			locations: 3,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := RollbackCheck{}.Run(Context{RootDir: writeFiles(t, tc.files), Config: &config.PreflightConfig{}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tc.passed {
+				t.Fatalf("passed = %v, want %v: %q %v", result.Passed, tc.passed, result.Message, result.Suggestions)
+			}
+			for _, want := range tc.message {
+				if !strings.Contains(result.Message, want) {
+					t.Errorf("message missing %q: %q", want, result.Message)
+				}
+			}
+			joined := strings.Join(result.Suggestions, "\n")
+			for _, want := range tc.want {
+				if !strings.Contains(joined, want) {
+					t.Errorf("suggestions missing %q:\n%s", want, joined)
+				}
+			}
+			for _, unwanted := range tc.unwanted {
+				if strings.Contains(joined, unwanted) {
+					t.Errorf("%s reported:\n%s", unwanted, joined)
+				}
+			}
+			if len(result.Locations) != tc.locations {
+				t.Errorf("got %d locations, want %d", len(result.Locations), tc.locations)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package checks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+	"github.com/preflightsh/preflight/internal/routes"
+)
+
+// defaultRoutesMax caps how many routes one scan requests.
+const defaultRoutesMax = 20
+
+// RoutesCheck probes the routes the framework defines against the live
+// site: critical routes must respond, auth-required routes must turn away
+// an anonymous visitor, and public pages must not bounce to a login page.
+type RoutesCheck struct{}
+
+func (c RoutesCheck) ID() string {
+	return "routes"
+}
+
+func (c RoutesCheck) Title() string {
+	return "Live routes"
+}
+
+// routeProbe is one route to request and what it answered.
+type routeProbe struct {
+	route    routes.Route
+	critical bool
+	status   int
+	location string
+	// loginForm is set when a 2xx page has a password field: the route
+	// answered with a sign-in form rather than its own content.
+	loginForm bool
+	err       error
+}
+
+func (c RoutesCheck) Run(ctx Context) (CheckResult, error) {
+	baseURL := routesBaseURL(ctx)
+	if baseURL == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No URLs configured, skipping",
+		}, nil
+	}
+
+	probes := c.candidates(ctx)
+	if len(probes) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No framework routes found, skipping",
+		}, nil
+	}
+
+	probeRoutes(ctx, baseURL, probes)
+
+	var errs, warns []string
+	for _, p := range probes {
+		label := p.route.Path
+		if p.route.Source != "" {
+			label += " (" + p.route.Source + ")"
+		}
+		switch {
+		case p.err != nil:
+			errs = append(errs, fmt.Sprintf("%s - request failed: %v", label, p.err))
+		case p.status >= 500:
+			errs = append(errs, fmt.Sprintf("%s - returned %d", label, p.status))
+		case p.route.Auth:
+			// A sign-in form served in place is how some frameworks
+			// protect a route, as in auth_routes.
+			if p.status >= 200 && p.status < 300 && !p.loginForm {
+				errs = append(errs, fmt.Sprintf("%s - requires auth but returned %d to an anonymous visitor", label, p.status))
+			}
+		case p.status >= 300 && p.status < 400 && isLoginLocation(p.location):
+			msg := fmt.Sprintf("%s - redirects anonymous visitors to %s", label, p.location)
+			if p.critical {
+				errs = append(errs, msg)
+			} else {
+				warns = append(warns, msg)
+			}
+		case p.status >= 400:
+			msg := fmt.Sprintf("%s - returned %d", label, p.status)
+			if p.critical {
+				errs = append(errs, msg)
+			} else {
+				warns = append(warns, msg)
+			}
+		}
+	}
+
+	if len(errs) == 0 && len(warns) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("%d routes respond as expected", len(probes)),
+		}, nil
+	}
+
+	findings := append(errs, warns...)
+	severity := SeverityWarn
+	if len(errs) > 0 {
+		severity = SeverityError
+	}
+	suggestions := []string{
+		"Pages that need a login should redirect or return 401/403 when signed out",
+		"Marketing pages like pricing and about should be reachable without signing in",
+		"Fix or remove routes that return errors",
+	}
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    severity,
+		Passed:      false,
+		Message:     fmt.Sprintf("%d of %d routes misbehave", len(findings), len(probes)),
+		Suggestions: append(suggestions, limitFindings(findings, 5)...),
+	}, nil
+}
+
+// candidates lists routes to request: configured critical paths first,
+// then every fixed page route the framework defines, up to the cap.
+func (c RoutesCheck) candidates(ctx Context) []*routeProbe {
+	cfg := ctx.Config.Checks.Routes
+	max := defaultRoutesMax
+	var critical []string
+	if cfg != nil {
+		critical = cfg.Critical
+		if cfg.Max > 0 {
+			max = cfg.Max
+		}
+	}
+
+	extracted := routes.Extract(ctx.RootDir)
+	byPath := make(map[string]routes.Route, len(extracted))
+	for _, r := range extracted {
+		if _, ok := byPath[r.Path]; !ok || r.Method == "GET" {
+			byPath[r.Path] = r
+		}
+	}
+
+	var out []*routeProbe
+	seen := map[string]bool{}
+	for _, path := range critical {
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		r, ok := byPath[path]
+		if !ok {
+			r = routes.Route{Path: path, Method: "GET", Source: "preflight.yml"}
+		}
+		out = append(out, &routeProbe{route: r, critical: true})
+	}
+
+	var rest []routes.Route
+	for _, r := range extracted {
+		if r.Requestable() && !seen[r.Path] {
+			seen[r.Path] = true
+			rest = append(rest, r)
+		}
+	}
+	// Public pages before auth-gated ones, so a small cap still covers
+	// the marketing site.
+	sort.SliceStable(rest, func(i, j int) bool { return !rest[i].Auth && rest[j].Auth })
+	for _, r := range rest {
+		out = append(out, &routeProbe{route: r})
+	}
+
+	if len(out) > max {
+		out = out[:max]
+	}
+	return out
+}
+
+// routesBaseURL returns the URL routes are requested on: production, or
+// staging when production isn't set. It's "" when neither is.
+func routesBaseURL(ctx Context) string {
+	baseURL := ctx.Config.URLs.Production
+	if baseURL == "" {
+		baseURL = ctx.Config.URLs.Staging
+	}
+	if baseURL == "" {
+		return ""
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "https://" + baseURL
+	}
+	return baseURL
+}
+
+// probeRoutes requests every route concurrently without following
+// redirects, so a bounce to a login page is seen as the redirect itself.
+func probeRoutes(ctx Context, baseURL string, probes []*routeProbe) {
+	client := &http.Client{}
+	if ctx.Client != nil {
+		copied := *ctx.Client
+		client = &copied
+	}
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	const workers = 4
+	jobs := make(chan *routeProbe)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				resp, err := doGet(ctx.reqContext(), client, baseURL+p.route.Path)
+				if err != nil {
+					p.err = err
+					continue
+				}
+				p.status = resp.StatusCode
+				p.location = resp.Header.Get("Location")
+				if p.status >= 200 && p.status < 300 {
+					body, _ := io.ReadAll(io.LimitReader(resp.Body, netutil.MaxResponseBody))
+					p.loginForm = hasPasswordField(string(body))
+				}
+				resp.Body.Close()
+			}
+		}()
+	}
+	for _, p := range probes {
+		jobs <- p
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// isLoginLocation reports whether a redirect target looks like a sign-in
+// page.
+func isLoginLocation(location string) bool {
+	l := strings.ToLower(location)
+	for _, hint := range []string{"login", "signin", "sign_in", "sign-in", "/auth"} {
+		if strings.Contains(l, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPasswordField reports whether an HTML page has a password input.
+func hasPasswordField(page string) bool {
+	l := strings.ToLower(page)
+	for _, attr := range []string{`type="password"`, `type='password'`, `type=password`} {
+		if strings.Contains(l, attr) {
+			return true
+		}
+	}
+	return false
+}
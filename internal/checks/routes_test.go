@@ -0,0 +1,107 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestRoutesCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/", "/about":
+			w.WriteHeader(http.StatusOK)
+		case "/pricing":
+			http.Redirect(w, r, "/login", http.StatusFound)
+		case "/dashboard":
+			w.WriteHeader(http.StatusOK) // should have required login
+		case "/billing":
+			http.Redirect(w, r, "/login", http.StatusFound)
+		case "/settings":
+			w.Write([]byte(`<form action="/login"><input type="password" name="password"></form>`))
+		case "/reports":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	dir := writeFiles(t, map[string]string{
+		"app/page.tsx":           "",
+		"app/about/page.tsx":     "",
+		"app/pricing/page.tsx":   "",
+		"app/dashboard/page.tsx": "",
+		"app/billing/page.tsx":   "",
+		"app/settings/page.tsx":  "",
+		"app/reports/page.tsx":   "",
+		"middleware.ts":          "import { auth } from './auth'\nexport const config = { matcher: ['/dashboard/:path*', '/billing', '/settings', '/reports'] }",
+	})
+
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	cfg.Checks.Routes = &config.RoutesConfig{Enabled: true}
+	result, err := RoutesCheck{}.Run(Context{RootDir: dir, Config: cfg, Client: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed || result.Severity != SeverityError {
+		t.Fatalf("got passed=%v severity=%v, want an error", result.Passed, result.Severity)
+	}
+	joined := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{
+		"/dashboard (app/dashboard/page.tsx) - requires auth but returned 200",
+		"/pricing (app/pricing/page.tsx) - redirects anonymous visitors to /login",
+		"/reports (app/reports/page.tsx) - returned 500",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+	for _, unwanted := range []string{"/billing", "/about (", "/settings"} {
+		if strings.Contains(joined, unwanted) {
+			t.Errorf("suggestions should not mention %q:\n%s", unwanted, joined)
+		}
+	}
+}
+
+func TestRoutesCheckCriticalPath(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	// No framework files: the critical path alone is requested.
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	cfg.Checks.Routes = &config.RoutesConfig{Enabled: true, Critical: []string{"/", "signup"}}
+	result, err := RoutesCheck{}.Run(Context{RootDir: t.TempDir(), Config: cfg, Client: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed || result.Severity != SeverityError {
+		t.Fatalf("got passed=%v severity=%v, want an error", result.Passed, result.Severity)
+	}
+	if joined := strings.Join(result.Suggestions, "\n"); !strings.Contains(joined, "/signup (preflight.yml) - returned 404") || strings.Contains(joined, "/ (") {
+		t.Errorf("Suggestions = %v", result.Suggestions)
+	}
+}
+
+func TestRoutesCheckNoRoutes(t *testing.T) {
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = "https://example.com"
+	cfg.Checks.Routes = &config.RoutesConfig{Enabled: true}
+	result, err := RoutesCheck{}.Run(Context{RootDir: t.TempDir(), Config: cfg, Client: http.DefaultClient})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || !strings.Contains(result.Message, "skipping") {
+		t.Errorf("got %+v, want a skipped pass", result)
+	}
+}
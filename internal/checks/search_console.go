@@ -0,0 +1,188 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// SearchConsoleCheck looks for proof that the site has been verified with
+// Google Search Console or Bing Webmaster Tools: the HTML or XML file
+// each hands out, a verification meta tag, or a DNS TXT record. Without
+// one, nobody is watching the site's indexing or its crawl errors after
+// launch.
+type SearchConsoleCheck struct{}
+
+func (c SearchConsoleCheck) ID() string {
+	return "search_console"
+}
+
+func (c SearchConsoleCheck) Title() string {
+	return "Search console verification"
+}
+
+// searchVerificationFiles match the file names consoles ask to be served
+// from the site root.
+var searchVerificationFiles = []struct {
+	engine  string
+	pattern *regexp.Regexp
+}{
+	{"Google", regexp.MustCompile(`^google[0-9a-f]{16}\.html$`)},
+	{"Bing", regexp.MustCompile(`^(?i)BingSiteAuth\.xml$`)},
+	{"Yandex", regexp.MustCompile(`^yandex_[0-9a-f]+\.html$`)},
+}
+
+// searchVerificationMeta are the <meta name> verification tags, by engine.
+var searchVerificationMeta = []struct {
+	engine string
+	name   string
+}{
+	{"Google", "google-site-verification"},
+	{"Bing", "msvalidate.01"},
+	{"Yandex", "yandex-verification"},
+	{"Baidu", "baidu-site-verification"},
+}
+
+// searchVerificationTXT are the DNS TXT record prefixes, by engine.
+var searchVerificationTXT = []struct {
+	engine string
+	prefix string
+}{
+	{"Google", "google-site-verification="},
+	{"Bing", "ms=ms"},
+	{"Yandex", "yandex-verification:"},
+}
+
+// nextVerificationPattern matches the verification block of a Next.js
+// Metadata API export: verification: { google: '...' }.
+var nextVerificationPattern = regexp.MustCompile(`(?s)verification\s*:\s*\{[^}]*\b(google|yandex|other)\s*:`)
+
+func (c SearchConsoleCheck) Run(ctx Context) (CheckResult, error) {
+	var found []string
+	engines := map[string]bool{}
+	add := func(engine, where string) {
+		engines[engine] = true
+		if entry := engine + " (" + where + ")"; !slices.Contains(found, entry) {
+			found = append(found, entry)
+		}
+	}
+
+	webRoots := []string{"public", "static", "web", "www", "dist", "build", "_site", "out", ""}
+	for _, root := range webRoots {
+		entries, err := os.ReadDir(filepath.Join(ctx.RootDir, root))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			for _, f := range searchVerificationFiles {
+				if f.pattern.MatchString(entry.Name()) {
+					add(f.engine, filepath.ToSlash(filepath.Join(root, entry.Name())))
+				}
+			}
+		}
+	}
+
+	var cfgLayout string
+	if ctx.Config.Checks.SEOMeta != nil {
+		cfgLayout = ctx.Config.Checks.SEOMeta.MainLayout
+	}
+	if layoutFile := getLayoutFile(ctx.RootDir, ctx.Config.AllStacks(), cfgLayout); layoutFile != "" {
+		if content, err := readFile(filepath.Join(ctx.RootDir, layoutFile)); err == nil {
+			source := strings.ToLower(stripComments(string(content)))
+			for _, m := range searchVerificationMeta {
+				if strings.Contains(source, m.name) {
+					add(m.engine, layoutFile)
+				}
+			}
+			if match := nextVerificationPattern.FindStringSubmatch(source); match != nil && match[1] != "other" {
+				add(strings.ToUpper(match[1][:1])+match[1][1:], layoutFile)
+			}
+		}
+	}
+
+	if ctx.PageHTML != "" {
+		doc := parseRenderedHTML(ctx.PageHTML)
+		for _, m := range searchVerificationMeta {
+			if doc.metaName[m.name] != "" {
+				add(m.engine, "meta tag on the homepage")
+			}
+		}
+	}
+	if !engines["Bing"] {
+		if _, ok := probeStaticFileOverHTTP(ctx, "/BingSiteAuth.xml"); ok {
+			add("Bing", "/BingSiteAuth.xml")
+		}
+	}
+	if ctx.Config.URLs.Production != "" {
+		for _, txt := range searchVerificationDNS(ctx) {
+			add(txt[0], txt[1])
+		}
+	}
+
+	if len(found) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  "No Google Search Console or Bing Webmaster Tools verification found",
+			Suggestions: []string{
+				"Verify the site in Google Search Console (https://search.google.com/search-console) with its HTML file, meta tag or DNS TXT record",
+				"Then import it into Bing Webmaster Tools (https://www.bing.com/webmasters), which reads the Google verification",
+				"Submit sitemap.xml in both once verified",
+			},
+		}, nil
+	}
+
+	result := CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   true,
+		Message:  "Verified with " + strings.Join(found, ", "),
+	}
+	if !engines["Google"] {
+		result.Details = append(result.Details, "No Google Search Console verification; Google brings most search traffic")
+	}
+	if !engines["Bing"] {
+		result.Details = append(result.Details, "No Bing verification found; Bing Webmaster Tools can import sites from Google Search Console, which leaves nothing in the repo")
+	}
+	return result, nil
+}
+
+// searchVerificationDNS looks for verification TXT records on the
+// production host and its registrable domain, where domain-wide
+// verification lives. It returns engine and record name pairs.
+func searchVerificationDNS(ctx Context) [][2]string {
+	host, err := extractDomain(ctx.Config.URLs.Production)
+	if err != nil || host == "" {
+		return nil
+	}
+	names := []string{host}
+	if apex, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil && apex != host {
+		names = append(names, apex)
+	}
+	var out [][2]string
+	for _, name := range names {
+		records, err := dnsLookupTXT(ctx, name)
+		if err != nil {
+			continue
+		}
+		for _, t := range searchVerificationTXT {
+			for _, record := range records {
+				if strings.HasPrefix(strings.ToLower(strings.TrimSpace(record)), t.prefix) {
+					out = append(out, [2]string{t.engine, "DNS TXT on " + name})
+					break
+				}
+			}
+		}
+	}
+	return out
+}
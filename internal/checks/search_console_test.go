@@ -0,0 +1,76 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+func TestSearchConsoleCheck(t *testing.T) {
+	defer netutil.ConfigureResolver("", "")
+	netutil.SetResolver(fakeDNS{txt: map[string][]string{
+		"example.com": {"v=spf1 -all", "google-site-verification=abc123"},
+	}})
+
+	for _, tc := range []struct {
+		name       string
+		files      map[string]string
+		production string
+		pageHTML   string
+		passed     bool
+		want       string
+	}{
+		{
+			name:   "nothing",
+			files:  map[string]string{"public/robots.txt": "User-agent: *"},
+			passed: false,
+		},
+		{
+			name:   "google file",
+			files:  map[string]string{"public/google1a2b3c4d5e6f7a8b.html": "google-site-verification: google1a2b3c4d5e6f7a8b.html"},
+			passed: true,
+			want:   "Google (public/google1a2b3c4d5e6f7a8b.html)",
+		},
+		{
+			name:   "bing file",
+			files:  map[string]string{"static/BingSiteAuth.xml": "<users><user>ABC</user></users>"},
+			passed: true,
+			want:   "Bing (static/BingSiteAuth.xml)",
+		},
+		{
+			name:     "rendered meta tag",
+			pageHTML: `<head><meta name="msvalidate.01" content="ABC"></head>`,
+			passed:   true,
+			want:     "Bing (meta tag on the homepage)",
+		},
+		{
+			name:   "next metadata",
+			files:  map[string]string{"app/layout.tsx": "export const metadata = { verification: { google: 'abc' } }"},
+			passed: true,
+			want:   "Google (app/layout.tsx)",
+		},
+		{
+			name:       "dns txt on the registrable domain",
+			production: "https://app.example.com",
+			passed:     true,
+			want:       "Google (DNS TXT on example.com)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.PreflightConfig{Stack: "next"}
+			cfg.URLs.Production = tc.production
+			result, err := SearchConsoleCheck{}.Run(Context{RootDir: writeFiles(t, tc.files), Config: cfg, PageHTML: tc.pageHTML})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tc.passed {
+				t.Fatalf("passed = %v, want %v (%s)", result.Passed, tc.passed, result.Message)
+			}
+			if !strings.Contains(result.Message, tc.want) {
+				t.Errorf("message = %q, want it to mention %q", result.Message, tc.want)
+			}
+		})
+	}
+}
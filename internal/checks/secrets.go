@@ -4,9 +4,11 @@ import (
 	"bufio"
 	"bytes"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
@@ -47,6 +49,7 @@ func (c SecretScanCheck) Run(ctx Context) (CheckResult, error) {
 		// AI Providers
 		{regexp.MustCompile(`sk-[a-zA-Z0-9]{48,}`), "OpenAI API key"},
 		{regexp.MustCompile(`sk-proj-[a-zA-Z0-9_-]{48,}`), "OpenAI project key"},
+		{regexp.MustCompile(`sk-(?:svcacct|admin)-[a-zA-Z0-9_-]{48,}`), "OpenAI service account key"},
 		{regexp.MustCompile(`sk-ant-[a-zA-Z0-9_-]{90,}`), "Anthropic API key"},
 		{regexp.MustCompile(`AIza[0-9A-Za-z_-]{35}`), "Google AI/Firebase API key"},
 		{regexp.MustCompile(`r8_[a-zA-Z0-9]{37}`), "Replicate API token"},
@@ -58,9 +61,20 @@ func (c SecretScanCheck) Run(ctx Context) (CheckResult, error) {
 		{regexp.MustCompile(`AKIA[0-9A-Z]{16}`), "AWS Access Key ID"},
 		{regexp.MustCompile(`(?i)aws.{0,20}secret.{0,20}['"][0-9a-zA-Z/+]{40}['"]`), "AWS Secret Access Key"},
 		{regexp.MustCompile(`GOOG[0-9a-zA-Z_-]{28,}`), "Google Cloud API key"},
+		{regexp.MustCompile(`(?i)(?:cloudflare|cf)_?api_?token['"]?\s*[:=]\s*['"]?[a-zA-Z0-9_-]{40}\b`), "Cloudflare API token"},
+		{regexp.MustCompile(`(?i)(?:cloudflare|cf)_?(?:global_?)?api_?key['"]?\s*[:=]\s*['"]?[a-f0-9]{37}\b`), "Cloudflare global API key"},
 
 		// Auth Providers
 		{regexp.MustCompile(`sbp_[a-zA-Z0-9]{40,}`), "Supabase service key"},
+		{regexp.MustCompile(`sb_secret_[a-zA-Z0-9_-]{30,}`), "Supabase secret key"},
+		{regexp.MustCompile(`eyJ[a-zA-Z0-9_-]{10,}\.eyJ[a-zA-Z0-9_-]{10,}\.[a-zA-Z0-9_-]{20,}`), "Supabase service_role key"},
+		{regexp.MustCompile(`(?i)jwt_?secret(?:_?key)?['"]?\s*[:=]\s*(?:['"][^'"\s${}]{16,}['"]|[a-zA-Z0-9+/=_-]{16,})`), "JWT signing secret"},
+
+		// Databases
+		{regexp.MustCompile(`(?i)\b(?:postgres(?:ql)?|mysql|mariadb|mongodb(?:\+srv)?|rediss?|amqps?)://[^\s:/@'"]*:[^\s@/'"]+@[^\s/:?'"]+`), "Database URL with password"},
+
+		// Search
+		{regexp.MustCompile(`(?i)algolia_?(?:admin|write)_?(?:api_?)?key['"]?\s*[:=]\s*['"]?[a-f0-9]{32}\b`), "Algolia admin API key"},
 
 		// Communication
 		{regexp.MustCompile(`AC[a-f0-9]{32}`), "Twilio Account SID"},
@@ -72,6 +86,7 @@ func (c SecretScanCheck) Run(ctx Context) (CheckResult, error) {
 		// Email
 		{regexp.MustCompile(`SG\.[a-zA-Z0-9_-]{22}\.[a-zA-Z0-9_-]{43}`), "SendGrid API key"},
 		{regexp.MustCompile(`key-[a-f0-9]{32}`), "Mailgun API key"},
+		{regexp.MustCompile(`\b[a-f0-9]{32}-[a-f0-9]{8}-[a-f0-9]{8}\b`), "Mailgun API key"},
 		{regexp.MustCompile(`re_[a-zA-Z0-9]{32,}`), "Resend API key"},
 
 		// Error Tracking
@@ -98,6 +113,7 @@ func (c SecretScanCheck) Run(ctx Context) (CheckResult, error) {
 		// Google OAuth
 		{regexp.MustCompile(`ya29\.[0-9A-Za-z_-]+`), "Google OAuth access token"},
 	}
+	patterns = append(patterns, customSecretPatterns(ctx.Config)...)
 
 	// Directories to skip
 	skipDirs := map[string]bool{
@@ -137,48 +153,33 @@ func (c SecretScanCheck) Run(ctx Context) (CheckResult, error) {
 	// Resolve git status once. A secrets scanner's job is to catch
 	// secrets that version control will carry, so git — not the filename
 	// — is the authority on what's in scope when we're inside a repo.
-	git := loadGitStatus(ctx.RootDir)
+	git := loadGitStatus(ctx)
 
 	var findings []secretFinding
 	maxFileSize := int64(1024 * 1024) // 1 MB
 	filesScanned := 0
-	filesErrored := 0
-
-	err := filepath.Walk(ctx.RootDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			if info != nil && info.IsDir() {
-				filesErrored++
-				return filepath.SkipDir
-			}
-			filesErrored++
-			return nil
-		}
-
-		// Skip directories
-		if info.IsDir() {
-			if skipDirs[info.Name()] {
-				return filepath.SkipDir
-			}
-			return nil
-		}
 
-		// Skip symlinks, devices, pipes — anything not a plain file. A
-		// hostile project could otherwise drop a symlink named like an
-		// in-scope source file (`leak.env` → `~/.aws/credentials`,
-		// `bigfile.js` → `/dev/zero`) and trick the scanner into
-		// reading outside the project root or hanging on a device.
-		if !info.Mode().IsRegular() {
-			return nil
+	files := ctx.files()
+	filesErrored := files.Unreadable
+	for _, f := range files.Files {
+		// Skip excluded directories. The index holds regular files only,
+		// so symlinks, devices and pipes are already gone: a hostile project
+		// can't drop a symlink named like an in-scope source file
+		// (`leak.env` → `~/.aws/credentials`, `bigfile.js` → `/dev/zero`)
+		// and trick the scanner into reading outside the project root or
+		// hanging on a device.
+		if f.inDir(skipDirs) {
+			continue
 		}
 
 		// Skip files that are too large
-		if info.Size() > maxFileSize {
-			return nil
+		if f.Size > maxFileSize {
+			continue
 		}
 
 		// Check extension
-		ext := filepath.Ext(path)
-		baseName := filepath.Base(path)
+		ext := f.Ext
+		baseName := f.Name
 
 		// Also scan dotenv-family files. filepath.Ext(".env.production")
 		// returns ".production" (not in codeExtensions), so a plain
@@ -186,13 +187,13 @@ func (c SecretScanCheck) Run(ctx Context) (CheckResult, error) {
 		// .env.staging, etc. — exactly the files most likely to leak
 		// real credentials. Use a prefix check instead.
 		if !codeExtensions[ext] && ext != "" && !strings.HasPrefix(baseName, ".env") {
-			return nil
+			continue
 		}
 
 		// Skip example/sample files regardless of git status — they hold
 		// placeholders, not real values, and are committed on purpose.
 		if strings.Contains(baseName, ".example") || strings.Contains(baseName, ".sample") {
-			return nil
+			continue
 		}
 
 		// Decide scope. Inside a git repo, git is authoritative: a file
@@ -202,12 +203,12 @@ func (c SecretScanCheck) Run(ctx Context) (CheckResult, error) {
 		// .gitignore (git keeps tracking files added before the ignore
 		// rule), which is the dangerous case a plain .gitignore-text
 		// check would miss.
-		rel := filepath.ToSlash(relPath(ctx.RootDir, path))
+		rel := f.Path
 		state := ""
 		if git.inRepo {
 			tracked := git.tracked[rel]
 			if git.ignored[rel] && !tracked {
-				return nil
+				continue
 			}
 			if tracked {
 				state = "tracked"
@@ -215,15 +216,17 @@ func (c SecretScanCheck) Run(ctx Context) (CheckResult, error) {
 				// Untracked and not ignored: `git add .` would commit it.
 				state = "committable"
 			}
-		} else if strings.HasSuffix(baseName, ".local") {
-			// Not a git repo: fall back to filename convention. The
-			// .env*.local family is meant to hold real secrets and is
-			// never committed.
-			return nil
+		} else if f.Ignored || strings.HasSuffix(baseName, ".local") {
+			// Not a git repo (a tarball, a CI checkout without .git):
+			// fall back to the project's .gitignore files, which say
+			// what will never be committed, and to filename convention.
+			// The .env*.local family is meant to hold real secrets and
+			// is never committed.
+			continue
 		}
 
 		// Scan file
-		fileFindings, scanErr := scanFileForSecrets(path, patterns)
+		fileFindings, scanErr := scanFileForSecrets(files.Abs(f), patterns)
 		if scanErr != nil {
 			filesErrored++
 		}
@@ -232,22 +235,10 @@ func (c SecretScanCheck) Run(ctx Context) (CheckResult, error) {
 		}
 		findings = append(findings, fileFindings...)
 		filesScanned++
-
-		return nil
-	})
+	}
 
 	findings = applySecretAllowlist(findings, ctx)
 
-	if err != nil {
-		return CheckResult{
-			ID:       c.ID(),
-			Title:    c.Title(),
-			Severity: SeverityWarn,
-			Passed:   false,
-			Message:  fmt.Sprintf("Error scanning files: %v", err),
-		}, nil
-	}
-
 	// Build scan summary
 	scanSummary := fmt.Sprintf("Scanned %d files", filesScanned)
 	if filesErrored > 0 {
@@ -303,6 +294,11 @@ func (c SecretScanCheck) Run(ctx Context) (CheckResult, error) {
 		message += fmt.Sprintf("\n  Note: %s", scanSummary)
 	}
 
+	locations := make([]Location, len(findings))
+	for i, f := range findings {
+		locations[i] = Location{File: filepath.ToSlash(relPath(ctx.RootDir, f.file)), Line: f.line, Message: "Possible " + f.secretType}
+	}
+
 	return CheckResult{
 		ID:       c.ID(),
 		Title:    c.Title(),
@@ -315,6 +311,7 @@ func (c SecretScanCheck) Run(ctx Context) (CheckResult, error) {
 			"Add sensitive files to .gitignore",
 			"Consider using git-crypt or similar for encrypted secrets",
 		},
+		Locations: locations,
 	}, nil
 }
 
@@ -337,18 +334,18 @@ type gitStatus struct {
 // loadGitStatus shells out to git once to learn the tracked and
 // ignored-untracked file sets for root. If root isn't a git work tree
 // (or git isn't installed), inRepo is false and callers fall back to
-// filename heuristics. Paths are reported relative to root because every
-// git invocation runs with -C root.
-func loadGitStatus(root string) gitStatus {
+// filename heuristics. Paths are reported relative to the project root
+// because every git invocation runs with -C root.
+func loadGitStatus(ctx Context) gitStatus {
 	st := gitStatus{tracked: map[string]bool{}, ignored: map[string]bool{}}
 
-	out, err := runGit(root, "rev-parse", "--is-inside-work-tree")
+	out, err := ctx.git("rev-parse", "--is-inside-work-tree")
 	if err != nil || strings.TrimSpace(out) != "true" {
 		return st
 	}
 	st.inRepo = true
 
-	if out, err := runGit(root, "ls-files", "-z"); err == nil {
+	if out, err := ctx.git("ls-files", "-z"); err == nil {
 		for _, p := range strings.Split(out, "\x00") {
 			if p != "" {
 				st.tracked[filepath.ToSlash(p)] = true
@@ -359,7 +356,7 @@ func loadGitStatus(root string) gitStatus {
 	// restricts that to the ones the standard ignore rules exclude. A
 	// tracked-but-ignored file therefore never lands here, which is what
 	// keeps it in scope above.
-	if out, err := runGit(root, "ls-files", "--others", "--ignored", "--exclude-standard", "-z"); err == nil {
+	if out, err := ctx.git("ls-files", "--others", "--ignored", "--exclude-standard", "-z"); err == nil {
 		for _, p := range strings.Split(out, "\x00") {
 			if p != "" {
 				st.ignored[filepath.ToSlash(p)] = true
@@ -369,6 +366,16 @@ func loadGitStatus(root string) gitStatus {
 	return st
 }
 
+// git runs git in the project, or fails without running it when the
+// project is untrusted: its .git/config can set core.fsmonitor or hooks,
+// which git executes.
+func (c Context) git(args ...string) (string, error) {
+	if c.Untrusted {
+		return "", errors.New("git isn't run on untrusted projects")
+	}
+	return runGit(c.RootDir, args...)
+}
+
 // runGit runs `git -C root <args...>` and returns stdout. Stderr is
 // discarded; callers only care whether the command succeeded.
 func runGit(root string, args ...string) (string, error) {
@@ -432,10 +439,92 @@ func matchesSecretAllowlist(relPath, fingerprint string, entries []config.Secret
 	return false
 }
 
+// secretRejects second-guesses the patterns, by description, whose
+// format also matches harmless values: a match it returns true for is a
+// placeholder or a local dev credential, not a secret.
+var secretRejects = map[string]func(match string) bool{
+	"Supabase service_role key":  notServiceRoleJWT,
+	"JWT signing secret":         placeholderSecretValue,
+	"Database URL with password": localDatabaseURL,
+}
+
+// customSecretPatterns returns the project's own patterns from
+// checks.secrets.patterns. LoadFile has already rejected any that don't
+// compile; one that still doesn't (a config built in code) is skipped.
+func customSecretPatterns(cfg *config.PreflightConfig) []secretPattern {
+	if cfg == nil || cfg.Checks.Secrets == nil {
+		return nil
+	}
+	var patterns []secretPattern
+	for _, p := range cfg.Checks.Secrets.Patterns {
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			continue
+		}
+		patterns = append(patterns, secretPattern{pattern: re, description: p.Name})
+	}
+	return patterns
+}
+
+// secretAssignment splits a `key = "value"` match into its value.
+var secretAssignment = regexp.MustCompile(`[:=]\s*['"]?([^'"]*)['"]?$`)
+
+// placeholderSecretValue reports whether an assignment's value was never
+// filled in: changeme, your-secret-here and the like.
+func placeholderSecretValue(match string) bool {
+	m := secretAssignment.FindStringSubmatch(match)
+	return m != nil && envPlaceholder.MatchString(m[1])
+}
+
+// notServiceRoleJWT reports whether a JWT isn't a Supabase service_role
+// key. The anon key is a JWT too, but it's meant to ship to browsers; only
+// the service_role one bypasses row-level security.
+func notServiceRoleJWT(match string) bool {
+	parts := strings.Split(match, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return true
+	}
+	var claims struct {
+		Role string `json:"role"`
+	}
+	if json.Unmarshal(payload, &claims) != nil {
+		return true
+	}
+	return claims.Role != "service_role"
+}
+
+// localDatabaseHosts are hosts whose credentials only work on a developer's
+// machine or inside a compose network.
+var localDatabaseHosts = map[string]bool{
+	"localhost":            true,
+	"127.0.0.1":            true,
+	"0.0.0.0":              true,
+	"host.docker.internal": true,
+}
+
+// localDatabaseURL reports whether a connection URL's password is a local
+// dev one or a placeholder: a local host, an unexpanded ${VAR},
+// "password", or the username again (postgres:postgres, the image
+// default a compose file sets up).
+func localDatabaseURL(match string) bool {
+	rest := match[strings.Index(match, "://")+3:]
+	at := strings.LastIndex(rest, "@")
+	userinfo, host := rest[:at], strings.ToLower(rest[at+1:])
+	user, password, _ := strings.Cut(userinfo, ":")
+	if localDatabaseHosts[host] {
+		return true
+	}
+	if strings.HasPrefix(password, "$") || strings.EqualFold(password, "password") || password == user {
+		return true
+	}
+	return envPlaceholder.MatchString(password)
+}
+
 func scanFileForSecrets(path string, patterns []secretPattern) ([]secretFinding, error) {
 	var findings []secretFinding
 
-	file, err := os.Open(path)
+	file, err := openFile(path)
 	if err != nil {
 		return nil, err
 	}
@@ -462,6 +551,9 @@ func scanFileForSecrets(path string, patterns []secretPattern) ([]secretFinding,
 		seen := map[string]struct{}{}
 		for _, sp := range patterns {
 			for _, m := range sp.pattern.FindAllString(line, -1) {
+				if reject := secretRejects[sp.description]; reject != nil && reject(m) {
+					continue
+				}
 				fp := fingerprintSecret(m)
 				if _, dup := seen[fp]; dup {
 					continue
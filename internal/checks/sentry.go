@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 )
 
 type SentryCheck struct{}
@@ -70,6 +71,7 @@ func (c SentryCheck) Run(ctx Context) (CheckResult, error) {
 		monoDir := filepath.Join(ctx.RootDir, monoRoot)
 		entries, err := os.ReadDir(monoDir)
 		if err != nil {
+			recordUnreadable(monoDir, err)
 			continue
 		}
 		for _, entry := range entries {
@@ -105,6 +107,7 @@ func (c SentryCheck) Run(ctx Context) (CheckResult, error) {
 		monoDir := filepath.Join(ctx.RootDir, monoRoot)
 		entries, err := os.ReadDir(monoDir)
 		if err != nil {
+			recordUnreadable(monoDir, err)
 			continue
 		}
 		for _, entry := range entries {
@@ -124,55 +127,28 @@ func (c SentryCheck) Run(ctx Context) (CheckResult, error) {
 
 	found := false
 
+	files := ctx.files()
+	skipDirs := map[string]bool{"vendor": true}
 	for _, dir := range searchDirs {
-		dirPath := filepath.Join(ctx.RootDir, dir)
-		if _, err := os.Stat(dirPath); os.IsNotExist(err) {
-			continue
-		}
-
-		err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil
-			}
-
-			if info.IsDir() {
-				name := info.Name()
-				if name == "node_modules" || name == "vendor" || name == ".git" {
-					return filepath.SkipDir
-				}
-				return nil
-			}
-
-			// Check extension
-			ext := filepath.Ext(path)
-			validExt := false
-			for _, e := range extensions {
-				if ext == e {
-					validExt = true
-					break
-				}
-			}
-			if !validExt {
-				return nil
+		for _, f := range files.Under(dir) {
+			if f.Ignored || f.inDir(skipDirs) || !slices.Contains(extensions, f.Ext) {
+				continue
 			}
 
-			content, err := os.ReadFile(path)
+			content, err := readFile(files.Abs(f))
 			if err != nil {
-				return nil
+				continue
 			}
 
 			for _, pattern := range patterns {
 				if pattern.Match(content) {
 					found = true
-					return filepath.SkipAll
+					break
 				}
 			}
-
-			return nil
-		})
-
-		if err != nil {
-			continue
+			if found {
+				break
+			}
 		}
 
 		if found {
@@ -18,6 +18,33 @@ func (c SEOMetadataCheck) Title() string {
 }
 
 func (c SEOMetadataCheck) Run(ctx Context) (CheckResult, error) {
+	result, err := c.checkRequired(ctx)
+	if err != nil {
+		return result, err
+	}
+	conflicts := headConflicts(ctx)
+	if len(conflicts) == 0 {
+		return result, nil
+	}
+	suggestion := "Keep one <title>, meta description and canonical link per page; when a head partial and the layout (or an SEO plugin) both emit them, drop one"
+	if result.Passed {
+		return CheckResult{
+			ID:          c.ID(),
+			Title:       c.Title(),
+			Severity:    SeverityWarn,
+			Passed:      false,
+			Message:     "Duplicate or conflicting head tags: " + strings.Join(conflicts, "; "),
+			Suggestions: []string{suggestion},
+			Details:     []string{result.Message},
+		}, nil
+	}
+	result.Suggestions = append(result.Suggestions, suggestion)
+	result.Details = append(result.Details, conflicts...)
+	return result, nil
+}
+
+// checkRequired looks for the tags every page needs.
+func (c SEOMetadataCheck) checkRequired(ctx Context) (CheckResult, error) {
 	cfg := ctx.Config.Checks.SEOMeta
 
 	// Get configured layout or auto-detect
@@ -25,7 +52,7 @@ func (c SEOMetadataCheck) Run(ctx Context) (CheckResult, error) {
 	if cfg != nil {
 		configuredLayout = cfg.MainLayout
 	}
-	layoutFile := getLayoutFile(ctx.RootDir, ctx.Config.Stack, configuredLayout)
+	layoutFile := getLayoutFile(ctx.RootDir, ctx.Config.AllStacks(), configuredLayout)
 
 	if layoutFile == "" {
 		return CheckResult{
@@ -37,8 +64,10 @@ func (c SEOMetadataCheck) Run(ctx Context) (CheckResult, error) {
 		}, nil
 	}
 
+	// Meta tags often live in a head partial the layout includes, so
+	// read those along with it.
 	layoutPath := filepath.Join(ctx.RootDir, layoutFile)
-	content, err := os.ReadFile(layoutPath)
+	content, err := readWithIncludes(layoutPath, ctx.RootDir, ctx.Config.Stack)
 	if err != nil {
 		return CheckResult{
 			ID:       c.ID(),
@@ -53,7 +82,7 @@ func (c SEOMetadataCheck) Run(ctx Context) (CheckResult, error) {
 	}
 
 	// Strip comments to avoid false positives on commented-out code
-	contentStr := stripComments(string(content))
+	contentStr := stripComments(content)
 
 	// For Next.js, also check page files for metadata/generateMetadata
 	if strings.Contains(layoutFile, "app/") {
@@ -63,8 +92,9 @@ func (c SEOMetadataCheck) Run(ctx Context) (CheckResult, error) {
 		generateMetadataPattern := regexp.MustCompile(`(?s)export\s+(async\s+)?function\s+generateMetadata`)
 		metadataExportPattern := regexp.MustCompile(`(?s)export\s+(const|let|var)\s+metadata\s*[=:]`)
 
-		_ = filepath.Walk(appDir, func(path string, info os.FileInfo, err error) error {
+		_ = walk(appDir, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
+				recordUnreadable(path, err)
 				if info != nil && info.IsDir() {
 					return filepath.SkipDir
 				}
@@ -86,7 +116,7 @@ func (c SEOMetadataCheck) Run(ctx Context) (CheckResult, error) {
 				!strings.HasSuffix(nameLower, ".jsx") && !strings.HasSuffix(nameLower, ".js") {
 				return nil
 			}
-			fileContent, err := os.ReadFile(path)
+			fileContent, err := readFile(path)
 			if err != nil {
 				return nil
 			}
@@ -201,99 +231,31 @@ func renderedHasSEOTag(doc renderedDoc, name string) bool {
 	return false
 }
 
-// getLayoutFile returns the configured layout or auto-detects one based on stack
-func getLayoutFile(rootDir string, stack string, configuredLayout string) string {
+// DetectLayout returns the main layout the SEO checks would find without
+// a configured mainLayout, or "".
+func DetectLayout(rootDir string, stacks []string) string {
+	return getLayoutFile(rootDir, stacks, "")
+}
+
+// getLayoutFile returns the configured layout or auto-detects one from
+// the layouts in each stack's pack.
+func getLayoutFile(rootDir string, stacks []string, configuredLayout string) string {
 	// Use configured layout if set
 	if configuredLayout != "" {
 		return configuredLayout
 	}
 
-	// Auto-detect based on stack
-	layoutsByStack := map[string][]string{
-		"next": {
-			"app/layout.tsx", "app/layout.js", "app/layout.jsx",
-			"src/app/layout.tsx", "src/app/layout.js",
-			"pages/_app.tsx", "pages/_app.js", "pages/_document.tsx", "pages/_document.js",
-		},
-		"react": {
-			"index.html", "public/index.html", "src/index.html",
-		},
-		"vite": {
-			"index.html", "src/index.html",
-		},
-		"vue": {
-			"index.html", "public/index.html", "src/App.vue",
-		},
-		"svelte": {
-			"src/app.html", "index.html",
-		},
-		"angular": {
-			"src/index.html",
-		},
-		"rails": {
-			"app/views/layouts/application.html.erb",
-			"app/views/layouts/base.html.erb",
-		},
-		"laravel": {
-			"resources/views/layouts/app.blade.php",
-			"resources/views/layouts/main.blade.php",
-		},
-		"django": {
-			"templates/base.html",
-			"templates/layout.html",
-		},
-		"craft": {
-			"templates/_layout.twig",
-			"templates/_layouts/main.twig",
-			"templates/_layouts/base.twig",
-			"templates/_base.twig",
-		},
-		"hugo": {
-			"layouts/_default/baseof.html",
-			"layouts/_default/base.html",
-		},
-		"jekyll": {
-			"_layouts/default.html",
-			"_layouts/base.html",
-		},
-		"gatsby": {
-			"src/components/layout.js",
-			"src/components/Layout.js",
-			"src/components/layout.tsx",
-		},
-		"astro": {
-			"src/layouts/Layout.astro",
-			"src/layouts/Base.astro",
-			"src/layouts/BaseLayout.astro",
-		},
-		"eleventy": {
-			"_includes/base.njk",
-			"_includes/layout.njk",
-		},
-		"php": {
-			"templates/layout.php",
-			"includes/header.php",
-			"layout.php",
-		},
-		"node": {
-			"views/layout.ejs",
-			"views/layout.pug",
-			"views/layouts/main.hbs",
-		},
-	}
-
-	// Try stack-specific layouts first
-	if layouts, ok := layoutsByStack[stack]; ok {
-		for _, layout := range layouts {
-			if _, err := os.Stat(filepath.Join(rootDir, layout)); err == nil {
-				return layout
-			}
+	// Try stack-specific layouts first, the main stack's before the rest
+	for _, stack := range stacks {
+		if layout, ok := FindPath(rootDir, stackPack(stack).Layouts); ok {
+			return layout
 		}
 	}
 
 	// Fallback: try common layouts for any stack
 	commonLayouts := []string{
 		"app/layout.tsx", "app/layout.js",
+		"src/app/layout.tsx", "src/app/layout.js",
 		"index.html", "public/index.html",
 		"templates/_layout.twig",
 		"app/views/layouts/application.html.erb",
@@ -441,89 +403,3 @@ func extractNestedBlockSEO(content, key string) string {
 	}
 	return extractBraceBlockSEO(content, loc[1]-1)
 }
-
-// resolveTemplateIncludes extracts template include/extends paths from content,
-// resolves them relative to the template root, and returns absolute paths that exist on disk.
-func resolveTemplateIncludes(content, rootDir, stack string) []string {
-	var paths []string
-	seen := make(map[string]bool)
-
-	templateRoots := getTemplateRoots(rootDir, stack)
-	rawPaths := extractIncludePaths(content)
-
-	for _, raw := range rawPaths {
-		for _, root := range templateRoots {
-			fullPath := filepath.Join(root, raw)
-			if _, err := os.Stat(fullPath); err == nil {
-				if !seen[fullPath] {
-					seen[fullPath] = true
-					paths = append(paths, fullPath)
-				}
-			}
-		}
-	}
-
-	return paths
-}
-
-func getTemplateRoots(rootDir, stack string) []string {
-	switch stack {
-	case "craft":
-		return []string{filepath.Join(rootDir, "templates")}
-	case "laravel":
-		return []string{filepath.Join(rootDir, "resources", "views")}
-	case "rails":
-		return []string{filepath.Join(rootDir, "app", "views")}
-	case "hugo":
-		return []string{filepath.Join(rootDir, "layouts")}
-	case "jekyll":
-		return []string{
-			filepath.Join(rootDir, "_layouts"),
-			filepath.Join(rootDir, "_includes"),
-		}
-	default:
-		return []string{rootDir}
-	}
-}
-
-func extractIncludePaths(content string) []string {
-	var paths []string
-
-	// Twig: {% include '...' %} and {% extends '...' %}
-	twigPattern := regexp.MustCompile(`\{%[-\s]+(?:include|extends)\s+['"]([^'"]+)['"]`)
-	for _, match := range twigPattern.FindAllStringSubmatch(content, -1) {
-		path := match[1]
-		if idx := strings.Index(path, "|"); idx != -1 {
-			path = path[:idx]
-		}
-		paths = append(paths, path)
-	}
-
-	// Blade: @include('...') and @extends('...')
-	bladePattern := regexp.MustCompile(`@(?:include|extends)\s*\(\s*['"]([^'"]+)['"]`)
-	for _, match := range bladePattern.FindAllStringSubmatch(content, -1) {
-		path := strings.ReplaceAll(match[1], ".", "/") + ".blade.php"
-		paths = append(paths, path)
-	}
-
-	// ERB: <%= render partial: '...' %> and <%= render '...' %>
-	erbPattern := regexp.MustCompile(`<%=\s*render\s+(?:partial:\s*)?['"]([^'"]+)['"]`)
-	for _, match := range erbPattern.FindAllStringSubmatch(content, -1) {
-		path := match[1]
-		dir := filepath.Dir(path)
-		base := filepath.Base(path)
-		if !strings.HasPrefix(base, "_") {
-			base = "_" + base
-		}
-		if !strings.HasSuffix(base, ".html.erb") {
-			base = base + ".html.erb"
-		}
-		if dir == "." {
-			paths = append(paths, base)
-		} else {
-			paths = append(paths, filepath.Join(dir, base))
-		}
-	}
-
-	return paths
-}
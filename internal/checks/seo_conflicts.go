@@ -0,0 +1,202 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// headTags counts the head elements a page should carry exactly once.
+// Existence checks pass when there are two; search engines then pick one
+// (or ignore both, for conflicting canonicals), and a noindex anywhere
+// wins over an index.
+type headTags struct {
+	titles       int
+	descriptions int
+	canonicals   []string
+	// robots holds the directives of each robots meta, by name (robots,
+	// googlebot).
+	robots map[string][]string
+}
+
+// conflicts describes what's doubled up or contradicting.
+func (t headTags) conflicts() []string {
+	var problems []string
+	if t.titles > 1 {
+		problems = append(problems, fmt.Sprintf("%d <title> tags", t.titles))
+	}
+	if t.descriptions > 1 {
+		problems = append(problems, fmt.Sprintf("%d meta descriptions", t.descriptions))
+	}
+	if len(t.canonicals) > 1 {
+		distinct := map[string]bool{}
+		var hrefs []string
+		for _, href := range t.canonicals {
+			if !distinct[href] {
+				distinct[href] = true
+				hrefs = append(hrefs, href)
+			}
+		}
+		if len(hrefs) > 1 {
+			problems = append(problems, fmt.Sprintf("%d canonical links pointing at different URLs (%s)", len(t.canonicals), strings.Join(hrefs, ", ")))
+		} else {
+			problems = append(problems, fmt.Sprintf("%d canonical links", len(t.canonicals)))
+		}
+	}
+	for _, name := range []string{"robots", "googlebot"} {
+		index, noindex := false, false
+		for _, directive := range t.robots[name] {
+			switch directive {
+			case "index", "all":
+				index = true
+			case "noindex", "none":
+				noindex = true
+			}
+		}
+		if index && noindex {
+			problems = append(problems, fmt.Sprintf("%s meta says both index and noindex", name))
+		}
+	}
+	return problems
+}
+
+// robotsDirectives splits a robots meta's content into lowercased
+// directives.
+func robotsDirectives(content string) []string {
+	var directives []string
+	for _, d := range strings.Split(content, ",") {
+		if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+			directives = append(directives, d)
+		}
+	}
+	return directives
+}
+
+var (
+	reSVGBlock       = regexp.MustCompile(`(?is)<svg\b.*?</svg>`)
+	reTitleTag       = regexp.MustCompile(`(?i)<title[\s>]`)
+	reDescriptionTag = regexp.MustCompile(`(?i)<meta[^>]+name\s*=\s*["']description["'][^>]*>`)
+	reCanonicalTag   = regexp.MustCompile(`(?i)<link[^>]+rel\s*=\s*["']canonical["'][^>]*>`)
+	reRobotsTag      = regexp.MustCompile(`(?i)<meta[^>]+name\s*=\s*["'](robots|googlebot)["'][^>]*>`)
+	reTagHref        = regexp.MustCompile(`(?i)\bhref\s*=\s*["']([^"']*)`)
+	reTagContent     = regexp.MustCompile(`(?i)\bcontent\s*=\s*["']([^"']*)`)
+	// reTemplateElse marks the start of an else branch in a template, so
+	// a tag in each branch of an if/else isn't a duplicate.
+	reTemplateElse = regexp.MustCompile(`(?i)\{%-?\s*(?:else|elif|elsif|elseif)\b|<%-?\s*(?:else|elsif)\b|@else(?:if)?\b|\{\{-?\s*else\b|\{\{#else\b|\{:else\b|\bv-else\b`)
+)
+
+// sourceHeadConflicts counts head tags in template source, one branch of
+// each template conditional at a time; tags in sibling branches are
+// alternatives, only one of which renders.
+func sourceHeadConflicts(content string) []string {
+	seen := map[string]bool{}
+	var problems []string
+	for _, branch := range reTemplateElse.Split(reSVGBlock.ReplaceAllString(content, ""), -1) {
+		t := headTags{
+			titles:       len(reTitleTag.FindAllString(branch, -1)),
+			descriptions: len(reDescriptionTag.FindAllString(branch, -1)),
+			robots:       map[string][]string{},
+		}
+		for _, tag := range reCanonicalTag.FindAllString(branch, -1) {
+			href := ""
+			if m := reTagHref.FindStringSubmatch(tag); m != nil {
+				href = m[1]
+			}
+			t.canonicals = append(t.canonicals, href)
+		}
+		for _, m := range reRobotsTag.FindAllStringSubmatch(branch, -1) {
+			if c := reTagContent.FindStringSubmatch(m[0]); c != nil {
+				name := strings.ToLower(m[1])
+				t.robots[name] = append(t.robots[name], robotsDirectives(c[1])...)
+			}
+		}
+		for _, p := range t.conflicts() {
+			if !seen[p] {
+				seen[p] = true
+				problems = append(problems, p)
+			}
+		}
+	}
+	return problems
+}
+
+// renderedHeadConflicts counts head tags in a served page.
+func renderedHeadConflicts(doc string) []string {
+	t := headTags{robots: map[string][]string{}}
+	z := html.NewTokenizer(strings.NewReader(doc))
+	svg := 0
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return t.conflicts()
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "svg" && svg > 0 {
+				svg--
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			attrs := map[string]string{}
+			for hasAttr {
+				var k, v []byte
+				k, v, hasAttr = z.TagAttr()
+				attrs[strings.ToLower(string(k))] = string(v)
+			}
+			switch string(name) {
+			case "svg":
+				if tt == html.StartTagToken {
+					svg++
+				}
+			case "title":
+				if svg == 0 {
+					t.titles++
+				}
+			case "meta":
+				switch n := strings.ToLower(strings.TrimSpace(attrs["name"])); n {
+				case "description":
+					t.descriptions++
+				case "robots", "googlebot":
+					t.robots[n] = append(t.robots[n], robotsDirectives(attrs["content"])...)
+				}
+			case "link":
+				for _, rel := range strings.Fields(strings.ToLower(attrs["rel"])) {
+					if rel == "canonical" {
+						t.canonicals = append(t.canonicals, strings.TrimSpace(attrs["href"]))
+					}
+				}
+			}
+		}
+	}
+}
+
+// headConflicts finds doubled-up or contradicting head tags in the
+// layout with the partials it includes, and in each rendered homepage.
+func headConflicts(ctx Context) []string {
+	var conflicts []string
+	var configured string
+	if cfg := ctx.Config.Checks.SEOMeta; cfg != nil {
+		configured = cfg.MainLayout
+	}
+	if layout := getLayoutFile(ctx.RootDir, ctx.Config.AllStacks(), configured); layout != "" {
+		if content, err := readWithIncludes(filepath.Join(ctx.RootDir, layout), ctx.RootDir, ctx.Config.Stack); err == nil {
+			for _, p := range sourceHeadConflicts(stripComments(content)) {
+				conflicts = append(conflicts, layout+": "+p)
+			}
+		}
+	}
+	for _, env := range []struct{ name, html string }{
+		{"production homepage", ctx.PageHTMLProduction},
+		{"staging homepage", ctx.PageHTMLStaging},
+	} {
+		if env.html == "" {
+			continue
+		}
+		for _, p := range renderedHeadConflicts(env.html) {
+			conflicts = append(conflicts, env.name+": "+p)
+		}
+	}
+	return conflicts
+}
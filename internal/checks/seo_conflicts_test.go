@@ -0,0 +1,70 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestSEOMetadataHeadConflicts(t *testing.T) {
+	head := `<meta name="description" content="Site"><meta property="og:title" content="Site"><meta property="og:description" content="Site">`
+	tests := []struct {
+		name   string
+		files  map[string]string
+		html   string
+		passed bool
+		want   []string
+	}{
+		{
+			name: "one of each",
+			files: map[string]string{"templates/base.html": "<head><title>Site</title>" + head +
+				`<link rel="canonical" href="https://example.com/"></head><body><svg><title>Menu</title></svg></body>`},
+			passed: true,
+		},
+		{
+			name: "partial repeats the title and description",
+			files: map[string]string{
+				"templates/base.html":         "<head><title>Site</title>" + head + "{% include \"partials/seo.html\" %}</head>",
+				"templates/partials/seo.html": `<title>{{ page.title }}</title><meta name="description" content="{{ page.summary }}">`,
+			},
+			want: []string{"templates/base.html: 2 <title> tags", "2 meta descriptions"},
+		},
+		{
+			name: "if and else branches",
+			files: map[string]string{"templates/base.html": "<head>{% if page %}<title>{{ page.title }}</title>{% else %}<title>Site</title>{% endif %}" + head +
+				`{% if draft %}<meta name="robots" content="noindex">{% else %}<meta name="robots" content="index, follow">{% endif %}</head>`},
+			passed: true,
+		},
+		{
+			name:  "rendered conflicts",
+			files: map[string]string{"templates/base.html": "<head><title>Site</title>" + head + "</head>"},
+			html: `<html><head><title>Site</title><link rel="canonical" href="https://example.com/"><link rel="canonical" href="https://www.example.com/">` +
+				`<meta name="robots" content="index,follow"><meta name="robots" content="noindex"></head></html>`,
+			want: []string{
+				"production homepage: 2 canonical links pointing at different URLs (https://example.com/, https://www.example.com/)",
+				"production homepage: robots meta says both index and noindex",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SEOMetadataCheck{}.Run(Context{
+				RootDir:            writeFiles(t, tt.files),
+				Config:             &config.PreflightConfig{Stack: "django"},
+				PageHTMLProduction: tt.html,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tt.passed {
+				t.Fatalf("passed = %v, want %v (%q)", result.Passed, tt.passed, result.Message)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(result.Message, w) {
+					t.Errorf("message %q missing %q", result.Message, w)
+				}
+			}
+		})
+	}
+}
@@ -84,7 +84,7 @@ func (c ServiceCheck) Run(ctx Context) (CheckResult, error) {
 		liveURL = url
 	}
 
-	if len(c.CodePatterns) > 0 && searchForPatterns(ctx.RootDir, ctx.Config.Stack, c.CodePatterns) {
+	if len(c.CodePatterns) > 0 && searchForPatterns(ctx, c.CodePatterns) {
 		if liveURL != "" {
 			return warn(c.LiveMissingMsg, c.LiveMissingSuggestions)
 		}
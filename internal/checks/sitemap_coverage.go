@@ -0,0 +1,287 @@
+package checks
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SitemapCoverageCheck compares the pages a static site build produced
+// with the URLs its sitemap lists. Pages missing from the sitemap are
+// slow to get indexed; sitemap entries without a page send crawlers to
+// 404s. Only built output is compared, so the check skips until the site
+// has been built.
+type SitemapCoverageCheck struct{}
+
+func (c SitemapCoverageCheck) ID() string {
+	return "sitemap_coverage"
+}
+
+func (c SitemapCoverageCheck) Title() string {
+	return "Sitemap coverage"
+}
+
+// staticOutputDirs lists where each static-site stack writes its build,
+// most likely first.
+var staticOutputDirs = map[string][]string{
+	"hugo":     {"public"},
+	"jekyll":   {"_site"},
+	"gatsby":   {"public"},
+	"eleventy": {"_site", "dist", "public"},
+	"astro":    {"dist"},
+	"static":   {"", "public", "dist", "build", "out", "_site"},
+}
+
+// IsStaticSiteStack reports whether a stack builds to static files the
+// sitemap coverage check can compare.
+func IsStaticSiteStack(stack string) bool {
+	_, ok := staticOutputDirs[stack]
+	return ok
+}
+
+// staticOutputDirsFor returns the build directories of every static-site
+// stack in stacks, without repeats.
+func staticOutputDirsFor(stacks []string) []string {
+	var dirs []string
+	for _, stack := range stacks {
+		for _, dir := range staticOutputDirs[stack] {
+			if !slices.Contains(dirs, dir) {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
+var (
+	reNoindexMeta = regexp.MustCompile(`(?i)<meta[^>]+name=["']robots["'][^>]+content=["'][^"']*noindex`)
+	// Hugo aliases and similar redirect stubs are pages nobody should index.
+	reMetaRefresh = regexp.MustCompile(`(?i)<meta[^>]+http-equiv=["']refresh["']`)
+)
+
+type sitemapURLSet struct {
+	URLs []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+func (c SitemapCoverageCheck) Run(ctx Context) (CheckResult, error) {
+	outDir, sitemapPath := findBuiltSitemap(ctx.RootDir, staticOutputDirsFor(ctx.Config.AllStacks()))
+	if outDir == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No built sitemap found, skipping",
+		}, nil
+	}
+	outRel := filepath.ToSlash(relPath(ctx.RootDir, outDir))
+	if outRel == "." {
+		outRel = "project root"
+	}
+
+	listed, err := readSitemapPaths(sitemapPath, outDir, map[string]bool{})
+	if err != nil {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  fmt.Sprintf("Could not parse %s: %v", relPath(ctx.RootDir, sitemapPath), err),
+			Suggestions: []string{
+				"Make sure your sitemap generator outputs valid XML",
+			},
+		}, nil
+	}
+	pages := builtPages(ctx.RootDir, outDir)
+
+	var unlisted, orphaned []string
+	for key, file := range pages {
+		if _, ok := listed[key]; !ok {
+			unlisted = append(unlisted, fmt.Sprintf("%s - not in sitemap", file))
+		}
+	}
+	for key, loc := range listed {
+		if _, ok := pages[key]; !ok {
+			orphaned = append(orphaned, fmt.Sprintf("%s - no page in %s", loc, outRel))
+		}
+	}
+	sort.Strings(unlisted)
+	sort.Strings(orphaned)
+
+	if len(unlisted) == 0 && len(orphaned) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("All %d pages in %s are in the sitemap", len(pages), outRel),
+		}, nil
+	}
+
+	var parts []string
+	if len(unlisted) > 0 {
+		parts = append(parts, fmt.Sprintf("%d pages missing from sitemap", len(unlisted)))
+	}
+	if len(orphaned) > 0 {
+		parts = append(parts, fmt.Sprintf("%d sitemap URLs with no page", len(orphaned)))
+	}
+
+	var suggestions []string
+	if len(unlisted) > 0 {
+		suggestions = append(suggestions, "Check your sitemap generator's exclude rules, or mark pages that shouldn't be indexed noindex")
+	}
+	if len(orphaned) > 0 {
+		suggestions = append(suggestions, "Remove sitemap entries for deleted pages, or rebuild the sitemap with the site")
+	}
+	suggestions = append(suggestions, limitFindings(unlisted, 5)...)
+	suggestions = append(suggestions, limitFindings(orphaned, 5)...)
+
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    SeverityWarn,
+		Passed:      false,
+		Message:     strings.Join(parts, ", "),
+		Suggestions: suggestions,
+	}, nil
+}
+
+// findBuiltSitemap returns the first candidate output directory holding a
+// sitemap, and the sitemap (or sitemap index) file itself.
+func findBuiltSitemap(rootDir string, candidates []string) (string, string) {
+	for _, dir := range candidates {
+		outDir := filepath.Join(rootDir, dir)
+		for _, name := range []string{"sitemap.xml", "sitemap-index.xml", "sitemap_index.xml"} {
+			p := filepath.Join(outDir, name)
+			if info, err := os.Stat(p); err == nil && !info.IsDir() {
+				return outDir, p
+			}
+		}
+	}
+	return "", ""
+}
+
+// readSitemapPaths returns the normalized page keys a sitemap lists,
+// mapped to the loc as written. Sitemap indexes are followed to child
+// sitemaps that exist in outDir; remote children are skipped.
+func readSitemapPaths(file, outDir string, visited map[string]bool) (map[string]string, error) {
+	if visited[file] {
+		return map[string]string{}, nil
+	}
+	visited[file] = true
+
+	data, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var set sitemapURLSet
+	if err := xml.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]string, len(set.URLs))
+	for _, u := range set.URLs {
+		loc := strings.TrimSpace(u.Loc)
+		if loc == "" {
+			continue
+		}
+		out[pageKey(urlPath(loc))] = loc
+	}
+	for _, s := range set.Sitemaps {
+		child := filepath.Join(outDir, filepath.FromSlash(strings.TrimPrefix(urlPath(strings.TrimSpace(s.Loc)), "/")))
+		if _, err := os.Stat(child); err != nil {
+			continue
+		}
+		childPaths, err := readSitemapPaths(child, outDir, visited)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range childPaths {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+// builtPages maps normalized page keys to the project-relative HTML file
+// that serves them, leaving out error pages, redirect stubs and pages
+// marked noindex.
+func builtPages(rootDir, outDir string) map[string]string {
+	skipDirs := map[string]bool{"node_modules": true, "vendor": true, ".git": true}
+	pages := map[string]string{}
+	_ = walkDir(outDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			recordUnreadable(p, err)
+			return nil
+		}
+		if d.IsDir() {
+			if p != outDir && (skipDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(p), ".html") {
+			return nil
+		}
+		rel := filepath.ToSlash(relPath(outDir, p))
+		switch strings.ToLower(path.Base(rel)) {
+		case "404.html", "500.html", "50x.html":
+			return nil
+		}
+		content, err := readFile(p)
+		if err != nil {
+			return nil
+		}
+		if reNoindexMeta.Match(content) || reMetaRefresh.Match(content) {
+			return nil
+		}
+		pages[pageKey("/"+rel)] = filepath.ToSlash(relPath(rootDir, p))
+		return nil
+	})
+	return pages
+}
+
+// urlPath returns the decoded path of a sitemap loc, which may be an
+// absolute URL or (against the spec, but common) a bare path.
+func urlPath(loc string) string {
+	u, err := url.Parse(loc)
+	if err != nil {
+		return loc
+	}
+	if u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+// pageKey normalizes a URL path or output file path so the forms a static
+// host serves interchangeably compare equal: /about/, /about/index.html,
+// /about.html and /about all become "about".
+func pageKey(p string) string {
+	if path.Base(p) == "index.html" {
+		p = path.Dir(p)
+	}
+	p = strings.TrimSuffix(p, ".html")
+	return strings.Trim(p, "/")
+}
+
+// limitFindings caps a findings list for display, noting how many were
+// left out.
+func limitFindings(findings []string, max int) []string {
+	if len(findings) <= max {
+		return findings
+	}
+	return append(findings[:max:max], fmt.Sprintf("... and %d more", len(findings)-max))
+}
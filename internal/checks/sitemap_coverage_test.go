@@ -0,0 +1,97 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestSitemapCoverageCheck(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"public/index.html":            "<html></html>",
+		"public/about/index.html":      "<html></html>",
+		"public/pricing.html":          "<html></html>",
+		"public/drafts/wip.html":       `<html><head><meta name="robots" content="noindex"></head></html>`,
+		"public/old-path/index.html":   `<html><head><meta http-equiv="refresh" content="0; url=/about/"></head></html>`,
+		"public/404.html":              "<html></html>",
+		"public/blog/hello/index.html": "<html></html>",
+		"public/sitemap.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/</loc></url>
+  <url><loc>https://example.com/about/</loc></url>
+  <url><loc>https://example.com/pricing</loc></url>
+  <url><loc>https://example.com/removed/</loc></url>
+</urlset>`,
+	})
+
+	cfg := &config.PreflightConfig{Stack: "hugo"}
+	result, err := SitemapCoverageCheck{}.Run(Context{RootDir: dir, Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed {
+		t.Fatalf("expected failure, got %+v", result)
+	}
+	if result.Message != "1 pages missing from sitemap, 1 sitemap URLs with no page" {
+		t.Errorf("Message = %q", result.Message)
+	}
+	joined := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{
+		"public/blog/hello/index.html - not in sitemap",
+		"https://example.com/removed/ - no page in public",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+}
+
+func TestSitemapCoverageFollowsIndex(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"dist/index.html":      "<html></html>",
+		"dist/docs/index.html": "<html></html>",
+		"dist/sitemap-index.xml": `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/sitemap-0.xml</loc></sitemap>
+</sitemapindex>`,
+		"dist/sitemap-0.xml": `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/</loc></url>
+  <url><loc>https://example.com/docs/</loc></url>
+</urlset>`,
+	})
+
+	cfg := &config.PreflightConfig{Stack: "astro"}
+	result, err := SitemapCoverageCheck{}.Run(Context{RootDir: dir, Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed {
+		t.Errorf("expected pass, got %+v", result)
+	}
+}
+
+func TestSitemapCoverageSkipsUnbuilt(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"content/post.md": "# hi"})
+	result, err := SitemapCoverageCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{Stack: "hugo"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || !strings.Contains(result.Message, "skipping") {
+		t.Errorf("got %+v, want a skipped pass", result)
+	}
+}
+
+func TestPageKey(t *testing.T) {
+	for in, want := range map[string]string{
+		"/":                 "",
+		"/index.html":       "",
+		"/about/":           "about",
+		"/about/index.html": "about",
+		"/about.html":       "about",
+		"/myindex.html":     "myindex",
+	} {
+		if got := pageKey(in); got != want {
+			t.Errorf("pageKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,395 @@
+package checks
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+const (
+	// sitemapNamespace is the namespace the sitemaps.org schema requires.
+	sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	// maxSitemapURLs is the protocol's limit per sitemap file.
+	maxSitemapURLs = 50000
+	// maxSitemapChildren bounds how many sitemaps of an index are fetched.
+	maxSitemapChildren = 10
+	// sitemapSampleSize is how many listed URLs are requested.
+	sitemapSampleSize = 20
+)
+
+// liveSitemapPaths are where a deployed sitemap is looked for, before the
+// Sitemap: lines of the production robots.txt.
+var liveSitemapPaths = []string{"/sitemap.xml", "/sitemap_index.xml", "/sitemap.xml.gz"}
+
+// sitemapDoc is a urlset or a sitemapindex; XMLName tells them apart.
+type sitemapDoc struct {
+	XMLName  xml.Name
+	URLs     []sitemapEntry `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc        string `xml:"loc"`
+	Lastmod    string `xml:"lastmod"`
+	Changefreq string `xml:"changefreq"`
+	Priority   string `xml:"priority"`
+}
+
+var sitemapChangefreqs = map[string]bool{
+	"always": true, "hourly": true, "daily": true, "weekly": true,
+	"monthly": true, "yearly": true, "never": true,
+}
+
+// w3cDatetimeLayouts are the W3C Datetime forms lastmod may take.
+var w3cDatetimeLayouts = []string{
+	"2006", "2006-01", "2006-01-02",
+	"2006-01-02T15:04Z07:00", "2006-01-02T15:04:05Z07:00", time.RFC3339Nano,
+}
+
+// parseSitemap decodes a sitemap (gunzipping a .gz one) and lists where
+// it departs from the sitemaps.org schema.
+func parseSitemap(data []byte) (sitemapDoc, []string, error) {
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return sitemapDoc{}, nil, err
+		}
+		if data, err = io.ReadAll(io.LimitReader(zr, netutil.MaxResponseBody)); err != nil {
+			return sitemapDoc{}, nil, err
+		}
+	}
+	var doc sitemapDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return sitemapDoc{}, nil, fmt.Errorf("not valid XML: %w", err)
+	}
+
+	var problems []string
+	entries, kind := doc.URLs, "url"
+	switch doc.XMLName.Local {
+	case "urlset":
+		if len(doc.URLs) == 0 {
+			problems = append(problems, "lists no URLs")
+		}
+	case "sitemapindex":
+		entries, kind = doc.Sitemaps, "sitemap"
+		if len(doc.Sitemaps) == 0 {
+			problems = append(problems, "index lists no sitemaps")
+		}
+	default:
+		return doc, nil, fmt.Errorf("root element is <%s>, want <urlset> or <sitemapindex>", doc.XMLName.Local)
+	}
+	if doc.XMLName.Space != sitemapNamespace {
+		problems = append(problems, fmt.Sprintf("<%s> must declare xmlns=%q", doc.XMLName.Local, sitemapNamespace))
+	}
+	if len(entries) > maxSitemapURLs {
+		problems = append(problems, fmt.Sprintf("lists %d entries; the limit is %d per file", len(entries), maxSitemapURLs))
+	}
+
+	for i, e := range entries {
+		n := i + 1
+		loc := strings.TrimSpace(e.Loc)
+		if u, err := url.Parse(loc); loc == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("%s %d: <loc> %q isn't a full URL", kind, n, loc))
+		} else if len(loc) > 2048 {
+			problems = append(problems, fmt.Sprintf("%s %d: <loc> is longer than 2048 characters", kind, n))
+		}
+		if lastmod := strings.TrimSpace(e.Lastmod); lastmod != "" && !isW3CDatetime(lastmod) {
+			problems = append(problems, fmt.Sprintf("%s %d: <lastmod> %q isn't a W3C datetime (2024-05-01 or 2024-05-01T09:30:00+00:00)", kind, n, lastmod))
+		}
+		if freq := strings.TrimSpace(e.Changefreq); freq != "" && !sitemapChangefreqs[freq] {
+			problems = append(problems, fmt.Sprintf("%s %d: <changefreq> %q isn't one of always, hourly, daily, weekly, monthly, yearly, never", kind, n, freq))
+		}
+		if p := strings.TrimSpace(e.Priority); p != "" {
+			if f, err := strconv.ParseFloat(p, 64); err != nil || f < 0 || f > 1 {
+				problems = append(problems, fmt.Sprintf("%s %d: <priority> %q must be between 0.0 and 1.0", kind, n, p))
+			}
+		}
+	}
+	return doc, problems, nil
+}
+
+func isW3CDatetime(s string) bool {
+	for _, layout := range w3cDatetimeLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchSitemap GETs a sitemap. ok is false when the URL doesn't serve
+// one: an error status or an HTML page (an SPA shell or a login screen).
+func fetchSitemap(ctx Context, sitemapURL string) (data []byte, status int, ok bool, err error) {
+	resp, err := doGet(ctx.reqContext(), ctx.Client, sitemapURL)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, false, nil
+	}
+	data, err = io.ReadAll(io.LimitReader(resp.Body, netutil.MaxResponseBody))
+	if err != nil {
+		return nil, resp.StatusCode, false, err
+	}
+	lower := strings.ToLower(strings.TrimSpace(string(data)))
+	if strings.Contains(strings.ToLower(resp.Header.Get("Content-Type")), "text/html") ||
+		strings.HasPrefix(lower, "<!doctype html") || strings.HasPrefix(lower, "<html") {
+		return nil, resp.StatusCode, false, nil
+	}
+	return data, resp.StatusCode, len(lower) > 0, nil
+}
+
+// findLiveSitemap returns the first sitemap production serves, trying the
+// usual paths, the Sitemap: lines of its robots.txt, then the parent
+// domains. reached is false when no request got an answer at all.
+func findLiveSitemap(ctx Context, base string) (sitemapURL string, data []byte, reached bool) {
+	base = strings.TrimSuffix(base, "/")
+	candidates := make([]string, 0, len(liveSitemapPaths))
+	for _, p := range liveSitemapPaths {
+		candidates = append(candidates, base+p)
+	}
+	if fetch, err := fetchRobotsTxt(ctx, base+"/robots.txt"); err == nil && fetch.status == http.StatusOK && !fetch.html {
+		for _, s := range parseRobotsTxt(fetch.body).sitemaps {
+			if !slices.ContainsFunc(candidates, func(c string) bool { return strings.EqualFold(c, s) }) {
+				candidates = append(candidates, s)
+			}
+		}
+	}
+	// A subdomain app's sitemap may live on the org's main site.
+	for _, parent := range parentBaseURLs(base) {
+		candidates = append(candidates, parent+"/sitemap.xml")
+	}
+	for _, candidate := range candidates {
+		data, _, ok, err := fetchSitemap(ctx, candidate)
+		if err == nil {
+			reached = true
+		}
+		if ok {
+			return candidate, data, true
+		}
+	}
+	return "", nil, reached
+}
+
+// checkLive fetches the sitemap production serves and validates it: the
+// XML against the sitemaps.org schema, each sitemap of an index, and a
+// sample of the listed URLs, which should answer 200 themselves rather
+// than redirect or 404. A file in the repo doesn't mean the deployed one
+// lists live pages. repo is the result of the repository search, returned
+// as is when production can't be reached.
+func (c SitemapCheck) checkLive(ctx Context, base string, repo CheckResult) CheckResult {
+	sitemapURL, data, reached := findLiveSitemap(ctx, base)
+	if !reached {
+		// healthEndpoint reports a site that's down.
+		repo.Details = append(repo.Details, "Could not reach production to fetch the sitemap")
+		return repo
+	}
+	if sitemapURL == "" {
+		if !repo.Passed {
+			return repo
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  "sitemap.xml not served on production (tried " + strings.Join(liveSitemapPaths, ", ") + " and robots.txt)",
+			Suggestions: []string{
+				"Check that the build deploys it (" + repo.Message + ")",
+			},
+		}
+	}
+
+	doc, problems, err := parseSitemap(data)
+	if err != nil {
+		return CheckResult{
+			ID:          c.ID(),
+			Title:       c.Title(),
+			Severity:    SeverityWarn,
+			Passed:      false,
+			Message:     "Production sitemap " + sitemapURL + " is invalid: " + err.Error(),
+			Suggestions: []string{"Serve a sitemap that follows https://www.sitemaps.org/protocol.html"},
+		}
+	}
+	for i := range problems {
+		problems[i] = sitemapURL + ": " + problems[i]
+	}
+
+	locs, sitemaps, childProblems := sitemapLocs(ctx, doc)
+	problems = append(problems, childProblems...)
+
+	sample := sampleSitemapURLs(locs, sitemapSampleSize)
+	broken := checkSitemapPages(ctx, sample)
+
+	summary := fmt.Sprintf("%d URL(s)", len(locs))
+	if doc.XMLName.Local == "sitemapindex" {
+		summary = fmt.Sprintf("%d sitemap(s), %d URL(s)", sitemaps, len(locs))
+	}
+	if len(broken) > 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  fmt.Sprintf("%d of %d sampled sitemap URLs don't return 200 (%s)", len(broken), len(sample), sitemapURL),
+			Suggestions: append([]string{
+				"List only live, canonical URLs: drop removed pages and use the final URL of redirects",
+			}, limitFindings(append(broken, problems...), 10)...),
+		}
+	}
+	if len(problems) > 0 {
+		return CheckResult{
+			ID:          c.ID(),
+			Title:       c.Title(),
+			Severity:    SeverityWarn,
+			Passed:      false,
+			Message:     fmt.Sprintf("Production sitemap has %d problem(s) (%s)", len(problems), sitemapURL),
+			Suggestions: limitFindings(problems, 10),
+		}
+	}
+	message := fmt.Sprintf("sitemap.xml served at %s: %s", sitemapURL, summary)
+	if len(sample) > 0 {
+		message += fmt.Sprintf(", %d sampled all return 200", len(sample))
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   true,
+		Message:  message,
+	}
+}
+
+// sitemapLocs lists the URLs of a parsed sitemap, fetching the first
+// maxSitemapChildren sitemaps of an index. problems describes children
+// that couldn't be read.
+func sitemapLocs(ctx Context, doc sitemapDoc) (locs []string, sitemaps int, problems []string) {
+	sitemaps = 1
+	if doc.XMLName.Local == "sitemapindex" {
+		children := doc.Sitemaps
+		if len(children) > maxSitemapChildren {
+			children = children[:maxSitemapChildren]
+		}
+		sitemaps = len(children)
+		for _, child := range children {
+			childURL := strings.TrimSpace(child.Loc)
+			if childURL == "" {
+				continue
+			}
+			childData, status, ok, err := fetchSitemap(ctx, childURL)
+			switch {
+			case err != nil:
+				problems = append(problems, fmt.Sprintf("Sitemap %s is unreachable: %v", childURL, err))
+				continue
+			case !ok:
+				problems = append(problems, fmt.Sprintf("Sitemap %s returns HTTP %d", childURL, status))
+				continue
+			}
+			childDoc, childProblems, err := parseSitemap(childData)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("Sitemap %s is invalid: %v", childURL, err))
+				continue
+			}
+			for _, p := range childProblems {
+				problems = append(problems, childURL+": "+p)
+			}
+			for _, u := range childDoc.URLs {
+				locs = append(locs, strings.TrimSpace(u.Loc))
+			}
+		}
+	} else {
+		for _, u := range doc.URLs {
+			locs = append(locs, strings.TrimSpace(u.Loc))
+		}
+	}
+	return locs, sitemaps, problems
+}
+
+// sampleSitemapURLs picks up to n of locs spread evenly across the list,
+// so one section of the site doesn't stand in for all of it.
+func sampleSitemapURLs(locs []string, n int) []string {
+	var valid []string
+	for _, loc := range locs {
+		if strings.HasPrefix(loc, "http://") || strings.HasPrefix(loc, "https://") {
+			valid = append(valid, loc)
+		}
+	}
+	if len(valid) <= n {
+		return valid
+	}
+	sample := make([]string, 0, n)
+	for i := range n {
+		sample = append(sample, valid[i*len(valid)/n])
+	}
+	return sample
+}
+
+// checkSitemapPages requests each URL concurrently without following
+// redirects and describes the ones that don't answer 200. HEAD is tried
+// first; servers that refuse it get a GET.
+func checkSitemapPages(ctx Context, pages []string) []string {
+	client := noRedirectClient(ctx.Client)
+	results := make([]string, len(pages))
+
+	const workers = 4
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = checkSitemapPage(ctx, client, pages[i])
+			}
+		}()
+	}
+	for i := range pages {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var broken []string
+	for _, r := range results {
+		if r != "" {
+			broken = append(broken, r)
+		}
+	}
+	return broken
+}
+
+func checkSitemapPage(ctx Context, client *http.Client, page string) string {
+	req, err := http.NewRequestWithContext(ctx.reqContext(), http.MethodHead, page, nil)
+	if err != nil {
+		return fmt.Sprintf("%s is unreachable: %v", page, err)
+	}
+	req.Header.Set("User-Agent", "Preflight/1.0")
+	resp, err := client.Do(req)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp.Body.Close()
+		resp, err = doGet(ctx.reqContext(), client, page)
+	}
+	if err != nil {
+		return fmt.Sprintf("%s is unreachable: %v", page, err)
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return ""
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return fmt.Sprintf("%s redirects (HTTP %d) to %s", page, resp.StatusCode, resp.Header.Get("Location"))
+	}
+	return fmt.Sprintf("%s returns HTTP %d", page, resp.StatusCode)
+}
@@ -0,0 +1,123 @@
+package checks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestParseSitemap(t *testing.T) {
+	doc, problems, err := parseSitemap([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset>
+  <url><loc>https://example.com/</loc><lastmod>2024-05-01</lastmod><priority>1.0</priority></url>
+  <url><loc>/about</loc><lastmod>May 1st</lastmod><changefreq>sometimes</changefreq><priority>2</priority></url>
+</urlset>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.URLs) != 2 {
+		t.Errorf("URLs = %v", doc.URLs)
+	}
+	want := []string{
+		`<urlset> must declare xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"`,
+		`url 2: <loc> "/about" isn't a full URL`,
+		`url 2: <lastmod> "May 1st" isn't a W3C datetime (2024-05-01 or 2024-05-01T09:30:00+00:00)`,
+		`url 2: <changefreq> "sometimes" isn't one of always, hourly, daily, weekly, monthly, yearly, never`,
+		`url 2: <priority> "2" must be between 0.0 and 1.0`,
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems = %q\nwant %q", problems, want)
+	}
+
+	if _, _, err := parseSitemap([]byte("<rss></rss>")); err == nil || !strings.Contains(err.Error(), "want <urlset> or <sitemapindex>") {
+		t.Errorf("err = %v, want a wrong root element", err)
+	}
+}
+
+func TestSitemapCheckLive(t *testing.T) {
+	var srvURL string
+	sitemaps := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body, ok := sitemaps[r.URL.Path]; ok {
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprint(w, strings.ReplaceAll(body, "BASE", srvURL))
+			return
+		}
+		switch r.URL.Path {
+		case "/", "/about":
+			w.Write([]byte("<html></html>"))
+		case "/old":
+			http.Redirect(w, r, "/about", http.StatusMovedPermanently)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	srvURL = srv.URL
+
+	dir := writeFiles(t, map[string]string{"public/sitemap.xml": "<urlset></urlset>"})
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	run := func(t *testing.T) CheckResult {
+		t.Helper()
+		result, err := SitemapCheck{}.Run(Context{RootDir: dir, Config: cfg, Client: srv.Client()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	const ns = `xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"`
+
+	t.Run("not deployed", func(t *testing.T) {
+		result := run(t)
+		if result.Passed || !strings.HasPrefix(result.Message, "sitemap.xml not served on production") {
+			t.Errorf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+	})
+
+	t.Run("index with broken pages", func(t *testing.T) {
+		sitemaps["/sitemap_index.xml"] = `<sitemapindex ` + ns + `><sitemap><loc>BASE/pages.xml</loc></sitemap><sitemap><loc>BASE/posts.xml</loc></sitemap></sitemapindex>`
+		sitemaps["/pages.xml"] = `<urlset ` + ns + `><url><loc>BASE/</loc></url><url><loc>BASE/old</loc></url><url><loc>BASE/gone</loc></url></urlset>`
+		defer func() { sitemaps = map[string]string{} }()
+		result := run(t)
+		if result.Passed || result.Message != "2 of 3 sampled sitemap URLs don't return 200 ("+srv.URL+"/sitemap_index.xml)" {
+			t.Fatalf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+		suggestions := strings.Join(result.Suggestions, "\n")
+		for _, want := range []string{
+			srv.URL + "/old redirects (HTTP 301) to /about",
+			srv.URL + "/gone returns HTTP 404",
+			"Sitemap " + srv.URL + "/posts.xml returns HTTP 404",
+		} {
+			if !strings.Contains(suggestions, want) {
+				t.Errorf("suggestions missing %q:\n%s", want, suggestions)
+			}
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		sitemaps["/sitemap.xml"] = `<urlset ` + ns + `><url><loc>BASE/</loc><lastmod>2024-05-01T09:30:00+00:00</lastmod></url><url><loc>BASE/about</loc></url></urlset>`
+		defer func() { sitemaps = map[string]string{} }()
+		result := run(t)
+		if !result.Passed || result.Message != "sitemap.xml served at "+srv.URL+"/sitemap.xml: 2 URL(s), 2 sampled all return 200" {
+			t.Errorf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+	})
+}
+
+func TestSampleSitemapURLs(t *testing.T) {
+	var locs []string
+	for i := range 100 {
+		locs = append(locs, fmt.Sprintf("https://example.com/%d", i))
+	}
+	sample := sampleSitemapURLs(locs, 4)
+	want := []string{"https://example.com/0", "https://example.com/25", "https://example.com/50", "https://example.com/75"}
+	if !reflect.DeepEqual(sample, want) {
+		t.Errorf("sample = %v, want %v", sample, want)
+	}
+}
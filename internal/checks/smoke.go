@@ -0,0 +1,204 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/config"
+	"github.com/preflightsh/preflight/internal/netutil"
+)
+
+// smokeTimeout bounds each smoke request. API endpoints get longer than
+// the scan client's default, which is tuned for static files.
+const smokeTimeout = 10 * time.Second
+
+// SmokeCheck calls the API endpoints listed under checks.smoke and
+// compares each response with the status and JSON fields expected of it:
+// the curl script every team writes before launch, kept in preflight.yml.
+type SmokeCheck struct{}
+
+func (c SmokeCheck) ID() string {
+	return "smoke"
+}
+
+func (c SmokeCheck) Title() string {
+	return "API smoke tests"
+}
+
+// smokeTargets returns the base URLs the endpoints are called against,
+// keyed by target name, in the order given.
+func smokeTargets(cfg *config.PreflightConfig) [][2]string {
+	targets := cfg.Checks.Smoke.Targets
+	if len(targets) == 0 {
+		targets = []string{"production"}
+		if cfg.URLs.Staging != "" {
+			targets = []string{"staging"}
+		}
+	}
+	var out [][2]string
+	for _, name := range targets {
+		base := cfg.URLs.Production
+		if name == "staging" {
+			base = cfg.URLs.Staging
+		}
+		if base == "" {
+			continue
+		}
+		if !strings.HasPrefix(base, "http://") && !strings.HasPrefix(base, "https://") {
+			base = "https://" + base
+		}
+		out = append(out, [2]string{name, strings.TrimSuffix(base, "/")})
+	}
+	return out
+}
+
+func (c SmokeCheck) Run(ctx Context) (CheckResult, error) {
+	smoke := ctx.Config.Checks.Smoke
+	var targets [][2]string
+	if smoke != nil {
+		targets = smokeTargets(ctx.Config)
+	}
+	if smoke == nil || len(smoke.Endpoints) == 0 || len(targets) == 0 || ctx.Client == nil {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Skipped (no endpoints or no URL to call them on)",
+		}, nil
+	}
+
+	// Redirects are responses to check, not to follow: an endpoint
+	// expected to answer 401 that redirects to a login page is a failure.
+	client := *ctx.Client
+	client.Timeout = smokeTimeout
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	var failures, details, names []string
+	total := 0
+	for _, target := range targets {
+		names = append(names, target[0])
+		for _, endpoint := range smoke.Endpoints {
+			total++
+			label := endpoint.String()
+			if len(targets) > 1 {
+				label = target[0] + ": " + label
+			}
+			if problem := runSmokeTest(ctx, &client, target[1], endpoint); problem != "" {
+				failures = append(failures, label+" - "+problem)
+				continue
+			}
+			details = append(details, fmt.Sprintf("%s - %d as expected", label, endpoint.Status))
+		}
+	}
+
+	if len(failures) > 0 {
+		return CheckResult{
+			ID:          c.ID(),
+			Title:       c.Title(),
+			Severity:    SeverityWarn,
+			Passed:      false,
+			Message:     fmt.Sprintf("%d of %d smoke test(s) failed against %s", len(failures), total, strings.Join(names, " and ")),
+			Suggestions: limitFindings(failures, 10),
+			Details:     details,
+		}, nil
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   true,
+		Message:  fmt.Sprintf("%d smoke test(s) passed against %s", total, strings.Join(names, " and ")),
+		Details:  details,
+	}, nil
+}
+
+// runSmokeTest makes endpoint's request against base and describes how
+// the response falls short, or returns "".
+func runSmokeTest(ctx Context, client *http.Client, base string, endpoint config.SmokeEndpoint) string {
+	var body io.Reader
+	payload := os.ExpandEnv(endpoint.Body)
+	if payload != "" {
+		body = strings.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx.reqContext(), endpoint.Method, base+endpoint.Path, body)
+	if err != nil {
+		return err.Error()
+	}
+	req.Header.Set("User-Agent", "Preflight/1.0")
+	if trimmed := strings.TrimSpace(payload); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range endpoint.Headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "request failed: " + err.Error()
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != endpoint.Status {
+		return fmt.Sprintf("expected %d, got %d", endpoint.Status, resp.StatusCode)
+	}
+	if len(endpoint.JSON) == 0 {
+		return ""
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, netutil.MaxResponseBody))
+	if err != nil {
+		return "could not read response: " + err.Error()
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "response isn't JSON"
+	}
+	var missing []string
+	for _, path := range endpoint.JSON {
+		if !hasJSONPath(doc, path) {
+			missing = append(missing, path)
+		}
+	}
+	if len(missing) > 0 {
+		return "response has no " + strings.Join(missing, ", ")
+	}
+	return ""
+}
+
+// hasJSONPath reports whether doc has a value at the dotted path, where
+// numeric segments index arrays.
+func hasJSONPath(doc any, path string) bool {
+	_, ok := jsonPathValue(doc, path)
+	return ok
+}
+
+// jsonPathValue returns the value at the dotted path in doc.
+func jsonPathValue(doc any, path string) (any, bool) {
+	for _, segment := range strings.Split(path, ".") {
+		switch v := doc.(type) {
+		case map[string]any:
+			next, ok := v[segment]
+			if !ok {
+				return nil, false
+			}
+			doc = next
+		case []any:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			doc = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return doc, true
+}
@@ -0,0 +1,53 @@
+package checks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestSmokeCheck(t *testing.T) {
+	t.Setenv("SMOKE_TOKEN", "secret")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/signup":
+			w.WriteHeader(http.StatusBadRequest)
+		case r.URL.Path == "/api/me" && r.Header.Get("Authorization") == "Bearer secret":
+			fmt.Fprint(w, `{"user": {"id": 1, "roles": ["admin"]}}`)
+		case r.URL.Path == "/api/me":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/account":
+			http.Redirect(w, r, "/login", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Staging = srv.URL
+	cfg.Checks.Smoke = &config.SmokeConfig{Endpoints: []config.SmokeEndpoint{
+		{Method: "POST", Path: "/api/signup", Status: 400},
+		{Method: "GET", Path: "/api/me", Status: 200, Headers: map[string]string{"Authorization": "Bearer ${SMOKE_TOKEN}"}, JSON: []string{"user.id", "user.roles.0", "user.email"}},
+		{Method: "GET", Path: "/account", Status: 200},
+	}}
+	result, err := SmokeCheck{}.Run(Context{Config: cfg, Client: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"GET /api/me - response has no user.email",
+		"GET /account - expected 200, got 302",
+	}
+	if result.Passed || !reflect.DeepEqual(result.Suggestions, want) {
+		t.Errorf("got passed=%v suggestions=%q", result.Passed, result.Suggestions)
+	}
+	if !strings.HasPrefix(result.Message, "2 of 3 smoke test(s) failed against staging") {
+		t.Errorf("message = %q", result.Message)
+	}
+}
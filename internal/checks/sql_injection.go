@@ -0,0 +1,124 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SQLInjectionCheck flags SQL built by concatenating or interpolating
+// values into the query string, in application code. Like redirect_ssrf
+// it reads one line at a time and can't tell a constant from user input,
+// so a finding is a smell to review, but parameterized queries never need
+// one of these lines.
+type SQLInjectionCheck struct{}
+
+func (c SQLInjectionCheck) ID() string {
+	return "sql_injection"
+}
+
+func (c SQLInjectionCheck) Title() string {
+	return "String-built SQL"
+}
+
+// sqlKeywords matches the uppercase SQL that marks a string literal as a
+// query. Lowercase is left out so UI copy ("select a file from...") doesn't
+// count.
+const sqlKeywords = `(?:SELECT\b[^"'` + "`" + `]*?\bFROM\b|INSERT\s+INTO\b|UPDATE\s+[\w."` + "`" + `]+\s+SET\b|DELETE\s+FROM\b|\bWHERE\b|\bORDER\s+BY\b)`
+
+// sqlConcatExts are the languages that build strings with +.
+var sqlConcatExts = []string{".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs", ".java", ".kt", ".cs", ".py", ".go"}
+
+type sqlSmellPattern struct {
+	pattern     *regexp.Regexp
+	description string
+	extensions  []string
+}
+
+// sqlLiteral matches a complete single- or double-quoted string holding
+// SQL. The quotes are matched separately so a query quoting its values
+// ("WHERE name = '" + name) is still one string.
+const sqlLiteral = `(?:"[^"\n]*` + sqlKeywords + `[^"\n]*"|'[^'\n]*` + sqlKeywords + `[^'\n]*')`
+
+var sqlSmellPatterns = []sqlSmellPattern{
+	{regexp.MustCompile(sqlLiteral + `\s*\+\s*[A-Za-z_$(]`), "concatenated into the query", sqlConcatExts},
+	{regexp.MustCompile(`(?:^|[^\w$.\]])` + "`" + `[^` + "`" + `]*` + sqlKeywords + `[^` + "`" + `]*\$\{`), "interpolated into a template literal", jsSinkExts},
+	{regexp.MustCompile(`\bf(?:"[^"\n]*` + sqlKeywords + `[^"\n]*\{|'[^'\n]*` + sqlKeywords + `[^'\n]*\{)`), "interpolated with an f-string", pySinkExts},
+	{regexp.MustCompile(sqlLiteral + `\.format\(`), "formatted with .format()", pySinkExts},
+	{regexp.MustCompile(`(?:"[^"\n]*` + sqlKeywords + `[^"\n]*%[sd][^"\n]*"|'[^'\n]*` + sqlKeywords + `[^'\n]*%[sd][^'\n]*')\s*%\s*[\w(]`), "formatted with %", pySinkExts},
+	{regexp.MustCompile(`"[^"\n]*` + sqlKeywords + `[^"\n]*#\{|\.(?:where|having|order|find_by_sql|exec_query|execute)\(\s*"[^"\n]*#\{`), "interpolated with #{}", rbSinkExts},
+	{regexp.MustCompile(sqlLiteral + `\s*\.\s*\$`), "concatenated into the query", phpSinkExts},
+	{regexp.MustCompile(`"[^"\n]*` + sqlKeywords + `[^"\n]*\{?\$[A-Za-z_]`), "interpolated into a double-quoted string", phpSinkExts},
+	{regexp.MustCompile(`\bfmt\.Sprintf\(\s*(?:"[^"\n]*` + sqlKeywords + `[^"\n]*%[svq]|` + "`" + `[^` + "`" + `]*` + sqlKeywords + `[^` + "`" + `]*%[svq])`), "formatted with fmt.Sprintf", goSinkExts},
+}
+
+// sqlSkipDirs hold schema changes, seed data and one-off scripts, where
+// built SQL runs on trusted input.
+var sqlSkipDirs = map[string]bool{
+	"migrations": true, "migrate": true, "seeds": true, "seeders": true, "scripts": true,
+	"fixtures": true, "examples": true, "docs": true,
+}
+
+func (c SQLInjectionCheck) Run(ctx Context) (CheckResult, error) {
+	files := ctx.files()
+	var findings []Location
+	var listed []string
+	for _, f := range files.Files {
+		if f.Ignored || f.Size > 500*1024 || f.inDir(templateSkipDirs) || f.inDir(sqlSkipDirs) || isTestSource(f.Path) {
+			continue
+		}
+		var patterns []sqlSmellPattern
+		for _, p := range sqlSmellPatterns {
+			if hasExtension(f.Name, p.extensions) {
+				patterns = append(patterns, p)
+			}
+		}
+		if len(patterns) == 0 {
+			continue
+		}
+		content, err := readFile(files.Abs(f))
+		if err != nil {
+			continue
+		}
+		for i, line := range strings.Split(string(content), "\n") {
+			if reCommentLine.MatchString(line) {
+				continue
+			}
+			line = stripCodeComments(line)
+			for _, p := range patterns {
+				if p.pattern.MatchString(line) {
+					loc := Location{File: f.Path, Line: i + 1, Message: "SQL " + p.description}
+					findings = append(findings, loc)
+					listed = append(listed, loc.String())
+					break
+				}
+			}
+		}
+	}
+
+	if len(findings) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No string-built SQL found",
+		}, nil
+	}
+	message := fmt.Sprintf("To review: %d queries built from strings", len(findings))
+	if len(findings) == 1 {
+		message = "To review: " + findings[0].String()
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  message,
+		Suggestions: append([]string{
+			"Pass values as query parameters (?, $1, :name) or through the ORM's query builder instead of building the SQL string",
+			"Table and column names can't be parameters; pick them from a fixed list",
+		}, limitFindings(listed, 8)...),
+		Locations: findings,
+	}, nil
+}
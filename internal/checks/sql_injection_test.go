@@ -0,0 +1,56 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestSQLInjectionCheck(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"src/users.ts": "const rows = await db.query(\"SELECT * FROM users WHERE id = \" + req.params.id)\n" +
+			"const more = await db.query(`SELECT * FROM orders WHERE user_id = ${userId}`)\n" +
+			"const safe = await sql`SELECT * FROM users WHERE id = ${id}`\n" +
+			"const prisma = await prisma.$queryRaw`SELECT * FROM users WHERE id = ${id}`\n" +
+			"const param = await db.query('SELECT * FROM users WHERE id = $1', [id])\n" +
+			"const label = 'Select a file from your computer' + suffix\n" +
+			"const byName = await db.query(\"SELECT * FROM users WHERE name = '\" + name + \"'\")\n",
+		"app/db.py": "cursor.execute(f\"SELECT * FROM users WHERE email = '{email}'\")\n" +
+			"cursor.execute(\"DELETE FROM sessions WHERE id = %s\" % session_id)\n" +
+			"cursor.execute(\"SELECT * FROM users WHERE id = %s\", (user_id,))\n",
+		"app/models/user.rb":      "User.where(\"name = '#{params[:name]}'\")\nUser.where(name: params[:name])\n",
+		"app/Models/Report.php":   "$rows = DB::select(\"SELECT * FROM reports WHERE owner = $owner\");\n$rows = DB::select('SELECT * FROM reports WHERE owner = ?', [$owner]);\n",
+		"internal/store/store.go": "q := fmt.Sprintf(\"SELECT * FROM items WHERE name = '%s'\", name)\nq2 := fmt.Sprintf(\"SELECT * FROM items LIMIT %d\", n)\n",
+		"db/migrations/001.py":    "cursor.execute(f\"UPDATE users SET plan = '{plan}'\")\n",
+		"src/users.test.ts":       "db.query(\"SELECT * FROM users WHERE id = \" + id)\n",
+	})
+	result, err := SQLInjectionCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed {
+		t.Fatal("expected findings")
+	}
+	got := map[string]bool{}
+	for _, loc := range result.Locations {
+		got[loc.String()] = true
+	}
+	want := []string{
+		"src/users.ts:1 - SQL concatenated into the query",
+		"src/users.ts:2 - SQL interpolated into a template literal",
+		"src/users.ts:7 - SQL concatenated into the query",
+		"app/db.py:1 - SQL interpolated with an f-string",
+		"app/db.py:2 - SQL formatted with %",
+		"app/models/user.rb:1 - SQL interpolated with #{}",
+		"app/Models/Report.php:1 - SQL interpolated into a double-quoted string",
+		"internal/store/store.go:1 - SQL formatted with fmt.Sprintf",
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("missing %q in %v", w, result.Locations)
+		}
+	}
+	if len(result.Locations) != len(want) {
+		t.Errorf("got %d locations, want %d: %v", len(result.Locations), len(want), result.Locations)
+	}
+}
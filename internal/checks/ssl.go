@@ -1,11 +1,16 @@
 package checks
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
 	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/preflightsh/preflight/internal/netutil"
@@ -62,10 +67,24 @@ func (c SSLCheck) Run(ctx Context) (CheckResult, error) {
 		host += ":443"
 	}
 
+	// A failed verification still hands over what the server sent, so
+	// the chain can be judged rather than just reported as untrusted.
+	var certs []*x509.Certificate
+	var verifyErr error
+	version := uint16(0)
+	countNetworkCall()
 	conn, err := netutil.SafeTLSDial("tcp", host, &tls.Config{
 		MinVersion: tls.VersionTLS12,
 	}, 10*time.Second)
-	if err != nil {
+	var certErr *tls.CertificateVerificationError
+	switch {
+	case err == nil:
+		state := conn.ConnectionState()
+		certs, version = state.PeerCertificates, state.Version
+		_ = conn.Close()
+	case errors.As(err, &certErr) && len(certErr.UnverifiedCertificates) > 0:
+		certs, verifyErr = certErr.UnverifiedCertificates, certErr.Err
+	default:
 		return CheckResult{
 			ID:       c.ID(),
 			Title:    c.Title(),
@@ -74,9 +93,7 @@ func (c SSLCheck) Run(ctx Context) (CheckResult, error) {
 			Message:  sanitizeTLSDialError(err),
 		}, nil
 	}
-	defer func() { _ = conn.Close() }()
 
-	certs := conn.ConnectionState().PeerCertificates
 	if len(certs) == 0 {
 		return CheckResult{
 			ID:       c.ID(),
@@ -89,59 +106,178 @@ func (c SSLCheck) Run(ctx Context) (CheckResult, error) {
 
 	cert := certs[0]
 	now := time.Now()
-
-	// Check expiration
 	daysUntilExpiry := int(cert.NotAfter.Sub(now).Hours() / 24)
 
-	if now.After(cert.NotAfter) {
-		return CheckResult{
-			ID:       c.ID(),
-			Title:    c.Title(),
-			Severity: SeverityError,
-			Passed:   false,
-			Message:  "SSL certificate has expired",
-			Suggestions: []string{
-				"Renew your SSL certificate immediately",
-			},
-		}, nil
+	var errs, warns, suggestions []string
+	add := func(severe bool, problem, fix string) {
+		if severe {
+			errs = append(errs, problem)
+		} else {
+			warns = append(warns, problem)
+		}
+		if fix != "" && !slices.Contains(suggestions, fix) {
+			suggestions = append(suggestions, fix)
+		}
 	}
 
-	if daysUntilExpiry <= 7 {
-		return CheckResult{
-			ID:       c.ID(),
-			Title:    c.Title(),
-			Severity: SeverityError,
-			Passed:   false,
-			Message:  fmt.Sprintf("SSL certificate expires in %d days", daysUntilExpiry),
-			Suggestions: []string{
-				"Renew your SSL certificate soon",
-				"Consider enabling auto-renewal",
-			},
-		}, nil
+	switch {
+	case now.After(cert.NotAfter):
+		add(true, "SSL certificate has expired", "Renew your SSL certificate immediately")
+	case daysUntilExpiry <= 7:
+		add(true, fmt.Sprintf("SSL certificate expires in %d days", daysUntilExpiry), "Renew your SSL certificate soon and enable auto-renewal")
+	case daysUntilExpiry < sslExpiryWarnDays:
+		add(false, fmt.Sprintf("SSL certificate expires in %d days", daysUntilExpiry), "Plan to renew your SSL certificate, or check why auto-renewal hasn't run")
+	}
+	if problem, fix := chainProblem(verifyErr, certs, now); problem != "" {
+		add(true, problem, fix)
+	}
+	for _, problem := range weakCertProblems(certs) {
+		add(true, problem, "Reissue the certificate with a 2048-bit RSA or P-256 ECDSA key, signed with SHA-256")
+	}
+	for _, v := range legacyTLSVersions(func(cfg *tls.Config) error {
+		countNetworkCall()
+		conn, err := netutil.SafeTLSDial("tcp", host, cfg, 5*time.Second)
+		if err == nil {
+			_ = conn.Close()
+		}
+		return err
+	}) {
+		add(false, "Server still accepts "+v, "Disable TLS 1.0 and 1.1 on the server or CDN; every current browser speaks TLS 1.2")
+	}
+
+	details := []string{fmt.Sprintf("Issued by %s, expires %s", certName(cert.Issuer.CommonName, cert.Issuer.Organization), cert.NotAfter.Format("2006-01-02"))}
+	if version != 0 {
+		details = append(details, "Negotiated "+tls.VersionName(version))
 	}
 
-	if daysUntilExpiry <= 30 {
+	if len(errs) == 0 && len(warns) == 0 {
 		return CheckResult{
 			ID:       c.ID(),
 			Title:    c.Title(),
-			Severity: SeverityWarn,
-			Passed:   false,
-			Message:  fmt.Sprintf("SSL certificate expires in %d days", daysUntilExpiry),
-			Suggestions: []string{
-				"Plan to renew your SSL certificate",
-			},
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("Valid, expires in %d days", daysUntilExpiry),
+			Details:  details,
 		}, nil
 	}
-
+	severity := SeverityWarn
+	if len(errs) > 0 {
+		severity = SeverityError
+	}
 	return CheckResult{
-		ID:       c.ID(),
-		Title:    c.Title(),
-		Severity: SeverityInfo,
-		Passed:   true,
-		Message:  fmt.Sprintf("Valid, expires in %d days", daysUntilExpiry),
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    severity,
+		Passed:      false,
+		Message:     strings.Join(append(errs, warns...), "; "),
+		Suggestions: suggestions,
+		Details:     details,
 	}, nil
 }
 
+// sslExpiryWarnDays is how close to expiry a certificate gets a warning:
+// three weeks leaves room for a failed auto-renewal to be noticed and
+// fixed.
+const sslExpiryWarnDays = 21
+
+// chainProblem explains a failed chain verification. Expiry and weak
+// signatures are left to the checks that report them in detail. Names
+// from the leaf certificate are kept out of the message, as in
+// sanitizeTLSDialError.
+func chainProblem(verifyErr error, certs []*x509.Certificate, now time.Time) (problem, fix string) {
+	if verifyErr == nil {
+		return "", ""
+	}
+	var hostErr x509.HostnameError
+	var authErr x509.UnknownAuthorityError
+	var invalidErr x509.CertificateInvalidError
+	var algErr x509.InsecureAlgorithmError
+	switch {
+	case errors.As(verifyErr, &algErr):
+		return "", ""
+	case errors.As(verifyErr, &hostErr):
+		return "Certificate hostname mismatch", "Issue a certificate whose names include the production host"
+	case errors.As(verifyErr, &invalidErr) && invalidErr.Reason == x509.Expired:
+		for _, c := range certs[1:] {
+			if now.After(c.NotAfter) {
+				return fmt.Sprintf("Intermediate certificate %s has expired", certName(c.Subject.CommonName, c.Subject.Organization)),
+					"Serve the current intermediate chain from your certificate authority"
+			}
+		}
+		return "", ""
+	case errors.As(verifyErr, &authErr):
+		leaf := certs[0]
+		if bytes.Equal(leaf.RawIssuer, leaf.RawSubject) {
+			return "Certificate is self-signed", "Use a certificate from a public authority such as Let's Encrypt"
+		}
+		if len(certs) == 1 {
+			return "Certificate chain is incomplete: the server sends no intermediate certificates",
+				"Serve the full chain (fullchain.pem), not just the site's own certificate"
+		}
+		return "Certificate chain doesn't lead to a trusted root", "Serve the intermediate chain your certificate authority provides"
+	}
+	return "Certificate verification failed", "Check the certificate chain with openssl s_client -showcerts"
+}
+
+// weakCertProblems reports served certificates signed with SHA-1 or MD5,
+// or carrying keys too short to be trusted. Self-signed roots are skipped:
+// their signatures are never checked.
+func weakCertProblems(certs []*x509.Certificate) []string {
+	var problems []string
+	for i, c := range certs {
+		if i > 0 && bytes.Equal(c.RawIssuer, c.RawSubject) {
+			continue
+		}
+		which := "Certificate"
+		if i > 0 {
+			which = "Intermediate certificate " + certName(c.Subject.CommonName, c.Subject.Organization)
+		}
+		switch c.SignatureAlgorithm {
+		case x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1, x509.MD5WithRSA, x509.MD2WithRSA:
+			problems = append(problems, fmt.Sprintf("%s is signed with %s", which, c.SignatureAlgorithm))
+		}
+		switch key := c.PublicKey.(type) {
+		case *rsa.PublicKey:
+			if bits := key.N.BitLen(); bits < 2048 {
+				problems = append(problems, fmt.Sprintf("%s has a %d-bit RSA key", which, bits))
+			}
+		case *ecdsa.PublicKey:
+			if bits := key.Curve.Params().BitSize; bits < 256 {
+				problems = append(problems, fmt.Sprintf("%s has a %d-bit ECDSA key", which, bits))
+			}
+		}
+	}
+	return problems
+}
+
+// legacyTLSVersions tries a handshake pinned to each of TLS 1.0 and 1.1
+// and returns the versions the server accepted. A handshake that fails
+// only at certificate verification still agreed on the version.
+func legacyTLSVersions(handshake func(*tls.Config) error) []string {
+	var accepted []string
+	for _, v := range []uint16{tls.VersionTLS10, tls.VersionTLS11} {
+		// #nosec G402 -- probing whether the server allows these versions; nothing is sent over the connection.
+		err := handshake(&tls.Config{MinVersion: v, MaxVersion: v})
+		var certErr *tls.CertificateVerificationError
+		if err == nil || errors.As(err, &certErr) {
+			accepted = append(accepted, tls.VersionName(v))
+		}
+	}
+	return accepted
+}
+
+// certName names a certificate by its common name, or its organization
+// when it has none.
+func certName(commonName string, org []string) string {
+	switch {
+	case commonName != "":
+		return commonName
+	case len(org) > 0:
+		return org[0]
+	}
+	return "(unnamed)"
+}
+
 // sanitizeTLSDialError formats a dial/TLS error for the user-visible
 // Message field without leaking internal hostnames learned from cert
 // subjects back to the caller. Both x509.HostnameError and Go 1.20+'s
@@ -0,0 +1,141 @@
+package checks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCert issues a certificate for name, signed by parent (self-signed
+// when parent is nil).
+func testCert(t *testing.T, name string, key, parentKey any, parent *x509.Certificate, ca bool) *x509.Certificate {
+	t.Helper()
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(90 * 24 * time.Hour),
+		DNSNames:              []string{name},
+		IsCA:                  ca,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	var pub any
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		pub = &k.PublicKey
+	case *ecdsa.PrivateKey:
+		pub = &k.PublicKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestChainProblem(t *testing.T) {
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	leafKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ca := testCert(t, "Test CA", caKey, nil, nil, true)
+	leaf := testCert(t, "example.com", leafKey, caKey, ca, false)
+	self := testCert(t, "example.com", leafKey, nil, nil, false)
+
+	verify := func(certs ...*x509.Certificate) error {
+		intermediates := x509.NewCertPool()
+		for _, c := range certs[1:] {
+			intermediates.AddCert(c)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{Roots: x509.NewCertPool(), Intermediates: intermediates, DNSName: "example.com"})
+		return err
+	}
+	for _, tc := range []struct {
+		name  string
+		certs []*x509.Certificate
+		want  string
+	}{
+		{"leaf only", []*x509.Certificate{leaf}, "the server sends no intermediate certificates"},
+		{"untrusted root", []*x509.Certificate{leaf, ca}, "doesn't lead to a trusted root"},
+		{"self-signed", []*x509.Certificate{self}, "self-signed"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			problem, fix := chainProblem(verify(tc.certs...), tc.certs, time.Now())
+			if !strings.Contains(problem, tc.want) || fix == "" {
+				t.Errorf("chainProblem = %q, %q; want %q", problem, fix, tc.want)
+			}
+		})
+	}
+	if problem, _ := chainProblem(nil, []*x509.Certificate{leaf}, time.Now()); problem != "" {
+		t.Errorf("chainProblem(nil) = %q, want none", problem)
+	}
+}
+
+func TestWeakCertProblems(t *testing.T) {
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	strongKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	weak := testCert(t, "example.com", weakKey, nil, nil, false)
+	strong := testCert(t, "example.com", strongKey, nil, nil, false)
+
+	if got := weakCertProblems([]*x509.Certificate{weak}); !reflect.DeepEqual(got, []string{"Certificate has a 1024-bit RSA key"}) {
+		t.Errorf("weakCertProblems(1024-bit RSA) = %q", got)
+	}
+	if got := weakCertProblems([]*x509.Certificate{strong}); len(got) != 0 {
+		t.Errorf("weakCertProblems(P-256) = %q, want none", got)
+	}
+}
+
+func TestLegacyTLSVersions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		min  uint16
+		want []string
+	}{
+		{"modern only", tls.VersionTLS12, nil},
+		{"legacy allowed", tls.VersionTLS10, []string{"TLS 1.0", "TLS 1.1"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+			srv.TLS = &tls.Config{MinVersion: tc.min}
+			srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+			srv.StartTLS()
+			defer srv.Close()
+
+			got := legacyTLSVersions(func(cfg *tls.Config) error {
+				// Verification fails against the test certificate, which
+				// still counts as the version being accepted.
+				conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", srv.Listener.Addr().String(), cfg)
+				if err == nil {
+					conn.Close()
+				}
+				return err
+			})
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("legacyTLSVersions = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
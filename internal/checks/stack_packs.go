@@ -0,0 +1,38 @@
+package checks
+
+import (
+	"sync"
+
+	"github.com/preflightsh/preflight/internal/stacks"
+)
+
+// stackPacks are the stack packs for the scan in progress: the built-in
+// ones, plus the project's .preflight/stacks when it has any.
+var stackPacks = struct {
+	sync.RWMutex
+	set *stacks.Set
+}{set: stacks.Builtin()}
+
+// ConfigureStacks sets the stack packs for the scan about to run. Like
+// ConfigureWalk, it applies process-wide; scans run one at a time.
+func ConfigureStacks(s *stacks.Set) {
+	if s == nil {
+		s = stacks.Builtin()
+	}
+	stackPacks.Lock()
+	defer stackPacks.Unlock()
+	stackPacks.set = s
+}
+
+func currentStacks() *stacks.Set {
+	stackPacks.RLock()
+	defer stackPacks.RUnlock()
+	return stackPacks.set
+}
+
+// stackPack returns the named stack's conventions, falling back to the
+// default pack's for a stack without one.
+func stackPack(name string) stacks.Pack {
+	p, _ := currentStacks().Get(name)
+	return p
+}
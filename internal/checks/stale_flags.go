@@ -0,0 +1,208 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+// StaleFlagsCheck finds temporary feature flags (tmp_, killswitch_ and the
+// like) that have outlived their purpose, going by the date in a comment
+// next to the flag or in its name. Left in, they keep dead branches alive
+// and a stray toggle in the dashboard can still flip launch behaviour.
+type StaleFlagsCheck struct{}
+
+func (c StaleFlagsCheck) ID() string {
+	return "stale_flags"
+}
+
+func (c StaleFlagsCheck) Title() string {
+	return "Stale feature flags"
+}
+
+// defaultTempFlagPrefixes name flags meant to be removed.
+var defaultTempFlagPrefixes = []string{"tmp_", "tmp-", "temp_", "temp-", "temporary_", "killswitch_", "kill_switch_", "kill-switch-"}
+
+// defaultStaleFlagMonths is how old a dated temporary flag can get.
+const defaultStaleFlagMonths = 3
+
+var (
+	// reFlagDate matches a date in a comment: 2026-03-01, 2026/03 or
+	// 2026.03.01.
+	reFlagDate = regexp.MustCompile(`\b(20\d\d)[-/.](0[1-9]|1[0-2])(?:[-/.](0[1-9]|[12]\d|3[01]))?\b`)
+	// reFlagNameDate matches a year and month in a flag name:
+	// tmp_checkout_2026_03 or killswitch-202603.
+	reFlagNameDate = regexp.MustCompile(`(20\d\d)[_-]?(0[1-9]|1[0-2])(?:[_-]?(0[1-9]|[12]\d|3[01]))?(?:[^\d]|$)`)
+	// reCommentLine matches a line that's only a comment.
+	reCommentLine = regexp.MustCompile(`^\s*(?://|#|/?\*|<!--|\{\{!--|--)`)
+	// reFlagDeadline matches wording that makes the date a removal date
+	// rather than the day the flag was added.
+	reFlagDeadline = regexp.MustCompile(`(?i)\b(?:remove|removal|expires?|expiry|delete|clean ?up|sunset|until|by|todo)\b`)
+)
+
+// appSourceExts are the source and component files application code is
+// read from.
+var appSourceExts = map[string]bool{
+	".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true, ".cjs": true,
+	".vue": true, ".svelte": true, ".astro": true, ".py": true, ".rb": true, ".erb": true,
+	".php": true, ".go": true, ".java": true, ".kt": true, ".ex": true, ".exs": true,
+	".cs": true, ".rs": true, ".swift": true,
+}
+
+// tempFlag is the first dated reference to a temporary flag.
+type tempFlag struct {
+	name     string
+	loc      Location
+	date     time.Time
+	deadline bool
+}
+
+func (c StaleFlagsCheck) Run(ctx Context) (CheckResult, error) {
+	cfg := ctx.Config.Checks.StaleFlags
+	if cfg == nil {
+		cfg = &config.StaleFlagsConfig{}
+	}
+	prefixes := cfg.Prefixes
+	if len(prefixes) == 0 {
+		prefixes = defaultTempFlagPrefixes
+	}
+	months := cfg.MaxAgeMonths
+	if months <= 0 {
+		months = defaultStaleFlagMonths
+	}
+	quoted := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		quoted[i] = regexp.QuoteMeta(p)
+	}
+	reFlag := regexp.MustCompile("[\"'`]((?i:" + strings.Join(quoted, "|") + `)[\w.:-]+)["'` + "`]")
+
+	flags := map[string]*tempFlag{}
+	files := ctx.files()
+	for _, f := range files.Files {
+		if f.Ignored || !appSourceExts[f.Ext] || f.Size > 500*1024 || f.inDir(templateSkipDirs) || isTestSource(f.Path) {
+			continue
+		}
+		if strings.HasPrefix(f.Path, ".") || strings.Contains(f.Path, "/.") {
+			continue
+		}
+		content, err := readFile(files.Abs(f))
+		if err != nil {
+			continue
+		}
+		lines := strings.Split(string(content), "\n")
+		for _, m := range reFlag.FindAllSubmatchIndex(content, -1) {
+			name := string(content[m[2]:m[3]])
+			if flag, ok := flags[name]; ok && !flag.date.IsZero() {
+				continue
+			}
+			line := lineAt(content, m[0])
+			flag := &tempFlag{name: name, loc: Location{File: f.Path, Line: line}}
+			flag.date, flag.deadline = flagDate(name, lines, line)
+			if _, ok := flags[name]; !ok || !flag.date.IsZero() {
+				flags[name] = flag
+			}
+		}
+	}
+
+	if len(flags) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "No temporary feature flags found",
+		}, nil
+	}
+
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	now := time.Now()
+	cutoff := now.AddDate(0, -months, 0)
+	var findings, undated []string
+	var locations []Location
+	for _, name := range names {
+		flag := flags[name]
+		switch {
+		case flag.date.IsZero():
+			undated = append(undated, name)
+			continue
+		case flag.deadline && flag.date.Before(now):
+			flag.loc.Message = fmt.Sprintf("%s is past its removal date (%s)", name, flag.date.Format("2006-01-02"))
+		case !flag.deadline && flag.date.Before(cutoff):
+			flag.loc.Message = fmt.Sprintf("%s dates from %s, over %d months ago", name, flag.date.Format("2006-01"), months)
+		default:
+			continue
+		}
+		findings = append(findings, flag.loc.String())
+		locations = append(locations, flag.loc)
+	}
+
+	var details []string
+	if len(undated) > 0 {
+		details = append(details, fmt.Sprintf("%d temporary flags with no date to judge: %s", len(undated), strings.Join(limitFindings(undated, 5), ", ")))
+	}
+	if len(findings) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  fmt.Sprintf("%d temporary flags, none past due", len(flags)),
+			Details:  details,
+		}, nil
+	}
+
+	message := fmt.Sprintf("%d stale temporary flags", len(findings))
+	if len(findings) == 1 {
+		message = "Temporary flag " + locations[0].Message
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  message,
+		Suggestions: append([]string{
+			"Remove the flag and the branch it no longer needs, then archive it in the flag dashboard",
+			"If the flag is still needed, update the date in its comment",
+		}, limitFindings(findings, 8)...),
+		Details:   details,
+		Locations: locations,
+	}, nil
+}
+
+// flagDate returns the date for the flag referenced on line (1-based):
+// one in its name, on that line, or in the comment lines directly above.
+// The boolean reports whether the date is a removal deadline.
+func flagDate(name string, lines []string, line int) (time.Time, bool) {
+	if m := reFlagNameDate.FindStringSubmatch(name); m != nil {
+		return parseFlagDate(m[1], m[2], m[3]), false
+	}
+	for i := line - 1; i >= 0 && i < len(lines) && i >= line-4; i-- {
+		text := lines[i]
+		if i < line-1 && !reCommentLine.MatchString(text) {
+			break
+		}
+		if loc := reFlagDate.FindStringIndex(text); loc != nil {
+			m := reFlagDate.FindStringSubmatch(text)
+			return parseFlagDate(m[1], m[2], m[3]), reFlagDeadline.MatchString(text[:loc[0]])
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseFlagDate builds a date from matched year, month and optional day.
+func parseFlagDate(year, month, day string) time.Time {
+	if day == "" {
+		day = "01"
+	}
+	t, _ := time.Parse("2006-01-02", year+"-"+month+"-"+day)
+	return t
+}
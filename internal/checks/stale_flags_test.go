@@ -0,0 +1,73 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestStaleFlagsCheck(t *testing.T) {
+	now := time.Now()
+	old := now.AddDate(0, -8, 0)
+	recent := now.AddDate(0, -1, 0)
+	past := now.AddDate(0, 0, -10).Format("2006-01-02")
+	future := now.AddDate(0, 2, 0).Format("2006-01-02")
+
+	dir := writeFiles(t, map[string]string{
+		"src/checkout.ts": "// added " + old.Format("2006-01-02") + " for the pricing test\n" +
+			"if (ldClient.variation('tmp_new_pricing', false)) {}\n" +
+			"// remove after " + past + "\n" +
+			"const banner = posthog.isFeatureEnabled(\"killswitch_banner\")\n" +
+			"// remove by " + future + "\n" +
+			"const promo = flags.isEnabled('temp_promo')\n" +
+			"const legacy = unleash.isEnabled('tmp_legacy_export')\n",
+		"app/models/order.rb": "# " + recent.Format("2006-01-02") + "\n" +
+			"Flipper.enabled?(\"tmp_refunds\")\n" +
+			"ld.variation(\"tmp_checkout_" + old.Format("2006_01") + "\", user, false)\n",
+		"src/checkout.test.ts": "expect(flags('tmp_in_tests'))\n",
+	})
+	cfg := &config.PreflightConfig{}
+	result, err := StaleFlagsCheck{}.Run(Context{RootDir: dir, Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed {
+		t.Fatal("expected a warning")
+	}
+	joined := strings.Join(result.Suggestions, "\n")
+	for _, want := range []string{
+		"src/checkout.ts:2 - tmp_new_pricing dates from " + old.Format("2006-01") + ", over 3 months ago",
+		"src/checkout.ts:4 - killswitch_banner is past its removal date (" + past + ")",
+		"app/models/order.rb:3 - tmp_checkout_" + old.Format("2006_01"),
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, joined)
+		}
+	}
+	for _, fresh := range []string{"temp_promo", "tmp_refunds", "tmp_in_tests"} {
+		if strings.Contains(joined, fresh) {
+			t.Errorf("%s reported as stale:\n%s", fresh, joined)
+		}
+	}
+	if len(result.Locations) != 3 {
+		t.Errorf("got %d locations, want 3", len(result.Locations))
+	}
+	if details := strings.Join(result.Details, "\n"); !strings.Contains(details, "tmp_legacy_export") {
+		t.Errorf("undated flag not noted in details: %q", details)
+	}
+
+	// A longer allowance and custom prefixes.
+	cfg.Checks.StaleFlags = &config.StaleFlagsConfig{Prefixes: []string{"exp_"}, MaxAgeMonths: 12}
+	dir = writeFiles(t, map[string]string{
+		"src/home.tsx": "// " + old.Format("2006-01") + "\nuseFlag('exp_hero')\nuseFlag('tmp_other')\n",
+	})
+	result, err = StaleFlagsCheck{}.Run(Context{RootDir: dir, Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || result.Message != "1 temporary flags, none past due" {
+		t.Errorf("expected pass, got %v %q", result.Passed, result.Message)
+	}
+}
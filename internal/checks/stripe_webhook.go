@@ -78,7 +78,8 @@ func (c StripeWebhookCheck) Run(ctx Context) (CheckResult, error) {
 			continue
 		}
 
-		_ = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		_ = walk(dirPath, func(path string, info os.FileInfo, err error) error {
+			recordUnreadable(path, err)
 			if err != nil || info.IsDir() || initFound {
 				return nil
 			}
@@ -92,7 +93,7 @@ func (c StripeWebhookCheck) Run(ctx Context) (CheckResult, error) {
 				return nil
 			}
 
-			content, err := os.ReadFile(path)
+			content, err := readFile(path)
 			if err != nil {
 				return nil
 			}
@@ -123,7 +124,7 @@ func (c StripeWebhookCheck) Run(ctx Context) (CheckResult, error) {
 	// commented-out `# gem "stripe"` must not count as a dependency.
 	for _, depFile := range []string{"Gemfile", "Gemfile.lock", "package.json"} {
 		path := filepath.Join(ctx.RootDir, depFile)
-		content, err := os.ReadFile(path)
+		content, err := readFile(path)
 		if err != nil {
 			continue
 		}
@@ -169,7 +170,7 @@ func (c StripeWebhookCheck) Run(ctx Context) (CheckResult, error) {
 }
 
 func scanEnvFile(path string, keys []string, foundKeys map[string]bool) {
-	file, err := os.Open(path)
+	file, err := openFile(path)
 	if err != nil {
 		return
 	}
@@ -1,7 +1,6 @@
 package checks
 
 import (
-	"os"
 	"path/filepath"
 	"regexp"
 )
@@ -47,7 +46,7 @@ func (c StructuredDataCheck) Run(ctx Context) (CheckResult, error) {
 	// Check main layout if configured
 	if cfg != nil && cfg.MainLayout != "" {
 		layoutPath := filepath.Join(ctx.RootDir, cfg.MainLayout)
-		content, err := os.ReadFile(layoutPath)
+		content, err := readFile(layoutPath)
 		if err == nil {
 			if hasStructuredData(string(content), ctx.Config.Stack) {
 				if ctx.Verbose {
@@ -87,7 +86,7 @@ func (c StructuredDataCheck) Run(ctx Context) (CheckResult, error) {
 		regexp.MustCompile(`["']@type["']\s*:\s*["'](Organization|WebSite|Article|Product|LocalBusiness|SoftwareApplication)`),
 	}
 
-	if match := searchForPatternsWithDetails(ctx.RootDir, ctx.Config.Stack, patterns); match != nil {
+	if match := searchForPatternsWithDetails(ctx, patterns); match != nil {
 		if ctx.Verbose {
 			details = append(details, "Found in: "+match.FilePath)
 		}
@@ -246,7 +245,7 @@ func checkStructuredDataPartialsWithDetails(rootDir, stack string) string {
 
 	for _, partialPath := range partialPaths {
 		fullPath := filepath.Join(rootDir, partialPath)
-		content, err := os.ReadFile(fullPath)
+		content, err := readFile(fullPath)
 		if err != nil {
 			continue
 		}
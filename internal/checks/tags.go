@@ -0,0 +1,122 @@
+package checks
+
+import "slices"
+
+// Tags group checks for one-off runs: `scan --only seo,security` or
+// `--skip network`. A check can carry several. "network" marks checks
+// that make requests (to the configured URLs, DNS or TLS endpoints), so
+// skipping it gives an offline scan.
+const (
+	TagSEO      = "seo"
+	TagSecurity = "security"
+	TagFiles    = "files"
+	TagServices = "services"
+	TagNetwork  = "network"
+)
+
+// AllTags lists the tags in the order help text shows them.
+var AllTags = []string{TagSEO, TagSecurity, TagFiles, TagServices, TagNetwork}
+
+// defaultTags covers IDs without an entry in checkTags, which in practice
+// means declared-service checks: they look for the SDK and env keys in the
+// project, then for the snippet on the live site.
+var defaultTags = []string{TagServices, TagFiles, TagNetwork}
+
+// checkTags holds each built-in check's tags. Add an entry when adding a
+// check that isn't a declared-service check.
+var checkTags = map[string][]string{
+	// SEO & Social
+	"seoMeta":          {TagSEO, TagFiles},
+	"canonical":        {TagSEO, TagFiles},
+	"ogTwitter":        {TagSEO, TagFiles, TagNetwork},
+	"twitter_card":     {TagSEO, TagNetwork},
+	"hreflang":         {TagSEO, TagNetwork},
+	"viewport":         {TagSEO, TagFiles},
+	"lang":             {TagSEO, TagFiles},
+	"structured_data":  {TagSEO, TagFiles},
+	"search_console":   {TagSEO, TagFiles, TagNetwork},
+	"favicon":          {TagSEO, TagFiles},
+	"image_alt":        {TagSEO, TagFiles},
+	"www_redirect":     {TagSEO, TagNetwork},
+	"indexNow":         {TagSEO, TagServices, TagFiles, TagNetwork},
+	"robotsTxt":        {TagSEO, TagFiles, TagNetwork},
+	"sitemap":          {TagSEO, TagFiles, TagNetwork},
+	"sitemap_coverage": {TagSEO, TagFiles},
+	"llmsTxt":          {TagSEO, TagFiles, TagNetwork},
+	// Security
+	"securityHeaders": {TagSecurity, TagNetwork},
+	"ssl":             {TagSecurity, TagNetwork},
+	"secrets":         {TagSecurity, TagFiles},
+	"vulnerability":   {TagSecurity, TagFiles},
+	"supply_chain":    {TagSecurity, TagFiles},
+	"osv":             {TagSecurity, TagFiles, TagNetwork},
+	"dependencies":    {TagFiles},
+	"dockerfile":      {TagSecurity, TagFiles},
+	"compose":         {TagSecurity, TagFiles},
+	"iac":             {TagSecurity, TagFiles},
+	"outdated_deps":   {TagSecurity, TagFiles, TagNetwork},
+	"envParity":       {TagSecurity, TagFiles},
+	"email_auth":      {TagSecurity, TagNetwork},
+	"bimi":            {TagNetwork},
+	// Infrastructure
+	"healthEndpoint":   {TagNetwork},
+	"ipv6":             {TagNetwork},
+	"cloudflare_zone":  {TagSecurity, TagNetwork},
+	"app_links":        {TagNetwork},
+	"bot_protection":   {TagSecurity, TagFiles},
+	"rate_limit":       {TagSecurity, TagFiles},
+	"routes":           {TagFiles, TagNetwork},
+	"auth_routes":      {TagSecurity, TagNetwork},
+	"smoke":            {TagNetwork},
+	"drift":            {TagFiles, TagNetwork},
+	"mirrors":          {TagSEO, TagNetwork},
+	"ops_readiness":    {TagFiles},
+	"changelog":        {TagFiles, TagNetwork},
+	"deployed_version": {TagNetwork},
+	"rollback":         {TagFiles},
+	"stale_flags":      {TagFiles},
+	// Code quality & files
+	"debug_statements":   {TagFiles},
+	"debug_mode":         {TagSecurity, TagFiles},
+	"localhost_urls":     {TagFiles},
+	"redirect_ssrf":      {TagSecurity, TagFiles},
+	"sql_injection":      {TagSecurity, TagFiles},
+	"legacy_artifacts":   {TagFiles},
+	"analytics_ids":      {TagFiles},
+	"error_pages":        {TagFiles, TagNetwork},
+	"image_optimization": {TagFiles},
+	"fonts":              {TagFiles},
+	"resilience":         {TagFiles},
+	"license":            {TagFiles},
+	"required_files":     {TagFiles},
+	"adsTxt":             {TagFiles, TagNetwork},
+	"humansTxt":          {TagFiles, TagNetwork},
+	"legal_pages":        {TagFiles, TagNetwork},
+	"cookies":            {TagSecurity, TagNetwork},
+	"regulated_gating":   {TagFiles, TagNetwork},
+	"a11y_statement":     {TagFiles, TagNetwork},
+	"refund_policy":      {TagFiles, TagNetwork},
+	// Judged from the declared-service results
+	"required_services": {TagServices},
+}
+
+// CheckTags returns c's tags. Custom checks only read files.
+func CheckTags(c Check) []string {
+	if _, ok := c.(CustomCheck); ok {
+		return []string{TagFiles}
+	}
+	return TagsFor(c.ID())
+}
+
+// TagsFor returns the tags of the built-in check with this ID.
+func TagsFor(id string) []string {
+	if tags, ok := checkTags[id]; ok {
+		return tags
+	}
+	return defaultTags
+}
+
+// IsTag reports whether name is a tag rather than a check ID.
+func IsTag(name string) bool {
+	return slices.Contains(AllTags, name)
+}
@@ -0,0 +1,22 @@
+package checks
+
+import "testing"
+
+// Every tagged ID must still be a registered check, and every tag one
+// that --only / --skip accept.
+func TestCheckTagsNameRegisteredChecks(t *testing.T) {
+	registered := make(map[string]bool, len(Registry))
+	for _, c := range Registry {
+		registered[c.ID()] = true
+	}
+	for id, tags := range checkTags {
+		if !registered[id] {
+			t.Errorf("checkTags[%q]: not in Registry", id)
+		}
+		for _, tag := range tags {
+			if !IsTag(tag) {
+				t.Errorf("checkTags[%q]: unknown tag %q", id, tag)
+			}
+		}
+	}
+}
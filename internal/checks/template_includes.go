@@ -0,0 +1,251 @@
+package checks
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// templateRef is one include, extends or partial reference in a template.
+// Engines resolve the same reference against several names (a Rails
+// partial "head" is _head.html.erb, or _head.html.haml, ...), so a ref
+// carries every name to try.
+type templateRef struct {
+	names []string
+	// relative refs are looked up next to the including file before the
+	// stack's template roots (Pug, EJS, Jekyll's include_relative, and
+	// unqualified Rails partials).
+	relative bool
+}
+
+// withExts returns name as-is when it already has an extension, otherwise
+// name with each of exts appended.
+func withExts(name string, exts ...string) []string {
+	if path.Ext(name) != "" {
+		return []string{name}
+	}
+	names := make([]string, len(exts))
+	for i, ext := range exts {
+		names[i] = name + ext
+	}
+	return names
+}
+
+var (
+	// Twig, Nunjucks and Liquid: {% include 'x' %}, {% extends "x" %},
+	// {% embed 'x' %}, and Twig's {{ include('x') }} function.
+	reTwigInclude     = regexp.MustCompile(`\{%-?\s*(?:include|extends|embed)\s+['"]([^'"]+)['"]`)
+	reTwigIncludeFunc = regexp.MustCompile(`\{\{-?\s*include\(\s*['"]([^'"]+)['"]`)
+	// Jekyll writes includes unquoted: {% include head.html %}.
+	reLiquidInclude = regexp.MustCompile(`\{%-?\s*(include|include_relative)\s+([^\s'"%{}]+)`)
+	// Shopify's {% render 'x' %} loads snippets/x.liquid.
+	reLiquidRender = regexp.MustCompile(`\{%-?\s*render\s+['"]([^'"]+)['"]`)
+	// Blade: @include('x'), @includeIf('x'), @extends('x'), @component('x').
+	reBladeInclude = regexp.MustCompile(`@(?:include|includeIf|extends|component)\s*\(\s*['"]([^'"]+)['"]`)
+	// ERB: <%= render 'x' %>, <%= render partial: "x" %>, <%= render("x") %>.
+	reERBRender = regexp.MustCompile(`<%==?\s*render\s*\(?\s*(?:partial:\s*|:partial\s*=>\s*)?['"]([^'"]+)['"]`)
+	// Handlebars: {{> x}}, {{> "x"}}, and partial blocks {{#> x}}.
+	reHandlebarsPartial = regexp.MustCompile(`\{\{~?#?>\s*['"]?([\w./-]+)['"]?`)
+	// Pug: `include x` and `extends x` at the start of a line.
+	rePugInclude = regexp.MustCompile(`(?m)^\s*(?:include|extends)(?::\w+)?\s+(\S+)\s*$`)
+	// Hugo: {{ partial "x.html" . }} and {{ partialCached "x.html" . }}
+	// load layouts/partials/x.html.
+	reHugoPartial = regexp.MustCompile(`\{\{-?\s*partial(?:Cached)?\s+"([^"]+)"`)
+	// EJS: <%- include('x') %>, and the old <% include x %> form.
+	reEJSInclude = regexp.MustCompile(`<%[-=_]?\s*include\s*\(\s*['"]([^'"]+)['"]|<%[-=_]?\s*include\s+([^\s%]+)\s*%>`)
+)
+
+// extractIncludePaths finds the templates a template pulls in.
+func extractIncludePaths(content string) []templateRef {
+	var refs []templateRef
+
+	for _, re := range []*regexp.Regexp{reTwigInclude, reTwigIncludeFunc} {
+		for _, match := range re.FindAllStringSubmatch(content, -1) {
+			name := match[1]
+			if idx := strings.Index(name, "|"); idx != -1 {
+				name = name[:idx]
+			}
+			refs = append(refs, templateRef{names: withExts(name, ".twig", ".html", ".html.twig", ".njk", ".liquid")})
+		}
+	}
+
+	for _, match := range reLiquidInclude.FindAllStringSubmatch(content, -1) {
+		refs = append(refs, templateRef{names: withExts(match[2], ".html", ".liquid"), relative: match[1] == "include_relative"})
+	}
+
+	for _, match := range reLiquidRender.FindAllStringSubmatch(content, -1) {
+		refs = append(refs, templateRef{names: withExts("snippets/"+match[1], ".liquid")})
+	}
+
+	for _, match := range reBladeInclude.FindAllStringSubmatch(content, -1) {
+		name := strings.ReplaceAll(match[1], ".", "/") + ".blade.php"
+		refs = append(refs, templateRef{names: []string{name}})
+	}
+
+	for _, match := range reERBRender.FindAllStringSubmatch(content, -1) {
+		refs = append(refs, erbPartialRef(match[1]))
+	}
+
+	for _, match := range reHandlebarsPartial.FindAllStringSubmatch(content, -1) {
+		name := match[1]
+		names := withExts(name, ".hbs", ".handlebars")
+		names = append(names, withExts("partials/"+name, ".hbs", ".handlebars")...)
+		refs = append(refs, templateRef{names: names})
+	}
+
+	for _, match := range reHugoPartial.FindAllStringSubmatch(content, -1) {
+		refs = append(refs, templateRef{names: withExts("partials/"+match[1], ".html")})
+	}
+
+	for _, match := range rePugInclude.FindAllStringSubmatch(content, -1) {
+		refs = append(refs, templateRef{names: withExts(match[1], ".pug"), relative: true})
+	}
+
+	for _, match := range reEJSInclude.FindAllStringSubmatch(content, -1) {
+		name := match[1]
+		if name == "" {
+			name = match[2]
+		}
+		refs = append(refs, templateRef{names: withExts(name, ".ejs"), relative: true})
+	}
+
+	return refs
+}
+
+// erbPartialRef applies Rails' partial naming: the file name gains a
+// leading underscore, and an unqualified partial is looked up in the
+// including template's directory and then in application/.
+func erbPartialRef(name string) templateRef {
+	dir, base := path.Split(name)
+	base = strings.TrimPrefix(base, "_")
+	var stems []string
+	if dir == "" {
+		stems = []string{"_" + base, "application/_" + base}
+	} else {
+		stems = []string{dir + "_" + base}
+	}
+	var names []string
+	for _, stem := range stems {
+		names = append(names, withExts(stem, ".html.erb", ".erb", ".html.haml", ".haml", ".html.slim", ".slim")...)
+	}
+	return templateRef{names: names, relative: dir == ""}
+}
+
+// resolveTemplateIncludes extracts template include/extends paths from content,
+// resolves them against fromDir (the including file's directory) and the
+// template roots, and returns absolute paths that exist on disk.
+func resolveTemplateIncludes(content, fromDir, rootDir, stack string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+
+	templateRoots := getTemplateRoots(rootDir, stack)
+
+	for _, ref := range extractIncludePaths(content) {
+		dirs := templateRoots
+		if ref.relative && fromDir != "" {
+			dirs = append([]string{fromDir}, templateRoots...)
+		}
+		for _, dir := range dirs {
+			for _, name := range ref.names {
+				// A leading slash means the template root, not the
+				// filesystem root (Pug's basedir, Twig's "/x").
+				fullPath := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(name, "/")))
+				if rel, err := filepath.Rel(rootDir, fullPath); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+					continue
+				}
+				if info, err := os.Stat(fullPath); err == nil && !info.IsDir() && !seen[fullPath] {
+					seen[fullPath] = true
+					paths = append(paths, fullPath)
+				}
+			}
+		}
+	}
+
+	return paths
+}
+
+func getTemplateRoots(rootDir, stack string) []string {
+	switch stack {
+	case "craft":
+		return []string{filepath.Join(rootDir, "templates")}
+	case "laravel":
+		return []string{filepath.Join(rootDir, "resources", "views")}
+	case "rails":
+		return []string{filepath.Join(rootDir, "app", "views")}
+	case "hugo":
+		return []string{filepath.Join(rootDir, "layouts")}
+	case "jekyll":
+		return []string{
+			filepath.Join(rootDir, "_layouts"),
+			filepath.Join(rootDir, "_includes"),
+		}
+	case "eleventy":
+		return []string{
+			filepath.Join(rootDir, "_includes"),
+			filepath.Join(rootDir, "src", "_includes"),
+			rootDir,
+		}
+	case "django", "flask", "python":
+		return []string{filepath.Join(rootDir, "templates")}
+	case "node":
+		return []string{filepath.Join(rootDir, "views"), rootDir}
+	default:
+		return []string{rootDir}
+	}
+}
+
+// maxTemplateGraphFiles caps how many templates one layout's graph may
+// pull in, so a pathological tree of includes can't stall a scan.
+const maxTemplateGraphFiles = 50
+
+// templateFile is one template reached from a layout.
+type templateFile struct {
+	path    string // absolute
+	content string
+}
+
+// templateGraph returns the layout at layoutPath followed by every template
+// it pulls in, directly or through other includes, partials and extends,
+// breadth-first. Each file appears once however many templates include it,
+// so include cycles end. A layout that can't be read yields an error; an
+// include that can't be read is left out.
+func templateGraph(layoutPath, rootDir, stack string) ([]templateFile, error) {
+	content, err := readFile(layoutPath)
+	if err != nil {
+		return nil, err
+	}
+	graph := []templateFile{{path: layoutPath, content: string(content)}}
+	seen := map[string]bool{layoutPath: true}
+	for i := 0; i < len(graph) && len(graph) < maxTemplateGraphFiles; i++ {
+		current := graph[i]
+		for _, includePath := range resolveTemplateIncludes(current.content, filepath.Dir(current.path), rootDir, stack) {
+			if seen[includePath] || len(graph) >= maxTemplateGraphFiles {
+				continue
+			}
+			seen[includePath] = true
+			included, err := readFile(includePath)
+			if err != nil {
+				continue
+			}
+			graph = append(graph, templateFile{path: includePath, content: string(included)})
+		}
+	}
+	return graph, nil
+}
+
+// readWithIncludes returns the content of a layout followed by the content
+// of every template in its graph, so a meta tag that lives in a head
+// partial counts as present in the layout.
+func readWithIncludes(layoutPath, rootDir, stack string) (string, error) {
+	graph, err := templateGraph(layoutPath, rootDir, stack)
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, len(graph))
+	for i, f := range graph {
+		parts[i] = f.content
+	}
+	return strings.Join(parts, "\n"), nil
+}
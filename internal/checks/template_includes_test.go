@@ -0,0 +1,146 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestResolveTemplateIncludes(t *testing.T) {
+	cases := []struct {
+		name   string
+		stack  string
+		layout string // project-relative path of the including file
+		body   string
+		want   string // project-relative path of the partial
+	}{
+		{"handlebars partial", "node", "views/layouts/main.hbs", "<head>{{> head}}</head>", "views/partials/head.hbs"},
+		{"handlebars quoted block", "ghost", "default.hbs", `{{#> "head"}}{{/head}}`, "partials/head.hbs"},
+		{"pug include", "node", "views/layout.pug", "html\n  include includes/head\n", "views/includes/head.pug"},
+		{"pug extends from root", "node", "views/pages/index.pug", "extends /layout\n", "views/layout.pug"},
+		{"ejs include", "node", "views/layout.ejs", `<%- include('partials/head', {title}) %>`, "views/partials/head.ejs"},
+		{"ejs legacy include", "node", "views/layout.ejs", "<% include head %>", "views/head.ejs"},
+		{"jekyll unquoted include", "jekyll", "_layouts/default.html", "{% include head.html %}", "_includes/head.html"},
+		{"jekyll include_relative", "jekyll", "docs/index.html", "{% include_relative meta.html %}", "docs/meta.html"},
+		{"liquid render snippet", "static", "layout/theme.liquid", "{% render 'meta-tags' %}", "snippets/meta-tags.liquid"},
+		{"erb unqualified partial", "rails", "app/views/layouts/application.html.erb", `<%= render "head" %>`, "app/views/layouts/_head.html.erb"},
+		{"erb application partial", "rails", "app/views/layouts/application.html.erb", "<%= render partial: 'meta' %>", "app/views/application/_meta.html.erb"},
+		{"erb qualified haml partial", "rails", "app/views/layouts/application.html.haml", `<%= render("shared/head", title: t) %>`, "app/views/shared/_head.html.haml"},
+		{"twig include without extension", "craft", "templates/_layout.twig", "{% include '_partials/head' %}", "templates/_partials/head.twig"},
+		{"twig include function", "craft", "templates/_layout.twig", "{{ include('_partials/seo.html') }}", "templates/_partials/seo.html"},
+		{"blade include", "laravel", "resources/views/layouts/app.blade.php", "@include('partials.head')", "resources/views/partials/head.blade.php"},
+		{"hugo partial", "hugo", "layouts/_default/baseof.html", `{{- partial "head.html" . -}}`, "layouts/partials/head.html"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{tc.layout: tc.body, tc.want: "<meta>"})
+			layoutPath := filepath.Join(dir, filepath.FromSlash(tc.layout))
+			got := resolveTemplateIncludes(tc.body, filepath.Dir(layoutPath), dir, tc.stack)
+			want := filepath.Join(dir, filepath.FromSlash(tc.want))
+			if len(got) != 1 || got[0] != want {
+				t.Errorf("resolved %v, want [%s]", got, want)
+			}
+		})
+	}
+}
+
+func TestResolveTemplateIncludesStaysInProject(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"views/layout.pug": "include ../../../../etc/passwd\n"})
+	got := resolveTemplateIncludes("include ../../../../etc/passwd\n", filepath.Join(dir, "views"), dir, "node")
+	if len(got) != 0 {
+		t.Errorf("resolved %v outside the project", got)
+	}
+}
+
+// Rails apps commonly keep their meta tags in a head partial. Reading the
+// layout alone reported every tag missing.
+func TestSEOMetadataCheckReadsPartials(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"app/views/layouts/application.html.erb": "<html><head><%= render 'head' %></head></html>",
+		"app/views/layouts/_head.html.erb": `<title>Site</title>
+<meta name="description" content="d">
+<meta property="og:title" content="t">
+<meta property="og:description" content="d">`,
+	})
+	cfg := &config.PreflightConfig{Stack: "rails"}
+	result, err := SEOMetadataCheck{}.Run(Context{RootDir: dir, Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed {
+		t.Errorf("got %q, want a pass from the partial's tags", result.Message)
+	}
+}
+
+func TestTemplateGraphFollowsNestedIncludes(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"templates/_layout.twig":        "{% include '_partials/head' %}",
+		"templates/_partials/head.twig": "{% include '_partials/meta' %}{% include '_layout' %}", // cycle back
+		"templates/_partials/meta.twig": "<meta property=\"og:title\">",
+	})
+	graph, err := templateGraph(filepath.Join(dir, "templates", "_layout.twig"), dir, "craft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range graph {
+		got = append(got, relPath(dir, f.path))
+	}
+	want := []string{
+		filepath.FromSlash("templates/_layout.twig"),
+		filepath.FromSlash("templates/_partials/head.twig"),
+		filepath.FromSlash("templates/_partials/meta.twig"),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("graph = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("graph[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTemplateGraphCapsFiles(t *testing.T) {
+	files := map[string]string{}
+	for i := 0; i < maxTemplateGraphFiles+10; i++ {
+		files[fmt.Sprintf("views/p%d.ejs", i)] = fmt.Sprintf("<%%- include('p%d') %%>", i+1)
+	}
+	dir := writeFiles(t, files)
+	graph, err := templateGraph(filepath.Join(dir, "views", "p0.ejs"), dir, "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph) != maxTemplateGraphFiles {
+		t.Errorf("graph has %d files, want the cap of %d", len(graph), maxTemplateGraphFiles)
+	}
+}
+
+// The analytics snippet sits two includes below the configured layout, in
+// a .tpl partial the directory fallback doesn't read, so only following
+// the graph finds it. The match should name the partial.
+func TestSearchForPatternsFollowsMainLayout(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"templates/_base.twig":            `{% include "_partials/head" %}`,
+		"templates/_partials/head.twig":   `{% include "_partials/scripts.tpl" %}`,
+		"templates/_partials/scripts.tpl": `<script async src="https://www.googletagmanager.com/gtag/js?id=G-ABC123"></script>`,
+	})
+	cfg := &config.PreflightConfig{Stack: "craft"}
+	cfg.Checks.SEOMeta = &config.SEOMetaConfig{MainLayout: "templates/_base.twig"}
+	ctx := Context{RootDir: dir, Config: cfg}
+
+	patterns := []*regexp.Regexp{regexp.MustCompile(`googletagmanager\.com/gtag`)}
+	match := searchForPatternsWithDetails(ctx, patterns)
+	if match == nil {
+		t.Fatal("expected a match in the nested partial")
+	}
+	if want := filepath.FromSlash("templates/_partials/scripts.tpl"); match.FilePath != want {
+		t.Errorf("FilePath = %s, want %s", match.FilePath, want)
+	}
+	if !searchForPatterns(ctx, patterns) {
+		t.Error("searchForPatterns missed the nested partial")
+	}
+}
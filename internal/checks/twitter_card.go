@@ -0,0 +1,137 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TwitterCardCheck reads the card tags off the production homepage as X
+// would when someone shares the link. ogTwitter reads the templates and
+// only falls back to the live page when they don't have the tags, so a
+// plugin or server-side layer that overrides or strips them goes
+// unnoticed there.
+type TwitterCardCheck struct{}
+
+func (c TwitterCardCheck) ID() string {
+	return "twitter_card"
+}
+
+func (c TwitterCardCheck) Title() string {
+	return "Twitter/X card (live)"
+}
+
+// twitterCardTypes are the twitter:card values X renders, with the minimum
+// image size each needs.
+var twitterCardTypes = map[string][2]int{
+	"summary":             {144, 144},
+	"summary_large_image": {twitterMinWidth, twitterMinHeight},
+	"app":                 {0, 0},
+	"player":              {0, 0},
+}
+
+func (c TwitterCardCheck) Run(ctx Context) (CheckResult, error) {
+	if ctx.PageHTMLProduction == "" {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityWarn,
+			Passed:   false,
+			Message:  "Couldn't fetch the production homepage to read its card tags",
+		}, nil
+	}
+	doc := parseRenderedHTML(ctx.PageHTMLProduction)
+	var problems, suggestions, details []string
+
+	card, hasCard := doc.metaContent("twitter:card")
+	card = strings.ToLower(strings.TrimSpace(card))
+	minSize, validCard := twitterCardTypes[card]
+	switch {
+	case !hasCard || card == "":
+		problems = append(problems, "no twitter:card")
+		suggestions = append(suggestions, `Add <meta name="twitter:card" content="summary_large_image">; without it X shows a bare link`)
+	case !validCard:
+		problems = append(problems, fmt.Sprintf("twitter:card %q isn't a card type", card))
+		suggestions = append(suggestions, "Set twitter:card to summary, summary_large_image, app or player")
+	}
+
+	// X falls back to the Open Graph tags for the title and image.
+	title, fallback := twitterOrOG(doc, "title")
+	switch {
+	case strings.TrimSpace(title) == "":
+		problems = append(problems, "no twitter:title or og:title")
+		suggestions = append(suggestions, `Add <meta name="twitter:title" content="..."> (or og:title, which X falls back to)`)
+	case fallback:
+		details = append(details, "twitter:title falls back to og:title")
+	}
+	image, fallback := twitterOrOG(doc, "image")
+	image = strings.TrimSpace(image)
+	switch {
+	case image == "" && card != "app" && card != "player":
+		problems = append(problems, "no twitter:image or og:image")
+		suggestions = append(suggestions, `Add <meta name="twitter:image" content="https://..."> with an absolute URL`)
+	case image == "":
+	case !strings.HasPrefix(image, "https://") && !strings.HasPrefix(image, "http://"):
+		problems = append(problems, "twitter:image isn't an absolute URL: "+image)
+		suggestions = append(suggestions, "Use an absolute https:// URL for twitter:image; X doesn't resolve relative paths")
+	default:
+		if fallback {
+			details = append(details, "twitter:image falls back to og:image")
+		}
+		if ctx.Client != nil {
+			width, height, err := fetchImageDimensions(ctx, image)
+			switch {
+			case err != nil:
+				problems = append(problems, "twitter:image couldn't be loaded ("+err.Error()+")")
+				suggestions = append(suggestions, "Serve the card image publicly with a 200 response")
+			case width < minSize[0] || height < minSize[1]:
+				problems = append(problems, fmt.Sprintf("twitter:image is %dx%d, under the %dx%d a %s card needs", width, height, minSize[0], minSize[1], card))
+				suggestions = append(suggestions, fmt.Sprintf("Use a %dx%d image for summary_large_image cards", twitterRecommendedWidth, twitterRecommendedHeight))
+			default:
+				details = append(details, fmt.Sprintf("twitter:image %dx%d", width, height))
+			}
+		}
+	}
+
+	switch card {
+	case "player":
+		if v, _ := doc.metaContent("twitter:player"); v == "" {
+			problems = append(problems, "player card without twitter:player")
+			suggestions = append(suggestions, "Add twitter:player with the HTTPS URL of the embeddable player, plus twitter:player:width and height")
+		}
+	case "app":
+		if !doc.hasMeta("twitter:app:id:iphone") && !doc.hasMeta("twitter:app:id:googleplay") {
+			problems = append(problems, "app card without twitter:app:id:iphone or twitter:app:id:googleplay")
+			suggestions = append(suggestions, "Add the App Store or Google Play ID of the app the card promotes")
+		}
+	}
+
+	if len(problems) == 0 {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Production serves a complete " + card + " card",
+			Details:  details,
+		}, nil
+	}
+	return CheckResult{
+		ID:          c.ID(),
+		Title:       c.Title(),
+		Severity:    SeverityWarn,
+		Passed:      false,
+		Message:     "Production card tags: " + strings.Join(problems, "; "),
+		Suggestions: suggestions,
+		Details:     details,
+	}, nil
+}
+
+// twitterOrOG returns the twitter: tag of the given kind (title, image),
+// or the og: one X falls back to, reporting whether it fell back.
+func twitterOrOG(doc renderedDoc, kind string) (value string, fallback bool) {
+	if v, ok := doc.metaContent("twitter:" + kind); ok && strings.TrimSpace(v) != "" {
+		return v, false
+	}
+	v, _ := doc.metaContent("og:" + kind)
+	return v, v != ""
+}
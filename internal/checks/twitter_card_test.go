@@ -0,0 +1,96 @@
+package checks
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestTwitterCardCheck(t *testing.T) {
+	pngOf := func(w, h int) []byte {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	large, small := pngOf(1200, 600), pngOf(200, 100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/card.png":
+			w.Write(large)
+		case "/small.png":
+			w.Write(small)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name   string
+		head   string
+		passed bool
+		want   string
+	}{
+		{
+			name:   "complete",
+			head:   `<meta name="twitter:card" content="summary_large_image"><meta name="twitter:title" content="Acme"><meta name="twitter:image" content="` + srv.URL + `/card.png">`,
+			passed: true,
+			want:   "Production serves a complete summary_large_image card",
+		},
+		{
+			name:   "og fallbacks",
+			head:   `<meta name="twitter:card" content="summary"><meta property="og:title" content="Acme"><meta property="og:image" content="` + srv.URL + `/card.png">`,
+			passed: true,
+			want:   "complete summary card",
+		},
+		{
+			name: "missing card",
+			head: `<meta property="og:title" content="Acme"><meta property="og:image" content="` + srv.URL + `/card.png">`,
+			want: "no twitter:card",
+		},
+		{
+			name: "bad type",
+			head: `<meta name="twitter:card" content="large"><meta name="twitter:title" content="Acme"><meta name="twitter:image" content="` + srv.URL + `/card.png">`,
+			want: `twitter:card "large" isn't a card type`,
+		},
+		{
+			name: "relative image",
+			head: `<meta name="twitter:card" content="summary_large_image"><meta name="twitter:title" content="Acme"><meta name="twitter:image" content="/card.png">`,
+			want: "twitter:image isn't an absolute URL",
+		},
+		{
+			name: "small image",
+			head: `<meta name="twitter:card" content="summary_large_image"><meta name="twitter:title" content="Acme"><meta name="twitter:image" content="` + srv.URL + `/small.png">`,
+			want: "twitter:image is 200x100, under the 300x157",
+		},
+		{
+			name: "broken image and no title",
+			head: `<meta name="twitter:card" content="summary_large_image"><meta name="twitter:image" content="` + srv.URL + `/gone.png">`,
+			want: "no twitter:title or og:title; twitter:image couldn't be loaded",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.PreflightConfig{}
+			cfg.URLs.Production = srv.URL
+			result, err := TwitterCardCheck{}.Run(Context{
+				Config:             cfg,
+				Client:             srv.Client(),
+				PageHTMLProduction: "<html><head>" + tc.head + "</head></html>",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tc.passed || !strings.Contains(result.Message, tc.want) {
+				t.Errorf("got passed=%v message=%q, want %v and %q", result.Passed, result.Message, tc.passed, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package checks
+
+import (
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// Usage is what one check consumed, for --profile-checks. File and network
+// counts cover the I/O helpers in this package, which every built-in check
+// goes through; CPU time is the process's (child processes such as npm
+// audit included) while the check ran.
+type Usage struct {
+	WallMs       int64 `json:"wallMs"`
+	CPUMs        int64 `json:"cpuMs"`
+	FilesRead    int64 `json:"filesRead"`
+	BytesScanned int64 `json:"bytesScanned"`
+	NetworkCalls int64 `json:"networkCalls"`
+}
+
+// ioCounters are process-wide. Checks run one at a time, so the
+// difference across a check's Run is that check's I/O; the route probes'
+// concurrent requests all belong to the check that started them.
+var ioCounters struct {
+	filesRead    atomic.Int64
+	bytesScanned atomic.Int64
+	networkCalls atomic.Int64
+}
+
+// StartUsage begins measuring a check. The returned function reports what
+// was used since.
+func StartUsage() func() Usage {
+	start := time.Now()
+	startCPU := processCPUTime()
+	files, bytes, calls := ioCounters.filesRead.Load(), ioCounters.bytesScanned.Load(), ioCounters.networkCalls.Load()
+	return func() Usage {
+		return Usage{
+			WallMs:       time.Since(start).Milliseconds(),
+			CPUMs:        (processCPUTime() - startCPU).Milliseconds(),
+			FilesRead:    ioCounters.filesRead.Load() - files,
+			BytesScanned: ioCounters.bytesScanned.Load() - bytes,
+			NetworkCalls: ioCounters.networkCalls.Load() - calls,
+		}
+	}
+}
+
+// readFile is os.ReadFile, counted, with read failures kept for the
+// scan's diagnostics.
+func readFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		recordUnreadable(path, err)
+		return nil, err
+	}
+	ioCounters.filesRead.Add(1)
+	ioCounters.bytesScanned.Add(int64(len(data)))
+	return data, nil
+}
+
+// countedFile counts the bytes read through it.
+type countedFile struct {
+	*os.File
+}
+
+func (f countedFile) Read(p []byte) (int, error) {
+	n, err := f.File.Read(p)
+	ioCounters.bytesScanned.Add(int64(n))
+	return n, err
+}
+
+// openFile is readFile's counterpart for checks that stream a file.
+func openFile(path string) (countedFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		recordUnreadable(path, err)
+		return countedFile{}, err
+	}
+	ioCounters.filesRead.Add(1)
+	return countedFile{f}, nil
+}
+
+// countNetworkCall records one request, DNS lookup or TLS handshake.
+func countNetworkCall() {
+	ioCounters.networkCalls.Add(1)
+}
+
+// countingTransport counts each round trip.
+type countingTransport struct {
+	next http.RoundTripper
+}
+
+func (t countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	countNetworkCall()
+	return t.next.RoundTrip(req)
+}
+
+// CountRequests wraps client's transport so its requests show up in Usage.
+// Clients the checks derive from it (copies with their own redirect
+// policy) are counted too.
+func CountRequests(client *http.Client) {
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	client.Transport = countingTransport{next: next}
+}
@@ -0,0 +1,48 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestStartUsageCountsFilesAndRequests(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.txt": "hello",
+		"b.txt": "hello, world",
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	client := srv.Client()
+	CountRequests(client)
+
+	stop := StartUsage()
+	if _, err := readFile(filepath.Join(dir, "a.txt")); err != nil {
+		t.Fatal(err)
+	}
+	f, err := openFile(filepath.Join(dir, "b.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 64)
+	for {
+		if _, err := f.Read(buf); err != nil {
+			break
+		}
+	}
+	f.Close()
+	_, _ = readFile(filepath.Join(dir, "missing.txt"))
+	for range 2 {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	u := stop()
+
+	if u.FilesRead != 2 || u.BytesScanned != 17 || u.NetworkCalls != 2 {
+		t.Errorf("usage = %+v, want 2 files, 17 bytes, 2 network calls", u)
+	}
+}
@@ -35,7 +35,7 @@ func (c ViewportCheck) Run(ctx Context) (CheckResult, error) {
 	if cfg != nil {
 		configuredLayout = cfg.MainLayout
 	}
-	layoutFile := getLayoutFile(ctx.RootDir, ctx.Config.Stack, configuredLayout)
+	layoutFile := getLayoutFile(ctx.RootDir, ctx.Config.AllStacks(), configuredLayout)
 
 	if layoutFile == "" {
 		return CheckResult{
@@ -48,7 +48,7 @@ func (c ViewportCheck) Run(ctx Context) (CheckResult, error) {
 	}
 
 	layoutPath := filepath.Join(ctx.RootDir, layoutFile)
-	content, err := os.ReadFile(layoutPath)
+	content, err := readFile(layoutPath)
 	if err != nil {
 		return CheckResult{
 			ID:       c.ID(),
@@ -72,13 +72,13 @@ func (c ViewportCheck) Run(ctx Context) (CheckResult, error) {
 		}, nil
 	}
 
-	// Check included template files
-	for _, includePath := range resolveTemplateIncludes(contentStr, ctx.RootDir, ctx.Config.Stack) {
-		includeContent, err := os.ReadFile(includePath)
-		if err != nil {
+	// Check included template files, following includes of includes
+	graph, _ := templateGraph(layoutPath, ctx.RootDir, ctx.Config.Stack)
+	for _, included := range graph {
+		if included.path == layoutPath {
 			continue
 		}
-		if hasViewportMeta(string(includeContent), ctx.Config.Stack) {
+		if hasViewportMeta(included.content, ctx.Config.Stack) {
 			return CheckResult{
 				ID:       c.ID(),
 				Title:    c.Title(),
@@ -245,7 +245,7 @@ func checkViewportPartials(rootDir, stack string) bool {
 
 	for _, partialPath := range partialPaths {
 		fullPath := filepath.Join(rootDir, partialPath)
-		content, err := os.ReadFile(fullPath)
+		content, err := readFile(fullPath)
 		if err != nil {
 			continue
 		}
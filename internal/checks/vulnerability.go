@@ -1,13 +1,18 @@
 package checks
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/preflightsh/preflight/internal/config"
 	"github.com/preflightsh/preflight/internal/fsutil"
 )
 
@@ -22,12 +27,42 @@ func (c VulnerabilityCheck) Title() string {
 }
 
 func (c VulnerabilityCheck) Run(ctx Context) (CheckResult, error) {
-	stack := ctx.Config.Stack
+	// The audit tools run with the project's own package manager config:
+	// .yarnrc can name the yarn to execute, Composer loads plugins from
+	// vendor/ and .npmrc picks the registry. None of that is safe to
+	// follow for an upload.
+	if ctx.Untrusted {
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  "Uploaded project, skipping vulnerability check",
+		}, nil
+	}
 
-	// Determine which audit command to run based on stack and files present
-	auditCmd, auditArgs, toolName := c.getAuditCommand(ctx.RootDir, stack)
+	// Determine which audit commands to run based on the declared stacks
+	// and files present. A hybrid repo (a Rails API with a React
+	// frontend) gets one audit per ecosystem.
+	type auditRun struct {
+		cmd  string
+		args []string
+		tool string
+	}
+	stacks := ctx.Config.AllStacks()
+	if len(stacks) == 0 {
+		stacks = []string{""}
+	}
+	var audits []auditRun
+	for _, stack := range stacks {
+		auditCmd, auditArgs, toolName := c.getAuditCommand(ctx.RootDir, stack)
+		if auditCmd == "" || slices.ContainsFunc(audits, func(a auditRun) bool { return a.tool == toolName }) {
+			continue
+		}
+		audits = append(audits, auditRun{auditCmd, auditArgs, toolName})
+	}
 
-	if auditCmd == "" {
+	if len(audits) == 0 {
 		return CheckResult{
 			ID:       c.ID(),
 			Title:    c.Title(),
@@ -37,6 +72,22 @@ func (c VulnerabilityCheck) Run(ctx Context) (CheckResult, error) {
 		}, nil
 	}
 
+	results := make([]CheckResult, 0, len(audits))
+	for _, a := range audits {
+		result, err := c.runAudit(ctx, a.cmd, a.args, a.tool)
+		if err != nil {
+			return result, err
+		}
+		results = append(results, result)
+	}
+	if len(results) == 1 {
+		return results[0], nil
+	}
+	return c.mergeResults(results), nil
+}
+
+// runAudit runs one ecosystem's audit tool and parses its report.
+func (c VulnerabilityCheck) runAudit(ctx Context, auditCmd string, auditArgs []string, toolName string) (CheckResult, error) {
 	// Check if the audit tool is available
 	if _, err := exec.LookPath(auditCmd); err != nil {
 		return CheckResult{
@@ -62,6 +113,7 @@ func (c VulnerabilityCheck) Run(ctx Context) (CheckResult, error) {
 	defer cancel()
 	cmd := exec.CommandContext(timeoutCtx, auditCmd, auditArgs...)
 	cmd.Dir = ctx.RootDir
+	countNetworkCall() // the audit queries the package registry
 	scratchDir, scratchErr := os.MkdirTemp("", "preflight-audit-")
 	if scratchErr == nil {
 		defer func() { _ = os.RemoveAll(scratchDir) }()
@@ -69,10 +121,152 @@ func (c VulnerabilityCheck) Run(ctx Context) (CheckResult, error) {
 		scratchDir = "" // run without HOME/TMPDIR rather than expose the real ones
 	}
 	cmd.Env = minimalSubprocessEnv(scratchDir)
-	output, err := cmd.CombinedOutput()
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	// The JavaScript audits report in JSON, counted by severity. When the
+	// report doesn't parse (an error instead of a report, an old yarn),
+	// fall back to reading the text.
+	if counts, ok := parseAuditCounts(toolName, stdout.Bytes()); ok {
+		return c.countsResult(counts, auditThreshold(ctx.Config), toolName), nil
+	}
+	output := stdout.String() + stderr.String()
+	return c.parseResult(err, truncateOutput(output, 2048), toolName)
+}
+
+// auditCounts are an audit's advisories by severity.
+type auditCounts struct {
+	Info     int `json:"info"`
+	Low      int `json:"low"`
+	Moderate int `json:"moderate"`
+	High     int `json:"high"`
+	Critical int `json:"critical"`
+}
+
+// bySeverity returns the counts in config.AuditSeverities order.
+func (a auditCounts) bySeverity() []int {
+	return []int{a.Low, a.Moderate, a.High, a.Critical}
+}
+
+// parseAuditCounts reads the severity counts from an npm, pnpm or yarn
+// (classic) audit --json report. npm and pnpm print one object with the
+// counts under metadata.vulnerabilities; yarn prints a line per event and
+// ends with an auditSummary.
+func parseAuditCounts(toolName string, output []byte) (auditCounts, bool) {
+	switch toolName {
+	case "npm audit", "pnpm audit":
+		var report struct {
+			Metadata *struct {
+				Vulnerabilities *auditCounts `json:"vulnerabilities"`
+			} `json:"metadata"`
+		}
+		if json.Unmarshal(output, &report) != nil || report.Metadata == nil || report.Metadata.Vulnerabilities == nil {
+			return auditCounts{}, false
+		}
+		return *report.Metadata.Vulnerabilities, true
+	case "yarn audit":
+		for _, line := range bytes.Split(output, []byte("\n")) {
+			var event struct {
+				Type string `json:"type"`
+				Data struct {
+					Vulnerabilities *auditCounts `json:"vulnerabilities"`
+				} `json:"data"`
+			}
+			if json.Unmarshal(line, &event) == nil && event.Type == "auditSummary" && event.Data.Vulnerabilities != nil {
+				return *event.Data.Vulnerabilities, true
+			}
+		}
+	}
+	return auditCounts{}, false
+}
+
+// auditThreshold returns the lowest severity that fails the audit.
+func auditThreshold(cfg *config.PreflightConfig) string {
+	if cfg != nil && cfg.Checks.Vulnerability != nil && cfg.Checks.Vulnerability.Threshold != "" {
+		return cfg.Checks.Vulnerability.Threshold
+	}
+	return "moderate"
+}
 
-	// Parse the result
-	return c.parseResult(err, truncateOutput(string(output), 2048), toolName)
+// countsResult reports an audit's severity counts, failing when any
+// advisory is at or above threshold. Every severity found is listed,
+// highest first, so the ones below the threshold aren't hidden.
+func (c VulnerabilityCheck) countsResult(counts auditCounts, threshold, toolName string) CheckResult {
+	n := counts.bySeverity()
+	lowest := slices.Index(config.AuditSeverities, threshold)
+	blocking := 0
+	var found []string
+	for i := len(n) - 1; i >= 0; i-- {
+		if n[i] == 0 {
+			continue
+		}
+		found = append(found, fmt.Sprintf("%d %s", n[i], config.AuditSeverities[i]))
+		if i >= lowest {
+			blocking += n[i]
+		}
+	}
+
+	if blocking == 0 {
+		message := "No vulnerabilities found (" + toolName + ")"
+		if len(found) > 0 {
+			message = fmt.Sprintf("No vulnerabilities at %s or above (%s: %s)", threshold, toolName, strings.Join(found, ", "))
+		}
+		return CheckResult{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Severity: SeverityInfo,
+			Passed:   true,
+			Message:  message,
+		}
+	}
+
+	noun := "vulnerabilities"
+	if blocking == 1 && len(found) == 1 {
+		noun = "vulnerability"
+	}
+	return CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityWarn,
+		Passed:   false,
+		Message:  fmt.Sprintf("%s: %s %s", toolName, strings.Join(found, ", "), noun),
+		Suggestions: []string{
+			"Review and update vulnerable dependencies",
+			"Run '" + toolName + "' for full details",
+		},
+	}
+}
+
+// mergeResults folds the audits of a multi-stack project into one
+// result: warn when any audit warns, with each audit's message in turn.
+func (c VulnerabilityCheck) mergeResults(results []CheckResult) CheckResult {
+	merged := CheckResult{
+		ID:       c.ID(),
+		Title:    c.Title(),
+		Severity: SeverityInfo,
+		Passed:   true,
+	}
+	var failing, passing []string
+	for _, r := range results {
+		if r.Passed {
+			passing = append(passing, r.Message)
+		} else {
+			failing = append(failing, r.Message)
+			merged.Severity = SeverityWarn
+			merged.Passed = false
+		}
+		for _, s := range r.Suggestions {
+			if !slices.Contains(merged.Suggestions, s) {
+				merged.Suggestions = append(merged.Suggestions, s)
+			}
+		}
+	}
+	// Lead with the audits that found something.
+	messages := append(failing, passing...)
+	merged.Message = strings.Join(messages, "; ")
+	return merged
 }
 
 // minimalSubprocessEnv returns the smallest set of env vars an audit
@@ -137,8 +331,9 @@ type auditEcosystem struct {
 func auditEcosystems() []auditEcosystem {
 	return []auditEcosystem{
 		{"bundler", []string{"Gemfile.lock"}, "bundle", []string{"audit", "check", "--update"}, "bundle-audit"},
-		{"yarn", []string{"yarn.lock"}, "yarn", []string{"audit", "--summary"}, "yarn audit"},
-		{"npm", []string{"package-lock.json"}, "npm", []string{"audit", "--audit-level=moderate"}, "npm audit"},
+		{"pnpm", []string{"pnpm-lock.yaml"}, "pnpm", []string{"audit", "--json"}, "pnpm audit"},
+		{"yarn", []string{"yarn.lock"}, "yarn", []string{"audit", "--json"}, "yarn audit"},
+		{"npm", []string{"package-lock.json"}, "npm", []string{"audit", "--json"}, "npm audit"},
 		{"composer", []string{"composer.lock"}, "composer", []string{"audit"}, "composer audit"},
 		{"pip", []string{"Pipfile.lock", "requirements.txt"}, "pip-audit", []string{}, "pip-audit"},
 		{"go", []string{"go.sum"}, "govulncheck", []string{"./..."}, "govulncheck"},
@@ -165,9 +360,9 @@ func stackEcosystems(stack string) map[string]bool {
 		return map[string]bool{"composer": true}
 	case "python", "django", "flask":
 		return map[string]bool{"pip": true}
-	case "node", "next", "nuxt", "react", "vue", "svelte", "angular",
+	case "node", "next", "nuxt", "react", "vue", "svelte", "sveltekit", "angular",
 		"astro", "gatsby", "eleventy", "vite", "remix", "ghost":
-		return map[string]bool{"yarn": true, "npm": true}
+		return map[string]bool{"pnpm": true, "yarn": true, "npm": true}
 	}
 	return nil
 }
@@ -211,6 +406,7 @@ func (c VulnerabilityCheck) getInstallSuggestion(cmd string) string {
 		"bundle":      "Install bundle-audit: gem install bundler-audit",
 		"npm":         "npm is usually included with Node.js",
 		"yarn":        "Install yarn: npm install -g yarn",
+		"pnpm":        "Install pnpm: npm install -g pnpm",
 		"composer":    "composer audit requires Composer 2.4+",
 		"pip-audit":   "Install pip-audit: pip install pip-audit",
 		"govulncheck": "Install govulncheck: go install golang.org/x/vuln/cmd/govulncheck@latest",
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
 )
 
 func writeLockfiles(t *testing.T, names ...string) string {
@@ -49,6 +51,12 @@ func TestGetAuditCommand_StackPrefersMatchingEcosystem(t *testing.T) {
 			lockfiles: []string{"package-lock.json", "yarn.lock"},
 			wantTool:  "yarn audit",
 		},
+		{
+			name:      "pnpm lockfile",
+			stack:     "sveltekit",
+			lockfiles: []string{"pnpm-lock.yaml"},
+			wantTool:  "pnpm audit",
+		},
 		{
 			// Preferred ecosystem lockfile absent → default order.
 			name:      "go stack without go.sum falls back to default order",
@@ -93,6 +101,19 @@ func TestGetAuditCommand_StackPrefersMatchingEcosystem(t *testing.T) {
 // the "inconclusive" branch, which both mislabeled a perfectly conclusive
 // audit and pasted the entire multi-line report into Message (a field the
 // dashboard renders on one line).
+// An upload's package manager config can run its own code, so the audit
+// tools aren't started at all.
+func TestVulnerabilityCheckSkipsUntrusted(t *testing.T) {
+	dir := writeLockfiles(t, "package-lock.json", ".npmrc")
+	result, err := VulnerabilityCheck{}.Run(Context{RootDir: dir, Config: &config.PreflightConfig{Stack: "node"}, Untrusted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || result.Severity != SeverityInfo || result.Message != "Uploaded project, skipping vulnerability check" {
+		t.Errorf("got %v %v %q", result.Passed, result.Severity, result.Message)
+	}
+}
+
 func TestParseResult(t *testing.T) {
 	npmLowOnly := "# npm audit report\n\ntmp  <=0.2.3\nSeverity: low\n" +
 		"tmp allows arbitrary temporary file write via symbolic link\n" +
@@ -205,6 +226,37 @@ func TestParseResult(t *testing.T) {
 	}
 }
 
+func TestAuditCounts(t *testing.T) {
+	npmReport := `{"auditReportVersion":2,"vulnerabilities":{},"metadata":{"vulnerabilities":{"info":0,"low":3,"moderate":1,"high":2,"critical":1,"total":7}}}`
+	yarnReport := `{"type":"auditAdvisory","data":{"resolution":{"id":1}}}
+{"type":"auditSummary","data":{"vulnerabilities":{"info":0,"low":0,"moderate":2,"high":0,"critical":0},"dependencies":120}}`
+
+	counts, ok := parseAuditCounts("npm audit", []byte(npmReport))
+	if !ok || counts != (auditCounts{Low: 3, Moderate: 1, High: 2, Critical: 1}) {
+		t.Fatalf("npm counts = %+v, %v", counts, ok)
+	}
+	if counts, ok := parseAuditCounts("yarn audit", []byte(yarnReport)); !ok || counts.Moderate != 2 {
+		t.Errorf("yarn counts = %+v, %v", counts, ok)
+	}
+	// An error report isn't a count of zero.
+	if _, ok := parseAuditCounts("npm audit", []byte(`{"error":{"code":"ENOLOCK"}}`)); ok {
+		t.Error("npm error report parsed as counts")
+	}
+
+	var c VulnerabilityCheck
+	got := c.countsResult(counts, "moderate", "npm audit")
+	if got.Passed || got.Message != "npm audit: 1 critical, 2 high, 1 moderate, 3 low vulnerabilities" {
+		t.Errorf("moderate threshold: passed = %v, message = %q", got.Passed, got.Message)
+	}
+	got = c.countsResult(auditCounts{Low: 3, Moderate: 1}, "high", "npm audit")
+	if !got.Passed || got.Message != "No vulnerabilities at high or above (npm audit: 1 moderate, 3 low)" {
+		t.Errorf("high threshold: passed = %v, message = %q", got.Passed, got.Message)
+	}
+	if got := c.countsResult(auditCounts{}, "low", "pnpm audit"); !got.Passed || got.Message != "No vulnerabilities found (pnpm audit)" {
+		t.Errorf("clean: %+v", got)
+	}
+}
+
 func TestCondenseOutput(t *testing.T) {
 	if got := condenseOutput("a\n\n  b\tc  \n"); got != "a b c" {
 		t.Errorf("condenseOutput = %q, want %q", got, "a b c")
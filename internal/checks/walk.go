@@ -0,0 +1,184 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+// walkConfig is the walk policy for the scan in progress: preflight.yml's
+// walk section with defaults applied. Every directory walk in this package
+// goes through walkDir (or walk) so the policy holds throughout.
+var walkConfig = struct {
+	sync.RWMutex
+	config.WalkConfig
+}{WalkConfig: config.WalkConfig{MaxDepth: config.DefaultMaxWalkDepth}}
+
+// ConfigureWalk sets the walk policy for the scan about to run. Like
+// ResetDiagnostics, it applies process-wide; scans run one at a time.
+func ConfigureWalk(c config.WalkConfig) {
+	if c.MaxDepth <= 0 {
+		c.MaxDepth = config.DefaultMaxWalkDepth
+	}
+	walkConfig.Lock()
+	defer walkConfig.Unlock()
+	walkConfig.WalkConfig = c
+}
+
+func currentWalkConfig() config.WalkConfig {
+	walkConfig.RLock()
+	defer walkConfig.RUnlock()
+	return walkConfig.WalkConfig
+}
+
+// walkDir is filepath.WalkDir under the walk policy. Directories it
+// declines to enter (too deep, on another filesystem, a symlink back up
+// the tree) are left out of the walk and noted in the scan's diagnostics,
+// so fn never sees them.
+func walkDir(root string, fn fs.WalkDirFunc) error {
+	cfg := currentWalkConfig()
+	w := &walker{cfg: cfg, fn: fn, visited: map[string]bool{}}
+	if info, err := os.Stat(root); err == nil {
+		w.rootDev, w.hasDev = deviceID(info)
+	}
+	if real, err := filepath.EvalSymlinks(root); err == nil {
+		w.realRoot = real
+		w.visited[real] = true
+	}
+	return w.walk(root, root, 0)
+}
+
+// walk is filepath.Walk under the walk policy, for callers written against
+// os.FileInfo.
+func walk(root string, fn filepath.WalkFunc) error {
+	return walkDir(root, func(p string, d fs.DirEntry, err error) error {
+		var info fs.FileInfo
+		if d != nil {
+			if i, infoErr := d.Info(); infoErr == nil {
+				info = i
+			} else if err == nil {
+				err = infoErr
+			}
+		}
+		return fn(p, info, err)
+	})
+}
+
+type walker struct {
+	cfg      config.WalkConfig
+	fn       fs.WalkDirFunc
+	rootDev  uint64
+	hasDev   bool
+	realRoot string
+	visited  map[string]bool // real paths of the symlink targets walked
+}
+
+// walk walks dir, reporting paths as if dir were at display, baseDepth
+// levels below the walk's root. display differs from dir only inside a
+// followed symlink, so callers see the link's path rather than its target.
+func (w *walker) walk(dir, display string, baseDepth int) error {
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		shown := display + strings.TrimPrefix(p, dir)
+		if err != nil || p == dir {
+			if p == dir && display != dir && err == nil {
+				// The followed link's target; the caller has already
+				// seen the link itself.
+				return nil
+			}
+			return w.fn(shown, d, err)
+		}
+		depth := baseDepth + strings.Count(strings.TrimPrefix(p, dir), string(filepath.Separator))
+
+		if d.Type()&fs.ModeSymlink != 0 && w.cfg.FollowSymlinks {
+			return w.followLink(p, shown, d, depth)
+		}
+		if d.IsDir() {
+			if reason := w.skipDir(d, depth); reason != "" {
+				recordUnreadable(p, errors.New(reason))
+				return filepath.SkipDir
+			}
+		}
+		return w.fn(shown, d, nil)
+	})
+	if errors.Is(err, filepath.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+// skipDir returns why the directory d, depth levels down, is left out,
+// or "" to enter it.
+func (w *walker) skipDir(d fs.DirEntry, depth int) string {
+	if depth > w.cfg.MaxDepth {
+		return fmt.Sprintf("deeper than walk.maxDepth (%d)", w.cfg.MaxDepth)
+	}
+	if !w.cfg.CrossMounts && w.hasDev {
+		if info, err := d.Info(); err == nil {
+			if dev, ok := deviceID(info); ok && dev != w.rootDev {
+				return "on another filesystem (set walk.crossMounts to scan it)"
+			}
+		}
+	}
+	return ""
+}
+
+// followLink handles a symlink met while following links is on. Links to
+// files are passed through as they are; links to directories are walked
+// unless the target is already covered or would lead back up the tree.
+func (w *walker) followLink(p, shown string, d fs.DirEntry, depth int) error {
+	target, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		// Dangling link: nothing to descend into.
+		return w.fn(shown, d, nil)
+	}
+	info, err := os.Stat(target)
+	if err != nil || !info.IsDir() {
+		return w.fn(shown, d, nil)
+	}
+	if w.realRoot != "" && (target == w.realRoot || strings.HasPrefix(w.realRoot, target+string(filepath.Separator)) || target == string(filepath.Separator)) {
+		recordUnreadable(p, errors.New("symlink to the project or a parent directory; not followed"))
+		return nil
+	}
+	if w.visited[target] || (w.realRoot != "" && strings.HasPrefix(target, w.realRoot+string(filepath.Separator))) {
+		// Walked already, or inside the project where the walk reaches it
+		// anyway.
+		return nil
+	}
+	linked := linkedDirEntry{name: d.Name(), info: info}
+	if reason := w.skipDir(linked, depth); reason != "" {
+		recordUnreadable(p, errors.New(reason))
+		return nil
+	}
+	w.visited[target] = true
+	if err := w.fn(shown, linked, nil); err != nil {
+		if errors.Is(err, filepath.SkipDir) {
+			return nil
+		}
+		return err
+	}
+	if err := w.walk(target, shown, depth); err != nil {
+		if errors.Is(err, filepath.SkipDir) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// linkedDirEntry presents a followed symlink as the directory it points
+// to, under the link's own name.
+type linkedDirEntry struct {
+	name string
+	info fs.FileInfo
+}
+
+func (e linkedDirEntry) Name() string               { return e.name }
+func (e linkedDirEntry) IsDir() bool                { return true }
+func (e linkedDirEntry) Type() fs.FileMode          { return fs.ModeDir }
+func (e linkedDirEntry) Info() (fs.FileInfo, error) { return e.info, nil }
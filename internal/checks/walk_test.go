@@ -0,0 +1,73 @@
+package checks
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+// walkedFiles lists the files walkDir reports under root, relative to it.
+func walkedFiles(t *testing.T, root string) []string {
+	t.Helper()
+	var files []string
+	_ = walkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, filepath.ToSlash(relPath(root, p)))
+		}
+		return nil
+	})
+	slices.Sort(files)
+	return files
+}
+
+func TestWalkDirPolicy(t *testing.T) {
+	defer ConfigureWalk(config.WalkConfig{})
+	defer ResetDiagnostics()
+
+	root := writeFiles(t, map[string]string{
+		"index.html":          "x",
+		"a/b/c/deep.html":     "x",
+		"a/shallow.html":      "x",
+		"node_modules/x/y.js": "x",
+	})
+	shared := writeFiles(t, map[string]string{"ui/button.js": "x"})
+	for link, target := range map[string]string{
+		"shared":   shared,
+		"up":       "..",
+		"rootlink": "/",
+		"self":     root,
+		"a/back":   filepath.Join(root, "a"),
+	} {
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Skipf("symlinks unavailable: %v", err)
+		}
+	}
+
+	// Defaults: links aren't followed, depth is ample.
+	ConfigureWalk(config.WalkConfig{})
+	want := []string{"a/b/c/deep.html", "a/back", "a/shallow.html", "index.html", "node_modules/x/y.js", "rootlink", "self", "shared", "up"}
+	if got := walkedFiles(t, root); !slices.Equal(got, want) {
+		t.Errorf("default walk = %v, want %v", got, want)
+	}
+
+	// Following links enters the external package once, under the link's
+	// path, and never goes back up the tree.
+	ResetDiagnostics()
+	ConfigureWalk(config.WalkConfig{FollowSymlinks: true, MaxDepth: 2})
+	want = []string{"a/shallow.html", "index.html", "node_modules/x/y.js", "shared/ui/button.js"}
+	if got := walkedFiles(t, root); !slices.Equal(got, want) {
+		t.Errorf("following walk = %v, want %v", got, want)
+	}
+	var skipped []string
+	for _, d := range Diagnostics(root) {
+		skipped = append(skipped, d.Path)
+	}
+	wantSkipped := []string{"a/b/c", "rootlink", "self", "up"}
+	if !slices.Equal(skipped, wantSkipped) {
+		t.Errorf("diagnostics = %v, want %v", skipped, wantSkipped)
+	}
+}
@@ -235,7 +235,8 @@ func detectIndexNowInSource(ctx Context, key string) (string, bool) {
 		if _, err := os.Stat(dirPath); err != nil {
 			continue
 		}
-		_ = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		_ = walk(dirPath, func(path string, info os.FileInfo, err error) error {
+			recordUnreadable(path, err)
 			if err != nil || found != "" {
 				return nil
 			}
@@ -249,7 +250,7 @@ func detectIndexNowInSource(ctx Context, key string) (string, bool) {
 			if !sourceExts[strings.ToLower(filepath.Ext(info.Name()))] {
 				return nil
 			}
-			content, err := os.ReadFile(path)
+			content, err := readFile(path)
 			if err != nil {
 				return nil
 			}
@@ -263,7 +264,8 @@ func detectIndexNowInSource(ctx Context, key string) (string, bool) {
 	return found, found != ""
 }
 
-// RobotsTxtCheck verifies robots.txt exists
+// RobotsTxtCheck verifies robots.txt exists and, when a production URL is
+// configured, that the deployed one is valid (see checkLive).
 type RobotsTxtCheck struct{}
 
 func (c RobotsTxtCheck) ID() string {
@@ -275,6 +277,16 @@ func (c RobotsTxtCheck) Title() string {
 }
 
 func (c RobotsTxtCheck) Run(ctx Context) (CheckResult, error) {
+	result, err := c.findInRepo(ctx)
+	if err != nil || ctx.Config.URLs.Production == "" || ctx.Client == nil {
+		return result, err
+	}
+	return c.checkLive(ctx, ctx.Config.URLs.Production, result), nil
+}
+
+// findInRepo looks for a robots.txt in the project, or the code that
+// generates one, falling back to the configured URL.
+func (c RobotsTxtCheck) findInRepo(ctx Context) (CheckResult, error) {
 	// Common web root directories across frameworks
 	webRoots := []string{
 		"public", // Laravel, Rails, many Node.js
@@ -296,7 +308,7 @@ func (c RobotsTxtCheck) Run(ctx Context) (CheckResult, error) {
 			path = root + "/robots.txt"
 		}
 		fullPath := filepath.Join(ctx.RootDir, path)
-		if content, err := os.ReadFile(fullPath); err == nil {
+		if content, err := readFile(fullPath); err == nil {
 			// Check if it has meaningful content
 			contentStr := strings.TrimSpace(string(content))
 			if len(contentStr) > 0 {
@@ -314,7 +326,7 @@ func (c RobotsTxtCheck) Run(ctx Context) (CheckResult, error) {
 	// Check monorepo public directories for static robots.txt
 	monorepoStaticPaths := findMonorepoPublicFiles(ctx.RootDir, "robots.txt")
 	for _, path := range monorepoStaticPaths {
-		if content, err := os.ReadFile(path); err == nil {
+		if content, err := readFile(path); err == nil {
 			contentStr := strings.TrimSpace(string(content))
 			if len(contentStr) > 0 {
 				relPath := relPath(ctx.RootDir, path)
@@ -389,7 +401,8 @@ func (c RobotsTxtCheck) Run(ctx Context) (CheckResult, error) {
 		if _, err := os.Stat(dirPath); err != nil {
 			continue
 		}
-		_ = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		_ = walk(dirPath, func(path string, info os.FileInfo, err error) error {
+			recordUnreadable(path, err)
 			if err != nil || robotsFound {
 				return nil
 			}
@@ -452,7 +465,9 @@ func (c RobotsTxtCheck) Run(ctx Context) (CheckResult, error) {
 	}, nil
 }
 
-// SitemapCheck verifies sitemap.xml exists
+// SitemapCheck verifies sitemap.xml exists and, when a production URL is
+// configured, that the deployed one is valid and lists live pages (see
+// checkLive).
 type SitemapCheck struct{}
 
 func (c SitemapCheck) ID() string {
@@ -464,6 +479,38 @@ func (c SitemapCheck) Title() string {
 }
 
 func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
+	result, err := c.findInRepo(ctx)
+	if err != nil || ctx.Config.URLs.Production == "" || ctx.Client == nil {
+		return result, err
+	}
+	return c.checkLive(ctx, ctx.Config.URLs.Production, result), nil
+}
+
+// sitemapGenerators lists the sitemap files of the packs for stacks, then
+// those of every other pack.
+func sitemapGenerators(stacks []string) []string {
+	set := currentStacks()
+	var paths []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		paths = append(paths, set.Own(name).Sitemaps...)
+	}
+	for _, name := range stacks {
+		add(name)
+	}
+	for _, name := range set.Names() {
+		add(name)
+	}
+	return paths
+}
+
+// findInRepo looks for a sitemap.xml in the project, or the code, plugin
+// or package that generates one, falling back to the configured URL.
+func (c SitemapCheck) findInRepo(ctx Context) (CheckResult, error) {
 	// Common web root directories across frameworks
 	webRoots := []string{
 		"public", // Laravel, Rails, many Node.js
@@ -485,7 +532,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 			path = root + "/sitemap.xml"
 		}
 		fullPath := filepath.Join(ctx.RootDir, path)
-		if content, err := os.ReadFile(fullPath); err == nil {
+		if content, err := readFile(fullPath); err == nil {
 			// Check if it has meaningful content
 			contentStr := strings.TrimSpace(string(content))
 			if len(contentStr) > 0 {
@@ -503,7 +550,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 	// Check monorepo public directories for static sitemap.xml
 	monorepoStaticPaths := findMonorepoPublicFiles(ctx.RootDir, "sitemap.xml")
 	for _, path := range monorepoStaticPaths {
-		if content, err := os.ReadFile(path); err == nil {
+		if content, err := readFile(path); err == nil {
 			contentStr := strings.TrimSpace(string(content))
 			if len(contentStr) > 0 {
 				relPath := relPath(ctx.RootDir, path)
@@ -518,41 +565,28 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 		}
 	}
 
-	// Check for dynamic sitemap generation across JS/TS frameworks
-	jsSitemapPaths := []string{
-		// Next.js App Router
-		"app/sitemap.ts", "app/sitemap.tsx", "app/sitemap.js", "app/sitemap.jsx",
-		"app/sitemap.xml/route.ts", "app/sitemap.xml/route.tsx", "app/sitemap.xml/route.js", "app/sitemap.xml/route.jsx",
-		"src/app/sitemap.ts", "src/app/sitemap.tsx", "src/app/sitemap.js", "src/app/sitemap.jsx",
-		"src/app/sitemap.xml/route.ts", "src/app/sitemap.xml/route.tsx", "src/app/sitemap.xml/route.js", "src/app/sitemap.xml/route.jsx",
-		// SvelteKit
-		"src/routes/sitemap.xml/+server.ts", "src/routes/sitemap.xml/+server.js",
-		// Nuxt
-		"server/routes/sitemap.xml.ts", "server/routes/sitemap.xml.js",
-		"server/routes/sitemap.xml.get.ts", "server/routes/sitemap.xml.get.js",
-		// Remix
-		"app/routes/sitemap[.]xml.ts", "app/routes/sitemap[.]xml.tsx",
-		"app/routes/sitemap.xml.ts", "app/routes/sitemap.xml.tsx",
-		// Angular (universal/SSR)
-		"src/assets/sitemap.xml",
-		// Eleventy
-		"src/sitemap.njk", "src/sitemap.liquid", "sitemap.njk", "sitemap.liquid",
-		"src/sitemap.11ty.js", "sitemap.11ty.js",
-	}
-
-	for _, path := range jsSitemapPaths {
-		fullPath := filepath.Join(ctx.RootDir, path)
-		if _, err := os.Stat(fullPath); err == nil {
+	// Check for the routes, controllers and templates that generate a
+	// sitemap at runtime, declared stacks first. Every pack's are tried:
+	// the declared stack may be the backend while the sitemap comes from
+	// elsewhere, so a file that only generates sitemaps counts wherever
+	// it's found.
+	for _, path := range sitemapGenerators(ctx.Config.AllStacks()) {
+		found, ok := path, false
+		if _, err := os.Stat(filepath.Join(ctx.RootDir, path)); err == nil {
+			ok = true
+		} else if strings.Contains(path, "*") {
+			found, ok = findProjectPath(ctx.RootDir, path)
+		}
+		if ok {
 			return CheckResult{
 				ID:       c.ID(),
 				Title:    c.Title(),
 				Severity: SeverityInfo,
 				Passed:   true,
-				Message:  "sitemap.xml generated via " + path,
+				Message:  "sitemap.xml generated via " + found,
 			}, nil
 		}
 	}
-
 	// Check monorepo structures for Next.js App Router sitemap
 	monorepoSitemapPaths := findMonorepoNextFiles(ctx.RootDir, []string{"sitemap.ts", "sitemap.tsx", "sitemap.js", "sitemap.jsx"})
 	for _, path := range monorepoSitemapPaths {
@@ -581,7 +615,8 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 		if _, err := os.Stat(dirPath); err != nil {
 			continue
 		}
-		_ = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		_ = walk(dirPath, func(path string, info os.FileInfo, err error) error {
+			recordUnreadable(path, err)
 			if err != nil || sitemapFound {
 				return nil
 			}
@@ -619,53 +654,6 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 		}, nil
 	}
 
-	// Check for dynamic sitemap generation across backend frameworks
-	dynamicSitemapPaths := []string{
-		// Ruby on Rails
-		"app/controllers/sitemap_controller.rb",
-		"app/controllers/sitemaps_controller.rb",
-		"config/sitemap.rb", // sitemap_generator gem config
-		// Laravel
-		"app/Http/Controllers/SitemapController.php",
-		"routes/sitemap.php",
-		// PHP — direct sitemap.php file in common web root directories
-		"sitemap.php",
-		"web/sitemap.php",
-		"public/sitemap.php",
-		"public_html/sitemap.php",
-		"www/sitemap.php",
-		"htdocs/sitemap.php",
-		// Django
-		"sitemaps.py",
-		// Phoenix/Elixir
-		"lib/*/controllers/sitemap_controller.ex",
-		// Go
-		"handlers/sitemap.go",
-		"internal/handlers/sitemap.go",
-		"pkg/handlers/sitemap.go",
-		"cmd/server/sitemap.go",
-		// Rust (Actix, Axum)
-		"src/routes/sitemap.rs",
-		"src/handlers/sitemap.rs",
-		// Node.js/Express
-		"routes/sitemap.js", "routes/sitemap.ts",
-		"src/routes/sitemap.js", "src/routes/sitemap.ts",
-		// ASP.NET
-		"Controllers/SitemapController.cs",
-	}
-
-	for _, pattern := range dynamicSitemapPaths {
-		if found, ok := findProjectPath(ctx.RootDir, pattern); ok {
-			return CheckResult{
-				ID:       c.ID(),
-				Title:    c.Title(),
-				Severity: SeverityInfo,
-				Passed:   true,
-				Message:  "sitemap.xml generated via " + found,
-			}, nil
-		}
-	}
-
 	// Check for sitemap view directories
 	sitemapViewDirs := []string{
 		// Rails
@@ -696,7 +684,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 	}
 	for _, path := range djangoUrlsPaths {
 		fullPath := filepath.Join(ctx.RootDir, path)
-		if content, err := os.ReadFile(fullPath); err == nil {
+		if content, err := readFile(fullPath); err == nil {
 			if strings.Contains(string(content), "sitemap") {
 				return CheckResult{
 					ID:       c.ID(),
@@ -711,7 +699,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 
 	// Check for sitemap generation in package.json (Node/Next.js)
 	pkgPath := filepath.Join(ctx.RootDir, "package.json")
-	if content, err := os.ReadFile(pkgPath); err == nil {
+	if content, err := readFile(pkgPath); err == nil {
 		if strings.Contains(string(content), "next-sitemap") ||
 			strings.Contains(string(content), "sitemap") {
 			return CheckResult{
@@ -726,7 +714,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 
 	// Check for sitemap in Gemfile (Rails)
 	gemfilePath := filepath.Join(ctx.RootDir, "Gemfile")
-	if content, err := os.ReadFile(gemfilePath); err == nil {
+	if content, err := readFile(gemfilePath); err == nil {
 		if strings.Contains(string(content), "sitemap_generator") ||
 			strings.Contains(string(content), "sitemap") {
 			return CheckResult{
@@ -741,7 +729,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 
 	// Check for sitemap in composer.json (Laravel/PHP)
 	composerPath := filepath.Join(ctx.RootDir, "composer.json")
-	if content, err := os.ReadFile(composerPath); err == nil {
+	if content, err := readFile(composerPath); err == nil {
 		if strings.Contains(string(content), "spatie/laravel-sitemap") ||
 			strings.Contains(string(content), "sitemap") {
 			return CheckResult{
@@ -756,7 +744,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 
 	// Check for sitemap in requirements.txt (Python/Flask/Django)
 	requirementsPath := filepath.Join(ctx.RootDir, "requirements.txt")
-	if content, err := os.ReadFile(requirementsPath); err == nil {
+	if content, err := readFile(requirementsPath); err == nil {
 		if strings.Contains(string(content), "django-sitemap") ||
 			strings.Contains(string(content), "flask-sitemap") ||
 			strings.Contains(string(content), "sitemap") {
@@ -794,7 +782,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 
 	// Craft CMS: Check for SEO plugins in composer.json
 	craftComposerPath := filepath.Join(ctx.RootDir, "composer.json")
-	if content, err := os.ReadFile(craftComposerPath); err == nil {
+	if content, err := readFile(craftComposerPath); err == nil {
 		// Check for Craft CMS SEO plugins that generate sitemaps
 		craftSeoPlugins := []string{
 			"nystudio107/craft-seomatic",
@@ -850,7 +838,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 
 	// Jekyll: Check for jekyll-sitemap in _config.yml or Gemfile
 	jekyllConfig := filepath.Join(ctx.RootDir, "_config.yml")
-	if content, err := os.ReadFile(jekyllConfig); err == nil {
+	if content, err := readFile(jekyllConfig); err == nil {
 		if strings.Contains(string(content), "jekyll-sitemap") {
 			return CheckResult{
 				ID:       c.ID(),
@@ -864,7 +852,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 
 	// Gatsby: Check for gatsby-plugin-sitemap
 	gatsbyConfig := filepath.Join(ctx.RootDir, "gatsby-config.js")
-	if content, err := os.ReadFile(gatsbyConfig); err == nil {
+	if content, err := readFile(gatsbyConfig); err == nil {
 		if strings.Contains(string(content), "gatsby-plugin-sitemap") {
 			return CheckResult{
 				ID:       c.ID(),
@@ -880,7 +868,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 	astroConfigs := []string{"astro.config.mjs", "astro.config.ts", "astro.config.js"}
 	for _, cfg := range astroConfigs {
 		fullPath := filepath.Join(ctx.RootDir, cfg)
-		if content, err := os.ReadFile(fullPath); err == nil {
+		if content, err := readFile(fullPath); err == nil {
 			if strings.Contains(string(content), "@astrojs/sitemap") || strings.Contains(string(content), "sitemap") {
 				return CheckResult{
 					ID:       c.ID(),
@@ -897,7 +885,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 	nuxtConfigs := []string{"nuxt.config.ts", "nuxt.config.js"}
 	for _, cfg := range nuxtConfigs {
 		fullPath := filepath.Join(ctx.RootDir, cfg)
-		if content, err := os.ReadFile(fullPath); err == nil {
+		if content, err := readFile(fullPath); err == nil {
 			if strings.Contains(string(content), "@nuxtjs/sitemap") || strings.Contains(string(content), "sitemap") {
 				return CheckResult{
 					ID:       c.ID(),
@@ -912,7 +900,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 
 	// SvelteKit: Check for sitemap in svelte.config.js
 	svelteConfig := filepath.Join(ctx.RootDir, "svelte.config.js")
-	if content, err := os.ReadFile(svelteConfig); err == nil {
+	if content, err := readFile(svelteConfig); err == nil {
 		if strings.Contains(string(content), "sitemap") {
 			return CheckResult{
 				ID:       c.ID(),
@@ -928,7 +916,7 @@ func (c SitemapCheck) Run(ctx Context) (CheckResult, error) {
 	eleventyConfigs := []string{".eleventy.js", "eleventy.config.js", "eleventy.config.cjs", "eleventy.config.mjs"}
 	for _, cfg := range eleventyConfigs {
 		fullPath := filepath.Join(ctx.RootDir, cfg)
-		if content, err := os.ReadFile(fullPath); err == nil {
+		if content, err := readFile(fullPath); err == nil {
 			if strings.Contains(string(content), "sitemap") {
 				return CheckResult{
 					ID:       c.ID(),
@@ -1028,7 +1016,7 @@ func (c LLMsTxtCheck) Run(ctx Context) (CheckResult, error) {
 		}
 		for _, path := range paths {
 			fullPath := filepath.Join(ctx.RootDir, path)
-			if content, err := os.ReadFile(fullPath); err == nil {
+			if content, err := readFile(fullPath); err == nil {
 				// Check if it has meaningful content
 				contentStr := strings.TrimSpace(string(content))
 				if len(contentStr) > 0 {
@@ -1047,7 +1035,7 @@ func (c LLMsTxtCheck) Run(ctx Context) (CheckResult, error) {
 	// Check monorepo public directories
 	monorepoPublicPaths := findMonorepoPublicFiles(ctx.RootDir, "llms.txt")
 	for _, path := range monorepoPublicPaths {
-		if content, err := os.ReadFile(path); err == nil {
+		if content, err := readFile(path); err == nil {
 			contentStr := strings.TrimSpace(string(content))
 			if len(contentStr) > 0 {
 				relPath := relPath(ctx.RootDir, path)
@@ -1155,7 +1143,8 @@ func (c LLMsTxtCheck) Run(ctx Context) (CheckResult, error) {
 		if _, err := os.Stat(dirPath); err != nil {
 			continue
 		}
-		_ = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		_ = walk(dirPath, func(path string, info os.FileInfo, err error) error {
+			recordUnreadable(path, err)
 			if err != nil || llmsFound {
 				return nil
 			}
@@ -1265,7 +1254,7 @@ func (c AdsTxtCheck) Run(ctx Context) (CheckResult, error) {
 			path = root + "/ads.txt"
 		}
 		fullPath := filepath.Join(ctx.RootDir, path)
-		if content, err := os.ReadFile(fullPath); err == nil {
+		if content, err := readFile(fullPath); err == nil {
 			// Check if it has meaningful content
 			contentStr := strings.TrimSpace(string(content))
 			if len(contentStr) > 0 {
@@ -1342,7 +1331,7 @@ func (c IndexNowCheck) Run(ctx Context) (CheckResult, error) {
 			}
 			for _, path := range paths {
 				fullPath := filepath.Join(ctx.RootDir, path)
-				if content, err := os.ReadFile(fullPath); err == nil {
+				if content, err := readFile(fullPath); err == nil {
 					contentStr := strings.TrimSpace(string(content))
 					if contentStr == key {
 						return CheckResult{
@@ -1364,12 +1353,13 @@ func (c IndexNowCheck) Run(ctx Context) (CheckResult, error) {
 		dir := filepath.Join(ctx.RootDir, root)
 		entries, err := os.ReadDir(dir)
 		if err != nil {
+			recordUnreadable(dir, err)
 			continue
 		}
 		for _, entry := range entries {
 			if !entry.IsDir() && hexPattern.MatchString(entry.Name()) {
 				foundKey := strings.TrimSuffix(entry.Name(), ".txt")
-				content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				content, err := readFile(filepath.Join(dir, entry.Name()))
 				if err == nil && strings.TrimSpace(string(content)) == foundKey {
 					path := entry.Name()
 					if root != "" {
@@ -1446,6 +1436,7 @@ func (c IndexNowCheck) Run(ctx Context) (CheckResult, error) {
 		dirPath := filepath.Join(ctx.RootDir, dir)
 		entries, err := os.ReadDir(dirPath)
 		if err != nil {
+			recordUnreadable(dirPath, err)
 			continue
 		}
 		for _, entry := range entries {
@@ -1453,7 +1444,7 @@ func (c IndexNowCheck) Run(ctx Context) (CheckResult, error) {
 				continue
 			}
 			filePath := filepath.Join(dirPath, entry.Name())
-			content, err := os.ReadFile(filePath)
+			content, err := readFile(filePath)
 			if err != nil {
 				continue
 			}
@@ -1479,7 +1470,7 @@ func (c IndexNowCheck) Run(ctx Context) (CheckResult, error) {
 	}
 	for _, path := range routeFiles {
 		fullPath := filepath.Join(ctx.RootDir, path)
-		content, err := os.ReadFile(fullPath)
+		content, err := readFile(fullPath)
 		if err != nil {
 			continue
 		}
@@ -1499,7 +1490,7 @@ func (c IndexNowCheck) Run(ctx Context) (CheckResult, error) {
 	envFiles := []string{".env", ".env.example", ".env.development", ".env.production", ".env.local"}
 	for _, path := range envFiles {
 		fullPath := filepath.Join(ctx.RootDir, path)
-		content, err := os.ReadFile(fullPath)
+		content, err := readFile(fullPath)
 		if err != nil {
 			continue
 		}
@@ -1546,7 +1537,7 @@ func (c IndexNowCheck) Run(ctx Context) (CheckResult, error) {
 	// Check for IndexNow packages in dependency files
 	// Gemfile (Rails)
 	gemfilePath := filepath.Join(ctx.RootDir, "Gemfile")
-	if content, err := os.ReadFile(gemfilePath); err == nil {
+	if content, err := readFile(gemfilePath); err == nil {
 		if strings.Contains(string(content), "indexnow") || strings.Contains(string(content), "index_now") {
 			return CheckResult{
 				ID:       c.ID(),
@@ -1560,7 +1551,7 @@ func (c IndexNowCheck) Run(ctx Context) (CheckResult, error) {
 
 	// package.json (Node.js)
 	pkgPath := filepath.Join(ctx.RootDir, "package.json")
-	if content, err := os.ReadFile(pkgPath); err == nil {
+	if content, err := readFile(pkgPath); err == nil {
 		if strings.Contains(string(content), "indexnow") {
 			return CheckResult{
 				ID:       c.ID(),
@@ -1574,7 +1565,7 @@ func (c IndexNowCheck) Run(ctx Context) (CheckResult, error) {
 
 	// composer.json (PHP/Laravel)
 	composerPath := filepath.Join(ctx.RootDir, "composer.json")
-	if content, err := os.ReadFile(composerPath); err == nil {
+	if content, err := readFile(composerPath); err == nil {
 		if strings.Contains(string(content), "indexnow") {
 			return CheckResult{
 				ID:       c.ID(),
@@ -1671,7 +1662,7 @@ func (c HumansTxtCheck) Run(ctx Context) (CheckResult, error) {
 			path = root + "/humans.txt"
 		}
 		fullPath := filepath.Join(ctx.RootDir, path)
-		if content, err := os.ReadFile(fullPath); err == nil {
+		if content, err := readFile(fullPath); err == nil {
 			contentStr := strings.TrimSpace(string(content))
 			if len(contentStr) > 0 {
 				return CheckResult{
@@ -1709,6 +1700,7 @@ func findMonorepoNextFiles(rootDir string, filenames []string) []string {
 		monoDir := filepath.Join(rootDir, monoRoot)
 		entries, err := os.ReadDir(monoDir)
 		if err != nil {
+			recordUnreadable(monoDir, err)
 			continue
 		}
 
@@ -1744,6 +1736,7 @@ func findMonorepoPublicFiles(rootDir, filename string) []string {
 		monoDir := filepath.Join(rootDir, monoRoot)
 		entries, err := os.ReadDir(monoDir)
 		if err != nil {
+			recordUnreadable(monoDir, err)
 			continue
 		}
 
@@ -316,3 +316,95 @@ func TestIndexNowCheckFindsPhoenixService(t *testing.T) {
 	}
 	t.Logf("phoenix index_now.ex -> passed=%v msg=%q", res.Passed, res.Message)
 }
+
+func TestParseRobotsTxt(t *testing.T) {
+	robots := parseRobotsTxt("\ufeffDisallow: /early\nUser-agent: *\nUser-agent: Googlebot\nDisallow: /admin # staff only\nDissallow: /tmp\nCrawl-delay: soon\nSitemap: /sitemap.xml\nUser-agent: GPTBot\nDisallow: /\n")
+	if len(robots.groups) != 2 || strings.Join(robots.groups[0].agents, ",") != "*,googlebot" {
+		t.Errorf("groups = %+v", robots.groups)
+	}
+	want := []string{
+		"line 1: Disallow: /early comes before any User-agent line, so crawlers ignore it",
+		`line 5: unknown directive "dissallow"`,
+		`line 6: Crawl-delay "soon" isn't a number`,
+		`line 7: Sitemap must be a full URL, got "/sitemap.xml"`,
+	}
+	if !reflect.DeepEqual(robots.problems, want) {
+		t.Errorf("problems = %q\nwant %q", robots.problems, want)
+	}
+	// Blocking an AI crawler is deliberate.
+	if blocked := robots.blockedCrawlers(); len(blocked) != 0 {
+		t.Errorf("blockedCrawlers = %v, want none", blocked)
+	}
+	if blocked := parseRobotsTxt("User-agent: *\nDisallow: /\n").blockedCrawlers(); !reflect.DeepEqual(blocked, []string{"*"}) {
+		t.Errorf("blockedCrawlers = %v, want [*]", blocked)
+	}
+}
+
+func TestRobotsTxtCheckLive(t *testing.T) {
+	robots := ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			if robots == "" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(robots))
+		case "/sitemap.xml":
+			_, _ = w.Write([]byte("<urlset></urlset>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	dir := writeFiles(t, map[string]string{"public/robots.txt": "User-agent: *\nDisallow:\n"})
+	cfg := &config.PreflightConfig{}
+	cfg.URLs.Production = srv.URL
+	run := func(t *testing.T) CheckResult {
+		t.Helper()
+		result, err := RobotsTxtCheck{}.Run(Context{RootDir: dir, Config: cfg, Client: srv.Client()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	t.Run("not deployed", func(t *testing.T) {
+		robots = ""
+		result := run(t)
+		if result.Passed || !strings.Contains(result.Message, "returned HTTP 404") {
+			t.Errorf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+		if len(result.Suggestions) == 0 || !strings.Contains(result.Suggestions[0], "public/robots.txt") {
+			t.Errorf("suggestions = %q, want the repo file named", result.Suggestions)
+		}
+	})
+
+	t.Run("blocks everything", func(t *testing.T) {
+		robots = "User-agent: *\nDisallow: /\n"
+		if result := run(t); result.Passed || result.Severity != SeverityError {
+			t.Errorf("got passed=%v severity=%v, want an error", result.Passed, result.Severity)
+		}
+	})
+
+	t.Run("unreachable sitemap", func(t *testing.T) {
+		robots = "User-agent: *\nDisallow: /admin\nSitemap: " + srv.URL + "/sitemap-index.xml\n"
+		result := run(t)
+		if result.Passed || result.Severity != SeverityWarn {
+			t.Fatalf("got passed=%v severity=%v, want a warning", result.Passed, result.Severity)
+		}
+		if want := "Sitemap " + srv.URL + "/sitemap-index.xml returns HTTP 404"; !reflect.DeepEqual(result.Suggestions, []string{want}) {
+			t.Errorf("suggestions = %q, want %q", result.Suggestions, want)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		robots = "User-agent: *\nDisallow: /admin\nSitemap: " + srv.URL + "/sitemap.xml\n"
+		result := run(t)
+		if !result.Passed || result.Message != "robots.txt served at "+srv.URL+"/robots.txt, 1 sitemap(s) reachable" {
+			t.Errorf("got passed=%v message=%q", result.Passed, result.Message)
+		}
+	})
+}
@@ -176,6 +176,7 @@ func getFinalURL(ctx context.Context, urlStr string) (string, error) {
 	// SafeHTTPClient guards both the initial dial AND each redirect hop
 	// against private / loopback / link-local addresses.
 	client := netutil.SafeHTTPClient(5 * time.Second)
+	CountRequests(client)
 
 	req, err := http.NewRequestWithContext(ctx, "HEAD", urlStr, nil)
 	if err != nil {
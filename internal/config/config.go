@@ -1,20 +1,252 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"gopkg.in/yaml.v3"
 )
 
 type PreflightConfig struct {
 	ProjectName string                   `yaml:"projectName"`
-	Stack       string                   `yaml:"stack"`
+	Stack       string                   `yaml:"-"`
+	Stacks      StackList                `yaml:"stack"`
 	URLs        URLConfig                `yaml:"urls,omitempty"`
 	Services    map[string]ServiceConfig `yaml:"services,omitempty"`
 	Checks      ChecksConfig             `yaml:"checks,omitempty"`
 	Ignore      []string                 `yaml:"ignore,omitempty"`
+	// Snooze maps a check or service ID to the date (YYYY-MM-DD) until
+	// which it is skipped. Unlike Ignore it lapses on its own, so a
+	// finding deferred during launch week comes back afterwards.
+	Snooze map[string]string `yaml:"snooze,omitempty"`
+	// Require maps a group name (error_tracking, analytics) to service
+	// IDs that can each fill it. The required_services check fails when
+	// no provider in a group is declared and working.
+	Require map[string][]string `yaml:"require,omitempty"`
+	// FailOn is the lowest severity that makes scan exit non-zero: "warn"
+	// (the default) or "error", which lets warnings through a CI gate.
+	FailOn string `yaml:"failOn,omitempty"`
+	// Severities maps a check ID to the severity ("info", "warn" or
+	// "error") it reports with when it fails, in place of its own. Exit
+	// codes and output follow the override.
+	Severities map[string]string `yaml:"severities,omitempty"`
+	// Profiles maps a profile name to the check IDs scan --profile runs
+	// for it, replacing the built-in profile of that name or adding one.
+	Profiles map[string][]string `yaml:"profiles,omitempty"`
+	// Compliance names the regulated verticals the product is in (coppa,
+	// gambling, alcohol, tobacco, cannabis). The regulated_gating check
+	// then looks for the age gate or region block each one needs.
+	Compliance []string `yaml:"compliance,omitempty"`
+	// CustomChecks are project-specific launch checklist items declared
+	// in preflight.yml instead of written in Go.
+	CustomChecks []CustomCheckConfig `yaml:"customChecks,omitempty"`
+	// Walk bounds the directory walks checks make.
+	Walk WalkConfig `yaml:"walk,omitempty"`
+	// DNS picks the resolver for the scan's DNS lookups.
+	DNS DNSConfig `yaml:"dns,omitempty"`
+	// Monorepo scans each app of a monorepo as a project of its own.
+	Monorepo *MonorepoConfig `yaml:"monorepo,omitempty"`
+}
+
+// StackList is stack: in preflight.yml, written as one stack name or a
+// list of them. A hybrid repo, such as a Rails API with a React frontend,
+// lists both and stack-aware checks consider each. Stack holds the first,
+// the project's main stack; AllStacks returns them all.
+type StackList []string
+
+func (s *StackList) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		*s = nil
+		if value.Value != "" {
+			*s = StackList{value.Value}
+		}
+		return nil
+	case yaml.SequenceNode:
+		var list []string
+		if err := value.Decode(&list); err != nil {
+			return err
+		}
+		*s = list
+		return nil
+	}
+	return fmt.Errorf("line %d: stack must be a stack name or a list of them", value.Line)
+}
+
+// MarshalYAML writes a single stack as a plain value, as it always was.
+func (s StackList) MarshalYAML() (interface{}, error) {
+	if len(s) == 1 {
+		return s[0], nil
+	}
+	return []string(s), nil
+}
+
+// AllStacks returns every stack the project declares, the main one
+// first. A config built in code with only Stack set has that one.
+func (c *PreflightConfig) AllStacks() []string {
+	if len(c.Stacks) > 0 {
+		return c.Stacks
+	}
+	if c.Stack != "" {
+		return []string{c.Stack}
+	}
+	return nil
+}
+
+// MonorepoConfig turns on per-app scanning (scan --monorepo does too).
+// Workspaces are doublestar globs of app directories, "!" excluding;
+// without them the package.json or pnpm workspaces are used, then
+// apps/*, packages/* and services/*.
+type MonorepoConfig struct {
+	Enabled    bool     `yaml:"enabled"`
+	Workspaces []string `yaml:"workspaces,omitempty"`
+}
+
+// DefaultMaxWalkDepth is how many directory levels below its starting
+// point a walk descends when walk.maxDepth isn't set. Real projects stay
+// well inside it; a symlink or bind-mount loop doesn't.
+const DefaultMaxWalkDepth = 32
+
+// WalkConfig controls how the scan treats symlinks, mount points and deep
+// trees. The zero value is the safe default: symlinked directories aren't
+// followed, walks stay on the project's filesystem, and they stop
+// DefaultMaxWalkDepth levels down.
+type WalkConfig struct {
+	// FollowSymlinks descends into symlinked directories, such as a
+	// monorepo package linked in from outside the project. Links back up
+	// to the project or one of its parents (a link to /) are never
+	// followed, and each target is walked once.
+	FollowSymlinks bool `yaml:"followSymlinks,omitempty"`
+	// CrossMounts descends into directories on other filesystems (bind
+	// mounts, network shares, /proc).
+	CrossMounts bool `yaml:"crossMounts,omitempty"`
+	// MaxDepth overrides DefaultMaxWalkDepth.
+	MaxDepth int `yaml:"maxDepth,omitempty"`
+}
+
+// DNSConfig is the dns section of preflight.yml. Without it, lookups go to
+// the system resolver. Some CI runners block port 53, which fails
+// email_auth and ssl and every request by hostname; naming a
+// DNS-over-HTTPS resolver sends lookups over port 443 instead.
+type DNSConfig struct {
+	// DoH is "cloudflare", "google" or the https URL of a resolver serving
+	// the DoH JSON API.
+	DoH string `yaml:"doh,omitempty"`
+	// Mode is "fallback" (the default), which asks the system resolver
+	// first and DoH when it fails, or "doh", which skips the system
+	// resolver and its timeouts altogether.
+	Mode string `yaml:"mode,omitempty"`
+}
+
+// validate checks the dns section. Mode needs a resolver to apply to.
+func (d DNSConfig) validate() error {
+	switch d.DoH {
+	case "", "cloudflare", "google":
+	default:
+		u, err := url.Parse(d.DoH)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("dns.doh: want cloudflare, google or an https URL, got %q", d.DoH)
+		}
+	}
+	switch d.Mode {
+	case "":
+	case "fallback", "doh":
+		if d.DoH == "" {
+			return fmt.Errorf("dns.mode: set dns.doh to the resolver to use")
+		}
+	default:
+		return fmt.Errorf("dns.mode: unknown mode %q (want fallback or doh)", d.Mode)
+	}
+	return nil
+}
+
+// CustomCheckConfig is one user-defined rule. Files matching File (a path
+// or doublestar glob) must exist; with MustMatch set every one of them
+// must match that regular expression, and with MustNotMatch none may.
+type CustomCheckConfig struct {
+	ID           string `yaml:"id"`
+	Title        string `yaml:"title,omitempty"`
+	Severity     string `yaml:"severity,omitempty"` // warn (default) or error
+	File         string `yaml:"file"`
+	MustMatch    string `yaml:"mustMatch,omitempty"`
+	MustNotMatch string `yaml:"mustNotMatch,omitempty"`
+	Suggestion   string `yaml:"suggestion,omitempty"`
+}
+
+// RequiredFile is one entry under checks.requiredFiles: a file, relative
+// to the project root, that must exist and have content. It can be
+// written as just the path, which reports a missing file as a warning.
+type RequiredFile struct {
+	Path     string `yaml:"path"`
+	Severity string `yaml:"severity,omitempty"` // warn (default) or error
+}
+
+// UnmarshalYAML accepts a bare path as well as a mapping.
+func (f *RequiredFile) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*f = RequiredFile{Path: value.Value}
+		return nil
+	}
+	type plain RequiredFile
+	return value.Decode((*plain)(f))
+}
+
+// validateRequiredFiles rejects entries without a path, paths that leave
+// the project, and unknown severities.
+func validateRequiredFiles(files []RequiredFile) error {
+	for i, f := range files {
+		if strings.TrimSpace(f.Path) == "" {
+			return fmt.Errorf("checks.requiredFiles[%d]: path is required", i)
+		}
+		if !filepath.IsLocal(filepath.FromSlash(f.Path)) {
+			return fmt.Errorf("checks.requiredFiles: %q must be a path inside the project", f.Path)
+		}
+		if err := ValidateFailOn(f.Severity); err != nil {
+			return fmt.Errorf("checks.requiredFiles: %s: severity: %w", f.Path, err)
+		}
+	}
+	return nil
+}
+
+// ValidatePaths rejects file settings that leave the project. Load
+// doesn't, since a project's own config may point at a file it shares
+// with a sibling; an upload's config is checked before any check reads
+// the files it names.
+func (c *PreflightConfig) ValidatePaths() error {
+	var paths [][2]string
+	if e := c.Checks.EnvParity; e != nil {
+		paths = append(paths,
+			[2]string{"checks.envParity.envFile", e.EnvFile},
+			[2]string{"checks.envParity.exampleFile", e.ExampleFile},
+			[2]string{"checks.envParity.productionFile", e.ProductionFile})
+	}
+	if s := c.Checks.SEOMeta; s != nil {
+		paths = append(paths, [2]string{"checks.seoMeta.mainLayout", s.MainLayout})
+	}
+	if ch := c.Checks.Changelog; ch != nil {
+		paths = append(paths, [2]string{"checks.changelog.file", ch.File})
+	}
+	if o := c.Checks.OpsReadiness; o != nil {
+		paths = append(paths,
+			[2]string{"checks.opsReadiness.runbook", o.Runbook},
+			[2]string{"checks.opsReadiness.deployDoc", o.DeployDoc})
+	}
+	for _, p := range paths {
+		if p[1] != "" && !filepath.IsLocal(filepath.FromSlash(p[1])) {
+			return fmt.Errorf("%s: %q must be a path inside the project", p[0], p[1])
+		}
+	}
+	return nil
 }
 
 type URLConfig struct {
@@ -27,23 +259,49 @@ type ServiceConfig struct {
 }
 
 type ChecksConfig struct {
-	EnvParity      *EnvParityConfig      `yaml:"envParity,omitempty"`
-	HealthEndpoint *HealthEndpointConfig `yaml:"healthEndpoint,omitempty"`
-	StripeWebhook  *StripeWebhookConfig  `yaml:"stripeWebhook,omitempty"`
-	SEOMeta        *SEOMetaConfig        `yaml:"seoMeta,omitempty"`
-	Security       *SecurityConfig       `yaml:"security,omitempty"`
-	Secrets        *SecretsConfig        `yaml:"secrets,omitempty"`
-	AdsTxt         *AdsTxtConfig         `yaml:"adsTxt,omitempty"`
-	License        *LicenseConfig        `yaml:"license,omitempty"`
-	IndexNow       *IndexNowConfig       `yaml:"indexNow,omitempty"`
-	EmailAuth      *EmailAuthConfig      `yaml:"emailAuth,omitempty"`
-	HumansTxt      *HumansTxtConfig      `yaml:"humansTxt,omitempty"`
+	EnvParity       *EnvParityConfig       `yaml:"envParity,omitempty"`
+	HealthEndpoint  *HealthEndpointConfig  `yaml:"healthEndpoint,omitempty"`
+	StripeWebhook   *StripeWebhookConfig   `yaml:"stripeWebhook,omitempty"`
+	SEOMeta         *SEOMetaConfig         `yaml:"seoMeta,omitempty"`
+	Security        *SecurityConfig        `yaml:"security,omitempty"`
+	Secrets         *SecretsConfig         `yaml:"secrets,omitempty"`
+	AdsTxt          *AdsTxtConfig          `yaml:"adsTxt,omitempty"`
+	License         *LicenseConfig         `yaml:"license,omitempty"`
+	IndexNow        *IndexNowConfig        `yaml:"indexNow,omitempty"`
+	EmailAuth       *EmailAuthConfig       `yaml:"emailAuth,omitempty"`
+	BIMI            *BIMIConfig            `yaml:"bimi,omitempty"`
+	IPv6            *IPv6Config            `yaml:"ipv6,omitempty"`
+	HumansTxt       *HumansTxtConfig       `yaml:"humansTxt,omitempty"`
+	Routes          *RoutesConfig          `yaml:"routes,omitempty"`
+	AuthRoutes      []string               `yaml:"authRoutes,omitempty"`
+	RequiredFiles   []RequiredFile         `yaml:"requiredFiles,omitempty"`
+	Drift           *DriftConfig           `yaml:"drift,omitempty"`
+	Mirrors         *MirrorsConfig         `yaml:"mirrors,omitempty"`
+	Smoke           *SmokeConfig           `yaml:"smoke,omitempty"`
+	Resilience      *ResilienceConfig      `yaml:"resilience,omitempty"`
+	ImageAlt        *ImageAltConfig        `yaml:"imageAlt,omitempty"`
+	A11yStatement   *A11yStatementConfig   `yaml:"a11yStatement,omitempty"`
+	OpsReadiness    *OpsReadinessConfig    `yaml:"opsReadiness,omitempty"`
+	Changelog       *ChangelogConfig       `yaml:"changelog,omitempty"`
+	DeployedVersion *DeployedVersionConfig `yaml:"deployedVersion,omitempty"`
+	Rollback        *RollbackConfig        `yaml:"rollback,omitempty"`
+	Hreflang        *HreflangConfig        `yaml:"hreflang,omitempty"`
+	StaleFlags      *StaleFlagsConfig      `yaml:"staleFlags,omitempty"`
+	CloudflareZone  *CloudflareZoneConfig  `yaml:"cloudflareZone,omitempty"`
+	AppLinks        *AppLinksConfig        `yaml:"appLinks,omitempty"`
+	Dependencies    *DependenciesConfig    `yaml:"dependencies,omitempty"`
+	Vulnerability   *VulnerabilityConfig   `yaml:"vulnerability,omitempty"`
+	OutdatedDeps    *OutdatedDepsConfig    `yaml:"outdatedDeps,omitempty"`
+	OSV             *OSVConfig             `yaml:"osv,omitempty"`
 }
 
 type EnvParityConfig struct {
 	Enabled     bool   `yaml:"enabled"`
 	EnvFile     string `yaml:"envFile"`
 	ExampleFile string `yaml:"exampleFile"`
+	// ProductionFile is compared like EnvFile and also checked for
+	// placeholder values. Default: .env.production
+	ProductionFile string `yaml:"productionFile,omitempty"`
 }
 
 type HealthEndpointConfig struct {
@@ -68,6 +326,16 @@ type SecurityConfig struct {
 type SecretsConfig struct {
 	Enabled   bool                   `yaml:"enabled"`
 	Allowlist []SecretAllowlistEntry `yaml:"allowlist,omitempty"`
+	// Patterns are the project's own secret formats, scanned for on top
+	// of the built-in ones: an internal token prefix, a vendor preflight
+	// doesn't know.
+	Patterns []SecretPatternConfig `yaml:"patterns,omitempty"`
+}
+
+type SecretPatternConfig struct {
+	// Name is what a finding is reported as ("Possible <name>").
+	Name  string `yaml:"name"`
+	Regex string `yaml:"regex"`
 }
 
 type SecretAllowlistEntry struct {
@@ -89,7 +357,20 @@ type IndexNowConfig struct {
 	Key     string `yaml:"key"`
 }
 
+// EmailAuthConfig enables email_auth. DKIMSelectors names the selectors
+// the domain's mail is signed with, checked before the common ones.
 type EmailAuthConfig struct {
+	Enabled       bool     `yaml:"enabled"`
+	DKIMSelectors []string `yaml:"dkimSelectors,omitempty"`
+}
+
+// BIMIConfig enables checking the production domain's BIMI logo setup.
+type BIMIConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// IPv6Config enables checking the production domain serves over IPv6.
+type IPv6Config struct {
 	Enabled bool `yaml:"enabled"`
 }
 
@@ -97,14 +378,283 @@ type HumansTxtConfig struct {
 	Enabled bool `yaml:"enabled"`
 }
 
+// DependenciesConfig sets the thresholds the dependencies report flags:
+// direct dependencies declared in the manifests (100 by default), packages
+// in the lockfiles (1500), and megabytes installed under node_modules,
+// vendor and .venv (500).
+type DependenciesConfig struct {
+	MaxDirect    int `yaml:"maxDirect,omitempty"`
+	MaxTotal     int `yaml:"maxTotal,omitempty"`
+	MaxInstallMB int `yaml:"maxInstallMB,omitempty"`
+}
+
+// VulnerabilityConfig tunes the dependency audit. Threshold is the lowest
+// advisory severity that fails it for npm, yarn and pnpm projects: low,
+// moderate (the default), high or critical.
+type VulnerabilityConfig struct {
+	Threshold string `yaml:"threshold,omitempty"`
+}
+
+// OutdatedDepsConfig turns on outdated_deps. MajorsBehind is how many
+// major versions behind the latest release a direct dependency can fall
+// before it's flagged (2 by default).
+type OutdatedDepsConfig struct {
+	Enabled      bool `yaml:"enabled"`
+	MajorsBehind int  `yaml:"majorsBehind,omitempty"`
+}
+
+// OSVConfig turns on osv, which sends every locked package's name and
+// version to OSV.dev.
+type OSVConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// AuditSeverities are the advisory severities, lowest first.
+var AuditSeverities = []string{"low", "moderate", "high", "critical"}
+
+// AppLinksConfig declares the apps production links open, which turns on
+// app_links: the iOS app IDs (TEAMID.bundle.id) apple-app-site-association
+// must list, and the Android packages assetlinks.json must, optionally with
+// the signing certificate fingerprints.
+type AppLinksConfig struct {
+	IOS     *IOSAppLinksConfig     `yaml:"ios,omitempty"`
+	Android *AndroidAppLinksConfig `yaml:"android,omitempty"`
+}
+
+type IOSAppLinksConfig struct {
+	AppIDs []string `yaml:"appIds"`
+}
+
+type AndroidAppLinksConfig struct {
+	Packages     []string `yaml:"packages"`
+	Fingerprints []string `yaml:"fingerprints,omitempty"`
+}
+
+// Declared reports whether any app is declared.
+func (c *AppLinksConfig) Declared() bool {
+	return c != nil && ((c.IOS != nil && len(c.IOS.AppIDs) > 0) || (c.Android != nil && len(c.Android.Packages) > 0))
+}
+
+// CloudflareZoneConfig enables cloudflare_zone, which reads the production
+// zone's settings through the Cloudflare API. The token comes from the
+// environment variable TokenEnv names (CLOUDFLARE_API_TOKEN by default) and
+// needs Zone:Read, DNS:Read and Zone WAF:Read. ZoneID skips the lookup by
+// domain.
+type CloudflareZoneConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	ZoneID   string `yaml:"zoneId,omitempty"`
+	TokenEnv string `yaml:"tokenEnv,omitempty"`
+}
+
+// StaleFlagsConfig configures stale_flags, which runs when enabled or when
+// a feature flag service is declared. Prefixes name temporary flags (tmp_,
+// temp_, killswitch_ by default), and MaxAgeMonths is how long a dated one
+// can stay (3 by default).
+type StaleFlagsConfig struct {
+	Enabled      bool     `yaml:"enabled"`
+	Prefixes     []string `yaml:"prefixes,omitempty"`
+	MaxAgeMonths int      `yaml:"maxAgeMonths,omitempty"`
+}
+
+// HreflangConfig lists the locales (en-US, fr, x-default) the production
+// homepage's hreflang links must cover.
+type HreflangConfig struct {
+	Locales []string `yaml:"locales,omitempty"`
+}
+
+// RollbackConfig enables rollback, which reads the deploy configuration
+// for a way back from a bad release.
+type RollbackConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// DeployedVersionConfig enables deployed_version. Path is the endpoint
+// reporting what's deployed (/version, /api/version or a health endpoint
+// otherwise), and Field the dotted JSON path of the version or commit in
+// its response, found by key name when unset.
+type DeployedVersionConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path,omitempty"`
+	Field   string `yaml:"field,omitempty"`
+}
+
+// ChangelogConfig enables changelog. VersionFrom is where the version
+// being launched is read: "package.json" or "git" (the latest tag).
+// Without it package.json is tried, then git. File overrides the usual
+// CHANGELOG.md names, and GitHubRepo ("owner/repo") lets a GitHub release
+// for the version stand in for a changelog entry.
+type ChangelogConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	File        string `yaml:"file,omitempty"`
+	VersionFrom string `yaml:"versionFrom,omitempty"`
+	GitHubRepo  string `yaml:"githubRepo,omitempty"`
+}
+
+// OpsReadinessConfig enables ops_readiness. Runbook and DeployDoc name the
+// files to read when they aren't where the check looks by default.
+type OpsReadinessConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Runbook   string `yaml:"runbook,omitempty"`
+	DeployDoc string `yaml:"deployDoc,omitempty"`
+}
+
+// RoutesConfig configures live probing of the framework's routes.
+// Critical paths are always requested, and any failure on one is an error.
+type RoutesConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Critical []string `yaml:"critical,omitempty"`
+	Max      int      `yaml:"max,omitempty"`
+}
+
+// DriftConfig configures comparing project files with what production
+// serves. Paths replaces the default list of static files compared.
+type DriftConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	Paths   []string `yaml:"paths,omitempty"`
+}
+
+// MirrorsConfig lists the other places the site is served from (a docs
+// mirror, regional domains, an .onion address), which are compared with
+// production. Pages are compared besides the homepage. TorProxy is the
+// SOCKS5 address (host:port) .onion mirrors are fetched through.
+type MirrorsConfig struct {
+	URLs     []string `yaml:"urls"`
+	Pages    []string `yaml:"pages,omitempty"`
+	TorProxy string   `yaml:"torProxy,omitempty"`
+}
+
+// SmokeConfig lists API endpoints the smoke check calls, with the
+// response each must give. Targets names the URLs they're called against,
+// "staging" and/or "production"; by default staging when it's set, else
+// production.
+type SmokeConfig struct {
+	Targets   []string        `yaml:"targets,omitempty"`
+	Endpoints []SmokeEndpoint `yaml:"endpoints"`
+}
+
+// SmokeEndpoint is one request and the response expected of it. JSON
+// lists dotted paths (data.user.id, items.0.name) the response body must
+// have. Header values and Body may use ${VAR} to read the environment, so
+// tokens stay out of preflight.yml.
+//
+// It can be written as a single line, "POST /api/signup -> 400", for a
+// request without a body that only needs its status checked.
+type SmokeEndpoint struct {
+	Method  string            `yaml:"method,omitempty"`
+	Path    string            `yaml:"path"`
+	Status  int               `yaml:"status,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Body    string            `yaml:"body,omitempty"`
+	JSON    []string          `yaml:"json,omitempty"`
+}
+
+// UnmarshalYAML accepts the one-line form as well as a mapping.
+func (e *SmokeEndpoint) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		type plain SmokeEndpoint
+		return value.Decode((*plain)(e))
+	}
+	request, status, hasStatus := strings.Cut(value.Value, "->")
+	fields := strings.Fields(request)
+	if len(fields) != 2 {
+		return fmt.Errorf("line %d: smoke endpoint %q: want \"METHOD /path -> status\"", value.Line, value.Value)
+	}
+	*e = SmokeEndpoint{Method: fields[0], Path: fields[1]}
+	if hasStatus {
+		n, err := strconv.Atoi(strings.TrimSpace(status))
+		if err != nil {
+			return fmt.Errorf("line %d: smoke endpoint %q: status must be a number", value.Line, value.Value)
+		}
+		e.Status = n
+	}
+	return nil
+}
+
+// String is the endpoint's request line, "GET /api/health".
+func (e SmokeEndpoint) String() string {
+	return e.Method + " " + e.Path
+}
+
+// validateSmoke rejects requests that can't be made. Method and status
+// defaults are filled in afterwards.
+func validateSmoke(s *SmokeConfig) error {
+	if s == nil {
+		return nil
+	}
+	for _, target := range s.Targets {
+		if target != "staging" && target != "production" {
+			return fmt.Errorf("checks.smoke.targets: unknown target %q (want staging or production)", target)
+		}
+	}
+	for i, e := range s.Endpoints {
+		if !strings.HasPrefix(e.Path, "/") {
+			return fmt.Errorf("checks.smoke.endpoints[%d]: path %q must start with /", i, e.Path)
+		}
+		switch strings.ToUpper(e.Method) {
+		case "", "GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS":
+		default:
+			return fmt.Errorf("checks.smoke.endpoints[%d]: unknown method %q", i, e.Method)
+		}
+		if e.Status != 0 && (e.Status < 100 || e.Status > 599) {
+			return fmt.Errorf("checks.smoke.endpoints[%d]: status %d isn't an HTTP status", i, e.Status)
+		}
+	}
+	return nil
+}
+
+// ResilienceConfig enables looking for timeouts around payment, auth and
+// email API calls.
+type ResilienceConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// A11yStatementConfig enables looking for an accessibility statement.
+type A11yStatementConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// ImageAltConfig sets the alt-text coverage, in percent, each template
+// directory must reach. Zero (the default) reports coverage without ever
+// warning.
+type ImageAltConfig struct {
+	MinCoverage int `yaml:"minCoverage"`
+}
+
+// SnoozeDateLayout is the format of Snooze values.
+const SnoozeDateLayout = "2006-01-02"
+
+// IsSnoozed reports whether id is snoozed at now. A snooze covers the whole
+// of its end date; an unparseable date never snoozes anything, so a typo
+// errs on the side of running the check.
+func (c *PreflightConfig) IsSnoozed(id string, now time.Time) bool {
+	until, ok := c.Snooze[id]
+	if !ok {
+		return false
+	}
+	end, err := time.ParseInLocation(SnoozeDateLayout, until, now.Location())
+	if err != nil {
+		return false
+	}
+	return now.Before(end.AddDate(0, 0, 1))
+}
+
 // Load reads and parses the preflight.yml config file
 func Load(rootDir string) (*PreflightConfig, error) {
-	configPath := filepath.Join(rootDir, "preflight.yml")
+	cfg, err := LoadFile(filepath.Join(rootDir, "preflight.yml"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("preflight.yml not found in %s", rootDir)
+	}
+	return cfg, err
+}
 
+// LoadFile reads and parses a config file kept outside the project it
+// describes (scan --config). A missing file is returned unwrapped, so
+// errors.Is(err, os.ErrNotExist) holds.
+func LoadFile(configPath string) (*PreflightConfig, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("preflight.yml not found in %s", rootDir)
+			return nil, err
 		}
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
@@ -114,15 +664,241 @@ func Load(rootDir string) (*PreflightConfig, error) {
 		return nil, fmt.Errorf("failed to parse preflight.yml: %w", err)
 	}
 
+	for _, stack := range cfg.Stacks {
+		if strings.TrimSpace(stack) == "" {
+			return nil, fmt.Errorf("stack: list entries can't be empty")
+		}
+	}
+	if len(cfg.Stacks) > 0 {
+		cfg.Stack = cfg.Stacks[0]
+	}
+	if err := validateRequire(cfg.Require); err != nil {
+		return nil, err
+	}
+	if err := ValidateFailOn(cfg.FailOn); err != nil {
+		return nil, fmt.Errorf("failOn: %w", err)
+	}
+	if err := validateCustomChecks(cfg.CustomChecks); err != nil {
+		return nil, err
+	}
+	for name, ids := range cfg.Profiles {
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("profiles.%s: list at least one check ID", name)
+		}
+	}
+	for id, severity := range cfg.Severities {
+		if err := ValidateSeverity(severity); err != nil {
+			return nil, fmt.Errorf("severities.%s: %w", id, err)
+		}
+	}
+	for _, regime := range cfg.Compliance {
+		if !slices.Contains(ComplianceRegimes, regime) {
+			return nil, fmt.Errorf("compliance: unknown regime %q (want %s)", regime, strings.Join(ComplianceRegimes, ", "))
+		}
+	}
+	if cfg.Monorepo != nil {
+		for _, pattern := range cfg.Monorepo.Workspaces {
+			if !doublestar.ValidatePattern(filepath.ToSlash(strings.TrimPrefix(pattern, "!"))) {
+				return nil, fmt.Errorf("monorepo.workspaces: invalid pattern %q", pattern)
+			}
+		}
+	}
+	if err := validateSmoke(cfg.Checks.Smoke); err != nil {
+		return nil, err
+	}
+	if err := validateRequiredFiles(cfg.Checks.RequiredFiles); err != nil {
+		return nil, err
+	}
+	if s := cfg.Checks.Secrets; s != nil {
+		for i, p := range s.Patterns {
+			if strings.TrimSpace(p.Name) == "" {
+				return nil, fmt.Errorf("checks.secrets.patterns[%d]: name is required", i)
+			}
+			if p.Regex == "" {
+				return nil, fmt.Errorf("checks.secrets.patterns.%s: regex is required", p.Name)
+			}
+			if _, err := regexp.Compile(p.Regex); err != nil {
+				return nil, fmt.Errorf("checks.secrets.patterns.%s: regex: %w", p.Name, err)
+			}
+		}
+	}
+	if c := cfg.Checks.Changelog; c != nil {
+		switch c.VersionFrom {
+		case "", "package.json", "git":
+		default:
+			return nil, fmt.Errorf("checks.changelog.versionFrom: unknown source %q (want package.json or git)", c.VersionFrom)
+		}
+		if c.GitHubRepo != "" && !githubRepoPattern.MatchString(c.GitHubRepo) {
+			return nil, fmt.Errorf("checks.changelog.githubRepo: want owner/repo, got %q", c.GitHubRepo)
+		}
+	}
+	if f := cfg.Checks.StaleFlags; f != nil {
+		if f.MaxAgeMonths < 0 {
+			return nil, fmt.Errorf("checks.staleFlags.maxAgeMonths: must be positive")
+		}
+		for _, p := range f.Prefixes {
+			if strings.TrimSpace(p) == "" {
+				return nil, fmt.Errorf("checks.staleFlags.prefixes: empty prefix")
+			}
+		}
+	}
+	if d := cfg.Checks.Dependencies; d != nil && (d.MaxDirect < 0 || d.MaxTotal < 0 || d.MaxInstallMB < 0) {
+		return nil, fmt.Errorf("checks.dependencies: thresholds must be positive")
+	}
+	if v := cfg.Checks.Vulnerability; v != nil && v.Threshold != "" && !slices.Contains(AuditSeverities, v.Threshold) {
+		return nil, fmt.Errorf("checks.vulnerability.threshold: unknown severity %q (want low, moderate, high or critical)", v.Threshold)
+	}
+	if o := cfg.Checks.OutdatedDeps; o != nil && o.MajorsBehind < 0 {
+		return nil, fmt.Errorf("checks.outdatedDeps.majorsBehind: must be positive")
+	}
+	if a := cfg.Checks.AppLinks; a != nil {
+		if a.IOS != nil {
+			for _, id := range a.IOS.AppIDs {
+				if !AppleAppIDPattern.MatchString(id) {
+					return nil, fmt.Errorf("checks.appLinks.ios.appIds: %q isn't TEAMID.bundle.id", id)
+				}
+			}
+		}
+		if a.Android != nil {
+			for _, fp := range a.Android.Fingerprints {
+				if !CertFingerprintPattern.MatchString(strings.ToUpper(fp)) {
+					return nil, fmt.Errorf("checks.appLinks.android.fingerprints: %q isn't a SHA-256 fingerprint (AB:CD:...)", fp)
+				}
+			}
+		}
+	}
+	if d := cfg.Checks.Drift; d != nil {
+		for _, p := range d.Paths {
+			if rel := path.Clean(strings.TrimLeft(p, "/")); rel == ".." || strings.HasPrefix(rel, "../") {
+				return nil, fmt.Errorf("checks.drift.paths: %q leaves the web root", p)
+			}
+		}
+	}
+	if m := cfg.Checks.Mirrors; m != nil {
+		for _, raw := range m.URLs {
+			if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("checks.mirrors.urls: %q isn't an http(s) URL", raw)
+			}
+		}
+	}
+	if cfg.Walk.MaxDepth < 0 {
+		return nil, fmt.Errorf("walk.maxDepth: must be positive")
+	}
+	if err := cfg.DNS.validate(); err != nil {
+		return nil, err
+	}
+
 	// Apply defaults
 	applyDefaults(&cfg)
 
 	return &cfg, nil
 }
 
+// githubRepoPattern matches an owner/repo name.
+var githubRepoPattern = regexp.MustCompile(`^[A-Za-z0-9-]+/[A-Za-z0-9._-]+$`)
+
+// AppleAppIDPattern matches an iOS app ID: the ten-character team ID and
+// the bundle ID.
+var AppleAppIDPattern = regexp.MustCompile(`^[A-Z0-9]{10}\.[A-Za-z0-9.-]+$`)
+
+// CertFingerprintPattern matches a SHA-256 certificate fingerprint as
+// assetlinks.json lists it: 32 colon-separated hex bytes.
+var CertFingerprintPattern = regexp.MustCompile(`^(?:[0-9A-F]{2}:){31}[0-9A-F]{2}$`)
+
+// ComplianceRegimes are the regulated verticals compliance: can name.
+var ComplianceRegimes = []string{"coppa", "gambling", "alcohol", "tobacco", "cannabis"}
+
+// validateRequire rejects empty groups and unknown service IDs, so a typo
+// like "sentri" can't leave a group silently unsatisfiable.
+func validateRequire(groups map[string][]string) error {
+	known := make(map[string]bool, len(AllServices))
+	for _, id := range AllServices {
+		known[id] = true
+	}
+	for name, providers := range groups {
+		if len(providers) == 0 {
+			return fmt.Errorf("require.%s: list at least one service ID", name)
+		}
+		for _, id := range providers {
+			if !known[id] {
+				return fmt.Errorf("require.%s: unknown service %q (run 'preflight checks' to list service IDs)", name, id)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateFailOn accepts the severities a failure threshold can name. An
+// empty value means the default.
+func ValidateFailOn(failOn string) error {
+	switch failOn {
+	case "", "warn", "error":
+		return nil
+	}
+	return fmt.Errorf("unknown severity %q (want warn or error)", failOn)
+}
+
+// ValidateSeverity accepts the severities a check can report with.
+func ValidateSeverity(severity string) error {
+	switch severity {
+	case "info", "warn", "error":
+		return nil
+	}
+	return fmt.Errorf("unknown severity %q (want info, warn or error)", severity)
+}
+
+// validateCustomChecks catches rules that could never run as written: a
+// missing id or file, a duplicate id, an unknown severity or a regular
+// expression that doesn't compile.
+func validateCustomChecks(rules []CustomCheckConfig) error {
+	seen := make(map[string]bool, len(rules))
+	for i, r := range rules {
+		if r.ID == "" {
+			return fmt.Errorf("customChecks[%d]: id is required", i)
+		}
+		if seen[r.ID] {
+			return fmt.Errorf("customChecks.%s: duplicate id", r.ID)
+		}
+		seen[r.ID] = true
+		if r.File == "" {
+			return fmt.Errorf("customChecks.%s: file is required", r.ID)
+		}
+		if !doublestar.ValidatePattern(filepath.ToSlash(r.File)) {
+			return fmt.Errorf("customChecks.%s: invalid file pattern %q", r.ID, r.File)
+		}
+		if err := ValidateFailOn(r.Severity); err != nil {
+			return fmt.Errorf("customChecks.%s: severity: %w", r.ID, err)
+		}
+		for field, expr := range map[string]string{"mustMatch": r.MustMatch, "mustNotMatch": r.MustNotMatch} {
+			if _, err := regexp.Compile(expr); err != nil {
+				return fmt.Errorf("customChecks.%s: %s: %w", r.ID, field, err)
+			}
+		}
+	}
+	return nil
+}
+
 func applyDefaults(cfg *PreflightConfig) {
 	if cfg.Stack == "" {
 		cfg.Stack = "unknown"
+		cfg.Stacks = StackList{cfg.Stack}
+	}
+
+	if cfg.Walk.MaxDepth == 0 {
+		cfg.Walk.MaxDepth = DefaultMaxWalkDepth
+	}
+
+	if cfg.Checks.Smoke != nil {
+		for i := range cfg.Checks.Smoke.Endpoints {
+			e := &cfg.Checks.Smoke.Endpoints[i]
+			e.Method = strings.ToUpper(e.Method)
+			if e.Method == "" {
+				e.Method = "GET"
+			}
+			if e.Status == 0 {
+				e.Status = 200
+			}
+		}
 	}
 
 	if cfg.Checks.EnvParity != nil {
@@ -132,6 +908,9 @@ func applyDefaults(cfg *PreflightConfig) {
 		if cfg.Checks.EnvParity.ExampleFile == "" {
 			cfg.Checks.EnvParity.ExampleFile = ".env.example"
 		}
+		if cfg.Checks.EnvParity.ProductionFile == "" {
+			cfg.Checks.EnvParity.ProductionFile = ".env.production"
+		}
 	}
 
 	if cfg.Checks.HealthEndpoint != nil {
@@ -0,0 +1,304 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadCustomChecks(t *testing.T) {
+	cases := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			yaml: `customChecks:
+  - id: privacy
+    title: Privacy page exists
+    file: app/privacy/page.tsx
+  - id: no_todos
+    severity: error
+    file: "app/**/*.tsx"
+    mustNotMatch: "TODO"
+`,
+		},
+		{"missing id", "customChecks:\n  - file: x\n", "id is required"},
+		{"missing file", "customChecks:\n  - id: a\n", "customChecks.a: file is required"},
+		{"duplicate id", "customChecks:\n  - id: a\n    file: x\n  - id: a\n    file: y\n", "duplicate id"},
+		{"bad severity", "customChecks:\n  - id: a\n    file: x\n    severity: fatal\n", "severity"},
+		{"bad regex", "customChecks:\n  - id: a\n    file: x\n    mustMatch: \"(\"\n", "mustMatch"},
+		{"bad glob", "customChecks:\n  - id: a\n    file: \"[\"\n", "invalid file pattern"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "preflight.yml"), []byte("projectName: x\n"+tc.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load(dir)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(cfg.CustomChecks) != 2 || cfg.CustomChecks[1].MustNotMatch != "TODO" {
+					t.Errorf("CustomChecks = %+v", cfg.CustomChecks)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("err = %v, want it to mention %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadSeverities(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "preflight.yml")
+	if err := os.WriteFile(path, []byte("projectName: x\nseverities:\n  debug_statements: error\n  favicon: info\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Severities["debug_statements"] != "error" || cfg.Severities["favicon"] != "info" {
+		t.Errorf("Severities = %v", cfg.Severities)
+	}
+
+	if err := os.WriteFile(path, []byte("projectName: x\nseverities:\n  favicon: fatal\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "severities.favicon") {
+		t.Errorf("err = %v, want it to name severities.favicon", err)
+	}
+}
+
+func TestLoadCompliance(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "preflight.yml")
+	if err := os.WriteFile(path, []byte("projectName: x\ncompliance: [gambling, coppa]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Compliance) != 2 || cfg.Compliance[0] != "gambling" {
+		t.Errorf("Compliance = %v", cfg.Compliance)
+	}
+
+	if err := os.WriteFile(path, []byte("projectName: x\ncompliance: [casino]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), `unknown regime "casino"`) {
+		t.Errorf("err = %v, want an unknown regime error", err)
+	}
+}
+
+func TestLoadStacks(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "preflight.yml")
+	if err := os.WriteFile(path, []byte("projectName: x\nstack: [rails, react]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Stack != "rails" || strings.Join(cfg.AllStacks(), ",") != "rails,react" {
+		t.Errorf("Stack = %q, AllStacks = %v", cfg.Stack, cfg.AllStacks())
+	}
+
+	if err := os.WriteFile(path, []byte("projectName: x\nstack: go\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err = Load(dir); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Stack != "go" || len(cfg.AllStacks()) != 1 {
+		t.Errorf("Stack = %q, AllStacks = %v", cfg.Stack, cfg.AllStacks())
+	}
+	if out, err := yaml.Marshal(cfg); err != nil || !strings.Contains(string(out), "stack: go\n") {
+		t.Errorf("Marshal = %q, %v; want the scalar form kept", out, err)
+	}
+
+	if err := os.WriteFile(path, []byte("projectName: x\nstack: {api: go}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil {
+		t.Error("Load accepted a mapping for stack")
+	}
+}
+
+func TestLoadDNS(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "preflight.yml")
+	for _, tc := range []struct {
+		yaml    string
+		wantErr string
+	}{
+		{"dns:\n  doh: cloudflare\n", ""},
+		{"dns:\n  doh: https://dns.example.com/dns-query\n  mode: doh\n", ""},
+		{"dns:\n  doh: quad9\n", "dns.doh: want cloudflare, google or an https URL"},
+		{"dns:\n  mode: doh\n", "dns.mode: set dns.doh"},
+		{"dns:\n  doh: google\n  mode: always\n", `unknown mode "always"`},
+	} {
+		if err := os.WriteFile(path, []byte("projectName: x\n"+tc.yaml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Load(dir)
+		if tc.wantErr == "" && err != nil {
+			t.Errorf("%q: %v", tc.yaml, err)
+		}
+		if tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Errorf("%q: err = %v, want %q", tc.yaml, err, tc.wantErr)
+		}
+	}
+}
+
+func TestLoadSmoke(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "preflight.yml")
+	yml := `projectName: x
+checks:
+  smoke:
+    endpoints:
+      - "post /api/signup -> 400"
+      - path: /api/health
+        json: [status]
+`
+	if err := os.WriteFile(path, []byte(yml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoints := cfg.Checks.Smoke.Endpoints
+	if len(endpoints) != 2 || endpoints[0].String() != "POST /api/signup" || endpoints[0].Status != 400 ||
+		endpoints[1].String() != "GET /api/health" || endpoints[1].Status != 200 {
+		t.Errorf("endpoints = %+v", endpoints)
+	}
+
+	for _, bad := range []string{`"GET api/health -> 200"`, `"GET /x -> ok"`, `"FETCH /x"`} {
+		yml := "projectName: x\nchecks:\n  smoke:\n    endpoints: [" + bad + "]\n"
+		if err := os.WriteFile(path, []byte(yml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(dir); err == nil {
+			t.Errorf("%s: want an error", bad)
+		}
+	}
+}
+
+func TestLoadRequiredFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "preflight.yml")
+	yml := `projectName: x
+checks:
+  requiredFiles:
+    - docs/runbook.md
+    - path: SECURITY.md
+      severity: error
+`
+	if err := os.WriteFile(path, []byte(yml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := cfg.Checks.RequiredFiles
+	if len(files) != 2 || files[0] != (RequiredFile{Path: "docs/runbook.md"}) || files[1] != (RequiredFile{Path: "SECURITY.md", Severity: "error"}) {
+		t.Errorf("requiredFiles = %+v", files)
+	}
+
+	for _, bad := range []string{`""`, `"../secrets.md"`, `"/etc/passwd"`, `{path: a.md, severity: fatal}`} {
+		yml := "projectName: x\nchecks:\n  requiredFiles: [" + bad + "]\n"
+		if err := os.WriteFile(path, []byte(yml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(dir); err == nil {
+			t.Errorf("%s: want an error", bad)
+		}
+	}
+}
+
+func TestLoadDriftPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "preflight.yml")
+	for paths, wantErr := range map[string]bool{
+		`["/", "/robots.txt", "docs/../llms.txt"]`: false,
+		`["/../../../etc/resolv.conf"]`:            true,
+		`["/assets/../../secret.txt"]`:             true,
+	} {
+		if err := os.WriteFile(path, []byte("projectName: x\nchecks:\n  drift:\n    paths: "+paths+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(dir); (err != nil) != wantErr {
+			t.Errorf("%s: err = %v", paths, err)
+		}
+	}
+}
+
+func TestLoadSecretPatterns(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "preflight.yml")
+	for yml, wantErr := range map[string]string{
+		"- name: Acme token\n        regex: 'acme_[a-z0-9]{32}'": "",
+		"- regex: 'acme_[a-z0-9]{32}'":                           "patterns[0]: name is required",
+		"- name: Acme token":                                     "patterns.Acme token: regex is required",
+		"- name: Acme token\n        regex: 'acme_[a-z'":         "patterns.Acme token: regex",
+	} {
+		if err := os.WriteFile(path, []byte("projectName: x\nchecks:\n  secrets:\n    enabled: true\n    patterns:\n      "+yml+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Load(dir)
+		if wantErr == "" && err != nil {
+			t.Errorf("%s: %v", yml, err)
+		}
+		if wantErr != "" && (err == nil || !strings.Contains(err.Error(), wantErr)) {
+			t.Errorf("%s: err = %v, want it to mention %q", yml, err, wantErr)
+		}
+	}
+}
+
+func TestLoadVulnerabilityThreshold(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "preflight.yml")
+	for threshold, wantErr := range map[string]bool{"high": false, "critical": false, "severe": true} {
+		if err := os.WriteFile(path, []byte("projectName: x\nchecks:\n  vulnerability:\n    threshold: "+threshold+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(dir); (err != nil) != wantErr {
+			t.Errorf("%s: err = %v", threshold, err)
+		}
+	}
+}
+
+func TestLoadChangelog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "preflight.yml")
+	for yml, wantErr := range map[string]string{
+		"versionFrom: git\n    githubRepo: acme/web": "",
+		"versionFrom: cargo":                         "unknown source",
+		"githubRepo: https://github.com/acme/web":    "want owner/repo",
+	} {
+		if err := os.WriteFile(path, []byte("projectName: x\nchecks:\n  changelog:\n    enabled: true\n    "+yml+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Load(dir)
+		if wantErr == "" && err != nil {
+			t.Errorf("%s: %v", yml, err)
+		}
+		if wantErr != "" && (err == nil || !strings.Contains(err.Error(), wantErr)) {
+			t.Errorf("%s: err = %v, want it to mention %q", yml, err, wantErr)
+		}
+	}
+}
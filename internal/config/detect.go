@@ -14,6 +14,7 @@ import (
 
 	"github.com/preflightsh/preflight/internal/fsutil"
 	"github.com/preflightsh/preflight/internal/netutil"
+	"github.com/preflightsh/preflight/internal/workspace"
 )
 
 // DetectStack determines the project stack based on files present
@@ -32,6 +33,20 @@ func DetectStack(rootDir string) string {
 		return "next"
 	}
 
+	// Nuxt, SvelteKit and Remix sit on Vue, Svelte and React, so look for
+	// them before the generic Node.js checks do.
+	if fileExists(rootDir, "nuxt.config.ts") || fileExists(rootDir, "nuxt.config.js") || fileExists(rootDir, "nuxt.config.mjs") ||
+		fileContains(rootDir, "package.json", "\"nuxt\"") {
+		return "nuxt"
+	}
+	if fileContains(rootDir, "package.json", "\"@sveltejs/kit\"") {
+		return "sveltekit"
+	}
+	if fileExists(rootDir, "remix.config.js") || fileExists(rootDir, "remix.config.mjs") ||
+		fileContains(rootDir, "package.json", "\"@remix-run/") {
+		return "remix"
+	}
+
 	// Check for Laravel
 	if fileExists(rootDir, "artisan") && fileExists(rootDir, "composer.json") {
 		return "laravel"
@@ -120,15 +135,26 @@ func DetectStack(rootDir string) string {
 
 	// === General Stacks ===
 
+	// Check for Phoenix (Elixir)
+	if fileContains(rootDir, "mix.exs", ":phoenix") {
+		return "phoenix"
+	}
+
 	// Check for Go
 	if fileExists(rootDir, "go.mod") {
 		return "go"
 	}
 
-	// Check for Python (Django/Flask)
+	// Check for Python (Django/Flask). manage.py alone marks a Django
+	// project; Flask is only recognizable by its dependency.
+	if fileExists(rootDir, "manage.py") {
+		return "django"
+	}
 	if fileExists(rootDir, "requirements.txt") || fileExists(rootDir, "pyproject.toml") || fileExists(rootDir, "Pipfile") {
-		if fileExists(rootDir, "manage.py") {
-			return "django"
+		for _, manifest := range []string{"requirements.txt", "pyproject.toml", "Pipfile"} {
+			if fileContainsFold(rootDir, manifest, "flask") {
+				return "flask"
+			}
 		}
 		return "python"
 	}
@@ -208,23 +234,22 @@ func fileContains(rootDir, relativePath, search string) bool {
 	return strings.Contains(string(content), search)
 }
 
+// fileContainsFold is fileContains ignoring case, for manifests where
+// package names are case-insensitive (Flask, flask).
+func fileContainsFold(rootDir, relativePath, search string) bool {
+	content, err := os.ReadFile(filepath.Join(rootDir, relativePath))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(content)), strings.ToLower(search))
+}
+
 // hasMonorepoFramework checks if any monorepo subdirectory contains the specified files
 func hasMonorepoFramework(rootDir string, files []string) bool {
-	monorepoRoots := []string{"apps", "packages", "services"}
-	for _, monoRoot := range monorepoRoots {
-		monoDir := filepath.Join(rootDir, monoRoot)
-		entries, err := os.ReadDir(monoDir)
-		if err != nil {
-			continue
-		}
-		for _, entry := range entries {
-			if !entry.IsDir() {
-				continue
-			}
-			for _, file := range files {
-				if fileExists(filepath.Join(monoDir, entry.Name()), file) {
-					return true
-				}
+	for _, dir := range workspace.Dirs(rootDir, nil) {
+		for _, file := range files {
+			if fileExists(dir, file) {
+				return true
 			}
 		}
 	}
@@ -278,6 +303,12 @@ var AllServices = []string{
 	"segment",
 	"hotjar",
 
+	// Feature Flags
+	"launchdarkly",
+	"flagsmith",
+	"unleash",
+	"posthog_flags",
+
 	// Auth
 	"auth0",
 	"clerk",
@@ -345,22 +376,10 @@ func DetectServices(rootDir string) map[string]bool {
 	}
 
 	// Check monorepo package.json files (apps/*, packages/*)
-	monorepoRoots := []string{"apps", "packages", "services"}
-	for _, monoRoot := range monorepoRoots {
-		monoDir := filepath.Join(rootDir, monoRoot)
-		entries, err := os.ReadDir(monoDir)
-		if err != nil {
-			continue
-		}
-		for _, entry := range entries {
-			if !entry.IsDir() {
-				continue
-			}
-			pkgPath := filepath.Join(monoDir, entry.Name(), "package.json")
-			if pkgJSON, err := os.ReadFile(pkgPath); err == nil {
-				content := strings.ToLower(string(pkgJSON))
-				detectServicesFromContent(content, services, "node")
-			}
+	for _, dir := range workspace.Dirs(rootDir, nil) {
+		if pkgJSON, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+			content := strings.ToLower(string(pkgJSON))
+			detectServicesFromContent(content, services, "node")
 		}
 	}
 
@@ -547,6 +566,20 @@ func detectServicesFromContent(content string, services map[string]bool, lang st
 		services["posthog"] = true
 	}
 
+	// Feature Flags
+	if strings.Contains(content, "launchdarkly") {
+		services["launchdarkly"] = true
+	}
+	if strings.Contains(content, "flagsmith") {
+		services["flagsmith"] = true
+	}
+	// Unleash - be specific to avoid matching the common word "unleash"
+	if strings.Contains(content, "unleash-client") || strings.Contains(content, "@unleash/") ||
+		strings.Contains(content, "unleash-proxy-client") || strings.Contains(content, "gem 'unleash'") ||
+		strings.Contains(content, "gem \"unleash\"") {
+		services["unleash"] = true
+	}
+
 	// Auth
 	if strings.Contains(content, "auth0") || strings.Contains(content, "@auth0/") {
 		services["auth0"] = true
@@ -736,6 +769,11 @@ func detectServicesFromEnv(rootDir string, services map[string]bool) map[string]
 		"segment":          {"SEGMENT_"},
 		"hotjar":           {"HOTJAR_"},
 
+		// Feature Flags
+		"launchdarkly": {"LAUNCHDARKLY_", "LD_SDK_KEY", "LD_CLIENT_SIDE_ID", "NEXT_PUBLIC_LD_", "NEXT_PUBLIC_LAUNCHDARKLY"},
+		"flagsmith":    {"FLAGSMITH_", "NEXT_PUBLIC_FLAGSMITH"},
+		"unleash":      {"UNLEASH_", "NEXT_PUBLIC_UNLEASH"},
+
 		// Auth
 		"auth0":    {"AUTH0_"},
 		"clerk":    {"CLERK_", "NEXT_PUBLIC_CLERK"},
@@ -794,21 +832,9 @@ func detectServicesFromEnv(rootDir string, services map[string]bool) map[string]
 	}
 
 	// Check env files in monorepo subdirectories
-	monorepoRoots := []string{"apps", "packages", "services"}
-	for _, monoRoot := range monorepoRoots {
-		monoDir := filepath.Join(rootDir, monoRoot)
-		entries, err := os.ReadDir(monoDir)
-		if err != nil {
-			continue
-		}
-		for _, entry := range entries {
-			if !entry.IsDir() {
-				continue
-			}
-			for _, envFile := range envFiles {
-				path := filepath.Join(monoDir, entry.Name(), envFile)
-				scanEnvFile(path, envPatterns, services)
-			}
+	for _, dir := range workspace.Dirs(rootDir, nil) {
+		for _, envFile := range envFiles {
+			scanEnvFile(filepath.Join(dir, envFile), envPatterns, services)
 		}
 	}
 
@@ -853,6 +879,12 @@ var analyticsServicePatterns = map[string]*regexp.Regexp{
 	"segment":          regexp.MustCompile(`(?i)cdn\.segment\.com|analytics\.load\(`),
 	"amplitude":        regexp.MustCompile(`(?i)cdn\.amplitude\.com|amplitude\.getInstance`),
 
+	// Feature flags - SDK packages and initialization
+	"launchdarkly":  regexp.MustCompile(`(?i)@launchdarkly/|launchdarkly-[a-z-]+-sdk|LDClient\.initialize|withLDProvider|LaunchDarkly::LDClient`),
+	"flagsmith":     regexp.MustCompile(`(?i)flagsmith\.init\(|new Flagsmith\(|from\s+flagsmith\s+import|FlagsmithProvider|edge\.api\.flagsmith\.com`),
+	"unleash":       regexp.MustCompile(`(?i)@unleash/|unleash-client|unleash-proxy-client|new UnleashClient\(|Unleash::Client`),
+	"posthog_flags": regexp.MustCompile(`posthog\.(?:isFeatureEnabled|getFeatureFlag|onFeatureFlags)\(|useFeatureFlagEnabled|useFeatureFlagVariantKey|useFeatureFlagPayload`),
+
 	// Communication - require specific URLs or SDK
 	"intercom": regexp.MustCompile(`(?i)widget\.intercom\.io|Intercom\(['"]|intercom-client`),
 	"crisp":    regexp.MustCompile(`(?i)client\.crisp\.chat|CRISP_WEBSITE_ID`),
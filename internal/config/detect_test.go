@@ -0,0 +1,36 @@
+package config
+
+import "testing"
+
+func TestDetectStack(t *testing.T) {
+	cases := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"rails", map[string]string{"Gemfile": "gem 'rails'", "config/routes.rb": ""}, "rails"},
+		{"nuxt config", map[string]string{"nuxt.config.ts": "", "package.json": `{"dependencies": {"vue": "3"}}`}, "nuxt"},
+		{"nuxt dependency", map[string]string{"package.json": `{"dependencies": {"nuxt": "3", "vue": "3"}}`}, "nuxt"},
+		{"sveltekit", map[string]string{"package.json": `{"devDependencies": {"@sveltejs/kit": "2", "svelte": "4"}}`, "vite.config.ts": ""}, "sveltekit"},
+		{"remix on vite", map[string]string{"package.json": `{"dependencies": {"@remix-run/react": "2", "react": "18"}}`, "vite.config.ts": ""}, "remix"},
+		{"plain svelte", map[string]string{"package.json": `{"dependencies": {"svelte": "4"}}`, "src/main.js": ""}, "svelte"},
+		{"astro", map[string]string{"astro.config.mjs": "", "package.json": `{"dependencies": {"astro": "4"}}`}, "astro"},
+		{"hugo", map[string]string{"hugo.toml": ""}, "hugo"},
+		{"jekyll", map[string]string{"_config.yml": "", "_layouts/default.html": ""}, "jekyll"},
+		{"django without requirements", map[string]string{"manage.py": ""}, "django"},
+		{"flask", map[string]string{"requirements.txt": "Flask==3.0\ngunicorn\n", "app.py": ""}, "flask"},
+		{"flask in pyproject", map[string]string{"pyproject.toml": "dependencies = [\"flask>=3\"]\n"}, "flask"},
+		{"python", map[string]string{"requirements.txt": "requests\n"}, "python"},
+		{"phoenix", map[string]string{"mix.exs": "{:phoenix, \"~> 1.7\"},\n", "assets/package.json": "{}"}, "phoenix"},
+		{"wordpress", map[string]string{"wp-config.php": ""}, "wordpress"},
+		{"go", map[string]string{"go.mod": "module x"}, "go"},
+		{"rust", map[string]string{"Cargo.toml": ""}, "rust"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DetectStack(writeProject(t, tc.files)); got != tc.want {
+				t.Errorf("DetectStack = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Package gitignore matches project paths against .gitignore rules, so
+// file scans can leave out generated artifacts the project already tells
+// git to ignore without needing git installed or the project to be a
+// repository.
+//
+// It covers the pattern syntax projects actually use: comments, negation,
+// directory-only rules, rules anchored to the ignore file's directory, and
+// *, ? and ** wildcards. Files inside an ignored directory are ignored
+// too; that inheritance is the caller's job as it walks the tree, since a
+// Matcher only ever judges one path.
+package gitignore
+
+import (
+	"path"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// rule is one pattern line from an ignore file.
+type rule struct {
+	base    string // directory of the ignore file, "" for the project root
+	pattern string // doublestar glob, relative to base
+	negate  bool
+	dirOnly bool
+}
+
+// Matcher holds the rules of every ignore file added to it, in the order
+// they were added. Add parent directories' files before their children's
+// so deeper rules take precedence, as they do in git.
+type Matcher struct {
+	rules []rule
+}
+
+// Add parses the content of the ignore file in dir, a project-relative
+// directory with forward slashes ("" or "." for the root).
+func (m *Matcher) Add(dir string, content []byte) {
+	if dir == "." {
+		dir = ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if r, ok := parseLine(dir, line); ok {
+			m.rules = append(m.rules, r)
+		}
+	}
+}
+
+// Match reports whether the project-relative path (forward slashes) is
+// ignored. The last rule that matches decides, so a later "!keep.log"
+// re-includes what an earlier "*.log" excluded.
+func (m *Matcher) Match(p string, isDir bool) bool {
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		rel := p
+		if r.base != "" {
+			if !strings.HasPrefix(p, r.base+"/") {
+				continue
+			}
+			rel = p[len(r.base)+1:]
+		}
+		if ok, _ := doublestar.Match(r.pattern, rel); ok {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+func parseLine(base, line string) (rule, bool) {
+	line = strings.TrimSuffix(line, "\r")
+	line = trimTrailingSpaces(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false
+	}
+
+	r := rule{base: base}
+	if strings.HasPrefix(line, "!") {
+		r.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule{}, false
+	}
+
+	// A slash anywhere but the end anchors the pattern to the ignore
+	// file's directory; otherwise it matches a name at any depth.
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	// Braces are literal in gitignore but alternation to doublestar.
+	line = strings.NewReplacer("{", `\{`, "}", `\}`).Replace(line)
+	if !anchored && !strings.HasPrefix(line, "**/") {
+		line = "**/" + line
+	}
+	if !doublestar.ValidatePattern(line) {
+		return rule{}, false
+	}
+	r.pattern = path.Clean(line)
+	return r, true
+}
+
+// trimTrailingSpaces drops trailing spaces unless the last one is escaped
+// with a backslash, as git does.
+func trimTrailingSpaces(line string) string {
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	return line
+}
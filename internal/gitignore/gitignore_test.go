@@ -0,0 +1,62 @@
+package gitignore
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	var m Matcher
+	m.Add("", []byte(`# build output
+*.log
+!keep.log
+/dist
+generated/
+docs/*.html
+**/cache/**
+trailing.txt
+literal{braces}.js
+`))
+	m.Add("packages/web", []byte("out\n/local.js\n"))
+
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"debug.log", false, true},
+		{"logs/server.log", false, true},
+		{"keep.log", false, false},
+		{"dist", true, true},
+		{"src/dist", true, false}, // anchored to the root
+		{"generated", true, true},
+		{"src/generated", true, true},
+		{"generated", false, false}, // directory-only rule
+		{"docs/index.html", false, true},
+		{"docs/api/index.html", false, false},
+		{"a/cache/b/c.js", false, true},
+		{"trailing.txt", false, true},
+		{"literal{braces}.js", false, true},
+		{"literalbraces.js", false, false},
+		{"packages/web/out", true, true},
+		{"packages/web/src/out", true, true},
+		{"packages/web/local.js", false, true},
+		{"packages/web/src/local.js", false, false},
+		{"packages/api/out", true, false}, // nested rules stay in their directory
+		{"src/app.js", false, false},
+	}
+	for _, tc := range cases {
+		if got := m.Match(tc.path, tc.isDir); got != tc.want {
+			t.Errorf("Match(%q, dir=%v) = %v, want %v", tc.path, tc.isDir, got, tc.want)
+		}
+	}
+}
+
+func TestDeeperRulesWin(t *testing.T) {
+	var m Matcher
+	m.Add("", []byte("*.gen.ts\n"))
+	m.Add("src", []byte("!api.gen.ts\n"))
+	if !m.Match("lib/types.gen.ts", false) {
+		t.Error("root rule should ignore lib/types.gen.ts")
+	}
+	if m.Match("src/api.gen.ts", false) {
+		t.Error("src/.gitignore should re-include src/api.gen.ts")
+	}
+}
@@ -144,7 +144,7 @@ func safeDialer(timeout time.Duration, exempt exemptAddrs) func(ctx context.Cont
 		if err != nil {
 			return nil, err
 		}
-		ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		ips, err := LookupIPAddr(ctx, host)
 		if err != nil {
 			return nil, err
 		}
@@ -227,13 +227,13 @@ func (e exemptAddrs) checkRedirect(req *http.Request, via []*http.Request) error
 		}
 		return nil
 	}
-	ips, err := net.LookupIP(host)
+	ips, err := LookupIPAddr(req.Context(), host)
 	if err != nil {
 		return err
 	}
 	for _, ip := range ips {
-		if IsPrivateIP(ip) {
-			return fmt.Errorf("%w: %s resolves to %s", ErrPrivateAddress, host, ip)
+		if IsPrivateIP(ip.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrPrivateAddress, host, ip.IP)
 		}
 	}
 	return nil
@@ -266,7 +266,7 @@ func SafeTLSDial(network, addr string, cfg *tls.Config, timeout time.Duration) (
 	deadline := time.Now().Add(timeout)
 	ctx, cancel := context.WithDeadline(context.Background(), deadline)
 	defer cancel()
-	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	ips, err := LookupIPAddr(ctx, host)
 	if err != nil {
 		return nil, err
 	}
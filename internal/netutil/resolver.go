@@ -0,0 +1,417 @@
+package netutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resolver answers the DNS queries preflight makes: TXT, MX and PTR
+// records for the email checks, and addresses for every dial the safe
+// clients and SafeTLSDial make. *net.Resolver satisfies it.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+}
+
+// DoHProviders are the DNS-over-HTTPS resolvers preflight.yml can name.
+// The endpoints are IP literals, so reaching them needs no DNS of its own:
+// the point is to work where port 53 is blocked.
+var DoHProviders = map[string]string{
+	"cloudflare": "https://1.1.1.1/dns-query",
+	"google":     "https://8.8.8.8/resolve",
+}
+
+// Resolver modes: try the system resolver and fall back to DoH when it
+// fails, or skip it and use DoH only.
+const (
+	ResolverFallback = "fallback"
+	ResolverDoH      = "doh"
+)
+
+// resolverAttemptTimeout bounds a lookup against one resolver when another
+// is waiting behind it, so a resolver that never answers doesn't use up
+// the caller's whole deadline.
+const resolverAttemptTimeout = 5 * time.Second
+
+// fallbackDNSServer is the public resolver the system resolver's TXT
+// lookups fall back to when no DoH resolver is configured.
+const fallbackDNSServer = "1.1.1.1:53"
+
+var resolver = struct {
+	sync.RWMutex
+	Resolver
+}{Resolver: systemResolver{}}
+
+// ConfigureResolver sets the resolver for the scan about to run from
+// preflight.yml's dns section: doh names a provider in DoHProviders or an
+// https URL serving the DoH JSON API, and mode is ResolverFallback (the
+// default) or ResolverDoH. An empty doh restores the system resolver. It
+// applies process-wide; scans run one at a time.
+func ConfigureResolver(doh, mode string) error {
+	var r Resolver = systemResolver{}
+	if doh != "" {
+		endpoint := doh
+		if known, ok := DoHProviders[doh]; ok {
+			endpoint = known
+		}
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("DoH resolver %q is neither cloudflare, google nor an https URL", doh)
+		}
+		dohResolver := &DoHResolver{Endpoint: endpoint}
+		switch mode {
+		case "", ResolverFallback:
+			r = FallbackResolver{Primary: net.DefaultResolver, Secondary: dohResolver}
+		case ResolverDoH:
+			r = dohResolver
+		default:
+			return fmt.Errorf("unknown resolver mode %q (want %s or %s)", mode, ResolverFallback, ResolverDoH)
+		}
+	}
+	SetResolver(r)
+	return nil
+}
+
+// SetResolver makes r the resolver every lookup goes through, process-wide.
+func SetResolver(r Resolver) {
+	resolver.Lock()
+	defer resolver.Unlock()
+	resolver.Resolver = r
+}
+
+func currentResolver() Resolver {
+	resolver.RLock()
+	defer resolver.RUnlock()
+	return resolver.Resolver
+}
+
+// LookupIPAddr resolves host with the configured resolver. A literal IP is
+// returned as is.
+func LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IPAddr{{IP: ip}}, nil
+	}
+	return currentResolver().LookupIPAddr(ctx, host)
+}
+
+// LookupTXT returns the TXT records of name from the configured resolver.
+// A domain or record that doesn't exist is a *net.DNSError with IsNotFound
+// set, whichever resolver answered.
+func LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return currentResolver().LookupTXT(ctx, name)
+}
+
+// LookupMX returns the MX records of name from the configured resolver,
+// sorted by preference.
+func LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	return currentResolver().LookupMX(ctx, name)
+}
+
+// LookupAddr returns the names the configured resolver's PTR records give
+// for addr.
+func LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	return currentResolver().LookupAddr(ctx, addr)
+}
+
+// isNotFound reports whether err says the name or record doesn't exist,
+// an answer a second resolver wouldn't change.
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// systemResolver is the resolver used without a dns section: the system
+// one, with TXT lookups retried against a public resolver so a flaky
+// local resolver doesn't produce false email_auth warnings.
+type systemResolver struct{}
+
+func (systemResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return net.DefaultResolver.LookupIPAddr(ctx, host)
+}
+
+func (systemResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	return net.DefaultResolver.LookupMX(ctx, name)
+}
+
+func (systemResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	return net.DefaultResolver.LookupAddr(ctx, addr)
+}
+
+func (systemResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	public := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{Timeout: resolverAttemptTimeout}
+			return d.DialContext(ctx, network, fallbackDNSServer)
+		},
+	}
+	return FallbackResolver{Primary: net.DefaultResolver, Secondary: public}.LookupTXT(ctx, name)
+}
+
+// FallbackResolver asks Primary, then Secondary when Primary fails for any
+// reason other than the name not existing (a timeout, a refused or blocked
+// port 53, a server error).
+type FallbackResolver struct {
+	Primary   Resolver
+	Secondary Resolver
+}
+
+func (r FallbackResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, resolverAttemptTimeout)
+	ips, err := r.Primary.LookupIPAddr(attemptCtx, host)
+	cancel()
+	if err == nil || isNotFound(err) || ctx.Err() != nil {
+		return ips, err
+	}
+	return r.Secondary.LookupIPAddr(ctx, host)
+}
+
+func (r FallbackResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, resolverAttemptTimeout)
+	records, err := r.Primary.LookupTXT(attemptCtx, name)
+	cancel()
+	if err == nil {
+		return records, nil
+	}
+	// Return nil with the error so callers can't consume a partial slice.
+	if isNotFound(err) || ctx.Err() != nil {
+		return nil, err
+	}
+	return r.Secondary.LookupTXT(ctx, name)
+}
+
+func (r FallbackResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, resolverAttemptTimeout)
+	records, err := r.Primary.LookupMX(attemptCtx, name)
+	cancel()
+	if err == nil || isNotFound(err) || ctx.Err() != nil {
+		return records, err
+	}
+	return r.Secondary.LookupMX(ctx, name)
+}
+
+func (r FallbackResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, resolverAttemptTimeout)
+	names, err := r.Primary.LookupAddr(attemptCtx, addr)
+	cancel()
+	if err == nil || isNotFound(err) || ctx.Err() != nil {
+		return names, err
+	}
+	return r.Secondary.LookupAddr(ctx, addr)
+}
+
+// DoH record types and response codes (RFC 1035).
+const (
+	dnsTypeA    = 1
+	dnsTypePTR  = 12
+	dnsTypeMX   = 15
+	dnsTypeTXT  = 16
+	dnsTypeAAAA = 28
+
+	dnsRcodeServFail = 2
+	dnsRcodeNXDomain = 3
+)
+
+// DoHResolver resolves over DNS-over-HTTPS with the JSON API Cloudflare
+// and Google both serve (GET ?name=&type= with Accept:
+// application/dns-json). Client defaults to a plain client with a 5s
+// timeout; the endpoint comes from preflight.yml, not scanned content.
+type DoHResolver struct {
+	Endpoint string
+	Client   *http.Client
+}
+
+// dohResponse is the part of a DoH JSON answer preflight reads.
+type dohResponse struct {
+	Status int `json:"Status"`
+	Answer []struct {
+		Type int    `json:"type"`
+		Data string `json:"data"`
+	} `json:"Answer"`
+}
+
+func (r *DoHResolver) query(ctx context.Context, name, qtype string, want int) ([]string, error) {
+	u, err := url.Parse(r.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("name", name)
+	q.Set("type", qtype)
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/dns-json")
+	req.Header.Set("User-Agent", "Preflight/1.0")
+
+	client := r.Client
+	if client == nil {
+		client = &http.Client{Timeout: resolverAttemptTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &net.DNSError{Err: err.Error(), Name: name, Server: r.Endpoint, IsTimeout: ctx.Err() != nil}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &net.DNSError{Err: fmt.Sprintf("DoH server returned HTTP %d", resp.StatusCode), Name: name, Server: r.Endpoint}
+	}
+	var answer dohResponse
+	if err := json.NewDecoder(LimitBody(resp.Body, 1<<20)).Decode(&answer); err != nil {
+		return nil, &net.DNSError{Err: "malformed DoH response: " + err.Error(), Name: name, Server: r.Endpoint}
+	}
+	switch answer.Status {
+	case 0:
+	case dnsRcodeNXDomain:
+		return nil, &net.DNSError{Err: "no such host", Name: name, Server: r.Endpoint, IsNotFound: true}
+	default:
+		return nil, &net.DNSError{
+			Err:         fmt.Sprintf("DNS server returned rcode %d", answer.Status),
+			Name:        name,
+			Server:      r.Endpoint,
+			IsTemporary: answer.Status == dnsRcodeServFail,
+		}
+	}
+	var data []string
+	for _, rr := range answer.Answer {
+		// CNAMEs along the way come back in Answer too.
+		if rr.Type == want {
+			data = append(data, rr.Data)
+		}
+	}
+	return data, nil
+}
+
+func (r *DoHResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	var ips []net.IPAddr
+	for _, qtype := range []struct {
+		name string
+		code int
+	}{{"A", dnsTypeA}, {"AAAA", dnsTypeAAAA}} {
+		data, err := r.query(ctx, host, qtype.name, qtype.code)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range data {
+			if ip := net.ParseIP(d); ip != nil {
+				ips = append(ips, net.IPAddr{IP: ip})
+			}
+		}
+	}
+	if len(ips) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, Server: r.Endpoint, IsNotFound: true}
+	}
+	return ips, nil
+}
+
+func (r *DoHResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	data, err := r.query(ctx, name, "TXT", dnsTypeTXT)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: name, Server: r.Endpoint, IsNotFound: true}
+	}
+	records := make([]string, len(data))
+	for i, d := range data {
+		records[i] = joinTXTStrings(d)
+	}
+	return records, nil
+}
+
+func (r *DoHResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	data, err := r.query(ctx, name, "MX", dnsTypeMX)
+	if err != nil {
+		return nil, err
+	}
+	var records []*net.MX
+	for _, d := range data {
+		// "10 mx1.example.com."
+		pref, host, ok := strings.Cut(strings.TrimSpace(d), " ")
+		n, convErr := strconv.ParseUint(pref, 10, 16)
+		if !ok || convErr != nil {
+			continue
+		}
+		records = append(records, &net.MX{Host: strings.TrimSpace(host), Pref: uint16(n)})
+	}
+	if len(records) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: name, Server: r.Endpoint, IsNotFound: true}
+	}
+	slices.SortStableFunc(records, func(a, b *net.MX) int { return int(a.Pref) - int(b.Pref) })
+	return records, nil
+}
+
+func (r *DoHResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil, &net.DNSError{Err: "unrecognized address", Name: addr}
+	}
+	data, err := r.query(ctx, reverseName(ip), "PTR", dnsTypePTR)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: addr, Server: r.Endpoint, IsNotFound: true}
+	}
+	return data, nil
+}
+
+// reverseName is the in-addr.arpa or ip6.arpa name whose PTR records
+// name ip.
+func reverseName(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa.", ip4[3], ip4[2], ip4[1], ip4[0])
+	}
+	const hexDigits = "0123456789abcdef"
+	var b strings.Builder
+	ip16 := ip.To16()
+	for i := len(ip16) - 1; i >= 0; i-- {
+		b.WriteByte(hexDigits[ip16[i]&0xf])
+		b.WriteByte('.')
+		b.WriteByte(hexDigits[ip16[i]>>4])
+		b.WriteByte('.')
+	}
+	b.WriteString("ip6.arpa.")
+	return b.String()
+}
+
+// joinTXTStrings turns a TXT record's presentation form, one or more
+// quoted strings ("v=spf1 include:a" " ~all"), into the single string
+// net.LookupTXT returns. Google sends a lone string unquoted, which is
+// returned as is.
+func joinTXTStrings(data string) string {
+	data = strings.TrimSpace(data)
+	if !strings.HasPrefix(data, `"`) {
+		return data
+	}
+	var b strings.Builder
+	quoted, escaped := false, false
+	for _, ch := range data {
+		switch {
+		case escaped:
+			b.WriteRune(ch)
+			escaped = false
+		case quoted && ch == '\\':
+			escaped = true
+		case ch == '"':
+			quoted = !quoted
+		case quoted:
+			b.WriteRune(ch)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,129 @@
+package netutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// dohServer answers DoH JSON queries from records, keyed by "name type".
+// Names it has no record for are NXDOMAIN.
+func dohServer(t *testing.T, records map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/dns-json" {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		name, qtype := r.URL.Query().Get("name"), r.URL.Query().Get("type")
+		w.Header().Set("Content-Type", "application/dns-json")
+		if name == "servfail.example.com" {
+			fmt.Fprint(w, `{"Status": 2}`)
+			return
+		}
+		body, ok := records[name+" "+qtype]
+		if !ok {
+			fmt.Fprint(w, `{"Status": 3}`)
+			return
+		}
+		fmt.Fprintf(w, `{"Status": 0, "Answer": [{"name": %q, "type": 5, "data": "alias.example.com."}, %s]}`, name, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDoHResolver(t *testing.T) {
+	srv := dohServer(t, map[string]string{
+		"example.com TXT":                 `{"type": 16, "data": "\"v=spf1 include:_spf.example.com\" \" ~all\""}, {"type": 16, "data": "google-site-verification=abc"}`,
+		"example.com A":                   `{"type": 1, "data": "93.184.215.14"}`,
+		"example.com AAAA":                `{"type": 28, "data": "2606:2800:21f:cb07::1"}`,
+		"example.com MX":                  `{"type": 15, "data": "20 mx2.example.com."}, {"type": 15, "data": "10 mx1.example.com."}`,
+		"14.215.184.93.in-addr.arpa. PTR": `{"type": 12, "data": "mail.example.com."}`,
+	})
+	r := &DoHResolver{Endpoint: srv.URL + "/dns-query", Client: srv.Client()}
+	ctx := context.Background()
+
+	txt, err := r.LookupTXT(ctx, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"v=spf1 include:_spf.example.com ~all", "google-site-verification=abc"}; !reflect.DeepEqual(txt, want) {
+		t.Errorf("TXT = %q, want %q", txt, want)
+	}
+
+	ips, err := r.LookupIPAddr(ctx, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ips) != 2 || ips[0].IP.String() != "93.184.215.14" || ips[1].IP.String() != "2606:2800:21f:cb07::1" {
+		t.Errorf("IPs = %v", ips)
+	}
+
+	mx, err := r.LookupMX(ctx, "example.com")
+	if err != nil || len(mx) != 2 || mx[0].Host != "mx1.example.com." || mx[0].Pref != 10 {
+		t.Errorf("MX = %v, %v; want mx1 first", mx, err)
+	}
+	if names, err := r.LookupAddr(ctx, "93.184.215.14"); err != nil || len(names) != 1 || names[0] != "mail.example.com." {
+		t.Errorf("PTR = %v, %v", names, err)
+	}
+
+	var dnsErr *net.DNSError
+	if _, err := r.LookupTXT(ctx, "_dmarc.example.com"); !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Errorf("NXDOMAIN err = %v, want IsNotFound", err)
+	}
+	if _, err := r.LookupTXT(ctx, "servfail.example.com"); !errors.As(err, &dnsErr) || dnsErr.IsNotFound || !dnsErr.IsTemporary {
+		t.Errorf("SERVFAIL err = %v, want a temporary error", err)
+	}
+}
+
+// stubResolver fails every lookup with err.
+type stubResolver struct{ err error }
+
+func (s stubResolver) LookupIPAddr(context.Context, string) ([]net.IPAddr, error) { return nil, s.err }
+func (s stubResolver) LookupTXT(context.Context, string) ([]string, error)        { return nil, s.err }
+func (s stubResolver) LookupMX(context.Context, string) ([]*net.MX, error)        { return nil, s.err }
+func (s stubResolver) LookupAddr(context.Context, string) ([]string, error)       { return nil, s.err }
+
+func TestFallbackResolver(t *testing.T) {
+	srv := dohServer(t, map[string]string{"example.com TXT": `{"type": 16, "data": "v=spf1 -all"}`})
+	doh := &DoHResolver{Endpoint: srv.URL, Client: srv.Client()}
+	ctx := context.Background()
+
+	blocked := FallbackResolver{Primary: stubResolver{&net.DNSError{Err: "i/o timeout", IsTimeout: true}}, Secondary: doh}
+	if txt, err := blocked.LookupTXT(ctx, "example.com"); err != nil || len(txt) != 1 || txt[0] != "v=spf1 -all" {
+		t.Errorf("TXT = %q, %v; want the DoH answer", txt, err)
+	}
+
+	// A name the system resolver says doesn't exist isn't asked again.
+	missing := FallbackResolver{Primary: stubResolver{&net.DNSError{Err: "no such host", IsNotFound: true}}, Secondary: doh}
+	if _, err := missing.LookupTXT(ctx, "example.com"); err == nil {
+		t.Error("want the primary's not-found error")
+	}
+}
+
+func TestConfigureResolver(t *testing.T) {
+	defer ConfigureResolver("", "")
+	if err := ConfigureResolver("cloudflare", ResolverDoH); err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := currentResolver().(*DoHResolver); !ok || r.Endpoint != DoHProviders["cloudflare"] {
+		t.Errorf("resolver = %#v, want the Cloudflare DoH resolver", currentResolver())
+	}
+	if err := ConfigureResolver("google", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := currentResolver().(FallbackResolver); !ok {
+		t.Errorf("resolver = %#v, want a FallbackResolver", currentResolver())
+	}
+	if err := ConfigureResolver("http://dns.example.com", ""); err == nil {
+		t.Error("want an error for a plain-http DoH URL")
+	}
+	// Literal IPs never reach the resolver.
+	if ips, err := LookupIPAddr(context.Background(), "192.0.2.1"); err != nil || len(ips) != 1 {
+		t.Errorf("LookupIPAddr(literal) = %v, %v", ips, err)
+	}
+}
@@ -0,0 +1,104 @@
+package output
+
+import (
+	"fmt"
+	"html"
+	"math"
+
+	"github.com/preflightsh/preflight/internal/checks"
+)
+
+// ReadinessScore rates a scan from 0 to 100: passing checks count in
+// full, warnings for half and failures for nothing. An empty scan scores
+// 100, since nothing stands in the way of launch.
+func ReadinessScore(results []checks.CheckResult) int {
+	if len(results) == 0 {
+		return 100
+	}
+	s := CalculateSummary(results)
+	return int(math.Round(100 * (float64(s.OK) + float64(s.Warn)/2) / float64(len(results))))
+}
+
+// Badge is a readiness score badge. Its JSON form is the shields.io
+// endpoint schema (https://shields.io/badges/endpoint-badge), so a served
+// badge can be restyled through shields as well as embedded directly.
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// badgeColors maps the shields color names a badge uses to the hex
+// shields renders them with, for the SVG written locally.
+var badgeColors = map[string]string{
+	"red":         "#e05d44",
+	"yellow":      "#dfb317",
+	"brightgreen": "#4c1",
+}
+
+// NewBadge builds the badge for a scan. The color follows the verdict the
+// other outputs print rather than the score: any failure is red, warnings
+// only are yellow, and a clean scan is green.
+func NewBadge(results []checks.CheckResult) Badge {
+	summary := CalculateSummary(results)
+	color := "brightgreen"
+	switch {
+	case summary.Fail > 0:
+		color = "red"
+	case summary.Warn > 0:
+		color = "yellow"
+	}
+	return Badge{
+		SchemaVersion: 1,
+		Label:         "preflight",
+		Message:       fmt.Sprintf("%d%%", ReadinessScore(results)),
+		Color:         color,
+	}
+}
+
+// SVG renders the badge in the flat shields style. Text widths are
+// estimated from an average glyph width for 11px Verdana, which is close
+// enough for the short label and score a badge carries.
+func (b Badge) SVG() []byte {
+	labelWidth := badgeTextWidth(b.Label) + 10
+	messageWidth := badgeTextWidth(b.Message) + 10
+	width := labelWidth + messageWidth
+	label, message := html.EscapeString(b.Label), html.EscapeString(b.Message)
+	fill, ok := badgeColors[b.Color]
+	if !ok {
+		fill = "#9f9f9f"
+	}
+	return fmt.Appendf(nil, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">
+  <title>%s: %s</title>
+  <linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+  <clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>
+  <g clip-path="url(#r)">
+    <rect width="%d" height="20" fill="#555"/>
+    <rect x="%d" width="%d" height="20" fill="%s"/>
+    <rect width="%d" height="20" fill="url(#s)"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+    <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>
+    <text x="%d" y="14">%s</text>
+    <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>
+    <text x="%d" y="14">%s</text>
+  </g>
+</svg>
+`,
+		width, label, message,
+		label, message,
+		width,
+		labelWidth,
+		labelWidth, messageWidth, fill,
+		width,
+		labelWidth/2, label,
+		labelWidth/2, label,
+		labelWidth+messageWidth/2, message,
+		labelWidth+messageWidth/2, message,
+	)
+}
+
+func badgeTextWidth(s string) int {
+	return int(math.Ceil(float64(len([]rune(s))) * 7))
+}
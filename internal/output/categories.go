@@ -0,0 +1,222 @@
+package output
+
+// The display categories below group results in the human output and the
+// HTML report. New checks get an entry in categoryMap (or, for service
+// integrations, serviceCheckIDs and serviceCategoryMap).
+
+// Category icons
+var categoryIcons = map[string]string{
+	"ENV":       "📋",
+	"HEALTH":    "💓",
+	"PAYMENTS":  "💳",
+	"ERRORS":    "🐛",
+	"ANALYTICS": "📊",
+	"INFRA":     "🔧",
+	"JOBS":      "⚡",
+	"SEO":       "🔍",
+	"SECURITY":  "🔒",
+	"SECRETS":   "🔑",
+	"AI":        "🤖",
+	"FLAGS":     "🚩",
+	"EMAIL":     "📧",
+	"AUTH":      "🔐",
+	"STORAGE":   "📦",
+	"SEARCH":    "🔎",
+	"CHAT":      "💬",
+	"NOTIFY":    "🔔",
+	"SOCIAL":    "📱",
+	"ICONS":     "🎨",
+	"FILES":     "📄",
+	"CUSTOM":    "🧩",
+	"SSL":       "🔐",
+	"LICENSE":   "📜",
+	"DEPS":      "📦",
+	"INDEXNOW":  "🔗",
+	"MOBILE":    "📱",
+	"LANG":      "🌐",
+	"PAGES":     "📃",
+	"DEBUG":     "🐞",
+	"PERF":      "⚡",
+	"LEGAL":     "⚖️ ",
+	"REQUIRED":  "📌",
+	"ROUTES":    "🧭",
+	"DRIFT":     "🔀",
+	"A11Y":      "♿",
+	"OPS":       "🚨",
+}
+
+// Map check IDs to display categories
+var categoryMap = map[string]string{
+	"envParity":          "ENV",
+	"healthEndpoint":     "HEALTH",
+	"smoke":              "HEALTH",
+	"routes":             "ROUTES",
+	"auth_routes":        "ROUTES",
+	"drift":              "DRIFT",
+	"mirrors":            "DRIFT",
+	"ops_readiness":      "OPS",
+	"changelog":          "FILES",
+	"deployed_version":   "HEALTH",
+	"rollback":           "OPS",
+	"stale_flags":        "FLAGS",
+	"seoMeta":            "SEO",
+	"ogTwitter":          "SOCIAL",
+	"twitter_card":       "SOCIAL",
+	"hreflang":           "SEO",
+	"securityHeaders":    "SECURITY",
+	"bot_protection":     "SECURITY",
+	"rate_limit":         "SECURITY",
+	"redirect_ssrf":      "SECURITY",
+	"sql_injection":      "SECURITY",
+	"ssl":                "SSL",
+	"secrets":            "SECRETS",
+	"favicon":            "ICONS",
+	"robotsTxt":          "FILES",
+	"sitemap":            "FILES",
+	"sitemap_coverage":   "FILES",
+	"llmsTxt":            "FILES",
+	"adsTxt":             "FILES",
+	"humansTxt":          "FILES",
+	"license":            "LICENSE",
+	"required_files":     "FILES",
+	"vulnerability":      "DEPS",
+	"osv":                "DEPS",
+	"supply_chain":       "DEPS",
+	"dockerfile":         "INFRA",
+	"compose":            "INFRA",
+	"iac":                "INFRA",
+	"dependencies":       "DEPS",
+	"outdated_deps":      "DEPS",
+	"indexNow":           "INDEXNOW",
+	"canonical":          "SEO",
+	"viewport":           "MOBILE",
+	"lang":               "LANG",
+	"error_pages":        "PAGES",
+	"debug_statements":   "DEBUG",
+	"debug_mode":         "DEBUG",
+	"localhost_urls":     "DEBUG",
+	"legacy_artifacts":   "FILES",
+	"analytics_ids":      "FILES",
+	"structured_data":    "SEO",
+	"search_console":     "SEO",
+	"image_optimization": "PERF",
+	"image_alt":          "A11Y",
+	"fonts":              "PERF",
+	"resilience":         "INFRA",
+	"email_auth":         "EMAIL",
+	"bimi":               "EMAIL",
+	"www_redirect":       "INFRA",
+	"ipv6":               "INFRA",
+	"cloudflare_zone":    "INFRA",
+	"app_links":          "MOBILE",
+	"legal_pages":        "LEGAL",
+	"cookies":            "LEGAL",
+	"regulated_gating":   "LEGAL",
+	"a11y_statement":     "LEGAL",
+	"refund_policy":      "LEGAL",
+}
+
+// Service check IDs - these will be grouped separately
+var serviceCheckIDs = map[string]bool{
+	// Payments
+	"stripe": true, "paypal": true, "braintree": true, "paddle": true, "lemonsqueezy": true,
+	// Error Tracking
+	"sentry": true, "bugsnag": true, "rollbar": true, "honeybadger": true, "datadog": true, "newrelic": true, "logrocket": true,
+	// Email
+	"postmark": true, "sendgrid": true, "mailgun": true, "aws_ses": true, "resend": true,
+	"mailchimp": true, "convertkit": true, "beehiiv": true, "aweber": true, "activecampaign": true,
+	"campaignmonitor": true, "drip": true, "klaviyo": true, "buttondown": true,
+	// Analytics
+	"plausible": true, "fathom": true, "umami": true, "google_analytics": true, "fullres": true, "datafast": true,
+	"posthog": true, "mixpanel": true, "amplitude": true, "segment": true, "hotjar": true,
+	// Feature Flags
+	"launchdarkly": true, "flagsmith": true, "unleash": true, "posthog_flags": true,
+	// Auth
+	"auth0": true, "clerk": true, "workos": true, "firebase": true, "supabase": true,
+	// Communication
+	"twilio": true, "slack": true, "discord": true, "intercom": true, "crisp": true,
+	// Infrastructure
+	"redis": true, "sidekiq": true, "rabbitmq": true, "elasticsearch": true, "convex": true,
+	// Storage & CDN
+	"aws_s3": true, "cloudinary": true, "cloudflare": true,
+	// Search
+	"algolia": true,
+	// AI
+	"openai": true, "anthropic": true, "google_ai": true, "mistral": true, "cohere": true,
+	"replicate": true, "huggingface": true, "grok": true, "perplexity": true, "together_ai": true,
+	// Cookie Consent
+	"cookieconsent": true, "cookiebot": true, "onetrust": true, "termly": true, "cookieyes": true, "iubenda": true,
+	// SEO
+	"indexNow": true,
+	// Requirement groups across the services above
+	"required_services": true,
+}
+
+// Service category mapping
+var serviceCategoryMap = map[string]string{
+	// Payments
+	"stripe": "PAYMENTS", "paypal": "PAYMENTS", "braintree": "PAYMENTS", "paddle": "PAYMENTS", "lemonsqueezy": "PAYMENTS",
+	// Error Tracking
+	"sentry": "ERRORS", "bugsnag": "ERRORS", "rollbar": "ERRORS", "honeybadger": "ERRORS",
+	"datadog": "ERRORS", "newrelic": "ERRORS", "logrocket": "ERRORS",
+	// Email
+	"postmark": "EMAIL", "sendgrid": "EMAIL", "mailgun": "EMAIL", "aws_ses": "EMAIL", "resend": "EMAIL",
+	"mailchimp": "EMAIL", "convertkit": "EMAIL", "beehiiv": "EMAIL", "aweber": "EMAIL",
+	"activecampaign": "EMAIL", "campaignmonitor": "EMAIL", "drip": "EMAIL", "klaviyo": "EMAIL", "buttondown": "EMAIL",
+	// Analytics
+	"plausible": "ANALYTICS", "fathom": "ANALYTICS", "umami": "ANALYTICS", "google_analytics": "ANALYTICS", "fullres": "ANALYTICS", "datafast": "ANALYTICS",
+	"posthog": "ANALYTICS", "mixpanel": "ANALYTICS", "amplitude": "ANALYTICS", "segment": "ANALYTICS", "hotjar": "ANALYTICS",
+	// Feature Flags
+	"launchdarkly": "FLAGS", "flagsmith": "FLAGS", "unleash": "FLAGS", "posthog_flags": "FLAGS",
+	// Auth
+	"auth0": "AUTH", "clerk": "AUTH", "workos": "AUTH", "firebase": "AUTH", "supabase": "AUTH",
+	// Communication
+	"twilio": "NOTIFY", "slack": "NOTIFY", "discord": "NOTIFY", "intercom": "CHAT", "crisp": "CHAT",
+	// Infrastructure
+	"redis": "INFRA", "sidekiq": "JOBS", "rabbitmq": "JOBS", "elasticsearch": "SEARCH", "convex": "INFRA",
+	// Storage & CDN
+	"aws_s3": "STORAGE", "cloudinary": "STORAGE", "cloudflare": "INFRA",
+	// Search
+	"algolia": "SEARCH",
+	// AI
+	"openai": "AI", "anthropic": "AI", "google_ai": "AI", "mistral": "AI", "cohere": "AI",
+	"replicate": "AI", "huggingface": "AI", "grok": "AI", "perplexity": "AI", "together_ai": "AI",
+	// Cookie Consent
+	"cookieconsent": "LEGAL", "cookiebot": "LEGAL", "onetrust": "LEGAL", "termly": "LEGAL", "cookieyes": "LEGAL", "iubenda": "LEGAL",
+	// SEO
+	"indexNow": "INDEXNOW",
+	// Requirement groups
+	"required_services": "REQUIRED",
+}
+
+// Category returns the display category of a check or service ID, e.g.
+// "SEO" for canonical, "ERRORS" for sentry.
+func Category(id string) string {
+	if serviceCheckIDs[id] {
+		return categoryFor(id, serviceCategoryMap)
+	}
+	return categoryFor(id, categoryMap)
+}
+
+// IsServiceCheck reports whether id is a third-party service integration,
+// which the reports list apart from the core checks.
+func IsServiceCheck(id string) bool {
+	return serviceCheckIDs[id]
+}
+
+// CategoryIcon returns the emoji shown next to a category, or a bullet.
+func CategoryIcon(category string) string {
+	if icon := categoryIcons[category]; icon != "" {
+		return icon
+	}
+	return "•"
+}
+
+// categoryFor looks id up in catMap. Every built-in check has an entry, so
+// the IDs that fall through are the project's customChecks.
+func categoryFor(id string, catMap map[string]string) string {
+	if category := catMap[id]; category != "" {
+		return category
+	}
+	return "CUSTOM"
+}
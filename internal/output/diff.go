@@ -0,0 +1,158 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/preflightsh/preflight/internal/checks"
+)
+
+// ChangeKind classifies how one check's outcome moved between two runs.
+type ChangeKind string
+
+const (
+	// ChangeRegressed: passed before, fails now.
+	ChangeRegressed ChangeKind = "regressed"
+	// ChangeFixed: failed before, passes now.
+	ChangeFixed ChangeKind = "fixed"
+	// ChangeChanged: failed both times, but with a different severity or
+	// message (e.g. 3 debug statements became 12).
+	ChangeChanged ChangeKind = "changed"
+	// ChangeAdded: only the second run has the check.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved: only the first run has the check.
+	ChangeRemoved ChangeKind = "removed"
+)
+
+// ResultChange is one check whose outcome differs between two runs. Before
+// or After is nil for added and removed checks.
+type ResultChange struct {
+	Kind   ChangeKind          `json:"kind"`
+	ID     string              `json:"id"`
+	Title  string              `json:"title"`
+	Before *checks.CheckResult `json:"before,omitempty"`
+	After  *checks.CheckResult `json:"after,omitempty"`
+}
+
+// ResultDiff is the comparison of two runs of the suite.
+type ResultDiff struct {
+	Changes   []ResultChange `json:"changes"`
+	Unchanged int            `json:"unchanged"`
+}
+
+// Regressions counts checks that passed before and fail now.
+func (d ResultDiff) Regressions() int {
+	n := 0
+	for _, c := range d.Changes {
+		if c.Kind == ChangeRegressed {
+			n++
+		}
+	}
+	return n
+}
+
+// changeOrder sorts the worst news first.
+var changeOrder = map[ChangeKind]int{
+	ChangeRegressed: 0,
+	ChangeChanged:   1,
+	ChangeRemoved:   2,
+	ChangeAdded:     3,
+	ChangeFixed:     4,
+}
+
+// DiffResults compares two result sets by check ID. Passing checks whose
+// message merely changed wording (counts of matched files and the like)
+// are treated as unchanged.
+func DiffResults(before, after []checks.CheckResult) ResultDiff {
+	beforeByID := make(map[string]checks.CheckResult, len(before))
+	for _, r := range before {
+		beforeByID[r.ID] = r
+	}
+
+	diff := ResultDiff{Changes: []ResultChange{}}
+	seen := make(map[string]bool, len(after))
+	for i := range after {
+		a := after[i]
+		seen[a.ID] = true
+		b, ok := beforeByID[a.ID]
+		if !ok {
+			diff.Changes = append(diff.Changes, ResultChange{Kind: ChangeAdded, ID: a.ID, Title: a.Title, After: &after[i]})
+			continue
+		}
+		var kind ChangeKind
+		switch {
+		case b.Passed && !a.Passed:
+			kind = ChangeRegressed
+		case !b.Passed && a.Passed:
+			kind = ChangeFixed
+		case !b.Passed && !a.Passed && (b.Severity != a.Severity || b.Message != a.Message):
+			kind = ChangeChanged
+		default:
+			diff.Unchanged++
+			continue
+		}
+		diff.Changes = append(diff.Changes, ResultChange{Kind: kind, ID: a.ID, Title: a.Title, Before: &b, After: &after[i]})
+	}
+	for i := range before {
+		if !seen[before[i].ID] {
+			diff.Changes = append(diff.Changes, ResultChange{Kind: ChangeRemoved, ID: before[i].ID, Title: before[i].Title, Before: &before[i]})
+		}
+	}
+
+	sort.SliceStable(diff.Changes, func(i, j int) bool {
+		return changeOrder[diff.Changes[i].Kind] < changeOrder[diff.Changes[j].Kind]
+	})
+	return diff
+}
+
+// WriteDiff renders a ResultDiff for people, worst news first.
+func WriteDiff(w io.Writer, beforeLabel, afterLabel string, diff ResultDiff) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s%s ✈  Preflight Compare%s\n", colorBold, colorCyan, colorReset)
+	fmt.Fprintf(w, "%s   %s → %s%s\n", colorGray, beforeLabel, afterLabel, colorReset)
+	fmt.Fprintln(w)
+
+	if len(diff.Changes) == 0 {
+		fmt.Fprintf(w, "  %s✓ No differences%s across %d checks\n", colorGreen, colorReset, diff.Unchanged)
+		return
+	}
+
+	for _, c := range diff.Changes {
+		var icon, color, detail string
+		switch c.Kind {
+		case ChangeRegressed:
+			icon, color = "✗", colorRed
+			detail = fmt.Sprintf("passed → %s: %s", c.After.Severity, c.After.Message)
+		case ChangeFixed:
+			icon, color = "✓", colorGreen
+			detail = fmt.Sprintf("%s → passed", c.Before.Severity)
+		case ChangeChanged:
+			icon, color = "~", colorYellow
+			detail = fmt.Sprintf("%s: %s → %s: %s", c.Before.Severity, c.Before.Message, c.After.Severity, c.After.Message)
+		case ChangeAdded:
+			icon, color = "+", colorCyan
+			detail = "only in " + afterLabel + resultState(c.After)
+		case ChangeRemoved:
+			icon, color = "-", colorYellow
+			detail = "only in " + beforeLabel + resultState(c.Before)
+		}
+		fmt.Fprintf(w, "  %s%s %-10s%s %s\n", color, icon, c.Kind, colorReset, c.Title)
+		fmt.Fprintf(w, "  %s             └─ %s%s\n", colorGray, detail, colorReset)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s────────────────────────────────────────────────────────%s\n", colorGray, colorReset)
+	fmt.Fprintf(w, "  %d changed, %d unchanged", len(diff.Changes), diff.Unchanged)
+	if n := diff.Regressions(); n > 0 {
+		fmt.Fprintf(w, "    %s✗ %d regressed%s", colorRed, n, colorReset)
+	}
+	fmt.Fprintln(w)
+}
+
+func resultState(r *checks.CheckResult) string {
+	if r.Passed {
+		return " (passed)"
+	}
+	return fmt.Sprintf(" (%s: %s)", r.Severity, r.Message)
+}
@@ -0,0 +1,54 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/checks"
+)
+
+// WriteGitHubAnnotations emits a GitHub Actions workflow command
+// (`::error file=...,line=...::msg`) for every located finding of a
+// failing check, so those findings show inline in the pull request's Files
+// Changed view. Checks without Locations produce nothing here; the regular
+// report still covers them.
+func WriteGitHubAnnotations(w io.Writer, results []checks.CheckResult) {
+	for _, r := range results {
+		if r.Passed {
+			continue
+		}
+		level := "warning"
+		if r.Severity == checks.SeverityError {
+			level = "error"
+		}
+		for _, loc := range r.Locations {
+			props := "file=" + escapeAnnotationProperty(loc.File)
+			if loc.Line > 0 {
+				props += fmt.Sprintf(",line=%d", loc.Line)
+			}
+			props += ",title=" + escapeAnnotationProperty(fmt.Sprintf("%s (%s)", r.Title, r.ID))
+			msg := loc.Message
+			if msg == "" {
+				msg = r.Message
+			}
+			fmt.Fprintf(w, "::%s %s::%s\n", level, props, escapeAnnotationData(msg))
+		}
+	}
+}
+
+// Workflow commands are line-based: data must not contain raw newlines,
+// and property values additionally can't contain the ':' and ','
+// separators. These match the escaping in @actions/core.
+var (
+	annotationDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	annotationPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+func escapeAnnotationData(s string) string {
+	return annotationDataEscaper.Replace(s)
+}
+
+func escapeAnnotationProperty(s string) string {
+	return annotationPropertyEscaper.Replace(s)
+}
@@ -0,0 +1,219 @@
+package output
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/audit"
+	"github.com/preflightsh/preflight/internal/checks"
+)
+
+// HTMLOutputter renders a single-file HTML report (inline CSS, no scripts
+// or external assets) that can be emailed or attached to a ticket for
+// people who will never open a terminal.
+type HTMLOutputter struct {
+	// GeneratedAt is stamped in the header; zero means now.
+	GeneratedAt time.Time
+	// Audit, when non-empty, is listed at the end of the report.
+	Audit []audit.Entry
+}
+
+type htmlCheck struct {
+	checks.CheckResult
+	Status string // "pass", "warn" or "fail"
+	Effort string
+}
+
+type htmlSection struct {
+	Category string
+	Icon     string
+	Checks   []htmlCheck
+	Worst    int
+}
+
+type htmlReport struct {
+	Project     string
+	GeneratedAt string
+	Summary     Summary
+	Effort      string
+	Verdict     string
+	VerdictTone string
+	Core        []htmlSection
+	Services    []htmlSection
+	Audit       []audit.Entry
+}
+
+func (h HTMLOutputter) Output(w io.Writer, projectName string, results []checks.CheckResult) {
+	generated := h.GeneratedAt
+	if generated.IsZero() {
+		generated = time.Now()
+	}
+	summary := CalculateSummary(results)
+	report := htmlReport{
+		Project:     projectName,
+		GeneratedAt: generated.Format("January 2, 2006 at 15:04 MST"),
+		Summary:     summary,
+		Audit:       h.Audit,
+	}
+	if effort := RemainingEffortMinutes(results); effort > 0 {
+		report.Effort = FormatEffort(effort)
+	}
+	switch {
+	case summary.Fail > 0:
+		report.Verdict, report.VerdictTone = "Not ready for launch", "fail"
+	case summary.Warn > 0:
+		report.Verdict, report.VerdictTone = "Review warnings before launch", "warn"
+	default:
+		report.Verdict, report.VerdictTone = "Ready for launch", "pass"
+	}
+
+	core := map[string]*htmlSection{}
+	services := map[string]*htmlSection{}
+	for _, r := range results {
+		if isSkipped(r) {
+			continue
+		}
+		c := htmlCheck{CheckResult: r, Status: "pass"}
+		rank := 0
+		if !r.Passed {
+			c.Status, rank = "warn", 1
+			if r.Severity == checks.SeverityError {
+				c.Status, rank = "fail", 2
+			}
+			c.Effort = FormatEffort(checks.EffortFor(r.ID).Minutes)
+		}
+		sections := core
+		if IsServiceCheck(r.ID) {
+			sections = services
+		}
+		category := Category(r.ID)
+		s := sections[category]
+		if s == nil {
+			s = &htmlSection{Category: category, Icon: CategoryIcon(category)}
+			sections[category] = s
+		}
+		s.Checks = append(s.Checks, c)
+		if rank > s.Worst {
+			s.Worst = rank
+		}
+	}
+	report.Core = sortedSections(core)
+	report.Services = sortedSections(services)
+
+	if err := htmlReportTemplate.Execute(w, report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering HTML report: %v\n", err)
+	}
+}
+
+// sortedSections puts the sections with the worst results first, then
+// orders by name so the report is stable between runs.
+func sortedSections(m map[string]*htmlSection) []htmlSection {
+	out := make([]htmlSection, 0, len(m))
+	for _, s := range m {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Worst != out[j].Worst {
+			return out[i].Worst > out[j].Worst
+		}
+		return out[i].Category < out[j].Category
+	})
+	return out
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"auditTime": func(t time.Time) string { return t.Local().Format("2006-01-02 15:04") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Preflight report: {{.Project}}</title>
+<style>
+  :root { --pass: #15803d; --warn: #b45309; --fail: #b91c1c; --muted: #6b7280; --line: #e5e7eb; }
+  * { box-sizing: border-box; }
+  body { margin: 0; font: 15px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; color: #111827; background: #f9fafb; }
+  main { max-width: 880px; margin: 0 auto; padding: 32px 20px 64px; }
+  header h1 { margin: 0 0 4px; font-size: 26px; }
+  header p { margin: 0; color: var(--muted); }
+  .verdict { margin: 24px 0; padding: 16px 20px; border-radius: 10px; color: #fff; font-size: 18px; font-weight: 600; }
+  .verdict.pass { background: var(--pass); } .verdict.warn { background: var(--warn); } .verdict.fail { background: var(--fail); }
+  .verdict small { display: block; font-weight: 400; font-size: 14px; opacity: .9; }
+  .counts { display: flex; gap: 12px; margin-bottom: 32px; }
+  .counts div { flex: 1; background: #fff; border: 1px solid var(--line); border-radius: 10px; padding: 12px 16px; }
+  .counts b { display: block; font-size: 24px; }
+  h2 { font-size: 18px; margin: 36px 0 12px; }
+  section { background: #fff; border: 1px solid var(--line); border-radius: 10px; margin-bottom: 16px; overflow: hidden; }
+  section h3 { margin: 0; padding: 10px 16px; font-size: 13px; letter-spacing: .06em; color: var(--muted); border-bottom: 1px solid var(--line); background: #f3f4f6; }
+  .check { padding: 12px 16px; border-left: 4px solid var(--pass); }
+  .check + .check { border-top: 1px solid var(--line); }
+  .check.warn { border-left-color: var(--warn); } .check.fail { border-left-color: var(--fail); }
+  .check .title { font-weight: 600; }
+  .badge { float: right; font-size: 12px; font-weight: 600; padding: 2px 8px; border-radius: 999px; color: #fff; }
+  .badge.pass { background: var(--pass); } .badge.warn { background: var(--warn); } .badge.fail { background: var(--fail); }
+  .message { color: #374151; margin-top: 2px; }
+  .effort { color: var(--muted); font-size: 13px; }
+  ul { margin: 8px 0 0; padding-left: 20px; }
+  li { margin: 2px 0; }
+  details { margin-top: 6px; color: var(--muted); font-size: 13px; }
+  code, .mono { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: 13px; }
+  table { width: 100%; border-collapse: collapse; background: #fff; border: 1px solid var(--line); border-radius: 10px; font-size: 13px; }
+  th, td { text-align: left; padding: 8px 12px; border-bottom: 1px solid var(--line); vertical-align: top; }
+  th { background: #f3f4f6; color: var(--muted); font-weight: 600; }
+  footer { margin-top: 40px; color: var(--muted); font-size: 13px; }
+  @media print { body { background: #fff; } .check, section { break-inside: avoid; } }
+</style>
+</head>
+<body>
+<main>
+<header>
+  <h1>✈ Preflight launch report</h1>
+  <p><strong>{{.Project}}</strong> · generated {{.GeneratedAt}}</p>
+</header>
+
+<div class="verdict {{.VerdictTone}}">{{.Verdict}}{{if .Effort}}<small>About {{.Effort}} of fixes remaining</small>{{end}}</div>
+
+<div class="counts">
+  <div><b style="color:var(--pass)">{{.Summary.OK}}</b>passed</div>
+  <div><b style="color:var(--warn)">{{.Summary.Warn}}</b>warnings</div>
+  <div><b style="color:var(--fail)">{{.Summary.Fail}}</b>failed</div>
+</div>
+
+{{define "section"}}
+<section>
+  <h3>{{.Icon}} {{.Category}}</h3>
+  {{range .Checks}}
+  <div class="check {{.Status}}">
+    <span class="badge {{.Status}}">{{if eq .Status "pass"}}PASS{{else if eq .Status "warn"}}WARN{{else}}FAIL{{end}}</span>
+    <div class="title">{{.Title}}</div>
+    {{if .Message}}<div class="message">{{.Message}}</div>{{end}}
+    {{if .Effort}}<div class="effort">Estimated fix: {{.Effort}}</div>{{end}}
+    {{if and (ne .Status "pass") .Suggestions}}<ul>{{range .Suggestions}}<li>{{.}}</li>{{end}}</ul>{{end}}
+    {{if .Details}}<details><summary>Details</summary><ul class="mono">{{range .Details}}<li>{{.}}</li>{{end}}</ul></details>{{end}}
+  </div>
+  {{end}}
+</section>
+{{end}}
+
+{{if .Core}}<h2>Checks</h2>{{range .Core}}{{template "section" .}}{{end}}{{end}}
+{{if .Services}}<h2>Services</h2>{{range .Services}}{{template "section" .}}{{end}}{{end}}
+
+{{if .Audit}}
+<h2>Audit log</h2>
+<table>
+  <tr><th>When</th><th>Who</th><th>Action</th><th>Check</th><th>Reason</th></tr>
+  {{range .Audit}}
+  <tr><td class="mono">{{auditTime .Time}}</td><td>{{.User}}</td><td>{{.Action}}{{if .Until}} until {{.Until}}{{end}}</td><td>{{.CheckID}}{{if .Path}} <code>{{.Path}}</code>{{end}}</td><td>{{.Reason}}</td></tr>
+  {{end}}
+</table>
+{{end}}
+
+<footer>Generated by Preflight · https://preflight.sh</footer>
+</main>
+</body>
+</html>
+`))
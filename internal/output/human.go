@@ -49,8 +49,15 @@ func shouldUseColor() bool {
 	return fi.Mode()&os.ModeCharDevice != 0
 }
 
+// maxDiagnosticsShown caps the unreadable paths listed without -v.
+const maxDiagnosticsShown = 5
+
 type HumanOutputter struct {
 	Verbose bool
+	// Skipped lists the checks a time-boxed scan didn't get to.
+	Skipped []string
+	// Diagnostics lists the paths the scan couldn't read.
+	Diagnostics []checks.Diagnostic
 }
 
 func (h HumanOutputter) Output(w io.Writer, projectName string, results []checks.CheckResult) {
@@ -60,143 +67,13 @@ func (h HumanOutputter) Output(w io.Writer, projectName string, results []checks
 	fmt.Fprintf(w, "%s   Project: %s%s\n", colorGray, projectName, colorReset)
 	fmt.Fprintln(w)
 
-	// Category icons
-	categoryIcons := map[string]string{
-		"ENV":       "📋",
-		"HEALTH":    "💓",
-		"PAYMENTS":  "💳",
-		"ERRORS":    "🐛",
-		"ANALYTICS": "📊",
-		"INFRA":     "🔧",
-		"JOBS":      "⚡",
-		"SEO":       "🔍",
-		"SECURITY":  "🔒",
-		"SECRETS":   "🔑",
-		"AI":        "🤖",
-		"EMAIL":     "📧",
-		"AUTH":      "🔐",
-		"STORAGE":   "📦",
-		"SEARCH":    "🔎",
-		"CHAT":      "💬",
-		"NOTIFY":    "🔔",
-		"SOCIAL":    "📱",
-		"ICONS":     "🎨",
-		"FILES":     "📄",
-		"SSL":       "🔐",
-		"LICENSE":   "📜",
-		"DEPS":      "📦",
-		"INDEXNOW":  "🔗",
-		"MOBILE":    "📱",
-		"LANG":      "🌐",
-		"PAGES":     "📃",
-		"DEBUG":     "🐞",
-		"PERF":      "⚡",
-		"LEGAL":     "⚖️ ",
-	}
-
-	// Map check IDs to display categories
-	categoryMap := map[string]string{
-		"envParity":          "ENV",
-		"healthEndpoint":     "HEALTH",
-		"seoMeta":            "SEO",
-		"ogTwitter":          "SOCIAL",
-		"securityHeaders":    "SECURITY",
-		"ssl":                "SSL",
-		"secrets":            "SECRETS",
-		"favicon":            "ICONS",
-		"robotsTxt":          "FILES",
-		"sitemap":            "FILES",
-		"llmsTxt":            "FILES",
-		"adsTxt":             "FILES",
-		"humansTxt":          "FILES",
-		"license":            "LICENSE",
-		"vulnerability":      "DEPS",
-		"indexNow":           "INDEXNOW",
-		"canonical":          "SEO",
-		"viewport":           "MOBILE",
-		"lang":               "LANG",
-		"error_pages":        "PAGES",
-		"debug_statements":   "DEBUG",
-		"structured_data":    "SEO",
-		"image_optimization": "PERF",
-		"email_auth":         "EMAIL",
-		"www_redirect":       "INFRA",
-		"legal_pages":        "LEGAL",
-	}
-
-	// Service check IDs - these will be grouped separately
-	serviceCheckIDs := map[string]bool{
-		// Payments
-		"stripe": true, "paypal": true, "braintree": true, "paddle": true, "lemonsqueezy": true,
-		// Error Tracking
-		"sentry": true, "bugsnag": true, "rollbar": true, "honeybadger": true, "datadog": true, "newrelic": true, "logrocket": true,
-		// Email
-		"postmark": true, "sendgrid": true, "mailgun": true, "aws_ses": true, "resend": true,
-		"mailchimp": true, "convertkit": true, "beehiiv": true, "aweber": true, "activecampaign": true,
-		"campaignmonitor": true, "drip": true, "klaviyo": true, "buttondown": true,
-		// Analytics
-		"plausible": true, "fathom": true, "umami": true, "google_analytics": true, "fullres": true, "datafast": true,
-		"posthog": true, "mixpanel": true, "amplitude": true, "segment": true, "hotjar": true,
-		// Auth
-		"auth0": true, "clerk": true, "workos": true, "firebase": true, "supabase": true,
-		// Communication
-		"twilio": true, "slack": true, "discord": true, "intercom": true, "crisp": true,
-		// Infrastructure
-		"redis": true, "sidekiq": true, "rabbitmq": true, "elasticsearch": true, "convex": true,
-		// Storage & CDN
-		"aws_s3": true, "cloudinary": true, "cloudflare": true,
-		// Search
-		"algolia": true,
-		// AI
-		"openai": true, "anthropic": true, "google_ai": true, "mistral": true, "cohere": true,
-		"replicate": true, "huggingface": true, "grok": true, "perplexity": true, "together_ai": true,
-		// Cookie Consent
-		"cookieconsent": true, "cookiebot": true, "onetrust": true, "termly": true, "cookieyes": true, "iubenda": true,
-		// SEO
-		"indexNow": true,
-	}
-
-	// Service category mapping
-	serviceCategoryMap := map[string]string{
-		// Payments
-		"stripe": "PAYMENTS", "paypal": "PAYMENTS", "braintree": "PAYMENTS", "paddle": "PAYMENTS", "lemonsqueezy": "PAYMENTS",
-		// Error Tracking
-		"sentry": "ERRORS", "bugsnag": "ERRORS", "rollbar": "ERRORS", "honeybadger": "ERRORS",
-		"datadog": "ERRORS", "newrelic": "ERRORS", "logrocket": "ERRORS",
-		// Email
-		"postmark": "EMAIL", "sendgrid": "EMAIL", "mailgun": "EMAIL", "aws_ses": "EMAIL", "resend": "EMAIL",
-		"mailchimp": "EMAIL", "convertkit": "EMAIL", "beehiiv": "EMAIL", "aweber": "EMAIL",
-		"activecampaign": "EMAIL", "campaignmonitor": "EMAIL", "drip": "EMAIL", "klaviyo": "EMAIL", "buttondown": "EMAIL",
-		// Analytics
-		"plausible": "ANALYTICS", "fathom": "ANALYTICS", "umami": "ANALYTICS", "google_analytics": "ANALYTICS", "fullres": "ANALYTICS", "datafast": "ANALYTICS",
-		"posthog": "ANALYTICS", "mixpanel": "ANALYTICS", "amplitude": "ANALYTICS", "segment": "ANALYTICS", "hotjar": "ANALYTICS",
-		// Auth
-		"auth0": "AUTH", "clerk": "AUTH", "workos": "AUTH", "firebase": "AUTH", "supabase": "AUTH",
-		// Communication
-		"twilio": "NOTIFY", "slack": "NOTIFY", "discord": "NOTIFY", "intercom": "CHAT", "crisp": "CHAT",
-		// Infrastructure
-		"redis": "INFRA", "sidekiq": "JOBS", "rabbitmq": "JOBS", "elasticsearch": "SEARCH", "convex": "INFRA",
-		// Storage & CDN
-		"aws_s3": "STORAGE", "cloudinary": "STORAGE", "cloudflare": "INFRA",
-		// Search
-		"algolia": "SEARCH",
-		// AI
-		"openai": "AI", "anthropic": "AI", "google_ai": "AI", "mistral": "AI", "cohere": "AI",
-		"replicate": "AI", "huggingface": "AI", "grok": "AI", "perplexity": "AI", "together_ai": "AI",
-		// Cookie Consent
-		"cookieconsent": "LEGAL", "cookiebot": "LEGAL", "onetrust": "LEGAL", "termly": "LEGAL", "cookieyes": "LEGAL", "iubenda": "LEGAL",
-		// SEO
-		"indexNow": "INDEXNOW",
-	}
-
 	// Separate results into non-service checks and service checks
 	// Also filter out skipped checks entirely
 	var coreResults []checks.CheckResult
 	var serviceResults []checks.CheckResult
 	for _, r := range results {
 		// Skip checks that are just "skipping" or "skipped" - don't clutter output
-		if r.Passed && (strings.Contains(strings.ToLower(r.Message), "skipping") ||
-			strings.Contains(strings.ToLower(r.Message), "skipped")) {
+		if isSkipped(r) {
 			continue
 		}
 		if serviceCheckIDs[r.ID] {
@@ -208,15 +85,9 @@ func (h HumanOutputter) Output(w io.Writer, projectName string, results []checks
 
 	// Helper function to print a check result
 	printResult := func(r checks.CheckResult, isLast bool, catMap map[string]string) {
-		category := catMap[r.ID]
-		if category == "" {
-			category = strings.ToUpper(r.ID)
-		}
+		category := categoryFor(r.ID, catMap)
 
-		icon := categoryIcons[category]
-		if icon == "" {
-			icon = "•"
-		}
+		icon := CategoryIcon(category)
 
 		status := formatStatus(r)
 		categoryLabel := fmt.Sprintf("%s  %-10s", icon, category)
@@ -239,6 +110,10 @@ func (h HumanOutputter) Output(w io.Writer, projectName string, results []checks
 			}
 		}
 
+		if r.Profile != nil {
+			fmt.Fprintf(w, "  %s                  ⏲  %s%s\n", colorGray, FormatUsage(*r.Profile), colorReset)
+		}
+
 		// Add subtle divider between checks (except after the last one)
 		if !isLast {
 			fmt.Fprintf(w, "  %s· · · · · · · · · · · · · · · · · · · · · · · · · · · ·%s\n", colorGray, colorReset)
@@ -282,6 +157,32 @@ func (h HumanOutputter) Output(w io.Writer, projectName string, results []checks
 		fmt.Fprintf(w, "    %s✗ Failed:%s  %s%d%s", colorRed, colorReset, colorBold, summary.Fail, colorReset)
 	}
 	fmt.Fprintln(w)
+	if effort := RemainingEffortMinutes(results); effort > 0 {
+		fmt.Fprintf(w, "  %s≈%s of fixes remaining%s\n", colorGray, FormatEffort(effort), colorReset)
+	}
+	if slowest := SlowestChecks(results, 3); len(slowest) > 0 {
+		parts := make([]string, len(slowest))
+		for i, r := range slowest {
+			parts[i] = fmt.Sprintf("%s %s", r.ID, formatMillis(r.Profile.WallMs))
+		}
+		fmt.Fprintf(w, "  %s⏲ Slowest: %s%s\n", colorGray, strings.Join(parts, ", "), colorReset)
+	}
+	if len(h.Diagnostics) > 0 {
+		fmt.Fprintf(w, "  %s⚠ Did not scan %d path(s); findings in them may be missing:%s\n", colorYellow, len(h.Diagnostics), colorReset)
+		shown := h.Diagnostics
+		if !h.Verbose && len(shown) > maxDiagnosticsShown {
+			shown = shown[:maxDiagnosticsShown]
+		}
+		for _, d := range shown {
+			fmt.Fprintf(w, "  %s   %s (%s)%s\n", colorGray, d.Path, d.Error, colorReset)
+		}
+		if more := len(h.Diagnostics) - len(shown); more > 0 {
+			fmt.Fprintf(w, "  %s   ...and %d more (-v lists them all)%s\n", colorGray, more, colorReset)
+		}
+	}
+	if len(h.Skipped) > 0 {
+		fmt.Fprintf(w, "  %s⏱ Not run (time budget): %s%s\n", colorGray, strings.Join(h.Skipped, ", "), colorReset)
+	}
 	fmt.Fprintln(w)
 
 	// Final verdict
@@ -289,12 +190,22 @@ func (h HumanOutputter) Output(w io.Writer, projectName string, results []checks
 		fmt.Fprintf(w, "  %s%s✗ Not ready for launch%s\n", colorBold, colorRed, colorReset)
 	} else if summary.Warn > 0 {
 		fmt.Fprintf(w, "  %s%s⚠ Review warnings before launch%s\n", colorBold, colorYellow, colorReset)
+	} else if len(h.Skipped) > 0 {
+		// Nothing wrong so far, but a partial scan can't clear a launch.
+		fmt.Fprintf(w, "  %s%s⚠ No problems found, but %d check(s) didn't run%s\n", colorBold, colorYellow, len(h.Skipped), colorReset)
 	} else {
 		fmt.Fprintf(w, "  %s%s✓ Ready for launch!%s\n", colorBold, colorGreen, colorReset)
 	}
 	fmt.Fprintln(w)
 }
 
+// isSkipped reports whether a check passed only because it didn't apply
+// ("No layout found, skipping"). Reports leave those out entirely.
+func isSkipped(r checks.CheckResult) bool {
+	msg := strings.ToLower(r.Message)
+	return r.Passed && (strings.Contains(msg, "skipping") || strings.Contains(msg, "skipped"))
+}
+
 // hasUsefulPassedMessage returns true if the message contains info worth showing
 // even when the check passed (e.g., license type, version info)
 func hasUsefulPassedMessage(msg string) bool {
@@ -309,6 +220,7 @@ func hasUsefulPassedMessage(msg string) bool {
 		"not declared",   // Service not declared
 		"prod:",          // Per-environment summary (security headers, SEO checks)
 		"staging:",
+		"have alt text", // Alt-text coverage figure
 	}
 
 	msgLower := strings.ToLower(msg)
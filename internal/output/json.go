@@ -6,15 +6,32 @@ import (
 	"io"
 	"os"
 
+	"github.com/preflightsh/preflight/internal/audit"
 	"github.com/preflightsh/preflight/internal/checks"
 )
 
-type JSONOutputter struct{}
+type JSONOutputter struct {
+	// Audit is the project's audit log, included verbatim when non-empty.
+	Audit []audit.Entry
+	// Skipped lists the checks a time-boxed scan didn't get to.
+	Skipped []string
+	// Diagnostics lists the paths the scan couldn't read.
+	Diagnostics []checks.Diagnostic
+	// FailOn is the policy the verdict applies ("warn" when empty).
+	FailOn string
+	// Scan describes the run for the verdict; Checks and Skipped are
+	// filled in from the results.
+	Scan ScanMeta
+}
 
 type JSONOutput struct {
-	Project string            `json:"project"`
-	Summary Summary           `json:"summary"`
-	Checks  []JSONCheckResult `json:"checks"`
+	Project     string              `json:"project"`
+	Summary     Summary             `json:"summary"`
+	Verdict     Verdict             `json:"verdict"`
+	Checks      []JSONCheckResult   `json:"checks"`
+	Audit       []audit.Entry       `json:"audit,omitempty"`
+	Skipped     []string            `json:"skipped,omitempty"`
+	Diagnostics []checks.Diagnostic `json:"diagnostics,omitempty"`
 }
 
 type JSONCheckResult struct {
@@ -24,10 +41,22 @@ type JSONCheckResult struct {
 	Severity    string   `json:"severity"`
 	Message     string   `json:"message,omitempty"`
 	Suggestions []string `json:"suggestions,omitempty"`
+	// Effort is the fix estimate, present only on failing checks.
+	Effort *checks.Effort `json:"effort,omitempty"`
+	// Profile is the check's resource usage, with --profile-checks.
+	Profile *checks.Usage `json:"profile,omitempty"`
 }
 
 func (j JSONOutputter) Output(w io.Writer, projectName string, results []checks.CheckResult) {
 	output := BuildJSONOutput(projectName, results)
+	output.Audit = j.Audit
+	output.Skipped = j.Skipped
+	output.Diagnostics = j.Diagnostics
+	output.Verdict = NewVerdict(results, j.FailOn)
+	meta := j.Scan
+	meta.Checks = len(results)
+	meta.Skipped = len(j.Skipped)
+	output.Verdict.Scan = meta
 
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
@@ -42,8 +71,10 @@ func BuildJSONOutput(projectName string, results []checks.CheckResult) JSONOutpu
 	output := JSONOutput{
 		Project: projectName,
 		Summary: CalculateSummary(results),
+		Verdict: NewVerdict(results, ""),
 		Checks:  make([]JSONCheckResult, len(results)),
 	}
+	output.Summary.EffortMinutes = RemainingEffortMinutes(results)
 
 	for i, r := range results {
 		output.Checks[i] = JSONCheckResult{
@@ -53,8 +84,44 @@ func BuildJSONOutput(projectName string, results []checks.CheckResult) JSONOutpu
 			Severity:    string(r.Severity),
 			Message:     r.Message,
 			Suggestions: r.Suggestions,
+			Profile:     r.Profile,
+		}
+		if !r.Passed {
+			effort := checks.EffortFor(r.ID)
+			output.Checks[i].Effort = &effort
 		}
 	}
 
 	return output
 }
+
+// ReadJSONOutput decodes a report written by JSONOutputter (scan --format
+// json) back into check results. Only what the report carries comes back:
+// Details and Locations aren't part of it.
+func ReadJSONOutput(r io.Reader) (JSONOutput, []checks.CheckResult, error) {
+	var report struct {
+		JSONOutput
+		Checks *[]JSONCheckResult `json:"checks"`
+	}
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return JSONOutput{}, nil, fmt.Errorf("not a preflight JSON report: %w", err)
+	}
+	if report.Checks == nil {
+		return JSONOutput{}, nil, fmt.Errorf("not a preflight JSON report: no \"checks\" list")
+	}
+	report.JSONOutput.Checks = *report.Checks
+
+	results := make([]checks.CheckResult, len(*report.Checks))
+	for i, c := range *report.Checks {
+		results[i] = checks.CheckResult{
+			ID:          c.ID,
+			Title:       c.Title,
+			Severity:    checks.Severity(c.Severity),
+			Passed:      c.Passed,
+			Message:     c.Message,
+			Suggestions: c.Suggestions,
+			Profile:     c.Profile,
+		}
+	}
+	return report.JSONOutput, results, nil
+}
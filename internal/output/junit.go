@@ -0,0 +1,107 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/checks"
+)
+
+// JUnitOutputter renders results as JUnit XML, one test case per check, so
+// CI systems that only visualize test reports (Jenkins, GitLab) show
+// preflight failures alongside the unit tests. Failing checks of either
+// severity become <failure> elements; the type attribute carries the
+// severity so a warning can be told apart from an error in the report.
+type JUnitOutputter struct{}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (j JUnitOutputter) Output(w io.Writer, projectName string, results []checks.CheckResult) {
+	j.OutputGroups(w, []ResultGroup{{Name: projectName, Results: results}})
+}
+
+// ResultGroup is one project's results in a report covering several, such
+// as the apps of a monorepo.
+type ResultGroup struct {
+	Name    string
+	Results []checks.CheckResult
+}
+
+// OutputGroups renders one <testsuite> per group in a single document.
+func (j JUnitOutputter) OutputGroups(w io.Writer, groups []ResultGroup) {
+	doc := junitTestSuites{Name: "preflight"}
+	for _, g := range groups {
+		suite := junitSuite(g.Name, g.Results)
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+		doc.Suites = append(doc.Suites, suite)
+	}
+
+	io.WriteString(w, xml.Header)
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JUnit XML: %v\n", err)
+		return
+	}
+	io.WriteString(w, "\n")
+}
+
+func junitSuite(projectName string, results []checks.CheckResult) junitTestSuite {
+	suite := junitTestSuite{
+		Name:  projectName,
+		Tests: len(results),
+		Cases: make([]junitTestCase, len(results)),
+	}
+	for i, r := range results {
+		tc := junitTestCase{
+			Name:      fmt.Sprintf("%s (%s)", r.Title, r.ID),
+			ClassName: "preflight." + r.ID,
+		}
+		if r.Passed {
+			tc.SystemOut = r.Message
+		} else {
+			suite.Failures++
+			text := r.Message
+			if len(r.Suggestions) > 0 {
+				text += "\n\n" + strings.Join(r.Suggestions, "\n")
+			}
+			tc.Failure = &junitFailure{
+				Message: r.Message,
+				Type:    string(r.Severity),
+				Text:    text,
+			}
+		}
+		suite.Cases[i] = tc
+	}
+	return suite
+}
@@ -0,0 +1,126 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/preflightsh/preflight/internal/checks"
+)
+
+// MarkdownOutputter renders a GitHub-flavored Markdown summary meant to be
+// posted as a pull request comment: a verdict line, a table of the checks
+// that need attention, passing checks folded away, and a collapsible
+// section per failing check with its suggestions and details.
+type MarkdownOutputter struct{}
+
+func (m MarkdownOutputter) Output(w io.Writer, projectName string, results []checks.CheckResult) {
+	summary := CalculateSummary(results)
+
+	var verdict string
+	switch {
+	case summary.Fail > 0:
+		verdict = "❌ **Not ready for launch**"
+	case summary.Warn > 0:
+		verdict = "⚠️ **Review warnings before launch**"
+	default:
+		verdict = "✅ **Ready for launch**"
+	}
+
+	fmt.Fprintf(w, "## ✈️ Preflight: %s\n\n", markdownText(projectName))
+	fmt.Fprintf(w, "%s · %d passed · %d warnings · %d failed", verdict, summary.OK, summary.Warn, summary.Fail)
+	if effort := RemainingEffortMinutes(results); effort > 0 {
+		fmt.Fprintf(w, " · about %s of fixes", FormatEffort(effort))
+	}
+	fmt.Fprint(w, "\n\n")
+
+	var failing, passing []checks.CheckResult
+	for _, r := range results {
+		switch {
+		case !r.Passed:
+			failing = append(failing, r)
+		case !isSkipped(r):
+			passing = append(passing, r)
+		}
+	}
+	sort.SliceStable(failing, func(i, j int) bool {
+		return markdownRank(failing[i]) > markdownRank(failing[j])
+	})
+
+	if len(failing) > 0 {
+		fmt.Fprintln(w, "| | Check | Category | Result |")
+		fmt.Fprintln(w, "|---|---|---|---|")
+		for _, r := range failing {
+			fmt.Fprintf(w, "| %s | %s | %s | %s |\n", markdownIcon(r), markdownText(r.Title), Category(r.ID), markdownText(r.Message))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(passing) > 0 {
+		fmt.Fprintf(w, "<details>\n<summary>✅ %d passed</summary>\n\n", len(passing))
+		fmt.Fprintln(w, "| Check | Category | Result |")
+		fmt.Fprintln(w, "|---|---|---|")
+		for _, r := range passing {
+			fmt.Fprintf(w, "| %s | %s | %s |\n", markdownText(r.Title), Category(r.ID), markdownText(r.Message))
+		}
+		fmt.Fprint(w, "\n</details>\n\n")
+	}
+
+	for _, r := range failing {
+		if len(r.Suggestions) == 0 && len(r.Details) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "<details>\n<summary>%s <b>%s</b> <code>%s</code></summary>\n\n", markdownIcon(r), htmlEscaper.Replace(r.Title), htmlEscaper.Replace(r.ID))
+		if r.Message != "" {
+			fmt.Fprintf(w, "%s\n\n", markdownText(r.Message))
+		}
+		fmt.Fprintf(w, "Estimated fix: %s\n\n", FormatEffort(checks.EffortFor(r.ID).Minutes))
+		for _, s := range r.Suggestions {
+			fmt.Fprintf(w, "- %s\n", markdownText(s))
+		}
+		if len(r.Suggestions) > 0 {
+			fmt.Fprintln(w)
+		}
+		if len(r.Details) > 0 {
+			fmt.Fprintln(w, "```")
+			for _, d := range r.Details {
+				fmt.Fprintln(w, strings.ReplaceAll(d, "```", "'''"))
+			}
+			fmt.Fprint(w, "```\n\n")
+		}
+		fmt.Fprint(w, "</details>\n\n")
+	}
+
+	fmt.Fprintln(w, "<sub>Generated by [Preflight](https://preflight.sh)</sub>")
+}
+
+func markdownRank(r checks.CheckResult) int {
+	if r.Severity == checks.SeverityError {
+		return 2
+	}
+	return 1
+}
+
+func markdownIcon(r checks.CheckResult) string {
+	switch {
+	case r.Passed:
+		return "✅"
+	case r.Severity == checks.SeverityError:
+		return "❌"
+	default:
+		return "⚠️"
+	}
+}
+
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// markdownText makes free text safe inside a table cell: pipes would
+// split the cell, newlines would end the row, and raw angle brackets
+// would be taken as HTML.
+func markdownText(s string) string {
+	s = htmlEscaper.Replace(s)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(strings.TrimSpace(s), "\n", "<br>")
+	return s
+}
@@ -1,7 +1,9 @@
 package output
 
 import (
+	"fmt"
 	"io"
+	"sort"
 
 	"github.com/preflightsh/preflight/internal/checks"
 )
@@ -18,6 +20,10 @@ type Summary struct {
 	OK   int `json:"ok"`
 	Warn int `json:"warn"`
 	Fail int `json:"fail"`
+	// EffortMinutes is the estimated time to fix every failing check (see
+	// checks.EffortFor). Filled in by the outputters rather than
+	// CalculateSummary, which stays a pure tally.
+	EffortMinutes int `json:"effortMinutes,omitempty"`
 }
 
 func CalculateSummary(results []checks.CheckResult) Summary {
@@ -40,3 +46,81 @@ func CalculateSummary(results []checks.CheckResult) Summary {
 
 	return summary
 }
+
+// RemainingEffortMinutes sums the fix estimates of every failing check.
+func RemainingEffortMinutes(results []checks.CheckResult) int {
+	total := 0
+	for _, r := range results {
+		if !r.Passed {
+			total += checks.EffortFor(r.ID).Minutes
+		}
+	}
+	return total
+}
+
+// FormatEffort renders minutes the way the summary line reads them:
+// "45 minutes", "1 hour", "2h 30m".
+func FormatEffort(minutes int) string {
+	switch {
+	case minutes < 60:
+		if minutes == 1 {
+			return "1 minute"
+		}
+		return fmt.Sprintf("%d minutes", minutes)
+	case minutes%60 == 0:
+		if minutes == 60 {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", minutes/60)
+	default:
+		return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+	}
+}
+
+// FormatUsage renders a check's --profile-checks figures on one line:
+// "1.2s wall, 840ms CPU, 312 files, 4.1 MB, 2 network calls".
+func FormatUsage(u checks.Usage) string {
+	calls := "network calls"
+	if u.NetworkCalls == 1 {
+		calls = "network call"
+	}
+	files := "files"
+	if u.FilesRead == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%s wall, %s CPU, %d %s, %s, %d %s",
+		formatMillis(u.WallMs), formatMillis(u.CPUMs), u.FilesRead, files, formatBytes(u.BytesScanned), u.NetworkCalls, calls)
+}
+
+// SlowestChecks returns up to n profiled results, longest wall time first.
+func SlowestChecks(results []checks.CheckResult, n int) []checks.CheckResult {
+	var profiled []checks.CheckResult
+	for _, r := range results {
+		if r.Profile != nil {
+			profiled = append(profiled, r)
+		}
+	}
+	sort.SliceStable(profiled, func(i, j int) bool { return profiled[i].Profile.WallMs > profiled[j].Profile.WallMs })
+	if len(profiled) > n {
+		profiled = profiled[:n]
+	}
+	return profiled
+}
+
+func formatMillis(ms int64) string {
+	if ms < 1000 {
+		return fmt.Sprintf("%dms", ms)
+	}
+	return fmt.Sprintf("%.1fs", float64(ms)/1000)
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n < 1000:
+		return fmt.Sprintf("%d B", n)
+	case n < 1000*1000:
+		return fmt.Sprintf("%.1f KB", float64(n)/1000)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1000*1000))
+	}
+}
@@ -3,9 +3,14 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/preflightsh/preflight/internal/audit"
 	"github.com/preflightsh/preflight/internal/checks"
 )
 
@@ -55,7 +60,26 @@ func TestJSONOutputterGolden(t *testing.T) {
   "summary": {
     "ok": 1,
     "warn": 1,
-    "fail": 1
+    "fail": 1,
+    "effortMinutes": 90
+  },
+  "verdict": {
+    "ready": false,
+    "failOn": "warn",
+    "blocking": [
+      "secrets",
+      "ogTwitter"
+    ],
+    "counts": {
+      "passed": 1,
+      "error": 1,
+      "warn": 1,
+      "info": 0
+    },
+    "score": 50,
+    "scan": {
+      "checks": 3
+    }
   },
   "checks": [
     {
@@ -73,14 +97,22 @@ func TestJSONOutputterGolden(t *testing.T) {
       "message": "og:image too small (64x64, min 200x200)",
       "suggestions": [
         "Use an image at least 1200x630"
-      ]
+      ],
+      "effort": {
+        "minutes": 30,
+        "difficulty": "easy"
+      }
     },
     {
       "id": "secrets",
       "title": "Secrets scan",
       "passed": false,
       "severity": "error",
-      "message": "Potential secrets detected"
+      "message": "Potential secrets detected",
+      "effort": {
+        "minutes": 60,
+        "difficulty": "hard"
+      }
     }
   ]
 }
@@ -100,7 +132,7 @@ func TestJSONOutputContractKeys(t *testing.T) {
 	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
 		t.Fatalf("output is not valid JSON: %v", err)
 	}
-	for _, key := range []string{"project", "summary", "checks"} {
+	for _, key := range []string{"project", "summary", "verdict", "checks"} {
 		if _, ok := decoded[key]; !ok {
 			t.Errorf("top-level key %q missing from JSON contract", key)
 		}
@@ -131,6 +163,37 @@ func TestJSONOutputContractKeys(t *testing.T) {
 	}
 }
 
+func TestVerdict(t *testing.T) {
+	results := append(sampleResults(), checks.CheckResult{ID: "humansTxt", Severity: checks.SeverityInfo, Message: "No humans.txt"})
+	v := NewVerdict(results, "error")
+	if v.Ready || !reflect.DeepEqual(v.Blocking, []string{"secrets"}) || v.Counts != (SeverityCounts{Passed: 1, Error: 1, Warn: 1, Info: 1}) {
+		t.Errorf("failOn error: %+v", v)
+	}
+	if v := NewVerdict(results[:2], "error"); !v.Ready || len(v.Blocking) != 0 {
+		t.Errorf("warnings only, failOn error: %+v", v)
+	}
+	if v := NewVerdict(results[:2], ""); v.Ready || v.FailOn != "warn" || !reflect.DeepEqual(v.Blocking, []string{"ogTwitter"}) {
+		t.Errorf("warnings only, default policy: %+v", v)
+	}
+
+	var buf bytes.Buffer
+	JSONOutputter{
+		FailOn:  "error",
+		Skipped: []string{"vulnerability"},
+		Scan:    ScanMeta{Version: "1.2.3", ScannedAt: "2026-10-14T09:00:00Z", DurationMs: 1500},
+	}.Output(&buf, "demo", results[:1])
+	var report struct {
+		Verdict Verdict `json:"verdict"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	want := ScanMeta{Version: "1.2.3", ScannedAt: "2026-10-14T09:00:00Z", DurationMs: 1500, Checks: 1, Skipped: 1}
+	if !report.Verdict.Ready || report.Verdict.FailOn != "error" || report.Verdict.Scan != want {
+		t.Errorf("verdict = %+v", report.Verdict)
+	}
+}
+
 func TestCalculateSummary(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -212,3 +275,349 @@ func TestHumanOutputterVerboseDetails(t *testing.T) {
 		t.Error("verbose output omitted Details")
 	}
 }
+
+func TestFormatEffort(t *testing.T) {
+	cases := map[int]string{
+		1:   "1 minute",
+		45:  "45 minutes",
+		60:  "1 hour",
+		120: "2 hours",
+		150: "2h 30m",
+	}
+	for in, want := range cases {
+		if got := FormatEffort(in); got != want {
+			t.Errorf("FormatEffort(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// Only failing checks cost anything; a passing check at any severity must
+// not inflate the estimate.
+func TestRemainingEffortMinutes(t *testing.T) {
+	if got := RemainingEffortMinutes(sampleResults()); got != 90 {
+		t.Errorf("RemainingEffortMinutes = %d, want 90 (ogTwitter 30 + secrets 60)", got)
+	}
+	passed := []checks.CheckResult{{ID: "secrets", Passed: true, Severity: checks.SeverityError}}
+	if got := RemainingEffortMinutes(passed); got != 0 {
+		t.Errorf("RemainingEffortMinutes(all passed) = %d, want 0", got)
+	}
+}
+
+// CI report viewers key off the suite counts and <failure> elements, and
+// each check has to show up as its own test case.
+func TestJUnitOutputter(t *testing.T) {
+	var buf bytes.Buffer
+	JUnitOutputter{}.Output(&buf, "demo", sampleResults())
+	got := buf.String()
+
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<testsuites name="preflight" tests="3" failures="2">`,
+		`<testsuite name="demo" tests="3" failures="2">`,
+		`<testcase name="Canonical URL (canonical)" classname="preflight.canonical">`,
+		`<system-out>Canonical URL configured</system-out>`,
+		`<failure message="og:image too small (64x64, min 200x200)" type="warn">og:image too small (64x64, min 200x200)&#xA;&#xA;Use an image at least 1200x630</failure>`,
+		`<testcase name="OG &amp; Twitter cards (ogTwitter)"`,
+		`type="error">Potential secrets detected</failure>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("JUnit output missing %q\n%s", want, got)
+		}
+	}
+}
+
+func TestJUnitOutputterGroups(t *testing.T) {
+	var buf bytes.Buffer
+	JUnitOutputter{}.OutputGroups(&buf, []ResultGroup{
+		{Name: "apps/web", Results: sampleResults()},
+		{Name: "apps/api", Results: sampleResults()[:1]},
+	})
+	got := buf.String()
+
+	for _, want := range []string{
+		`<testsuites name="preflight" tests="4" failures="2">`,
+		`<testsuite name="apps/web" tests="3" failures="2">`,
+		`<testsuite name="apps/api" tests="1" failures="0">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("JUnit output missing %q\n%s", want, got)
+		}
+	}
+}
+
+func TestMarkdownOutputter(t *testing.T) {
+	var buf bytes.Buffer
+	MarkdownOutputter{}.Output(&buf, "demo", sampleResults())
+	got := buf.String()
+
+	for _, want := range []string{
+		"## ✈️ Preflight: demo",
+		"❌ **Not ready for launch** · 1 passed · 1 warnings · 1 failed",
+		"| ❌ | Secrets scan | SECRETS | Potential secrets detected |",
+		"| ⚠️ | OG &amp; Twitter cards | SOCIAL | og:image too small (64x64, min 200x200) |",
+		"<summary>✅ 1 passed</summary>",
+		"| Canonical URL | SEO | Canonical URL configured |",
+		"<summary>⚠️ <b>OG &amp; Twitter cards</b> <code>ogTwitter</code></summary>",
+		"- Use an image at least 1200x630",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Markdown output missing %q\n%s", want, got)
+		}
+	}
+	// Errors sort ahead of warnings in the attention table.
+	if strings.Index(got, "| ❌ |") > strings.Index(got, "| ⚠️ |") {
+		t.Errorf("error row should come before warning row\n%s", got)
+	}
+}
+
+func TestStatusFile(t *testing.T) {
+	scanned := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	status := StatusMarkdown("demo", sampleResults(), scanned)
+	for _, want := range []string{
+		"## Launch status: demo",
+		"Last scanned: 2026-10-14",
+		"**❌ Not ready for launch** · readiness 50% · 1 passed · 1 warnings · 1 failed",
+		"- ❌ **Secrets scan** (`secrets`): Potential secrets detected\n- ⚠️ **OG &amp; Twitter cards** (`ogTwitter`)",
+	} {
+		if !strings.Contains(status, want) {
+			t.Errorf("status missing %q\n%s", want, status)
+		}
+	}
+	if strings.Contains(status, "Canonical URL") {
+		t.Errorf("status lists a passing check\n%s", status)
+	}
+
+	path := filepath.Join(t.TempDir(), "PREFLIGHT.md")
+	if err := os.WriteFile(path, []byte("# Launch notes\n\nDNS moves on Friday.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if written, err := WriteStatusFile(path, status); err != nil || !written {
+		t.Fatalf("first write: %v, %v", written, err)
+	}
+	// A later scan with the same results only has a new date.
+	if written, err := WriteStatusFile(path, StatusMarkdown("demo", sampleResults(), scanned.AddDate(0, 0, 1))); err != nil || written {
+		t.Errorf("rewrote an unchanged status: %v, %v", written, err)
+	}
+	fixed := sampleResults()[:2]
+	if written, err := WriteStatusFile(path, StatusMarkdown("demo", fixed, scanned.AddDate(0, 0, 2))); err != nil || !written {
+		t.Fatalf("second write: %v, %v", written, err)
+	}
+	got, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(got), "# Launch notes\n\nDNS moves on Friday.\n\n"+statusBegin) ||
+		strings.Count(string(got), statusBegin) != 1 || strings.Contains(string(got), "Secrets scan") ||
+		!strings.Contains(string(got), "Last scanned: 2026-10-16") {
+		t.Errorf("file after update:\n%s", got)
+	}
+}
+
+func TestMarkdownTextEscapesTableCells(t *testing.T) {
+	if got := markdownText("a | b\n<c>"); got != `a \| b<br>&lt;c&gt;` {
+		t.Errorf("markdownText = %q", got)
+	}
+}
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	results := []checks.CheckResult{
+		{
+			ID: "secrets", Title: "Secrets scan", Severity: checks.SeverityError,
+			Locations: []checks.Location{{File: "config/app,prod.js", Line: 12, Message: "Possible Stripe key"}},
+		},
+		{
+			ID: "debug_statements", Title: "Debug statements", Severity: checks.SeverityWarn,
+			Message:   "Found 1 debug statement(s)",
+			Locations: []checks.Location{{File: "src/app.js", Line: 3}},
+		},
+		{
+			// Passing checks never annotate, even with locations.
+			ID: "canonical", Title: "Canonical URL", Passed: true,
+			Locations: []checks.Location{{File: "index.html", Line: 1}},
+		},
+		// Failing without locations: nothing to point at.
+		{ID: "sitemap", Title: "sitemap.xml", Severity: checks.SeverityWarn, Message: "missing"},
+	}
+
+	var buf bytes.Buffer
+	WriteGitHubAnnotations(&buf, results)
+	want := "::error file=config/app%2Cprod.js,line=12,title=Secrets scan (secrets)::Possible Stripe key\n" +
+		"::warning file=src/app.js,line=3,title=Debug statements (debug_statements)::Found 1 debug statement(s)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("annotations:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestEscapeAnnotationData(t *testing.T) {
+	if got := escapeAnnotationData("100% sure\nnext"); got != "100%25 sure%0Anext" {
+		t.Errorf("escapeAnnotationData = %q", got)
+	}
+}
+
+func TestDiffResults(t *testing.T) {
+	before := []checks.CheckResult{
+		{ID: "favicon", Title: "Favicon", Passed: true, Severity: checks.SeverityInfo},
+		{ID: "sitemap", Title: "Sitemap", Passed: false, Severity: checks.SeverityWarn, Message: "missing"},
+		{ID: "debug_statements", Title: "Debug", Passed: false, Severity: checks.SeverityWarn, Message: "3 found"},
+		{ID: "canonical", Title: "Canonical", Passed: true, Message: "ok on 3 pages"},
+		{ID: "llmsTxt", Title: "llms.txt", Passed: true},
+	}
+	after := []checks.CheckResult{
+		{ID: "canonical", Title: "Canonical", Passed: true, Message: "ok on 4 pages"},
+		{ID: "sitemap", Title: "Sitemap", Passed: true, Severity: checks.SeverityInfo},
+		{ID: "debug_statements", Title: "Debug", Passed: false, Severity: checks.SeverityWarn, Message: "12 found"},
+		{ID: "favicon", Title: "Favicon", Passed: false, Severity: checks.SeverityWarn, Message: "no favicon"},
+		{ID: "stripe", Title: "Stripe", Passed: true},
+	}
+
+	diff := DiffResults(before, after)
+	var got []string
+	for _, c := range diff.Changes {
+		got = append(got, string(c.Kind)+":"+c.ID)
+	}
+	want := []string{"regressed:favicon", "changed:debug_statements", "removed:llmsTxt", "added:stripe", "fixed:sitemap"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("changes = %v, want %v", got, want)
+	}
+	// A passing check whose wording changed is not a difference.
+	if diff.Unchanged != 1 {
+		t.Errorf("Unchanged = %d, want 1 (canonical)", diff.Unchanged)
+	}
+	if diff.Regressions() != 1 {
+		t.Errorf("Regressions = %d, want 1", diff.Regressions())
+	}
+}
+
+func TestHTMLOutputter(t *testing.T) {
+	results := append(sampleResults(),
+		checks.CheckResult{ID: "sentry", Title: "Sentry", Passed: true, Message: "DSN configured"},
+		checks.CheckResult{ID: "sitemap", Title: "Sitemap", Passed: true, Message: "No build output, skipping"},
+	)
+	var buf bytes.Buffer
+	HTMLOutputter{
+		GeneratedAt: time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC),
+		Audit:       []audit.Entry{{Time: time.Now(), User: "dev <dev@example.com>", Action: "ignore", CheckID: "secrets", Reason: "fixtures"}},
+	}.Output(&buf, "demo", results)
+	got := buf.String()
+
+	for _, want := range []string{
+		"generated March 1, 2026 at 09:30 UTC",
+		`<div class="verdict fail">Not ready for launch<small>About 1h 30m of fixes remaining</small>`,
+		"<h3>🔑 SECRETS</h3>",
+		"OG &amp; Twitter cards",
+		"<li>Use an image at least 1200x630</li>",
+		"<h2>Services</h2>",
+		"<h3>🐛 ERRORS</h3>",
+		"<h2>Audit log</h2>",
+		"dev &lt;dev@example.com&gt;",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML report missing %q", want)
+		}
+	}
+	// The failing error-severity section sorts ahead of the warning one.
+	if strings.Index(got, "SECRETS</h3>") > strings.Index(got, "SOCIAL</h3>") {
+		t.Error("sections not ordered worst-first")
+	}
+	if strings.Contains(got, "skipping") {
+		t.Error("skipped checks should be left out of the report")
+	}
+	if strings.Contains(got, "<script") || strings.Contains(got, "http://") {
+		t.Error("report must not load scripts or external assets")
+	}
+}
+
+// Unmapped IDs are shown as CUSTOM, so a built-in check without an entry
+// would be filed there by mistake.
+func TestEveryRegisteredCheckHasCategory(t *testing.T) {
+	for _, c := range checks.Registry {
+		if got := Category(c.ID()); got == "CUSTOM" {
+			t.Errorf("check %q has no display category", c.ID())
+		}
+	}
+}
+
+func TestReadinessScoreAndBadge(t *testing.T) {
+	// One pass, one warning, one failure: (1 + 0.5) / 3.
+	results := sampleResults()
+	if got := ReadinessScore(results); got != 50 {
+		t.Errorf("ReadinessScore = %d, want 50", got)
+	}
+	if got := ReadinessScore(nil); got != 100 {
+		t.Errorf("ReadinessScore(nil) = %d, want 100", got)
+	}
+
+	badge := NewBadge(results)
+	if badge.Message != "50%" || badge.Color != "red" {
+		t.Errorf("badge = %+v", badge)
+	}
+	if got := NewBadge(results[:2]).Color; got != "yellow" {
+		t.Errorf("warnings-only color = %s, want yellow", got)
+	}
+	if got := NewBadge(results[:1]); got.Color != "brightgreen" || got.Message != "100%" {
+		t.Errorf("clean badge = %+v", got)
+	}
+
+	// shields.io rejects an endpoint response without these keys.
+	data, err := json.Marshal(badge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"schemaVersion":1,"label":"preflight","message":"50%","color":"red"}`; string(data) != want {
+		t.Errorf("JSON = %s, want %s", data, want)
+	}
+
+	svg := string(badge.SVG())
+	for _, want := range []string{`<svg xmlns="http://www.w3.org/2000/svg"`, `aria-label="preflight: 50%"`, `fill="#e05d44"`, `>50%</text>`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG missing %q:\n%s", want, svg)
+		}
+	}
+}
+
+// diff reads reports saved by earlier versions of the tool, so the reader
+// has to round-trip what JSONOutputter writes.
+func TestReadJSONOutputRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	JSONOutputter{}.Output(&buf, "acme", sampleResults())
+	report, results, err := ReadJSONOutput(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Project != "acme" || report.Summary.Fail != 1 || len(report.Checks) != 3 {
+		t.Errorf("report = %+v", report)
+	}
+	if len(results) != 3 || results[1].ID != "ogTwitter" || results[1].Severity != checks.SeverityWarn || results[1].Passed {
+		t.Errorf("results = %+v", results)
+	}
+	if d := DiffResults(sampleResults(), results); len(d.Changes) != 0 {
+		t.Errorf("re-read results differ from the originals: %+v", d.Changes)
+	}
+
+	for _, bad := range []string{`not json`, `{"project": "x"}`} {
+		if _, _, err := ReadJSONOutput(strings.NewReader(bad)); err == nil {
+			t.Errorf("ReadJSONOutput(%q) accepted a non-report", bad)
+		}
+	}
+}
+
+func TestProfileOutput(t *testing.T) {
+	results := sampleResults()
+	results[0].Profile = &checks.Usage{WallMs: 1500, CPUMs: 40, FilesRead: 1, BytesScanned: 2500, NetworkCalls: 3}
+	results[2].Profile = &checks.Usage{WallMs: 20}
+
+	if got, want := FormatUsage(*results[0].Profile), "1.5s wall, 40ms CPU, 1 file, 2.5 KB, 3 network calls"; got != want {
+		t.Errorf("FormatUsage = %q, want %q", got, want)
+	}
+	slowest := SlowestChecks(results, 5)
+	if len(slowest) != 2 || slowest[0].ID != results[0].ID {
+		t.Errorf("SlowestChecks = %v, want the two profiled checks, slowest first", slowest)
+	}
+
+	var buf bytes.Buffer
+	JSONOutputter{}.Output(&buf, "demo", results)
+	_, back, err := ReadJSONOutput(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back[0].Profile == nil || *back[0].Profile != *results[0].Profile || back[1].Profile != nil {
+		t.Errorf("profiles after JSON round trip = %v, %v", back[0].Profile, back[1].Profile)
+	}
+}
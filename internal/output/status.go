@@ -0,0 +1,120 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/checks"
+)
+
+// The status block sits between these markers, so the file it's written
+// to can hold other notes that survive each rewrite.
+const (
+	statusBegin = "<!-- preflight:status -->"
+	statusEnd   = "<!-- /preflight:status -->"
+)
+
+// statusDatePrefix starts the line carrying the scan date, which is left
+// out when deciding whether the status changed.
+const statusDatePrefix = "Last scanned: "
+
+// StatusMarkdown renders the launch status a repository keeps in a file
+// like PREFLIGHT.md (scan --write-summary): the date, the readiness
+// score and verdict, and every check still outstanding, errors first.
+// Unlike the Markdown report it leaves passing checks out, so the file
+// only changes in a diff when the status does.
+func StatusMarkdown(projectName string, results []checks.CheckResult, scanned time.Time) string {
+	summary := CalculateSummary(results)
+	verdict := "✅ Ready for launch"
+	switch {
+	case summary.Fail > 0:
+		verdict = "❌ Not ready for launch"
+	case summary.Warn > 0:
+		verdict = "⚠️ Review warnings before launch"
+	}
+
+	var b strings.Builder
+	fmt.Fprintln(&b, statusBegin)
+	fmt.Fprintf(&b, "## Launch status: %s\n\n", markdownText(projectName))
+	fmt.Fprintf(&b, "%s%s\n\n", statusDatePrefix, scanned.Format(time.DateOnly))
+	fmt.Fprintf(&b, "**%s** · readiness %d%% · %d passed · %d warnings · %d failed", verdict, ReadinessScore(results), summary.OK, summary.Warn, summary.Fail)
+	if effort := RemainingEffortMinutes(results); effort > 0 {
+		fmt.Fprintf(&b, " · about %s of fixes", FormatEffort(effort))
+	}
+	fmt.Fprint(&b, "\n\n")
+
+	var outstanding []checks.CheckResult
+	for _, r := range results {
+		if !r.Passed {
+			outstanding = append(outstanding, r)
+		}
+	}
+	sort.SliceStable(outstanding, func(i, j int) bool {
+		return markdownRank(outstanding[i]) > markdownRank(outstanding[j])
+	})
+	if len(outstanding) == 0 {
+		fmt.Fprint(&b, "Nothing outstanding.\n\n")
+	} else {
+		fmt.Fprint(&b, "### Outstanding\n\n")
+		for _, r := range outstanding {
+			fmt.Fprintf(&b, "- %s **%s** (`%s`): %s\n", markdownIcon(r), markdownText(r.Title), r.ID, markdownText(r.Message))
+		}
+		fmt.Fprintln(&b)
+	}
+	fmt.Fprint(&b, "<sub>Updated by `preflight scan --write-summary`</sub>\n")
+	fmt.Fprintln(&b, statusEnd)
+	return b.String()
+}
+
+// WriteStatusFile puts status (from StatusMarkdown) in the file at path.
+// A file already holding a status block has just that block replaced; one
+// without gets it appended. When the status is the same as before apart
+// from the date, the file is left alone, so rescanning an unchanged tree
+// doesn't show up as a change. It reports whether it wrote the file.
+func WriteStatusFile(path, status string) (bool, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+
+	var updated []byte
+	begin := bytes.Index(existing, []byte(statusBegin))
+	end := bytes.Index(existing, []byte(statusEnd))
+	switch {
+	case begin >= 0 && end > begin:
+		end += len(statusEnd)
+		if bytes.HasPrefix(existing[end:], []byte("\n")) {
+			end++
+		}
+		if withoutStatusDate(string(existing[begin:end])) == withoutStatusDate(status) {
+			return false, nil
+		}
+		updated = append(append(append(updated, existing[:begin]...), status...), existing[end:]...)
+	case len(bytes.TrimSpace(existing)) == 0:
+		updated = []byte(status)
+	default:
+		updated = append(bytes.TrimRight(existing, "\n"), "\n\n"...)
+		updated = append(updated, status...)
+	}
+	if err := os.WriteFile(path, updated, 0o644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func withoutStatusDate(block string) string {
+	lines := strings.Split(block, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(line, statusDatePrefix) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"github.com/preflightsh/preflight/internal/checks"
+)
+
+// Verdict is the launch decision for a scan, for deploy scripts to gate
+// on (jq -e .verdict.ready) instead of re-deriving it from the checks.
+// Ready follows the same failOn policy as scan's exit code: by default a
+// failing check at warn or error severity blocks, and with failOn: error
+// only errors do. Failing info checks never block.
+type Verdict struct {
+	Ready  bool   `json:"ready"`
+	FailOn string `json:"failOn"`
+	// Blocking lists the IDs of the checks that make the scan not ready,
+	// errors first. It's [] rather than absent when there are none.
+	Blocking []string       `json:"blocking"`
+	Counts   SeverityCounts `json:"counts"`
+	// Score is the readiness score the badge shows (ReadinessScore).
+	Score int      `json:"score"`
+	Scan  ScanMeta `json:"scan"`
+}
+
+// SeverityCounts tallies a scan's checks: the passing ones, and the
+// failing ones by severity.
+type SeverityCounts struct {
+	Passed int `json:"passed"`
+	Error  int `json:"error"`
+	Warn   int `json:"warn"`
+	Info   int `json:"info"`
+}
+
+// ScanMeta describes the scan a verdict is for. The fields besides Checks
+// are filled in by the caller that ran the scan (scan --format json sets
+// them all); a report built from saved results has only what it knows.
+type ScanMeta struct {
+	Version string `json:"version,omitempty"`
+	// ScannedAt is when the scan ran, in RFC 3339.
+	ScannedAt  string `json:"scannedAt,omitempty"`
+	DurationMs int64  `json:"durationMs,omitempty"`
+	// Checks is the number of checks that ran.
+	Checks int `json:"checks"`
+	// Skipped is the number of checks a time-boxed scan didn't get to.
+	// The verdict only covers the ones that ran.
+	Skipped int  `json:"skipped,omitempty"`
+	Cached  bool `json:"cached,omitempty"`
+}
+
+// NewVerdict decides whether results are ready for launch under failOn
+// ("warn", the default when empty, or "error"; see config.ValidateFailOn).
+func NewVerdict(results []checks.CheckResult, failOn string) Verdict {
+	if failOn == "" {
+		failOn = "warn"
+	}
+	v := Verdict{
+		FailOn:   failOn,
+		Blocking: []string{},
+		Score:    ReadinessScore(results),
+		Scan:     ScanMeta{Checks: len(results)},
+	}
+	var warnings []string
+	for _, r := range results {
+		if r.Passed {
+			v.Counts.Passed++
+			continue
+		}
+		switch r.Severity {
+		case checks.SeverityError:
+			v.Counts.Error++
+			v.Blocking = append(v.Blocking, r.ID)
+		case checks.SeverityWarn:
+			v.Counts.Warn++
+			if failOn != "error" {
+				warnings = append(warnings, r.ID)
+			}
+		default:
+			v.Counts.Info++
+		}
+	}
+	v.Blocking = append(v.Blocking, warnings...)
+	v.Ready = len(v.Blocking) == 0
+	return v
+}
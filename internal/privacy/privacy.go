@@ -0,0 +1,236 @@
+// Package privacy builds a starter data-processing inventory (the record
+// of processing activities GDPR Article 30 asks for) from the third-party
+// services a project uses. It knows what personal data each kind of
+// service typically receives, not what this project actually sends, so
+// its output is a draft for the privacy review to correct rather than a
+// finished record.
+package privacy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+// Processor is a third-party service that receives personal data.
+type Processor struct {
+	ServiceID string
+	Name      string
+	Category  string
+	// Data overrides the category's usual personal data, for services
+	// that collect more (session replay) or less (cookieless analytics).
+	Data []string
+	Note string
+}
+
+// category is what a kind of processor is for and what it usually gets.
+type category struct {
+	title   string
+	purpose string
+	data    []string
+}
+
+var categories = map[string]category{
+	"payments": {"Payments", "Taking payments and managing subscriptions",
+		[]string{"name", "email", "billing address", "payment card details", "IP address", "purchase history"}},
+	"errors": {"Error tracking & monitoring", "Diagnosing errors and performance problems",
+		[]string{"IP address", "user ID and email when attached to errors", "browser and device details", "request URLs and payloads"}},
+	"email": {"Email", "Sending transactional and marketing email",
+		[]string{"email address", "name", "message content", "open and click activity"}},
+	"analytics": {"Analytics", "Measuring site usage",
+		[]string{"IP address", "cookie or device identifiers", "pages viewed", "referrer", "browser and device details"}},
+	"auth": {"Authentication", "Signing users in and managing accounts",
+		[]string{"email address", "name", "password hash or social login profile", "IP address", "login history"}},
+	"communication": {"Communication & support", "Messaging and customer support",
+		[]string{"name", "email address", "phone number", "message content"}},
+	"ai": {"AI providers", "Generating responses with hosted AI models",
+		[]string{"prompt content, including any personal data users type or the app adds"}},
+	"flags": {"Feature flags", "Deciding which features each user sees",
+		[]string{"user ID", "email and other attributes used for targeting", "IP address"}},
+	"consent": {"Cookie consent", "Recording cookie consent",
+		[]string{"IP address", "consent choices", "browser details"}},
+}
+
+// categoryOrder is the report order of the categories.
+var categoryOrder = []string{"payments", "auth", "email", "analytics", "flags", "errors", "communication", "ai", "consent"}
+
+var sessionReplayData = []string{"session recordings (clicks, scrolling, typed text unless masked)", "IP address", "browser and device details", "pages viewed"}
+
+var cookielessData = []string{"pages viewed", "referrer", "browser and device details (aggregated; IP addresses are hashed or discarded)"}
+
+// Processors lists the supported services that receive personal data.
+// Infrastructure the project runs itself (Redis, Sidekiq, Elasticsearch)
+// isn't a third party and is left out.
+var Processors = []Processor{
+	{ServiceID: "stripe", Name: "Stripe", Category: "payments"},
+	{ServiceID: "paypal", Name: "PayPal", Category: "payments"},
+	{ServiceID: "braintree", Name: "Braintree", Category: "payments"},
+	{ServiceID: "paddle", Name: "Paddle", Category: "payments", Note: "Merchant of record: a controller for the sale itself"},
+	{ServiceID: "lemonsqueezy", Name: "Lemon Squeezy", Category: "payments", Note: "Merchant of record: a controller for the sale itself"},
+
+	{ServiceID: "sentry", Name: "Sentry", Category: "errors"},
+	{ServiceID: "bugsnag", Name: "Bugsnag", Category: "errors"},
+	{ServiceID: "rollbar", Name: "Rollbar", Category: "errors"},
+	{ServiceID: "honeybadger", Name: "Honeybadger", Category: "errors"},
+	{ServiceID: "datadog", Name: "Datadog", Category: "errors"},
+	{ServiceID: "newrelic", Name: "New Relic", Category: "errors"},
+	{ServiceID: "logrocket", Name: "LogRocket", Category: "errors", Data: sessionReplayData},
+
+	{ServiceID: "postmark", Name: "Postmark", Category: "email"},
+	{ServiceID: "sendgrid", Name: "SendGrid", Category: "email"},
+	{ServiceID: "mailgun", Name: "Mailgun", Category: "email"},
+	{ServiceID: "aws_ses", Name: "Amazon SES", Category: "email"},
+	{ServiceID: "resend", Name: "Resend", Category: "email"},
+	{ServiceID: "mailchimp", Name: "Mailchimp", Category: "email"},
+	{ServiceID: "convertkit", Name: "Kit (ConvertKit)", Category: "email"},
+	{ServiceID: "beehiiv", Name: "beehiiv", Category: "email"},
+	{ServiceID: "aweber", Name: "AWeber", Category: "email"},
+	{ServiceID: "activecampaign", Name: "ActiveCampaign", Category: "email"},
+	{ServiceID: "campaignmonitor", Name: "Campaign Monitor", Category: "email"},
+	{ServiceID: "drip", Name: "Drip", Category: "email"},
+	{ServiceID: "klaviyo", Name: "Klaviyo", Category: "email"},
+	{ServiceID: "buttondown", Name: "Buttondown", Category: "email"},
+
+	{ServiceID: "plausible", Name: "Plausible", Category: "analytics", Data: cookielessData},
+	{ServiceID: "fathom", Name: "Fathom", Category: "analytics", Data: cookielessData},
+	{ServiceID: "umami", Name: "Umami", Category: "analytics", Data: cookielessData},
+	{ServiceID: "fullres", Name: "Fullres", Category: "analytics"},
+	{ServiceID: "datafast", Name: "DataFast", Category: "analytics"},
+	{ServiceID: "google_analytics", Name: "Google Analytics", Category: "analytics"},
+	{ServiceID: "posthog", Name: "PostHog", Category: "analytics", Note: "Session replay, if enabled, records what users see and type"},
+	{ServiceID: "mixpanel", Name: "Mixpanel", Category: "analytics"},
+	{ServiceID: "amplitude", Name: "Amplitude", Category: "analytics"},
+	{ServiceID: "segment", Name: "Segment", Category: "analytics", Note: "Forwards events to every destination configured in Segment; list those too"},
+	{ServiceID: "hotjar", Name: "Hotjar", Category: "analytics", Data: sessionReplayData},
+
+	{ServiceID: "launchdarkly", Name: "LaunchDarkly", Category: "flags"},
+	{ServiceID: "flagsmith", Name: "Flagsmith", Category: "flags", Note: "Leave out if self-hosted"},
+	{ServiceID: "unleash", Name: "Unleash", Category: "flags", Note: "Leave out if self-hosted"},
+
+	{ServiceID: "auth0", Name: "Auth0", Category: "auth"},
+	{ServiceID: "clerk", Name: "Clerk", Category: "auth"},
+	{ServiceID: "workos", Name: "WorkOS", Category: "auth"},
+	{ServiceID: "firebase", Name: "Firebase", Category: "auth", Note: "Firestore or Realtime Database, if used, may hold any user data"},
+	{ServiceID: "supabase", Name: "Supabase", Category: "auth", Note: "The Supabase database may hold any user data"},
+
+	{ServiceID: "twilio", Name: "Twilio", Category: "communication"},
+	{ServiceID: "intercom", Name: "Intercom", Category: "communication"},
+	{ServiceID: "crisp", Name: "Crisp", Category: "communication"},
+
+	{ServiceID: "openai", Name: "OpenAI", Category: "ai"},
+	{ServiceID: "anthropic", Name: "Anthropic", Category: "ai"},
+	{ServiceID: "google_ai", Name: "Google AI (Gemini)", Category: "ai"},
+	{ServiceID: "mistral", Name: "Mistral", Category: "ai"},
+	{ServiceID: "cohere", Name: "Cohere", Category: "ai"},
+	{ServiceID: "replicate", Name: "Replicate", Category: "ai"},
+	{ServiceID: "huggingface", Name: "Hugging Face", Category: "ai"},
+	{ServiceID: "grok", Name: "xAI (Grok)", Category: "ai"},
+	{ServiceID: "perplexity", Name: "Perplexity", Category: "ai"},
+	{ServiceID: "together_ai", Name: "Together AI", Category: "ai"},
+
+	{ServiceID: "cookieconsent", Name: "CookieConsent", Category: "consent"},
+	{ServiceID: "cookiebot", Name: "Cookiebot", Category: "consent"},
+	{ServiceID: "onetrust", Name: "OneTrust", Category: "consent"},
+	{ServiceID: "termly", Name: "Termly", Category: "consent"},
+	{ServiceID: "cookieyes", Name: "CookieYes", Category: "consent"},
+	{ServiceID: "iubenda", Name: "iubenda", Category: "consent"},
+}
+
+// Entry is one processor the project uses.
+type Entry struct {
+	Processor
+	// Declared is set when preflight.yml declares the service, Detected
+	// when the project's dependencies, env keys or pages mention it.
+	Declared bool
+	Detected bool
+}
+
+// PersonalData is the personal data the processor likely receives.
+func (p Processor) PersonalData() []string {
+	if p.Data != nil {
+		return p.Data
+	}
+	return categories[p.Category].data
+}
+
+// Inventory lists the processors among the services declared in cfg or
+// detected in the project, in report order.
+func Inventory(cfg *config.PreflightConfig, detected map[string]bool) []Entry {
+	var out []Entry
+	for _, cat := range categoryOrder {
+		for _, p := range Processors {
+			if p.Category != cat {
+				continue
+			}
+			e := Entry{Processor: p, Declared: cfg.Services[p.ServiceID].Declared, Detected: detected[p.ServiceID]}
+			if e.Declared || e.Detected {
+				out = append(out, e)
+			}
+		}
+	}
+	return out
+}
+
+// WriteMarkdown renders entries as a Markdown document to start the
+// privacy review from: a summary table, a section per processor with the
+// questions the review has to answer, and the gaps a draft can't fill.
+func WriteMarkdown(w io.Writer, projectName string, entries []Entry, generated time.Time) {
+	fmt.Fprintf(w, "# Data processing inventory: %s\n\n", projectName)
+	fmt.Fprintf(w, "_Draft generated by preflight on %s from the services the project declares or uses. "+
+		"Personal data listed is what each kind of service typically receives; confirm it against what "+
+		"the app actually sends, and against each processor's data processing agreement (DPA)._\n\n",
+		generated.Format("2006-01-02"))
+
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No third-party services that receive personal data were found.")
+		fmt.Fprintln(w, "Declare the ones preflight can't detect under `services:` in preflight.yml.")
+		return
+	}
+
+	fmt.Fprintln(w, "| Processor | Category | Purpose | Personal data (likely) | Found in |")
+	fmt.Fprintln(w, "|---|---|---|---|---|")
+	for _, e := range entries {
+		cat := categories[e.Category]
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n", e.Name, cat.title, cat.purpose,
+			strings.Join(e.PersonalData(), ", "), e.source())
+	}
+	fmt.Fprintln(w)
+
+	for _, e := range entries {
+		cat := categories[e.Category]
+		fmt.Fprintf(w, "## %s\n\n", e.Name)
+		fmt.Fprintf(w, "- **Purpose:** %s\n", cat.purpose)
+		fmt.Fprintf(w, "- **Personal data (likely):** %s\n", strings.Join(e.PersonalData(), ", "))
+		if e.Note != "" {
+			fmt.Fprintf(w, "- **Note:** %s\n", e.Note)
+		}
+		fmt.Fprintln(w, "- **Lawful basis:** _to fill in (contract, consent, legitimate interests...)_")
+		fmt.Fprintln(w, "- **Data subjects:** _to fill in (customers, visitors, newsletter subscribers...)_")
+		fmt.Fprintln(w, "- **Retention:** _to fill in_")
+		fmt.Fprintln(w, "- **Processing location and transfer mechanism:** _to fill in (EU region, SCCs, Data Privacy Framework...)_")
+		fmt.Fprintln(w, "- [ ] DPA signed or accepted")
+		fmt.Fprintln(w, "- [ ] Listed in the privacy policy")
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "## Not covered by this draft")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "- Your own database, logs and backups, and where they are hosted")
+	fmt.Fprintln(w, "- Hosting, CDN and DNS providers, which see every visitor's IP address")
+	fmt.Fprintln(w, "- Services called without an SDK or env key preflight recognizes")
+	fmt.Fprintln(w, "- Embedded third-party content (videos, maps, fonts, social widgets)")
+}
+
+func (e Entry) source() string {
+	switch {
+	case e.Declared && e.Detected:
+		return "preflight.yml, project files"
+	case e.Declared:
+		return "preflight.yml"
+	default:
+		return "project files (not declared)"
+	}
+}
@@ -0,0 +1,58 @@
+package privacy
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/preflightsh/preflight/internal/config"
+)
+
+func TestProcessorsAreKnownServices(t *testing.T) {
+	for _, p := range Processors {
+		if !slices.Contains(config.AllServices, p.ServiceID) {
+			t.Errorf("%s: not in config.AllServices", p.ServiceID)
+		}
+		if _, ok := categories[p.Category]; !ok || !slices.Contains(categoryOrder, p.Category) {
+			t.Errorf("%s: unknown category %q", p.ServiceID, p.Category)
+		}
+	}
+}
+
+func TestInventory(t *testing.T) {
+	cfg := &config.PreflightConfig{Services: map[string]config.ServiceConfig{
+		"sentry": {Declared: true},
+		"stripe": {Declared: true},
+		"redis":  {Declared: true},
+	}}
+	entries := Inventory(cfg, map[string]bool{"stripe": true, "plausible": true})
+
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.ServiceID)
+	}
+	// Report order is by category (payments, analytics, errors); Redis is
+	// the project's own infrastructure.
+	if want := []string{"stripe", "plausible", "sentry"}; !slices.Equal(ids, want) {
+		t.Fatalf("entries = %v, want %v", ids, want)
+	}
+
+	var b strings.Builder
+	WriteMarkdown(&b, "demo", entries, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	doc := b.String()
+	for _, want := range []string{
+		"# Data processing inventory: demo",
+		"on 2026-10-01",
+		"| Stripe | Payments | Taking payments and managing subscriptions | name, email, billing address",
+		"| preflight.yml, project files |",
+		"| Plausible | Analytics |",
+		"| project files (not declared) |",
+		"## Sentry",
+		"- [ ] DPA signed or accepted",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("markdown missing %q:\n%s", want, doc)
+		}
+	}
+}
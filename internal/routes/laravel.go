@@ -0,0 +1,128 @@
+package routes
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	reLaravelRoute  = regexp.MustCompile(`Route::(get|post|put|patch|delete|any|match|view|redirect|permanentRedirect|resource|apiResource)\(\s*(?:\[[^\]]*\]\s*,\s*)?["']([^"']*)["']`)
+	reLaravelPrefix = regexp.MustCompile(`(?:prefix\(\s*|["']prefix["']\s*=>\s*)["']([^"']*)["']`)
+	reLaravelAuthMW = regexp.MustCompile(`(?:middleware\(|["']middleware["']\s*=>)[^)]*["'](auth(?::\w+)?|verified|can:[^"']*)["']`)
+	reLaravelGroup  = regexp.MustCompile(`->group\(|Route::group\(`)
+	reLaravelParam  = regexp.MustCompile(`\{(\w+)\??\}`)
+)
+
+// laravelFrame is one open Route group closure.
+type laravelFrame struct {
+	depth  int // brace depth inside the closure
+	prefix string
+	auth   bool
+}
+
+func extractLaravel(rootDir string) []Route {
+	if readFile(filepath.Join(rootDir, "artisan")) == "" {
+		return nil
+	}
+	var out []Route
+	out = append(out, laravelRouteFile(rootDir, "routes/web.php", "", false)...)
+	// RouteServiceProvider mounts api.php under /api by default.
+	out = append(out, laravelRouteFile(rootDir, "routes/api.php", "/api", true)...)
+	return out
+}
+
+// laravelRouteFile reads one routes file. Statements are gathered from a
+// `Route::` call up to the `;` that ends them (or the closure that opens a
+// group), so chained options spread over several lines are seen together.
+func laravelRouteFile(rootDir, name, basePrefix string, api bool) []Route {
+	path := filepath.Join(rootDir, filepath.FromSlash(name))
+	content := readFile(path)
+	if content == "" {
+		return nil
+	}
+
+	var out []Route
+	stack := []laravelFrame{{depth: 0, prefix: basePrefix}}
+	depth := 0
+	var stmt strings.Builder
+	stmtLine := 0
+
+	for i, raw := range strings.Split(content, "\n") {
+		line := raw
+		if j := strings.Index(line, "//"); j >= 0 && !strings.Contains(line[:j], "'") && !strings.Contains(line[:j], `"`) {
+			line = line[:j]
+		}
+		if strings.Contains(line, "Route::") && stmt.Len() == 0 {
+			stmtLine = i + 1
+		}
+		if stmtLine > 0 {
+			stmt.WriteString(line)
+			stmt.WriteString(" ")
+		}
+
+		// Route params like {id} open and close on the same line, so only
+		// closures move the net depth.
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+
+		if stmtLine > 0 {
+			s := stmt.String()
+			opensGroup := reLaravelGroup.MatchString(s) && strings.Contains(line, "{")
+			if opensGroup || strings.Contains(line, ";") {
+				top := stack[len(stack)-1]
+				auth := top.auth || reLaravelAuthMW.MatchString(s)
+				if opensGroup {
+					prefix := top.prefix
+					if m := reLaravelPrefix.FindStringSubmatch(s); m != nil {
+						prefix = joinPath(top.prefix, m[1])
+					}
+					stack = append(stack, laravelFrame{depth: depth, prefix: prefix, auth: auth})
+				} else {
+					source := fmt.Sprintf("%s:%d", name, stmtLine)
+					out = append(out, laravelRoutes(s, top.prefix, auth, api, source)...)
+				}
+				stmt.Reset()
+				stmtLine = 0
+			}
+		}
+
+		for len(stack) > 1 && depth < stack[len(stack)-1].depth {
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return out
+}
+
+// laravelRoutes turns one Route:: statement into routes.
+func laravelRoutes(stmt, prefix string, auth, api bool, source string) []Route {
+	m := reLaravelRoute.FindStringSubmatch(stmt)
+	if m == nil {
+		return nil
+	}
+	verb, path := m[1], m[2]
+	full := laravelPath(joinPath(prefix, path))
+	mk := func(method, p string) Route {
+		return Route{Path: p, Method: method, Source: source, Dynamic: isDynamic(p), API: api, Auth: auth}
+	}
+
+	switch verb {
+	case "view", "redirect", "permanentRedirect":
+		return []Route{mk("GET", full)}
+	case "any", "match":
+		return []Route{mk("ANY", full)}
+	case "resource", "apiResource":
+		param := ":" + strings.TrimSuffix(filepath.Base(full), "s")
+		routes := []Route{mk("GET", full), mk("GET", full+"/"+param)}
+		if verb == "resource" {
+			routes = append(routes, mk("GET", full+"/create"))
+		}
+		return routes
+	}
+	return []Route{mk(strings.ToUpper(verb), full)}
+}
+
+// laravelPath rewrites {id} and {id?} parameters as :id.
+func laravelPath(p string) string {
+	return reLaravelParam.ReplaceAllString(p, ":$1")
+}
@@ -0,0 +1,206 @@
+package routes
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	nextPageExts = map[string]bool{".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mdx": true}
+
+	// Route handler exports: `export async function GET(`, `export const POST =`.
+	reNextHandlerMethod = regexp.MustCompile(`export\s+(?:async\s+)?(?:function|const)\s+(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)\b`)
+
+	// middleware.ts `config.matcher`, as a string or an array of strings.
+	reNextMatcher = regexp.MustCompile(`matcher\s*:\s*(\[[^\]]*\]|"[^"]*"|'[^']*')`)
+	// Clerk-style `const isProtectedRoute = createRouteMatcher([...])`.
+	reNextRouteMatcher = regexp.MustCompile(`(\w+)\s*=\s*createRouteMatcher\(\s*\[([^\]]*)\]`)
+	reQuoted           = regexp.MustCompile(`["']([^"']+)["']`)
+	reNextAuthHint     = regexp.MustCompile(`(?i)auth|session|clerk|getToken|/login|/sign-?in`)
+)
+
+// nextSpecialPages are pages-router files that aren't routes.
+var nextSpecialPages = map[string]bool{"_app": true, "_document": true, "_error": true, "404": true, "500": true}
+
+func extractNext(rootDir string) []Route {
+	var found []Route
+	for _, dir := range []string{"app", "src/app"} {
+		found = append(found, nextAppRoutes(rootDir, filepath.Join(rootDir, dir))...)
+	}
+	for _, dir := range []string{"pages", "src/pages"} {
+		found = append(found, nextPagesRoutes(rootDir, filepath.Join(rootDir, dir))...)
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	protected := nextProtectedPrefixes(rootDir)
+	for i := range found {
+		for _, prefix := range protected {
+			if found[i].Path == prefix || strings.HasPrefix(found[i].Path, strings.TrimSuffix(prefix, "/")+"/") {
+				found[i].Auth = true
+				break
+			}
+		}
+	}
+	return found
+}
+
+// nextAppRoutes walks an App Router directory for page and route files.
+func nextAppRoutes(rootDir, appDir string) []Route {
+	if info, err := os.Stat(appDir); err != nil || !info.IsDir() {
+		return nil
+	}
+	var out []Route
+	_ = filepath.WalkDir(appDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() {
+			// Private folders and intercepting routes don't map to URLs.
+			if path != appDir && (strings.HasPrefix(name, "_") || strings.HasPrefix(name, "(.") || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := filepath.Ext(name)
+		base := strings.TrimSuffix(name, ext)
+		if !nextPageExts[ext] || (base != "page" && base != "route") {
+			return nil
+		}
+
+		relDir, _ := filepath.Rel(appDir, filepath.Dir(path))
+		var segs []string
+		for _, seg := range strings.Split(filepath.ToSlash(relDir), "/") {
+			switch {
+			case seg == "." || seg == "":
+			case strings.HasPrefix(seg, "(") && strings.HasSuffix(seg, ")"):
+				// Route group: organizes files, not URLs.
+			case strings.HasPrefix(seg, "@"):
+				// Parallel route slot.
+			default:
+				segs = append(segs, nextSegment(seg))
+			}
+		}
+		routePath := joinPath(segs...)
+
+		if base == "page" {
+			out = append(out, Route{Path: routePath, Method: "GET", Source: rel(rootDir, path), Dynamic: isDynamic(routePath)})
+			return nil
+		}
+		methods := reNextHandlerMethod.FindAllStringSubmatch(readFile(path), -1)
+		if len(methods) == 0 {
+			out = append(out, Route{Path: routePath, Method: "ANY", Source: rel(rootDir, path), Dynamic: isDynamic(routePath), API: true})
+		}
+		for _, m := range methods {
+			out = append(out, Route{Path: routePath, Method: m[1], Source: rel(rootDir, path), Dynamic: isDynamic(routePath), API: true})
+		}
+		return nil
+	})
+	return out
+}
+
+// nextPagesRoutes walks a Pages Router directory.
+func nextPagesRoutes(rootDir, pagesDir string) []Route {
+	if info, err := os.Stat(pagesDir); err != nil || !info.IsDir() {
+		return nil
+	}
+	var out []Route
+	_ = filepath.WalkDir(pagesDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		ext := filepath.Ext(name)
+		if !nextPageExts[ext] {
+			return nil
+		}
+		relPath, _ := filepath.Rel(pagesDir, path)
+		relPath = strings.TrimSuffix(filepath.ToSlash(relPath), ext)
+		if nextSpecialPages[relPath] {
+			return nil
+		}
+
+		var segs []string
+		for _, seg := range strings.Split(relPath, "/") {
+			segs = append(segs, nextSegment(seg))
+		}
+		if segs[len(segs)-1] == "index" {
+			segs = segs[:len(segs)-1]
+		}
+		routePath := joinPath(segs...)
+		api := routePath == "/api" || strings.HasPrefix(routePath, "/api/")
+		method := "GET"
+		if api {
+			method = "ANY"
+		}
+		out = append(out, Route{Path: routePath, Method: method, Source: rel(rootDir, path), Dynamic: isDynamic(routePath), API: api})
+		return nil
+	})
+	return out
+}
+
+// nextSegment converts a file-system segment to route syntax:
+// [id] → :id, [...slug] and [[...slug]] → :slug*.
+func nextSegment(seg string) string {
+	if strings.HasPrefix(seg, "[") && strings.HasSuffix(seg, "]") {
+		inner := strings.Trim(seg, "[]")
+		if strings.HasPrefix(inner, "...") {
+			return ":" + strings.TrimPrefix(inner, "...") + "*"
+		}
+		return ":" + inner
+	}
+	return seg
+}
+
+// nextProtectedPrefixes reads middleware.ts for the paths it guards. Only
+// middleware that looks auth-related counts, and only explicit matchers
+// are trusted: a middleware with no matcher runs everywhere, including
+// pages that handle signed-out visitors themselves.
+func nextProtectedPrefixes(rootDir string) []string {
+	var content string
+	for _, name := range []string{"middleware.ts", "middleware.js", "src/middleware.ts", "src/middleware.js"} {
+		if content = readFile(filepath.Join(rootDir, name)); content != "" {
+			break
+		}
+	}
+	if content == "" || !reNextAuthHint.MatchString(content) {
+		return nil
+	}
+
+	var patterns []string
+	for _, m := range reNextRouteMatcher.FindAllStringSubmatch(content, -1) {
+		if strings.Contains(strings.ToLower(m[1]), "protect") || strings.Contains(strings.ToLower(m[1]), "private") {
+			for _, q := range reQuoted.FindAllStringSubmatch(m[2], -1) {
+				patterns = append(patterns, q[1])
+			}
+		}
+	}
+	if len(patterns) == 0 {
+		if m := reNextMatcher.FindStringSubmatch(content); m != nil {
+			for _, q := range reQuoted.FindAllStringSubmatch(m[1], -1) {
+				patterns = append(patterns, q[1])
+			}
+		}
+	}
+
+	var prefixes []string
+	for _, p := range patterns {
+		// Negative-lookahead matchers exclude paths rather than name them.
+		if strings.Contains(p, "(?!") {
+			continue
+		}
+		// "/dashboard/:path*", "/admin(.*)" → "/dashboard", "/admin".
+		if i := strings.IndexAny(p, ":(*"); i >= 0 {
+			p = p[:i]
+		}
+		p = strings.TrimSuffix(p, "/")
+		if p != "" {
+			prefixes = append(prefixes, p)
+		}
+	}
+	return prefixes
+}
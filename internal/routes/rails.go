@@ -0,0 +1,207 @@
+package routes
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	reRailsRoot     = regexp.MustCompile(`^root\s*(?:\(\s*)?(?:to:\s*|["']?)["']?([\w/]+)#(\w+)`)
+	reRailsVerb     = regexp.MustCompile(`^(get|post|put|patch|delete|match)\s*\(?\s*["']([^"']*)["'](.*)`)
+	reRailsTo       = regexp.MustCompile(`(?:to:\s*|=>\s*)["']([\w/]+)#(\w+)["']`)
+	reRailsResource = regexp.MustCompile(`^(resources?)\s*\(?\s*:(\w+)(.*)`)
+	reRailsOnly     = regexp.MustCompile(`(only|except):\s*(?:%i\[([^\]]*)\]|\[([^\]]*)\]|:(\w+))`)
+	reRailsNS       = regexp.MustCompile(`^namespace\s*\(?\s*:(\w+)`)
+	reRailsScope    = regexp.MustCompile(`^scope\s*\(?\s*(?:path:\s*)?["']([^"']+)["']`)
+	reRailsAuth     = regexp.MustCompile(`^authenticated?\b`)
+	reRailsBlock    = regexp.MustCompile(`\bdo(\s*\|[^|]*\|)?\s*$`)
+
+	reRailsBeforeAuth = regexp.MustCompile(`(skip_)?before_action\s+:(authenticate\w*!?|require_(?:login|user|authentication)\w*|ensure_\w*logged_in\w*)(.*)`)
+)
+
+// railsFrame is one open `do ... end` block in routes.rb.
+type railsFrame struct {
+	path       string // URL prefix this block adds
+	controller string // controller namespace this block adds ("admin/")
+	auth       bool
+}
+
+// railsAction remembers which controller action a route maps to, so auth
+// filters in the controller can be applied afterwards.
+type railsAction struct {
+	index      int
+	controller string
+	action     string
+}
+
+func extractRails(rootDir string) []Route {
+	routesFile := filepath.Join(rootDir, "config", "routes.rb")
+	content := readFile(routesFile)
+	if content == "" {
+		return nil
+	}
+	source := rel(rootDir, routesFile)
+
+	var out []Route
+	var actions []railsAction
+	stack := []railsFrame{{}}
+	add := func(method, path string, line int, controller, action string) {
+		top := stack[len(stack)-1]
+		full := joinPath(top.path, path)
+		out = append(out, Route{
+			Path:    full,
+			Method:  strings.ToUpper(method),
+			Source:  fmt.Sprintf("%s:%d", source, line),
+			Dynamic: isDynamic(full),
+			API:     strings.HasPrefix(full, "/api/") || full == "/api",
+			Auth:    top.auth,
+		})
+		if controller != "" {
+			actions = append(actions, railsAction{len(out) - 1, top.controller + controller, action})
+		}
+	}
+
+	for i, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+		if j := strings.Index(line, " #"); j >= 0 {
+			line = strings.TrimSpace(line[:j])
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lineNo := i + 1
+		top := stack[len(stack)-1]
+		opens := reRailsBlock.MatchString(line)
+		frame := railsFrame{path: top.path, controller: top.controller, auth: top.auth}
+
+		switch {
+		case line == "end" || strings.HasPrefix(line, "end "):
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		case reRailsRoot.MatchString(line):
+			m := reRailsRoot.FindStringSubmatch(line)
+			add("GET", "/", lineNo, m[1], m[2])
+		case reRailsVerb.MatchString(line):
+			m := reRailsVerb.FindStringSubmatch(line)
+			verb, path, rest := m[1], m[2], m[3]
+			if verb == "match" {
+				verb = "ANY"
+			}
+			controller, action := "", ""
+			if to := reRailsTo.FindStringSubmatch(rest); to != nil {
+				controller, action = to[1], to[2]
+			} else if strings.Contains(path, "#") {
+				// get "pages#about" is not a path at all.
+				continue
+			} else if parts := strings.Split(strings.Trim(path, "/"), "/"); len(parts) == 2 {
+				// get "pages/about" routes to pages#about.
+				controller, action = parts[0], parts[1]
+			}
+			add(verb, path, lineNo, controller, action)
+		case reRailsResource.MatchString(line):
+			m := reRailsResource.FindStringSubmatch(line)
+			kind, name, rest := m[1], m[2], m[3]
+			base := "/" + name
+			controller := name
+			if kind == "resource" {
+				controller = name + "s"
+			}
+			if railsResourceHas(rest, "index", kind) {
+				add("GET", base, lineNo, controller, "index")
+			}
+			if railsResourceHas(rest, "new", kind) {
+				add("GET", base+"/new", lineNo, controller, "new")
+			}
+			if kind == "resources" && railsResourceHas(rest, "show", kind) {
+				add("GET", base+"/:id", lineNo, controller, "show")
+			}
+			// Nested routes live under the member path.
+			frame.path = joinPath(top.path, base)
+			if kind == "resources" {
+				frame.path = joinPath(top.path, base, ":"+strings.TrimSuffix(name, "s")+"_id")
+			}
+		case reRailsNS.MatchString(line):
+			ns := reRailsNS.FindStringSubmatch(line)[1]
+			frame.path = joinPath(top.path, ns)
+			frame.controller = top.controller + ns + "/"
+		case reRailsScope.MatchString(line):
+			frame.path = joinPath(top.path, reRailsScope.FindStringSubmatch(line)[1])
+		case reRailsAuth.MatchString(line):
+			frame.auth = true
+		}
+
+		if opens {
+			stack = append(stack, frame)
+		}
+	}
+
+	applyRailsControllerAuth(rootDir, out, actions)
+	return out
+}
+
+// railsResourceHas reports whether a resources line generates action,
+// honoring only:/except:. A singular resource has no index.
+func railsResourceHas(rest, action, kind string) bool {
+	if kind == "resource" && action == "index" {
+		action = "show"
+	}
+	return railsFilterScope(rest)(action)
+}
+
+// applyRailsControllerAuth marks routes whose controller (or
+// ApplicationController) requires login via before_action, minus actions
+// the filter's only:/except: or a skip_before_action exempts.
+func applyRailsControllerAuth(rootDir string, out []Route, actions []railsAction) {
+	appFilter := railsAuthFilter(readFile(filepath.Join(rootDir, "app", "controllers", "application_controller.rb")))
+	cache := map[string]railsFilters{}
+	for _, a := range actions {
+		f, ok := cache[a.controller]
+		if !ok {
+			f = railsAuthFilter(readFile(filepath.Join(rootDir, "app", "controllers", a.controller+"_controller.rb")))
+			cache[a.controller] = f
+		}
+		required := (f.require != nil && f.require(a.action)) || (appFilter.require != nil && appFilter.require(a.action))
+		if f.skip != nil && f.skip(a.action) {
+			required = false
+		}
+		if required {
+			out[a.index].Auth = true
+		}
+	}
+}
+
+type railsFilters struct {
+	require func(action string) bool
+	skip    func(action string) bool
+}
+
+func railsAuthFilter(content string) railsFilters {
+	var f railsFilters
+	for _, m := range reRailsBeforeAuth.FindAllStringSubmatch(content, -1) {
+		applies := railsFilterScope(m[3])
+		if m[1] == "skip_" {
+			f.skip = applies
+		} else {
+			f.require = applies
+		}
+	}
+	return f
+}
+
+// railsFilterScope turns a filter's trailing `only: [...]` / `except:`
+// options into a predicate over action names.
+func railsFilterScope(opts string) func(string) bool {
+	m := reRailsOnly.FindStringSubmatch(opts)
+	if m == nil {
+		return func(string) bool { return true }
+	}
+	list := " " + strings.NewReplacer(":", " ", ",", " ").Replace(m[2]+m[3]+" "+m[4]) + " "
+	if m[1] == "only" {
+		return func(a string) bool { return strings.Contains(list, " "+a+" ") }
+	}
+	return func(a string) bool { return !strings.Contains(list, " "+a+" ") }
+}
@@ -0,0 +1,103 @@
+// Package routes extracts an application's route list from its framework's
+// route definitions (Next.js file-system routes, Rails config/routes.rb,
+// Laravel routes/*.php) without running the app. Live checks use the list
+// to probe real pages instead of guessing paths.
+//
+// Extraction is static and best-effort: it reads the common ways routes
+// are declared and skips what it can't follow (routes generated in loops,
+// engines mounted from gems). Missing a route is always preferred to
+// inventing one.
+package routes
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Route is one path the application serves.
+type Route struct {
+	// Path uses ":name" for dynamic segments in every framework, e.g.
+	// "/blog/:slug", and ":name*" for catch-alls.
+	Path string `json:"path"`
+	// Method is the HTTP method, or "ANY" when the definition doesn't
+	// restrict it (Next.js pages, Rails `match`).
+	Method string `json:"method"`
+	// Source is where the route is defined: a file, or file:line.
+	Source string `json:"source"`
+	// Dynamic routes contain parameters and can't be requested as-is.
+	Dynamic bool `json:"dynamic,omitempty"`
+	// API routes return data rather than pages.
+	API bool `json:"api,omitempty"`
+	// Auth is set when the definition puts the route behind
+	// authentication (middleware, Devise blocks, before_action filters).
+	Auth bool `json:"auth,omitempty"`
+}
+
+// Requestable reports whether an anonymous GET to the route's path is
+// meaningful: a fixed path that serves a page.
+func (r Route) Requestable() bool {
+	return !r.Dynamic && !r.API && (r.Method == "GET" || r.Method == "ANY")
+}
+
+// Extract returns every route the supported frameworks define under
+// rootDir, sorted by path then method. Each extractor only acts when its
+// framework's files are present, so stack detection isn't needed.
+func Extract(rootDir string) []Route {
+	var all []Route
+	all = append(all, extractNext(rootDir)...)
+	all = append(all, extractRails(rootDir)...)
+	all = append(all, extractLaravel(rootDir)...)
+
+	seen := make(map[string]bool, len(all))
+	var out []Route
+	for _, r := range all {
+		key := r.Method + " " + r.Path
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, r)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		return out[i].Method < out[j].Method
+	})
+	return out
+}
+
+// joinPath joins URL path segments, collapsing duplicate slashes and
+// always returning a leading slash.
+func joinPath(parts ...string) string {
+	var segs []string
+	for _, p := range parts {
+		for _, s := range strings.Split(p, "/") {
+			if s != "" {
+				segs = append(segs, s)
+			}
+		}
+	}
+	return "/" + strings.Join(segs, "/")
+}
+
+func isDynamic(path string) bool {
+	return strings.Contains(path, ":") || strings.Contains(path, "*")
+}
+
+func readFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func rel(root, path string) string {
+	if r, err := filepath.Rel(root, path); err == nil {
+		return filepath.ToSlash(r)
+	}
+	return path
+}
@@ -0,0 +1,163 @@
+package routes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range files {
+		full := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(body), 0o644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func byKey(rs []Route) map[string]Route {
+	m := make(map[string]Route, len(rs))
+	for _, r := range rs {
+		m[r.Method+" "+r.Path] = r
+	}
+	return m
+}
+
+func TestExtractNext(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"app/page.tsx":                     "export default function Home() {}",
+		"app/(marketing)/pricing/page.tsx": "",
+		"app/blog/[slug]/page.tsx":         "",
+		"app/dashboard/settings/page.tsx":  "",
+		"app/_components/page.tsx":         "",
+		"app/api/health/route.ts":          "export async function GET() {}",
+		"pages/about.tsx":                  "",
+		"pages/_app.tsx":                   "",
+		"pages/docs/index.tsx":             "",
+		"middleware.ts":                    "import { auth } from './auth'\nexport const config = { matcher: ['/dashboard/:path*'] }",
+	})
+	got := byKey(Extract(dir))
+
+	for _, key := range []string{"GET /", "GET /pricing", "GET /blog/:slug", "GET /dashboard/settings", "GET /api/health", "GET /about", "GET /docs"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("missing %s in %v", key, got)
+		}
+	}
+	if _, ok := got["GET /_components"]; ok {
+		t.Error("private folder should not be a route")
+	}
+	if _, ok := got["GET /_app"]; ok {
+		t.Error("_app should not be a route")
+	}
+	if !got["GET /dashboard/settings"].Auth {
+		t.Error("middleware matcher should mark /dashboard as auth")
+	}
+	if got["GET /pricing"].Auth {
+		t.Error("/pricing should be public")
+	}
+	if got["GET /blog/:slug"].Requestable() || got["GET /api/health"].Requestable() {
+		t.Error("dynamic and API routes should not be requestable")
+	}
+}
+
+func TestExtractRails(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"config/routes.rb": `Rails.application.routes.draw do
+  root "pages#home"
+  get "/pricing", to: "pages#pricing"
+  resources :posts, only: [:index, :show]
+  authenticate :user do
+    get "/billing", to: "billing#show"
+  end
+  namespace :admin do
+    resources :users, only: %i[index]
+  end
+end
+`,
+		"app/controllers/admin/users_controller.rb": "class Admin::UsersController < ApplicationController\n  before_action :authenticate_admin!\nend\n",
+		"app/controllers/posts_controller.rb":       "class PostsController < ApplicationController\n  before_action :authenticate_user!, except: [:index]\nend\n",
+	})
+	got := byKey(Extract(dir))
+
+	for key, auth := range map[string]bool{
+		"GET /":            false,
+		"GET /pricing":     false,
+		"GET /posts":       false,
+		"GET /posts/:id":   true,
+		"GET /billing":     true,
+		"GET /admin/users": true,
+	} {
+		r, ok := got[key]
+		if !ok {
+			t.Errorf("missing %s in %v", key, got)
+			continue
+		}
+		if r.Auth != auth {
+			t.Errorf("%s: Auth = %v, want %v", key, r.Auth, auth)
+		}
+	}
+	if r := got["GET /pricing"]; r.Source != "config/routes.rb:3" {
+		t.Errorf("Source = %q", r.Source)
+	}
+}
+
+func TestExtractLaravel(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"artisan": "#!/usr/bin/env php",
+		"routes/web.php": `<?php
+use Illuminate\Support\Facades\Route;
+
+Route::get('/', function () {
+    return view('welcome');
+});
+Route::view('/about', 'about');
+
+Route::middleware(['auth', 'verified'])->group(function () {
+    Route::get('/dashboard', [DashboardController::class, 'index']);
+    Route::prefix('settings')->group(function () {
+        Route::get('/profile', [ProfileController::class, 'edit']);
+    });
+});
+
+Route::get('/posts/{post}', [PostController::class, 'show']);
+Route::get('/contact', [ContactController::class, 'show']);
+`,
+		"routes/api.php": "<?php\nRoute::get('/user', fn () => 1)->middleware('auth:sanctum');\n",
+	})
+	got := byKey(Extract(dir))
+
+	for key, auth := range map[string]bool{
+		"GET /":                 false,
+		"GET /about":            false,
+		"GET /dashboard":        true,
+		"GET /settings/profile": true,
+		"GET /posts/:post":      false,
+		"GET /contact":          false,
+		"GET /api/user":         true,
+	} {
+		r, ok := got[key]
+		if !ok {
+			t.Errorf("missing %s in %v", key, got)
+			continue
+		}
+		if r.Auth != auth {
+			t.Errorf("%s: Auth = %v, want %v", key, r.Auth, auth)
+		}
+	}
+	if !got["GET /api/user"].API {
+		t.Error("api.php routes should be API routes")
+	}
+}
+
+func TestExtractNoFramework(t *testing.T) {
+	dir := writeTree(t, map[string]string{"index.html": "<html></html>"})
+	if got := Extract(dir); len(got) != 0 {
+		t.Errorf("Extract() = %v, want none", got)
+	}
+}
@@ -0,0 +1,258 @@
+// Package stacks holds what preflight knows about each framework's
+// layout: where its main layout, error pages and sitemap generator live,
+// and which env files it loads. Each stack is a pack, a small YAML file.
+// The built-in packs are compiled in from builtin/; a project can add
+// its own stacks or override built-in ones in .preflight/stacks/ without
+// rebuilding preflight.
+package stacks
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectDir is where a project keeps its own packs, relative to its
+// root.
+const ProjectDir = ".preflight/stacks"
+
+// DefaultName is the pack whose lists stand in for the ones a pack
+// leaves out, and for stacks without a pack at all.
+const DefaultName = "default"
+
+// Pack is one stack's conventions. Paths are relative to the project root
+// and may use * to match one path segment (lib/*_web/...). A list left
+// out of a pack (as opposed to given as []) comes from the default pack.
+type Pack struct {
+	Name string `yaml:"name"`
+	// Extends names a pack whose lists this one starts from. A project
+	// pack for a framework preflight doesn't know can extend the closest
+	// one it does.
+	Extends string `yaml:"extends,omitempty"`
+	// Layouts are the candidates for the main layout, the file every page
+	// renders through, in order; the first that exists is used.
+	Layouts []string `yaml:"layouts,omitempty"`
+	// Templates are the files searched for the tags and scripts that end
+	// up in every page's head, such as analytics snippets.
+	Templates  []string   `yaml:"templates,omitempty"`
+	ErrorPages ErrorPages `yaml:"errorPages,omitempty"`
+	// Sitemaps are the files that generate sitemap.xml at runtime: a
+	// route, controller or template.
+	Sitemaps []string `yaml:"sitemaps,omitempty"`
+	Env      Env      `yaml:"env,omitempty"`
+}
+
+// ErrorPages are where the stack's custom error pages live, and what to
+// suggest when there's none.
+type ErrorPages struct {
+	NotFound    []string `yaml:"notFound,omitempty"`
+	ServerError []string `yaml:"serverError,omitempty"`
+	Suggestions []string `yaml:"suggestions,omitempty"`
+}
+
+// Env lists the stack's env conventions.
+type Env struct {
+	// Files are dotenv files the framework loads besides .env, such as
+	// .env.local. They usually override a few variables rather than set
+	// them all.
+	Files []string `yaml:"files,omitempty"`
+}
+
+// Set is a collection of packs by name.
+type Set struct {
+	packs map[string]Pack
+}
+
+//go:embed builtin/*.yaml
+var builtinFS embed.FS
+
+var builtin = mustLoadBuiltin()
+
+func mustLoadBuiltin() *Set {
+	s := &Set{packs: map[string]Pack{}}
+	entries, err := builtinFS.ReadDir("builtin")
+	if err != nil {
+		panic(err)
+	}
+	for _, e := range entries {
+		data, err := builtinFS.ReadFile(path.Join("builtin", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		p, err := parsePack(data, e.Name())
+		if err != nil {
+			panic(err)
+		}
+		s.packs[p.Name] = p
+	}
+	return s
+}
+
+// Builtin returns the packs compiled into preflight.
+func Builtin() *Set {
+	return builtin
+}
+
+// Load returns the built-in packs with the project's own from
+// .preflight/stacks/*.yml (or .yaml) applied on top. A project pack named
+// like a built-in one replaces just the lists it sets; one with a new name
+// adds a stack. A project without the directory gets the built-in packs.
+func Load(rootDir string) (*Set, error) {
+	dir := filepath.Join(rootDir, filepath.FromSlash(ProjectDir))
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return builtin, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Set{packs: make(map[string]Pack, len(builtin.packs))}
+	for name, p := range builtin.packs {
+		s.packs[name] = p
+	}
+	var local []Pack
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yml" && ext != ".yaml") {
+			continue
+		}
+		rel := ProjectDir + "/" + e.Name()
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		p, err := parsePack(data, rel)
+		if err != nil {
+			return nil, err
+		}
+		local = append(local, p)
+	}
+
+	// Overrides go on first so an extends sees the project's version of
+	// the pack it names. A pack extending another project pack waits for
+	// that one, whatever order the files are read in.
+	pending := map[string]Pack{}
+	for _, p := range local {
+		if p.Extends == "" {
+			s.packs[p.Name] = override(s.packs[p.Name], p)
+		} else {
+			pending[p.Name] = p
+		}
+	}
+	var resolve func(name string, chain []string) error
+	resolve = func(name string, chain []string) error {
+		p, ok := pending[name]
+		if !ok {
+			return nil
+		}
+		if slices.Contains(chain, name) {
+			return fmt.Errorf("%s/%s: extends loops back to itself (%s)", ProjectDir, name, strings.Join(append(chain, name), " -> "))
+		}
+		// A pack extending its own name starts from the built-in one.
+		if p.Extends != name {
+			if err := resolve(p.Extends, append(chain, name)); err != nil {
+				return err
+			}
+		}
+		base, ok := s.packs[p.Extends]
+		if !ok {
+			return fmt.Errorf("%s/%s: extends unknown stack %q", ProjectDir, name, p.Extends)
+		}
+		s.packs[name] = override(base, p)
+		delete(pending, name)
+		return nil
+	}
+	names := make([]string, 0, len(pending))
+	for name := range pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := resolve(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func parsePack(data []byte, file string) (Pack, error) {
+	var p Pack
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil {
+		return Pack{}, fmt.Errorf("%s: %w", file, err)
+	}
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return Pack{}, fmt.Errorf("%s: missing name", file)
+	}
+	// Paths are joined onto the project root, so one that's absolute or
+	// climbs out with .. would read files outside the project.
+	for _, list := range [][]string{p.Layouts, p.Templates, p.ErrorPages.NotFound, p.ErrorPages.ServerError, p.Sitemaps, p.Env.Files} {
+		for _, rel := range list {
+			if !fs.ValidPath(rel) {
+				return Pack{}, fmt.Errorf("%s: %q must be a path inside the project, without .. or a leading /", file, rel)
+			}
+		}
+	}
+	return p, nil
+}
+
+// override returns base with each list p sets replacing base's.
+func override(base, p Pack) Pack {
+	base.Name = p.Name
+	replace := func(dst *[]string, src []string) {
+		if src != nil {
+			*dst = src
+		}
+	}
+	replace(&base.Layouts, p.Layouts)
+	replace(&base.Templates, p.Templates)
+	replace(&base.ErrorPages.NotFound, p.ErrorPages.NotFound)
+	replace(&base.ErrorPages.ServerError, p.ErrorPages.ServerError)
+	replace(&base.ErrorPages.Suggestions, p.ErrorPages.Suggestions)
+	replace(&base.Sitemaps, p.Sitemaps)
+	replace(&base.Env.Files, p.Env.Files)
+	return base
+}
+
+// Get returns the named stack's pack, with the lists it leaves out taken
+// from the default pack. A stack without a pack gets the default one, and
+// false.
+func (s *Set) Get(name string) (Pack, bool) {
+	p, ok := s.packs[name]
+	if !ok || name == DefaultName {
+		d := s.packs[DefaultName]
+		d.Name = name
+		return d, ok
+	}
+	return override(s.packs[DefaultName], p), true
+}
+
+// Names returns every stack with a pack, sorted, the default pack aside.
+func (s *Set) Names() []string {
+	names := make([]string, 0, len(s.packs))
+	for name := range s.packs {
+		if name != DefaultName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Own returns the named pack as written, without the default pack's
+// lists, for conventions that only mean something for the stack that
+// lists them.
+func (s *Set) Own(name string) Pack {
+	return s.packs[name]
+}
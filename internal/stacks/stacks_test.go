@@ -0,0 +1,121 @@
+package stacks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writePacks(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, filepath.FromSlash(ProjectDir))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestBuiltinPacks(t *testing.T) {
+	s := Builtin()
+	if len(s.Names()) < 20 {
+		t.Errorf("only %d built-in packs: %v", len(s.Names()), s.Names())
+	}
+	next, ok := s.Get("next")
+	if !ok || next.Layouts[0] != "app/layout.tsx" || !reflect.DeepEqual(next.Env.Files, []string{".env.local"}) {
+		t.Errorf("next = %+v, %v", next, ok)
+	}
+	// Lists a pack leaves out come from the default pack; an empty list
+	// stays empty.
+	strapi, _ := s.Get("strapi")
+	if !reflect.DeepEqual(strapi.ErrorPages.NotFound, []string{"404.html", "public/404.html"}) {
+		t.Errorf("strapi 404 pages = %v", strapi.ErrorPages.NotFound)
+	}
+	if hugo, _ := s.Get("hugo"); hugo.ErrorPages.ServerError == nil || len(hugo.ErrorPages.ServerError) != 0 {
+		t.Errorf("hugo 500 pages = %#v, want empty", hugo.ErrorPages.ServerError)
+	}
+	if p, ok := s.Get("cobol"); ok || p.Name != "cobol" || !reflect.DeepEqual(p.Templates, []string{"index.html", "public/index.html"}) {
+		t.Errorf("unknown stack = %+v, %v", p, ok)
+	}
+}
+
+func TestLoadProjectPacks(t *testing.T) {
+	root := writePacks(t, map[string]string{
+		// Overrides one list of a built-in pack.
+		"rails.yml": "name: rails\nlayouts:\n  - app/views/layouts/site.html.erb\n",
+		// A new stack built on a known one.
+		"blitz.yaml": "name: blitz\nextends: next\nsitemaps:\n  - app/sitemap.xml.ts\n",
+		"notes.txt":  "not a pack",
+	})
+	s, err := Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rails, _ := s.Get("rails")
+	if !reflect.DeepEqual(rails.Layouts, []string{"app/views/layouts/site.html.erb"}) {
+		t.Errorf("rails layouts = %v", rails.Layouts)
+	}
+	if !reflect.DeepEqual(rails.ErrorPages.NotFound, []string{"public/404.html"}) {
+		t.Errorf("override dropped rails's other lists: %v", rails.ErrorPages.NotFound)
+	}
+	blitz, ok := s.Get("blitz")
+	if !ok || blitz.Layouts[0] != "app/layout.tsx" || !reflect.DeepEqual(blitz.Sitemaps, []string{"app/sitemap.xml.ts"}) {
+		t.Errorf("blitz = %+v, %v", blitz, ok)
+	}
+	// The built-in set isn't changed by a project's packs.
+	if rails, _ := Builtin().Get("rails"); rails.Layouts[0] != "app/views/layouts/application.html.erb" {
+		t.Errorf("built-in rails layouts = %v", rails.Layouts)
+	}
+
+	if s, err := Load(t.TempDir()); err != nil || s != Builtin() {
+		t.Errorf("Load without %s = %v, %v", ProjectDir, s, err)
+	}
+}
+
+func TestLoadExtendsChain(t *testing.T) {
+	// a.yml is read first but extends the pack b.yml defines.
+	s, err := Load(writePacks(t, map[string]string{
+		"a.yml": "name: acme\nextends: base\nlayouts: [src/acme.html]\n",
+		"b.yml": "name: base\nextends: next\nsitemaps: [app/base-sitemap.ts]\n",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	acme, ok := s.Get("acme")
+	next, _ := s.Get("next")
+	if !ok || !reflect.DeepEqual(acme.Layouts, []string{"src/acme.html"}) || !reflect.DeepEqual(acme.Sitemaps, []string{"app/base-sitemap.ts"}) || !reflect.DeepEqual(acme.Templates, next.Templates) {
+		t.Errorf("acme = %+v, %v", acme, ok)
+	}
+
+	_, err = Load(writePacks(t, map[string]string{
+		"a.yml": "name: a\nextends: b\n",
+		"b.yml": "name: b\nextends: a\n",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "a -> b -> a") {
+		t.Errorf("loop: err = %v", err)
+	}
+}
+
+func TestLoadRejectsBadPacks(t *testing.T) {
+	for name, content := range map[string]string{
+		"unnamed.yml":  "layouts: [index.html]\n",
+		"typo.yml":     "name: x\nlayout: [index.html]\n",
+		"extends.yml":  "name: x\nextends: nope\n",
+		"escape.yml":   "name: x\nlayouts: [\"../../../etc/hostname\"]\n",
+		"absolute.yml": "name: x\nenv:\n  files: [/etc/passwd]\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(writePacks(t, map[string]string{name: content}))
+			if err == nil || !strings.Contains(err.Error(), ProjectDir) {
+				t.Errorf("err = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+// Package telemetry records OpenTelemetry trace spans for a scan and exports
+// them over OTLP/HTTP using the JSON encoding, so a fleet of preflight runs
+// can be observed in any OTLP-capable backend without the CLI taking on the
+// OpenTelemetry SDK as a dependency.
+//
+// Tracing is off unless an endpoint is configured through the standard
+// environment variables:
+//
+//	OTEL_EXPORTER_OTLP_TRACES_ENDPOINT  full URL, e.g. http://collector:4318/v1/traces
+//	OTEL_EXPORTER_OTLP_ENDPOINT         base URL; /v1/traces is appended
+//	OTEL_EXPORTER_OTLP_HEADERS          comma-separated key=value pairs
+//	OTEL_SERVICE_NAME                   defaults to "preflight"
+//	OTEL_RESOURCE_ATTRIBUTES            comma-separated key=value pairs
+//	OTEL_SDK_DISABLED=true              turns tracing off regardless
+//
+// A W3C TRACEPARENT variable, as set by CI systems that trace their own
+// pipelines, makes the scan's root span a child of that trace.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// scopeName identifies the instrumentation library in exported spans.
+const scopeName = "github.com/preflightsh/preflight"
+
+// Tracer collects finished spans for one scan in memory and ships them in a
+// single export request on Flush. A nil *Tracer is valid and records
+// nothing, so callers never need to check whether tracing is enabled.
+type Tracer struct {
+	endpoint string
+	headers  map[string]string
+	resource []Attribute
+	version  string
+	client   *http.Client
+
+	traceID      string
+	remoteParent string
+
+	mu    sync.Mutex
+	spans []*Span
+}
+
+// FromEnv returns a Tracer configured from the OTEL_* environment
+// variables, or nil when no OTLP endpoint is set.
+func FromEnv(version string) *Tracer {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return nil
+	}
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		if base == "" {
+			return nil
+		}
+		endpoint = strings.TrimRight(base, "/") + "/v1/traces"
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "preflight"
+	}
+	res := []Attribute{String("service.name", serviceName), String("service.version", version)}
+	for k, v := range parseKeyValues(os.Getenv("OTEL_RESOURCE_ATTRIBUTES")) {
+		if k != "service.name" {
+			res = append(res, String(k, v))
+		}
+	}
+
+	t := &Tracer{
+		endpoint: endpoint,
+		headers:  parseKeyValues(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")),
+		resource: res,
+		version:  version,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		traceID:  randomHex(16),
+	}
+	if traceID, spanID, ok := parseTraceparent(os.Getenv("TRACEPARENT")); ok {
+		t.traceID, t.remoteParent = traceID, spanID
+	}
+	return t
+}
+
+// Span is one timed operation. Set attributes and status before End; a
+// span is exported only once it has ended.
+type Span struct {
+	tracer   *Tracer
+	parentID string
+	spanID   string
+	name     string
+	start    time.Time
+	end      time.Time
+	attrs    []Attribute
+	errMsg   string
+	failed   bool
+}
+
+// Start begins a span. A nil parent makes it a root span (or a child of the
+// TRACEPARENT trace, when one was inherited).
+func (t *Tracer) Start(name string, parent *Span, attrs ...Attribute) *Span {
+	if t == nil {
+		return nil
+	}
+	parentID := t.remoteParent
+	if parent != nil {
+		parentID = parent.spanID
+	}
+	return &Span{
+		tracer:   t,
+		parentID: parentID,
+		spanID:   randomHex(8),
+		name:     name,
+		start:    time.Now(),
+		attrs:    attrs,
+	}
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// SetError marks the span as failed with the given description.
+func (s *Span) SetError(msg string) {
+	if s == nil {
+		return
+	}
+	s.failed = true
+	s.errMsg = msg
+}
+
+// End records the span's end time and queues it for export.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.end = time.Now()
+	s.tracer.mu.Lock()
+	s.tracer.spans = append(s.tracer.spans, s)
+	s.tracer.mu.Unlock()
+}
+
+// Flush exports every ended span in one OTLP/HTTP request and clears the
+// queue. Export problems are returned for the caller to report; they never
+// affect the scan itself.
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.mu.Unlock()
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(t.payload(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("otlp export: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "preflight/"+t.version)
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("otlp export: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("otlp export: %s returned %s", t.endpoint, resp.Status)
+	}
+	return nil
+}
+
+// The types below mirror the OTLP/JSON trace encoding: hex IDs, decimal
+// string timestamps and typed attribute values.
+
+// Attribute is a key/value pair attached to a span or the resource.
+type Attribute struct {
+	Key   string         `json:"key"`
+	Value attributeValue `json:"value"`
+}
+
+type attributeValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+// String returns a string-valued span attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: attributeValue{StringValue: &value}}
+}
+
+// Bool returns a boolean span attribute.
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: attributeValue{BoolValue: &value}}
+}
+
+// Int returns an integer span attribute.
+func Int(key string, value int) Attribute {
+	s := strconv.Itoa(value)
+	return Attribute{Key: key, Value: attributeValue{IntValue: &s}}
+}
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []Attribute `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanJSON `json:"spans"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type spanJSON struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []Attribute `json:"attributes,omitempty"`
+	Status            status      `json:"status"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	spanKindInternal = 1
+	statusCodeOK     = 1
+	statusCodeError  = 2
+)
+
+func (t *Tracer) payload(spans []*Span) exportRequest {
+	out := make([]spanJSON, 0, len(spans))
+	for _, s := range spans {
+		st := status{Code: statusCodeOK}
+		if s.failed {
+			st = status{Code: statusCodeError, Message: s.errMsg}
+		}
+		out = append(out, spanJSON{
+			TraceID:           t.traceID,
+			SpanID:            s.spanID,
+			ParentSpanID:      s.parentID,
+			Name:              s.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        s.attrs,
+			Status:            st,
+		})
+	}
+	return exportRequest{ResourceSpans: []resourceSpans{{
+		Resource: resource{Attributes: t.resource},
+		ScopeSpans: []scopeSpans{{
+			Scope: scope{Name: scopeName, Version: t.version},
+			Spans: out,
+		}},
+	}}}
+}
+
+// parseKeyValues parses the OTEL "k1=v1,k2=v2" list format, URL-decoding
+// values as the spec allows.
+func parseKeyValues(s string) map[string]string {
+	out := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		if decoded, err := url.QueryUnescape(v); err == nil {
+			v = decoded
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// parseTraceparent extracts the trace and parent span IDs from a W3C
+// traceparent value ("00-<32 hex>-<16 hex>-<2 hex>").
+func parseTraceparent(s string) (traceID, spanID string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return "", "", false
+	}
+	for _, id := range parts[1:3] {
+		if _, err := hex.DecodeString(id); err != nil || strings.Trim(id, "0") == "" {
+			return "", "", false
+		}
+	}
+	return strings.ToLower(parts[1]), strings.ToLower(parts[2]), true
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,114 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromEnvDisabledWithoutEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	if tr := FromEnv("dev"); tr != nil {
+		t.Fatal("FromEnv returned a tracer with no endpoint configured")
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	if tr := FromEnv("dev"); tr != nil {
+		t.Fatal("FromEnv ignored OTEL_SDK_DISABLED")
+	}
+
+	// The nil tracer and its spans must be safe to use.
+	var tr *Tracer
+	span := tr.Start("scan", nil)
+	span.SetAttributes(String("k", "v"))
+	span.SetError("boom")
+	span.End()
+	if err := tr.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFlushExportsOTLPJSON(t *testing.T) {
+	var got exportRequest
+	var gotHeader, gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Get("X-Api-Key")
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("export body is not JSON: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("OTEL_SDK_DISABLED", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", srv.URL+"/")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "x-api-key=secret%20key")
+	t.Setenv("TRACEPARENT", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	tr := FromEnv("1.2.3")
+	root := tr.Start("preflight.scan", nil)
+	child := tr.Start("check ssl", root, String("preflight.check.id", "ssl"), Bool("preflight.check.passed", false))
+	child.SetError("timeout")
+	child.End()
+	root.End()
+	if err := tr.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotPath != "/v1/traces" {
+		t.Errorf("path = %q, want /v1/traces", gotPath)
+	}
+	if gotHeader != "secret key" {
+		t.Errorf("x-api-key header = %q, want the decoded value", gotHeader)
+	}
+	spans := got.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	c, r := spans[0], spans[1]
+	if r.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || c.TraceID != r.TraceID {
+		t.Errorf("trace IDs = %q/%q, want the TRACEPARENT trace", c.TraceID, r.TraceID)
+	}
+	if r.ParentSpanID != "00f067aa0ba902b7" {
+		t.Errorf("root parent = %q, want the TRACEPARENT span", r.ParentSpanID)
+	}
+	if c.ParentSpanID != r.SpanID {
+		t.Errorf("child parent = %q, want root span %q", c.ParentSpanID, r.SpanID)
+	}
+	if c.Status.Code != statusCodeError || c.Status.Message != "timeout" {
+		t.Errorf("child status = %+v, want error/timeout", c.Status)
+	}
+	if r.Status.Code != statusCodeOK {
+		t.Errorf("root status = %+v, want ok", r.Status)
+	}
+
+	// A second flush has nothing left to send.
+	got = exportRequest{}
+	if err := tr.Flush(context.Background()); err != nil || len(got.ResourceSpans) != 0 {
+		t.Errorf("second Flush sent spans again (err=%v)", err)
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		in string
+		ok bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-zzzzzzzzzzzzzzzz-01", false},
+		{"garbage", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if _, _, ok := parseTraceparent(tt.in); ok != tt.ok {
+			t.Errorf("parseTraceparent(%q) ok = %v, want %v", tt.in, ok, tt.ok)
+		}
+	}
+}
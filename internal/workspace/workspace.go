@@ -0,0 +1,158 @@
+// Package workspace finds the apps in a monorepo. Detection and checks
+// that look inside apps/*, packages/* and services/* for a framework's
+// files share Dirs; scan --monorepo uses Discover to scan each app as a
+// project of its own.
+package workspace
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultRoots are the directories monorepos conventionally keep their
+// apps and packages in, one level down.
+var DefaultRoots = []string{"apps", "packages", "services"}
+
+// projectMarkers are the files that make a directory an app rather than a
+// folder of assets or shared config.
+var projectMarkers = []string{
+	"preflight.yml", "package.json", "Gemfile", "composer.json", "go.mod",
+	"pyproject.toml", "requirements.txt", "Cargo.toml", "mix.exs", "pom.xml", "build.gradle",
+}
+
+// App is one app or package in a monorepo.
+type App struct {
+	// Path is the app's directory relative to the repository root, with
+	// forward slashes (apps/web).
+	Path string `json:"path"`
+	// Dir is the app's absolute directory.
+	Dir string `json:"-"`
+}
+
+// Dirs returns every directory one level under DefaultRoots in rootDir.
+// onErr, when set, is told about roots that exist but can't be listed.
+func Dirs(rootDir string, onErr func(path string, err error)) []string {
+	var dirs []string
+	for _, root := range DefaultRoots {
+		monoDir := filepath.Join(rootDir, root)
+		entries, err := os.ReadDir(monoDir)
+		if err != nil {
+			if onErr != nil {
+				onErr(monoDir, err)
+			}
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				dirs = append(dirs, filepath.Join(monoDir, entry.Name()))
+			}
+		}
+	}
+	return dirs
+}
+
+// Discover lists the apps in the monorepo at rootDir, sorted by path.
+// patterns are doublestar globs of app directories relative to rootDir
+// (apps/*, services/api); a pattern starting with ! excludes matches.
+// Without patterns, the workspaces declared in package.json or
+// pnpm-workspace.yaml are used, then DefaultRoots. Only directories with
+// a project file (package.json, go.mod, preflight.yml...) count.
+func Discover(rootDir string, patterns []string) ([]App, error) {
+	if len(patterns) == 0 {
+		patterns = declaredWorkspaces(rootDir)
+	}
+	if len(patterns) == 0 {
+		for _, root := range DefaultRoots {
+			patterns = append(patterns, root+"/*")
+		}
+	}
+
+	fsys := os.DirFS(rootDir)
+	var include, exclude []string
+	for _, p := range patterns {
+		p = strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(p), "./"), "/")
+		if rest, ok := strings.CutPrefix(p, "!"); ok {
+			exclude = append(exclude, strings.TrimPrefix(rest, "./"))
+			continue
+		}
+		include = append(include, p)
+	}
+
+	seen := map[string]bool{}
+	var apps []App
+	for _, pattern := range include {
+		matches, err := doublestar.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range matches {
+			if seen[rel] || excluded(rel, exclude) || strings.Contains("/"+rel+"/", "/node_modules/") {
+				continue
+			}
+			dir := filepath.Join(rootDir, filepath.FromSlash(rel))
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() || !hasProjectMarker(dir) {
+				continue
+			}
+			seen[rel] = true
+			apps = append(apps, App{Path: rel, Dir: dir})
+		}
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Path < apps[j].Path })
+	return apps, nil
+}
+
+func excluded(rel string, exclude []string) bool {
+	for _, pattern := range exclude {
+		if ok, _ := doublestar.Match(pattern, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasProjectMarker(dir string) bool {
+	for _, name := range projectMarkers {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// declaredWorkspaces reads the workspace globs npm, Yarn and pnpm are
+// configured with.
+func declaredWorkspaces(rootDir string) []string {
+	if data, err := os.ReadFile(filepath.Join(rootDir, "package.json")); err == nil {
+		var pkg struct {
+			Workspaces json.RawMessage `json:"workspaces"`
+		}
+		if json.Unmarshal(data, &pkg) == nil && len(pkg.Workspaces) > 0 {
+			// An array, or Yarn's {"packages": [...]} form.
+			var list []string
+			if json.Unmarshal(pkg.Workspaces, &list) == nil && len(list) > 0 {
+				return list
+			}
+			var obj struct {
+				Packages []string `json:"packages"`
+			}
+			if json.Unmarshal(pkg.Workspaces, &obj) == nil && len(obj.Packages) > 0 {
+				return obj.Packages
+			}
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(rootDir, "pnpm-workspace.yaml")); err == nil {
+		var ws struct {
+			Packages []string `yaml:"packages"`
+		}
+		if yaml.Unmarshal(data, &ws) == nil {
+			return ws.Packages
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range files {
+		full := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(body), 0o644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func paths(apps []App) []string {
+	var out []string
+	for _, a := range apps {
+		out = append(out, a.Path)
+	}
+	return out
+}
+
+func TestDiscover(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		patterns []string
+		want     []string
+	}{
+		{
+			name: "default roots",
+			files: map[string]string{
+				"apps/web/package.json":     "{}",
+				"apps/docs/README.md":       "no project file",
+				"packages/ui/package.json":  "{}",
+				"services/api/go.mod":       "module api",
+				"tools/script/package.json": "{}",
+			},
+			want: []string{"apps/web", "packages/ui", "services/api"},
+		},
+		{
+			name: "package.json workspaces",
+			files: map[string]string{
+				"package.json":            `{"workspaces": ["sites/*"]}`,
+				"sites/shop/package.json": "{}",
+				"apps/web/package.json":   "{}",
+			},
+			want: []string{"sites/shop"},
+		},
+		{
+			name: "yarn packages form",
+			files: map[string]string{
+				"package.json":     `{"workspaces": {"packages": ["web"]}}`,
+				"web/package.json": "{}",
+			},
+			want: []string{"web"},
+		},
+		{
+			name: "pnpm workspace",
+			files: map[string]string{
+				"pnpm-workspace.yaml":     "packages:\n  - 'apps/*'\n",
+				"apps/a/package.json":     "{}",
+				"packages/b/package.json": "{}",
+			},
+			want: []string{"apps/a"},
+		},
+		{
+			name: "configured patterns with exclusion",
+			files: map[string]string{
+				"apps/web/package.json":                "{}",
+				"apps/legacy/package.json":             "{}",
+				"apps/web/node_modules/x/package.json": "{}",
+			},
+			patterns: []string{"./apps/**", "!apps/legacy"},
+			want:     []string{"apps/web"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeTree(t, tt.files)
+			apps, err := Discover(dir, tt.patterns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := paths(apps); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Discover = %v, want %v", got, tt.want)
+			}
+			for _, a := range apps {
+				if a.Dir != filepath.Join(dir, filepath.FromSlash(a.Path)) {
+					t.Errorf("%s: Dir = %s", a.Path, a.Dir)
+				}
+			}
+		})
+	}
+}
+
+func TestDirs(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"apps/web/x":    "",
+		"apps/file.txt": "",
+		"services/a/x":  "",
+	})
+	var missing []string
+	got := Dirs(dir, func(path string, err error) { missing = append(missing, filepath.Base(path)) })
+	want := []string{filepath.Join(dir, "apps", "web"), filepath.Join(dir, "services", "a")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Dirs = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(missing, []string{"packages"}) {
+		t.Errorf("onErr saw %v, want [packages]", missing)
+	}
+}